@@ -30,6 +30,7 @@ const (
 	boardsFeatureFlagName = "BoardsFeatureFlags"
 	PluginName            = "focalboard"
 	SharedBoardsName      = "enablepublicsharedboards"
+	EnableAuditDBName     = "enableauditdb"
 
 	notifyFreqCardSecondsKey  = "notify_freq_card_seconds"
 	notifyFreqBoardSecondsKey = "notify_freq_board_seconds"
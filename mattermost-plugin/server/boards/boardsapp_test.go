@@ -76,6 +76,18 @@ func TestSetConfiguration(t *testing.T) {
 		assert.Equal(t, true, config.EnablePublicSharedBoards)
 	})
 
+	t.Run("test enable audit records in the database", func(t *testing.T) {
+		mmConfig := baseConfig
+		mmConfig.PluginSettings.Plugins = make(map[string]map[string]interface{})
+		config := createBoardsConfig(*mmConfig, "", "")
+		assert.Equal(t, false, config.EnableAuditDB)
+
+		mmConfig.PluginSettings.Plugins[PluginName] = make(map[string]interface{})
+		mmConfig.PluginSettings.Plugins[PluginName][EnableAuditDBName] = true
+		config = createBoardsConfig(*mmConfig, "", "")
+		assert.Equal(t, true, config.EnableAuditDB)
+	})
+
 	t.Run("test boards feature flags", func(t *testing.T) {
 		featureFlags := &model.FeatureFlags{
 			TestFeature:        "test",
@@ -56,6 +56,12 @@ func createBoardsConfig(mmconfig mm_model.Config, baseURL string, serverID strin
 		enablePublicSharedBoards = true
 	}
 
+	// the audit records are deleted by the data retention of the boards
+	enableAuditDB := false
+	if mmconfig.PluginSettings.Plugins[PluginName][EnableAuditDBName] == true {
+		enableAuditDB = true
+	}
+
 	enableBoardsDeletion := false
 	if mmconfig.DataRetentionSettings.EnableBoardsDeletion != nil {
 		enableBoardsDeletion = true
@@ -86,6 +92,7 @@ func createBoardsConfig(mmconfig mm_model.Config, baseURL string, serverID strin
 		LocalModeSocketLocation:  "",
 		AuthMode:                 "mattermost",
 		EnablePublicSharedBoards: enablePublicSharedBoards,
+		EnableAuditDB:            enableAuditDB,
 		FeatureFlags:             featureFlags,
 		NotifyFreqCardSeconds:    getPluginSettingInt(mmconfig, notifyFreqCardSecondsKey, 120),
 		NotifyFreqBoardSeconds:   getPluginSettingInt(mmconfig, notifyFreqBoardSecondsKey, 86400),
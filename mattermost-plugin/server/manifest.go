@@ -46,6 +46,14 @@ const manifestStr = `
         "help_text": "This allows board editors to share boards that can be accessed by anyone with the link.",
         "placeholder": "",
         "default": false
+      },
+      {
+        "key": "EnableAuditDB",
+        "display_name": "Save Audit Records in the Database:",
+        "type": "bool",
+        "help_text": "This saves the audit records of the boards in the database, where board admins can query them. The data retention of the boards deletes the records older than the retention period. Changes take effect when the plugin is restarted.",
+        "placeholder": "",
+        "default": false
       }
     ]
   }
@@ -2,11 +2,15 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/app"
+	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
 
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
@@ -16,6 +20,36 @@ type AdminSetPasswordData struct {
 	Password string `json:"password"`
 }
 
+type AdminCreateUserData struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// AdminUser is a user as listed by the admin API, with its email.
+type AdminUser struct {
+	*model.User
+	Email string `json:"email"`
+}
+
+type AdminRevokeSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+type AdminTransferBoardOwnershipData struct {
+	Username string `json:"username"`
+}
+
+type AdminDataRetentionData struct {
+	// Number of days after which unmodified boards are deleted. The
+	// configured retention period is used if zero
+	Days int `json:"days"`
+}
+
+type AdminDataRetentionResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
 func (a *API) handleAdminSetPassword(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	username := vars["username"]
@@ -37,8 +71,13 @@ func (a *API) handleAdminSetPassword(w http.ResponseWriter, r *http.Request) {
 	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
 	auditRec.AddMeta("username", username)
 
-	if !strings.Contains(requestData.Password, "") {
-		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "password is required", err)
+	if requestData.Password == "" {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "password is required", nil)
+		return
+	}
+
+	if _, err = a.app.GetUserByUsername(username); err != nil {
+		a.adminErrorResponse(w, r, err)
 		return
 	}
 
@@ -53,3 +92,437 @@ func (a *API) handleAdminSetPassword(w http.ResponseWriter, r *http.Request) {
 	jsonStringResponse(w, http.StatusOK, "{}")
 	auditRec.Success()
 }
+
+func (a *API) handleAdminGetUsers(w http.ResponseWriter, r *http.Request) {
+	auditRec := a.makeAuditRecord(r, "adminGetUsers", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+
+	users, err := a.app.GetAllUsers()
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	adminUsers := make([]*AdminUser, len(users))
+	for i, user := range users {
+		adminUsers[i] = &AdminUser{User: user, Email: user.Email}
+	}
+
+	data, err := json.Marshal(adminUsers)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("usersCount", len(users))
+	auditRec.Success()
+}
+
+func (a *API) handleAdminCreateUser(w http.ResponseWriter, r *http.Request) {
+	requestBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	var requestData AdminCreateUserData
+	if err = json.Unmarshal(requestBody, &requestData); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "", err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminCreateUser", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+	auditRec.AddMeta("username", requestData.Username)
+
+	if requestData.Username == "" || requestData.Email == "" {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "username and email are required", nil)
+		return
+	}
+
+	user, err := a.app.CreateUser(requestData.Username, requestData.Email, requestData.Password)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	data, err := json.Marshal(&AdminUser{User: user, Email: user.Email})
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("userID", user.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminDeactivateUser(w http.ResponseWriter, r *http.Request) {
+	username := mux.Vars(r)["username"]
+
+	auditRec := a.makeAuditRecord(r, "adminDeactivateUser", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+	auditRec.AddMeta("username", username)
+
+	if err := a.app.DeactivateUser(username); err != nil {
+		a.adminErrorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleAdminRevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	username := mux.Vars(r)["username"]
+
+	auditRec := a.makeAuditRecord(r, "adminRevokeUserSessions", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+	auditRec.AddMeta("username", username)
+
+	revoked, err := a.app.RevokeUserSessions(username)
+	if err != nil {
+		a.adminErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(AdminRevokeSessionsResponse{Revoked: revoked})
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("revoked", revoked)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminGetTeams(w http.ResponseWriter, r *http.Request) {
+	auditRec := a.makeAuditRecord(r, "adminGetTeams", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+
+	teams, err := a.app.GetAllTeams()
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(teams)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("teamsCount", len(teams))
+	auditRec.Success()
+}
+
+func (a *API) handleAdminGetBoards(w http.ResponseWriter, r *http.Request) {
+	teamID := mux.Vars(r)["teamID"]
+
+	auditRec := a.makeAuditRecord(r, "adminGetBoards", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	boards, err := a.app.GetBoardsInTeam(teamID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(boards)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("boardsCount", len(boards))
+	auditRec.Success()
+}
+
+func (a *API) handleAdminExportTeam(w http.ResponseWriter, r *http.Request) {
+	teamID := mux.Vars(r)["teamID"]
+
+	auditRec := a.makeAuditRecord(r, "adminExportTeam", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	opts, err := exportArchiveOptionsFromQuery(r.URL.Query())
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	boards, err := a.app.GetBoardsInTeam(teamID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+	ids := make([]string, 0, len(boards))
+	for _, board := range boards {
+		ids = append(ids, board.ID)
+	}
+
+	opts.TeamID = teamID
+	opts.BoardIDs = ids
+
+	filename := fmt.Sprintf("archive-%s%s", time.Now().Format("2006-01-02"), archiveExtension)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Transfer-Encoding", "binary")
+
+	if err := a.app.ExportArchive(w, opts); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	auditRec.AddMeta("boardsCount", len(ids))
+	auditRec.Success()
+}
+
+func (a *API) handleAdminImport(w http.ResponseWriter, r *http.Request) {
+	teamID := mux.Vars(r)["teamID"]
+	username := r.URL.Query().Get("username")
+
+	auditRec := a.makeAuditRecord(r, "adminImport", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("username", username)
+
+	if username == "" {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "the username of the owner of the imported boards is required", nil)
+		return
+	}
+	user, err := a.app.GetUserByUsername(username)
+	if err != nil {
+		a.adminErrorResponse(w, r, err)
+		return
+	}
+
+	file, handle, err := r.FormFile(UploadFormFileKey)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	defer file.Close()
+	auditRec.AddMeta("filename", handle.Filename)
+	auditRec.AddMeta("size", handle.Size)
+
+	opt := model.ImportArchiveOptions{
+		TeamID:     teamID,
+		ModifiedBy: user.ID,
+	}
+
+	if err := a.app.ImportArchive(file, opt); err != nil {
+		a.logger.Debug("Error importing archive",
+			mlog.String("team_id", teamID),
+			mlog.Err(err),
+		)
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleAdminBackup(w http.ResponseWriter, r *http.Request) {
+	auditRec := a.makeAuditRecord(r, "adminBackup", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+
+	filename := fmt.Sprintf("backup-%s.zip", time.Now().Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Transfer-Encoding", "binary")
+
+	manifest, err := a.app.Backup(w)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	auditRec.AddMeta("schemaVersion", manifest.SchemaVersion)
+	auditRec.AddMeta("filesCount", manifest.Files)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminRestore(w http.ResponseWriter, r *http.Request) {
+	auditRec := a.makeAuditRecord(r, "adminRestore", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	file, handle, err := r.FormFile(UploadFormFileKey)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	defer file.Close()
+	auditRec.AddMeta("filename", handle.Filename)
+	auditRec.AddMeta("size", handle.Size)
+
+	manifest, err := a.app.Restore(file, handle.Size)
+	var errUnsupported model.ErrUnsupportedBackup
+	switch {
+	case errors.Is(err, model.ErrRestoreNotEmpty):
+		a.errorResponse(w, r.URL.Path, http.StatusConflict, err.Error(), err)
+		return
+	case errors.Is(err, app.ErrInvalidBackup), errors.As(err, &errUnsupported):
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	case err != nil:
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("schemaVersion", manifest.SchemaVersion)
+	auditRec.AddMeta("sourceDBType", manifest.DBType)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminTransferBoardOwnership(w http.ResponseWriter, r *http.Request) {
+	boardID := mux.Vars(r)["boardID"]
+
+	requestBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	var requestData AdminTransferBoardOwnershipData
+	if err = json.Unmarshal(requestBody, &requestData); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "", err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminTransferBoardOwnership", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("username", requestData.Username)
+
+	board, err := a.app.TransferBoardOwnership(boardID, requestData.Username)
+	if err != nil {
+		a.adminErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(board)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminRunDataRetention(w http.ResponseWriter, r *http.Request) {
+	requestBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	var requestData AdminDataRetentionData
+	if len(requestBody) > 0 {
+		if err = json.Unmarshal(requestBody, &requestData); err != nil {
+			a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "", err)
+			return
+		}
+	}
+	if requestData.Days == 0 {
+		requestData.Days = a.app.GetConfig().DataRetentionDays
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminRunDataRetention", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("days", requestData.Days)
+
+	deleted, err := a.app.RunDataRetention(requestData.Days)
+	if errors.Is(err, app.ErrInvalidRetentionDays) {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(AdminDataRetentionResponse{Deleted: deleted})
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("deleted", deleted)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminCleanUpOrphanFiles(w http.ResponseWriter, r *http.Request) {
+	auditRec := a.makeAuditRecord(r, "adminCleanUpOrphanFiles", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	result, err := a.app.CleanUpOrphanFiles()
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("fileInfos", result.FileInfos)
+	auditRec.AddMeta("files", result.Files)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminGetSystemSettings(w http.ResponseWriter, r *http.Request) {
+	auditRec := a.makeAuditRecord(r, "adminGetSystemSettings", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+
+	settings, err := a.app.GetSystemSettings()
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+// adminErrorResponse writes the error of an admin operation on a user or a
+// board, which may not exist.
+func (a *API) adminErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	if model.IsErrNotFound(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, err.Error(), err)
+		return
+	}
+	a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/focalboard/server/app"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/services/metrics"
 	"github.com/mattermost/focalboard/server/services/permissions"
 
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
@@ -23,6 +25,10 @@ const (
 	True                   = "true"
 )
 
+// HeaderExceededWIPLimits lists the WIP limits exceeded by a change that
+// the board allowed, as a JSON array of WIPLimitStatus.
+const HeaderExceededWIPLimits = "X-Exceeded-WIP-Limits"
+
 const (
 	ErrorNoTeamCode    = 1000
 	ErrorNoTeamMessage = "No team"
@@ -49,6 +55,7 @@ type API struct {
 	MattermostAuth  bool
 	logger          mlog.LoggerIFace
 	audit           *audit.Audit
+	metrics         *metrics.Metrics
 	isPlugin        bool
 }
 
@@ -59,6 +66,7 @@ func NewAPI(
 	permissions permissions.PermissionsService,
 	logger mlog.LoggerIFace,
 	audit *audit.Audit,
+	metrics *metrics.Metrics,
 	isPlugin bool,
 ) *API {
 	return &API{
@@ -68,12 +76,14 @@ func NewAPI(
 		permissions:     permissions,
 		logger:          logger,
 		audit:           audit,
+		metrics:         metrics,
 		isPlugin:        isPlugin,
 	}
 }
 
 func (a *API) RegisterRoutes(r *mux.Router) {
 	apiv2 := r.PathPrefix("/api/v2").Subrouter()
+	apiv2.Use(a.metricsHandler)
 	apiv2.Use(a.panicHandler)
 	apiv2.Use(a.requireCSRFToken)
 
@@ -84,6 +94,7 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 	a.registerSharingRoutes(apiv2)
 	a.registerTeamsRoutes(apiv2)
 	a.registerAchivesRoutes(apiv2)
+	a.registerImportJobsRoutes(apiv2)
 	a.registerSubscriptionsRoutes(apiv2)
 	a.registerFilesRoutes(apiv2)
 	a.registerLimitsRoutes(apiv2)
@@ -96,13 +107,33 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 	a.registerTemplatesRoutes(apiv2)
 	a.registerBoardsRoutes(apiv2)
 	a.registerBlocksRoutes(apiv2)
+	a.registerAuditRoutes(apiv2)
+	a.registerFeedTokenRoutes(apiv2)
 
 	// System routes are outside the /api/v2 path
 	a.registerSystemRoutes(r)
+
+	// Feed routes are outside the /api/v2 path, see registerFeedRoutes
+	a.registerFeedRoutes(r)
 }
 
 func (a *API) RegisterAdminRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v2/admin/users", a.adminRequired(a.handleAdminGetUsers)).Methods("GET")
+	r.HandleFunc("/api/v2/admin/users", a.adminRequired(a.handleAdminCreateUser)).Methods("POST")
 	r.HandleFunc("/api/v2/admin/users/{username}/password", a.adminRequired(a.handleAdminSetPassword)).Methods("POST")
+	r.HandleFunc("/api/v2/admin/users/{username}/deactivate", a.adminRequired(a.handleAdminDeactivateUser)).Methods("POST")
+	r.HandleFunc("/api/v2/admin/users/{username}/sessions", a.adminRequired(a.handleAdminRevokeUserSessions)).Methods("DELETE")
+	r.HandleFunc("/api/v2/admin/teams", a.adminRequired(a.handleAdminGetTeams)).Methods("GET")
+	r.HandleFunc("/api/v2/admin/teams/{teamID}/boards", a.adminRequired(a.handleAdminGetBoards)).Methods("GET")
+	r.HandleFunc("/api/v2/admin/teams/{teamID}/export", a.adminRequired(a.handleAdminExportTeam)).Methods("GET")
+	r.HandleFunc("/api/v2/admin/teams/{teamID}/import", a.adminRequired(a.handleAdminImport)).Methods("POST")
+	r.HandleFunc("/api/v2/admin/boards/{boardID}/owner", a.adminRequired(a.handleAdminTransferBoardOwnership)).Methods("POST")
+	r.HandleFunc("/api/v2/admin/data-retention", a.adminRequired(a.handleAdminRunDataRetention)).Methods("POST")
+	r.HandleFunc("/api/v2/admin/files/cleanup", a.adminRequired(a.handleAdminCleanUpOrphanFiles)).Methods("POST")
+	r.HandleFunc("/api/v2/admin/settings", a.adminRequired(a.handleAdminGetSystemSettings)).Methods("GET")
+	r.HandleFunc("/api/v2/admin/audit", a.adminRequired(a.handleAdminGetAuditRecords)).Methods("GET")
+	r.HandleFunc("/api/v2/admin/backup", a.adminRequired(a.handleAdminBackup)).Methods("GET")
+	r.HandleFunc("/api/v2/admin/restore", a.adminRequired(a.handleAdminRestore)).Methods("POST")
 }
 
 func getUserID(r *http.Request) string {
@@ -131,6 +162,37 @@ func (a *API) panicHandler(next http.Handler) http.Handler {
 	})
 }
 
+// statusRecorder keeps track of the status code written by the
+// handlers so it can be reported once the request finishes.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (sr *statusRecorder) WriteHeader(statusCode int) {
+	sr.statusCode = statusCode
+	sr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (a *API) metricsHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		// the route template is used instead of the path to keep the
+		// number of label values bounded
+		route := "unknown"
+		if currentRoute := mux.CurrentRoute(r); currentRoute != nil {
+			if template, err := currentRoute.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+		a.metrics.ObserveAPIRequestDuration(route, r.Method, recorder.statusCode, time.Since(start))
+	})
+}
+
 func (a *API) requireCSRFToken(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !a.checkCSRFToken(r) {
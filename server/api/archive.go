@@ -1,13 +1,17 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/services/importer"
 
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
 )
@@ -20,6 +24,7 @@ func (a *API) registerAchivesRoutes(r *mux.Router) {
 	// Archive APIs
 	r.HandleFunc("/boards/{boardID}/archive/export", a.sessionRequired(a.handleArchiveExportBoard)).Methods("GET")
 	r.HandleFunc("/teams/{teamID}/archive/import", a.sessionRequired(a.handleArchiveImport)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/archive/import/{format}", a.sessionRequired(a.handleArchiveImportExternal)).Methods("POST")
 	r.HandleFunc("/teams/{teamID}/archive/export", a.sessionRequired(a.handleArchiveExportTeam)).Methods("GET")
 }
 
@@ -37,6 +42,36 @@ func (a *API) handleArchiveExportBoard(w http.ResponseWriter, r *http.Request) {
 	//   description: Id of board to export
 	//   required: true
 	//   type: string
+	// - name: since
+	//   in: query
+	//   description: Only export the blocks updated at or after this time, in milliseconds since the epoch
+	//   required: false
+	//   type: integer
+	// - name: exclude_comments
+	//   in: query
+	//   description: If true, the comments of the cards are not exported
+	//   required: false
+	//   type: boolean
+	// - name: files
+	//   in: query
+	//   description: How attached files are exported, include (default), exclude or reference
+	//   required: false
+	//   type: string
+	// - name: include_deleted
+	//   in: query
+	//   description: If true, the deleted blocks are exported as block_tombstone lines, which are not imported
+	//   required: false
+	//   type: boolean
+	// - name: include_members
+	//   in: query
+	//   description: If true, the board members are exported as board_member lines, which are not imported
+	//   required: false
+	//   type: boolean
+	// - name: include_history
+	//   in: query
+	//   description: If true, the history of the boards and blocks is exported as board_history and block_history lines, which are not imported
+	//   required: false
+	//   type: boolean
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -74,10 +109,13 @@ func (a *API) handleArchiveExportBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	opts := model.ExportArchiveOptions{
-		TeamID:   board.TeamID,
-		BoardIDs: []string{board.ID},
+	opts, err := exportArchiveOptionsFromQuery(r.URL.Query())
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
 	}
+	opts.TeamID = board.TeamID
+	opts.BoardIDs = []string{board.ID}
 
 	filename := fmt.Sprintf("archive-%s%s", time.Now().Format("2006-01-02"), archiveExtension)
 	w.Header().Set("Content-Type", "application/octet-stream")
@@ -94,7 +132,8 @@ func (a *API) handleArchiveExportBoard(w http.ResponseWriter, r *http.Request) {
 func (a *API) handleArchiveImport(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /teams/{teamID}/archive/import archiveImport
 	//
-	// Import an archive of boards.
+	// Import an archive of boards. Large archives should be imported in the background with
+	// an import job instead.
 	//
 	// ---
 	// produces:
@@ -164,6 +203,108 @@ func (a *API) handleArchiveImport(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleArchiveImportExternal(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/archive/import/{format} archiveImportExternal
+	//
+	// Imports the export of another tool as boards, and returns a report of the items that could
+	// not be mapped.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// consumes:
+	// - multipart/form-data
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: format
+	//   in: path
+	//   description: Format of the export, either trello (JSON), jira (CSV) or asana (JSON)
+	//   required: true
+	//   type: string
+	// - name: file
+	//   in: formData
+	//   description: export file to import
+	//   required: true
+	//   type: file
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ImportReport"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session, _ := ctx.Value(sessionContextKey).(*model.Session)
+	userID := session.UserID
+
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	format := vars["format"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to create board"})
+		return
+	}
+
+	file, handle, err := r.FormFile(UploadFormFileKey)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	defer file.Close()
+
+	auditRec := a.makeAuditRecord(r, "importExternal", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("format", format)
+	auditRec.AddMeta("filename", handle.Filename)
+	auditRec.AddMeta("size", handle.Size)
+
+	opt := model.ImportArchiveOptions{
+		TeamID:     teamID,
+		ModifiedBy: userID,
+	}
+
+	report, err := a.app.ImportExternal(format, file, opt)
+	var unsupported importer.ErrUnsupportedFormat
+	var invalid importer.ErrInvalidExport
+	if errors.As(err, &unsupported) || errors.As(err, &invalid) || errors.Is(err, importer.ErrNothingToImport) {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if err != nil {
+		a.logger.Debug("Error importing external export",
+			mlog.String("team_id", teamID),
+			mlog.String("format", format),
+			mlog.Err(err),
+		)
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("boardsCount", len(report.BoardIDs))
+	auditRec.AddMeta("cardsCount", report.Cards)
+	auditRec.AddMeta("unmappedCount", len(report.Items))
+	auditRec.Success()
+}
+
 func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /teams/{teamID}/archive/export archiveExportTeam
 	//
@@ -178,6 +319,36 @@ func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 	//   description: Id of team
 	//   required: true
 	//   type: string
+	// - name: since
+	//   in: query
+	//   description: Only export the blocks updated at or after this time, in milliseconds since the epoch
+	//   required: false
+	//   type: integer
+	// - name: exclude_comments
+	//   in: query
+	//   description: If true, the comments of the cards are not exported
+	//   required: false
+	//   type: boolean
+	// - name: files
+	//   in: query
+	//   description: How attached files are exported, include (default), exclude or reference
+	//   required: false
+	//   type: string
+	// - name: include_deleted
+	//   in: query
+	//   description: If true, the deleted blocks are exported as block_tombstone lines, which are not imported
+	//   required: false
+	//   type: boolean
+	// - name: include_members
+	//   in: query
+	//   description: If true, the board members are exported as board_member lines, which are not imported
+	//   required: false
+	//   type: boolean
+	// - name: include_history
+	//   in: query
+	//   description: If true, the history of the boards and blocks is exported as board_history and block_history lines, which are not imported
+	//   required: false
+	//   type: boolean
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -206,6 +377,12 @@ func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 	defer a.audit.LogRecord(audit.LevelRead, auditRec)
 	auditRec.AddMeta("TeamID", teamID)
 
+	opts, err := exportArchiveOptionsFromQuery(r.URL.Query())
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
 	boards, err := a.app.GetBoardsForUserAndTeam(userID, teamID)
 	if err != nil {
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
@@ -216,10 +393,8 @@ func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 		ids = append(ids, board.ID)
 	}
 
-	opts := model.ExportArchiveOptions{
-		TeamID:   teamID,
-		BoardIDs: ids,
-	}
+	opts.TeamID = teamID
+	opts.BoardIDs = ids
 
 	filename := fmt.Sprintf("archive-%s%s", time.Now().Format("2006-01-02"), archiveExtension)
 	w.Header().Set("Content-Type", "application/octet-stream")
@@ -232,3 +407,22 @@ func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 
 	auditRec.Success()
 }
+
+// exportArchiveOptionsFromQuery reads the filters of an archive export from
+// the query parameters of a request.
+func exportArchiveOptionsFromQuery(query url.Values) (model.ExportArchiveOptions, error) {
+	var opts model.ExportArchiveOptions
+	var err error
+
+	if opts.ModifiedSince, err = int64QueryParam(query, "since"); err != nil {
+		return opts, err
+	}
+	if opts.Files, err = model.ExportFilesModeFromString(query.Get("files")); err != nil {
+		return opts, err
+	}
+	opts.ExcludeComments = query.Get("exclude_comments") == True
+	opts.IncludeDeleted = query.Get("include_deleted") == True
+	opts.IncludeMembers = query.Get("include_members") == True
+	opts.IncludeHistory = query.Get("include_history") == True
+	return opts, nil
+}
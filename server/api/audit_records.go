@@ -0,0 +1,178 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+)
+
+func (a *API) registerAuditRoutes(r *mux.Router) {
+	// Audit APIs
+	r.HandleFunc("/boards/{boardID}/audit", a.sessionRequired(a.handleGetBoardAuditRecords)).Methods("GET")
+}
+
+func (a *API) handleGetBoardAuditRecords(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/audit getBoardAuditRecords
+	//
+	// Returns the persisted audit records of a board, newest first. Requires board admin permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: user_id
+	//   in: query
+	//   description: only return records of this user
+	//   required: false
+	//   type: string
+	// - name: event
+	//   in: query
+	//   description: only return records of this event
+	//   required: false
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: only return records created at or after this time, in milliseconds since the epoch
+	//   required: false
+	//   type: integer
+	// - name: until
+	//   in: query
+	//   description: only return records created before this time, in milliseconds since the epoch
+	//   required: false
+	//   type: integer
+	// - name: page
+	//   in: query
+	//   description: the page to fetch
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: number of records per page
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/AuditRecordsList"
+	//   '403':
+	//     description: access denied
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardRoles) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to board audit records"})
+		return
+	}
+
+	opts, err := auditRecordsQueryOptions(r.URL.Query())
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	opts.BoardID = boardID
+	opts.TeamID = ""
+
+	auditRec := a.makeAuditRecord(r, "getBoardAuditRecords", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	a.writeAuditRecords(w, r, opts, auditRec)
+}
+
+func (a *API) handleAdminGetAuditRecords(w http.ResponseWriter, r *http.Request) {
+	opts, err := auditRecordsQueryOptions(r.URL.Query())
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminGetAuditRecords", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+
+	a.writeAuditRecords(w, r, opts, auditRec)
+}
+
+func (a *API) writeAuditRecords(w http.ResponseWriter, r *http.Request, opts model.QueryAuditRecordsOptions, auditRec *audit.Record) {
+	records, err := a.app.GetAuditRecords(opts)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("recordsCount", len(records.Items))
+	auditRec.Success()
+}
+
+// auditRecordsQueryOptions builds the audit records query options from the
+// request query parameters.
+func auditRecordsQueryOptions(query url.Values) (model.QueryAuditRecordsOptions, error) {
+	opts := model.QueryAuditRecordsOptions{
+		UserID:  query.Get("user_id"),
+		TeamID:  query.Get("team_id"),
+		BoardID: query.Get("board_id"),
+		Event:   query.Get("event"),
+	}
+
+	var err error
+	if opts.Since, err = int64QueryParam(query, "since"); err != nil {
+		return opts, err
+	}
+	if opts.Until, err = int64QueryParam(query, "until"); err != nil {
+		return opts, err
+	}
+
+	page, err := int64QueryParam(query, "page")
+	if err != nil {
+		return opts, err
+	}
+	perPage, err := int64QueryParam(query, "per_page")
+	if err != nil {
+		return opts, err
+	}
+	if page < 0 || perPage < 0 {
+		return opts, fmt.Errorf("invalid pagination parameters: page=%d, per_page=%d", page, perPage)
+	}
+	opts.Page = int(page)
+	opts.PerPage = int(perPage)
+
+	return opts, nil
+}
+
+func int64QueryParam(query url.Values, name string) (int64, error) {
+	str := query.Get(name)
+	if str == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s parameter: %w", name, err)
+	}
+	return value, nil
+}
@@ -25,6 +25,11 @@ func (a *API) registerBlocksRoutes(r *mux.Router) {
 	r.HandleFunc("/boards/{boardID}/blocks/{blockID}", a.sessionRequired(a.handlePatchBlock)).Methods("PATCH")
 	r.HandleFunc("/boards/{boardID}/blocks/{blockID}/undelete", a.sessionRequired(a.handleUndeleteBlock)).Methods("POST")
 	r.HandleFunc("/boards/{boardID}/blocks/{blockID}/duplicate", a.sessionRequired(a.handleDuplicateBlock)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/blocks/{blockID}/move", a.sessionRequired(a.handleMoveCard)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/blocks/{blockID}/copy", a.sessionRequired(a.handleCopyCard)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/blocks/{blockID}/archive", a.sessionRequired(a.handleArchiveCard)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/blocks/{blockID}/unarchive", a.sessionRequired(a.handleUnarchiveCard)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/cards/bulk", a.sessionRequired(a.handleBulkCardOperation)).Methods("POST")
 }
 
 func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
@@ -51,6 +56,31 @@ func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
 	//   description: Type of blocks to return, omit to specify all types
 	//   required: false
 	//   type: string
+	// - name: per_page
+	//   in: query
+	//   description: Number of blocks per page. When set, or when cursor or since are set, a BlocksPage is returned instead of an array
+	//   required: false
+	//   type: integer
+	// - name: cursor
+	//   in: query
+	//   description: Cursor returned by the previous page
+	//   required: false
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: Only return blocks updated at or after this time, in milliseconds since the epoch, and the blocks deleted since then
+	//   required: false
+	//   type: integer
+	// - name: include_archived
+	//   in: query
+	//   description: Return the archived cards and their child blocks, which are omitted by default. Pages of blocks always include them
+	//   required: false
+	//   type: boolean
+	// - name: If-None-Match
+	//   in: header
+	//   description: ETag of a previously fetched version of the blocks
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -60,6 +90,10 @@ func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
 	//       type: array
 	//       items:
 	//         "$ref": "#/definitions/Block"
+	//   '304':
+	//     description: the blocks have not been modified
+	//   '400':
+	//     description: invalid pagination parameters
 	//   '404':
 	//     description: board not found
 	//   default:
@@ -114,6 +148,11 @@ func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("all", all)
 	auditRec.AddMeta("blockID", blockID)
 
+	if all == "" && blockID == "" && (query.Has("per_page") || query.Has("cursor") || query.Has("since")) {
+		a.writeBlocksPage(w, r, boardID, parentID, blockType, auditRec)
+		return
+	}
+
 	var blocks []model.Block
 	var block *model.Block
 	switch {
@@ -145,6 +184,10 @@ func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if blockID == "" && query.Get("include_archived") != True {
+		blocks = model.WithoutArchivedCards(blocks)
+	}
+
 	a.logger.Debug("GetBlocks",
 		mlog.String("boardID", boardID),
 		mlog.String("parentID", parentID),
@@ -160,6 +203,16 @@ func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	etag := blocksEtag(blocks)
+	if blockID != "" && len(blocks) == 1 {
+		etag = blockEtag(&blocks[0])
+	}
+	if writeNotModifiedIfMatch(w, r, etag) {
+		auditRec.AddMeta("blockCount", len(blocks))
+		auditRec.Success()
+		return
+	}
+
 	json, err := json.Marshal(blocks)
 	if err != nil {
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
@@ -172,6 +225,58 @@ func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) writeBlocksPage(w http.ResponseWriter, r *http.Request, boardID, parentID, blockType string, auditRec *audit.Record) {
+	query := r.URL.Query()
+	opts := model.QueryBlocksPageOptions{
+		ParentID:  parentID,
+		BlockType: model.BlockType(blockType),
+	}
+
+	var err error
+	if opts.UpdatedSince, err = int64QueryParam(query, "since"); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	perPage, err := int64QueryParam(query, "per_page")
+	if err != nil || perPage < 0 {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "invalid per_page parameter", err)
+		return
+	}
+	opts.PerPage = int(perPage)
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		if opts.Cursor, err = model.DecodeBlocksCursor(cursor); err != nil {
+			a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+	}
+
+	page, err := a.app.GetBlocksPage(boardID, opts)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	page.Blocks, err = a.app.ApplyCloudLimits(page.Blocks)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(page)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("blockCount", len(page.Blocks))
+	auditRec.AddMeta("deletedCount", len(page.Deleted))
+	auditRec.Success()
+}
+
 func (a *API) handlePostBlocks(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /boards/{boardID}/blocks updateBlocks
 	//
@@ -206,10 +311,18 @@ func (a *API) handlePostBlocks(w http.ResponseWriter, r *http.Request) {
 	// responses:
 	//   '200':
 	//     description: success
+	//     headers:
+	//       X-Exceeded-WIP-Limits:
+	//         type: string
+	//         description: JSON array of the WIP limits exceeded by the change on a board that allows it
 	//     schema:
 	//       items:
 	//         $ref: '#/definitions/Block'
 	//       type: array
+	//   '400':
+	//     description: a card property value is invalid or a WIP limit of the board would be exceeded
+	//   '413':
+	//     description: the storage quota of the team is exceeded
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -283,15 +396,20 @@ func (a *API) handlePostBlocks(w http.ResponseWriter, r *http.Request) {
 	// this query param exists when creating template from board, or board from template
 	sourceBoardID := r.URL.Query().Get("sourceBoardID")
 	if sourceBoardID != "" {
-		if updateFileIDsErr := a.app.CopyCardFiles(sourceBoardID, blocks); updateFileIDsErr != nil {
+		updateFileIDsErr := a.app.CopyCardFiles(sourceBoardID, blocks)
+		if errors.Is(updateFileIDsErr, model.ErrTeamStorageQuotaExceeded) {
+			a.errorResponse(w, r.URL.Path, http.StatusRequestEntityTooLarge, updateFileIDsErr.Error(), updateFileIDsErr)
+			return
+		}
+		if updateFileIDsErr != nil {
 			a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", updateFileIDsErr)
 			return
 		}
 	}
 
-	newBlocks, err := a.app.InsertBlocks(blocks, session.UserID, !disableNotify)
+	newBlocks, exceeded, err := a.app.InsertBlocks(blocks, session.UserID, !disableNotify)
 	if err != nil {
-		if errors.Is(err, app.ErrViewsLimitReached) {
+		if errors.Is(err, app.ErrViewsLimitReached) || model.IsErrWIPLimitExceeded(err) || model.IsErrInvalidCardProperties(err) {
 			a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
 		} else {
 			a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
@@ -311,6 +429,10 @@ func (a *API) handlePostBlocks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = setExceededWIPLimitsHeader(w, exceeded); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
 	jsonBytesResponse(w, http.StatusOK, json)
 
 	auditRec.AddMeta("blockCount", len(blocks))
@@ -503,13 +625,28 @@ func (a *API) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
 	//   required: true
 	//   schema:
 	//     "$ref": "#/definitions/BlockPatch"
+	// - name: If-Match
+	//   in: header
+	//   description: ETag the block must match for the patch to be applied
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
 	//   '200':
 	//     description: success
+	//     headers:
+	//       X-Exceeded-WIP-Limits:
+	//         type: string
+	//         description: JSON array of the WIP limits exceeded by the change on a board that allows it
+	//   '400':
+	//     description: a card property value is invalid or a WIP limit of the board would be exceeded
 	//   '404':
 	//     description: block not found
+	//   '409':
+	//     description: the block doesn't have the expected update time
+	//   '412':
+	//     description: the block has been modified since it was fetched
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -553,16 +690,51 @@ func (a *API) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("boardID", boardID)
 	auditRec.AddMeta("blockID", blockID)
 
-	err = a.app.PatchBlock(blockID, patch, userID)
+	ifMatch := r.Header.Get("If-Match") != ""
+	if !a.checkIfMatch(w, r, blockEtag(block)) {
+		return
+	}
+	if ifMatch && patch.ExpectedUpdateAt == nil {
+		// the store checks the update time again when applying the patch, so
+		// a concurrent change made after the If-Match check is still detected
+		patch.ExpectedUpdateAt = &block.UpdateAt
+	}
+
+	exceeded, err := a.app.PatchBlock(blockID, patch, userID)
 	if errors.Is(err, app.ErrPatchUpdatesLimitedCards) {
 		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", err)
 		return
 	}
+	if model.IsErrBlockConflict(err) && ifMatch {
+		a.writePreconditionFailed(w, r, err)
+		return
+	}
+	if model.IsErrBlockConflict(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusConflict, err.Error(), err)
+		return
+	}
+	if model.IsErrWIPLimitExceeded(err) || model.IsErrInvalidCardProperties(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
 	if err != nil {
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
 		return
 	}
 
+	updatedBlock, err := a.app.GetBlockByID(blockID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+	if updatedBlock != nil {
+		setResponseHeader(w, "ETag", blockEtag(updatedBlock))
+	}
+	if err = setExceededWIPLimitsHeader(w, exceeded); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
 	a.logger.Debug("PATCH Block", mlog.String("boardID", boardID), mlog.String("blockID", blockID))
 	jsonStringResponse(w, http.StatusOK, "{}")
 
@@ -594,6 +766,14 @@ func (a *API) handlePatchBlocks(w http.ResponseWriter, r *http.Request) {
 	// responses:
 	//   '200':
 	//     description: success
+	//     headers:
+	//       X-Exceeded-WIP-Limits:
+	//         type: string
+	//         description: JSON array of the WIP limits exceeded by the change on a board that allows it
+	//   '400':
+	//     description: a card property value is invalid or a WIP limit of the board would be exceeded
+	//   '409':
+	//     description: a block doesn't have the expected update time
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -638,16 +818,29 @@ func (a *API) handlePatchBlocks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	err = a.app.PatchBlocks(teamID, patches, userID)
+	exceeded, err := a.app.PatchBlocks(teamID, patches, userID)
 	if errors.Is(err, app.ErrPatchUpdatesLimitedCards) {
 		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", err)
 		return
 	}
+	if model.IsErrBlockConflict(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusConflict, err.Error(), err)
+		return
+	}
+	if model.IsErrWIPLimitExceeded(err) || model.IsErrInvalidCardProperties(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
 	if err != nil {
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
 		return
 	}
 
+	if err = setExceededWIPLimitsHeader(w, exceeded); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
 	a.logger.Debug("PATCH Blocks", mlog.String("patches", strconv.Itoa(len(patches.BlockIDs))))
 	jsonStringResponse(w, http.StatusOK, "{}")
 
@@ -756,3 +949,476 @@ func (a *API) handleDuplicateBlock(w http.ResponseWriter, r *http.Request) {
 
 	auditRec.Success()
 }
+
+func (a *API) handleMoveCard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/blocks/{blockID}/move moveCard
+	//
+	// Moves a card and its child blocks to another board, mapping its
+	// property values to the properties of the destination board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: blockID
+	//   in: path
+	//   description: ID of the card to move
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the destination board
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CardTransferRequest"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CardTransferResult"
+	//   '400':
+	//     description: the card can't be moved to the board
+	//   '404':
+	//     description: board or card not found
+	//   '413':
+	//     description: the storage quota of the team is exceeded
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	a.handleTransferCard(w, r, true)
+}
+
+func (a *API) handleCopyCard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/blocks/{blockID}/copy copyCard
+	//
+	// Copies a card and its child blocks, except comments, to another
+	// board, mapping its property values to the properties of the
+	// destination board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: blockID
+	//   in: path
+	//   description: ID of the card to copy
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the destination board
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CardTransferRequest"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CardTransferResult"
+	//   '400':
+	//     description: the card can't be copied to the board
+	//   '404':
+	//     description: board or card not found
+	//   '413':
+	//     description: the storage quota of the team is exceeded
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	a.handleTransferCard(w, r, false)
+}
+
+func (a *API) handleTransferCard(w http.ResponseWriter, r *http.Request, move bool) {
+	boardID := mux.Vars(r)["boardID"]
+	cardID := mux.Vars(r)["blockID"]
+	userID := getUserID(r)
+
+	requestBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	var transfer *model.CardTransferRequest
+	if err = json.Unmarshal(requestBody, &transfer); err != nil || transfer == nil || transfer.BoardID == "" {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "a destination board is required", err)
+		return
+	}
+
+	sourcePermission := model.PermissionViewBoard
+	if move {
+		sourcePermission = model.PermissionManageBoardCards
+	}
+	if !a.permissions.HasPermissionToBoard(userID, boardID, sourcePermission) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to source board"})
+		return
+	}
+	if !a.permissions.HasPermissionToBoard(userID, transfer.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to destination board"})
+		return
+	}
+
+	card, err := a.app.GetBlockByID(cardID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+	if card == nil || card.BoardID != boardID {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", nil)
+		return
+	}
+
+	action := "copyCard"
+	if move {
+		action = "moveCard"
+	}
+	auditRec := a.makeAuditRecord(r, action, audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("cardID", cardID)
+	auditRec.AddMeta("destBoardID", transfer.BoardID)
+
+	var result *model.CardTransferResult
+	if move {
+		result, err = a.app.MoveCard(cardID, transfer.BoardID, userID)
+	} else {
+		result, err = a.app.CopyCard(cardID, transfer.BoardID, userID)
+	}
+	if model.IsErrNotFound(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", err)
+		return
+	}
+	if errors.Is(err, model.ErrTeamStorageQuotaExceeded) {
+		a.errorResponse(w, r.URL.Path, http.StatusRequestEntityTooLarge, err.Error(), err)
+		return
+	}
+	if errors.Is(err, app.ErrTransferNotACard) ||
+		errors.Is(err, app.ErrMoveCardToSameBoard) ||
+		errors.Is(err, app.ErrTransferBetweenTemplate) ||
+		model.IsErrWIPLimitExceeded(err) ||
+		model.IsErrInvalidCardProperties(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	a.logger.Debug(action,
+		mlog.String("cardID", cardID),
+		mlog.String("destBoardID", transfer.BoardID),
+		mlog.Int("unmappedCount", len(result.UnmappedProperties)),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("blockCount", len(result.Blocks))
+	auditRec.Success()
+}
+
+func (a *API) handleArchiveCard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/blocks/{blockID}/archive archiveCard
+	//
+	// Archives a card. Archived cards and their child blocks are hidden from
+	// the views of the board, but they are kept by the data retention and can
+	// be restored
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: blockID
+	//   in: path
+	//   description: ID of the card to archive
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Block"
+	//   '400':
+	//     description: the block isn't a card
+	//   '404':
+	//     description: board or card not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	a.handleSetCardArchived(w, r, true)
+}
+
+func (a *API) handleUnarchiveCard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/blocks/{blockID}/unarchive unarchiveCard
+	//
+	// Restores an archived card
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: blockID
+	//   in: path
+	//   description: ID of the card to restore
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Block"
+	//   '400':
+	//     description: the block isn't a card, or the card can't be restored
+	//   '404':
+	//     description: board or card not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	a.handleSetCardArchived(w, r, false)
+}
+
+func (a *API) handleSetCardArchived(w http.ResponseWriter, r *http.Request, archive bool) {
+	boardID := mux.Vars(r)["boardID"]
+	cardID := mux.Vars(r)["blockID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to make board changes"})
+		return
+	}
+
+	card, err := a.app.GetBlockByID(cardID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+	if card == nil || card.BoardID != boardID {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", nil)
+		return
+	}
+
+	action := "unarchiveCard"
+	if archive {
+		action = "archiveCard"
+	}
+	auditRec := a.makeAuditRecord(r, action, audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("cardID", cardID)
+
+	if archive {
+		card, err = a.app.ArchiveCard(cardID, userID)
+	} else {
+		card, err = a.app.UnarchiveCard(cardID, userID)
+	}
+	if errors.Is(err, app.ErrPatchUpdatesLimitedCards) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", err)
+		return
+	}
+	if errors.Is(err, app.ErrArchiveNotACard) ||
+		model.IsErrWIPLimitExceeded(err) ||
+		model.IsErrInvalidCardProperties(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	a.logger.Debug(action, mlog.String("cardID", cardID))
+
+	data, err := json.Marshal(card)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleBulkCardOperation(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/cards/bulk bulkCardOperation
+	//
+	// Applies an action to several cards of a board at once. The cards are
+	// selected by ID or by the filter of a view, and the changes are saved
+	// in a single transaction
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the card selection and the action to apply
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/BulkCardOperation"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/BulkCardResult"
+	//   '400':
+	//     description: invalid operation, or a card property value or WIP limit of the board would be invalid
+	//   '404':
+	//     description: board, view, property or card not found
+	//   '409':
+	//     description: a card was modified during the operation
+	//   '413':
+	//     description: the storage quota of the team is exceeded
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	op, err := model.BulkCardOperationFromJSON(r.Body)
+	if err != nil || op == nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "invalid bulk card operation", err)
+		return
+	}
+	if err = op.IsValid(); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to make board changes"})
+		return
+	}
+	if op.Action == model.BulkCardActionMoveToBoard &&
+		!a.permissions.HasPermissionToBoard(userID, op.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to destination board"})
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "bulkCardOperation", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("action", op.Action)
+	auditRec.AddMeta("viewID", op.ViewID)
+	if op.PropertyID != "" {
+		auditRec.AddMeta("propertyID", op.PropertyID)
+	}
+	if op.Action == model.BulkCardActionMoveToBoard {
+		auditRec.AddMeta("destBoardID", op.BoardID)
+	}
+
+	result, err := a.app.ApplyBulkCardOperation(boardID, op, userID)
+	if model.IsErrNotFound(err) || model.IsErrNotAllFound(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", err)
+		return
+	}
+	if errors.Is(err, app.ErrPatchUpdatesLimitedCards) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", err)
+		return
+	}
+	if model.IsErrBlockConflict(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusConflict, err.Error(), err)
+		return
+	}
+	if errors.Is(err, model.ErrTeamStorageQuotaExceeded) {
+		a.errorResponse(w, r.URL.Path, http.StatusRequestEntityTooLarge, err.Error(), err)
+		return
+	}
+	if errors.Is(err, model.ErrBulkCardsTooManyCards) ||
+		errors.Is(err, model.ErrBulkCardsInvalidProperty) ||
+		errors.Is(err, model.ErrBulkCardsUnknownOption) ||
+		errors.Is(err, model.ErrInvalidViewFilter) ||
+		errors.Is(err, app.ErrMoveCardToSameBoard) ||
+		errors.Is(err, app.ErrTransferBetweenTemplate) ||
+		model.IsErrWIPLimitExceeded(err) ||
+		model.IsErrInvalidCardProperties(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	a.logger.Debug("BulkCardOperation",
+		mlog.String("boardID", boardID),
+		mlog.String("action", op.Action),
+		mlog.Int("cardCount", len(result.CardIDs)),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("cardIDs", result.CardIDs)
+	auditRec.AddMeta("cardCount", len(result.CardIDs))
+	auditRec.Success()
+}
+
+// setExceededWIPLimitsHeader lists the WIP limits exceeded by a change that
+// the board allowed, so the clients can warn the user.
+func setExceededWIPLimitsHeader(w http.ResponseWriter, exceeded []*model.WIPLimitStatus) error {
+	if len(exceeded) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(exceeded)
+	if err != nil {
+		return err
+	}
+	setResponseHeader(w, HeaderExceededWIPLimits, string(data))
+	return nil
+}
@@ -22,6 +22,8 @@ func (a *API) registerBoardsRoutes(r *mux.Router) {
 	r.HandleFunc("/boards/{boardID}", a.sessionRequired(a.handleDeleteBoard)).Methods("DELETE")
 	r.HandleFunc("/boards/{boardID}/duplicate", a.sessionRequired(a.handleDuplicateBoard)).Methods("POST")
 	r.HandleFunc("/boards/{boardID}/undelete", a.sessionRequired(a.handleUndeleteBoard)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/archive", a.sessionRequired(a.handleArchiveBoard)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/unarchive", a.sessionRequired(a.handleUnarchiveBoard)).Methods("POST")
 	r.HandleFunc("/boards/{boardID}/metadata", a.sessionRequired(a.handleGetBoardMetadata)).Methods("GET")
 }
 
@@ -39,6 +41,11 @@ func (a *API) handleGetBoards(w http.ResponseWriter, r *http.Request) {
 	//   description: Team ID
 	//   required: true
 	//   type: string
+	// - name: include_archived
+	//   in: query
+	//   description: Return the archived boards, which are omitted by default
+	//   required: false
+	//   type: boolean
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -71,6 +78,9 @@ func (a *API) handleGetBoards(w http.ResponseWriter, r *http.Request) {
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
 		return
 	}
+	if r.URL.Query().Get("include_archived") != True {
+		boards = model.WithoutArchivedBoards(boards)
+	}
 
 	a.logger.Debug("GetBoards",
 		mlog.String("teamID", teamID),
@@ -193,6 +203,11 @@ func (a *API) handleGetBoard(w http.ResponseWriter, r *http.Request) {
 	//   description: Board ID
 	//   required: true
 	//   type: string
+	// - name: If-None-Match
+	//   in: header
+	//   description: ETag of a previously fetched version of the board
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -200,6 +215,8 @@ func (a *API) handleGetBoard(w http.ResponseWriter, r *http.Request) {
 	//     description: success
 	//     schema:
 	//       "$ref": "#/definitions/Board"
+	//   '304':
+	//     description: the board has not been modified
 	//   '404':
 	//     description: board not found
 	//   default:
@@ -248,6 +265,11 @@ func (a *API) handleGetBoard(w http.ResponseWriter, r *http.Request) {
 		mlog.String("boardID", boardID),
 	)
 
+	if writeNotModifiedIfMatch(w, r, boardEtag(board)) {
+		auditRec.Success()
+		return
+	}
+
 	data, err := json.Marshal(board)
 	if err != nil {
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
@@ -280,6 +302,11 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 	//   required: true
 	//   schema:
 	//     "$ref": "#/definitions/BoardPatch"
+	// - name: If-Match
+	//   in: header
+	//   description: ETag the board must match for the patch to be applied
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -289,6 +316,10 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 	//       $ref: '#/definitions/Board'
 	//   '404':
 	//     description: board not found
+	//   '409':
+	//     description: the board doesn't have the expected update time
+	//   '412':
+	//     description: the board has been modified since it was fetched
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -324,6 +355,11 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = patch.ValidateCardProperties(board); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
 	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
 		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to modifying board properties"})
 		return
@@ -335,6 +371,18 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if patch.ChangesWIPLimitMode() || patch.ChangesPropertyValidationMode() {
+		if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardType) {
+			a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to modifying board enforcement settings"})
+			return
+		}
+	}
+	if patch.ArchiveAt != nil {
+		if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionDeleteBoard) {
+			a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to archive board"})
+			return
+		}
+	}
 	if patch.ChannelID != nil {
 		if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardRoles) {
 			a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to modifying board access"})
@@ -347,8 +395,26 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("boardID", boardID)
 	auditRec.AddMeta("userID", userID)
 
+	ifMatch := r.Header.Get("If-Match") != ""
+	if !a.checkIfMatch(w, r, boardEtag(board)) {
+		return
+	}
+	if ifMatch && patch.ExpectedUpdateAt == nil {
+		// the store checks the update time again when applying the patch, so
+		// a concurrent change made after the If-Match check is still detected
+		patch.ExpectedUpdateAt = &board.UpdateAt
+	}
+
 	// patch board
 	updatedBoard, err := a.app.PatchBoard(patch, boardID, userID)
+	if model.IsErrBoardConflict(err) && ifMatch {
+		a.writePreconditionFailed(w, r, err)
+		return
+	}
+	if model.IsErrBoardConflict(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusConflict, err.Error(), err)
+		return
+	}
 	if err != nil {
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
 		return
@@ -366,6 +432,7 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// response
+	setResponseHeader(w, "ETag", boardEtag(updatedBoard))
 	jsonBytesResponse(w, http.StatusOK, data)
 
 	auditRec.Success()
@@ -454,6 +521,8 @@ func (a *API) handleDuplicateBoard(w http.ResponseWriter, r *http.Request) {
 	//       $ref: '#/definitions/BoardsAndBlocks'
 	//   '404':
 	//     description: board not found
+	//   '413':
+	//     description: the storage quota of the team is exceeded
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -511,6 +580,10 @@ func (a *API) handleDuplicateBoard(w http.ResponseWriter, r *http.Request) {
 	)
 
 	boardsAndBlocks, _, err := a.app.DuplicateBoard(boardID, userID, toTeam, asTemplate == True)
+	if errors.Is(err, model.ErrTeamStorageQuotaExceeded) {
+		a.errorResponse(w, r.URL.Path, http.StatusRequestEntityTooLarge, err.Error(), err)
+		return
+	}
 	if err != nil {
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, err.Error(), err)
 		return
@@ -580,6 +653,108 @@ func (a *API) handleUndeleteBoard(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleArchiveBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/archive archiveBoard
+	//
+	// Archives a board. Archived boards are hidden from the board lists, but
+	// they are still found by the search, kept by the data retention and can
+	// be restored
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: ID of board to archive
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Board"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	a.handleSetBoardArchived(w, r, true)
+}
+
+func (a *API) handleUnarchiveBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/unarchive unarchiveBoard
+	//
+	// Restores an archived board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: ID of board to restore
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Board"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	a.handleSetBoardArchived(w, r, false)
+}
+
+func (a *API) handleSetBoardArchived(w http.ResponseWriter, r *http.Request, archive bool) {
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	action := "unarchiveBoard"
+	if archive {
+		action = "archiveBoard"
+	}
+	auditRec := a.makeAuditRecord(r, action, audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionDeleteBoard) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to archive board"})
+		return
+	}
+
+	var board *model.Board
+	var err error
+	if archive {
+		board, err = a.app.ArchiveBoard(boardID, userID)
+	} else {
+		board, err = a.app.UnarchiveBoard(boardID, userID)
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	a.logger.Debug(action, mlog.String("boardID", boardID))
+
+	data, err := json.Marshal(board)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
 func (a *API) handleGetBoardMetadata(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /boards/{boardID}/metadata getBoardMetadata
 	//
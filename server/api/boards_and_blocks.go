@@ -188,6 +188,8 @@ func (a *API) handlePatchBoardsAndBlocks(w http.ResponseWriter, r *http.Request)
 	//     description: success
 	//     schema:
 	//       $ref: '#/definitions/BoardsAndBlocks'
+	//   '409':
+	//     description: a block doesn't have the expected update time
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -286,6 +288,10 @@ func (a *API) handlePatchBoardsAndBlocks(w http.ResponseWriter, r *http.Request)
 		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", err)
 		return
 	}
+	if model.IsErrBlockConflict(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusConflict, err.Error(), err)
+		return
+	}
 	if err != nil {
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
 		return
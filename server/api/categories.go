@@ -20,6 +20,13 @@ func (a *API) registerCategoriesRoutes(r *mux.Router) {
 	r.HandleFunc("/teams/{teamID}/categories/{categoryID}", a.sessionRequired(a.handleDeleteCategory)).Methods(http.MethodDelete)
 	r.HandleFunc("/teams/{teamID}/categories", a.sessionRequired(a.handleGetUserCategoryBoards)).Methods(http.MethodGet)
 	r.HandleFunc("/teams/{teamID}/categories/{categoryID}/boards/{boardID}", a.sessionRequired(a.handleUpdateCategoryBoard)).Methods(http.MethodPost)
+
+	// Shared category APIs
+	r.HandleFunc("/teams/{teamID}/shared-categories", a.sessionRequired(a.handleGetSharedCategoryBoards)).Methods(http.MethodGet)
+	r.HandleFunc("/teams/{teamID}/shared-categories", a.sessionRequired(a.handleCreateSharedCategory)).Methods(http.MethodPost)
+	r.HandleFunc("/teams/{teamID}/shared-categories/{categoryID}", a.sessionRequired(a.handleUpdateSharedCategory)).Methods(http.MethodPut)
+	r.HandleFunc("/teams/{teamID}/shared-categories/{categoryID}", a.sessionRequired(a.handleDeleteSharedCategory)).Methods(http.MethodDelete)
+	r.HandleFunc("/teams/{teamID}/shared-categories/{categoryID}/boards/{boardID}", a.sessionRequired(a.handleUpdateSharedCategoryBoard)).Methods(http.MethodPost)
 }
 
 func (a *API) handleCreateCategory(w http.ResponseWriter, r *http.Request) {
@@ -100,8 +107,18 @@ func (a *API) handleCreateCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// shared categories are managed through their own endpoints
+	if category.IsShared() {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "cannot create a shared category for a user", nil)
+		return
+	}
+
 	createdCategory, err := a.app.CreateCategory(&category)
 	if err != nil {
+		if errors.Is(err, app.ErrorInvalidCategoryParent) {
+			a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "", err)
+			return
+		}
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
 		return
 	}
@@ -204,6 +221,8 @@ func (a *API) handleUpdateCategory(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, app.ErrorCategoryDeleted):
 			a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", err)
+		case errors.Is(err, app.ErrorInvalidCategory), errors.Is(err, app.ErrorInvalidCategoryParent):
+			a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "", err)
 		case errors.Is(err, app.ErrorCategoryPermissionDenied):
 			// TODO: The permissions should be handled as much as possible at
 			// the API level, this needs to be changed
@@ -292,7 +311,7 @@ func (a *API) handleDeleteCategory(w http.ResponseWriter, r *http.Request) {
 func (a *API) handleGetUserCategoryBoards(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /teams/{teamID}/categories getUserCategoryBoards
 	//
-	// Gets the user's board categories
+	// Gets the user's board categories, followed by the shared categories of the team
 	//
 	// ---
 	// produces:
@@ -333,6 +352,15 @@ func (a *API) handleGetUserCategoryBoards(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		sharedCategoryBlocks, sharedErr := a.app.GetSharedCategoryBoards(userID, teamID)
+		if sharedErr != nil {
+			a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", sharedErr)
+			return
+		}
+		categoryBlocks = append(categoryBlocks, sharedCategoryBlocks...)
+	}
+
 	data, err := json.Marshal(categoryBlocks)
 	if err != nil {
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
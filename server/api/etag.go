@@ -0,0 +1,90 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// boardEtag returns the strong ETag of a board, derived from its update time.
+func boardEtag(board *model.Board) string {
+	return fmt.Sprintf(`"%s-%d"`, board.ID, board.UpdateAt)
+}
+
+// blockEtag returns the strong ETag of a block, derived from its update time.
+func blockEtag(block *model.Block) string {
+	return fmt.Sprintf(`"%s-%d"`, block.ID, block.UpdateAt)
+}
+
+// blocksEtag returns the strong ETag of a list of blocks. It changes when any
+// of the blocks is updated, added or removed.
+func blocksEtag(blocks []model.Block) string {
+	entries := make([]string, len(blocks))
+	for i, block := range blocks {
+		entries[i] = block.ID + ":" + strconv.FormatInt(block.UpdateAt, 10) + ":" + strconv.FormatBool(block.Limited)
+	}
+	sort.Strings(entries)
+
+	hash := sha256.Sum256([]byte(strings.Join(entries, ",")))
+	return `"` + hex.EncodeToString(hash[:16]) + `"`
+}
+
+// etagMatches reports whether the value of an If-Match or If-None-Match
+// header matches the given ETag. With the weak comparison of If-None-Match,
+// the weak validators match as well; with the strong comparison of If-Match,
+// they never match, as per RFC 7232.
+func etagMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeNotModifiedIfMatch sets the ETag header of the response and, if the
+// request's If-None-Match header matches it, writes a 304 response. Returns
+// true if the response has been written.
+func writeNotModifiedIfMatch(w http.ResponseWriter, r *http.Request, etag string) bool {
+	setResponseHeader(w, "ETag", etag)
+
+	ifNoneMatch := r.Header.Get("If-None-Match")
+	if ifNoneMatch == "" || !etagMatches(ifNoneMatch, etag, true) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// checkIfMatch writes a 412 response if the request has an If-Match header
+// that doesn't match the current ETag of the resource. Returns false if the
+// request must not proceed.
+func (a *API) checkIfMatch(w http.ResponseWriter, r *http.Request, etag string) bool {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" || etagMatches(ifMatch, etag, false) {
+		return true
+	}
+
+	a.writePreconditionFailed(w, r, nil)
+	return false
+}
+
+// writePreconditionFailed writes the 412 response of a request whose If-Match
+// header no longer matches the resource when the change is applied.
+func (a *API) writePreconditionFailed(w http.ResponseWriter, r *http.Request, err error) {
+	a.errorResponse(w, r.URL.Path, http.StatusPreconditionFailed, "the resource has been modified", err)
+}
@@ -0,0 +1,298 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/services/ical"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+func (a *API) registerFeedTokenRoutes(r *mux.Router) {
+	r.HandleFunc("/users/me/feed-token", a.sessionRequired(a.handleGetFeedToken)).Methods("GET")
+	r.HandleFunc("/users/me/feed-token", a.sessionRequired(a.handleRegenerateFeedToken)).Methods("POST")
+	r.HandleFunc("/users/me/feed-token", a.sessionRequired(a.handleRevokeFeedToken)).Methods("DELETE")
+}
+
+// registerFeedRoutes registers the calendar feeds, outside the /api/v2 path
+// as the calendar apps can't send the CSRF header. They are authenticated
+// by the feed token of a user instead of a session.
+func (a *API) registerFeedRoutes(r *mux.Router) {
+	r.HandleFunc("/feeds/boards/{boardID}.ics", a.handleGetBoardFeed).Methods("GET")
+	r.HandleFunc("/feeds/users/{userID}.ics", a.handleGetUserFeed).Methods("GET")
+}
+
+func (a *API) handleGetFeedToken(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /users/me/feed-token getFeedToken
+	//
+	// Returns the token authenticating the calendar feeds of the current user.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/FeedToken"
+	//   '404':
+	//     description: the user has no feed token
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	feedToken, err := a.app.GetFeedToken(userID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+	if feedToken == nil {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", nil)
+		return
+	}
+
+	data, err := json.Marshal(feedToken)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleRegenerateFeedToken(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /users/me/feed-token regenerateFeedToken
+	//
+	// Generates a new token authenticating the calendar feeds of the current user. The
+	// previous token is revoked, so the calendar apps using it must subscribe again.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/FeedToken"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "regenerateFeedToken", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	feedToken, err := a.app.RegenerateFeedToken(userID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(feedToken)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleRevokeFeedToken(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /users/me/feed-token revokeFeedToken
+	//
+	// Revokes the token authenticating the calendar feeds of the current user.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: the user has no feed token
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "revokeFeedToken", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	if err := a.app.RevokeFeedToken(userID); err != nil {
+		if model.IsErrNotFound(err) {
+			a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", err)
+			return
+		}
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetBoardFeed(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /feeds/boards/{boardID}.ics getBoardFeed
+	//
+	// Returns the iCalendar feed of the cards of a board, at the dates of one of its date
+	// properties. The feed is authenticated by the feed token of a user that can view the board.
+	//
+	// ---
+	// produces:
+	// - text/calendar
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: token
+	//   in: query
+	//   description: Feed token of the user
+	//   required: true
+	//   type: string
+	// - name: property
+	//   in: query
+	//   description: ID of the date property of the cards, the first one of the board by default
+	//   required: false
+	//   type: string
+	// responses:
+	//   '200':
+	//     description: success
+	//   '400':
+	//     description: the property isn't a date property
+	//   '401':
+	//     description: invalid feed token
+	//   '403':
+	//     description: access denied to the board
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+
+	userID, ok := a.feedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to board"})
+		return
+	}
+
+	calendar, err := a.app.GetBoardCalendar(boardID, r.URL.Query().Get("property"))
+	if errors.Is(err, model.ErrFeedPropertyNotDate) {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if model.IsErrNotFound(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", err)
+		return
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	a.calendarResponse(w, r, calendar)
+}
+
+func (a *API) handleGetUserFeed(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /feeds/users/{userID}.ics getUserFeed
+	//
+	// Returns the iCalendar feed of the cards assigned to a user, across the boards they
+	// are a member of, at the dates of the first date property of each board. The feed is
+	// authenticated by the feed token of the user.
+	//
+	// ---
+	// produces:
+	// - text/calendar
+	// parameters:
+	// - name: userID
+	//   in: path
+	//   description: User ID
+	//   required: true
+	//   type: string
+	// - name: token
+	//   in: query
+	//   description: Feed token of the user
+	//   required: true
+	//   type: string
+	// responses:
+	//   '200':
+	//     description: success
+	//   '401':
+	//     description: invalid feed token
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID, ok := a.feedUserID(w, r)
+	if !ok {
+		return
+	}
+	if userID != mux.Vars(r)["userID"] {
+		a.errorResponse(w, r.URL.Path, http.StatusUnauthorized, "", model.ErrInvalidFeedToken)
+		return
+	}
+
+	calendar, err := a.app.GetUserCalendar(userID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	a.calendarResponse(w, r, calendar)
+}
+
+// feedUserID returns the ID of the user of the feed token of a request, or
+// writes an error if the token is invalid.
+func (a *API) feedUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID, err := a.app.GetFeedTokenUserID(r.URL.Query().Get("token"))
+	if errors.Is(err, model.ErrInvalidFeedToken) {
+		a.errorResponse(w, r.URL.Path, http.StatusUnauthorized, "", err)
+		return "", false
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return "", false
+	}
+	return userID, true
+}
+
+func (a *API) calendarResponse(w http.ResponseWriter, r *http.Request, calendar *ical.Calendar) {
+	var buf bytes.Buffer
+	if err := calendar.Write(&buf); err != nil {
+		a.logger.Error("cannot write calendar feed", mlog.String("path", r.URL.Path), mlog.Err(err))
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	setResponseHeader(w, "Content-Type", "text/calendar; charset=utf-8")
+	setResponseHeader(w, "Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
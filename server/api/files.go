@@ -2,16 +2,20 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
+	"mime"
 	"net/http"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/app"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
 
+	mmModel "github.com/mattermost/mattermost-server/v6/model"
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
 )
 
@@ -36,6 +40,9 @@ func (a *API) registerFilesRoutes(r *mux.Router) {
 	// Files API
 	r.HandleFunc("/files/teams/{teamID}/{boardID}/{filename}", a.attachSession(a.handleServeFile, false)).Methods("GET")
 	r.HandleFunc("/teams/{teamID}/{boardID}/files", a.sessionRequired(a.handleUploadFile)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/storage", a.sessionRequired(a.handleGetTeamStorageUsage)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/attachments", a.sessionRequired(a.handleGetBoardAttachments)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/attachments/{fileID}", a.sessionRequired(a.handleDeleteAttachment)).Methods("DELETE")
 }
 
 func (a *API) handleServeFile(w http.ResponseWriter, r *http.Request) {
@@ -65,13 +72,24 @@ func (a *API) handleServeFile(w http.ResponseWriter, r *http.Request) {
 	//   description: name of the file
 	//   required: true
 	//   type: string
+	// - name: size
+	//   in: query
+	//   description: thumb or preview to get a JPEG thumbnail or preview of an image, instead of the original file
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
 	//   '200':
 	//     description: success
+	//   '400':
+	//     description: invalid size
+	//   '403':
+	//     description: the file is infected
 	//   '404':
 	//     description: file not found
+	//   '409':
+	//     description: the file is quarantined until it is scanned
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -80,8 +98,14 @@ func (a *API) handleServeFile(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	boardID := vars["boardID"]
 	filename := vars["filename"]
+	size := r.URL.Query().Get("size")
 	userID := getUserID(r)
 
+	if size != "" && size != app.FileSizeThumbnail && size != app.FileSizePreview {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "", app.ErrInvalidFileSize)
+		return
+	}
+
 	hasValidReadToken := a.hasValidReadTokenForBoard(r, boardID)
 	if userID == "" && !hasValidReadToken {
 		a.errorResponse(w, r.URL.Path, http.StatusUnauthorized, "", nil)
@@ -108,20 +132,10 @@ func (a *API) handleServeFile(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("boardID", boardID)
 	auditRec.AddMeta("teamID", board.TeamID)
 	auditRec.AddMeta("filename", filename)
-
-	contentType := "image/jpg"
-
-	fileExtension := strings.ToLower(filepath.Ext(filename))
-	if fileExtension == "png" {
-		contentType = "image/png"
+	if size != "" {
+		auditRec.AddMeta("size", size)
 	}
 
-	if fileExtension == "gif" {
-		contentType = "image/gif"
-	}
-
-	w.Header().Set("Content-Type", contentType)
-
 	fileInfo, err := a.app.GetFileInfo(filename)
 	if err != nil {
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
@@ -147,9 +161,35 @@ func (a *API) handleServeFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if size != "" && fileInfo != nil {
+		// the thumbnails are generated before the files are scanned
+		if err := a.app.CheckFileScan(boardID, filename); err != nil {
+			a.fileScanErrorResponse(w, r, err)
+			return
+		}
+
+		imageReader, err := a.app.GetFileImageReader(fileInfo, size)
+		if err != nil {
+			a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+			return
+		}
+		// files without thumbnails, like the ones uploaded before they
+		// were generated, are served in full
+		if imageReader != nil {
+			defer imageReader.Close()
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			http.ServeContent(w, r, filename, time.Now(), imageReader)
+			auditRec.Success()
+			return
+		}
+	}
+
+	setFileContentHeaders(w, fileInfo, filename)
+
 	fileReader, err := a.app.GetFileReader(board.TeamID, boardID, filename)
 	if err != nil {
-		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		a.fileScanErrorResponse(w, r, err)
 		return
 	}
 	defer fileReader.Close()
@@ -157,6 +197,60 @@ func (a *API) handleServeFile(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+// fileScanErrorResponse writes the error of a file that can't be served,
+// telling apart the files blocked by their malware scan.
+func (a *API) fileScanErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, model.ErrFileInfected):
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, err.Error(), err)
+	case errors.Is(err, model.ErrFileQuarantined):
+		a.errorResponse(w, r.URL.Path, http.StatusConflict, err.Error(), err)
+	default:
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+	}
+}
+
+// setFileContentHeaders sets the content type of a file to the MIME type
+// detected when it was uploaded. Only images are displayed inline, other
+// files are downloaded, so that uploaded documents like HTML pages aren't
+// rendered by the browsers.
+func setFileContentHeaders(w http.ResponseWriter, fileInfo *mmModel.FileInfo, filename string) {
+	contentType := ""
+	if fileInfo != nil && fileInfo.MimeType != "" && fileInfo.MimeType != "empty" {
+		contentType = fileInfo.MimeType
+	} else {
+		// files uploaded before the MIME types were detected
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".png":
+			contentType = "image/png"
+		case ".gif":
+			contentType = "image/gif"
+		default:
+			contentType = "image/jpg"
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if !inlineContentTypes[contentType] {
+		name := filename
+		if fileInfo != nil && fileInfo.Name != "" {
+			name = fileInfo.Name
+		}
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	}
+}
+
+// inlineContentTypes are the types of the files displayed by the browsers.
+var inlineContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/jpg":  true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+	"image/bmp":  true,
+}
+
 func (a *API) handleUploadFile(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /teams/{teamID}/boards/{boardID}/files uploadFile
 	//
@@ -191,6 +285,8 @@ func (a *API) handleUploadFile(w http.ResponseWriter, r *http.Request) {
 	//       "$ref": "#/definitions/FileUploadResponse"
 	//   '404':
 	//     description: board not found
+	//   '413':
+	//     description: file too large, or storage quota of the team exceeded
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -236,7 +332,11 @@ func (a *API) handleUploadFile(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("teamID", board.TeamID)
 	auditRec.AddMeta("filename", handle.Filename)
 
-	fileID, err := a.app.SaveFile(file, board.TeamID, boardID, handle.Filename)
+	fileID, err := a.app.SaveFile(file, board.TeamID, boardID, handle.Filename, userID)
+	if errors.Is(err, model.ErrTeamStorageQuotaExceeded) {
+		a.errorResponse(w, r.URL.Path, http.StatusRequestEntityTooLarge, err.Error(), err)
+		return
+	}
 	if err != nil {
 		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
 		return
@@ -257,3 +357,200 @@ func (a *API) handleUploadFile(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("fileID", fileID)
 	auditRec.Success()
 }
+
+func (a *API) handleGetBoardAttachments(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/attachments getBoardAttachments
+	//
+	// Returns the files attached to the cards of a board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: parent_id
+	//   in: query
+	//   description: ID of a card, to only return its files
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Attachment"
+	//   '404':
+	//     description: board not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	parentID := r.URL.Query().Get("parent_id")
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to board"})
+		return
+	}
+
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+	if board == nil {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", nil)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardAttachments", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	if parentID != "" {
+		auditRec.AddMeta("parentID", parentID)
+	}
+
+	attachments, err := a.app.GetBoardAttachments(boardID, parentID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(attachments)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("attachmentCount", len(attachments))
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /boards/{boardID}/attachments/{fileID} deleteAttachment
+	//
+	// Deletes the image blocks of a board displaying a file. The file is
+	// kept while the blocks can be restored, then removed by the orphan
+	// files cleanup
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: fileID
+	//   in: path
+	//   description: ID of the file to delete
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: board or file not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	fileID := vars["fileID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to make board changes"})
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteAttachment", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("fileID", fileID)
+
+	err := a.app.DeleteAttachment(boardID, fileID, userID)
+	if model.IsErrNotFound(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", err)
+		return
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	a.logger.Debug("DELETE Attachment", mlog.String("boardID", boardID), mlog.String("fileID", fileID))
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetTeamStorageUsage(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/storage getTeamStorageUsage
+	//
+	// Returns the size of the files uploaded in the boards of a team, and its quota
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/TeamStorageUsage"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to team"})
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getTeamStorageUsage", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	usage, err := a.app.GetTeamStorageUsage(teamID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(usage)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
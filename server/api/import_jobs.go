@@ -0,0 +1,317 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+func (a *API) registerImportJobsRoutes(r *mux.Router) {
+	r.HandleFunc("/teams/{teamID}/archive/import-jobs", a.sessionRequired(a.handleCreateImportJob)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/archive/import-jobs", a.sessionRequired(a.handleGetImportJobs)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/archive/import-jobs/{jobID}", a.sessionRequired(a.handleGetImportJob)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/archive/import-jobs/{jobID}/resume", a.sessionRequired(a.handleResumeImportJob)).Methods("POST")
+}
+
+func (a *API) handleCreateImportJob(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/archive/import-jobs createImportJob
+	//
+	// Starts importing an archive of boards in the background. The progress of the import is
+	// polled with the returned job ID, and sent to the user over websocket.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// consumes:
+	// - multipart/form-data
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: dry_run
+	//   in: query
+	//   description: If true, the archive is only validated and nothing is imported
+	//   required: false
+	//   type: boolean
+	// - name: file
+	//   in: formData
+	//   description: archive file to import
+	//   required: true
+	//   type: file
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '202':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ImportJob"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session, _ := ctx.Value(sessionContextKey).(*model.Session)
+	userID := session.UserID
+
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to create board"})
+		return
+	}
+
+	file, handle, err := r.FormFile(UploadFormFileKey)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	defer file.Close()
+
+	dryRun := r.URL.Query().Get("dry_run") == True
+
+	auditRec := a.makeAuditRecord(r, "createImportJob", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("filename", handle.Filename)
+	auditRec.AddMeta("size", handle.Size)
+	auditRec.AddMeta("dryRun", dryRun)
+
+	job := &model.ImportJob{
+		TeamID:    teamID,
+		CreatedBy: userID,
+		DryRun:    dryRun,
+		Filename:  handle.Filename,
+	}
+
+	job, err = a.app.CreateImportJob(job, file)
+	if err != nil {
+		a.logger.Debug("Error creating import job",
+			mlog.String("team_id", teamID),
+			mlog.Err(err),
+		)
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusAccepted, data)
+
+	auditRec.AddMeta("jobID", job.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleGetImportJobs(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/archive/import-jobs getImportJobs
+	//
+	// Returns the import jobs started by the current user in a team, from the most recent one.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/ImportJob"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session, _ := ctx.Value(sessionContextKey).(*model.Session)
+	userID := session.UserID
+
+	teamID := mux.Vars(r)["teamID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to team"})
+		return
+	}
+
+	jobs, err := a.app.GetImportJobsForUser(teamID, userID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(jobs)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleGetImportJob(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/archive/import-jobs/{jobID} getImportJob
+	//
+	// Returns the status, progress and report of an import job.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: jobID
+	//   in: path
+	//   description: Import job ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ImportJob"
+	//   '404':
+	//     description: job not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	job, ok := a.getImportJobForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleResumeImportJob(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/archive/import-jobs/{jobID}/resume resumeImportJob
+	//
+	// Resumes a failed or interrupted import job. The boards already imported by the job are
+	// skipped.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: jobID
+	//   in: path
+	//   description: Import job ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '202':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ImportJob"
+	//   '404':
+	//     description: job not found
+	//   '409':
+	//     description: the job is running or completed
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	job, ok := a.getImportJobForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "resumeImportJob", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", job.TeamID)
+	auditRec.AddMeta("jobID", job.ID)
+
+	job, err := a.app.ResumeImportJob(job.ID)
+	if errors.Is(err, model.ErrImportJobNotResumable) {
+		a.errorResponse(w, r.URL.Path, http.StatusConflict, err.Error(), err)
+		return
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusAccepted, data)
+	auditRec.Success()
+}
+
+// getImportJobForRequest returns the import job of a request, which is only
+// visible to the user that started it. It writes the error response and
+// returns false if there is none.
+func (a *API) getImportJobForRequest(w http.ResponseWriter, r *http.Request) (*model.ImportJob, bool) {
+	ctx := r.Context()
+	session, _ := ctx.Value(sessionContextKey).(*model.Session)
+	userID := session.UserID
+
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	jobID := vars["jobID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to team"})
+		return nil, false
+	}
+
+	job, err := a.app.GetImportJob(jobID)
+	if model.IsErrNotFound(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", err)
+		return nil, false
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return nil, false
+	}
+
+	if job.TeamID != teamID || job.CreatedBy != userID {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", model.NewErrNotFound(jobID))
+		return nil, false
+	}
+	return job, true
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/utils"
 
 	mmModel "github.com/mattermost/mattermost-server/v6/model"
 )
@@ -17,6 +19,7 @@ func (a *API) registerInsightsRoutes(r *mux.Router) {
 	// Insights APIs
 	r.HandleFunc("/teams/{teamID}/boards/insights", a.sessionRequired(a.handleTeamBoardsInsights)).Methods("GET")
 	r.HandleFunc("/users/me/boards/insights", a.sessionRequired(a.handleUserBoardsInsights)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/insights", a.sessionRequired(a.handleGetBoardChart)).Methods("GET")
 }
 
 func (a *API) handleTeamBoardsInsights(w http.ResponseWriter, r *http.Request) {
@@ -240,3 +243,162 @@ func (a *API) handleUserBoardsInsights(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("userBoardInsightCount", len(boardsInsights.Items))
 	auditRec.Success()
 }
+
+func (a *API) handleGetBoardChart(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/insights getBoardChart
+	//
+	// Returns a chart computed from the history of the cards of a board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: chart
+	//   in: query
+	//   description: The chart to compute, one of cumulativeFlow, cycleTime, throughput or burndown
+	//   required: true
+	//   type: string
+	// - name: property_id
+	//   in: query
+	//   description: ID of the select property the cards are grouped by
+	//   required: true
+	//   type: string
+	// - name: start_value
+	//   in: query
+	//   description: Option ID where the cycle time starts, required by the cycleTime chart
+	//   required: false
+	//   type: string
+	// - name: end_value
+	//   in: query
+	//   description: Option ID marking cards as done, required by the cycleTime, throughput and burndown charts
+	//   required: false
+	//   type: string
+	// - name: date_property_id
+	//   in: query
+	//   description: ID of the date property the burndown is computed against, required by the burndown chart
+	//   required: false
+	//   type: string
+	// - name: from
+	//   in: query
+	//   description: Start of the time range in milliseconds since the epoch. Defaults to 30 days before to
+	//   required: false
+	//   type: integer
+	// - name: to
+	//   in: query
+	//   description: End of the time range in milliseconds since the epoch. Defaults to now
+	//   required: false
+	//   type: integer
+	// - name: interval
+	//   in: query
+	//   description: Length of the chart buckets, day or week. Defaults to week for throughput and day otherwise
+	//   required: false
+	//   type: string
+	// - name: time_zone
+	//   in: query
+	//   description: Time zone used to align the buckets. Defaults to the time zone of the user
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/BoardChart"
+	//   '400':
+	//     description: invalid chart options
+	//   '404':
+	//     description: board not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+	query := r.URL.Query()
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to board"})
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardChart", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	opts := &model.BoardChartOptions{
+		Chart:          query.Get("chart"),
+		PropertyID:     query.Get("property_id"),
+		StartValue:     query.Get("start_value"),
+		EndValue:       query.Get("end_value"),
+		DatePropertyID: query.Get("date_property_id"),
+		Interval:       query.Get("interval"),
+		To:             utils.GetMillis(),
+	}
+	auditRec.AddMeta("chart", opts.Chart)
+
+	if opts.Interval == "" {
+		opts.Interval = model.BoardChartIntervalDay
+		if opts.Chart == model.BoardChartThroughput {
+			opts.Interval = model.BoardChartIntervalWeek
+		}
+	}
+
+	var err error
+	if to := query.Get("to"); to != "" {
+		if opts.To, err = strconv.ParseInt(to, 10, 64); err != nil {
+			a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "invalid to parameter", err)
+			return
+		}
+	}
+	opts.From = opts.To - 30*24*time.Hour.Milliseconds()
+	if from := query.Get("from"); from != "" {
+		if opts.From, err = strconv.ParseInt(from, 10, 64); err != nil {
+			a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "invalid from parameter", err)
+			return
+		}
+	}
+
+	timeZone := query.Get("time_zone")
+	if timeZone == "" {
+		// the time zone of the user is only known when running as a plugin
+		timeZone, _ = a.app.GetUserTimezone(userID)
+	} else if _, err = time.LoadLocation(timeZone); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "invalid time_zone parameter", err)
+		return
+	}
+	opts.Location, _ = time.LoadLocation(timeZone)
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	chart, err := a.app.GetBoardChart(boardID, opts)
+	if errors.Is(err, model.ErrInvalidBoardChartOptions) {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if model.IsErrNotFound(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", err)
+		return
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(chart)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
@@ -0,0 +1,398 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/app"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+)
+
+func (a *API) handleGetSharedCategoryBoards(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/shared-categories getSharedCategoryBoards
+	//
+	// Gets the shared board categories of a team
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       items:
+	//         "$ref": "#/definitions/CategoryBoards"
+	//       type: array
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	teamID := mux.Vars(r)["teamID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to team"})
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getSharedCategoryBoards", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	categoryBoards, err := a.app.GetSharedCategoryBoards(userID, teamID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(categoryBoards)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleCreateSharedCategory(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/shared-categories createSharedCategory
+	//
+	// Create a category shared with the whole team. Requires the manage team permission
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: category to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/Category"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Category"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	teamID := mux.Vars(r)["teamID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to manage shared categories"})
+		return
+	}
+
+	requestBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	var category model.Category
+	if err = json.Unmarshal(requestBody, &category); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "", err)
+		return
+	}
+
+	if category.TeamID != teamID {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "teamID mismatch", nil)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createSharedCategory", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	category.Type = model.CategoryTypeShared
+	category.UserID = ""
+
+	createdCategory, err := a.app.CreateCategory(&category)
+	if err != nil {
+		a.sharedCategoryErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(createdCategory)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("categoryID", createdCategory.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleUpdateSharedCategory(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /teams/{teamID}/shared-categories/{categoryID} updateSharedCategory
+	//
+	// Update a category shared with the whole team. Requires the manage team permission
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: categoryID
+	//   in: path
+	//   description: Category ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: category to update
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/Category"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Category"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	categoryID := vars["categoryID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to manage shared categories"})
+		return
+	}
+
+	requestBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	var category model.Category
+	if err = json.Unmarshal(requestBody, &category); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "", err)
+		return
+	}
+
+	if category.ID != categoryID {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "categoryID mismatch in patch and body", nil)
+		return
+	}
+
+	if category.TeamID != teamID {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "teamID mismatch", nil)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "updateSharedCategory", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("categoryID", categoryID)
+
+	category.Type = model.CategoryTypeShared
+	category.UserID = ""
+
+	updatedCategory, err := a.app.UpdateCategory(&category)
+	if err != nil {
+		a.sharedCategoryErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(updatedCategory)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteSharedCategory(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /teams/{teamID}/shared-categories/{categoryID} deleteSharedCategory
+	//
+	// Delete a category shared with the whole team. Nested categories are
+	// moved to its parent. Requires the manage team permission
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: categoryID
+	//   in: path
+	//   description: Category ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	categoryID := vars["categoryID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to manage shared categories"})
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteSharedCategory", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("categoryID", categoryID)
+
+	// shared categories don't belong to any user
+	deletedCategory, err := a.app.DeleteCategory(categoryID, "", teamID)
+	if err != nil {
+		a.sharedCategoryErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(deletedCategory)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleUpdateSharedCategoryBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/shared-categories/{categoryID}/boards/{boardID} updateSharedCategoryBoard
+	//
+	// Set the shared category of a board. The category ID "0" removes the
+	// board from its shared category. Requires the manage team permission
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: categoryID
+	//   in: path
+	//   description: Category ID
+	//   required: true
+	//   type: string
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	categoryID := vars["categoryID"]
+	boardID := vars["boardID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to manage shared categories"})
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to board"})
+		return
+	}
+
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+	if board == nil || board.TeamID != teamID {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "board doesn't belong to the team", nil)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "updateSharedCategoryBoard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("categoryID", categoryID)
+	auditRec.AddMeta("boardID", boardID)
+
+	if err := a.app.AddUpdateSharedCategoryBoard(teamID, categoryID, boardID); err != nil {
+		a.sharedCategoryErrorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) sharedCategoryErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	var errInvalidCategory *model.ErrInvalidCategory
+
+	switch {
+	case model.IsErrNotFound(err), errors.Is(err, app.ErrorCategoryDeleted):
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", err)
+	case errors.Is(err, app.ErrorCategoryPermissionDenied):
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", err)
+	case errors.Is(err, app.ErrorInvalidCategory),
+		errors.Is(err, app.ErrorInvalidCategoryParent),
+		errors.As(err, &errInvalidCategory):
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "", err)
+	default:
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+	}
+}
@@ -2,17 +2,35 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/app"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
 
+	mmModel "github.com/mattermost/mattermost-server/v6/model"
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
 )
 
 func (a *API) registerTemplatesRoutes(r *mux.Router) {
 	r.HandleFunc("/teams/{teamID}/templates", a.sessionRequired(a.handleGetTemplates)).Methods("GET")
+
+	// Template library APIs
+	r.HandleFunc("/teams/{teamID}/templates/library", a.sessionRequired(a.handleGetTemplateLibrary)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/templates/import", a.sessionRequired(a.handleImportTemplates)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/publish", a.sessionRequired(a.handlePublishTemplate)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/template/update", a.sessionRequired(a.handleUpdateBoardFromTemplate)).Methods("POST")
+	r.HandleFunc("/templates/{templateID}", a.sessionRequired(a.handleGetTemplate)).Methods("GET")
+	r.HandleFunc("/templates/{templateID}", a.sessionRequired(a.handlePatchTemplate)).Methods("PATCH")
+	r.HandleFunc("/templates/{templateID}", a.sessionRequired(a.handleUnpublishTemplate)).Methods("DELETE")
+	r.HandleFunc("/templates/{templateID}/propagate", a.sessionRequired(a.handlePropagateTemplate)).Methods("POST")
+	r.HandleFunc("/templates/{templateID}/export", a.sessionRequired(a.handleExportTemplate)).Methods("GET")
 }
 
 func (a *API) handleGetTemplates(w http.ResponseWriter, r *http.Request) {
@@ -88,3 +106,686 @@ func (a *API) handleGetTemplates(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("templatesCount", len(results))
 	auditRec.Success()
 }
+
+// getTemplateWithPermission fetches the template of the request and checks
+// that the user has the given permission on its template board. Team members
+// can view the templates of their team. Writes the error response and returns
+// nil if the request must not proceed.
+func (a *API) getTemplateWithPermission(w http.ResponseWriter, r *http.Request, permission *mmModel.Permission) *model.Template {
+	templateID := mux.Vars(r)["templateID"]
+	userID := getUserID(r)
+
+	template, err := a.app.GetTemplate(templateID)
+	if model.IsErrNotFound(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", err)
+		return nil
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return nil
+	}
+
+	if !a.permissions.HasPermissionToTeam(userID, template.TeamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to template"})
+		return nil
+	}
+
+	if permission == model.PermissionViewBoard && template.Board.Type == model.BoardTypeOpen {
+		return template
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, template.BoardID, permission) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to template"})
+		return nil
+	}
+	return template
+}
+
+func (a *API) handleGetTemplateLibrary(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/templates/library getTemplateLibrary
+	//
+	// Returns the templates published to the template library of a team
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: category
+	//   in: query
+	//   description: only return the templates of this category
+	//   required: false
+	//   type: string
+	// - name: tag
+	//   in: query
+	//   description: only return the templates with this tag
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Template"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+	query := r.URL.Query()
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to team"})
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getTemplateLibrary", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	opts := model.QueryTemplatesOptions{
+		TeamID:   teamID,
+		Category: query.Get("category"),
+		Tag:      query.Get("tag"),
+	}
+
+	templates, err := a.app.GetTemplates(opts)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	results := []*model.Template{}
+	for _, template := range templates {
+		if template.Board.Type == model.BoardTypeOpen ||
+			a.permissions.HasPermissionToBoard(userID, template.BoardID, model.PermissionViewBoard) {
+			results = append(results, template)
+		}
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("templatesCount", len(results))
+	auditRec.Success()
+}
+
+func (a *API) handleGetTemplate(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /templates/{templateID} getTemplate
+	//
+	// Returns a template of the template library
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: templateID
+	//   in: path
+	//   description: Template ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Template"
+	//   '404':
+	//     description: template not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	template := a.getTemplateWithPermission(w, r, model.PermissionViewBoard)
+	if template == nil {
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getTemplate", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("templateID", template.ID)
+
+	data, err := json.Marshal(template)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handlePublishTemplate(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/publish publishTemplate
+	//
+	// Publishes a board to the template library of its team. Publishing a board that
+	// was already published creates a new version of its template.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: ID of the board to publish
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the publish options
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/PublishTemplateOptions"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Template"
+	//   '404':
+	//     description: board not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+	if board == nil {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", nil)
+		return
+	}
+
+	if !a.permissions.HasPermissionToTeam(userID, board.TeamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to team"})
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionShareBoard) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to publish board"})
+		return
+	}
+
+	requestBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	var opts *model.PublishTemplateOptions
+	if err = json.Unmarshal(requestBody, &opts); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "", err)
+		return
+	}
+	if opts == nil {
+		opts = &model.PublishTemplateOptions{}
+	}
+
+	if err = opts.IsValid(); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "publishTemplate", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("category", opts.Category)
+
+	template, err := a.app.PublishTemplate(boardID, userID, opts)
+	if errors.Is(err, app.ErrCannotPublishTemplateBoard) {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	a.logger.Debug("PublishTemplate",
+		mlog.String("boardID", boardID),
+		mlog.String("templateID", template.ID),
+		mlog.Int("version", template.Version),
+	)
+
+	data, err := json.Marshal(template)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("templateID", template.ID)
+	auditRec.AddMeta("version", template.Version)
+	auditRec.Success()
+}
+
+func (a *API) handlePatchTemplate(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /templates/{templateID} patchTemplate
+	//
+	// Updates the category and tags of a template. Requires admin permissions on the template board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: templateID
+	//   in: path
+	//   description: Template ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: template patch to apply
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/TemplatePatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Template"
+	//   '404':
+	//     description: template not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	template := a.getTemplateWithPermission(w, r, model.PermissionManageBoardRoles)
+	if template == nil {
+		return
+	}
+	userID := getUserID(r)
+
+	requestBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	var patch *model.TemplatePatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil || patch == nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "invalid template patch", err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "patchTemplate", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("templateID", template.ID)
+
+	board := template.Board
+	updatedTemplate, err := a.app.PatchTemplate(template.ID, patch, userID)
+	if errors.Is(err, model.ErrTemplateCategoryTooLong) ||
+		errors.Is(err, model.ErrTemplateTooManyTags) ||
+		errors.Is(err, model.ErrTemplateInvalidTag) {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+	updatedTemplate.Board = board
+
+	data, err := json.Marshal(updatedTemplate)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleUnpublishTemplate(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /templates/{templateID} unpublishTemplate
+	//
+	// Removes a template from the template library and deletes its template board. Boards created
+	// from the template are kept. Requires admin permissions on the template board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: templateID
+	//   in: path
+	//   description: Template ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: template not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	template := a.getTemplateWithPermission(w, r, model.PermissionManageBoardRoles)
+	if template == nil {
+		return
+	}
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "unpublishTemplate", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("templateID", template.ID)
+	auditRec.AddMeta("boardID", template.BoardID)
+
+	if err := a.app.UnpublishTemplate(template.ID, userID); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handlePropagateTemplate(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /templates/{templateID}/propagate propagateTemplate
+	//
+	// Pushes the card properties and views of the current version of a template to the boards
+	// created from previous versions of it. Requires admin permissions on the template board.
+	// The boards whose properties the user can't modify are skipped and reported.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: templateID
+	//   in: path
+	//   description: Template ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/TemplatePropagationReport"
+	//   '404':
+	//     description: template not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	template := a.getTemplateWithPermission(w, r, model.PermissionManageBoardRoles)
+	if template == nil {
+		return
+	}
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "propagateTemplate", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("templateID", template.ID)
+	auditRec.AddMeta("version", template.Version)
+
+	canUpdate := func(boardID string) bool {
+		return a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties)
+	}
+	report, err := a.app.PropagateTemplate(template.ID, userID, canUpdate)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("boardsCount", len(report.Boards))
+	auditRec.AddMeta("skippedBoardsCount", len(report.SkippedBoardIDs))
+	auditRec.Success()
+}
+
+func (a *API) handleUpdateBoardFromTemplate(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/template/update updateBoardFromTemplate
+	//
+	// Pushes the card properties and views of the current version of the template a board was
+	// created from to the board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/TemplateBoardUpdate"
+	//   '404':
+	//     description: the board was not created from a template of the library
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to modifying board properties"})
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "updateBoardFromTemplate", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	update, err := a.app.UpdateBoardFromTemplate(boardID, userID)
+	if model.IsErrNotFound(err) {
+		a.errorResponse(w, r.URL.Path, http.StatusNotFound, "", err)
+		return
+	}
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(update)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleExportTemplate(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /templates/{templateID}/export exportTemplate
+	//
+	// Exports an archive containing a template board and its template metadata.
+	//
+	// ---
+	// produces:
+	// - application/octet-stream
+	// parameters:
+	// - name: templateID
+	//   in: path
+	//   description: Template ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     content:
+	//       application-octet-stream:
+	//         type: string
+	//         format: binary
+	//   '404':
+	//     description: template not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	template := a.getTemplateWithPermission(w, r, model.PermissionViewBoard)
+	if template == nil {
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "exportTemplate", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("templateID", template.ID)
+
+	filename := fmt.Sprintf("template-%s%s", time.Now().Format("2006-01-02"), archiveExtension)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Transfer-Encoding", "binary")
+
+	if err := a.app.ExportTemplate(w, template.ID); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	auditRec.Success()
+}
+
+func (a *API) handleImportTemplates(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/templates/import importTemplates
+	//
+	// Imports the boards of an archive as templates of the template library of a team. The
+	// template metadata stored in the archive takes precedence over the category and tags
+	// parameters.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// consumes:
+	// - multipart/form-data
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: file
+	//   in: formData
+	//   description: archive file to import
+	//   required: true
+	//   type: file
+	// - name: category
+	//   in: formData
+	//   description: category of the imported templates
+	//   required: false
+	//   type: string
+	// - name: tags
+	//   in: formData
+	//   description: comma separated tags of the imported templates
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Template"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r.URL.Path, http.StatusForbidden, "", PermissionError{"access denied to team"})
+		return
+	}
+
+	file, handle, err := r.FormFile(UploadFormFileKey)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "", err)
+		return
+	}
+	defer file.Close()
+
+	category := r.FormValue("category")
+	tags := []string{}
+	for _, tag := range strings.Split(r.FormValue("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	auditRec := a.makeAuditRecord(r, "importTemplates", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("filename", handle.Filename)
+	auditRec.AddMeta("size", handle.Size)
+
+	templates, err := a.app.ImportTemplates(file, teamID, userID, category, tags)
+	if errors.Is(err, model.ErrTemplateCategoryTooLong) ||
+		errors.Is(err, model.ErrTemplateTooManyTags) ||
+		errors.Is(err, model.ErrTemplateInvalidTag) {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if err != nil {
+		a.logger.Debug("Error importing templates",
+			mlog.String("team_id", teamID),
+			mlog.Err(err),
+		)
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	data, err := json.Marshal(templates)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("templatesCount", len(templates))
+	auditRec.Success()
+}
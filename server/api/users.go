@@ -16,6 +16,7 @@ func (a *API) registerUsersRoutes(r *mux.Router) {
 	r.HandleFunc("/users", a.sessionRequired(a.handleGetUsersList)).Methods("POST")
 	r.HandleFunc("/users/me", a.sessionRequired(a.handleGetMe)).Methods("GET")
 	r.HandleFunc("/users/me/memberships", a.sessionRequired(a.handleGetMyMemberships)).Methods("GET")
+	r.HandleFunc("/users/me/cards", a.sessionRequired(a.handleGetMyCards)).Methods("GET")
 	r.HandleFunc("/users/{userID}", a.sessionRequired(a.handleGetUser)).Methods("GET")
 	r.HandleFunc("/users/{userID}/config", a.sessionRequired(a.handleUpdateUserConfig)).Methods(http.MethodPut)
 }
@@ -179,6 +180,143 @@ func (a *API) handleGetMyMemberships(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleGetMyCards(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /users/me/cards getMyCards
+	//
+	// Returns the cards that the current user created or is assigned to in a person
+	// property, across the boards they are a member of. Archived cards and boards,
+	// and templates, are left out.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: team_id
+	//   in: query
+	//   description: only return cards of the boards of this team
+	//   required: false
+	//   type: string
+	// - name: status
+	//   in: query
+	//   description: only return cards with a select option of this name, case insensitive
+	//   required: false
+	//   type: string
+	// - name: due_after
+	//   in: query
+	//   description: only return cards due at or after this time, in milliseconds since the epoch
+	//   required: false
+	//   type: integer
+	// - name: due_before
+	//   in: query
+	//   description: only return cards due before this time, in milliseconds since the epoch
+	//   required: false
+	//   type: integer
+	// - name: sort
+	//   in: query
+	//   description: the sort of the cards, one of updateAt (default), createAt, title or dueAt
+	//   required: false
+	//   type: string
+	// - name: desc
+	//   in: query
+	//   description: if true, sort in descending order
+	//   required: false
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: the page to fetch
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: number of cards per page
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UserCardsList"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	query := r.URL.Query()
+
+	opts := model.QueryUserCardsOptions{
+		TeamID:     query.Get("team_id"),
+		Status:     query.Get("status"),
+		SortBy:     query.Get("sort"),
+		Descending: query.Get("desc") == True,
+	}
+	var err error
+	if opts.DueAfter, err = int64QueryParam(query, "due_after"); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if opts.DueBefore, err = int64QueryParam(query, "due_before"); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	page, err := int64QueryParam(query, "page")
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	perPage, err := int64QueryParam(query, "per_page")
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if page < 0 || perPage < 0 {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, "invalid pagination parameters", nil)
+		return
+	}
+	opts.Page = int(page)
+	opts.PerPage = int(perPage)
+	if err = opts.IsValid(); err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getMyCards", audit.Fail)
+	auditRec.AddMeta("userID", userID)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+
+	cards, err := a.app.GetCardsForUser(userID, opts)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	blocks := make([]model.Block, len(cards.Items))
+	for i, item := range cards.Items {
+		blocks[i] = item.Card
+	}
+	blocks, err = a.app.ApplyCloudLimits(blocks)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+	for i := range blocks {
+		cards.Items[i].Card = blocks[i]
+	}
+
+	data, err := json.Marshal(cards)
+	if err != nil {
+		a.errorResponse(w, r.URL.Path, http.StatusInternalServerError, "", err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("cardsCount", len(cards.Items))
+	auditRec.Success()
+}
+
 func (a *API) handleGetUser(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /users/{userID} getUser
 	//
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+const dataRetentionBatchSize = 1000
+
+var ErrInvalidRetentionDays = errors.New("the retention period must be at least one day")
+
+// GetAllUsers returns every user of the server, including the deactivated
+// ones.
+func (a *App) GetAllUsers() ([]*model.User, error) {
+	return a.store.GetAllUsers()
+}
+
+// CreateUser registers a new user and returns it.
+func (a *App) CreateUser(username, email, password string) (*model.User, error) {
+	if err := a.RegisterUser(username, email, password); err != nil {
+		return nil, err
+	}
+	return a.store.GetUserByUsername(username)
+}
+
+// DeactivateUser prevents a user from logging in and revokes their
+// sessions and feed token. Their boards and cards are kept.
+func (a *App) DeactivateUser(username string) error {
+	user, err := a.GetUserByUsername(username)
+	if err != nil {
+		return err
+	}
+	if err := a.store.DeactivateUser(user.ID); err != nil {
+		return err
+	}
+	if _, err := a.store.DeleteSessionsForUser(user.ID); err != nil {
+		return err
+	}
+	if err := a.store.DeleteFeedToken(user.ID); err != nil && !model.IsErrNotFound(err) {
+		return err
+	}
+
+	a.logger.Info("Deactivated user", mlog.String("user_id", user.ID))
+	return nil
+}
+
+// RevokeUserSessions logs a user out of every client and returns how many
+// sessions were revoked.
+func (a *App) RevokeUserSessions(username string) (int64, error) {
+	user, err := a.GetUserByUsername(username)
+	if err != nil {
+		return 0, err
+	}
+	return a.store.DeleteSessionsForUser(user.ID)
+}
+
+// GetBoardsInTeam returns all the boards and templates of a team,
+// regardless of their members.
+func (a *App) GetBoardsInTeam(teamID string) ([]*model.Board, error) {
+	return a.store.GetBoardsInTeam(teamID)
+}
+
+// TransferBoardOwnership makes a user the owner and an admin of a board.
+func (a *App) TransferBoardOwnership(boardID, username string) (*model.Board, error) {
+	user, err := a.GetUserByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := a.store.TransferBoardOwnership(boardID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBoardChange(board.TeamID, board)
+		return nil
+	})
+	return board, nil
+}
+
+// RunDataRetention permanently deletes the boards and cards that weren't
+// modified for the given number of days, and returns how many rows were
+// deleted.
+func (a *App) RunDataRetention(days int) (int64, error) {
+	if days < 1 {
+		return 0, ErrInvalidRetentionDays
+	}
+	retentionDate := time.Now().AddDate(0, 0, -days)
+	return a.store.RunDataRetention(utils.GetMillisForTime(retentionDate), dataRetentionBatchSize)
+}
+
+// GetSystemSettings returns the settings stored in the database.
+func (a *App) GetSystemSettings() (map[string]string, error) {
+	return a.store.GetSystemSettings()
+}
+
+// GetUserByUsername returns an active user, or a not found error.
+func (a *App) GetUserByUsername(username string) (*model.User, error) {
+	user, err := a.store.GetUserByUsername(username)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if user == nil {
+		return nil, model.NewErrNotFound("user username=" + username)
+	}
+	return user, nil
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeactivateUser(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("deactivates the user and revokes the sessions and feed token", func(t *testing.T) {
+		user := &model.User{ID: "user-id", Username: "john"}
+		th.Store.EXPECT().GetUserByUsername("john").Return(user, nil)
+		th.Store.EXPECT().DeactivateUser("user-id").Return(nil)
+		th.Store.EXPECT().DeleteSessionsForUser("user-id").Return(int64(2), nil)
+		th.Store.EXPECT().DeleteFeedToken("user-id").Return(nil)
+
+		require.NoError(t, th.App.DeactivateUser("john"))
+	})
+
+	t.Run("user without feed token", func(t *testing.T) {
+		user := &model.User{ID: "user-id", Username: "john"}
+		th.Store.EXPECT().GetUserByUsername("john").Return(user, nil)
+		th.Store.EXPECT().DeactivateUser("user-id").Return(nil)
+		th.Store.EXPECT().DeleteSessionsForUser("user-id").Return(int64(0), nil)
+		th.Store.EXPECT().DeleteFeedToken("user-id").Return(model.NewErrNotFound("feed token"))
+
+		require.NoError(t, th.App.DeactivateUser("john"))
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		th.Store.EXPECT().GetUserByUsername("jane").Return(nil, sql.ErrNoRows)
+
+		err := th.App.DeactivateUser("jane")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func TestTransferBoardOwnership(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	user := &model.User{ID: "user-id", Username: "john"}
+	board := &model.Board{ID: "board-id", TeamID: "team-id", CreatedBy: "user-id"}
+	th.Store.EXPECT().GetUserByUsername("john").Return(user, nil)
+	th.Store.EXPECT().TransferBoardOwnership("board-id", "user-id").Return(board, nil)
+	// the change is broadcast to the members of the board
+	th.Store.EXPECT().GetMembersForBoard("board-id").Return(nil, nil).AnyTimes()
+
+	transferred, err := th.App.TransferBoardOwnership("board-id", "john")
+	require.NoError(t, err)
+	require.Equal(t, "user-id", transferred.CreatedBy)
+}
+
+func TestRunDataRetention(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("deletes the data older than the retention period", func(t *testing.T) {
+		before := model.GetMillis() - 10*24*60*60*1000
+		th.Store.EXPECT().RunDataRetention(gomock.Any(), int64(dataRetentionBatchSize)).
+			DoAndReturn(func(globalRetentionDate, batchSize int64) (int64, error) {
+				require.GreaterOrEqual(t, globalRetentionDate, before)
+				require.Less(t, globalRetentionDate, model.GetMillis()-9*24*60*60*1000)
+				return 42, nil
+			})
+
+		deleted, err := th.App.RunDataRetention(10)
+		require.NoError(t, err)
+		require.Equal(t, int64(42), deleted)
+	})
+
+	t.Run("invalid retention period", func(t *testing.T) {
+		_, err := th.App.RunDataRetention(0)
+		require.ErrorIs(t, err, ErrInvalidRetentionDays)
+	})
+}
@@ -39,6 +39,9 @@ type fileBackend interface {
 	MoveFile(oldPath, newPath string) error
 	WriteFile(fr io.Reader, path string) (int64, error)
 	RemoveFile(path string) error
+	ListDirectoryRecursively(path string) ([]string, error)
+	FileModTime(path string) (time.Time, error)
+	FileSize(path string) (int64, error)
 }
 
 type Services struct {
@@ -69,6 +72,9 @@ type App struct {
 
 	cardLimitMux sync.RWMutex
 	cardLimit    int
+
+	importJobsMux     sync.Mutex
+	runningImportJobs map[string]bool
 }
 
 func (a *App) SetConfig(config *config.Configuration) {
@@ -90,8 +96,9 @@ func New(config *config.Configuration, wsAdapter ws.Adapter, services Services)
 		metrics:             services.Metrics,
 		notifications:       services.Notifications,
 		logger:              services.Logger,
-		blockChangeNotifier: utils.NewCallbackQueue("blockChangeNotifier", blockChangeNotifierQueueSize, blockChangeNotifierPoolSize, services.Logger),
+		blockChangeNotifier: utils.NewCallbackQueue("blockChangeNotifier", blockChangeNotifierQueueSize, blockChangeNotifierPoolSize, services.Logger, services.Metrics),
 		servicesAPI:         services.ServicesAPI,
+		runningImportJobs:   map[string]bool{},
 	}
 	app.initialize(services.SkipTemplateInit)
 	return app
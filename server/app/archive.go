@@ -0,0 +1,127 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+var ErrArchiveNotACard = errors.New("only cards can be archived")
+
+// ArchiveCard archives a card, which hides it and its child blocks from
+// the views of the board without deleting them.
+func (a *App) ArchiveCard(cardID, modifiedByID string) (*model.Block, error) {
+	return a.setCardArchiveAt(cardID, utils.GetMillis(), modifiedByID)
+}
+
+// UnarchiveCard restores an archived card.
+func (a *App) UnarchiveCard(cardID, modifiedByID string) (*model.Block, error) {
+	return a.setCardArchiveAt(cardID, 0, modifiedByID)
+}
+
+func (a *App) setCardArchiveAt(cardID string, archiveAt int64, modifiedByID string) (*model.Block, error) {
+	card, err := a.store.GetBlock(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card == nil {
+		return nil, model.NewErrNotFound("card ID=" + cardID)
+	}
+	if card.Type != model.TypeCard {
+		return nil, ErrArchiveNotACard
+	}
+	// archiving an archived card keeps its archived time
+	if (card.ArchiveAt != 0) == (archiveAt != 0) {
+		return card, nil
+	}
+
+	if _, err := a.PatchBlock(cardID, &model.BlockPatch{ArchiveAt: &archiveAt}, modifiedByID); err != nil {
+		return nil, err
+	}
+	return a.store.GetBlock(cardID)
+}
+
+// ArchiveBoard archives a board, which hides it from the board lists
+// without deleting it. Archived boards are still returned by the search.
+func (a *App) ArchiveBoard(boardID, modifiedByID string) (*model.Board, error) {
+	return a.setBoardArchiveAt(boardID, utils.GetMillis(), modifiedByID)
+}
+
+// UnarchiveBoard restores an archived board.
+func (a *App) UnarchiveBoard(boardID, modifiedByID string) (*model.Board, error) {
+	return a.setBoardArchiveAt(boardID, 0, modifiedByID)
+}
+
+func (a *App) setBoardArchiveAt(boardID string, archiveAt int64, modifiedByID string) (*model.Board, error) {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if (board.ArchiveAt != 0) == (archiveAt != 0) {
+		return board, nil
+	}
+	return a.PatchBoard(&model.BoardPatch{ArchiveAt: &archiveAt}, boardID, modifiedByID)
+}
+
+// RunAutoArchive archives the cards that meet the auto-archive rules of
+// their board and returns how many were archived. A board that fails is
+// logged and skipped.
+func (a *App) RunAutoArchive() (int, error) {
+	boards, err := a.store.GetBoardsWithAutoArchiveRules()
+	if err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for _, board := range boards {
+		count, err := a.autoArchiveBoardCards(board)
+		if err != nil {
+			a.logger.Error("Unable to auto-archive the cards of a board",
+				mlog.String("board_id", board.ID),
+				mlog.Err(err),
+			)
+			continue
+		}
+		archived += count
+	}
+
+	if archived > 0 {
+		a.logger.Info("Auto-archived cards", mlog.Int("card_count", archived))
+	}
+	return archived, nil
+}
+
+func (a *App) autoArchiveBoardCards(board *model.Board) (int, error) {
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return 0, err
+	}
+	rules := schema.AutoArchiveRules()
+	if len(rules) == 0 {
+		return 0, nil
+	}
+
+	cards, err := a.store.GetBlocksWithType(board.ID, model.TypeCard)
+	if err != nil {
+		return 0, err
+	}
+	now := utils.GetMillis()
+	history, err := a.store.GetCardHistory(board.ID, model.AutoArchivePropertyIDs(rules), now)
+	if err != nil {
+		return 0, err
+	}
+
+	toArchive := model.CardsToAutoArchive(rules, cards, history, now)
+	if len(toArchive) == 0 {
+		return 0, nil
+	}
+	op := &model.BulkCardOperation{Action: model.BulkCardActionArchive}
+	result := &model.BulkCardResult{}
+	if err := a.bulkPatchCards(board, op, toArchive, model.SystemUserID, result); err != nil {
+		return 0, err
+	}
+	return len(result.Blocks), nil
+}
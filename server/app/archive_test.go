@@ -0,0 +1,94 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveCardErrors(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("card not found", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("card-id").Return(nil, nil)
+
+		_, err := th.App.ArchiveCard("card-id", "user-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("block that isn't a card", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("view-id").Return(&model.Block{ID: "view-id", Type: model.TypeView}, nil)
+
+		_, err := th.App.ArchiveCard("view-id", "user-id")
+		require.ErrorIs(t, err, ErrArchiveNotACard)
+	})
+
+	t.Run("archived card keeps its archived time", func(t *testing.T) {
+		card := &model.Block{ID: "card-id", Type: model.TypeCard, ArchiveAt: 1234}
+		th.Store.EXPECT().GetBlock("card-id").Return(card, nil)
+
+		archived, err := th.App.ArchiveCard("card-id", "user-id")
+		require.NoError(t, err)
+		require.Equal(t, int64(1234), archived.ArchiveAt)
+	})
+
+	t.Run("unarchiving a card that isn't archived", func(t *testing.T) {
+		card := &model.Block{ID: "card-id", Type: model.TypeCard}
+		th.Store.EXPECT().GetBlock("card-id").Return(card, nil)
+
+		restored, err := th.App.UnarchiveCard("card-id", "user-id")
+		require.NoError(t, err)
+		require.Zero(t, restored.ArchiveAt)
+	})
+}
+
+func TestRunAutoArchive(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	cardProperties := []map[string]interface{}{
+		{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+			map[string]interface{}{"id": "done", "value": "Done", "autoArchiveDays": float64(30)},
+		}},
+	}
+
+	t.Run("no boards with rules", func(t *testing.T) {
+		th.Store.EXPECT().GetBoardsWithAutoArchiveRules().Return([]*model.Board{}, nil)
+
+		count, err := th.App.RunAutoArchive()
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
+	t.Run("boards that fail are skipped", func(t *testing.T) {
+		th.Store.EXPECT().GetBoardsWithAutoArchiveRules().Return([]*model.Board{
+			{ID: "failing-board", CardProperties: cardProperties},
+			{ID: "board-id", CardProperties: cardProperties},
+		}, nil)
+		th.Store.EXPECT().GetBlocksWithType("failing-board", model.TypeCard).Return(nil, errors.New("error"))
+		th.Store.EXPECT().GetBlocksWithType("board-id", model.TypeCard).Return([]model.Block{
+			{ID: "card-id", BoardID: "board-id", Type: model.TypeCard, Fields: map[string]interface{}{
+				"properties": map[string]interface{}{"status": "done"},
+			}},
+		}, nil)
+		th.Store.EXPECT().GetCardHistory("board-id", []string{"status"}, gomock.Any()).Return([]*model.CardHistoryEntry{
+			{CardID: "card-id", UpdateAt: utils.GetMillis(), Values: map[string]string{"status": "done"}},
+		}, nil)
+
+		count, err := th.App.RunAutoArchive()
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		th.Store.EXPECT().GetBoardsWithAutoArchiveRules().Return(nil, errors.New("error"))
+
+		_, err := th.App.RunAutoArchive()
+		require.Error(t, err)
+	})
+}
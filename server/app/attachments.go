@@ -0,0 +1,339 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	mmModel "github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// orphanFileGracePeriod is how long an uploaded file is kept without being
+// referenced by a block, as the image blocks are created after the upload of
+// their files.
+const orphanFileGracePeriod = 24 * time.Hour
+
+// uploadedFileName matches the names of the files uploaded in the boards and
+// of their thumbnails and previews.
+var uploadedFileName = regexp.MustCompile(`^7[a-z0-9]{26}(_thumb|_preview)?(\.[A-Za-z0-9]+)?$`)
+
+// fileInfoIDFromName returns the ID of the FileInfo of an uploaded file,
+// which is its name without the leading 7 nor the extension and suffixes.
+func fileInfoIDFromName(fileName string) string {
+	id := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	id = strings.TrimSuffix(strings.TrimSuffix(id, "_thumb"), "_preview")
+	if len(id) < 2 {
+		return ""
+	}
+	return id[1:]
+}
+
+// GetBoardAttachments returns the files displayed by the image blocks of a
+// board, or of a card if parentID isn't empty.
+func (a *App) GetBoardAttachments(boardID, parentID string) ([]model.Attachment, error) {
+	var blocks []model.Block
+	var err error
+	if parentID != "" {
+		blocks, err = a.store.GetBlocksWithParentAndType(boardID, parentID, model.TypeImage)
+	} else {
+		blocks, err = a.store.GetBlocksWithType(boardID, model.TypeImage)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := []model.Attachment{}
+	for _, block := range blocks {
+		fileID, _ := block.Fields["fileId"].(string)
+		if fileID == "" {
+			continue
+		}
+
+		attachment := model.Attachment{
+			FileID:    fileID,
+			BlockID:   block.ID,
+			ParentID:  block.ParentID,
+			BoardID:   block.BoardID,
+			Extension: filepath.Ext(fileID),
+			CreateAt:  block.CreateAt,
+			CreatedBy: block.CreatedBy,
+		}
+
+		// the files copied with their cards and the ones uploaded before
+		// the file infos were saved have none
+		if infoID := fileInfoIDFromName(fileID); infoID != "" {
+			fileInfo, err := a.store.GetFileInfo(infoID)
+			if err != nil {
+				return nil, err
+			}
+			if fileInfo != nil {
+				attachment.Name = fileInfo.Name
+				attachment.Extension = fileInfo.Extension
+				attachment.MimeType = fileInfo.MimeType
+				attachment.Size = fileInfo.Size
+				attachment.Width = fileInfo.Width
+				attachment.Height = fileInfo.Height
+				attachment.HasPreviewImage = fileInfo.HasPreviewImage
+			}
+		}
+
+		if attachment.ScanStatus, err = a.GetFileScanStatus(boardID, fileID); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// DeleteAttachment deletes the image blocks of a board displaying a file.
+// The blocks can be restored, so the file is kept until CleanUpOrphanFiles
+// finds it isn't referenced anymore.
+func (a *App) DeleteAttachment(boardID, fileID, userID string) error {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return err
+	}
+	if board == nil {
+		return model.NewErrNotFound("board boardID=" + boardID)
+	}
+
+	blocks, err := a.store.GetBlocksWithType(boardID, model.TypeImage)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, block := range blocks {
+		if blockFileID, _ := block.Fields["fileId"].(string); blockFileID != fileID {
+			continue
+		}
+		found = true
+		if err := a.DeleteBlock(block.ID, userID); err != nil {
+			return fmt.Errorf("cannot delete image block %s: %w", block.ID, err)
+		}
+	}
+	if !found {
+		return model.NewErrNotFound("attachment fileID=" + fileID)
+	}
+	return nil
+}
+
+// fileInfoOfCopy returns the FileInfo of the copy of an uploaded file, so
+// the copy counts in the storage quota of its team. The files uploaded
+// before the FileInfos were saved get one with the size of the file.
+func (a *App) fileInfoOfCopy(sourcePath, destPath string) (*mmModel.FileInfo, error) {
+	var source *mmModel.FileInfo
+	if infoID := fileInfoIDFromName(filepath.Base(sourcePath)); infoID != "" {
+		var err error
+		if source, err = a.store.GetFileInfo(infoID); err != nil {
+			return nil, err
+		}
+	}
+
+	fileInfo := &mmModel.FileInfo{}
+	if source != nil {
+		*fileInfo = *source
+	} else {
+		size, err := a.filesBackend.FileSize(sourcePath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get the size of file %s: %w", sourcePath, err)
+		}
+		fileInfo.CreatorId = "boards"
+		fileInfo.Name = filepath.Base(sourcePath)
+		fileInfo.Extension = filepath.Ext(sourcePath)
+		fileInfo.Size = size
+	}
+
+	now := utils.GetMillis()
+	fileInfo.Id = fileInfoIDFromName(filepath.Base(destPath))
+	fileInfo.Path = destPath
+	fileInfo.CreateAt = now
+	fileInfo.UpdateAt = now
+	fileInfo.DeleteAt = 0
+	return fileInfo, nil
+}
+
+// copyFileImages copies the thumbnail and preview of a copied file next to
+// the copy. The copy is usable without them, so they are dropped from its
+// FileInfo if they can't be copied.
+func (a *App) copyFileImages(fileInfo *mmModel.FileInfo) {
+	if !fileInfo.HasPreviewImage {
+		return
+	}
+
+	basePath := strings.TrimSuffix(fileInfo.Path, filepath.Ext(fileInfo.Path))
+	thumbnailPath := basePath + "_thumb.jpg"
+	previewPath := basePath + "_preview.jpg"
+	err := a.filesBackend.CopyFile(fileInfo.ThumbnailPath, thumbnailPath)
+	if err == nil {
+		err = a.filesBackend.CopyFile(fileInfo.PreviewPath, previewPath)
+	}
+	if err != nil {
+		a.logger.Warn("cannot copy the thumbnail of a file", mlog.String("path", fileInfo.Path), mlog.Err(err))
+		a.removeFiles([]string{thumbnailPath})
+		fileInfo.ThumbnailPath = emptyString
+		fileInfo.PreviewPath = emptyString
+		fileInfo.HasPreviewImage = false
+		return
+	}
+	fileInfo.ThumbnailPath = thumbnailPath
+	fileInfo.PreviewPath = previewPath
+}
+
+// removeFiles removes files from the files backend, returning how many were
+// removed. The files that can't be removed are only logged.
+func (a *App) removeFiles(paths []string) int {
+	removed := 0
+	for _, path := range paths {
+		if path == "" || path == emptyString {
+			continue
+		}
+		exists, err := a.filesBackend.FileExists(path)
+		if err == nil && exists {
+			err = a.filesBackend.RemoveFile(path)
+			if err == nil {
+				removed++
+			}
+		}
+		if err != nil {
+			a.logger.Warn("cannot remove file", mlog.String("path", path), mlog.Err(err))
+		}
+	}
+	return removed
+}
+
+// GetTeamStorageUsage returns the size of the files uploaded in the boards of
+// a team, and its quota.
+func (a *App) GetTeamStorageUsage(teamID string) (*model.TeamStorageUsage, error) {
+	used, err := a.store.GetTeamFilesSize(teamID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.TeamStorageUsage{
+		TeamID: teamID,
+		Used:   used,
+		Quota:  a.config.TeamStorageQuota,
+	}, nil
+}
+
+// checkTeamStorageQuota returns ErrTeamStorageQuotaExceeded if adding size
+// bytes to the files of a team exceeds its quota.
+func (a *App) checkTeamStorageQuota(teamID string, size int64) error {
+	quota := a.config.TeamStorageQuota
+	if quota <= 0 {
+		return nil
+	}
+
+	used, err := a.store.GetTeamFilesSize(teamID)
+	if err != nil {
+		return err
+	}
+	if used+size > quota {
+		return fmt.Errorf("%w: %d bytes used out of %d", model.ErrTeamStorageQuotaExceeded, used, quota)
+	}
+	return nil
+}
+
+// CleanUpOrphanFiles deletes the FileInfos and the uploaded files that
+// aren't referenced by any block, including the deleted ones that can
+// still be restored. Files uploaded during the grace period are kept, as
+// their blocks may not have been created yet.
+func (a *App) CleanUpOrphanFiles() (*model.OrphanFilesCleanup, error) {
+	fileNames, err := a.store.GetBlockFileIDs()
+	if err != nil {
+		return nil, err
+	}
+	referenced := make(map[string]bool, len(fileNames))
+	for _, fileName := range fileNames {
+		if infoID := fileInfoIDFromName(fileName); infoID != "" {
+			referenced[infoID] = true
+		}
+	}
+
+	cutoff := time.Now().Add(-orphanFileGracePeriod)
+	result := &model.OrphanFilesCleanup{}
+
+	fileInfos, err := a.store.GetFileInfosCreatedBefore(utils.GetMillisForTime(cutoff))
+	if err != nil {
+		return nil, err
+	}
+	for _, fileInfo := range fileInfos {
+		if referenced[fileInfo.Id] {
+			continue
+		}
+		result.Files += a.removeFiles([]string{fileInfo.Path, fileInfo.ThumbnailPath, fileInfo.PreviewPath})
+		if err := a.deleteFileScanOfPath(fileInfo.Path); err != nil {
+			return nil, err
+		}
+		if err := a.store.DeleteFileInfo(fileInfo.Id); err != nil {
+			return nil, err
+		}
+		result.FileInfos++
+		result.Bytes += fileInfo.Size
+	}
+
+	// the files without FileInfo, like the ones copied with their cards
+	paths, err := a.filesBackend.ListDirectoryRecursively("")
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the files: %w", err)
+	}
+	for _, path := range paths {
+		if !isUploadedFilePath(path) || referenced[fileInfoIDFromName(filepath.Base(path))] {
+			continue
+		}
+
+		modTime, err := a.filesBackend.FileModTime(path)
+		if err != nil {
+			a.logger.Warn("cannot get the modification time of a file", mlog.String("path", path), mlog.Err(err))
+			continue
+		}
+		if modTime.After(cutoff) {
+			continue
+		}
+
+		size, err := a.filesBackend.FileSize(path)
+		if err != nil {
+			a.logger.Warn("cannot get the size of a file", mlog.String("path", path), mlog.Err(err))
+			continue
+		}
+		if err := a.filesBackend.RemoveFile(path); err != nil {
+			a.logger.Warn("cannot remove file", mlog.String("path", path), mlog.Err(err))
+			continue
+		}
+		if err := a.deleteFileScanOfPath(path); err != nil {
+			return nil, err
+		}
+		result.Files++
+		result.Bytes += size
+	}
+
+	a.logger.Info("orphan files cleaned up",
+		mlog.Int("fileinfos", result.FileInfos),
+		mlog.Int("files", result.Files),
+		mlog.Int64("bytes", result.Bytes),
+	)
+	return result, nil
+}
+
+// deleteFileScanOfPath deletes the scan of an uploaded file from its path.
+func (a *App) deleteFileScanOfPath(path string) error {
+	if !isUploadedFilePath(path) {
+		return nil
+	}
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	return a.store.DeleteFileScan(parts[1], parts[2])
+}
+
+// isUploadedFilePath returns true if a path of the files backend is a file
+// uploaded in a board, stored as team/board/file. The archives of the import
+// jobs are stored in the same way, under the imports directory of the team.
+func isUploadedFilePath(path string) bool {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	return len(parts) == 3 && parts[1] != importJobsDir && uploadedFileName.MatchString(parts[2])
+}
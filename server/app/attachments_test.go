@@ -0,0 +1,317 @@
+package app
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	mmModel "github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest/mock"
+	"github.com/mattermost/mattermost-server/v6/shared/filestore/mocks"
+)
+
+func newImageBlock(id, parentID, fileID string) model.Block {
+	return model.Block{
+		ID:        id,
+		BoardID:   testBoardID,
+		ParentID:  parentID,
+		Type:      model.TypeImage,
+		CreatedBy: "user-id",
+		Fields:    map[string]interface{}{"fileId": fileID},
+	}
+}
+
+func TestGetBoardAttachments(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	fileName := utils.NewID(utils.IDTypeNone) + ".png"
+	copiedFileName := utils.NewID(utils.IDTypeNone) + ".jpg"
+	blocks := []model.Block{
+		newImageBlock("image-1", "card-1", fileName),
+		newImageBlock("image-2", "card-2", copiedFileName),
+		newImageBlock("image-3", "card-2", ""),
+	}
+	fileInfo := &mmModel.FileInfo{
+		Id:              fileName[1:27],
+		Name:            "logo.png",
+		Extension:       ".png",
+		MimeType:        "image/png",
+		Size:            2048,
+		Width:           640,
+		Height:          480,
+		HasPreviewImage: true,
+	}
+
+	t.Run("attachments of a board", func(t *testing.T) {
+		th.Store.EXPECT().GetBlocksWithType(testBoardID, model.TypeImage).Return(blocks, nil)
+		th.Store.EXPECT().GetFileInfo(fileName[1:27]).Return(fileInfo, nil)
+		th.Store.EXPECT().GetFileInfo(copiedFileName[1:27]).Return(nil, nil)
+		th.Store.EXPECT().GetFileScan(testBoardID, fileName).Return(&model.FileScan{Status: model.FileScanStatusClean}, nil)
+		th.Store.EXPECT().GetFileScan(testBoardID, copiedFileName).Return(nil, model.NewErrNotFound("file scan"))
+
+		attachments, err := th.App.GetBoardAttachments(testBoardID, "")
+		require.NoError(t, err)
+		require.Len(t, attachments, 2)
+
+		assert.Equal(t, fileName, attachments[0].FileID)
+		assert.Equal(t, "image-1", attachments[0].BlockID)
+		assert.Equal(t, "card-1", attachments[0].ParentID)
+		assert.Equal(t, "logo.png", attachments[0].Name)
+		assert.Equal(t, int64(2048), attachments[0].Size)
+		assert.Equal(t, 640, attachments[0].Width)
+		assert.True(t, attachments[0].HasPreviewImage)
+		assert.Equal(t, model.FileScanStatusClean, attachments[0].ScanStatus)
+
+		// a file without FileInfo is listed with what its block knows
+		assert.Equal(t, copiedFileName, attachments[1].FileID)
+		assert.Equal(t, ".jpg", attachments[1].Extension)
+		assert.Equal(t, "user-id", attachments[1].CreatedBy)
+		assert.Zero(t, attachments[1].Size)
+		assert.Empty(t, attachments[1].ScanStatus)
+	})
+
+	t.Run("attachments of a card", func(t *testing.T) {
+		th.Store.EXPECT().GetBlocksWithParentAndType(testBoardID, "card-1", model.TypeImage).Return(blocks[:1], nil)
+		th.Store.EXPECT().GetFileInfo(fileName[1:27]).Return(fileInfo, nil)
+		th.Store.EXPECT().GetFileScan(testBoardID, fileName).Return(nil, model.NewErrNotFound("file scan"))
+
+		attachments, err := th.App.GetBoardAttachments(testBoardID, "card-1")
+		require.NoError(t, err)
+		require.Len(t, attachments, 1)
+		assert.Equal(t, "image-1", attachments[0].BlockID)
+	})
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{ID: testBoardID, TeamID: "team-id"}
+	fileName := utils.NewID(utils.IDTypeNone) + ".png"
+	block := newImageBlock("image-1", "card-1", fileName)
+
+	t.Run("file not referenced by the board", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+		th.Store.EXPECT().GetBlocksWithType(testBoardID, model.TypeImage).Return([]model.Block{block}, nil)
+
+		err := th.App.DeleteAttachment(testBoardID, "7other.png", "user-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("deletes the blocks and keeps the file", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil).Times(2)
+		th.Store.EXPECT().GetBlocksWithType(testBoardID, model.TypeImage).Return([]model.Block{block}, nil)
+		th.Store.EXPECT().GetBlock("image-1").Return(&block, nil)
+		th.Store.EXPECT().DeleteBlock("image-1", "user-id").Return(nil)
+		th.Store.EXPECT().GetMembersForBoard(testBoardID).Return([]*model.BoardMember{}, nil).AnyTimes()
+
+		// the image block can be restored, so the file is left to the
+		// orphan files cleanup
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+
+		require.NoError(t, th.App.DeleteAttachment(testBoardID, fileName, "user-id"))
+		mockedFileBackend.AssertNotCalled(t, "RemoveFile", mock.Anything)
+	})
+}
+
+func TestTeamStorageQuota(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.TeamStorageQuota = 1000
+	defer func() { th.App.config.TeamStorageQuota = 0 }()
+
+	t.Run("storage usage", func(t *testing.T) {
+		th.Store.EXPECT().GetTeamFilesSize("team-id").Return(int64(400), nil)
+
+		usage, err := th.App.GetTeamStorageUsage("team-id")
+		require.NoError(t, err)
+		assert.Equal(t, &model.TeamStorageUsage{TeamID: "team-id", Used: 400, Quota: 1000}, usage)
+	})
+
+	t.Run("file fitting in the quota", func(t *testing.T) {
+		th.Store.EXPECT().GetTeamFilesSize("team-id").Return(int64(400), nil).Times(2)
+		th.Store.EXPECT().SaveFileInfo(gomock.Any()).Return(nil)
+
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+		mockedFileBackend.On("WriteFile", mock.Anything, mock.Anything).Return(int64(600), nil)
+
+		_, err := th.App.SaveFile(bytes.NewReader([]byte("text")), "team-id", testBoardID, "file.txt", "user-id")
+		require.NoError(t, err)
+	})
+
+	t.Run("file exceeding the quota is removed", func(t *testing.T) {
+		th.Store.EXPECT().GetTeamFilesSize("team-id").Return(int64(400), nil).Times(2)
+
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+		mockedFileBackend.On("WriteFile", mock.Anything, mock.Anything).Return(int64(601), nil)
+		mockedFileBackend.On("RemoveFile", mock.Anything).Return(nil)
+
+		_, err := th.App.SaveFile(bytes.NewReader([]byte("text")), "team-id", testBoardID, "file.txt", "user-id")
+		require.ErrorIs(t, err, model.ErrTeamStorageQuotaExceeded)
+		mockedFileBackend.AssertCalled(t, "RemoveFile", mock.Anything)
+	})
+
+	t.Run("team over its quota", func(t *testing.T) {
+		th.Store.EXPECT().GetTeamFilesSize("team-id").Return(int64(1001), nil)
+
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+
+		_, err := th.App.SaveFile(bytes.NewReader([]byte("text")), "team-id", testBoardID, "file.txt", "user-id")
+		require.ErrorIs(t, err, model.ErrTeamStorageQuotaExceeded)
+		mockedFileBackend.AssertNotCalled(t, "WriteFile", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCleanUpOrphanFiles(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	referenced := utils.NewID(utils.IDTypeNone)
+	orphan := utils.NewID(utils.IDTypeNone)
+	copied := utils.NewID(utils.IDTypeNone)
+	recent := utils.NewID(utils.IDTypeNone)
+	importJob := utils.NewID(utils.IDTypeNone)
+	old := time.Now().Add(-2 * orphanFileGracePeriod)
+
+	referencedPath := filepath.Join("team-id", "board-id", referenced+".png")
+	referencedThumbnail := filepath.Join("team-id", "board-id", referenced+"_thumb.jpg")
+	orphanPath := filepath.Join("team-id", "board-id", orphan+".png")
+	orphanThumbnail := filepath.Join("team-id", "board-id", orphan+"_thumb.jpg")
+	copiedPath := filepath.Join("team-id", "other-board-id", copied+".png")
+	recentPath := filepath.Join("team-id", "board-id", recent+".txt")
+	importJobPath := filepath.Join("team-id", importJobsDir, importJob)
+
+	th.Store.EXPECT().GetBlockFileIDs().Return([]string{referenced + ".png"}, nil)
+	th.Store.EXPECT().GetFileInfosCreatedBefore(gomock.Any()).Return([]*mmModel.FileInfo{
+		{Id: referenced[1:], Path: referencedPath, ThumbnailPath: referencedThumbnail, Size: 100},
+		{Id: orphan[1:], Path: orphanPath, ThumbnailPath: orphanThumbnail, PreviewPath: emptyString, Size: 200},
+	}, nil)
+	th.Store.EXPECT().DeleteFileInfo(orphan[1:]).Return(nil)
+	th.Store.EXPECT().DeleteFileScan("board-id", orphan+".png").Return(nil)
+	th.Store.EXPECT().DeleteFileScan("other-board-id", copied+".png").Return(nil)
+
+	mockedFileBackend := &mocks.FileBackend{}
+	th.App.filesBackend = mockedFileBackend
+	mockedFileBackend.On("FileExists", orphanPath).Return(true, nil)
+	mockedFileBackend.On("FileExists", orphanThumbnail).Return(false, nil)
+	mockedFileBackend.On("RemoveFile", orphanPath).Return(nil)
+	mockedFileBackend.On("ListDirectoryRecursively", "").Return([]string{
+		referencedPath,
+		referencedThumbnail,
+		copiedPath,
+		recentPath,
+		importJobPath,
+		"focalboard.db",
+	}, nil)
+	mockedFileBackend.On("FileModTime", copiedPath).Return(old, nil)
+	mockedFileBackend.On("FileModTime", recentPath).Return(time.Now(), nil)
+	mockedFileBackend.On("FileSize", copiedPath).Return(int64(300), nil)
+	mockedFileBackend.On("RemoveFile", copiedPath).Return(nil)
+
+	result, err := th.App.CleanUpOrphanFiles()
+	require.NoError(t, err)
+	assert.Equal(t, &model.OrphanFilesCleanup{FileInfos: 1, Files: 2, Bytes: 500}, result)
+
+	mockedFileBackend.AssertExpectations(t)
+	mockedFileBackend.AssertNotCalled(t, "RemoveFile", referencedPath)
+	mockedFileBackend.AssertNotCalled(t, "RemoveFile", recentPath)
+	mockedFileBackend.AssertNotCalled(t, "FileModTime", importJobPath)
+}
+
+func TestIsUploadedFilePath(t *testing.T) {
+	fileName := utils.NewID(utils.IDTypeNone)
+
+	assert.True(t, isUploadedFilePath("team-id/board-id/"+fileName+".png"))
+	assert.True(t, isUploadedFilePath("team-id/board-id/"+fileName+"_preview.jpg"))
+	assert.True(t, isUploadedFilePath("team-id/board-id/"+fileName))
+	assert.False(t, isUploadedFilePath("team-id/imports/"+fileName))
+	assert.False(t, isUploadedFilePath(fileName+".png"))
+	assert.False(t, isUploadedFilePath("team-id/board-id/sub/"+fileName+".png"))
+	assert.False(t, isUploadedFilePath("team-id/board-id/notes.txt"))
+}
+
+func TestCopyCardFilesQuota(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.TeamStorageQuota = 1000
+	defer func() { th.App.config.TeamStorageQuota = 0 }()
+
+	sourceBoard := &model.Board{ID: "source-board-id", TeamID: "team-id"}
+	destBoard := &model.Board{ID: testBoardID, TeamID: "team-id"}
+	fileName := utils.NewID(utils.IDTypeNone) + ".png"
+	sourcePath := filepath.Join("team-id", sourceBoard.ID, fileName)
+	sourceInfo := &mmModel.FileInfo{
+		Id:              fileName[1:27],
+		Path:            sourcePath,
+		ThumbnailPath:   filepath.Join("team-id", sourceBoard.ID, fileName[:27]+"_thumb.jpg"),
+		PreviewPath:     filepath.Join("team-id", sourceBoard.ID, fileName[:27]+"_preview.jpg"),
+		HasPreviewImage: true,
+		Name:            "image.png",
+		Size:            600,
+	}
+
+	t.Run("the copy gets a FileInfo", func(t *testing.T) {
+		block := newImageBlock("image-1", "card-1", fileName)
+		th.Store.EXPECT().GetBoard(sourceBoard.ID).Return(sourceBoard, nil)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(destBoard, nil)
+		th.Store.EXPECT().GetFileInfo(fileName[1:27]).Return(sourceInfo, nil)
+		th.Store.EXPECT().GetTeamFilesSize("team-id").Return(int64(400), nil)
+		th.Store.EXPECT().GetFileScan(sourceBoard.ID, fileName).Return(nil, model.NewErrNotFound("scan"))
+
+		var saved *mmModel.FileInfo
+		th.Store.EXPECT().SaveFileInfo(gomock.Any()).DoAndReturn(func(fileInfo *mmModel.FileInfo) error {
+			saved = fileInfo
+			return nil
+		})
+
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+		mockedFileBackend.On("CopyFile", mock.Anything, mock.Anything).Return(nil)
+
+		require.NoError(t, th.App.CopyCardFiles(sourceBoard.ID, []model.Block{block}))
+
+		copyName := block.Fields["fileId"].(string)
+		require.NotEqual(t, fileName, copyName)
+		require.NotNil(t, saved)
+		require.Equal(t, fileInfoIDFromName(copyName), saved.Id)
+		require.Equal(t, filepath.Join("team-id", testBoardID, copyName), saved.Path)
+		require.Equal(t, int64(600), saved.Size)
+		require.Equal(t, "image.png", saved.Name)
+		require.True(t, saved.HasPreviewImage)
+		require.Equal(t, filepath.Join("team-id", testBoardID, copyName[:27]+"_thumb.jpg"), saved.ThumbnailPath)
+		mockedFileBackend.AssertCalled(t, "CopyFile", sourceInfo.ThumbnailPath, saved.ThumbnailPath)
+
+		// the FileInfo of the source is left untouched
+		require.Equal(t, sourcePath, sourceInfo.Path)
+	})
+
+	t.Run("copy exceeding the quota", func(t *testing.T) {
+		block := newImageBlock("image-1", "card-1", fileName)
+		th.Store.EXPECT().GetBoard(sourceBoard.ID).Return(sourceBoard, nil)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(destBoard, nil)
+		th.Store.EXPECT().GetFileInfo(fileName[1:27]).Return(sourceInfo, nil)
+		th.Store.EXPECT().GetTeamFilesSize("team-id").Return(int64(401), nil)
+
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+
+		err := th.App.CopyCardFiles(sourceBoard.ID, []model.Block{block})
+		require.ErrorIs(t, err, model.ErrTeamStorageQuotaExceeded)
+		mockedFileBackend.AssertNotCalled(t, "CopyFile", mock.Anything, mock.Anything)
+	})
+}
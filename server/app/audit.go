@@ -0,0 +1,38 @@
+package app
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+// GetAuditRecords returns a page of the persisted audit records matching
+// the given options.
+func (a *App) GetAuditRecords(opts model.QueryAuditRecordsOptions) (*model.AuditRecordsList, error) {
+	if opts.PerPage <= 0 {
+		opts.PerPage = model.AuditRecordsDefaultPerPage
+	}
+	if opts.PerPage > model.AuditRecordsMaxPerPage {
+		opts.PerPage = model.AuditRecordsMaxPerPage
+	}
+	if opts.Page < 0 {
+		opts.Page = 0
+	}
+
+	records, err := a.store.GetAuditRecords(opts)
+	if err != nil {
+		return nil, err
+	}
+	return model.GetAuditRecordsListWithPagination(records, opts.PerPage), nil
+}
+
+// RunAuditRetention permanently deletes the persisted audit records older
+// than the given number of days, and returns how many were deleted.
+func (a *App) RunAuditRetention(days int) (int64, error) {
+	if days < 1 {
+		return 0, ErrInvalidRetentionDays
+	}
+	retentionDate := time.Now().AddDate(0, 0, -days)
+	return a.store.DeleteAuditRecordsBefore(utils.GetMillisForTime(retentionDate))
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func TestGetAuditRecords(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	records := []*model.AuditRecord{{ID: "record-1"}, {ID: "record-2"}, {ID: "record-3"}}
+
+	t.Run("has next page", func(t *testing.T) {
+		opts := model.QueryAuditRecordsOptions{BoardID: "board-id", PerPage: 2}
+		th.Store.EXPECT().GetAuditRecords(opts).Return(records, nil)
+
+		list, err := th.App.GetAuditRecords(opts)
+		require.NoError(t, err)
+		require.True(t, list.HasNext)
+		require.Len(t, list.Items, 2)
+	})
+
+	t.Run("default page size", func(t *testing.T) {
+		expectedOpts := model.QueryAuditRecordsOptions{UserID: "user-id", PerPage: model.AuditRecordsDefaultPerPage}
+		th.Store.EXPECT().GetAuditRecords(expectedOpts).Return(records, nil)
+
+		list, err := th.App.GetAuditRecords(model.QueryAuditRecordsOptions{UserID: "user-id", Page: -1})
+		require.NoError(t, err)
+		require.False(t, list.HasNext)
+		require.Len(t, list.Items, 3)
+	})
+}
+
+func TestRunAuditRetention(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("deletes the records older than the retention period", func(t *testing.T) {
+		before := utils.GetMillis() - 30*24*60*60*1000
+		th.Store.EXPECT().DeleteAuditRecordsBefore(gomock.Any()).DoAndReturn(func(date int64) (int64, error) {
+			require.InDelta(t, before, date, 60*1000)
+			return 3, nil
+		})
+
+		deleted, err := th.App.RunAuditRetention(30)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, deleted)
+	})
+
+	t.Run("invalid retention period", func(t *testing.T) {
+		_, err := th.App.RunAuditRetention(0)
+		require.ErrorIs(t, err, ErrInvalidRetentionDays)
+	})
+}
@@ -0,0 +1,313 @@
+package app
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+const (
+	backupBatchSize    = 1000
+	backupManifestFile = "manifest.json"
+	backupTablesDir    = "tables/"
+	backupFilesDir     = "files/"
+)
+
+var ErrInvalidBackup = errors.New("invalid backup")
+
+// backupColumns is the first line of the file of a table in a backup.
+type backupColumns struct {
+	Columns []string `json:"columns"`
+}
+
+// Backup writes a backup of all the data of the server to w: the rows of the
+// database tables, read in a single transaction, and the files of the files
+// backend. The format is described in docs/backup-format.md.
+func (a *App) Backup(w io.Writer) (*model.BackupManifest, error) {
+	schemaVersion, err := a.store.GetSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &model.BackupManifest{
+		Version:       model.BackupFormatVersion,
+		SchemaVersion: schemaVersion,
+		DBType:        a.store.DBType(),
+		CreateAt:      utils.GetMillis(),
+		Tables:        map[string]int64{},
+	}
+
+	zw := zip.NewWriter(w)
+
+	var tableWriter io.Writer
+	var currentTable string
+	err = a.store.ReadTables(backupBatchSize, func(batch *model.TableBatch) error {
+		if batch.Table != currentTable {
+			var err error
+			if tableWriter, err = zw.Create(backupTablesDir + batch.Table + ".jsonl"); err != nil {
+				return err
+			}
+			if err = writeJSONLine(tableWriter, backupColumns{Columns: batch.Columns}); err != nil {
+				return err
+			}
+			currentTable = batch.Table
+			manifest.Tables[batch.Table] = 0
+		}
+
+		for _, row := range batch.Rows {
+			if err := writeJSONLine(tableWriter, row); err != nil {
+				return err
+			}
+		}
+		manifest.Tables[batch.Table] += int64(len(batch.Rows))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot back up the database: %w", err)
+	}
+
+	// files are never modified once written, so the ones referenced by the
+	// snapshot of the database are all there
+	files, err := a.filesBackend.ListDirectoryRecursively("")
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the files to back up: %w", err)
+	}
+	for _, filePath := range files {
+		if err := a.writeBackupFile(zw, filePath); err != nil {
+			return nil, fmt.Errorf("cannot back up file %s: %w", filePath, err)
+		}
+		manifest.Files++
+	}
+
+	mw, err := zw.Create(backupManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.NewEncoder(mw).Encode(manifest); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (a *App) writeBackupFile(zw *zip.Writer, filePath string) error {
+	src, err := a.filesBackend.Reader(filePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dest, err := zw.Create(backupFilesDir + filepath.ToSlash(filePath))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dest, src)
+	return err
+}
+
+func writeJSONLine(w io.Writer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(b); err != nil {
+		return err
+	}
+	_, err = w.Write(newline)
+	return err
+}
+
+// Restore loads a backup into the server, which must not have any users or
+// boards. The database tables are restored in a single transaction, then the
+// files are written to the files backend. The server should be restarted
+// afterwards.
+func (a *App) Restore(r io.ReaderAt, size int64) (*model.BackupManifest, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBackup, err.Error())
+	}
+
+	manifest, err := readBackupManifest(zr)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Version != model.BackupFormatVersion {
+		return nil, model.NewErrUnsupportedBackup("version", manifest.Version, model.BackupFormatVersion)
+	}
+	schemaVersion, err := a.store.GetSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	if manifest.SchemaVersion != schemaVersion {
+		return nil, model.NewErrUnsupportedBackup("schema version", manifest.SchemaVersion, schemaVersion)
+	}
+
+	var tableFiles, files []*zip.File
+	for _, f := range zr.File {
+		switch {
+		case strings.HasPrefix(f.Name, backupTablesDir):
+			tableFiles = append(tableFiles, f)
+		case strings.HasPrefix(f.Name, backupFilesDir):
+			files = append(files, f)
+		}
+	}
+
+	tr := &backupTablesReader{files: tableFiles}
+	defer tr.close()
+	if err := a.store.RestoreTables(tr.next); err != nil {
+		return nil, fmt.Errorf("cannot restore the database: %w", err)
+	}
+
+	for _, f := range files {
+		if err := a.restoreBackupFile(f); err != nil {
+			return nil, fmt.Errorf("cannot restore file %s: %w", f.Name, err)
+		}
+	}
+
+	a.logger.Info("backup restored",
+		mlog.Int64("create_at", manifest.CreateAt),
+		mlog.String("db_type", manifest.DBType),
+		mlog.Int("files", len(files)),
+	)
+	return manifest, nil
+}
+
+func readBackupManifest(zr *zip.Reader) (*model.BackupManifest, error) {
+	f, err := zr.Open(backupManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidBackup, backupManifestFile)
+	}
+	defer f.Close()
+
+	var manifest model.BackupManifest
+	if err := json.NewDecoder(f).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("%w: cannot read %s: %s", ErrInvalidBackup, backupManifestFile, err.Error())
+	}
+	return &manifest, nil
+}
+
+func (a *App) restoreBackupFile(f *zip.File) error {
+	filePath := strings.TrimPrefix(f.Name, backupFilesDir)
+	if filePath == "" || strings.HasSuffix(filePath, "/") {
+		return nil
+	}
+	if path.Clean(filePath) != filePath || strings.HasPrefix(filePath, "../") {
+		return fmt.Errorf("%w: invalid file path %s", ErrInvalidBackup, f.Name)
+	}
+
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = a.filesBackend.WriteFile(src, filepath.FromSlash(filePath))
+	return err
+}
+
+// backupTablesReader reads the table files of a backup in batches.
+type backupTablesReader struct {
+	files   []*zip.File
+	current io.ReadCloser
+	decoder *json.Decoder
+	table   string
+	columns []string
+}
+
+// next returns the next batch of rows of the backup, or io.EOF once all
+// the tables have been read.
+func (tr *backupTablesReader) next() (*model.TableBatch, error) {
+	for {
+		if tr.current == nil {
+			if len(tr.files) == 0 {
+				return nil, io.EOF
+			}
+			if err := tr.open(tr.files[0]); err != nil {
+				return nil, err
+			}
+			tr.files = tr.files[1:]
+		}
+
+		batch := &model.TableBatch{Table: tr.table, Columns: tr.columns}
+		for len(batch.Rows) < backupBatchSize {
+			var row []interface{}
+			err := tr.decoder.Decode(&row)
+			if errors.Is(err, io.EOF) {
+				tr.close()
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: cannot read table %s: %s", ErrInvalidBackup, tr.table, err.Error())
+			}
+			if len(row) != len(tr.columns) {
+				return nil, fmt.Errorf("%w: table %s has rows with %d values for %d columns", ErrInvalidBackup, tr.table, len(row), len(tr.columns))
+			}
+			for i, value := range row {
+				row[i] = backupValue(value)
+			}
+			batch.Rows = append(batch.Rows, row)
+		}
+
+		if len(batch.Rows) != 0 {
+			return batch, nil
+		}
+	}
+}
+
+func (tr *backupTablesReader) open(f *zip.File) error {
+	table := strings.TrimSuffix(strings.TrimPrefix(f.Name, backupTablesDir), ".jsonl")
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bufio.NewReader(rc))
+	decoder.UseNumber()
+
+	var columns backupColumns
+	if err := decoder.Decode(&columns); err != nil {
+		_ = rc.Close()
+		return fmt.Errorf("%w: cannot read the columns of table %s: %s", ErrInvalidBackup, table, err.Error())
+	}
+
+	tr.current = rc
+	tr.decoder = decoder
+	tr.table = table
+	tr.columns = columns.Columns
+	return nil
+}
+
+func (tr *backupTablesReader) close() {
+	if tr.current != nil {
+		_ = tr.current.Close()
+		tr.current = nil
+	}
+}
+
+// backupValue converts the numbers read from a backup to int64 or float64.
+// Dates are left as strings, and converted by the store when restored.
+func backupValue(value interface{}) interface{} {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	if i, err := number.Int64(); err == nil {
+		return i
+	}
+	f, _ := number.Float64()
+	return f
+}
@@ -32,6 +32,51 @@ func (a *App) GetBlocks(boardID, parentID string, blockType string) ([]model.Blo
 	return a.store.GetBlocksWithParent(boardID, parentID)
 }
 
+// GetBlocksPage returns a page of the blocks of a board. When the options
+// request a delta sync, the first page also includes the tombstones of the
+// blocks deleted since then.
+func (a *App) GetBlocksPage(boardID string, opts model.QueryBlocksPageOptions) (*model.BlocksPage, error) {
+	if opts.PerPage <= 0 {
+		opts.PerPage = model.BlocksPageDefaultPerPage
+	}
+	if opts.PerPage > model.BlocksPageMaxPerPage {
+		opts.PerPage = model.BlocksPageMaxPerPage
+	}
+
+	blocks, err := a.store.GetBlocksPage(boardID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.BlocksPage{Blocks: blocks}
+	if len(blocks) > opts.PerPage {
+		page.Blocks = blocks[:opts.PerPage]
+		page.HasNext = true
+
+		last := page.Blocks[len(page.Blocks)-1]
+		page.NextCursor = model.BlocksCursor{UpdateAt: last.UpdateAt, ID: last.ID}.Encode()
+	}
+
+	if opts.UpdatedSince != 0 && opts.Cursor == nil {
+		tombstones, err := a.store.GetBlockTombstones(boardID, opts.UpdatedSince)
+		if err != nil {
+			return nil, err
+		}
+		page.Deleted = make([]model.BlockTombstone, 0, len(tombstones))
+		for _, tombstone := range tombstones {
+			if opts.ParentID != "" && tombstone.ParentID != opts.ParentID {
+				continue
+			}
+			if opts.BlockType != "" && tombstone.Type != opts.BlockType {
+				continue
+			}
+			page.Deleted = append(page.Deleted, tombstone)
+		}
+	}
+
+	return page, nil
+}
+
 func (a *App) DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]model.Block, error) {
 	board, err := a.GetBoard(boardID)
 	if err != nil {
@@ -69,36 +114,56 @@ func (a *App) GetBlocksWithBoardID(boardID string) ([]model.Block, error) {
 	return a.store.GetBlocksWithBoardID(boardID)
 }
 
-func (a *App) PatchBlock(blockID string, blockPatch *model.BlockPatch, modifiedByID string) error {
+// PatchBlock patches the block and returns the WIP limits exceeded by the
+// change on a board that allows it.
+func (a *App) PatchBlock(blockID string, blockPatch *model.BlockPatch, modifiedByID string) ([]*model.WIPLimitStatus, error) {
 	oldBlock, err := a.store.GetBlock(blockID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if a.IsCloudLimited() {
 		containsLimitedBlocks, lErr := a.ContainsLimitedBlocks([]model.Block{*oldBlock})
 		if lErr != nil {
-			return lErr
+			return nil, lErr
 		}
 		if containsLimitedBlocks {
-			return ErrPatchUpdatesLimitedCards
+			return nil, ErrPatchUpdatesLimitedCards
 		}
 	}
 
 	board, err := a.store.GetBoard(oldBlock.BoardID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = a.store.PatchBlock(blockID, blockPatch, modifiedByID)
+	var patched []model.Block
+	previous := map[string]*model.Block{oldBlock.ID: oldBlock}
+	if oldBlock.Type == model.TypeCard {
+		patched = []model.Block{patchedCopy(*oldBlock, blockPatch)}
+		if err = a.validateCardProperties(board, patched, previous, modifiedByID); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := &model.CardChanges{
+		BlockPatches: &model.BlockPatchBatch{
+			BlockIDs:     []string{blockID},
+			BlockPatches: []model.BlockPatch{*blockPatch},
+		},
+	}
+	boards := map[string]*model.Board{board.ID: board}
+	exceeded, err := a.saveCardChanges(changes, boards, map[string][]model.Block{board.ID: patched}, previous, modifiedByID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	a.refreshPatchedComputedProperties(board, previous, patched, modifiedByID)
+
 	a.metrics.IncrementBlocksPatched(1)
 	block, err := a.store.GetBlock(blockID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	a.blockChangeNotifier.Enqueue(func() error {
 		// broadcast on websocket
@@ -111,27 +176,44 @@ func (a *App) PatchBlock(blockID string, blockPatch *model.BlockPatch, modifiedB
 		a.notifyBlockChanged(notify.Update, block, oldBlock, modifiedByID)
 		return nil
 	})
-	return nil
+	return exceeded, nil
 }
 
-func (a *App) PatchBlocks(teamID string, blockPatches *model.BlockPatchBatch, modifiedByID string) error {
+// PatchBlocks patches the blocks and returns the WIP limits exceeded by
+// the changes on the boards that allow it.
+func (a *App) PatchBlocks(teamID string, blockPatches *model.BlockPatchBatch, modifiedByID string) ([]*model.WIPLimitStatus, error) {
 	oldBlocks, err := a.store.GetBlocksByIDs(blockPatches.BlockIDs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if a.IsCloudLimited() {
 		containsLimitedBlocks, err := a.ContainsLimitedBlocks(oldBlocks)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if containsLimitedBlocks {
-			return ErrPatchUpdatesLimitedCards
+			return nil, ErrPatchUpdatesLimitedCards
 		}
 	}
 
-	if err := a.store.PatchBlocks(blockPatches, modifiedByID); err != nil {
-		return err
+	boards, err := a.getBoardsForCards(oldBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.validatePatchedCards(boards, oldBlocks, blockPatches, modifiedByID); err != nil {
+		return nil, err
+	}
+
+	patched, previous := patchedCards(oldBlocks, blockPatches)
+	exceeded, err := a.saveCardChanges(&model.CardChanges{BlockPatches: blockPatches}, boards, patched, previous, modifiedByID)
+	if err != nil {
+		return nil, err
+	}
+
+	for boardID, board := range boards {
+		a.refreshPatchedComputedProperties(board, previous, patched[boardID], modifiedByID)
 	}
 
 	a.blockChangeNotifier.Enqueue(func() error {
@@ -147,17 +229,81 @@ func (a *App) PatchBlocks(teamID string, blockPatches *model.BlockPatchBatch, mo
 		}
 		return nil
 	})
+	return exceeded, nil
+}
+
+// validatePatchedCards runs validateCardProperties for the boards of the
+// patched cards.
+func (a *App) validatePatchedCards(boards map[string]*model.Board, oldBlocks []model.Block, blockPatches *model.BlockPatchBatch, modifiedByID string) error {
+	if len(boards) == 0 {
+		return nil
+	}
+
+	patched, previous := patchedCards(oldBlocks, blockPatches)
+	for boardID, blocks := range patched {
+		if err := a.validateCardProperties(boards[boardID], blocks, previous, modifiedByID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// patchedCards returns the patched copies of the cards, keyed by board ID,
+// and the cards before the patches, keyed by card ID.
+func patchedCards(oldBlocks []model.Block, blockPatches *model.BlockPatchBatch) (map[string][]model.Block, map[string]*model.Block) {
+	patches := make(map[string]*model.BlockPatch, len(blockPatches.BlockIDs))
+	for i, blockID := range blockPatches.BlockIDs {
+		patches[blockID] = &blockPatches.BlockPatches[i]
+	}
+
+	patched := map[string][]model.Block{}
+	previous := map[string]*model.Block{}
+	for i := range oldBlocks {
+		patch, ok := patches[oldBlocks[i].ID]
+		if !ok || oldBlocks[i].Type != model.TypeCard {
+			continue
+		}
+		old := oldBlocks[i]
+		patched[old.BoardID] = append(patched[old.BoardID], patchedCopy(old, patch))
+		previous[old.ID] = &old
+	}
+	return patched, previous
+}
+
+// patchedCopy returns the block with the patch applied, leaving the
+// original block untouched.
+func patchedCopy(block model.Block, patch *model.BlockPatch) model.Block {
+	fields := make(map[string]interface{}, len(block.Fields))
+	for key, value := range block.Fields {
+		fields[key] = value
+	}
+	block.Fields = fields
+	return *patch.Patch(&block)
+}
+
 func (a *App) InsertBlock(block model.Block, modifiedByID string) error {
 	board, bErr := a.store.GetBoard(block.BoardID)
 	if bErr != nil {
 		return bErr
 	}
 
-	err := a.store.InsertBlock(&block, modifiedByID)
+	if cErr := a.validateCardProperties(board, []model.Block{block}, nil, modifiedByID); cErr != nil {
+		return cErr
+	}
+
+	inserted := []model.Block{block}
+	changes := &model.CardChanges{InsertedBlocks: inserted}
+	boards := map[string]*model.Board{board.ID: board}
+	_, err := a.saveCardChanges(changes, boards, map[string][]model.Block{board.ID: inserted}, nil, modifiedByID)
 	if err == nil {
+		block = inserted[0]
+
+		if block.Type == model.TypeCard {
+			if updated, ok := a.refreshComputedProperties(board, []model.Block{block}, modifiedByID)[block.ID]; ok {
+				block = updated
+			}
+		}
+
 		a.blockChangeNotifier.Enqueue(func() error {
 			a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
 			a.metrics.IncrementBlocksInserted(1)
@@ -180,7 +326,10 @@ func (a *App) InsertBlock(block model.Block, modifiedByID string) error {
 	return err
 }
 
-func (a *App) isWithinViewsLimit(boardID string, block model.Block) (bool, error) {
+// isWithinViewsLimit returns true if a view can be added to the parent of
+// the block, pendingViews being the number of views of the parent about to
+// be inserted with it.
+func (a *App) isWithinViewsLimit(boardID string, block model.Block, pendingViews int) (bool, error) {
 	limits, err := a.GetBoardsCloudLimits()
 	if err != nil {
 		return false, err
@@ -199,53 +348,76 @@ func (a *App) isWithinViewsLimit(boardID string, block model.Block) (bool, error
 	// check passes. When that view is created, the limit will be reached.
 	// That's why we need to check for if existing + the being-created
 	// view doesn't exceed the limit.
-	return len(views) < limits.Views, nil
+	return len(views)+pendingViews < limits.Views, nil
 }
 
-func (a *App) InsertBlocks(blocks []model.Block, modifiedByID string, allowNotifications bool) ([]model.Block, error) {
+// InsertBlocks inserts the blocks of a board and returns them, with the
+// WIP limits exceeded by the new cards on a board that allows it.
+func (a *App) InsertBlocks(blocks []model.Block, modifiedByID string, allowNotifications bool) ([]model.Block, []*model.WIPLimitStatus, error) {
 	if len(blocks) == 0 {
-		return []model.Block{}, nil
+		return []model.Block{}, nil, nil
 	}
 
 	// all blocks must belong to the same board
 	boardID := blocks[0].BoardID
 	for _, block := range blocks {
 		if block.BoardID != boardID {
-			return nil, ErrBlocksFromMultipleBoards
+			return nil, nil, ErrBlocksFromMultipleBoards
 		}
 	}
 
 	board, err := a.store.GetBoard(boardID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	needsNotify := make([]model.Block, 0, len(blocks))
+	if err = a.validateCardProperties(board, blocks, nil, modifiedByID); err != nil {
+		return nil, nil, err
+	}
+
+	pendingViews := map[string]int{}
 	for i := range blocks {
 		// this check is needed to whitelist inbuilt template
 		// initialization. They do contain more than 5 views per board.
 		if boardID != "0" && blocks[i].Type == model.TypeView {
-			withinLimit, err := a.isWithinViewsLimit(board.ID, blocks[i])
+			withinLimit, err := a.isWithinViewsLimit(board.ID, blocks[i], pendingViews[blocks[i].ParentID])
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			if !withinLimit {
 				a.logger.Info("views limit reached on board", mlog.String("board_id", blocks[i].ParentID), mlog.String("team_id", board.TeamID))
-				return nil, ErrViewsLimitReached
+				return nil, nil, ErrViewsLimitReached
 			}
+			pendingViews[blocks[i].ParentID]++
 		}
+	}
 
-		err := a.store.InsertBlock(&blocks[i], modifiedByID)
-		if err != nil {
-			return nil, err
-		}
+	changes := &model.CardChanges{InsertedBlocks: blocks}
+	boards := map[string]*model.Board{board.ID: board}
+	exceeded, err := a.saveCardChanges(changes, boards, map[string][]model.Block{board.ID: blocks}, nil, modifiedByID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	needsNotify := make([]model.Block, 0, len(blocks))
+	for i := range blocks {
 		needsNotify = append(needsNotify, blocks[i])
 
 		a.wsAdapter.BroadcastBlockChange(board.TeamID, blocks[i])
 		a.metrics.IncrementBlocksInserted(1)
 	}
 
+	if hasCards(blocks) {
+		updated := a.refreshComputedProperties(board, blocks, modifiedByID)
+		for i := range blocks {
+			if updatedBlock, ok := updated[blocks[i].ID]; ok {
+				blocks[i] = updatedBlock
+				needsNotify[i] = updatedBlock
+			}
+		}
+	}
+
 	a.blockChangeNotifier.Enqueue(func() error {
 		for _, b := range needsNotify {
 			block := b
@@ -267,7 +439,7 @@ func (a *App) InsertBlocks(blocks []model.Block, modifiedByID string, allowNotif
 		}
 	}()
 
-	return blocks, nil
+	return blocks, exceeded, nil
 }
 
 func (a *App) CopyCardFiles(sourceBoardID string, copiedBlocks []model.Block) error {
@@ -316,6 +488,14 @@ func (a *App) CopyCardFiles(sourceBoardID string, copiedBlocks []model.Block) er
 			mlog.String("destinationFilePath", destinationFilePath),
 		)
 
+		fileInfo, err := a.fileInfoOfCopy(sourceFilePath, destinationFilePath)
+		if err != nil {
+			return err
+		}
+		if err := a.checkTeamStorageQuota(destTeamID, fileInfo.Size); err != nil {
+			return err
+		}
+
 		if err := a.filesBackend.CopyFile(sourceFilePath, destinationFilePath); err != nil {
 			a.logger.Error(
 				"CopyCardFiles failed to copy file",
@@ -323,6 +503,14 @@ func (a *App) CopyCardFiles(sourceBoardID string, copiedBlocks []model.Block) er
 				mlog.String("destinationFilePath", destinationFilePath),
 				mlog.Err(err),
 			)
+		} else {
+			a.copyFileImages(fileInfo)
+			if err := a.store.SaveFileInfo(fileInfo); err != nil {
+				return err
+			}
+			if err := a.copyFileScan(sourceBoard.ID, fileName.(string), destTeamID, block.BoardID, destFilename); err != nil {
+				return fmt.Errorf("cannot quarantine the copy of file %s: %w", sourceFilePath, err)
+			}
 		}
 		block.Fields["fileId"] = destFilename
 	}
@@ -355,18 +543,8 @@ func (a *App) DeleteBlock(blockID string, modifiedBy string) error {
 		return err
 	}
 
-	if block.Type == model.TypeImage {
-		fileName, fileIDExists := block.Fields["fileId"]
-		if fileName, fileIDIsString := fileName.(string); fileIDExists && fileIDIsString {
-			filePath := filepath.Join(block.BoardID, fileName)
-			err = a.filesBackend.RemoveFile(filePath)
-
-			if err != nil {
-				a.logger.Error("Error deleting image file",
-					mlog.String("FilePath", filePath),
-					mlog.Err(err))
-			}
-		}
+	if block.Type == model.TypeCard {
+		a.refreshComputedProperties(board, []model.Block{*block}, modifiedBy)
 	}
 
 	a.blockChangeNotifier.Enqueue(func() error {
@@ -431,6 +609,12 @@ func (a *App) UndeleteBlock(blockID string, modifiedBy string) (*model.Block, er
 		return nil, err
 	}
 
+	if block.Type == model.TypeCard {
+		if updated, ok := a.refreshComputedProperties(board, []model.Block{*block}, modifiedBy)[block.ID]; ok {
+			block = &updated
+		}
+	}
+
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.wsAdapter.BroadcastBlockChange(board.TeamID, *block)
 		a.metrics.IncrementBlocksInserted(1)
@@ -452,6 +636,33 @@ func (a *App) UndeleteBlock(blockID string, modifiedBy string) (*model.Block, er
 	return block, nil
 }
 
+// getBoardsForCards returns the boards the given cards belong to, keyed by
+// ID.
+func (a *App) getBoardsForCards(blocks []model.Block) (map[string]*model.Board, error) {
+	boards := map[string]*model.Board{}
+	for _, block := range blocks {
+		if block.Type != model.TypeCard || boards[block.BoardID] != nil {
+			continue
+		}
+
+		board, err := a.store.GetBoard(block.BoardID)
+		if err != nil {
+			return nil, err
+		}
+		boards[block.BoardID] = board
+	}
+	return boards, nil
+}
+
+func hasCards(blocks []model.Block) bool {
+	for _, block := range blocks {
+		if block.Type == model.TypeCard {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *App) GetBlockCountsByType() (map[string]int64, error) {
 	return a.store.GetBlockCountsByType()
 }
@@ -31,7 +31,7 @@ func TestInsertBlock(t *testing.T) {
 		block := model.Block{BoardID: boardID}
 		board := &model.Board{ID: boardID}
 		th.Store.EXPECT().GetBoard(boardID).Return(board, nil)
-		th.Store.EXPECT().InsertBlock(&block, "user-id-1").Return(nil)
+		th.Store.EXPECT().SaveCardChanges(&model.CardChanges{BoardIDs: []string{}, InsertedBlocks: []model.Block{block}}, "user-id-1", gomock.Any()).Return(nil)
 		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil)
 		err := th.App.InsertBlock(block, "user-id-1")
 		require.NoError(t, err)
@@ -42,7 +42,7 @@ func TestInsertBlock(t *testing.T) {
 		block := model.Block{BoardID: boardID}
 		board := &model.Board{ID: boardID}
 		th.Store.EXPECT().GetBoard(boardID).Return(board, nil)
-		th.Store.EXPECT().InsertBlock(&block, "user-id-1").Return(blockError{"error"})
+		th.Store.EXPECT().SaveCardChanges(&model.CardChanges{BoardIDs: []string{}, InsertedBlocks: []model.Block{block}}, "user-id-1", gomock.Any()).Return(blockError{"error"})
 		err := th.App.InsertBlock(block, "user-id-1")
 		require.Error(t, err, "error")
 	})
@@ -62,18 +62,18 @@ func TestPatchBlocks(t *testing.T) {
 
 		block1 := model.Block{ID: "block1"}
 		th.Store.EXPECT().GetBlocksByIDs([]string{"block1"}).Return([]model.Block{block1}, nil)
-		th.Store.EXPECT().PatchBlocks(gomock.Eq(&blockPatches), gomock.Eq("user-id-1")).Return(nil)
+		th.Store.EXPECT().SaveCardChanges(&model.CardChanges{BoardIDs: []string{}, BlockPatches: &blockPatches}, "user-id-1", gomock.Any()).Return(nil)
 		th.Store.EXPECT().GetBlock("block1").Return(&block1, nil)
 		// this call comes from the WS server notification
 		th.Store.EXPECT().GetMembersForBoard(gomock.Any()).Times(1)
-		err := th.App.PatchBlocks("team-id", &blockPatches, "user-id-1")
+		_, err := th.App.PatchBlocks("team-id", &blockPatches, "user-id-1")
 		require.NoError(t, err)
 	})
 
 	t.Run("patchBlocks error scenario", func(t *testing.T) {
 		blockPatches := model.BlockPatchBatch{BlockIDs: []string{}}
 		th.Store.EXPECT().GetBlocksByIDs([]string{}).Return(nil, sql.ErrNoRows)
-		err := th.App.PatchBlocks("team-id", &blockPatches, "user-id-1")
+		_, err := th.App.PatchBlocks("team-id", &blockPatches, "user-id-1")
 		require.ErrorIs(t, err, sql.ErrNoRows)
 	})
 
@@ -108,7 +108,7 @@ func TestPatchBlocks(t *testing.T) {
 		th.Store.EXPECT().GetBoard("board-id").Return(board1, nil)
 		th.Store.EXPECT().GetLicense().Return(fakeLicense)
 		th.Store.EXPECT().GetCardLimitTimestamp().Return(int64(150), nil)
-		err := th.App.PatchBlocks("team-id", &blockPatches, "user-id-1")
+		_, err := th.App.PatchBlocks("team-id", &blockPatches, "user-id-1")
 		require.ErrorIs(t, err, ErrPatchUpdatesLimitedCards)
 	})
 }
@@ -205,7 +205,7 @@ func TestIsWithinViewsLimit(t *testing.T) {
 		th.Store.EXPECT().GetCardLimitTimestamp().Return(int64(1), nil)
 		th.Store.EXPECT().GetBlocksWithParentAndType("board_id", "parent_id", "view").Return([]model.Block{{}}, nil)
 
-		withinLimits, err := th.App.isWithinViewsLimit("board_id", model.Block{ParentID: "parent_id"})
+		withinLimits, err := th.App.isWithinViewsLimit("board_id", model.Block{ParentID: "parent_id"}, 0)
 		assert.NoError(t, err)
 		assert.True(t, withinLimits)
 	})
@@ -223,7 +223,7 @@ func TestIsWithinViewsLimit(t *testing.T) {
 		th.Store.EXPECT().GetCardLimitTimestamp().Return(int64(1), nil)
 		th.Store.EXPECT().GetBlocksWithParentAndType("board_id", "parent_id", "view").Return([]model.Block{{}}, nil)
 
-		withinLimits, err := th.App.isWithinViewsLimit("board_id", model.Block{ParentID: "parent_id"})
+		withinLimits, err := th.App.isWithinViewsLimit("board_id", model.Block{ParentID: "parent_id"}, 0)
 		assert.NoError(t, err)
 		assert.False(t, withinLimits)
 	})
@@ -241,7 +241,7 @@ func TestIsWithinViewsLimit(t *testing.T) {
 		th.Store.EXPECT().GetCardLimitTimestamp().Return(int64(1), nil)
 		th.Store.EXPECT().GetBlocksWithParentAndType("board_id", "parent_id", "view").Return([]model.Block{{}, {}, {}}, nil)
 
-		withinLimits, err := th.App.isWithinViewsLimit("board_id", model.Block{ParentID: "parent_id"})
+		withinLimits, err := th.App.isWithinViewsLimit("board_id", model.Block{ParentID: "parent_id"}, 0)
 		assert.NoError(t, err)
 		assert.False(t, withinLimits)
 	})
@@ -259,7 +259,7 @@ func TestIsWithinViewsLimit(t *testing.T) {
 		th.Store.EXPECT().GetCardLimitTimestamp().Return(int64(1), nil)
 		th.Store.EXPECT().GetBlocksWithParentAndType("board_id", "parent_id", "view").Return([]model.Block{}, nil)
 
-		withinLimits, err := th.App.isWithinViewsLimit("board_id", model.Block{ParentID: "parent_id"})
+		withinLimits, err := th.App.isWithinViewsLimit("board_id", model.Block{ParentID: "parent_id"}, 0)
 		assert.NoError(t, err)
 		assert.True(t, withinLimits)
 	})
@@ -270,7 +270,7 @@ func TestIsWithinViewsLimit(t *testing.T) {
 		}
 		th.Store.EXPECT().GetLicense().Return(nonCloudLicense)
 
-		withinLimits, err := th.App.isWithinViewsLimit("board_id", model.Block{ParentID: "parent_id"})
+		withinLimits, err := th.App.isWithinViewsLimit("board_id", model.Block{ParentID: "parent_id"}, 0)
 		assert.NoError(t, err)
 		assert.True(t, withinLimits)
 	})
@@ -285,9 +285,9 @@ func TestInsertBlocks(t *testing.T) {
 		block := model.Block{BoardID: boardID}
 		board := &model.Board{ID: boardID}
 		th.Store.EXPECT().GetBoard(boardID).Return(board, nil)
-		th.Store.EXPECT().InsertBlock(&block, "user-id-1").Return(nil)
+		th.Store.EXPECT().SaveCardChanges(&model.CardChanges{BoardIDs: []string{}, InsertedBlocks: []model.Block{block}}, "user-id-1", gomock.Any()).Return(nil)
 		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil)
-		_, err := th.App.InsertBlocks([]model.Block{block}, "user-id-1", false)
+		_, _, err := th.App.InsertBlocks([]model.Block{block}, "user-id-1", false)
 		require.NoError(t, err)
 	})
 
@@ -296,8 +296,8 @@ func TestInsertBlocks(t *testing.T) {
 		block := model.Block{BoardID: boardID}
 		board := &model.Board{ID: boardID}
 		th.Store.EXPECT().GetBoard(boardID).Return(board, nil)
-		th.Store.EXPECT().InsertBlock(&block, "user-id-1").Return(blockError{"error"})
-		_, err := th.App.InsertBlocks([]model.Block{block}, "user-id-1", false)
+		th.Store.EXPECT().SaveCardChanges(&model.CardChanges{BoardIDs: []string{}, InsertedBlocks: []model.Block{block}}, "user-id-1", gomock.Any()).Return(blockError{"error"})
+		_, _, err := th.App.InsertBlocks([]model.Block{block}, "user-id-1", false)
 		require.Error(t, err, "error")
 	})
 
@@ -310,7 +310,7 @@ func TestInsertBlocks(t *testing.T) {
 		}
 		board := &model.Board{ID: boardID}
 		th.Store.EXPECT().GetBoard(boardID).Return(board, nil)
-		th.Store.EXPECT().InsertBlock(&block, "user-id-1").Return(nil)
+		th.Store.EXPECT().SaveCardChanges(&model.CardChanges{BoardIDs: []string{}, InsertedBlocks: []model.Block{block}}, "user-id-1", gomock.Any()).Return(nil)
 		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil)
 
 		// setting up mocks for limits
@@ -329,7 +329,7 @@ func TestInsertBlocks(t *testing.T) {
 		th.Store.EXPECT().GetCardLimitTimestamp().Return(int64(1), nil)
 		th.Store.EXPECT().GetBlocksWithParentAndType("test-board-id", "parent_id", "view").Return([]model.Block{{}}, nil)
 
-		_, err := th.App.InsertBlocks([]model.Block{block}, "user-id-1", false)
+		_, _, err := th.App.InsertBlocks([]model.Block{block}, "user-id-1", false)
 		require.NoError(t, err)
 	})
 
@@ -359,13 +359,11 @@ func TestInsertBlocks(t *testing.T) {
 		th.Store.EXPECT().GetCardLimitTimestamp().Return(int64(1), nil)
 		th.Store.EXPECT().GetBlocksWithParentAndType("test-board-id", "parent_id", "view").Return([]model.Block{{}, {}}, nil)
 
-		_, err := th.App.InsertBlocks([]model.Block{block}, "user-id-1", false)
+		_, _, err := th.App.InsertBlocks([]model.Block{block}, "user-id-1", false)
 		require.Error(t, err)
 	})
 
 	t.Run("creating multiple views, reaching limit in the process", func(t *testing.T) {
-		t.Skipf("Will be fixed soon")
-
 		boardID := testBoardID
 		view1 := model.Block{
 			Type:     model.TypeView,
@@ -381,8 +379,6 @@ func TestInsertBlocks(t *testing.T) {
 
 		board := &model.Board{ID: boardID}
 		th.Store.EXPECT().GetBoard(boardID).Return(board, nil)
-		th.Store.EXPECT().InsertBlock(&view1, "user-id-1").Return(nil).Times(2)
-		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil).Times(2)
 
 		// setting up mocks for limits
 		fakeLicense := &mmModel.License{
@@ -400,7 +396,7 @@ func TestInsertBlocks(t *testing.T) {
 		th.Store.EXPECT().GetCardLimitTimestamp().Return(int64(1), nil).Times(2)
 		th.Store.EXPECT().GetBlocksWithParentAndType("test-board-id", "parent_id", "view").Return([]model.Block{{}}, nil).Times(2)
 
-		_, err := th.App.InsertBlocks([]model.Block{view1, view2}, "user-id-1", false)
+		_, _, err := th.App.InsertBlocks([]model.Block{view1, view2}, "user-id-1", false)
 		require.Error(t, err)
 	})
 }
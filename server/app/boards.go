@@ -64,12 +64,18 @@ func (a *App) GetBoardMetadata(boardID string) (*model.Board, *model.BoardMetada
 		return nil, nil, err
 	}
 
+	wipLimits, err := a.GetBoardWIPLimits(board)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	boardMetadata := model.BoardMetadata{
 		BoardID:                 boardID,
 		DescendantFirstUpdateAt: earliestTime,
 		DescendantLastUpdateAt:  latestTime,
 		CreatedBy:               board.CreatedBy,
 		LastModifiedBy:          lastModifiedBy,
+		WIPLimits:               wipLimits,
 	}
 	return board, &boardMetadata, nil
 }
@@ -190,6 +196,14 @@ func (a *App) DuplicateBoard(boardID, userID, toTeam string, asTemplate bool) (*
 		if categoryErr := a.setBoardCategoryFromSource(boardID, board.ID, userID, board.TeamID); categoryErr != nil {
 			return nil, nil, categoryErr
 		}
+
+		if !asTemplate {
+			a.addBoardToDefaultCategory(board)
+
+			if linkErr := a.linkBoardToTemplate(boardID, board.ID); linkErr != nil {
+				a.logger.Error("Could not link duplicated board to its template", mlog.String("BoardID", board.ID), mlog.Err(linkErr))
+			}
+		}
 	}
 
 	// bab.Blocks now has updated file ids for any blocks containing files.  We need to store them.
@@ -280,6 +294,8 @@ func (a *App) CreateBoard(board *model.Board, userID string, addMember bool) (*m
 		return nil, err
 	}
 
+	a.addBoardToDefaultCategory(newBoard)
+
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.wsAdapter.BroadcastBoardChange(newBoard.TeamID, newBoard)
 
@@ -314,6 +330,10 @@ func (a *App) PatchBoard(patch *model.BoardPatch, boardID, userID string) (*mode
 		return nil, err
 	}
 
+	if len(patch.UpdatedCardProperties) != 0 || len(patch.DeletedCardProperties) != 0 {
+		a.refreshComputedProperties(updatedBoard, nil, userID)
+	}
+
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.wsAdapter.BroadcastBoardChange(updatedBoard.TeamID, updatedBoard)
 		if patch.ChannelID != nil && *patch.ChannelID != "" {
@@ -22,12 +22,20 @@ func (a *App) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, a
 		return nil, err
 	}
 
+	a.notifyBoardsAndBlocksCreated(newBab, members, userID)
+	return newBab, nil
+}
+
+// notifyBoardsAndBlocksCreated notifies the clients and the webhooks of the
+// boards and blocks created, and of the members added to the boards.
+func (a *App) notifyBoardsAndBlocksCreated(newBab *model.BoardsAndBlocks, members []*model.BoardMember, userID string) {
 	// all new boards should belong to the same team
 	teamID := newBab.Boards[0].TeamID
 
 	// This can be synchronous because this action is not common
 	for _, board := range newBab.Boards {
 		a.wsAdapter.BroadcastBoardChange(teamID, board)
+		a.addBoardToDefaultCategory(board)
 	}
 
 	for _, block := range newBab.Blocks {
@@ -38,10 +46,8 @@ func (a *App) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, a
 		a.notifyBlockChanged(notify.Add, &b, nil, userID)
 	}
 
-	if addMember {
-		for _, member := range members {
-			a.wsAdapter.BroadcastMemberChange(teamID, member.BoardID, member)
-		}
+	for _, member := range members {
+		a.wsAdapter.BroadcastMemberChange(teamID, member.BoardID, member)
 	}
 
 	if len(newBab.Blocks) != 0 {
@@ -54,8 +60,6 @@ func (a *App) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, a
 			}
 		}()
 	}
-
-	return newBab, nil
 }
 
 func (a *App) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
@@ -0,0 +1,327 @@
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// ApplyBulkCardOperation applies an action to the cards of a board
+// selected by ID or by the filter of a view. All the changes are saved in
+// a single transaction and sent to the clients in a single websocket
+// message per board.
+func (a *App) ApplyBulkCardOperation(boardID string, op *model.BulkCardOperation, modifiedByID string) (*model.BulkCardResult, error) {
+	if err := op.IsValid(); err != nil {
+		return nil, err
+	}
+
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	cards, err := a.selectBulkCards(board, op)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.IsCloudLimited() {
+		containsLimitedBlocks, lErr := a.ContainsLimitedBlocks(cards)
+		if lErr != nil {
+			return nil, lErr
+		}
+		if containsLimitedBlocks {
+			return nil, ErrPatchUpdatesLimitedCards
+		}
+	}
+
+	result := &model.BulkCardResult{
+		CardIDs:            make([]string, len(cards)),
+		Blocks:             []model.Block{},
+		UnmappedProperties: []*model.UnmappedCardProperty{},
+	}
+	for i := range cards {
+		result.CardIDs[i] = cards[i].ID
+	}
+	if len(cards) == 0 {
+		return result, nil
+	}
+
+	switch op.Action {
+	case model.BulkCardActionDelete:
+		err = a.bulkDeleteCards(board, cards, modifiedByID)
+	case model.BulkCardActionMoveToBoard:
+		err = a.bulkMoveCards(board, op.BoardID, cards, modifiedByID, result)
+	default:
+		err = a.bulkPatchCards(board, op, cards, modifiedByID, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Debug("Bulk card operation applied",
+		mlog.String("board_id", board.ID),
+		mlog.String("action", op.Action),
+		mlog.Int("card_count", len(cards)),
+	)
+	return result, nil
+}
+
+// selectBulkCards returns the cards of the board listed in the operation,
+// or the ones matching the filter of its view. When both are set, only
+// the listed cards matching the filter are returned.
+func (a *App) selectBulkCards(board *model.Board, op *model.BulkCardOperation) ([]model.Block, error) {
+	var filter *model.CardFilter
+	if op.ViewID != "" {
+		view, err := a.store.GetBlock(op.ViewID)
+		if err != nil {
+			return nil, err
+		}
+		if view == nil || view.BoardID != board.ID || view.Type != model.TypeView {
+			return nil, model.NewErrNotFound("view ID=" + op.ViewID)
+		}
+		if filter, err = model.ParseViewFilter(view); err != nil {
+			return nil, err
+		}
+	}
+
+	var candidates []model.Block
+	if len(op.CardIDs) > 0 {
+		blocks, err := a.store.GetBlocksByIDs(uniqueIDs(op.CardIDs))
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range blocks {
+			if block.BoardID != board.ID || block.Type != model.TypeCard {
+				return nil, model.NewErrNotFound("card ID=" + block.ID)
+			}
+		}
+		candidates = blocks
+	} else {
+		blocks, err := a.store.GetBlocksWithType(board.ID, model.TypeCard)
+		if err != nil {
+			return nil, err
+		}
+		// archived cards are hidden from the views
+		for _, block := range blocks {
+			if block.ArchiveAt == 0 {
+				candidates = append(candidates, block)
+			}
+		}
+	}
+
+	cards := make([]model.Block, 0, len(candidates))
+	for i := range candidates {
+		if filter.Matches(&candidates[i]) {
+			cards = append(cards, candidates[i])
+		}
+	}
+	if len(cards) > model.MaxBulkCards {
+		return nil, model.ErrBulkCardsTooManyCards
+	}
+	return cards, nil
+}
+
+// bulkPatchCards applies the property and archive actions and adds the
+// changed cards to the result.
+func (a *App) bulkPatchCards(board *model.Board, op *model.BulkCardOperation, cards []model.Block, modifiedByID string, result *model.BulkCardResult) error {
+	patches := &model.BlockPatchBatch{}
+
+	if op.Action == model.BulkCardActionArchive {
+		now := utils.GetMillis()
+		for _, card := range cards {
+			if card.ArchiveAt == 0 {
+				patches.BlockIDs = append(patches.BlockIDs, card.ID)
+				patches.BlockPatches = append(patches.BlockPatches, model.BlockPatch{ArchiveAt: &now})
+			}
+		}
+	} else {
+		schema, err := model.ParsePropertySchema(board)
+		if err != nil {
+			return err
+		}
+		pd, ok := schema[op.PropertyID]
+		if !ok {
+			return model.NewErrNotFound("property ID=" + op.PropertyID)
+		}
+		for i := range cards {
+			patch, pErr := op.PatchCard(&cards[i], pd)
+			if pErr != nil {
+				return pErr
+			}
+			if patch != nil {
+				patches.BlockIDs = append(patches.BlockIDs, cards[i].ID)
+				patches.BlockPatches = append(patches.BlockPatches, *patch)
+			}
+		}
+
+		boards := map[string]*model.Board{board.ID: board}
+		if err := a.validatePatchedCards(boards, cards, patches, modifiedByID); err != nil {
+			return err
+		}
+	}
+
+	if len(patches.BlockIDs) == 0 {
+		return nil
+	}
+
+	boards := map[string]*model.Board{board.ID: board}
+	patched, previous := patchedCards(cards, patches)
+	exceeded, err := a.saveCardChanges(&model.CardChanges{BlockPatches: patches}, boards, patched, previous, modifiedByID)
+	if err != nil {
+		return err
+	}
+	updated := a.refreshPatchedComputedProperties(board, previous, patched[board.ID], modifiedByID)
+
+	blocks, err := a.store.GetBlocksByIDs(patches.BlockIDs)
+	if err != nil {
+		return err
+	}
+	for i := range blocks {
+		if block, ok := updated[blocks[i].ID]; ok {
+			blocks[i] = block
+		}
+	}
+
+	oldCards := make(map[string]*model.Block, len(cards))
+	for i := range cards {
+		oldCards[cards[i].ID] = &cards[i]
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBlockChanges(board.TeamID, board.ID, blocks)
+		a.metrics.IncrementBlocksPatched(len(blocks))
+		for i := range blocks {
+			a.webhook.NotifyUpdate(blocks[i])
+			a.notifyBlockChanged(notify.Update, &blocks[i], oldCards[blocks[i].ID], modifiedByID)
+		}
+		return nil
+	})
+	result.Blocks = blocks
+	result.ExceededWIPLimits = exceeded
+	return nil
+}
+
+// bulkDeleteCards deletes the cards.
+func (a *App) bulkDeleteCards(board *model.Board, cards []model.Block, modifiedByID string) error {
+	cardIDs := make([]string, len(cards))
+	for i := range cards {
+		cardIDs[i] = cards[i].ID
+	}
+
+	if err := a.store.DeleteBlocks(cardIDs, modifiedByID); err != nil {
+		return err
+	}
+	a.refreshComputedProperties(board, cards, modifiedByID)
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBlockChanges(board.TeamID, board.ID, deletedBlocks(cards))
+		a.metrics.IncrementBlocksDeleted(len(cards))
+		for i := range cards {
+			a.notifyBlockChanged(notify.Delete, &cards[i], &cards[i], modifiedByID)
+		}
+		return nil
+	})
+
+	go func() {
+		if err := a.UpdateCardLimitTimestamp(); err != nil {
+			a.logger.Error(
+				"UpdateCardLimitTimestamp failed after deleting cards",
+				mlog.Err(err),
+			)
+		}
+	}()
+	return nil
+}
+
+// bulkMoveCards moves the cards and their child blocks to another board
+// and adds the moved blocks to the result.
+func (a *App) bulkMoveCards(sourceBoard *model.Board, destBoardID string, cards []model.Block, modifiedByID string, result *model.BulkCardResult) error {
+	if destBoardID == sourceBoard.ID {
+		return ErrMoveCardToSameBoard
+	}
+	destBoard, err := a.store.GetBoard(destBoardID)
+	if err != nil {
+		return err
+	}
+	if sourceBoard.IsTemplate != destBoard.IsTemplate {
+		return ErrTransferBetweenTemplate
+	}
+
+	var blocks []model.Block
+	for i := range cards {
+		cardBlocks, cErr := a.getCardBlocks(&cards[i], true)
+		if cErr != nil {
+			return cErr
+		}
+		blocks = append(blocks, cardBlocks...)
+	}
+	previous := deletedBlocks(blocks)
+
+	unmapped, err := mapTransferredBlocks(sourceBoard, destBoard, blocks)
+	if err != nil {
+		return err
+	}
+
+	if err = a.validateCardProperties(destBoard, blocks, nil, modifiedByID); err != nil {
+		return err
+	}
+	computed, err := a.transferComputedProperties(sourceBoard, destBoard, blocks, previous)
+	if err != nil {
+		return err
+	}
+	if err = a.CopyCardFiles(sourceBoard.ID, blocks); err != nil {
+		return err
+	}
+
+	changes := &model.CardChanges{BlockPatches: mergeBlockPatches(computed[destBoard.ID], computed[sourceBoard.ID]), MovedBlocks: blocks}
+	boards := map[string]*model.Board{destBoard.ID: destBoard}
+	changed := map[string][]model.Block{destBoard.ID: blocks}
+	exceeded, err := a.saveCardChanges(changes, boards, changed, nil, modifiedByID)
+	if err != nil {
+		return err
+	}
+	a.broadcastTransferComputedProperties(sourceBoard, destBoard, computed)
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBlockChanges(sourceBoard.TeamID, sourceBoard.ID, previous)
+		a.wsAdapter.BroadcastBlockChanges(destBoard.TeamID, destBoard.ID, blocks)
+		a.metrics.IncrementBlocksPatched(len(blocks))
+		return nil
+	})
+	result.Blocks = blocks
+	result.UnmappedProperties = unmapped
+	result.ExceededWIPLimits = exceeded
+	return nil
+}
+
+// deletedBlocks returns the deletion markers sent to the clients for the
+// blocks.
+func deletedBlocks(blocks []model.Block) []model.Block {
+	now := utils.GetMillis()
+	deleted := make([]model.Block, len(blocks))
+	for i, block := range blocks {
+		deleted[i] = model.Block{
+			ID:       block.ID,
+			ParentID: block.ParentID,
+			BoardID:  block.BoardID,
+			UpdateAt: now,
+			DeleteAt: now,
+		}
+	}
+	return deleted
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectBulkCards(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{ID: testBoardID}
+	card := func(id, status string, archiveAt int64) model.Block {
+		return model.Block{
+			ID:        id,
+			BoardID:   testBoardID,
+			Type:      model.TypeCard,
+			ArchiveAt: archiveAt,
+			Fields:    map[string]interface{}{"properties": map[string]interface{}{"status": status}},
+		}
+	}
+	view := &model.Block{
+		ID:      "view-id",
+		BoardID: testBoardID,
+		Type:    model.TypeView,
+		Fields: map[string]interface{}{"filter": map[string]interface{}{
+			"operation": "and",
+			"filters": []interface{}{
+				map[string]interface{}{"propertyId": "status", "condition": "includes", "values": []interface{}{"done"}},
+			},
+		}},
+	}
+
+	t.Run("by view filter", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("view-id").Return(view, nil)
+		th.Store.EXPECT().GetBlocksWithType(testBoardID, model.TypeCard).Return([]model.Block{
+			card("card-1", "done", 0),
+			card("card-2", "todo", 0),
+			card("card-3", "done", 1234),
+		}, nil)
+
+		cards, err := th.App.selectBulkCards(board, &model.BulkCardOperation{ViewID: "view-id"})
+		require.NoError(t, err)
+		require.Len(t, cards, 1)
+		require.Equal(t, "card-1", cards[0].ID)
+	})
+
+	t.Run("by IDs restricted to the view", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("view-id").Return(view, nil)
+		th.Store.EXPECT().GetBlocksByIDs([]string{"card-1", "card-2"}).Return([]model.Block{
+			card("card-1", "done", 0),
+			card("card-2", "todo", 0),
+		}, nil)
+
+		cards, err := th.App.selectBulkCards(board, &model.BulkCardOperation{
+			CardIDs: []string{"card-1", "card-2", "card-1"},
+			ViewID:  "view-id",
+		})
+		require.NoError(t, err)
+		require.Len(t, cards, 1)
+		require.Equal(t, "card-1", cards[0].ID)
+	})
+
+	t.Run("view of another board", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("other-view-id").Return(&model.Block{ID: "other-view-id", BoardID: "other-board", Type: model.TypeView}, nil)
+
+		_, err := th.App.selectBulkCards(board, &model.BulkCardOperation{ViewID: "other-view-id"})
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("block that isn't a card", func(t *testing.T) {
+		th.Store.EXPECT().GetBlocksByIDs([]string{"text-id"}).Return([]model.Block{
+			{ID: "text-id", BoardID: testBoardID, Type: model.TypeText},
+		}, nil)
+
+		_, err := th.App.selectBulkCards(board, &model.BulkCardOperation{CardIDs: []string{"text-id"}})
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func TestApplyBulkCardOperationErrors(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("invalid operation", func(t *testing.T) {
+		_, err := th.App.ApplyBulkCardOperation(testBoardID, &model.BulkCardOperation{Action: model.BulkCardActionDelete}, "user-id")
+		require.ErrorIs(t, err, model.ErrBulkCardsNoSelection)
+	})
+
+	t.Run("unknown property", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(testBoardID).Return(&model.Board{ID: testBoardID}, nil)
+		th.Store.EXPECT().GetBlocksByIDs([]string{"card-1"}).Return([]model.Block{
+			{ID: "card-1", BoardID: testBoardID, Type: model.TypeCard, Fields: map[string]interface{}{}},
+		}, nil)
+
+		_, err := th.App.ApplyBulkCardOperation(testBoardID, &model.BulkCardOperation{
+			CardIDs:    []string{"card-1"},
+			Action:     model.BulkCardActionSetProperty,
+			PropertyID: "missing",
+			Value:      "value",
+		}, "user-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("move to the same board", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(testBoardID).Return(&model.Board{ID: testBoardID}, nil)
+		th.Store.EXPECT().GetBlocksByIDs([]string{"card-1"}).Return([]model.Block{
+			{ID: "card-1", BoardID: testBoardID, Type: model.TypeCard},
+		}, nil)
+
+		_, err := th.App.ApplyBulkCardOperation(testBoardID, &model.BulkCardOperation{
+			CardIDs: []string{"card-1"},
+			Action:  model.BulkCardActionMoveToBoard,
+			BoardID: testBoardID,
+		}, "user-id")
+		require.ErrorIs(t, err, ErrMoveCardToSameBoard)
+	})
+}
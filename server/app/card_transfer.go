@@ -0,0 +1,371 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+var (
+	ErrTransferNotACard        = errors.New("only cards can be moved or copied to another board")
+	ErrMoveCardToSameBoard     = errors.New("the card is already on the destination board")
+	ErrTransferBetweenTemplate = errors.New("cards can't be moved or copied between templates and boards")
+)
+
+// MoveCard moves a card and its child blocks to another board, mapping its
+// property values to the properties of the destination board.
+func (a *App) MoveCard(cardID, destBoardID, modifiedByID string) (*model.CardTransferResult, error) {
+	return a.transferCard(cardID, destBoardID, modifiedByID, true)
+}
+
+// CopyCard copies a card and its child blocks, except comments, to
+// another board, mapping its property values to the properties of the
+// destination board.
+func (a *App) CopyCard(cardID, destBoardID, modifiedByID string) (*model.CardTransferResult, error) {
+	return a.transferCard(cardID, destBoardID, modifiedByID, false)
+}
+
+func (a *App) transferCard(cardID, destBoardID, modifiedByID string, move bool) (*model.CardTransferResult, error) {
+	card, err := a.store.GetBlock(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card == nil {
+		return nil, model.NewErrNotFound("card ID=" + cardID)
+	}
+	if card.Type != model.TypeCard {
+		return nil, ErrTransferNotACard
+	}
+	if move && card.BoardID == destBoardID {
+		return nil, ErrMoveCardToSameBoard
+	}
+
+	sourceBoard, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	destBoard, err := a.store.GetBoard(destBoardID)
+	if err != nil {
+		return nil, err
+	}
+	if sourceBoard.IsTemplate != destBoard.IsTemplate {
+		return nil, ErrTransferBetweenTemplate
+	}
+
+	blocks, err := a.getCardBlocks(card, move)
+	if err != nil {
+		return nil, err
+	}
+	var previous []model.Block
+	if move {
+		previous = deletedBlocks(blocks)
+	}
+
+	unmapped, err := mapTransferredBlocks(sourceBoard, destBoard, blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	var previousIDs []string
+	if move {
+		previousIDs = make([]string, len(blocks))
+		for i := range blocks {
+			previousIDs[i] = blocks[i].ID
+		}
+	} else {
+		// GenerateBlockIDs keeps the order of the blocks
+		oldIDs := make([]string, len(blocks))
+		for i := range blocks {
+			oldIDs[i] = blocks[i].ID
+		}
+		blocks = model.GenerateBlockIDs(blocks, a.logger)
+		newIDs := make(map[string]string, len(blocks))
+		for i := range blocks {
+			newIDs[oldIDs[i]] = blocks[i].ID
+		}
+		for _, u := range unmapped {
+			u.CardID = newIDs[u.CardID]
+		}
+	}
+
+	if err = a.validateCardProperties(destBoard, blocks, nil, modifiedByID); err != nil {
+		return nil, err
+	}
+	computed, err := a.transferComputedProperties(sourceBoard, destBoard, blocks, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	// the files are copied once the blocks are valid. The copies of a
+	// transfer rejected by the WIP limits are removed with the orphan files.
+	if err = a.CopyCardFiles(sourceBoard.ID, blocks); err != nil {
+		return nil, err
+	}
+
+	changes := &model.CardChanges{BlockPatches: mergeBlockPatches(computed[destBoard.ID], computed[sourceBoard.ID])}
+	if move {
+		changes.MovedBlocks = blocks
+	} else {
+		changes.InsertedBlocks = blocks
+	}
+	boards := map[string]*model.Board{destBoard.ID: destBoard}
+	changed := map[string][]model.Block{destBoard.ID: blocks}
+	exceeded, err := a.saveCardChanges(changes, boards, changed, nil, modifiedByID)
+	if err != nil {
+		return nil, err
+	}
+	a.broadcastTransferComputedProperties(sourceBoard, destBoard, computed)
+
+	a.logger.Debug("Card transferred to another board",
+		mlog.String("card_id", cardID),
+		mlog.String("source_board_id", sourceBoard.ID),
+		mlog.String("dest_board_id", destBoard.ID),
+		mlog.Bool("move", move),
+		mlog.Int("unmapped_count", len(unmapped)),
+	)
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		for _, blockID := range previousIDs {
+			a.wsAdapter.BroadcastBlockDelete(sourceBoard.TeamID, blockID, sourceBoard.ID)
+		}
+		for _, block := range blocks {
+			a.wsAdapter.BroadcastBlockChange(destBoard.TeamID, block)
+		}
+		if move {
+			a.metrics.IncrementBlocksPatched(len(blocks))
+		} else {
+			a.metrics.IncrementBlocksInserted(len(blocks))
+		}
+		return nil
+	})
+
+	if !move {
+		go func() {
+			if uErr := a.UpdateCardLimitTimestamp(); uErr != nil {
+				a.logger.Error(
+					"UpdateCardLimitTimestamp failed after copying a card",
+					mlog.Err(uErr),
+				)
+			}
+		}()
+	}
+
+	return &model.CardTransferResult{
+		Blocks:             blocks,
+		UnmappedProperties: unmapped,
+		ExceededWIPLimits:  exceeded,
+	}, nil
+}
+
+// transferComputedProperties recomputes the computed properties of the
+// boards cards are transferred between, as they are once the transfer is
+// saved, so that they are saved along with it. The values of the transferred
+// cards are set in blocks, and the patches of the other cards are returned
+// keyed by board ID. previous holds the blocks as they were on the source
+// board when they are moved, and is nil when they are copied.
+func (a *App) transferComputedProperties(sourceBoard, destBoard *model.Board, blocks, previous []model.Block) (map[string]*model.BlockPatchBatch, error) {
+	patches := map[string]*model.BlockPatchBatch{}
+
+	destSchema, err := model.ParsePropertySchema(destBoard)
+	if err != nil {
+		return nil, err
+	}
+	if destSchema.HasComputedProperties() {
+		cards, err := a.store.GetBlocksWithType(destBoard.ID, model.TypeCard)
+		if err != nil {
+			return nil, err
+		}
+
+		transferred := map[string]int{}
+		var changed []model.Block
+		for i := range blocks {
+			if blocks[i].Type == model.TypeCard {
+				transferred[blocks[i].ID] = i
+				changed = append(changed, blocks[i])
+			}
+		}
+		cards = append(cards, changed...)
+
+		destPatches := &model.BlockPatchBatch{}
+		computed := computedPropertiesPatches(destSchema, cards, changed)
+		for i, id := range computed.BlockIDs {
+			if index, ok := transferred[id]; ok {
+				setComputedProperties(&blocks[index], computed.BlockPatches[i])
+				continue
+			}
+			destPatches.BlockIDs = append(destPatches.BlockIDs, id)
+			destPatches.BlockPatches = append(destPatches.BlockPatches, computed.BlockPatches[i])
+		}
+		patches[destBoard.ID] = destPatches
+	}
+
+	if previous == nil {
+		return patches, nil
+	}
+
+	sourceSchema, err := model.ParsePropertySchema(sourceBoard)
+	if err != nil {
+		return nil, err
+	}
+	if sourceSchema.HasComputedProperties() {
+		cards, err := a.store.GetBlocksWithType(sourceBoard.ID, model.TypeCard)
+		if err != nil {
+			return nil, err
+		}
+
+		moved := make(map[string]bool, len(previous))
+		for _, block := range previous {
+			moved[block.ID] = true
+		}
+		remaining := make([]model.Block, 0, len(cards))
+		for _, card := range cards {
+			if !moved[card.ID] {
+				remaining = append(remaining, card)
+			}
+		}
+		patches[sourceBoard.ID] = computedPropertiesPatches(sourceSchema, remaining, previous)
+	}
+	return patches, nil
+}
+
+// setComputedProperties merges a patch of the computed properties into the
+// properties of a card, without changing the maps it shares with other
+// blocks.
+func setComputedProperties(card *model.Block, patch model.BlockPatch) {
+	properties, _ := card.Fields["properties"].(map[string]interface{})
+	merged := make(map[string]interface{}, len(properties))
+	for key, value := range properties {
+		merged[key] = value
+	}
+	computed, _ := patch.UpdatedFields["properties"].(map[string]interface{})
+	for key, value := range computed {
+		if value == nil {
+			delete(merged, key)
+		} else {
+			merged[key] = value
+		}
+	}
+
+	fields := make(map[string]interface{}, len(card.Fields)+1)
+	for key, value := range card.Fields {
+		fields[key] = value
+	}
+	fields["properties"] = merged
+	card.Fields = fields
+}
+
+// mergeBlockPatches returns several batches of patches as a single one, or
+// nil if there are no patches. Nil batches are skipped.
+func mergeBlockPatches(batches ...*model.BlockPatchBatch) *model.BlockPatchBatch {
+	merged := &model.BlockPatchBatch{}
+	for _, batch := range batches {
+		if batch == nil {
+			continue
+		}
+		merged.BlockIDs = append(merged.BlockIDs, batch.BlockIDs...)
+		merged.BlockPatches = append(merged.BlockPatches, batch.BlockPatches...)
+	}
+	if len(merged.BlockIDs) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// broadcastTransferComputedProperties sends the cards whose computed
+// properties were saved with a transfer to the clients.
+func (a *App) broadcastTransferComputedProperties(sourceBoard, destBoard *model.Board, patches map[string]*model.BlockPatchBatch) {
+	for _, board := range []*model.Board{sourceBoard, destBoard} {
+		batch, ok := patches[board.ID]
+		if !ok || len(batch.BlockIDs) == 0 {
+			continue
+		}
+		if _, err := a.broadcastComputedProperties(board, batch.BlockIDs); err != nil {
+			a.logger.Error("Could not broadcast the computed properties of the board",
+				mlog.String("boardID", board.ID),
+				mlog.Err(err),
+			)
+		}
+	}
+}
+
+// getCardBlocks returns the card followed by its descendants, parents
+// before their children as the clients expect.
+func (a *App) getCardBlocks(card *model.Block, withComments bool) ([]model.Block, error) {
+	descendants, err := a.getDescendants(card.BoardID, card.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]model.Block, 0, len(descendants)+1)
+	blocks = append(blocks, *card)
+	for _, block := range descendants {
+		if withComments || block.Type != model.TypeComment {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+// getDescendants returns the blocks below a block, level by level.
+func (a *App) getDescendants(boardID, blockID string) ([]model.Block, error) {
+	var descendants []model.Block
+	parentIDs := []string{blockID}
+	for len(parentIDs) > 0 {
+		var childIDs []string
+		for _, parentID := range parentIDs {
+			children, err := a.store.GetBlocksWithParent(boardID, parentID)
+			if err != nil {
+				return nil, err
+			}
+			for _, child := range children {
+				descendants = append(descendants, child)
+				childIDs = append(childIDs, child.ID)
+			}
+		}
+		parentIDs = childIDs
+	}
+	return descendants, nil
+}
+
+// mapTransferredBlocks points the blocks to the destination board and maps
+// the property values of its cards to the destination schema.
+func mapTransferredBlocks(sourceBoard, destBoard *model.Board, blocks []model.Block) ([]*model.UnmappedCardProperty, error) {
+	sourceSchema, err := model.ParsePropertySchema(sourceBoard)
+	if err != nil {
+		return nil, err
+	}
+	destSchema, err := model.ParsePropertySchema(destBoard)
+	if err != nil {
+		return nil, err
+	}
+
+	unmapped := []*model.UnmappedCardProperty{}
+	for i := range blocks {
+		block := &blocks[i]
+		if block.ParentID == sourceBoard.ID {
+			block.ParentID = destBoard.ID
+		}
+		block.BoardID = destBoard.ID
+
+		properties, ok := block.Fields["properties"].(map[string]interface{})
+		if block.Type != model.TypeCard || !ok || sourceBoard.ID == destBoard.ID {
+			continue
+		}
+
+		fields := make(map[string]interface{}, len(block.Fields))
+		for key, value := range block.Fields {
+			fields[key] = value
+		}
+		mapped, cardUnmapped := model.MapCardProperties(sourceSchema, destSchema, properties)
+		fields["properties"] = mapped
+		block.Fields = fields
+
+		for _, u := range cardUnmapped {
+			u.CardID = block.ID
+		}
+		unmapped = append(unmapped, cardUnmapped...)
+	}
+	return unmapped, nil
+}
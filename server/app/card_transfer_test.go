@@ -0,0 +1,92 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferCardErrors(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("card not found", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("card-id").Return(nil, nil)
+		_, err := th.App.MoveCard("card-id", "dest-board-id", "user-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("not a card", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("text-id").Return(&model.Block{ID: "text-id", BoardID: testBoardID, Type: model.TypeText}, nil)
+		_, err := th.App.CopyCard("text-id", "dest-board-id", "user-id")
+		require.ErrorIs(t, err, ErrTransferNotACard)
+	})
+
+	t.Run("move to the same board", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("card-id").Return(&model.Block{ID: "card-id", BoardID: testBoardID, Type: model.TypeCard}, nil)
+		_, err := th.App.MoveCard("card-id", testBoardID, "user-id")
+		require.ErrorIs(t, err, ErrMoveCardToSameBoard)
+	})
+
+	t.Run("template to board", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("card-id").Return(&model.Block{ID: "card-id", BoardID: testBoardID, Type: model.TypeCard}, nil)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(&model.Board{ID: testBoardID, IsTemplate: true}, nil)
+		th.Store.EXPECT().GetBoard("dest-board-id").Return(&model.Board{ID: "dest-board-id"}, nil)
+		_, err := th.App.CopyCard("card-id", "dest-board-id", "user-id")
+		require.ErrorIs(t, err, ErrTransferBetweenTemplate)
+	})
+
+	t.Run("invalid properties before copying the files", func(t *testing.T) {
+		card := &model.Block{ID: "card-id", BoardID: testBoardID, ParentID: testBoardID, Type: model.TypeCard}
+		image := model.Block{ID: "image-id", BoardID: testBoardID, ParentID: "card-id", Type: model.TypeImage, Fields: map[string]interface{}{"fileId": "7file.png"}}
+		destBoard := &model.Board{
+			ID:         "dest-board-id",
+			Properties: map[string]interface{}{model.BoardPropertyPropertyValidation: model.PropertyValidationStrict},
+			CardProperties: []map[string]interface{}{
+				{"id": "status", "name": "Status", "type": "text", "required": true},
+			},
+		}
+		th.Store.EXPECT().GetBlock("card-id").Return(card, nil)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(&model.Board{ID: testBoardID}, nil)
+		th.Store.EXPECT().GetBoard("dest-board-id").Return(destBoard, nil)
+		th.Store.EXPECT().GetBlocksWithParent(testBoardID, "card-id").Return([]model.Block{image}, nil)
+		th.Store.EXPECT().GetBlocksWithParent(testBoardID, "image-id").Return(nil, nil)
+
+		// no file is copied, as the card is rejected first
+		_, err := th.App.MoveCard("card-id", "dest-board-id", "user-id")
+		require.True(t, model.IsErrInvalidCardProperties(err))
+	})
+}
+
+func TestMapTransferredBlocks(t *testing.T) {
+	source := &model.Board{
+		ID: "source-board-id",
+		CardProperties: []map[string]interface{}{
+			{"id": "s-estimate", "name": "Estimate", "type": "number"},
+		},
+	}
+	dest := &model.Board{
+		ID: "dest-board-id",
+		CardProperties: []map[string]interface{}{
+			{"id": "d-estimate", "name": "Estimate", "type": "number"},
+		},
+	}
+	originalFields := map[string]interface{}{"properties": map[string]interface{}{"s-estimate": "3"}}
+	blocks := []model.Block{
+		{ID: "card-id", BoardID: source.ID, ParentID: source.ID, Type: model.TypeCard, Fields: originalFields},
+		{ID: "text-id", BoardID: source.ID, ParentID: "card-id", Type: model.TypeText},
+	}
+
+	unmapped, err := mapTransferredBlocks(source, dest, blocks)
+	require.NoError(t, err)
+	require.Empty(t, unmapped)
+	require.Equal(t, dest.ID, blocks[0].BoardID)
+	require.Equal(t, dest.ID, blocks[0].ParentID)
+	require.Equal(t, map[string]interface{}{"d-estimate": "3"}, blocks[0].Fields["properties"])
+	require.Equal(t, dest.ID, blocks[1].BoardID)
+	require.Equal(t, "card-id", blocks[1].ParentID)
+
+	// the fields of the source card are left untouched
+	require.Equal(t, map[string]interface{}{"s-estimate": "3"}, originalFields["properties"])
+}
@@ -11,6 +11,7 @@ var (
 	ErrorCategoryPermissionDenied = errors.New("category doesn't belong to user")
 	ErrorCategoryDeleted          = errors.New("category is deleted")
 	ErrorInvalidCategory          = errors.New("invalid category")
+	ErrorInvalidCategoryParent    = errors.New("invalid category parent")
 )
 
 func (a *App) CreateCategory(category *model.Category) (*model.Category, error) {
@@ -19,10 +20,20 @@ func (a *App) CreateCategory(category *model.Category) (*model.Category, error)
 		return nil, err
 	}
 
+	if err := a.validateCategoryParent(category); err != nil {
+		return nil, err
+	}
+
 	if err := a.store.CreateCategory(*category); err != nil {
 		return nil, err
 	}
 
+	if category.IsShared() && category.IsDefault {
+		if err := a.store.SetDefaultSharedCategory(category.TeamID, category.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	createdCategory, err := a.store.GetCategory(category.ID)
 	if err != nil {
 		return nil, err
@@ -50,14 +61,31 @@ func (a *App) UpdateCategory(category *model.Category) (*model.Category, error)
 		return nil, ErrorCategoryPermissionDenied
 	}
 
+	// the type of a category cannot change
+	if category.Type == "" {
+		category.Type = existingCategory.Type
+	}
+	if category.Type != existingCategory.Type || category.TeamID != existingCategory.TeamID {
+		return nil, ErrorInvalidCategory
+	}
+
 	category.UpdateAt = utils.GetMillis()
 	if err = category.IsValid(); err != nil {
 		return nil, err
 	}
+	if err = a.validateCategoryParent(category); err != nil {
+		return nil, err
+	}
 	if err = a.store.UpdateCategory(*category); err != nil {
 		return nil, err
 	}
 
+	if category.IsShared() && category.IsDefault && !existingCategory.IsDefault {
+		if err = a.store.SetDefaultSharedCategory(category.TeamID, category.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	updatedCategory, err := a.store.GetCategory(category.ID)
 	if err != nil {
 		return nil, err
@@ -96,6 +124,11 @@ func (a *App) DeleteCategory(categoryID, userID, teamID string) (*model.Category
 		return nil, err
 	}
 
+	// nested categories are moved up to the parent of the deleted one
+	if err = a.reparentChildCategories(existingCategory); err != nil {
+		return nil, err
+	}
+
 	deletedCategory, err := a.store.GetCategory(categoryID)
 	if err != nil {
 		return nil, err
@@ -107,3 +140,85 @@ func (a *App) DeleteCategory(categoryID, userID, teamID string) (*model.Category
 
 	return deletedCategory, nil
 }
+
+// getSiblingCategories returns the live categories that share the
+// scope of the given one, either the team's shared categories or the
+// private categories of its owner.
+func (a *App) getSiblingCategories(category *model.Category) ([]model.Category, error) {
+	if category.IsShared() {
+		return a.store.GetSharedCategories(category.TeamID)
+	}
+
+	categoryBoards, err := a.store.GetUserCategoryBoards(category.UserID, category.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make([]model.Category, 0, len(categoryBoards))
+	for _, categoryBoard := range categoryBoards {
+		categories = append(categories, categoryBoard.Category)
+	}
+	return categories, nil
+}
+
+// validateCategoryParent checks that the parent of a nested category
+// exists, has the same scope and that the nesting doesn't create a cycle.
+func (a *App) validateCategoryParent(category *model.Category) error {
+	if category.ParentID == "" {
+		return nil
+	}
+
+	siblings, err := a.getSiblingCategories(category)
+	if err != nil {
+		return err
+	}
+
+	categoriesByID := make(map[string]model.Category, len(siblings))
+	for _, sibling := range siblings {
+		categoriesByID[sibling.ID] = sibling
+	}
+
+	parentID := category.ParentID
+	for parentID != "" {
+		if parentID == category.ID {
+			return ErrorInvalidCategoryParent
+		}
+
+		parent, ok := categoriesByID[parentID]
+		if !ok {
+			return ErrorInvalidCategoryParent
+		}
+
+		// prevents looping forever on a cycle already stored
+		delete(categoriesByID, parentID)
+		parentID = parent.ParentID
+	}
+
+	return nil
+}
+
+func (a *App) reparentChildCategories(category *model.Category) error {
+	siblings, err := a.getSiblingCategories(category)
+	if err != nil {
+		return err
+	}
+
+	for i := range siblings {
+		child := siblings[i]
+		if child.ParentID != category.ID {
+			continue
+		}
+
+		child.ParentID = category.ParentID
+		child.UpdateAt = utils.GetMillis()
+		if err := a.store.UpdateCategory(child); err != nil {
+			return err
+		}
+
+		go func() {
+			a.wsAdapter.BroadcastCategoryChange(child)
+		}()
+	}
+
+	return nil
+}
@@ -1,6 +1,10 @@
 package app
 
-import "github.com/mattermost/focalboard/server/model"
+import (
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
 
 func (a *App) GetUserCategoryBoards(userID, teamID string) ([]model.CategoryBoards, error) {
 	return a.store.GetUserCategoryBoards(userID, teamID)
@@ -25,3 +29,84 @@ func (a *App) AddUpdateUserCategoryBoard(teamID, userID, categoryID, boardID str
 
 	return nil
 }
+
+// GetSharedCategoryBoards returns the shared categories of a team. The
+// boards of each category are limited to the ones the user can see.
+func (a *App) GetSharedCategoryBoards(userID, teamID string) ([]model.CategoryBoards, error) {
+	sharedCategoryBoards, err := a.store.GetSharedCategoryBoards(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sharedCategoryBoards) == 0 {
+		return sharedCategoryBoards, nil
+	}
+
+	boards, err := a.store.GetBoardsForUserAndTeam(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	visibleBoards := make(map[string]bool, len(boards))
+	for _, board := range boards {
+		visibleBoards[board.ID] = true
+	}
+
+	for i := range sharedCategoryBoards {
+		boardIDs := []string{}
+		for _, boardID := range sharedCategoryBoards[i].BoardIDs {
+			if visibleBoards[boardID] {
+				boardIDs = append(boardIDs, boardID)
+			}
+		}
+		sharedCategoryBoards[i].BoardIDs = boardIDs
+	}
+
+	return sharedCategoryBoards, nil
+}
+
+// AddUpdateSharedCategoryBoard places a board in a shared category of
+// the team. The category ID "0" removes the board from its shared
+// category.
+func (a *App) AddUpdateSharedCategoryBoard(teamID, categoryID, boardID string) error {
+	if categoryID != "0" {
+		category, err := a.store.GetCategory(categoryID)
+		if err != nil {
+			return err
+		}
+		if !category.IsShared() || category.TeamID != teamID {
+			return ErrorInvalidCategory
+		}
+		if category.DeleteAt != 0 {
+			return ErrorCategoryDeleted
+		}
+	}
+
+	// shared category boards are not owned by any user
+	return a.AddUpdateUserCategoryBoard(teamID, "", categoryID, boardID)
+}
+
+// addBoardToDefaultCategory places a newly created board in the default
+// shared category of its team, if there is one.
+func (a *App) addBoardToDefaultCategory(board *model.Board) {
+	if board.IsTemplate {
+		return
+	}
+
+	category, err := a.store.GetDefaultSharedCategory(board.TeamID)
+	if model.IsErrNotFound(err) {
+		return
+	}
+	if err != nil {
+		a.logger.Error("Could not get the default category of the team", mlog.String("teamID", board.TeamID), mlog.Err(err))
+		return
+	}
+
+	if err := a.AddUpdateUserCategoryBoard(board.TeamID, "", category.ID, board.ID); err != nil {
+		a.logger.Error("Could not add board to the default category",
+			mlog.String("boardID", board.ID),
+			mlog.String("categoryID", category.ID),
+			mlog.Err(err),
+		)
+	}
+}
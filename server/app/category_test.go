@@ -0,0 +1,59 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func TestValidateCategoryParent(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	sharedCategories := []model.Category{
+		{ID: "root", Name: "Root", TeamID: "team-id", Type: model.CategoryTypeShared},
+		{ID: "child", Name: "Child", TeamID: "team-id", Type: model.CategoryTypeShared, ParentID: "root"},
+		{ID: "grandchild", Name: "Grandchild", TeamID: "team-id", Type: model.CategoryTypeShared, ParentID: "child"},
+	}
+
+	t.Run("category without parent", func(t *testing.T) {
+		category := &model.Category{ID: "new", TeamID: "team-id", Type: model.CategoryTypeShared}
+		require.NoError(t, th.App.validateCategoryParent(category))
+	})
+
+	t.Run("nested shared category", func(t *testing.T) {
+		th.Store.EXPECT().GetSharedCategories("team-id").Return(sharedCategories, nil)
+
+		category := &model.Category{ID: "new", TeamID: "team-id", Type: model.CategoryTypeShared, ParentID: "grandchild"}
+		require.NoError(t, th.App.validateCategoryParent(category))
+	})
+
+	t.Run("parent must have the same scope", func(t *testing.T) {
+		th.Store.EXPECT().GetUserCategoryBoards("user-id", "team-id").Return([]model.CategoryBoards{}, nil)
+
+		category := &model.Category{ID: "new", UserID: "user-id", TeamID: "team-id", Type: model.CategoryTypePrivate, ParentID: "root"}
+		require.ErrorIs(t, th.App.validateCategoryParent(category), ErrorInvalidCategoryParent)
+	})
+
+	t.Run("nesting cannot create a cycle", func(t *testing.T) {
+		th.Store.EXPECT().GetSharedCategories("team-id").Return(sharedCategories, nil)
+
+		category := sharedCategories[0]
+		category.ParentID = "grandchild"
+		require.ErrorIs(t, th.App.validateCategoryParent(&category), ErrorInvalidCategoryParent)
+	})
+}
+
+func TestUpdateCategoryType(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	existing := &model.Category{ID: "category-id", Name: "Category", UserID: "user-id", TeamID: "team-id", Type: model.CategoryTypePrivate}
+	th.Store.EXPECT().GetCategory("category-id").Return(existing, nil)
+
+	category := &model.Category{ID: "category-id", Name: "Category", UserID: "user-id", TeamID: "team-id", Type: model.CategoryTypeShared}
+	_, err := th.App.UpdateCategory(category)
+	require.ErrorIs(t, err, ErrorInvalidCategory)
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// updateComputedProperties recomputes the formula and rollup properties
+// of the cards of a board and saves the ones that changed. Only the changed
+// cards and the cards depending on them are recomputed, or all the cards if
+// changed is nil. The updated cards are returned keyed by ID.
+func (a *App) updateComputedProperties(board *model.Board, changed []model.Block, modifiedByID string) (map[string]model.Block, error) {
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+	if !schema.HasComputedProperties() {
+		return nil, nil
+	}
+
+	cards, err := a.store.GetBlocksWithType(board.ID, model.TypeCard)
+	if err != nil {
+		return nil, err
+	}
+
+	patches := computedPropertiesPatches(schema, cards, changed)
+	if len(patches.BlockIDs) == 0 {
+		return nil, nil
+	}
+
+	if err := a.store.PatchBlocks(patches, modifiedByID); err != nil {
+		return nil, err
+	}
+	return a.broadcastComputedProperties(board, patches.BlockIDs)
+}
+
+// computedPropertiesPatches returns the patches of the computed properties
+// that changed, for the cards of a board depending on the changed cards, or
+// for all of them if changed is nil.
+func computedPropertiesPatches(schema model.PropSchema, cards []model.Block, changed []model.Block) *model.BlockPatchBatch {
+	evaluator := model.NewPropertyEvaluator(schema, cards, time.Now())
+
+	patches := &model.BlockPatchBatch{}
+	var recomputed map[string]bool
+	if changed != nil {
+		cardIDs := evaluator.DependentCardIDs(changed)
+		if len(cardIDs) == 0 {
+			return patches
+		}
+		recomputed = make(map[string]bool, len(cardIDs))
+		for _, id := range cardIDs {
+			recomputed[id] = true
+		}
+	}
+
+	for _, card := range cards {
+		if recomputed != nil && !recomputed[card.ID] {
+			continue
+		}
+		properties, _ := card.Fields["properties"].(map[string]interface{})
+
+		changed := map[string]interface{}{}
+		for propertyID, value := range evaluator.ComputeCard(card.ID) {
+			existing, exists := properties[propertyID]
+			switch {
+			case value == "" && exists:
+				// a nil value removes the property when the patch is merged
+				changed[propertyID] = nil
+			case value != "" && existing != value:
+				changed[propertyID] = value
+			}
+		}
+
+		if len(changed) == 0 {
+			continue
+		}
+		patches.BlockIDs = append(patches.BlockIDs, card.ID)
+		patches.BlockPatches = append(patches.BlockPatches, model.BlockPatch{
+			UpdatedFields: map[string]interface{}{"properties": changed},
+			MergeFields:   true,
+		})
+	}
+	return patches
+}
+
+// broadcastComputedProperties sends the cards of a board whose computed
+// properties were saved to the clients, and returns them keyed by ID.
+func (a *App) broadcastComputedProperties(board *model.Board, cardIDs []string) (map[string]model.Block, error) {
+	updatedCards, err := a.store.GetBlocksByIDs(cardIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make(map[string]model.Block, len(updatedCards))
+	for _, card := range updatedCards {
+		updated[card.ID] = card
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.metrics.IncrementBlocksPatched(len(updatedCards))
+		for _, card := range updatedCards {
+			a.wsAdapter.BroadcastBlockChange(board.TeamID, card)
+			a.webhook.NotifyUpdate(card)
+		}
+		return nil
+	})
+
+	return updated, nil
+}
+
+// refreshComputedProperties recomputes the computed properties of a board
+// after some of its cards changed, or after its schema changed if changed
+// is nil. Errors are logged, as the change that triggered the computation
+// has already been saved.
+func (a *App) refreshComputedProperties(board *model.Board, changed []model.Block, modifiedByID string) map[string]model.Block {
+	updated, err := a.updateComputedProperties(board, changed, modifiedByID)
+	if err != nil {
+		a.logger.Error("Could not update the computed properties of the board",
+			mlog.String("boardID", board.ID),
+			mlog.Err(err),
+		)
+	}
+	return updated
+}
+
+// refreshPatchedComputedProperties recomputes the computed properties of
+// a board after some of its cards were patched, unless the patches changed
+// none of the properties feeding them. previous holds the cards before the
+// patches.
+func (a *App) refreshPatchedComputedProperties(board *model.Board, previous map[string]*model.Block, patched []model.Block, modifiedByID string) map[string]model.Block {
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil || !schema.HasComputedProperties() {
+		return nil
+	}
+
+	var changed []model.Block
+	for i := range patched {
+		if schema.CardChangeAffectsComputedProperties(previous[patched[i].ID], &patched[i]) {
+			// the previous version of the card also covers its previous parent
+			changed = append(changed, *previous[patched[i].ID])
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return a.refreshComputedProperties(board, changed, modifiedByID)
+}
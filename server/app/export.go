@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/wiggin77/merror"
@@ -68,6 +69,16 @@ func (a *App) writeArchiveVersion(zw *zip.Writer) error {
 
 // writeArchiveBoard writes a single board to the archive in a zip directory.
 func (a *App) writeArchiveBoard(zw *zip.Writer, board model.Board, opt model.ExportArchiveOptions) error {
+	if opt.ModifiedSince != 0 && board.UpdateAt < opt.ModifiedSince {
+		changed, err := a.hasArchiveChanges(board.ID, opt)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+	}
+
 	// create a directory per board
 	w, err := zw.Create(board.ID + "/board.jsonl")
 	if err != nil {
@@ -79,70 +90,215 @@ func (a *App) writeArchiveBoard(zw *zip.Writer, board model.Board, opt model.Exp
 		return err
 	}
 
-	var files []string
-	// write the board's blocks
-	// TODO: paginate this
-	blocks, err := a.GetBlocksWithBoardID(board.ID)
+	// write the board's blocks, a page at a time
+	files, err := a.writeArchiveBlocks(w, board.ID, opt)
 	if err != nil {
 		return err
 	}
 
-	for _, block := range blocks {
-		if err = a.writeArchiveBlockLine(w, block); err != nil {
+	if opt.IncludeDeleted {
+		tombstones, err := a.store.GetBlockTombstones(board.ID, opt.ModifiedSince)
+		if err != nil {
 			return err
 		}
-		if block.Type == model.TypeImage {
-			filename, err := extractImageFilename(block)
-			if err != nil {
+		for _, tombstone := range tombstones {
+			if err = writeArchiveLine(w, "block_tombstone", tombstone); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opt.IncludeMembers {
+		members, err := a.GetMembersForBoard(board.ID)
+		if err != nil {
+			return err
+		}
+		for _, member := range members {
+			if err = writeArchiveLine(w, "board_member", member); err != nil {
 				return err
 			}
-			files = append(files, filename)
+		}
+	}
+
+	if opt.IncludeHistory {
+		if err = a.writeArchiveHistory(w, board.ID, opt); err != nil {
+			return err
 		}
 	}
 
 	// write the files
-	for _, filename := range files {
-		if err := a.writeArchiveFile(zw, filename, board.ID, opt); err != nil {
-			return fmt.Errorf("cannot write file %s to archive: %w", filename, err)
+	if opt.Files != model.ExportFilesReference {
+		for _, filename := range files {
+			if err := a.writeArchiveFile(zw, filename, board.ID, opt); err != nil {
+				return fmt.Errorf("cannot write file %s to archive: %w", filename, err)
+			}
+		}
+	}
+
+	// write the template metadata, if any
+	if info, ok := opt.TemplateInfo[board.ID]; ok {
+		if err := a.writeArchiveTemplateInfo(zw, board.ID, info); err != nil {
+			return fmt.Errorf("cannot write template info to archive: %w", err)
 		}
 	}
 	return nil
 }
 
-// writeArchiveBlockLine writes a single block to the archive.
-func (a *App) writeArchiveBlockLine(w io.Writer, block model.Block) error {
-	b, err := json.Marshal(&block)
+// hasArchiveChanges returns true if a board has blocks updated, or deleted
+// when those are exported, since opt.ModifiedSince.
+func (a *App) hasArchiveChanges(boardID string, opt model.ExportArchiveOptions) (bool, error) {
+	blocks, err := a.store.GetBlocksPage(boardID, model.QueryBlocksPageOptions{
+		UpdatedSince: opt.ModifiedSince,
+		PerPage:      1,
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(blocks) != 0 {
+		return true, nil
+	}
+
+	if !opt.IncludeDeleted {
+		return false, nil
+	}
+	tombstones, err := a.store.GetBlockTombstones(boardID, opt.ModifiedSince)
+	if err != nil {
+		return false, err
+	}
+	return len(tombstones) != 0, nil
+}
+
+// writeArchiveBlocks writes the blocks of a board to the archive, and
+// returns the names of the files attached to them.
+func (a *App) writeArchiveBlocks(w io.Writer, boardID string, opt model.ExportArchiveOptions) ([]string, error) {
+	var files []string
+
+	pageOpts := model.QueryBlocksPageOptions{
+		UpdatedSince: opt.ModifiedSince,
+		PerPage:      model.BlocksPageMaxPerPage,
+	}
+	for {
+		blocks, err := a.store.GetBlocksPage(boardID, pageOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		hasNext := len(blocks) > pageOpts.PerPage
+		if hasNext {
+			blocks = blocks[:pageOpts.PerPage]
+		}
+
+		for _, block := range blocks {
+			if !includeBlockInArchive(block, opt) {
+				continue
+			}
+			if err = a.writeArchiveBlockLine(w, block); err != nil {
+				return nil, err
+			}
+
+			filename, err := archiveBlockFilename(block)
+			if err != nil {
+				return nil, err
+			}
+			if filename != "" {
+				files = append(files, filename)
+			}
+		}
+
+		if !hasNext {
+			return files, nil
+		}
+		last := blocks[len(blocks)-1]
+		pageOpts.Cursor = &model.BlocksCursor{UpdateAt: last.UpdateAt, ID: last.ID}
+	}
+}
+
+// writeArchiveHistory writes the history of a board and of its blocks to the
+// archive.
+func (a *App) writeArchiveHistory(w io.Writer, boardID string, opt model.ExportArchiveOptions) error {
+	boards, err := a.store.GetBoardHistory(boardID, model.QueryBoardHistoryOptions{AfterUpdateAt: opt.ModifiedSince})
 	if err != nil {
 		return err
 	}
-	line := model.ArchiveLine{
-		Type: "block",
-		Data: b,
+	for _, board := range boards {
+		if err = writeArchiveLine(w, "board_history", board); err != nil {
+			return err
+		}
 	}
 
-	b, err = json.Marshal(&line)
+	blocks, err := a.store.GetBlockHistoryDescendants(boardID, model.QueryBlockHistoryOptions{AfterUpdateAt: opt.ModifiedSince})
 	if err != nil {
 		return err
 	}
+	for _, block := range blocks {
+		if !includeBlockInArchive(block, opt) {
+			continue
+		}
+		if err = writeArchiveLine(w, "block_history", block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	_, err = w.Write(b)
+// includeBlockInArchive returns false for the blocks filtered out by the
+// export options.
+func includeBlockInArchive(block model.Block, opt model.ExportArchiveOptions) bool {
+	if opt.ExcludeComments && block.Type == model.TypeComment {
+		return false
+	}
+	if opt.Files == model.ExportFilesExclude {
+		if filename, _ := archiveBlockFilename(block); filename != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// archiveBlockFilename returns the name of the file attached to a block, if
+// any.
+func archiveBlockFilename(block model.Block) (string, error) {
+	if block.Type == model.TypeImage {
+		return extractImageFilename(block)
+	}
+	filename, _ := block.Fields["fileId"].(string)
+	return filename, nil
+}
+
+// writeArchiveTemplateInfo writes the template metadata of a board to the zip.
+func (a *App) writeArchiveTemplateInfo(zw *zip.Writer, boardID string, info *model.TemplateArchiveInfo) error {
+	b, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
 
-	// jsonl files need a newline
-	_, err = w.Write(newline)
+	w, err := zw.Create(boardID + "/template.json")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
 	return err
 }
 
 // writeArchiveBlockLine writes a single block to the archive.
+func (a *App) writeArchiveBlockLine(w io.Writer, block model.Block) error {
+	return writeArchiveLine(w, "block", &block)
+}
+
+// writeArchiveBoardLine writes a single board to the archive.
 func (a *App) writeArchiveBoardLine(w io.Writer, board model.Board) error {
-	b, err := json.Marshal(&board)
+	return writeArchiveLine(w, "board", &board)
+}
+
+// writeArchiveLine writes a line of the given type to the archive.
+func writeArchiveLine(w io.Writer, lineType string, data interface{}) error {
+	b, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 	line := model.ArchiveLine{
-		Type: "board",
+		Type: lineType,
 		Data: b,
 	}
 
@@ -163,25 +319,34 @@ func (a *App) writeArchiveBoardLine(w io.Writer, board model.Board) error {
 
 // writeArchiveFile writes a single file to the archive.
 func (a *App) writeArchiveFile(zw *zip.Writer, filename string, boardID string, opt model.ExportArchiveOptions) error {
-	dest, err := zw.Create(boardID + "/" + filename)
-	if err != nil {
-		return err
-	}
-
 	src, err := a.GetFileReader(opt.TeamID, boardID, filename)
 	if err != nil {
-		// just log this; image file is missing but we'll still export an equivalent board
+		// just log this; image file is missing or blocked by its scan but we'll still export an equivalent board
 		a.logger.Error("image file missing for export",
 			mlog.String("filename", filename),
 			mlog.String("team_id", opt.TeamID),
 			mlog.String("board_id", boardID),
+			mlog.Err(err),
 		)
 		return nil
 	}
 	defer src.Close()
 
-	_, err = io.Copy(dest, src)
-	return err
+	// files are mostly compressed already, so they are stored as is and
+	// streamed straight to the writer instead of going through deflate.
+	dest, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     boardID + "/" + filename,
+		Method:   zip.Store,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(dest, src); err != nil {
+		return err
+	}
+	return zw.Flush()
 }
 
 // getBoardsForArchive fetches all the specified boards.
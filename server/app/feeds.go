@@ -0,0 +1,332 @@
+package app
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/ical"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+// feedRefreshInterval is how often the calendar apps are asked to refresh
+// the feeds, so that they follow the changes of the cards.
+const feedRefreshInterval = 15 * time.Minute
+
+const oneDay = 24 * time.Hour
+
+// GetFeedToken returns the feed token of a user, or nil if they have none.
+func (a *App) GetFeedToken(userID string) (*model.FeedToken, error) {
+	feedToken, err := a.store.GetFeedToken(userID)
+	if model.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return feedToken, nil
+}
+
+// RegenerateFeedToken generates a new feed token for a user, revoking the
+// previous one.
+func (a *App) RegenerateFeedToken(userID string) (*model.FeedToken, error) {
+	feedToken := &model.FeedToken{
+		UserID:   userID,
+		Token:    utils.NewID(utils.IDTypeToken),
+		CreateAt: utils.GetMillis(),
+	}
+	if err := a.store.UpsertFeedToken(feedToken); err != nil {
+		return nil, err
+	}
+	return feedToken, nil
+}
+
+// RevokeFeedToken deletes the feed token of a user, so that their feeds
+// can't be read anymore.
+func (a *App) RevokeFeedToken(userID string) error {
+	return a.store.DeleteFeedToken(userID)
+}
+
+// GetFeedTokenUserID returns the ID of the user of a feed token, or
+// ErrInvalidFeedToken if no active user has this token.
+func (a *App) GetFeedTokenUserID(token string) (string, error) {
+	if token == "" {
+		return "", model.ErrInvalidFeedToken
+	}
+	feedToken, err := a.store.GetFeedTokenByToken(token)
+	if model.IsErrNotFound(err) {
+		return "", model.ErrInvalidFeedToken
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// the tokens of the deactivated users are rejected, even if they are
+	// still members of boards
+	user, err := a.store.GetUserByID(feedToken.UserID)
+	if errors.Is(err, sql.ErrNoRows) || model.IsErrNotFound(err) {
+		return "", model.ErrInvalidFeedToken
+	}
+	if err != nil {
+		return "", err
+	}
+	if user == nil || user.DeleteAt != 0 {
+		return "", model.ErrInvalidFeedToken
+	}
+	return user.ID, nil
+}
+
+// GetBoardCalendar returns the calendar of the cards of a board, at the
+// dates of one of its date properties. If propertyID is empty, the first
+// date property of the board is used.
+func (a *App) GetBoardCalendar(boardID, propertyID string) (*ical.Calendar, error) {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if board == nil {
+		return nil, model.NewErrNotFound("board boardID=" + boardID)
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+	if propertyID == "" {
+		propertyID = firstDateProperty(schema)
+	} else if schema[propertyID].Type != "date" {
+		return nil, model.ErrFeedPropertyNotDate
+	}
+
+	calendar := &ical.Calendar{
+		Name:            board.Title,
+		RefreshInterval: feedRefreshInterval,
+		Events:          []ical.Event{},
+	}
+	if propertyID == "" {
+		return calendar, nil
+	}
+
+	cards, err := a.store.GetBlocksWithType(boardID, model.TypeCard)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := newCalendarFeed(a)
+	for i := range cards {
+		feed.addCard(board, schema, propertyID, &cards[i])
+	}
+	if calendar.Events, err = feed.events(); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// GetUserCalendar returns the calendar of the cards assigned to a user,
+// across the unarchived boards they are a member of, at the dates of the
+// first date property of each board.
+func (a *App) GetUserCalendar(userID string) (*ical.Calendar, error) {
+	calendar := &ical.Calendar{
+		Name:            "My cards",
+		RefreshInterval: feedRefreshInterval,
+		Events:          []ical.Event{},
+	}
+
+	members, err := a.store.GetMembersForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	boardIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		boardIDs = append(boardIDs, member.BoardID)
+	}
+	if len(boardIDs) == 0 {
+		return calendar, nil
+	}
+
+	allBoards, err := a.store.GetBoardsByIDs(boardIDs)
+	if err != nil {
+		return nil, err
+	}
+	boards := make([]*model.Board, 0, len(allBoards))
+	for _, board := range allBoards {
+		if !board.IsTemplate && board.ArchiveAt == 0 {
+			boards = append(boards, board)
+		}
+	}
+
+	// the cards without date are filtered out by the store
+	query, err := model.NewUserCardsQuery(userID, boards, model.QueryUserCardsOptions{DueAfter: 1})
+	if err != nil {
+		return nil, err
+	}
+	cards, err := a.store.GetCardsForUser(query)
+	if err != nil {
+		return nil, err
+	}
+
+	boardsByID := make(map[string]*model.Board, len(boards))
+	schemas := make(map[string]model.PropSchema, len(boards))
+	for _, board := range boards {
+		boardsByID[board.ID] = board
+		if schemas[board.ID], err = model.ParsePropertySchema(board); err != nil {
+			return nil, err
+		}
+	}
+
+	feed := newCalendarFeed(a)
+	for i := range cards {
+		card := &cards[i]
+		board := boardsByID[card.BoardID]
+		schema := schemas[card.BoardID]
+		// the cards created by the user are listed only if assigned to them
+		if board == nil || !containsString(model.GetCardAssignees(card, schema), userID) {
+			continue
+		}
+		feed.addCard(board, schema, firstDateProperty(schema), card)
+	}
+	if calendar.Events, err = feed.events(); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// calendarFeed builds the events of the cards of a calendar, resolving the
+// usernames of their assignees at once.
+type calendarFeed struct {
+	app       *App
+	cards     []*model.Block
+	boards    []*model.Board
+	dates     []*model.CardDate
+	assignees [][]string
+}
+
+func newCalendarFeed(app *App) *calendarFeed {
+	return &calendarFeed{app: app}
+}
+
+func (f *calendarFeed) addCard(board *model.Board, schema model.PropSchema, propertyID string, card *model.Block) {
+	if card.ArchiveAt != 0 {
+		return
+	}
+	if isTemplate, _ := card.Fields["isTemplate"].(bool); isTemplate {
+		return
+	}
+	date := model.GetCardDate(card, propertyID)
+	if date == nil {
+		return
+	}
+
+	f.cards = append(f.cards, card)
+	f.boards = append(f.boards, board)
+	f.dates = append(f.dates, date)
+	f.assignees = append(f.assignees, model.GetCardAssignees(card, schema))
+}
+
+func (f *calendarFeed) events() ([]ical.Event, error) {
+	usernames, err := f.usernames()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]ical.Event, 0, len(f.cards))
+	for i, card := range f.cards {
+		board := f.boards[i]
+		date := f.dates[i]
+
+		description := []string{"Board: " + board.Title}
+		if len(f.assignees[i]) > 0 {
+			names := make([]string, 0, len(f.assignees[i]))
+			for _, userID := range f.assignees[i] {
+				if username, ok := usernames[userID]; ok {
+					names = append(names, "@"+username)
+				}
+			}
+			if len(names) > 0 {
+				description = append(description, "Assignees: "+strings.Join(names, ", "))
+			}
+		}
+
+		event := ical.Event{
+			UID:          card.ID + "@focalboard",
+			Summary:      card.Title,
+			Description:  strings.Join(description, "\n"),
+			URL:          utils.MakeCardLink(f.app.config.ServerRoot, board.TeamID, board.ID, card.ID),
+			Start:        utils.GetTimeForMillis(date.From),
+			End:          utils.GetTimeForMillis(date.From),
+			AllDay:       !date.IncludeTime,
+			LastModified: utils.GetTimeForMillis(card.UpdateAt),
+		}
+		if date.To != 0 {
+			event.End = utils.GetTimeForMillis(date.To)
+		}
+		if event.AllDay {
+			// the dates without time are stored at noon UTC, and the
+			// all-day events end the day after their last day
+			event.Start = event.Start.UTC().Truncate(oneDay)
+			event.End = event.End.UTC().Truncate(oneDay).Add(oneDay)
+		}
+		events = append(events, event)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+	return events, nil
+}
+
+// usernames returns the usernames of the assignees of the cards, keyed by
+// user ID. The users that don't exist anymore are left out.
+func (f *calendarFeed) usernames() (map[string]string, error) {
+	userIDs := []string{}
+	seen := map[string]bool{}
+	for _, assignees := range f.assignees {
+		for _, userID := range assignees {
+			if !seen[userID] {
+				seen[userID] = true
+				userIDs = append(userIDs, userID)
+			}
+		}
+	}
+
+	usernames := make(map[string]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return usernames, nil
+	}
+	users, err := f.app.store.GetUsersList(userIDs)
+	if err != nil && !model.IsErrNotFound(err) {
+		return nil, err
+	}
+	for _, user := range users {
+		usernames[user.ID] = user.Username
+	}
+	return usernames, nil
+}
+
+// firstDateProperty returns the ID of the first date property of a board,
+// or an empty string if it has none.
+func firstDateProperty(schema model.PropSchema) string {
+	var first *model.PropDef
+	for id := range schema {
+		pd := schema[id]
+		if pd.Type == "date" && (first == nil || pd.Index < first.Index) {
+			first = &pd
+		}
+	}
+	if first == nil {
+		return ""
+	}
+	return first.ID
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
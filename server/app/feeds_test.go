@@ -0,0 +1,201 @@
+package app
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedTokens(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("a user without token", func(t *testing.T) {
+		th.Store.EXPECT().GetFeedToken("user-id").Return(nil, model.NewErrNotFound("feed token"))
+
+		feedToken, err := th.App.GetFeedToken("user-id")
+		require.NoError(t, err)
+		require.Nil(t, feedToken)
+	})
+
+	t.Run("regenerate a token", func(t *testing.T) {
+		var saved *model.FeedToken
+		th.Store.EXPECT().UpsertFeedToken(gomock.Any()).DoAndReturn(func(feedToken *model.FeedToken) error {
+			saved = feedToken
+			return nil
+		})
+
+		feedToken, err := th.App.RegenerateFeedToken("user-id")
+		require.NoError(t, err)
+		require.Equal(t, saved, feedToken)
+		require.Equal(t, "user-id", feedToken.UserID)
+		require.NotEmpty(t, feedToken.Token)
+		require.NotZero(t, feedToken.CreateAt)
+	})
+
+	t.Run("user of a token", func(t *testing.T) {
+		th.Store.EXPECT().GetFeedTokenByToken("token").Return(&model.FeedToken{UserID: "user-id", Token: "token"}, nil)
+		th.Store.EXPECT().GetUserByID("user-id").Return(&model.User{ID: "user-id"}, nil)
+
+		userID, err := th.App.GetFeedTokenUserID("token")
+		require.NoError(t, err)
+		require.Equal(t, "user-id", userID)
+	})
+
+	t.Run("invalid tokens", func(t *testing.T) {
+		th.Store.EXPECT().GetFeedTokenByToken("revoked").Return(nil, model.NewErrNotFound("feed token"))
+
+		_, err := th.App.GetFeedTokenUserID("revoked")
+		require.ErrorIs(t, err, model.ErrInvalidFeedToken)
+		_, err = th.App.GetFeedTokenUserID("")
+		require.ErrorIs(t, err, model.ErrInvalidFeedToken)
+	})
+
+	t.Run("token of a deactivated user", func(t *testing.T) {
+		th.Store.EXPECT().GetFeedTokenByToken("deleted-user-token").Return(&model.FeedToken{UserID: "deleted-user-id"}, nil)
+		th.Store.EXPECT().GetUserByID("deleted-user-id").Return(nil, sql.ErrNoRows)
+		th.Store.EXPECT().GetFeedTokenByToken("deactivated-user-token").Return(&model.FeedToken{UserID: "deactivated-user-id"}, nil)
+		th.Store.EXPECT().GetUserByID("deactivated-user-id").Return(&model.User{ID: "deactivated-user-id", DeleteAt: 1000}, nil)
+
+		_, err := th.App.GetFeedTokenUserID("deleted-user-token")
+		require.ErrorIs(t, err, model.ErrInvalidFeedToken)
+		_, err = th.App.GetFeedTokenUserID("deactivated-user-token")
+		require.ErrorIs(t, err, model.ErrInvalidFeedToken)
+	})
+}
+
+func TestGetBoardCalendar(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+	th.App.config.ServerRoot = "http://localhost:8000"
+
+	board := &model.Board{ID: "board-id", TeamID: "team-id", Title: "Roadmap", CardProperties: []map[string]interface{}{
+		{"id": "owner", "name": "Owner", "type": "person"},
+		{"id": "start", "name": "Start", "type": "date"},
+		{"id": "end", "name": "End", "type": "date"},
+	}}
+	card := func(id string, properties map[string]interface{}) model.Block {
+		return model.Block{ID: id, BoardID: "board-id", Type: model.TypeCard, Title: "Card " + id, UpdateAt: 1641801600000, Fields: map[string]interface{}{
+			"properties": properties,
+		}}
+	}
+	cards := []model.Block{
+		card("timed", map[string]interface{}{"start": `{"from":1642671000000,"to":1642674600000,"includeTime":true}`}),
+		card("range", map[string]interface{}{"start": `{"from":1642161600000,"to":1642334400000}`, "owner": "user-1"}),
+		card("no-date", map[string]interface{}{"owner": "user-1"}),
+		card("end-only", map[string]interface{}{"end": `{"from":1642161600000}`}),
+	}
+	archived := card("archived", map[string]interface{}{"start": `{"from":1642161600000}`})
+	archived.ArchiveAt = 1000
+	template := card("template", map[string]interface{}{"start": `{"from":1642161600000}`})
+	template.Fields["isTemplate"] = true
+	cards = append(cards, archived, template)
+
+	t.Run("cards at the first date property", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+		th.Store.EXPECT().GetBlocksWithType("board-id", model.TypeCard).Return(cards, nil)
+		th.Store.EXPECT().GetUsersList([]string{"user-1"}).Return([]*model.User{{ID: "user-1", Username: "alice"}}, nil)
+
+		calendar, err := th.App.GetBoardCalendar("board-id", "")
+		require.NoError(t, err)
+		require.Equal(t, "Roadmap", calendar.Name)
+		require.Len(t, calendar.Events, 2)
+
+		// the events are sorted by start, and the all-day range ends the day after its last day
+		allDay := calendar.Events[0]
+		require.Equal(t, "range@focalboard", allDay.UID)
+		require.Equal(t, "Card range", allDay.Summary)
+		require.True(t, allDay.AllDay)
+		require.Equal(t, time.Date(2022, 1, 14, 0, 0, 0, 0, time.UTC), allDay.Start.UTC())
+		require.Equal(t, time.Date(2022, 1, 17, 0, 0, 0, 0, time.UTC), allDay.End.UTC())
+		require.Equal(t, "Board: Roadmap\nAssignees: @alice", allDay.Description)
+		require.Equal(t, "http://localhost:8000/team/team-id/board-id/0/range", allDay.URL)
+		require.Equal(t, time.Date(2022, 1, 10, 8, 0, 0, 0, time.UTC), allDay.LastModified.UTC())
+
+		timed := calendar.Events[1]
+		require.Equal(t, "timed@focalboard", timed.UID)
+		require.False(t, timed.AllDay)
+		require.Equal(t, time.Date(2022, 1, 20, 9, 30, 0, 0, time.UTC), timed.Start.UTC())
+		require.Equal(t, time.Date(2022, 1, 20, 10, 30, 0, 0, time.UTC), timed.End.UTC())
+		require.Equal(t, "Board: Roadmap", timed.Description)
+	})
+
+	t.Run("cards at a given date property", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+		th.Store.EXPECT().GetBlocksWithType("board-id", model.TypeCard).Return(cards, nil)
+
+		calendar, err := th.App.GetBoardCalendar("board-id", "end")
+		require.NoError(t, err)
+		require.Len(t, calendar.Events, 1)
+		require.Equal(t, "end-only@focalboard", calendar.Events[0].UID)
+		require.Equal(t, time.Date(2022, 1, 15, 0, 0, 0, 0, time.UTC), calendar.Events[0].End.UTC())
+	})
+
+	t.Run("not a date property", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil).Times(2)
+
+		_, err := th.App.GetBoardCalendar("board-id", "owner")
+		require.ErrorIs(t, err, model.ErrFeedPropertyNotDate)
+		_, err = th.App.GetBoardCalendar("board-id", "unknown")
+		require.ErrorIs(t, err, model.ErrFeedPropertyNotDate)
+	})
+
+	t.Run("board without date property", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("other-board-id").Return(&model.Board{ID: "other-board-id", Title: "Other"}, nil)
+
+		calendar, err := th.App.GetBoardCalendar("other-board-id", "")
+		require.NoError(t, err)
+		require.Equal(t, "Other", calendar.Name)
+		require.Empty(t, calendar.Events)
+	})
+}
+
+func TestGetUserCalendar(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	boards := []*model.Board{
+		{ID: "board-id", TeamID: "team-id", Title: "Board", CardProperties: []map[string]interface{}{
+			{"id": "owner", "name": "Owner", "type": "person"},
+			{"id": "due", "name": "Due", "type": "date"},
+		}},
+		{ID: "archived-board-id", TeamID: "team-id", ArchiveAt: 1000},
+	}
+	card := func(id, owner string) model.Block {
+		return model.Block{ID: id, BoardID: "board-id", Type: model.TypeCard, CreatedBy: "user-id", Fields: map[string]interface{}{
+			"properties": map[string]interface{}{"owner": owner, "due": `{"from":1642161600000}`},
+		}}
+	}
+
+	t.Run("cards assigned to the user", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForUser("user-id").Return([]*model.BoardMember{
+			{BoardID: "board-id", UserID: "user-id"},
+			{BoardID: "archived-board-id", UserID: "user-id"},
+		}, nil)
+		th.Store.EXPECT().GetBoardsByIDs([]string{"board-id", "archived-board-id"}).Return(boards, nil)
+		th.Store.EXPECT().GetCardsForUser(gomock.Any()).DoAndReturn(func(query *model.UserCardsQuery) ([]model.Block, error) {
+			require.Equal(t, []string{"board-id"}, query.BoardIDs)
+			require.Zero(t, query.PerPage)
+			return []model.Block{card("assigned", "user-id"), card("created", "other-user-id")}, nil
+		})
+		th.Store.EXPECT().GetUsersList([]string{"user-id"}).Return([]*model.User{{ID: "user-id", Username: "alice"}}, nil)
+
+		calendar, err := th.App.GetUserCalendar("user-id")
+		require.NoError(t, err)
+		require.Len(t, calendar.Events, 1)
+		require.Equal(t, "assigned@focalboard", calendar.Events[0].UID)
+		require.Equal(t, "Board: Board\nAssignees: @alice", calendar.Events[0].Description)
+	})
+
+	t.Run("no boards", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForUser("user-id").Return([]*model.BoardMember{}, nil)
+
+		calendar, err := th.App.GetUserCalendar("user-id")
+		require.NoError(t, err)
+		require.Empty(t, calendar.Events)
+	})
+}
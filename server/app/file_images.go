@@ -0,0 +1,211 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"strings"
+
+	// decoders of the image formats with thumbnails
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	mmModel "github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+const (
+	FileSizeThumbnail = "thumb"
+	FileSizePreview   = "preview"
+
+	fileThumbnailWidth  = 120
+	fileThumbnailHeight = 100
+	filePreviewWidth    = 1920
+	fileImageQuality    = 90
+
+	// maxImageResolution is the largest number of pixels of the images
+	// whose thumbnails are generated, to bound the memory used to decode
+	// them.
+	maxImageResolution = 7680 * 4320
+)
+
+var (
+	ErrInvalidFileSize = errors.New("invalid file size, must be thumb or preview")
+	errImageTooLarge   = errors.New("image resolution too large")
+)
+
+// hasFileImages returns true if thumbnails and previews are generated for the
+// files of the MIME type.
+func hasFileImages(mimeType string) bool {
+	switch mimeType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// generateFileImages reads the dimensions of an image file and writes its
+// thumbnail and preview alongside it, as JPEG images.
+func (a *App) generateFileImages(fileInfo *mmModel.FileInfo) error {
+	reader, err := a.filesBackend.Reader(fileInfo.Path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	config, _, err := image.DecodeConfig(reader)
+	if err != nil {
+		return err
+	}
+	fileInfo.Width = config.Width
+	fileInfo.Height = config.Height
+	if config.Width*config.Height > maxImageResolution {
+		return fmt.Errorf("%w: %dx%d", errImageTooLarge, config.Width, config.Height)
+	}
+
+	if _, err = reader.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	img, _, err := image.Decode(reader)
+	if err != nil {
+		return err
+	}
+
+	basePath := strings.TrimSuffix(fileInfo.Path, fileInfo.Extension)
+	thumbnailPath := basePath + "_thumb.jpg"
+	width, height := fitImage(config.Width, config.Height, fileThumbnailWidth, fileThumbnailHeight)
+	if err := a.writeJPEGImage(resizeImage(img, width, height), thumbnailPath); err != nil {
+		return fmt.Errorf("cannot write the thumbnail: %w", err)
+	}
+
+	previewPath := basePath + "_preview.jpg"
+	width, height = fitImage(config.Width, config.Height, filePreviewWidth, 0)
+	if err := a.writeJPEGImage(resizeImage(img, width, height), previewPath); err != nil {
+		return fmt.Errorf("cannot write the preview: %w", err)
+	}
+
+	fileInfo.ThumbnailPath = thumbnailPath
+	fileInfo.PreviewPath = previewPath
+	fileInfo.HasPreviewImage = true
+	return nil
+}
+
+// generateFileImagesOrWarn generates the thumbnail and preview of an image
+// file. The file is usable without them, so failing to generate them is
+// only logged.
+func (a *App) generateFileImagesOrWarn(fileInfo *mmModel.FileInfo) {
+	if err := a.generateFileImages(fileInfo); err != nil {
+		a.logger.Warn("cannot generate the thumbnail of an image",
+			mlog.String("path", fileInfo.Path),
+			mlog.Err(err),
+		)
+	}
+}
+
+// generateScannedFileImages generates the thumbnail and preview of an image
+// file once its scan found it clean, unless it already has them, like the
+// copies of files scanned before.
+func (a *App) generateScannedFileImages(scan *model.FileScan) error {
+	fileInfo, err := a.GetFileInfo(scan.FileID)
+	if err != nil {
+		return err
+	}
+	if fileInfo == nil || fileInfo.HasPreviewImage || !hasFileImages(fileInfo.MimeType) {
+		return nil
+	}
+
+	a.generateFileImagesOrWarn(fileInfo)
+	if !fileInfo.HasPreviewImage {
+		return nil
+	}
+	return a.store.UpdateFileInfoImages(fileInfo)
+}
+
+func (a *App) writeJPEGImage(img image.Image, path string) error {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: fileImageQuality}); err != nil {
+		return err
+	}
+	_, err := a.filesBackend.WriteFile(&buf, path)
+	return err
+}
+
+// fitImage returns the dimensions of an image scaled down to fit in
+// maxWidth x maxHeight, keeping its aspect ratio. A zero maximum doesn't
+// limit the dimension. Images are never scaled up.
+func fitImage(width, height, maxWidth, maxHeight int) (int, int) {
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		scale = float64(maxWidth) / float64(width)
+	}
+	if maxHeight > 0 && float64(height)*scale > float64(maxHeight) {
+		scale = float64(maxHeight) / float64(height)
+	}
+
+	scaledWidth := int(float64(width) * scale)
+	scaledHeight := int(float64(height) * scale)
+	if scaledWidth < 1 {
+		scaledWidth = 1
+	}
+	if scaledHeight < 1 {
+		scaledHeight = 1
+	}
+	return scaledWidth, scaledHeight
+}
+
+// resizeImage scales an image down to width x height, each pixel of the
+// result being the average of the pixels of the image it covers. The
+// transparent parts of the image are drawn over a white background, as
+// JPEG has no transparency.
+func resizeImage(img image.Image, width, height int) *image.RGBA {
+	bounds := img.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0, y1 := scaledRange(y, height, srcHeight)
+		for x := 0; x < width; x++ {
+			x0, x1 := scaledRange(x, width, srcWidth)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(bounds.Min.X+sx, bounds.Min.Y+sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+
+			// the colors are premultiplied by alpha, so adding the missing
+			// coverage draws them over white
+			white := 0xffff - a/n
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r/n + white) >> 8),
+				G: uint8((g/n + white) >> 8),
+				B: uint8((b/n + white) >> 8),
+				A: 0xff,
+			})
+		}
+	}
+	return dst
+}
+
+// scaledRange returns the range of the source pixels covered by the pixel i
+// of a dimension scaled from srcSize to size.
+func scaledRange(i, size, srcSize int) (int, int) {
+	start := i * srcSize / size
+	end := (i + 1) * srcSize / size
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopReadCloseSeeker struct {
+	*bytes.Reader
+}
+
+func (nopReadCloseSeeker) Close() error { return nil }
+
+func testPNGImage(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestFitImage(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		width, height        int
+		maxWidth, maxHeight  int
+		expectedW, expectedH int
+	}{
+		{"wide image in a thumbnail", 400, 200, 120, 100, 120, 60},
+		{"tall image in a thumbnail", 200, 400, 120, 100, 50, 100},
+		{"small image isn't scaled up", 60, 40, 120, 100, 60, 40},
+		{"unlimited height", 3840, 2160, 1920, 0, 1920, 1080},
+		{"narrow image keeps a pixel", 1, 1000, 120, 100, 1, 100},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			width, height := fitImage(tc.width, tc.height, tc.maxWidth, tc.maxHeight)
+			assert.Equal(t, tc.expectedW, width)
+			assert.Equal(t, tc.expectedH, height)
+		})
+	}
+}
+
+func TestResizeImage(t *testing.T) {
+	t.Run("averages the pixels", func(t *testing.T) {
+		img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+		img.Set(0, 0, color.NRGBA{A: 255})
+		img.Set(1, 0, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+
+		resized := resizeImage(img, 1, 1)
+		assert.Equal(t, image.Rect(0, 0, 1, 1), resized.Bounds())
+		assert.Equal(t, color.RGBA{R: 127, G: 127, B: 127, A: 255}, resized.RGBAAt(0, 0))
+	})
+
+	t.Run("draws transparent pixels over white", func(t *testing.T) {
+		img := image.NewNRGBA(image.Rect(10, 10, 14, 14))
+
+		resized := resizeImage(img, 2, 2)
+		assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, resized.RGBAAt(1, 1))
+	})
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/scanner"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// fileRescanDelay is how long a scan stays pending or failed before it is
+// retried. It is longer than the timeout of the scans, so that the scans
+// still running aren't started twice.
+const fileRescanDelay = 10 * time.Minute
+
+// fileScanner returns the configured scanner, or nil if the uploads aren't
+// scanned.
+func (a *App) fileScanner() (scanner.Scanner, error) {
+	return scanner.New(a.config.FileScanner, a.config.ClamAVAddress)
+}
+
+// quarantineFile records that a file written in a board must be scanned
+// before it can be downloaded, and scans it in the background. Nothing is
+// done if the uploads aren't scanned.
+func (a *App) quarantineFile(teamID, boardID, fileName, userID string) error {
+	fileScanner, err := a.fileScanner()
+	if err != nil || fileScanner == nil {
+		return err
+	}
+
+	scan := &model.FileScan{
+		FileID:    fileName,
+		BoardID:   boardID,
+		TeamID:    teamID,
+		CreatedBy: userID,
+		Status:    model.FileScanStatusPending,
+	}
+	if err := a.store.InsertFileScan(scan); err != nil {
+		return err
+	}
+
+	go a.scanFile(fileScanner, scan)
+	return nil
+}
+
+// copyFileScan quarantines the copy of a file, like the ones made with
+// their cards. The copies of infected files are infected too, even if the
+// uploads aren't scanned anymore.
+func (a *App) copyFileScan(sourceBoardID, sourceFileName, teamID, boardID, fileName string) error {
+	source, err := a.store.GetFileScan(sourceBoardID, sourceFileName)
+	if err != nil && !model.IsErrNotFound(err) {
+		return err
+	}
+
+	if source == nil || source.Status != model.FileScanStatusInfected {
+		userID := ""
+		if source != nil {
+			userID = source.CreatedBy
+		}
+		return a.quarantineFile(teamID, boardID, fileName, userID)
+	}
+
+	return a.store.InsertFileScan(&model.FileScan{
+		FileID:    fileName,
+		BoardID:   boardID,
+		TeamID:    teamID,
+		CreatedBy: source.CreatedBy,
+		Status:    model.FileScanStatusInfected,
+		Result:    source.Result,
+	})
+}
+
+// scanFile scans a quarantined file and saves the result, which is sent to
+// the user that uploaded it. The files that can't be scanned stay
+// quarantined until they are scanned again.
+func (a *App) scanFile(fileScanner scanner.Scanner, scan *model.FileScan) {
+	filePath := filepath.Join(scan.TeamID, scan.BoardID, scan.FileID)
+	logger := a.logger.With(mlog.String("path", filePath))
+
+	result, err := a.runFileScan(fileScanner, filePath)
+	switch {
+	case err != nil:
+		logger.Error("cannot scan file", mlog.Err(err))
+		scan.Status = model.FileScanStatusFailed
+		scan.Result = err.Error()
+	case result.Infected:
+		logger.Warn("infected file quarantined",
+			mlog.String("threat", result.Threat),
+			mlog.String("uploader", scan.CreatedBy),
+		)
+		scan.Status = model.FileScanStatusInfected
+		scan.Result = result.Threat
+	default:
+		scan.Status = model.FileScanStatusClean
+		scan.Result = ""
+		// the images are generated before the file is marked clean, so that
+		// they can be shown as soon as it is
+		if err := a.generateScannedFileImages(scan); err != nil {
+			logger.Warn("cannot save the thumbnail of a scanned file", mlog.Err(err))
+		}
+	}
+
+	if err := a.store.UpdateFileScan(scan); err != nil {
+		logger.Error("cannot save the result of a file scan", mlog.Err(err))
+		return
+	}
+
+	a.wsAdapter.BroadcastFileScanChange(scan.TeamID, scan)
+}
+
+func (a *App) runFileScan(fileScanner scanner.Scanner, filePath string) (*scanner.Result, error) {
+	reader, err := a.filesBackend.Reader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return fileScanner.Scan(reader)
+}
+
+// CheckFileScan returns ErrFileQuarantined or ErrFileInfected if the scan
+// of a file of a board prevents its download. The files uploaded while the
+// scans were disabled have no scan and can always be downloaded. If the
+// scans are disabled, only the infected files are blocked, as the
+// quarantined ones would never be scanned.
+func (a *App) CheckFileScan(boardID, fileName string) error {
+	scan, err := a.store.GetFileScan(boardID, fileName)
+	if model.IsErrNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = scan.CheckDownload()
+	if a.config.FileScanner == scanner.TypeNone && errors.Is(err, model.ErrFileQuarantined) {
+		return nil
+	}
+	return err
+}
+
+// GetFileScanStatus returns the status of the scan of a file of a board, or
+// an empty string if the file wasn't scanned.
+func (a *App) GetFileScanStatus(boardID, fileName string) (string, error) {
+	scan, err := a.store.GetFileScan(boardID, fileName)
+	if model.IsErrNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return scan.Status, nil
+}
+
+// RescanFiles scans again the files whose scans are pending or failed for
+// some time, for instance because the scanner was unreachable or the server
+// restarted during the scans, and returns how many were scanned.
+func (a *App) RescanFiles() (int, error) {
+	fileScanner, err := a.fileScanner()
+	if err != nil || fileScanner == nil {
+		return 0, err
+	}
+
+	updatedBefore := utils.GetMillisForTime(time.Now().Add(-fileRescanDelay))
+	scans, err := a.store.GetUnfinishedFileScans(updatedBefore)
+	if err != nil {
+		return 0, err
+	}
+	for _, scan := range scans {
+		a.scanFile(fileScanner, scan)
+	}
+	return len(scans), nil
+}
@@ -0,0 +1,255 @@
+package app
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/scanner"
+
+	mmModel "github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/shared/filestore"
+	"github.com/mattermost/mattermost-server/v6/shared/filestore/mocks"
+)
+
+type testScanner struct {
+	result *scanner.Result
+	err    error
+}
+
+func (s *testScanner) Scan(r io.Reader) (*scanner.Result, error) {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, err
+	}
+	return s.result, s.err
+}
+
+// newFileReader returns a function giving a new reader of the content each
+// time a file is read from the mocked files backend.
+func newFileReader(content string) func(string) filestore.ReadCloseSeeker {
+	return func(string) filestore.ReadCloseSeeker {
+		return &nopReadCloseSeeker{bytes.NewReader([]byte(content))}
+	}
+}
+
+func TestScanFile(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	filePath := filepath.Join("team-id", testBoardID, "7file.txt")
+	mockedFileBackend := &mocks.FileBackend{}
+	th.App.filesBackend = mockedFileBackend
+	mockedFileBackend.On("Reader", filePath).Return(newFileReader("content"), nil)
+
+	newScan := func() *model.FileScan {
+		return &model.FileScan{
+			FileID:    "7file.txt",
+			BoardID:   testBoardID,
+			TeamID:    "team-id",
+			CreatedBy: "user-id",
+			Status:    model.FileScanStatusPending,
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		scanner        *testScanner
+		expectedStatus string
+		expectedResult string
+	}{
+		{"clean", &testScanner{result: &scanner.Result{}}, model.FileScanStatusClean, ""},
+		{"infected", &testScanner{result: &scanner.Result{Infected: true, Threat: "Eicar-Test-Signature"}}, model.FileScanStatusInfected, "Eicar-Test-Signature"},
+		{"failed", &testScanner{err: errDummy}, model.FileScanStatusFailed, errDummy.Error()},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scan := newScan()
+			if tc.expectedStatus == model.FileScanStatusClean {
+				th.Store.EXPECT().GetFileInfo("file").Return(&mmModel.FileInfo{Id: "file", Path: filePath, MimeType: "text/plain"}, nil)
+			}
+			th.Store.EXPECT().UpdateFileScan(scan).Return(nil)
+
+			th.App.scanFile(tc.scanner, scan)
+			assert.Equal(t, tc.expectedStatus, scan.Status)
+			assert.Equal(t, tc.expectedResult, scan.Result)
+		})
+	}
+}
+
+func TestSaveScannedFile(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+	th.App.config.FileScanner = scanner.TypeNoop
+
+	var mu sync.Mutex
+	written := map[string][]byte{}
+	mockedFileBackend := &mocks.FileBackend{}
+	th.App.filesBackend = mockedFileBackend
+	mockedFileBackend.On("WriteFile", mock.Anything, mock.Anything).Return(func(reader io.Reader, path string) int64 {
+		data, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		written[path] = data
+		return int64(len(data))
+	}, nil)
+	mockedFileBackend.On("Reader", mock.Anything).Return(func(path string) filestore.ReadCloseSeeker {
+		mu.Lock()
+		defer mu.Unlock()
+		return nopReadCloseSeeker{bytes.NewReader(written[path])}
+	}, nil)
+
+	var savedFileInfo *mmModel.FileInfo
+	th.Store.EXPECT().SaveFileInfo(gomock.Any()).DoAndReturn(func(fileInfo *mmModel.FileInfo) error {
+		savedFileInfo = fileInfo
+		return nil
+	})
+	th.Store.EXPECT().InsertFileScan(gomock.Any()).Return(nil)
+
+	// the images are saved before the file is marked clean
+	var imagesFileInfo *mmModel.FileInfo
+	scanned := make(chan *mmModel.FileInfo, 1)
+	th.Store.EXPECT().GetFileInfo(gomock.Any()).DoAndReturn(func(string) (*mmModel.FileInfo, error) {
+		fileInfo := *savedFileInfo
+		return &fileInfo, nil
+	})
+	updateImages := th.Store.EXPECT().UpdateFileInfoImages(gomock.Any()).DoAndReturn(func(fileInfo *mmModel.FileInfo) error {
+		imagesFileInfo = fileInfo
+		return nil
+	})
+	th.Store.EXPECT().UpdateFileScan(gomock.Any()).After(updateImages).DoAndReturn(func(scan *model.FileScan) error {
+		assert.Equal(t, model.FileScanStatusClean, scan.Status)
+		scanned <- imagesFileInfo
+		return nil
+	})
+
+	content := testPNGImage(t, 400, 200)
+	_, err := th.App.SaveFile(bytes.NewReader(content), "team-id", testBoardID, "image.png", "user-id")
+	require.NoError(t, err)
+
+	// the image isn't decoded before its scan
+	assert.False(t, savedFileInfo.HasPreviewImage)
+	assert.Zero(t, savedFileInfo.Width)
+
+	select {
+	case fileInfo := <-scanned:
+		assert.True(t, fileInfo.HasPreviewImage)
+		assert.Equal(t, 400, fileInfo.Width)
+		assert.Equal(t, 200, fileInfo.Height)
+		mu.Lock()
+		assert.NotEmpty(t, written[fileInfo.ThumbnailPath])
+		mu.Unlock()
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the file wasn't scanned")
+	}
+}
+
+func TestCheckFileScan(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	testCases := []struct {
+		name        string
+		fileScanner string
+		scan        *model.FileScan
+		expectedErr error
+	}{
+		{"not scanned", scanner.TypeNoop, nil, nil},
+		{"clean", scanner.TypeNoop, &model.FileScan{Status: model.FileScanStatusClean}, nil},
+		{"pending", scanner.TypeNoop, &model.FileScan{Status: model.FileScanStatusPending}, model.ErrFileQuarantined},
+		{"failed", scanner.TypeNoop, &model.FileScan{Status: model.FileScanStatusFailed}, model.ErrFileQuarantined},
+		{"infected", scanner.TypeNoop, &model.FileScan{Status: model.FileScanStatusInfected}, model.ErrFileInfected},
+		{"pending with the scans disabled", scanner.TypeNone, &model.FileScan{Status: model.FileScanStatusPending}, nil},
+		{"infected with the scans disabled", scanner.TypeNone, &model.FileScan{Status: model.FileScanStatusInfected}, model.ErrFileInfected},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			th.App.config.FileScanner = tc.fileScanner
+			if tc.scan != nil {
+				th.Store.EXPECT().GetFileScan(testBoardID, "7file.txt").Return(tc.scan, nil)
+			} else {
+				th.Store.EXPECT().GetFileScan(testBoardID, "7file.txt").Return(nil, model.NewErrNotFound("file scan"))
+			}
+
+			err := th.App.CheckFileScan(testBoardID, "7file.txt")
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+
+	t.Run("blocks the download of the file", func(t *testing.T) {
+		th.App.config.FileScanner = scanner.TypeNoop
+		th.Store.EXPECT().GetFileScan(testBoardID, "7file.txt").Return(&model.FileScan{Status: model.FileScanStatusInfected}, nil)
+
+		reader, err := th.App.GetFileReader("team-id", testBoardID, "7file.txt")
+		require.ErrorIs(t, err, model.ErrFileInfected)
+		require.Nil(t, reader)
+	})
+}
+
+func TestCopyFileScan(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+	th.App.config.FileScanner = scanner.TypeNone
+
+	t.Run("the copy of an infected file is infected", func(t *testing.T) {
+		th.Store.EXPECT().GetFileScan("source-board-id", "7source.png").Return(&model.FileScan{
+			CreatedBy: "user-id",
+			Status:    model.FileScanStatusInfected,
+			Result:    "Eicar-Test-Signature",
+		}, nil)
+		th.Store.EXPECT().InsertFileScan(&model.FileScan{
+			FileID:    "7copy.png",
+			BoardID:   testBoardID,
+			TeamID:    "team-id",
+			CreatedBy: "user-id",
+			Status:    model.FileScanStatusInfected,
+			Result:    "Eicar-Test-Signature",
+		}).Return(nil)
+
+		require.NoError(t, th.App.copyFileScan("source-board-id", "7source.png", "team-id", testBoardID, "7copy.png"))
+	})
+
+	t.Run("the copy of another file is only scanned if the scans are enabled", func(t *testing.T) {
+		th.Store.EXPECT().GetFileScan("source-board-id", "7source.png").Return(&model.FileScan{Status: model.FileScanStatusClean}, nil)
+
+		require.NoError(t, th.App.copyFileScan("source-board-id", "7source.png", "team-id", testBoardID, "7copy.png"))
+	})
+}
+
+func TestRescanFiles(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("scans disabled", func(t *testing.T) {
+		th.App.config.FileScanner = scanner.TypeNone
+		count, err := th.App.RescanFiles()
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
+	t.Run("scans the unfinished scans", func(t *testing.T) {
+		th.App.config.FileScanner = scanner.TypeNoop
+		scan := &model.FileScan{FileID: "7file.txt", BoardID: testBoardID, TeamID: "team-id", Status: model.FileScanStatusFailed}
+		th.Store.EXPECT().GetUnfinishedFileScans(gomock.Any()).Return([]*model.FileScan{scan}, nil)
+		th.Store.EXPECT().GetFileInfo("file").Return(nil, nil)
+		th.Store.EXPECT().UpdateFileScan(scan).Return(nil)
+
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+		mockedFileBackend.On("Reader", filepath.Join("team-id", testBoardID, "7file.txt")).Return(newFileReader("content"), nil)
+
+		count, err := th.App.RescanFiles()
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		require.Equal(t, model.FileScanStatusClean, scan.Status)
+	})
+}
@@ -1,9 +1,12 @@
 package app
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"net/http"
 	"path/filepath"
 	"strings"
 
@@ -14,11 +17,21 @@ import (
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
 )
 
-const emptyString = "empty"
+const (
+	emptyString = "empty"
+
+	// sniffLen is the number of bytes used to detect the MIME type of a file
+	sniffLen = 512
+
+	octetStreamMimeType = "application/octet-stream"
+)
 
 var errEmptyFilename = errors.New("IsFileArchived: empty filename not allowed")
 
-func (a *App) SaveFile(reader io.Reader, teamID, rootID, filename string) (string, error) {
+// SaveFile stores a file uploaded by a user in a board and returns its name.
+// If the uploads are scanned, the file is quarantined until its scan finds
+// it clean.
+func (a *App) SaveFile(reader io.Reader, teamID, rootID, filename, userID string) (string, error) {
 	// NOTE: File extension includes the dot
 	fileExtension := strings.ToLower(filepath.Ext(filename))
 	if fileExtension == ".jpeg" {
@@ -29,11 +42,26 @@ func (a *App) SaveFile(reader io.Reader, teamID, rootID, filename string) (strin
 	fullFilename := fmt.Sprintf(`%s%s`, createdFilename, fileExtension)
 	filePath := filepath.Join(teamID, rootID, fullFilename)
 
-	fileSize, appErr := a.filesBackend.WriteFile(reader, filePath)
+	if err := a.checkTeamStorageQuota(teamID, 0); err != nil {
+		return "", err
+	}
+
+	bufferedReader := bufio.NewReaderSize(reader, sniffLen)
+	mimeType := detectMimeType(bufferedReader, fileExtension)
+
+	fileSize, appErr := a.filesBackend.WriteFile(bufferedReader, filePath)
 	if appErr != nil {
 		return "", fmt.Errorf("unable to store the file in the files storage: %w", appErr)
 	}
 
+	// the size of the file is only known once it's written
+	if err := a.checkTeamStorageQuota(teamID, fileSize); err != nil {
+		if rmErr := a.filesBackend.RemoveFile(filePath); rmErr != nil {
+			a.logger.Error("cannot remove a file exceeding the team quota", mlog.String("path", filePath), mlog.Err(rmErr))
+		}
+		return "", err
+	}
+
 	now := utils.GetMillis()
 
 	fileInfo := &mmModel.FileInfo{
@@ -44,13 +72,13 @@ func (a *App) SaveFile(reader io.Reader, teamID, rootID, filename string) (strin
 		CreateAt:        now,
 		UpdateAt:        now,
 		DeleteAt:        0,
-		Path:            emptyString,
+		Path:            filePath,
 		ThumbnailPath:   emptyString,
 		PreviewPath:     emptyString,
 		Name:            filename,
 		Extension:       fileExtension,
 		Size:            fileSize,
-		MimeType:        emptyString,
+		MimeType:        mimeType,
 		Width:           0,
 		Height:          0,
 		HasPreviewImage: false,
@@ -58,14 +86,42 @@ func (a *App) SaveFile(reader io.Reader, teamID, rootID, filename string) (strin
 		Content:         "",
 		RemoteId:        nil,
 	}
-	err := a.store.SaveFileInfo(fileInfo)
+	fileScanner, err := a.fileScanner()
+	if err != nil {
+		return "", err
+	}
+	// the scanned files are only decoded once their scan finds them clean,
+	// and their thumbnails generated then
+	if fileScanner == nil && hasFileImages(mimeType) {
+		a.generateFileImagesOrWarn(fileInfo)
+	}
+
+	err = a.store.SaveFileInfo(fileInfo)
 	if err != nil {
 		return "", err
 	}
 
+	if err := a.quarantineFile(teamID, rootID, fullFilename, userID); err != nil {
+		return "", fmt.Errorf("cannot quarantine the file: %w", err)
+	}
+
 	return fullFilename, nil
 }
 
+// detectMimeType returns the MIME type of a file from its first bytes, or
+// from its extension if they don't match a known type.
+func detectMimeType(reader *bufio.Reader, fileExtension string) string {
+	// a file shorter than sniffLen is sniffed on what could be read
+	head, _ := reader.Peek(sniffLen)
+	mimeType := http.DetectContentType(head)
+	if mimeType == octetStreamMimeType {
+		if extensionType := mime.TypeByExtension(fileExtension); extensionType != "" {
+			return extensionType
+		}
+	}
+	return mimeType
+}
+
 func (a *App) GetFileInfo(filename string) (*mmModel.FileInfo, error) {
 	if len(filename) == 0 {
 		return nil, errEmptyFilename
@@ -84,7 +140,13 @@ func (a *App) GetFileInfo(filename string) (*mmModel.FileInfo, error) {
 	return fileInfo, nil
 }
 
+// GetFileReader returns a reader of a file of a board, or an error if its
+// scan doesn't allow to download it.
 func (a *App) GetFileReader(teamID, rootID, filename string) (filestore.ReadCloseSeeker, error) {
+	if err := a.CheckFileScan(rootID, filename); err != nil {
+		return nil, err
+	}
+
 	filePath := filepath.Join(teamID, rootID, filename)
 	exists, err := a.filesBackend.FileExists(filePath)
 	if err != nil {
@@ -120,3 +182,22 @@ func (a *App) GetFileReader(teamID, rootID, filename string) (filestore.ReadClos
 
 	return reader, nil
 }
+
+// GetFileImageReader returns a reader of the thumbnail or the preview of an
+// image file, or nil if the file has none.
+func (a *App) GetFileImageReader(fileInfo *mmModel.FileInfo, size string) (filestore.ReadCloseSeeker, error) {
+	var path string
+	switch size {
+	case FileSizeThumbnail:
+		path = fileInfo.ThumbnailPath
+	case FileSizePreview:
+		path = fileInfo.PreviewPath
+	default:
+		return nil, ErrInvalidFileSize
+	}
+
+	if !fileInfo.HasPreviewImage || path == "" || path == emptyString {
+		return nil, nil
+	}
+	return a.filesBackend.Reader(path)
+}
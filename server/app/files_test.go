@@ -1,7 +1,10 @@
 package app
 
 import (
+	"bytes"
 	"errors"
+	"image"
+	"image/jpeg"
 	"io"
 	"os"
 	"path/filepath"
@@ -11,6 +14,8 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/mattermost/focalboard/server/model"
+
 	mmModel "github.com/mattermost/mattermost-server/v6/model"
 	"github.com/mattermost/mattermost-server/v6/plugin/plugintest/mock"
 	"github.com/mattermost/mattermost-server/v6/shared/filestore"
@@ -32,6 +37,7 @@ func TestGetFileReader(t *testing.T) {
 	testFilePath := filepath.Join("1", "test-board-id", "temp-file-name")
 
 	th, _ := SetupTestHelper(t)
+	th.Store.EXPECT().GetFileScan(testBoardID, testFileName).Return(nil, model.NewErrNotFound("file scan")).AnyTimes()
 	mockedReadCloseSeek := &mocks.ReadCloseSeeker{}
 	t.Run("should get file reader from filestore successfully", func(t *testing.T) {
 		mockedFileBackend := &mocks.FileBackend{}
@@ -187,6 +193,8 @@ func TestGetFileReader(t *testing.T) {
 func TestSaveFile(t *testing.T) {
 	th, _ := SetupTestHelper(t)
 	mockedReadCloseSeek := &mocks.ReadCloseSeeker{}
+	// the first bytes of the files are read to detect their type
+	mockedReadCloseSeek.On("Read", mock.Anything).Return(0, io.EOF)
 	t.Run("should save file to file store using file backend", func(t *testing.T) {
 		fileName := "temp-file-name.txt"
 		mockedFileBackend := &mocks.FileBackend{}
@@ -205,8 +213,8 @@ func TestSaveFile(t *testing.T) {
 			return nil
 		}
 
-		mockedFileBackend.On("WriteFile", mockedReadCloseSeek, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
-		actual, err := th.App.SaveFile(mockedReadCloseSeek, "1", testBoardID, fileName)
+		mockedFileBackend.On("WriteFile", mock.Anything, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
+		actual, err := th.App.SaveFile(mockedReadCloseSeek, "1", testBoardID, fileName, "user-id")
 		assert.Equal(t, fileName, actual)
 		assert.Nil(t, err)
 	})
@@ -229,12 +237,76 @@ func TestSaveFile(t *testing.T) {
 			return nil
 		}
 
-		mockedFileBackend.On("WriteFile", mockedReadCloseSeek, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
-		actual, err := th.App.SaveFile(mockedReadCloseSeek, "1", "test-board-id", fileName)
+		mockedFileBackend.On("WriteFile", mock.Anything, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
+		actual, err := th.App.SaveFile(mockedReadCloseSeek, "1", "test-board-id", fileName, "user-id")
 		assert.Nil(t, err)
 		assert.NotNil(t, actual)
 	})
 
+	t.Run("should detect the type of images and generate their thumbnail and preview", func(t *testing.T) {
+		content := testPNGImage(t, 400, 200)
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+
+		written := map[string][]byte{}
+		writeFileFunc := func(reader io.Reader, path string) int64 {
+			data, err := io.ReadAll(reader)
+			assert.NoError(t, err)
+			written[path] = data
+			return int64(len(data))
+		}
+		writeFileErrorFunc := func(reader io.Reader, filePath string) error {
+			return nil
+		}
+		mockedFileBackend.On("WriteFile", mock.Anything, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
+		mockedFileBackend.On("Reader", mock.Anything).Return(func(path string) filestore.ReadCloseSeeker {
+			return nopReadCloseSeeker{bytes.NewReader(written[path])}
+		}, nil)
+
+		var savedFileInfo *mmModel.FileInfo
+		th.Store.EXPECT().SaveFileInfo(gomock.Any()).DoAndReturn(func(fileInfo *mmModel.FileInfo) error {
+			savedFileInfo = fileInfo
+			return nil
+		})
+
+		actual, err := th.App.SaveFile(bytes.NewReader(content), "1", testBoardID, "image.bin", "user-id")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual)
+
+		assert.Equal(t, "image/png", savedFileInfo.MimeType)
+		assert.Equal(t, 400, savedFileInfo.Width)
+		assert.Equal(t, 200, savedFileInfo.Height)
+		assert.True(t, savedFileInfo.HasPreviewImage)
+		assert.Equal(t, content, written[savedFileInfo.Path])
+
+		thumbnail, err := jpeg.Decode(bytes.NewReader(written[savedFileInfo.ThumbnailPath]))
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 120, 60), thumbnail.Bounds())
+
+		preview, err := jpeg.Decode(bytes.NewReader(written[savedFileInfo.PreviewPath]))
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 400, 200), preview.Bounds())
+	})
+
+	t.Run("should detect the type of other files without generating thumbnails", func(t *testing.T) {
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+		mockedFileBackend.On("WriteFile", mock.Anything, mock.Anything).Return(int64(20), nil)
+
+		var savedFileInfo *mmModel.FileInfo
+		th.Store.EXPECT().SaveFileInfo(gomock.Any()).DoAndReturn(func(fileInfo *mmModel.FileInfo) error {
+			savedFileInfo = fileInfo
+			return nil
+		})
+
+		_, err := th.App.SaveFile(strings.NewReader("<html><body>page</body></html>"), "1", testBoardID, "page.jpg", "user-id")
+		assert.NoError(t, err)
+		assert.Equal(t, "text/html; charset=utf-8", savedFileInfo.MimeType)
+		assert.False(t, savedFileInfo.HasPreviewImage)
+		assert.Zero(t, savedFileInfo.Width)
+		mockedFileBackend.AssertNotCalled(t, "Reader", mock.Anything)
+	})
+
 	t.Run("should return error when fileBackend.WriteFile returns error", func(t *testing.T) {
 		fileName := "temp-file-name.jpeg"
 		mockedFileBackend := &mocks.FileBackend{}
@@ -253,8 +325,8 @@ func TestSaveFile(t *testing.T) {
 			return mockedError
 		}
 
-		mockedFileBackend.On("WriteFile", mockedReadCloseSeek, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
-		actual, err := th.App.SaveFile(mockedReadCloseSeek, "1", "test-board-id", fileName)
+		mockedFileBackend.On("WriteFile", mock.Anything, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
+		actual, err := th.App.SaveFile(mockedReadCloseSeek, "1", "test-board-id", fileName, "user-id")
 		assert.Equal(t, "", actual)
 		assert.Equal(t, "unable to store the file in the files storage: Mocked File backend error", err.Error())
 	})
@@ -304,3 +376,42 @@ func TestGetFileInfo(t *testing.T) {
 		assert.Nil(t, fetchedFileInfo)
 	})
 }
+
+func TestGetFileImageReader(t *testing.T) {
+	th, _ := SetupTestHelper(t)
+
+	fileInfo := &mmModel.FileInfo{
+		Path:            "1/test-board-id/7file.png",
+		ThumbnailPath:   "1/test-board-id/7file_thumb.jpg",
+		PreviewPath:     "1/test-board-id/7file_preview.jpg",
+		HasPreviewImage: true,
+	}
+
+	t.Run("should return the thumbnail and the preview of images", func(t *testing.T) {
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+		mockedReadCloseSeek := &mocks.ReadCloseSeeker{}
+		mockedFileBackend.On("Reader", "1/test-board-id/7file_thumb.jpg").Return(mockedReadCloseSeek, nil)
+		mockedFileBackend.On("Reader", "1/test-board-id/7file_preview.jpg").Return(mockedReadCloseSeek, nil)
+
+		reader, err := th.App.GetFileImageReader(fileInfo, FileSizeThumbnail)
+		assert.NoError(t, err)
+		assert.Equal(t, mockedReadCloseSeek, reader)
+
+		reader, err = th.App.GetFileImageReader(fileInfo, FileSizePreview)
+		assert.NoError(t, err)
+		assert.Equal(t, mockedReadCloseSeek, reader)
+	})
+
+	t.Run("should return nil for files without thumbnail", func(t *testing.T) {
+		reader, err := th.App.GetFileImageReader(&mmModel.FileInfo{ThumbnailPath: emptyString}, FileSizeThumbnail)
+		assert.NoError(t, err)
+		assert.Nil(t, reader)
+	})
+
+	t.Run("should return error for invalid sizes", func(t *testing.T) {
+		reader, err := th.App.GetFileImageReader(fileInfo, "huge")
+		assert.ErrorIs(t, err, ErrInvalidFileSize)
+		assert.Nil(t, reader)
+	})
+}
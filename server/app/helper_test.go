@@ -33,9 +33,9 @@ func SetupTestHelper(t *testing.T) (*TestHelper, func()) {
 	auth := auth.New(&cfg, store, nil)
 	logger := mlog.CreateConsoleTestLogger(false, mlog.LvlDebug)
 	sessionToken := "TESTTOKEN"
-	wsserver := ws.NewServer(auth, sessionToken, false, logger, store)
-	webhook := webhook.NewClient(&cfg, logger)
 	metricsService := metrics.NewMetrics(metrics.InstanceInfo{})
+	wsserver := ws.NewServer(auth, sessionToken, false, logger, store, metricsService)
+	webhook := webhook.NewClient(&cfg, logger)
 
 	appServices := Services{
 		Auth:             auth,
@@ -39,7 +39,10 @@ func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 	peek, err := br.Peek(len(legacyFileBegin))
 	if err == nil && string(peek) == legacyFileBegin {
 		a.logger.Debug("importing legacy archive")
-		_, errImport := a.ImportBoardJSONL(br, opt)
+		boardID, errImport := a.ImportBoardJSONL(br, opt)
+		if errImport == nil && opt.BoardImported != nil {
+			errImport = opt.BoardImported(boardID, nil)
+		}
 
 		go func() {
 			if err := a.UpdateCardLimitTimestamp(); err != nil {
@@ -56,14 +59,18 @@ func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 	a.logger.Debug("importing archive")
 	zr := zipstream.NewReader(br)
 
-	boardMap := make(map[string]string) // maps old board ids to new
+	boardMap := make(map[string]string)                            // maps old board ids to new
+	templateInfoMap := make(map[string]*model.TemplateArchiveInfo) // maps old board ids to template metadata
+	for dir, boardID := range opt.ImportedBoards {
+		boardMap[dir] = boardID
+	}
 
 	for {
 		hdr, err := zr.Next()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				a.logger.Debug("import archive - done", mlog.Int("boards_imported", len(boardMap)))
-				return nil
+				return a.notifyBoardsImported(boardMap, templateInfoMap, opt)
 			}
 			return err
 		}
@@ -81,11 +88,26 @@ func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 				return model.NewErrUnsupportedArchiveVersion(ver, archiveVersion)
 			}
 		case "board.jsonl":
-			boardID, err := a.ImportBoardJSONL(zr, opt)
+			if _, ok := opt.ImportedBoards[dir]; ok {
+				a.logger.Debug("skipping board already imported", mlog.String("dir", dir))
+				continue
+			}
+			boardID, err := a.importBoardJSONL(dir, zr, opt)
 			if err != nil {
 				return fmt.Errorf("cannot import board %s: %w", dir, err)
 			}
 			boardMap[dir] = boardID
+			if opt.BoardProcessed != nil {
+				if err := opt.BoardProcessed(dir, boardID); err != nil {
+					return fmt.Errorf("cannot complete import of board %s: %w", dir, err)
+				}
+			}
+		case "template.json":
+			info, errInfo := parseTemplateInfoFile(zr)
+			if errInfo != nil {
+				return fmt.Errorf("cannot import template info %s: %w", dir, errInfo)
+			}
+			templateInfoMap[dir] = info
 		default:
 			// import file/image;  dir is the old board id
 			boardID, ok := boardMap[dir]
@@ -96,12 +118,18 @@ func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 				)
 				continue
 			}
+			if opt.DryRun {
+				continue
+			}
 			// save file with original filename so it matches name in image block.
 			filePath := filepath.Join(opt.TeamID, boardID, filename)
 			_, err := a.filesBackend.WriteFile(zr, filePath)
 			if err != nil {
 				return fmt.Errorf("cannot import file %s for board %s: %w", filename, dir, err)
 			}
+			if err := a.quarantineFile(opt.TeamID, boardID, filename, opt.ModifiedBy); err != nil {
+				return fmt.Errorf("cannot quarantine file %s for board %s: %w", filename, dir, err)
+			}
 		}
 
 		a.logger.Trace("import archive file",
@@ -123,6 +151,13 @@ func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 // ImportBoardJSONL imports a JSONL file containing blocks for one board. The resulting
 // board id is returned.
 func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (string, error) {
+	return a.importBoardJSONL("", r, opt)
+}
+
+// importBoardJSONL imports the JSONL file of a board found in the given
+// directory of an archive. The board, its blocks and the membership of the
+// importing user are committed in a single transaction.
+func (a *App) importBoardJSONL(dir string, r io.Reader, opt model.ImportArchiveOptions) (string, error) {
 	// TODO: Stream this once `model.GenerateBlockIDs` can take a stream of blocks.
 	//       We don't want to load the whole file in memory, even though it's a single board.
 	boardsAndBlocks := &model.BoardsAndBlocks{
@@ -137,6 +172,7 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (str
 	}
 	now := utils.GetMillis()
 	var boardID string
+	notImported := map[string]int{}
 
 	lineNum := 1
 	firstLine := true
@@ -196,8 +232,19 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (str
 					block.UpdateAt = now
 					block.BoardID = boardID
 					boardsAndBlocks.Blocks = append(boardsAndBlocks.Blocks, block)
+				case "block_tombstone", "board_member", "board_history", "block_history":
+					// written by the export options; they describe the
+					// exported boards, not the new ones created by an import.
+					if notImported[archiveLine.Type] == 0 && opt.LineSkipped != nil {
+						opt.LineSkipped(dir, model.NewErrNotImportedArchiveLineType(lineNum, archiveLine.Type))
+					}
+					notImported[archiveLine.Type]++
 				default:
-					return "", model.NewErrUnsupportedArchiveLineType(lineNum, archiveLine.Type)
+					lineErr := model.NewErrUnsupportedArchiveLineType(lineNum, archiveLine.Type)
+					if opt.LineSkipped == nil {
+						return "", lineErr
+					}
+					opt.LineSkipped(dir, lineErr)
 				}
 				firstLine = false
 			}
@@ -212,6 +259,14 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (str
 		lineNum++
 	}
 
+	for lineType, count := range notImported {
+		a.logger.Warn("archive lines not imported",
+			mlog.String("dir", dir),
+			mlog.String("type", lineType),
+			mlog.Int("count", count),
+		)
+	}
+
 	a.fixBoardsandBlocks(boardsAndBlocks, opt)
 
 	var err error
@@ -220,21 +275,18 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (str
 		return "", fmt.Errorf("error generating archive block IDs: %w", err)
 	}
 
-	boardsAndBlocks, err = a.CreateBoardsAndBlocks(boardsAndBlocks, opt.ModifiedBy, false)
-	if err != nil {
-		return "", fmt.Errorf("error inserting archive blocks: %w", err)
+	if opt.DryRun {
+		return a.validateArchiveBoard(boardsAndBlocks)
 	}
 
-	// add user to all the new boards.
-	for _, board := range boardsAndBlocks.Boards {
-		boardMember := &model.BoardMember{
-			BoardID:     board.ID,
-			UserID:      opt.ModifiedBy,
-			SchemeAdmin: true,
-		}
-		if _, err := a.AddMemberToBoard(boardMember); err != nil {
-			return "", fmt.Errorf("cannot add member to board: %w", err)
-		}
+	// the importing user is added as an admin of the new boards
+	if opt.ImportJob != nil {
+		boardsAndBlocks, err = a.createImportedBoardsAndBlocks(dir, boardsAndBlocks, opt)
+	} else {
+		boardsAndBlocks, err = a.CreateBoardsAndBlocks(boardsAndBlocks, opt.ModifiedBy, true)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error inserting archive blocks: %w", err)
 	}
 
 	// find new board id
@@ -244,6 +296,40 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (str
 	return "", fmt.Errorf("missing board in archive: %w", model.ErrInvalidBoardBlock)
 }
 
+// createImportedBoardsAndBlocks creates the board and blocks imported from a
+// directory of an archive by an import job. The board is added to the
+// imported boards of the job in the same transaction.
+func (a *App) createImportedBoardsAndBlocks(dir string, boardsAndBlocks *model.BoardsAndBlocks, opt model.ImportArchiveOptions) (*model.BoardsAndBlocks, error) {
+	if len(boardsAndBlocks.Boards) == 0 {
+		return nil, fmt.Errorf("missing board in archive: %w", model.ErrInvalidBoardBlock)
+	}
+
+	job := opt.ImportJob
+	job.ImportedBoards[dir] = boardsAndBlocks.Boards[0].ID
+	newBab, members, err := a.store.CreateImportedBoardsAndBlocks(boardsAndBlocks, opt.ModifiedBy, job)
+	if err != nil {
+		delete(job.ImportedBoards, dir)
+		return nil, err
+	}
+
+	a.notifyBoardsAndBlocksCreated(newBab, members, opt.ModifiedBy)
+	return newBab, nil
+}
+
+// validateArchiveBoard checks the board and blocks read from an archive in a
+// dry run, and returns the generated board ID.
+func (a *App) validateArchiveBoard(boardsAndBlocks *model.BoardsAndBlocks) (string, error) {
+	if err := boardsAndBlocks.IsValid(); err != nil {
+		return "", fmt.Errorf("invalid archive board: %w", err)
+	}
+	for _, board := range boardsAndBlocks.Boards {
+		if err := board.IsValid(); err != nil {
+			return "", fmt.Errorf("invalid archive board: %w", err)
+		}
+	}
+	return boardsAndBlocks.Boards[0].ID, nil
+}
+
 // fixBoardsandBlocks allows the caller of `ImportArchive` to modify or filters boards and blocks being
 // imported via callbacks.
 func (a *App) fixBoardsandBlocks(boardsAndBlocks *model.BoardsAndBlocks, opt model.ImportArchiveOptions) {
@@ -395,6 +481,34 @@ func arrayMapsValue(m map[string]interface{}, key string) ([]map[string]interfac
 	return arr, true
 }
 
+// notifyBoardsImported calls the BoardImported callback of the import options,
+// if any, for each one of the imported boards.
+func (a *App) notifyBoardsImported(boardMap map[string]string, templateInfoMap map[string]*model.TemplateArchiveInfo, opt model.ImportArchiveOptions) error {
+	if opt.BoardImported == nil {
+		return nil
+	}
+
+	for dir, boardID := range boardMap {
+		if err := opt.BoardImported(boardID, templateInfoMap[dir]); err != nil {
+			return fmt.Errorf("cannot complete import of board %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+func parseTemplateInfoFile(r io.Reader) (*model.TemplateArchiveInfo, error) {
+	file, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read template.json: %w", err)
+	}
+
+	var info model.TemplateArchiveInfo
+	if err := json.Unmarshal(file, &info); err != nil {
+		return nil, fmt.Errorf("cannot parse template.json: %w", err)
+	}
+	return &info, nil
+}
+
 func parseVersionFile(r io.Reader) (int, error) {
 	file, err := io.ReadAll(r)
 	if err != nil {
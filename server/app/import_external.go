@@ -0,0 +1,118 @@
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/importer"
+)
+
+// ImportExternal imports the export of another tool, such as Trello, in a
+// team. The export is converted to an archive, which is imported like any
+// other, and the returned report lists what could not be mapped.
+func (a *App) ImportExternal(format string, r io.Reader, opt model.ImportArchiveOptions) (*model.ImportReport, error) {
+	resolver, err := a.externalUserResolver(opt.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := importer.Convert(format, r, importer.Options{ResolveUser: resolver})
+	if err != nil {
+		return nil, err
+	}
+
+	var archive bytes.Buffer
+	if err := a.writeConvertedArchive(&archive, result); err != nil {
+		return nil, fmt.Errorf("cannot write the converted %s export: %w", format, err)
+	}
+
+	report := result.Report
+	boardImported := opt.BoardImported
+	opt.BoardImported = func(boardID string, info *model.TemplateArchiveInfo) error {
+		report.BoardIDs = append(report.BoardIDs, boardID)
+		if boardImported != nil {
+			return boardImported(boardID, info)
+		}
+		return nil
+	}
+
+	if err := a.ImportArchive(&archive, opt); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// externalUserResolver matches the users of an export with the users of a
+// team, by email, username or full name.
+func (a *App) externalUserResolver(teamID string) (importer.UserResolver, error) {
+	users, err := a.store.GetUsersByTeam(teamID)
+	if err != nil && !model.IsErrNotFound(err) {
+		return nil, err
+	}
+
+	byEmail := map[string]string{}
+	byUsername := map[string]string{}
+	byName := map[string]string{}
+	for _, user := range users {
+		if user.Email != "" {
+			byEmail[strings.ToLower(user.Email)] = user.ID
+		}
+		byUsername[strings.ToLower(user.Username)] = user.ID
+		if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+			byName[strings.ToLower(name)] = user.ID
+		}
+		if user.Nickname != "" {
+			byName[strings.ToLower(user.Nickname)] = user.ID
+		}
+	}
+
+	return func(user importer.ExternalUser) string {
+		if userID, ok := byEmail[strings.ToLower(user.Email)]; ok && user.Email != "" {
+			return userID
+		}
+		if userID, ok := byUsername[strings.ToLower(user.Username)]; ok && user.Username != "" {
+			return userID
+		}
+		if userID, ok := byName[strings.ToLower(user.FullName)]; ok && user.FullName != "" {
+			return userID
+		}
+		// standalone users have no full name, but it often matches their
+		// username
+		if userID, ok := byUsername[strings.ToLower(user.FullName)]; ok && user.FullName != "" {
+			return userID
+		}
+		return ""
+	}, nil
+}
+
+// writeConvertedArchive writes the boards and blocks converted from an
+// export to an archive.
+func (a *App) writeConvertedArchive(w io.Writer, result *importer.Result) error {
+	zw := zip.NewWriter(w)
+	if err := a.writeArchiveVersion(zw); err != nil {
+		return err
+	}
+
+	for _, board := range result.Boards {
+		bw, err := zw.Create(board.ID + "/board.jsonl")
+		if err != nil {
+			return err
+		}
+		if err := a.writeArchiveBoardLine(bw, *board); err != nil {
+			return err
+		}
+		for _, block := range result.Blocks {
+			if block.BoardID != board.ID {
+				continue
+			}
+			if err := a.writeArchiveBlockLine(bw, block); err != nil {
+				return err
+			}
+		}
+	}
+	return zw.Close()
+}
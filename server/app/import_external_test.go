@@ -0,0 +1,43 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/importer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalUserResolver(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	users := []*model.User{
+		{ID: "user-1", Username: "jane", Email: "Jane@example.com", FirstName: "Jane", LastName: "Doe"},
+		{ID: "user-2", Username: "john", Nickname: "Johnny"},
+		{ID: "user-3", Username: "bob"},
+	}
+	th.Store.EXPECT().GetUsersByTeam("team-id").Return(users, nil)
+
+	resolve, err := th.App.externalUserResolver("team-id")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		user     importer.ExternalUser
+		expected string
+	}{
+		{"by email", importer.ExternalUser{Email: "jane@EXAMPLE.com", Username: "john"}, "user-1"},
+		{"by username", importer.ExternalUser{Username: "John"}, "user-2"},
+		{"by full name", importer.ExternalUser{FullName: "jane doe"}, "user-1"},
+		{"by nickname", importer.ExternalUser{FullName: "Johnny"}, "user-2"},
+		{"full name matching a username", importer.ExternalUser{FullName: "bob"}, "user-3"},
+		{"no match", importer.ExternalUser{ID: "42", Username: "alice", FullName: "Alice"}, ""},
+		{"empty user", importer.ExternalUser{}, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, resolve(tc.user))
+		})
+	}
+}
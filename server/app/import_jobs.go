@@ -0,0 +1,243 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+const (
+	importJobsDir = "imports"
+
+	// importJobStaleTimeout is the time after which a pending or running
+	// job with no progress is considered interrupted, e.g. by a restart of
+	// the server, and can be resumed.
+	importJobStaleTimeout = 10 * time.Minute
+)
+
+// countingReader counts the bytes read from an archive to report the
+// progress of an import.
+type countingReader struct {
+	r     io.Reader
+	count int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	atomic.AddInt64(&cr.count, int64(n))
+	return n, err
+}
+
+func (cr *countingReader) bytesRead() int64 {
+	return atomic.LoadInt64(&cr.count)
+}
+
+// CreateImportJob saves an archive to the files backend and imports it in
+// the background. The TeamID, CreatedBy, Filename and DryRun fields of the
+// job must be set.
+func (a *App) CreateImportJob(job *model.ImportJob, r io.Reader) (*model.ImportJob, error) {
+	job.ID = utils.NewID(utils.IDTypeNone)
+	job.Status = model.ImportJobStatusPending
+	job.FilePath = filepath.Join(job.TeamID, importJobsDir, job.ID)
+
+	size, err := a.filesBackend.WriteFile(r, job.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot save the archive of import job %s: %w", job.ID, err)
+	}
+	job.Size = size
+
+	newJob, err := a.store.InsertImportJob(job)
+	if err != nil {
+		if rmErr := a.filesBackend.RemoveFile(job.FilePath); rmErr != nil {
+			a.logger.Warn("cannot remove the archive of an import job", mlog.String("path", job.FilePath), mlog.Err(rmErr))
+		}
+		return nil, err
+	}
+
+	a.startImportJob(newJob.ID)
+	return newJob, nil
+}
+
+// GetImportJob returns an import job.
+func (a *App) GetImportJob(jobID string) (*model.ImportJob, error) {
+	return a.store.GetImportJob(jobID)
+}
+
+// GetImportJobsForUser returns the import jobs started by a user in a team.
+func (a *App) GetImportJobsForUser(teamID, userID string) ([]*model.ImportJob, error) {
+	return a.store.GetImportJobsForUser(teamID, userID)
+}
+
+// ResumeImportJob restarts a failed or interrupted import job. The boards
+// already imported by the job are skipped.
+func (a *App) ResumeImportJob(jobID string) (*model.ImportJob, error) {
+	job, err := a.store.GetImportJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !a.isImportJobResumable(job) {
+		return nil, model.ErrImportJobNotResumable
+	}
+
+	job.Status = model.ImportJobStatusPending
+	job.Error = ""
+	if job, err = a.store.UpdateImportJob(job); err != nil {
+		return nil, err
+	}
+
+	if !a.startImportJob(job.ID) {
+		return nil, model.ErrImportJobNotResumable
+	}
+	return job, nil
+}
+
+func (a *App) isImportJobResumable(job *model.ImportJob) bool {
+	switch job.Status {
+	case model.ImportJobStatusFailed:
+		return true
+	case model.ImportJobStatusCompleted:
+		return false
+	}
+
+	a.importJobsMux.Lock()
+	running := a.runningImportJobs[job.ID]
+	a.importJobsMux.Unlock()
+
+	stale := utils.GetMillis()-job.UpdateAt > importJobStaleTimeout.Milliseconds()
+	return !running && stale
+}
+
+// startImportJob runs an import job in the background, unless it is already
+// running on this server.
+func (a *App) startImportJob(jobID string) bool {
+	a.importJobsMux.Lock()
+	defer a.importJobsMux.Unlock()
+
+	if a.runningImportJobs[jobID] {
+		return false
+	}
+	a.runningImportJobs[jobID] = true
+
+	go func() {
+		defer func() {
+			a.importJobsMux.Lock()
+			delete(a.runningImportJobs, jobID)
+			a.importJobsMux.Unlock()
+		}()
+		a.runImportJob(jobID)
+	}()
+	return true
+}
+
+func (a *App) runImportJob(jobID string) {
+	job, err := a.store.GetImportJob(jobID)
+	if err != nil {
+		a.logger.Error("cannot get import job", mlog.String("job_id", jobID), mlog.Err(err))
+		return
+	}
+
+	a.logger.Debug("running import job",
+		mlog.String("job_id", job.ID),
+		mlog.String("team_id", job.TeamID),
+		mlog.Bool("dry_run", job.DryRun),
+	)
+
+	// boards already imported by a previous run are skipped
+	job.Status = model.ImportJobStatusRunning
+	job.BoardsProcessed = len(job.ImportedBoards)
+	a.saveImportJob(job)
+
+	reader, err := a.filesBackend.Reader(job.FilePath)
+	if err != nil {
+		a.finishImportJob(job, fmt.Errorf("cannot read the archive: %w", err))
+		return
+	}
+	defer reader.Close()
+
+	cr := &countingReader{r: reader}
+	importedBoards := make(map[string]string, len(job.ImportedBoards))
+	for dir, boardID := range job.ImportedBoards {
+		importedBoards[dir] = boardID
+	}
+
+	opt := model.ImportArchiveOptions{
+		TeamID:         job.TeamID,
+		ModifiedBy:     job.CreatedBy,
+		DryRun:         job.DryRun,
+		ImportedBoards: importedBoards,
+		BoardProcessed: func(dir, boardID string) error {
+			job.BoardsProcessed++
+			job.SetBytesRead(cr.bytesRead())
+			return a.updateImportJob(job)
+		},
+		LineSkipped: func(dir string, lineErr model.ErrUnsupportedArchiveLineType) {
+			addSkippedLine(job, model.ImportSkippedLine{
+				Board:  dir,
+				Line:   lineErr.Line(),
+				Type:   lineErr.Type(),
+				Reason: lineErr.Error(),
+			})
+		},
+	}
+
+	if !job.DryRun {
+		// the imported boards are saved along with the boards themselves
+		opt.ImportJob = job
+	}
+	a.finishImportJob(job, a.ImportArchive(cr, opt))
+}
+
+// finishImportJob saves the outcome of an import job. The archive is kept
+// if the job failed, so that it can be resumed.
+func (a *App) finishImportJob(job *model.ImportJob, importErr error) {
+	if importErr != nil {
+		a.logger.Error("import job failed", mlog.String("job_id", job.ID), mlog.Err(importErr))
+		job.Status = model.ImportJobStatusFailed
+		job.Error = importErr.Error()
+		a.saveImportJob(job)
+		return
+	}
+
+	job.Status = model.ImportJobStatusCompleted
+	job.SetBytesRead(job.Size)
+	a.saveImportJob(job)
+
+	if err := a.filesBackend.RemoveFile(job.FilePath); err != nil {
+		a.logger.Warn("cannot remove the archive of an import job", mlog.String("job_id", job.ID), mlog.Err(err))
+	}
+}
+
+// updateImportJob persists the progress of an import job and notifies the
+// user that started it.
+func (a *App) updateImportJob(job *model.ImportJob) error {
+	if _, err := a.store.UpdateImportJob(job); err != nil {
+		return err
+	}
+	a.wsAdapter.BroadcastImportJobChange(job.TeamID, job)
+	return nil
+}
+
+// saveImportJob is like updateImportJob, but only logs errors.
+func (a *App) saveImportJob(job *model.ImportJob) {
+	if err := a.updateImportJob(job); err != nil {
+		a.logger.Error("cannot update import job", mlog.String("job_id", job.ID), mlog.Err(err))
+	}
+}
+
+// addSkippedLine adds a skipped line to the report of a job, unless it was
+// already reported by a previous run.
+func addSkippedLine(job *model.ImportJob, line model.ImportSkippedLine) {
+	for _, skipped := range job.SkippedLines {
+		if skipped.Board == line.Board && skipped.Line == line.Line {
+			return
+		}
+	}
+	job.SkippedLines = append(job.SkippedLines, line)
+}
@@ -43,10 +43,9 @@ func TestApp_ImportArchive(t *testing.T) {
 			ModifiedBy: "user",
 		}
 
-		th.Store.EXPECT().CreateBoardsAndBlocks(gomock.AssignableToTypeOf(&model.BoardsAndBlocks{}), "user").Return(babs, nil)
+		th.Store.EXPECT().CreateBoardsAndBlocksWithAdmin(gomock.AssignableToTypeOf(&model.BoardsAndBlocks{}), "user").Return(babs, []*model.BoardMember{boardMember}, nil)
 		th.Store.EXPECT().GetMembersForBoard(board.ID).AnyTimes().Return([]*model.BoardMember{boardMember}, nil)
-		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
-		th.Store.EXPECT().GetMemberForBoard(board.ID, "user").Return(boardMember, nil)
+		th.Store.EXPECT().GetDefaultSharedCategory("test-team").Return(nil, model.NewErrNotFound("default category"))
 
 		err := th.App.ImportArchive(r, opts)
 		require.NoError(t, err, "import archive should not fail")
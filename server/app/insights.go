@@ -1,6 +1,8 @@
 package app
 
 import (
+	"fmt"
+
 	"github.com/mattermost/focalboard/server/model"
 	mmModel "github.com/mattermost/mattermost-server/v6/model"
 	"github.com/pkg/errors"
@@ -76,3 +78,54 @@ func getUserBoards(userID string, teamID string, a *App) ([]string, error) {
 	}
 	return boardIDs, nil
 }
+
+// GetBoardChart computes a chart of the board from the history of its
+// cards.
+func (a *App) GetBoardChart(boardID string, opts *model.BoardChartOptions) (*model.BoardChart, error) {
+	if err := opts.IsValid(); err != nil {
+		return nil, err
+	}
+
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateBoardChartProperties(schema, opts); err != nil {
+		return nil, err
+	}
+
+	history, err := a.store.GetCardHistory(board.ID, opts.PropertyIDs(), opts.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.ComputeBoardChart(board.ID, opts, history), nil
+}
+
+// validateBoardChartProperties checks that the chart properties and values
+// exist on the board.
+func validateBoardChartProperties(schema model.PropSchema, opts *model.BoardChartOptions) error {
+	property, ok := schema[opts.PropertyID]
+	if !ok || property.Type != "select" {
+		return fmt.Errorf("%w: %s is not a select property", model.ErrInvalidBoardChartOptions, opts.PropertyID)
+	}
+
+	for _, value := range []string{opts.StartValue, opts.EndValue} {
+		if _, ok := property.Options[value]; value != "" && !ok {
+			return fmt.Errorf("%w: unknown option %s", model.ErrInvalidBoardChartOptions, value)
+		}
+	}
+
+	if opts.DatePropertyID != "" {
+		dateProperty, ok := schema[opts.DatePropertyID]
+		if !ok || dateProperty.Type != "date" {
+			return fmt.Errorf("%w: %s is not a date property", model.ErrInvalidBoardChartOptions, opts.DatePropertyID)
+		}
+	}
+	return nil
+}
@@ -87,3 +87,72 @@ func TestGetTeamAndUserBoardsInsights(t *testing.T) {
 		require.ErrorIs(t, err, insightError{"board-insight-error"})
 	})
 }
+
+func TestGetBoardChart(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{
+		ID: "board-id",
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do"},
+					map[string]interface{}{"id": "done", "value": "Done"},
+				},
+			},
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+		},
+	}
+	opts := func() *model.BoardChartOptions {
+		return &model.BoardChartOptions{
+			Chart:      model.BoardChartThroughput,
+			PropertyID: "status",
+			EndValue:   "done",
+			From:       1659312000000,
+			To:         1659916800000,
+			Interval:   model.BoardChartIntervalDay,
+		}
+	}
+
+	t.Run("computes the chart from the card history", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+		th.Store.EXPECT().GetCardHistory("board-id", []string{"status"}, int64(1659916800000)).Return([]*model.CardHistoryEntry{
+			{CardID: "card-id", UpdateAt: 1659312000001, Values: map[string]string{"status": "todo"}},
+			{CardID: "card-id", UpdateAt: 1659398400001, Values: map[string]string{"status": "done"}},
+		}, nil)
+
+		chart, err := th.App.GetBoardChart("board-id", opts())
+		require.NoError(t, err)
+		require.Len(t, chart.Throughput, 8)
+		require.Equal(t, 1, chart.Throughput[1].Count)
+	})
+
+	t.Run("the property must be a select property", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+
+		chartOpts := opts()
+		chartOpts.PropertyID = "estimate"
+		_, err := th.App.GetBoardChart("board-id", chartOpts)
+		require.ErrorIs(t, err, model.ErrInvalidBoardChartOptions)
+	})
+
+	t.Run("the values must be options of the property", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+
+		chartOpts := opts()
+		chartOpts.EndValue = "unknown"
+		_, err := th.App.GetBoardChart("board-id", chartOpts)
+		require.ErrorIs(t, err, model.ErrInvalidBoardChartOptions)
+	})
+
+	t.Run("invalid options don't hit the store", func(t *testing.T) {
+		chartOpts := opts()
+		chartOpts.Interval = ""
+		_, err := th.App.GetBoardChart("board-id", chartOpts)
+		require.ErrorIs(t, err, model.ErrInvalidBoardChartOptions)
+	})
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// validateCardProperties checks the property values of the changed cards
+// against the card properties of the board. previous holds the current
+// version of the changed cards that already exist. On strict boards an
+// ErrInvalidCardProperties is returned for invalid values, lenient boards
+// accept them.
+func (a *App) validateCardProperties(board *model.Board, changed []model.Block, previous map[string]*model.Block, modifiedByID string) error {
+	if !hasCards(changed) {
+		return nil
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return err
+	}
+
+	var members map[string]bool
+	if schema.NeedsMembers() {
+		boardMembers, err := a.store.GetMembersForBoard(board.ID)
+		if err != nil {
+			return err
+		}
+		members = make(map[string]bool, len(boardMembers))
+		for _, member := range boardMembers {
+			members[member.UserID] = true
+		}
+	}
+
+	validator := model.NewCardValidator(schema, members)
+	var invalid []*model.PropertyValidationError
+	for i := range changed {
+		invalid = append(invalid, validator.Validate(&changed[i], previous[changed[i].ID])...)
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+
+	if board.PropertyValidationMode() == model.PropertyValidationStrict {
+		return model.NewErrInvalidCardProperties(invalid)
+	}
+
+	a.logger.Debug("Invalid card property values accepted",
+		mlog.String("board_id", board.ID),
+		mlog.Int("invalid_count", len(invalid)),
+		mlog.String("user_id", modifiedByID),
+	)
+	return nil
+}
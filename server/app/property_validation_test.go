@@ -0,0 +1,70 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func propertyValidationTestBoard(mode string) *model.Board {
+	return &model.Board{
+		ID: testBoardID,
+		Properties: map[string]interface{}{
+			model.BoardPropertyPropertyValidation: mode,
+		},
+		CardProperties: []map[string]interface{}{
+			{"id": "owner", "name": "Owner", "type": "person", "required": true},
+		},
+	}
+}
+
+func propertyValidationTestCard(owner string) model.Block {
+	return model.Block{
+		ID:      "card-id",
+		BoardID: testBoardID,
+		Type:    model.TypeCard,
+		Fields:  map[string]interface{}{"properties": map[string]interface{}{"owner": owner}},
+	}
+}
+
+func TestValidateCardProperties(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	members := []*model.BoardMember{{BoardID: testBoardID, UserID: "user-id"}}
+
+	t.Run("strict board accepts valid values", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForBoard(testBoardID).Return(members, nil)
+		board := propertyValidationTestBoard(model.PropertyValidationStrict)
+		err := th.App.validateCardProperties(board, []model.Block{propertyValidationTestCard("user-id")}, nil, "user-id")
+		require.NoError(t, err)
+	})
+
+	t.Run("strict board rejects invalid values", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForBoard(testBoardID).Return(members, nil)
+		board := propertyValidationTestBoard(model.PropertyValidationStrict)
+		err := th.App.validateCardProperties(board, []model.Block{propertyValidationTestCard("other-user-id")}, nil, "user-id")
+		require.True(t, model.IsErrInvalidCardProperties(err))
+
+		var invalidErr *model.ErrInvalidCardProperties
+		require.ErrorAs(t, err, &invalidErr)
+		require.Equal(t, []*model.PropertyValidationError{
+			{CardID: "card-id", PropertyID: "owner", Reason: model.PropertyValidationNotBoardMember},
+		}, invalidErr.Errors)
+	})
+
+	t.Run("lenient board accepts invalid values", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForBoard(testBoardID).Return(members, nil)
+		board := propertyValidationTestBoard(model.PropertyValidationLenient)
+		err := th.App.validateCardProperties(board, []model.Block{propertyValidationTestCard("")}, nil, "user-id")
+		require.NoError(t, err)
+	})
+
+	t.Run("blocks that aren't cards are not validated", func(t *testing.T) {
+		board := propertyValidationTestBoard(model.PropertyValidationStrict)
+		view := model.Block{ID: "view-id", BoardID: testBoardID, Type: model.TypeView}
+		err := th.App.validateCardProperties(board, []model.Block{view}, nil, "user-id")
+		require.NoError(t, err)
+	})
+}
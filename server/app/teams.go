@@ -63,3 +63,7 @@ func (a *App) UpsertTeamSignupToken(team model.Team) error {
 func (a *App) GetTeamCount() (int64, error) {
 	return a.store.GetTeamCount()
 }
+
+func (a *App) GetAllTeams() ([]*model.Team, error) {
+	return a.store.GetAllTeams()
+}
@@ -0,0 +1,560 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+var ErrCannotPublishTemplateBoard = errors.New("cannot publish a template board")
+
+// GetTemplates returns the templates of a team's library along with their
+// template boards. Templates whose board has been deleted are skipped.
+func (a *App) GetTemplates(opts model.QueryTemplatesOptions) ([]*model.Template, error) {
+	templates, err := a.store.GetTemplates(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.Template, 0, len(templates))
+	for _, template := range templates {
+		board, err := a.GetBoard(template.BoardID)
+		if err != nil {
+			return nil, err
+		}
+		if board == nil {
+			continue
+		}
+		template.Board = board
+		results = append(results, template)
+	}
+	return results, nil
+}
+
+// GetTemplate returns a template of the library along with its template board.
+func (a *App) GetTemplate(templateID string) (*model.Template, error) {
+	template, err := a.store.GetTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := a.store.GetBoard(template.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	template.Board = board
+	return template, nil
+}
+
+// PublishTemplate publishes a board to the template library of its team. The
+// contents of the board are copied to a new template board. If the board was
+// already published, a new version of the existing template is created and
+// the board holding the previous version is deleted.
+func (a *App) PublishTemplate(boardID, userID string, opts *model.PublishTemplateOptions) (*model.Template, error) {
+	if err := opts.IsValid(); err != nil {
+		return nil, err
+	}
+
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if board.IsTemplate {
+		return nil, ErrCannotPublishTemplateBoard
+	}
+
+	existing, err := a.store.GetTemplateForSourceBoard(board.TeamID, board.ID)
+	if err != nil && !model.IsErrNotFound(err) {
+		return nil, err
+	}
+
+	version := 1
+	title := board.Title
+	if existing != nil {
+		version = existing.Version + 1
+	}
+	if opts.Title != "" {
+		title = opts.Title
+	}
+
+	templateBoard, err := a.createTemplateBoard(board.ID, board.TeamID, userID, title, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing == nil {
+		template := &model.Template{
+			TeamID:        board.TeamID,
+			BoardID:       templateBoard.ID,
+			SourceBoardID: board.ID,
+			Category:      opts.Category,
+			Tags:          opts.Tags,
+			Version:       version,
+			CreatedBy:     userID,
+			ModifiedBy:    userID,
+		}
+		template, err = a.store.InsertTemplate(template)
+		if err != nil {
+			return nil, err
+		}
+		template.Board = templateBoard
+		return template, nil
+	}
+
+	previousBoardID := existing.BoardID
+	existing.BoardID = templateBoard.ID
+	existing.Version = version
+	existing.ModifiedBy = userID
+	if opts.Category != "" {
+		existing.Category = opts.Category
+	}
+	if opts.Tags != nil {
+		existing.Tags = opts.Tags
+	}
+
+	template, err := a.store.UpdateTemplate(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.copyBoardMembers(previousBoardID, templateBoard.ID); err != nil {
+		a.logger.Error("Could not copy members of the previous template version",
+			mlog.String("templateID", template.ID),
+			mlog.Err(err),
+		)
+	}
+	if err := a.DeleteBoard(previousBoardID, userID); err != nil {
+		a.logger.Error("Could not delete the previous template version",
+			mlog.String("templateID", template.ID),
+			mlog.String("boardID", previousBoardID),
+			mlog.Err(err),
+		)
+	}
+
+	template.Board = templateBoard
+	return template, nil
+}
+
+// createTemplateBoard copies a board into a new open template board with the
+// given title and version.
+func (a *App) createTemplateBoard(boardID, teamID, userID, title string, version int) (*model.Board, error) {
+	bab, _, err := a.DuplicateBoard(boardID, userID, teamID, true)
+	if err != nil {
+		return nil, fmt.Errorf("cannot copy board %s to a template: %w", boardID, err)
+	}
+	if len(bab.Boards) == 0 {
+		return nil, fmt.Errorf("cannot copy board %s to a template: %w", boardID, model.ErrInvalidBoardBlock)
+	}
+
+	templateBoard := bab.Boards[0]
+	templateBoard.Title = title
+	templateBoard.Type = model.BoardTypeOpen
+	templateBoard.TemplateVersion = version
+
+	templateBoard, err = a.store.InsertBoard(templateBoard, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBoardChange(templateBoard.TeamID, templateBoard)
+		return nil
+	})
+	return templateBoard, nil
+}
+
+// copyBoardMembers adds the members of a board to another board, keeping
+// their roles. Members that already exist in the destination are skipped.
+func (a *App) copyBoardMembers(sourceBoardID, destinationBoardID string) error {
+	members, err := a.store.GetMembersForBoard(sourceBoardID)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		if member.Synthetic {
+			continue
+		}
+		if _, err := a.store.GetMemberForBoard(destinationBoardID, member.UserID); err == nil {
+			continue
+		}
+
+		newMember := *member
+		newMember.BoardID = destinationBoardID
+		if _, err := a.AddMemberToBoard(&newMember); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PatchTemplate updates the metadata of a template.
+func (a *App) PatchTemplate(templateID string, patch *model.TemplatePatch, userID string) (*model.Template, error) {
+	template, err := a.store.GetTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	template = patch.Patch(template)
+	template.ModifiedBy = userID
+	return a.store.UpdateTemplate(template)
+}
+
+// UnpublishTemplate removes a template from the library and deletes its
+// template board. Boards created from the template are not modified.
+func (a *App) UnpublishTemplate(templateID, userID string) error {
+	template, err := a.store.GetTemplate(templateID)
+	if err != nil {
+		return err
+	}
+
+	if err := a.store.DeleteTemplate(templateID); err != nil {
+		return err
+	}
+	return a.DeleteBoard(template.BoardID, userID)
+}
+
+// linkBoardToTemplate records that a board has been created from a template
+// board, if the template board belongs to the template library.
+func (a *App) linkBoardToTemplate(templateBoardID, boardID string) error {
+	template, err := a.store.GetTemplateForBoard(templateBoardID)
+	if model.IsErrNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return a.store.SaveTemplateDerivedBoard(&model.TemplateDerivedBoard{
+		BoardID:         boardID,
+		TemplateID:      template.ID,
+		TemplateVersion: template.Version,
+	})
+}
+
+// PropagateTemplate pushes the card properties and views of the current
+// version of a template to the boards created from previous versions of it.
+// Changes are additive: properties, property options and views that were
+// removed from the template, or added to a board, are kept. The boards for
+// which canUpdate returns false are skipped and reported.
+func (a *App) PropagateTemplate(templateID, userID string, canUpdate func(boardID string) bool) (*model.TemplatePropagationReport, error) {
+	template, err := a.GetTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	templateViews, err := a.store.GetBlocksWithType(template.BoardID, model.TypeView)
+	if err != nil {
+		return nil, err
+	}
+
+	derivedBoards, err := a.store.GetTemplateDerivedBoards(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.TemplatePropagationReport{
+		TemplateID:      template.ID,
+		Version:         template.Version,
+		Boards:          []*model.TemplateBoardUpdate{},
+		SkippedBoardIDs: []string{},
+	}
+
+	for _, derivedBoard := range derivedBoards {
+		if derivedBoard.TemplateVersion >= template.Version {
+			continue
+		}
+		if !canUpdate(derivedBoard.BoardID) {
+			report.SkippedBoardIDs = append(report.SkippedBoardIDs, derivedBoard.BoardID)
+			continue
+		}
+
+		board, err := a.GetBoard(derivedBoard.BoardID)
+		if err != nil {
+			return nil, err
+		}
+		if board == nil {
+			continue
+		}
+
+		update, err := a.applyTemplateToBoard(template, templateViews, board, userID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot update board %s from template %s: %w", board.ID, templateID, err)
+		}
+		report.Boards = append(report.Boards, update)
+	}
+	return report, nil
+}
+
+// UpdateBoardFromTemplate pushes the card properties and views of the current
+// version of a template to a board created from it.
+func (a *App) UpdateBoardFromTemplate(boardID, userID string) (*model.TemplateBoardUpdate, error) {
+	derivedBoard, err := a.store.GetTemplateDerivedBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := a.GetTemplate(derivedBoard.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	templateViews, err := a.store.GetBlocksWithType(template.BoardID, model.TypeView)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+	return a.applyTemplateToBoard(template, templateViews, board, userID)
+}
+
+func (a *App) applyTemplateToBoard(template *model.Template, templateViews []model.Block, board *model.Board, userID string) (*model.TemplateBoardUpdate, error) {
+	update := &model.TemplateBoardUpdate{
+		BoardID:           board.ID,
+		AddedProperties:   []string{},
+		UpdatedProperties: []string{},
+		AddedViews:        []string{},
+	}
+
+	updatedProperties := templateCardPropertiesUpdate(template.Board.CardProperties, board.CardProperties, update)
+	if len(updatedProperties) != 0 {
+		patch := &model.BoardPatch{UpdatedCardProperties: updatedProperties}
+		if _, err := a.PatchBoard(patch, board.ID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	views, err := a.store.GetBlocksWithType(board.ID, model.TypeView)
+	if err != nil {
+		return nil, err
+	}
+	viewTitles := map[string]bool{}
+	for _, view := range views {
+		viewTitles[strings.ToLower(view.Title)] = true
+	}
+
+	newViews := []model.Block{}
+	for _, templateView := range templateViews {
+		if viewTitles[strings.ToLower(templateView.Title)] {
+			continue
+		}
+		newViews = append(newViews, templateViewForBoard(templateView, board.ID))
+	}
+	if len(newViews) != 0 {
+		inserted, _, err := a.InsertBlocks(newViews, userID, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, view := range inserted {
+			update.AddedViews = append(update.AddedViews, view.ID)
+		}
+	}
+
+	err = a.store.SaveTemplateDerivedBoard(&model.TemplateDerivedBoard{
+		BoardID:         board.ID,
+		TemplateID:      template.ID,
+		TemplateVersion: template.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Debug("Updated board from template",
+		mlog.String("boardID", board.ID),
+		mlog.String("templateID", template.ID),
+		mlog.Int("version", template.Version),
+		mlog.Bool("changed", update.HasChanges()),
+	)
+	return update, nil
+}
+
+// templateCardPropertiesUpdate returns the card properties that need to be
+// added or updated in a board for it to include the card properties of its
+// template, and records them in the update.
+func templateCardPropertiesUpdate(templateProperties, boardProperties []map[string]interface{}, update *model.TemplateBoardUpdate) []map[string]interface{} {
+	boardPropertiesByID := map[string]map[string]interface{}{}
+	for _, property := range boardProperties {
+		if id, ok := property["id"].(string); ok {
+			boardPropertiesByID[id] = property
+		}
+	}
+
+	updated := []map[string]interface{}{}
+	for _, templateProperty := range templateProperties {
+		id, ok := templateProperty["id"].(string)
+		if !ok {
+			continue
+		}
+
+		boardProperty, exists := boardPropertiesByID[id]
+		if !exists {
+			updated = append(updated, templateProperty)
+			update.AddedProperties = append(update.AddedProperties, id)
+			continue
+		}
+
+		if merged, changed := mergePropertyOptions(boardProperty, templateProperty); changed {
+			updated = append(updated, merged)
+			update.UpdatedProperties = append(update.UpdatedProperties, id)
+		}
+	}
+	return updated
+}
+
+// mergePropertyOptions adds to a card property the options of the template
+// property that it doesn't have yet.
+func mergePropertyOptions(property, templateProperty map[string]interface{}) (map[string]interface{}, bool) {
+	templateOptions, ok := templateProperty["options"].([]interface{})
+	if !ok {
+		return property, false
+	}
+	options, _ := property["options"].([]interface{})
+
+	optionIDs := map[string]bool{}
+	for _, option := range options {
+		if optionMap, ok := option.(map[string]interface{}); ok {
+			if id, ok := optionMap["id"].(string); ok {
+				optionIDs[id] = true
+			}
+		}
+	}
+
+	mergedOptions := append([]interface{}{}, options...)
+	for _, option := range templateOptions {
+		optionMap, ok := option.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id, ok := optionMap["id"].(string); ok && !optionIDs[id] {
+			mergedOptions = append(mergedOptions, option)
+		}
+	}
+
+	if len(mergedOptions) == len(options) {
+		return property, false
+	}
+
+	merged := make(map[string]interface{}, len(property))
+	for key, value := range property {
+		merged[key] = value
+	}
+	merged["options"] = mergedOptions
+	return merged, true
+}
+
+// templateViewForBoard copies a view of a template to a board. The card
+// ordering and default card template of the view are dropped, as they refer
+// to blocks of the template.
+func templateViewForBoard(templateView model.Block, boardID string) model.Block {
+	fields := make(map[string]interface{}, len(templateView.Fields))
+	for key, value := range templateView.Fields {
+		fields[key] = value
+	}
+	fields["cardOrder"] = []interface{}{}
+	delete(fields, "defaultTemplateId")
+
+	now := utils.GetMillis()
+	return model.Block{
+		ID:       utils.NewID(utils.IDTypeView),
+		BoardID:  boardID,
+		ParentID: boardID,
+		Type:     model.TypeView,
+		Title:    templateView.Title,
+		Fields:   fields,
+		CreateAt: now,
+		UpdateAt: now,
+	}
+}
+
+// ExportTemplate writes an archive containing a template board and its
+// template metadata.
+func (a *App) ExportTemplate(w io.Writer, templateID string) error {
+	template, err := a.store.GetTemplate(templateID)
+	if err != nil {
+		return err
+	}
+
+	opts := model.ExportArchiveOptions{
+		TeamID:   template.TeamID,
+		BoardIDs: []string{template.BoardID},
+		TemplateInfo: map[string]*model.TemplateArchiveInfo{
+			template.BoardID: {
+				Category: template.Category,
+				Tags:     template.Tags,
+				Version:  template.Version,
+			},
+		},
+	}
+	return a.ExportArchive(w, opts)
+}
+
+// ImportTemplates imports the boards of an archive as templates of a team's
+// library. The template metadata stored in the archive is used when present,
+// otherwise the given category and tags are applied.
+func (a *App) ImportTemplates(r io.Reader, teamID, userID, category string, tags []string) ([]*model.Template, error) {
+	if err := (&model.PublishTemplateOptions{Category: category, Tags: tags}).IsValid(); err != nil {
+		return nil, err
+	}
+
+	templates := []*model.Template{}
+	opt := model.ImportArchiveOptions{
+		TeamID:     teamID,
+		ModifiedBy: userID,
+		BoardModifier: func(board *model.Board, _ map[string]interface{}) bool {
+			board.IsTemplate = true
+			board.Type = model.BoardTypeOpen
+			return true
+		},
+		BoardImported: func(boardID string, info *model.TemplateArchiveInfo) error {
+			template := &model.Template{
+				TeamID:     teamID,
+				BoardID:    boardID,
+				Category:   category,
+				Tags:       tags,
+				Version:    1,
+				CreatedBy:  userID,
+				ModifiedBy: userID,
+			}
+			if info != nil {
+				template.Category = info.Category
+				template.Tags = info.Tags
+				if info.Version > 0 {
+					template.Version = info.Version
+				}
+			}
+
+			board, err := a.store.GetBoard(boardID)
+			if err != nil {
+				return err
+			}
+			if board.TemplateVersion != template.Version {
+				board.TemplateVersion = template.Version
+				if board, err = a.store.InsertBoard(board, userID); err != nil {
+					return err
+				}
+			}
+
+			template, err = a.store.InsertTemplate(template)
+			if err != nil {
+				return err
+			}
+			template.Board = board
+			templates = append(templates, template)
+			return nil
+		},
+	}
+
+	if err := a.ImportArchive(r, opt); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func TestTemplateCardPropertiesUpdate(t *testing.T) {
+	templateProperties := []map[string]interface{}{
+		{
+			"id":   "status",
+			"type": "select",
+			"options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "done", "value": "Done"},
+			},
+		},
+		{"id": "estimate", "type": "number"},
+		{"id": "owner", "type": "person"},
+	}
+
+	boardProperties := []map[string]interface{}{
+		{
+			"id":   "status",
+			"type": "select",
+			"options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "Backlog"},
+				map[string]interface{}{"id": "blocked", "value": "Blocked"},
+			},
+		},
+		{"id": "owner", "type": "person"},
+	}
+
+	update := &model.TemplateBoardUpdate{}
+	updated := templateCardPropertiesUpdate(templateProperties, boardProperties, update)
+
+	require.Len(t, updated, 2)
+	assert.Equal(t, []string{"estimate"}, update.AddedProperties)
+	assert.Equal(t, []string{"status"}, update.UpdatedProperties)
+
+	// board options are kept, including renamed ones, and template options are appended
+	assert.Equal(t, "status", updated[0]["id"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": "todo", "value": "Backlog"},
+		map[string]interface{}{"id": "blocked", "value": "Blocked"},
+		map[string]interface{}{"id": "done", "value": "Done"},
+	}, updated[0]["options"])
+	assert.Equal(t, "estimate", updated[1]["id"])
+
+	// the original board property is not modified
+	assert.Len(t, boardProperties[0]["options"], 2)
+
+	t.Run("up to date board", func(t *testing.T) {
+		update := &model.TemplateBoardUpdate{}
+		updated := templateCardPropertiesUpdate(templateProperties, templateProperties, update)
+		require.Empty(t, updated)
+		require.False(t, update.HasChanges())
+	})
+}
+
+func TestTemplateViewForBoard(t *testing.T) {
+	templateView := model.Block{
+		ID:       "template-view-id",
+		BoardID:  "template-board-id",
+		ParentID: "template-board-id",
+		Type:     model.TypeView,
+		Title:    "By status",
+		Fields: map[string]interface{}{
+			"viewType":          "board",
+			"cardOrder":         []interface{}{"card-1", "card-2"},
+			"defaultTemplateId": "card-template-id",
+		},
+	}
+
+	view := templateViewForBoard(templateView, "board-id")
+	assert.NotEqual(t, templateView.ID, view.ID)
+	assert.Equal(t, "board-id", view.BoardID)
+	assert.Equal(t, "board-id", view.ParentID)
+	assert.Equal(t, "By status", view.Title)
+	assert.Equal(t, "board", view.Fields["viewType"])
+	assert.Empty(t, view.Fields["cardOrder"])
+	assert.NotContains(t, view.Fields, "defaultTemplateId")
+
+	// the template view is not modified
+	assert.Len(t, templateView.Fields["cardOrder"], 2)
+}
+
+func TestPatchTemplate(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	template := &model.Template{
+		ID:       "template-id",
+		TeamID:   "team-id",
+		BoardID:  "board-id",
+		Category: "Engineering",
+		Tags:     []string{"sprint"},
+		Version:  2,
+	}
+	category := "Product"
+	patch := &model.TemplatePatch{Category: &category}
+
+	th.Store.EXPECT().GetTemplate("template-id").Return(template, nil)
+	th.Store.EXPECT().UpdateTemplate(template).Return(template, nil)
+
+	updated, err := th.App.PatchTemplate("template-id", patch, "user-id")
+	require.NoError(t, err)
+	assert.Equal(t, "Product", updated.Category)
+	assert.Equal(t, []string{"sprint"}, updated.Tags)
+	assert.Equal(t, "user-id", updated.ModifiedBy)
+}
@@ -0,0 +1,91 @@
+package app
+
+import "github.com/mattermost/focalboard/server/model"
+
+// GetCardsForUser returns a page of the cards that the user created or is
+// assigned to, across the unarchived boards they are a member of.
+func (a *App) GetCardsForUser(userID string, opts model.QueryUserCardsOptions) (*model.UserCardsList, error) {
+	if err := opts.IsValid(); err != nil {
+		return nil, err
+	}
+	if opts.PerPage <= 0 {
+		opts.PerPage = model.UserCardsDefaultPerPage
+	}
+	if opts.PerPage > model.UserCardsMaxPerPage {
+		opts.PerPage = model.UserCardsMaxPerPage
+	}
+	if opts.Page < 0 {
+		opts.Page = 0
+	}
+
+	members, err := a.store.GetMembersForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	boardIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		boardIDs = append(boardIDs, member.BoardID)
+	}
+	list := &model.UserCardsList{Items: []*model.UserCard{}}
+	if len(boardIDs) == 0 {
+		return list, nil
+	}
+
+	allBoards, err := a.store.GetBoardsByIDs(boardIDs)
+	if err != nil {
+		return nil, err
+	}
+	boards := make([]*model.Board, 0, len(allBoards))
+	for _, board := range allBoards {
+		if board.IsTemplate || board.ArchiveAt != 0 {
+			continue
+		}
+		if opts.TeamID != "" && board.TeamID != opts.TeamID {
+			continue
+		}
+		boards = append(boards, board)
+	}
+
+	query, err := model.NewUserCardsQuery(userID, boards, opts)
+	if err != nil {
+		return nil, err
+	}
+	if query == nil {
+		return list, nil
+	}
+
+	cards, err := a.store.GetCardsForUser(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(cards) == opts.PerPage+1 {
+		list.HasNext = true
+		cards = cards[:opts.PerPage]
+	}
+
+	boardsByID := make(map[string]*model.Board, len(boards))
+	for _, board := range boards {
+		boardsByID[board.ID] = board
+	}
+	dueProperties := map[string]string{}
+	for propertyID, ids := range query.DueProperties {
+		for _, boardID := range ids {
+			dueProperties[boardID] = propertyID
+		}
+	}
+
+	for i := range cards {
+		board := boardsByID[cards[i].BoardID]
+		item := &model.UserCard{
+			Card:       cards[i],
+			BoardTitle: board.Title,
+			BoardIcon:  board.Icon,
+			TeamID:     board.TeamID,
+		}
+		if propertyID, ok := dueProperties[board.ID]; ok {
+			item.DueAt = model.CardDueAt(&cards[i], propertyID)
+		}
+		list.Items = append(list.Items, item)
+	}
+	return list, nil
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCardsForUser(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	members := []*model.BoardMember{
+		{BoardID: "board-id", UserID: "user-id"},
+		{BoardID: "other-team-board-id", UserID: "user-id"},
+		{BoardID: "archived-board-id", UserID: "user-id"},
+		{BoardID: "template-id", UserID: "user-id"},
+	}
+	boards := []*model.Board{
+		{ID: "board-id", TeamID: "team-id", Title: "Board", Icon: "📋", CardProperties: []map[string]interface{}{
+			{"id": "owner", "name": "Owner", "type": "person"},
+			{"id": "due", "name": "Due", "type": "date"},
+		}},
+		{ID: "other-team-board-id", TeamID: "other-team-id"},
+		{ID: "archived-board-id", TeamID: "team-id", ArchiveAt: 1000},
+		{ID: "template-id", TeamID: "team-id", IsTemplate: true},
+	}
+	card := func(id string) model.Block {
+		return model.Block{ID: id, BoardID: "board-id", Type: model.TypeCard, Fields: map[string]interface{}{
+			"properties": map[string]interface{}{"owner": "user-id", "due": `{"from":100}`},
+		}}
+	}
+
+	t.Run("cards of the unarchived boards of the team", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForUser("user-id").Return(members, nil)
+		th.Store.EXPECT().GetBoardsByIDs([]string{"board-id", "other-team-board-id", "archived-board-id", "template-id"}).Return(boards, nil)
+		th.Store.EXPECT().GetCardsForUser(gomock.Any()).DoAndReturn(func(query *model.UserCardsQuery) ([]model.Block, error) {
+			require.Equal(t, []string{"board-id"}, query.BoardIDs)
+			require.Equal(t, 2, query.PerPage)
+			return []model.Block{card("card-1"), card("card-2"), card("card-3")}, nil
+		})
+
+		list, err := th.App.GetCardsForUser("user-id", model.QueryUserCardsOptions{TeamID: "team-id", PerPage: 2})
+		require.NoError(t, err)
+		require.True(t, list.HasNext)
+		require.Len(t, list.Items, 2)
+		require.Equal(t, "card-1", list.Items[0].Card.ID)
+		require.Equal(t, "Board", list.Items[0].BoardTitle)
+		require.Equal(t, "📋", list.Items[0].BoardIcon)
+		require.Equal(t, "team-id", list.Items[0].TeamID)
+		require.Equal(t, int64(100), list.Items[0].DueAt)
+	})
+
+	t.Run("no boards", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForUser("user-id").Return([]*model.BoardMember{}, nil)
+
+		list, err := th.App.GetCardsForUser("user-id", model.QueryUserCardsOptions{})
+		require.NoError(t, err)
+		require.False(t, list.HasNext)
+		require.Empty(t, list.Items)
+	})
+
+	t.Run("status that no board has", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForUser("user-id").Return(members[:1], nil)
+		th.Store.EXPECT().GetBoardsByIDs([]string{"board-id"}).Return(boards[:1], nil)
+
+		list, err := th.App.GetCardsForUser("user-id", model.QueryUserCardsOptions{Status: "Done"})
+		require.NoError(t, err)
+		require.Empty(t, list.Items)
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		_, err := th.App.GetCardsForUser("user-id", model.QueryUserCardsOptions{SortBy: "priority"})
+		require.ErrorIs(t, err, model.ErrInvalidUserCardsSort)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForUser("user-id").Return(nil, errors.New("error"))
+
+		_, err := th.App.GetCardsForUser("user-id", model.QueryUserCardsOptions{})
+		require.Error(t, err)
+	})
+}
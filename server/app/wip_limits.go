@@ -0,0 +1,159 @@
+package app
+
+import (
+	"sort"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// GetBoardWIPLimits returns the number of cards of each board column with a
+// WIP limit.
+func (a *App) GetBoardWIPLimits(board *model.Board) ([]*model.WIPLimitStatus, error) {
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+	if !schema.HasWIPLimits() {
+		return []*model.WIPLimitStatus{}, nil
+	}
+
+	cards, err := a.store.GetBlocksWithType(board.ID, model.TypeCard)
+	if err != nil {
+		return nil, err
+	}
+	return schema.WIPLimitStatuses(cards), nil
+}
+
+// wipLimitsCheck checks the WIP limits of the boards of the changed cards
+// in the transaction saving the changes, so concurrent changes can't
+// exceed a limit together.
+type wipLimitsCheck struct {
+	schemas  map[string]model.PropSchema
+	strict   map[string]bool
+	changed  map[string][]model.Block
+	previous map[string]*model.Block
+
+	// the limits exceeded on the boards that allow it
+	exceeded []*model.WIPLimitStatus
+}
+
+// newWIPLimitsCheck returns the check of the WIP limits for the changed
+// cards, keyed by board ID. previous holds the current version of the
+// changed cards that already exist. On a strict board, exceeding a limit
+// is only allowed to the board admins.
+func (a *App) newWIPLimitsCheck(boards map[string]*model.Board, changed map[string][]model.Block, previous map[string]*model.Block, modifiedByID string) (*wipLimitsCheck, error) {
+	c := &wipLimitsCheck{
+		schemas:  map[string]model.PropSchema{},
+		strict:   map[string]bool{},
+		changed:  changed,
+		previous: previous,
+	}
+	for boardID, blocks := range changed {
+		board, ok := boards[boardID]
+		if !ok || !hasCards(blocks) {
+			continue
+		}
+
+		schema, err := model.ParsePropertySchema(board)
+		if err != nil {
+			return nil, err
+		}
+		if !schema.HasWIPLimits() {
+			continue
+		}
+		c.schemas[boardID] = schema
+
+		if board.WIPLimitMode() == model.WIPLimitModeStrict {
+			isAdmin, err := a.isBoardAdmin(boardID, modifiedByID)
+			if err != nil {
+				return nil, err
+			}
+			c.strict[boardID] = !isAdmin
+		}
+	}
+	return c, nil
+}
+
+// boardIDs returns the IDs of the boards with WIP limits.
+func (c *wipLimitsCheck) boardIDs() []string {
+	boardIDs := make([]string, 0, len(c.schemas))
+	for boardID := range c.schemas {
+		boardIDs = append(boardIDs, boardID)
+	}
+	sort.Strings(boardIDs)
+	return boardIDs
+}
+
+// check checks the WIP limits of the board once the changed cards are
+// applied to its current cards. It returns an ErrWIPLimitExceeded if a
+// limit is exceeded on a board that doesn't allow it.
+func (c *wipLimitsCheck) check(boardID string, cards []model.Block) error {
+	schema, ok := c.schemas[boardID]
+	if !ok {
+		return nil
+	}
+	changed := c.changed[boardID]
+
+	changedByID := make(map[string]model.Block, len(changed))
+	for _, block := range changed {
+		changedByID[block.ID] = block
+	}
+	for i := range cards {
+		if block, ok := changedByID[cards[i].ID]; ok {
+			cards[i] = block
+			delete(changedByID, block.ID)
+		}
+	}
+	for _, block := range changed {
+		if _, isNew := changedByID[block.ID]; isNew && block.Type == model.TypeCard {
+			cards = append(cards, block)
+		}
+	}
+
+	exceeded := schema.ExceededWIPLimits(cards, changed, c.previous)
+	if len(exceeded) == 0 {
+		return nil
+	}
+	if c.strict[boardID] {
+		return model.NewErrWIPLimitExceeded(exceeded)
+	}
+	c.exceeded = append(c.exceeded, exceeded...)
+	return nil
+}
+
+// saveCardChanges saves the changes and checks the WIP limits of the
+// boards of the changed cards, keyed by board ID, in the same transaction.
+// previous holds the current version of the changed cards that already
+// exist. It returns the exceeded limits the boards allowed.
+func (a *App) saveCardChanges(changes *model.CardChanges, boards map[string]*model.Board, changed map[string][]model.Block, previous map[string]*model.Block, modifiedByID string) ([]*model.WIPLimitStatus, error) {
+	wipLimits, err := a.newWIPLimitsCheck(boards, changed, previous, modifiedByID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes.BoardIDs = wipLimits.boardIDs()
+	if err := a.store.SaveCardChanges(changes, modifiedByID, wipLimits.check); err != nil {
+		return nil, err
+	}
+
+	if len(wipLimits.exceeded) > 0 {
+		a.logger.Debug("WIP limit exceeded",
+			mlog.Array("board_ids", changes.BoardIDs),
+			mlog.String("user_id", modifiedByID),
+		)
+	}
+	return wipLimits.exceeded, nil
+}
+
+func (a *App) isBoardAdmin(boardID, userID string) (bool, error) {
+	member, err := a.store.GetMemberForBoard(boardID, userID)
+	if model.IsErrNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return member.SchemeAdmin, nil
+}
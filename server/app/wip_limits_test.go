@@ -0,0 +1,139 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func wipLimitsTestBoard(mode string) *model.Board {
+	return &model.Board{
+		ID: testBoardID,
+		Properties: map[string]interface{}{
+			model.BoardPropertyWIPLimitMode: mode,
+		},
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "doing", "value": "Doing", "wipLimit": float64(1)},
+			}},
+		},
+	}
+}
+
+func wipLimitsTestCard(id, status string) model.Block {
+	return model.Block{
+		ID:      id,
+		BoardID: testBoardID,
+		Type:    model.TypeCard,
+		Fields:  map[string]interface{}{"properties": map[string]interface{}{"status": status}},
+	}
+}
+
+func TestCheckWIPLimits(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	existing := func() []model.Block {
+		return []model.Block{wipLimitsTestCard("card1", "doing"), wipLimitsTestCard("card2", "todo")}
+	}
+	newCheck := func(board *model.Board, changed []model.Block, previous map[string]*model.Block, userID string) *wipLimitsCheck {
+		boards := map[string]*model.Board{board.ID: board}
+		c, err := th.App.newWIPLimitsCheck(boards, map[string][]model.Block{board.ID: changed}, previous, userID)
+		require.NoError(t, err)
+		return c
+	}
+
+	t.Run("strict board rejects a non admin", func(t *testing.T) {
+		board := wipLimitsTestBoard(model.WIPLimitModeStrict)
+		th.Store.EXPECT().GetMemberForBoard(testBoardID, "user-id").Return(&model.BoardMember{SchemeEditor: true}, nil)
+
+		c := newCheck(board, []model.Block{wipLimitsTestCard("card3", "doing")}, nil, "user-id")
+		require.Equal(t, []string{testBoardID}, c.boardIDs())
+		err := c.check(testBoardID, existing())
+		require.True(t, model.IsErrWIPLimitExceeded(err))
+		var wipErr *model.ErrWIPLimitExceeded
+		require.ErrorAs(t, err, &wipErr)
+		require.Equal(t, []*model.WIPLimitStatus{{PropertyID: "status", OptionID: "doing", Limit: 1, Count: 2}}, wipErr.Limits)
+		require.Empty(t, c.exceeded)
+	})
+
+	t.Run("strict board allows a board admin", func(t *testing.T) {
+		board := wipLimitsTestBoard(model.WIPLimitModeStrict)
+		th.Store.EXPECT().GetMemberForBoard(testBoardID, "admin-id").Return(&model.BoardMember{SchemeAdmin: true}, nil)
+
+		c := newCheck(board, []model.Block{wipLimitsTestCard("card3", "doing")}, nil, "admin-id")
+		require.NoError(t, c.check(testBoardID, existing()))
+		require.Len(t, c.exceeded, 1)
+	})
+
+	t.Run("warn board allows everyone and reports the limit", func(t *testing.T) {
+		board := wipLimitsTestBoard(model.WIPLimitModeWarn)
+
+		c := newCheck(board, []model.Block{wipLimitsTestCard("card3", "doing")}, nil, "user-id")
+		require.NoError(t, c.check(testBoardID, existing()))
+		require.Equal(t, []*model.WIPLimitStatus{{PropertyID: "status", OptionID: "doing", Limit: 1, Count: 2}}, c.exceeded)
+	})
+
+	t.Run("counts the cards read by the check", func(t *testing.T) {
+		board := wipLimitsTestBoard(model.WIPLimitModeWarn)
+
+		c := newCheck(board, []model.Block{wipLimitsTestCard("card3", "doing")}, nil, "user-id")
+		require.NoError(t, c.check(testBoardID, []model.Block{wipLimitsTestCard("card2", "todo")}))
+		require.Empty(t, c.exceeded)
+	})
+
+	t.Run("moving a card out of a full option", func(t *testing.T) {
+		board := wipLimitsTestBoard(model.WIPLimitModeStrict)
+		th.Store.EXPECT().GetMemberForBoard(testBoardID, "user-id").Return(nil, model.NewErrNotFound("member"))
+
+		previous := map[string]*model.Block{"card1": &existing()[0]}
+		c := newCheck(board, []model.Block{wipLimitsTestCard("card1", "todo")}, previous, "user-id")
+		require.NoError(t, c.check(testBoardID, existing()))
+		require.Empty(t, c.exceeded)
+	})
+
+	t.Run("board without limits", func(t *testing.T) {
+		board := &model.Board{ID: testBoardID}
+		c := newCheck(board, []model.Block{wipLimitsTestCard("card3", "doing")}, nil, "user-id")
+		require.Empty(t, c.boardIDs())
+		require.NoError(t, c.check(testBoardID, existing()))
+	})
+}
+
+func TestPatchBlocksWIPLimits(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	existing := func() []model.Block {
+		return []model.Block{wipLimitsTestCard("card1", "doing"), wipLimitsTestCard("card2", "todo")}
+	}
+	board := wipLimitsTestBoard(model.WIPLimitModeStrict)
+
+	patches := &model.BlockPatchBatch{
+		BlockIDs: []string{"card2"},
+		BlockPatches: []model.BlockPatch{
+			{UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{"status": "doing"}}},
+		},
+	}
+	oldBlocks := existing()
+	th.Store.EXPECT().GetBlocksByIDs([]string{"card2"}).Return([]model.Block{oldBlocks[1]}, nil)
+	th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+	th.Store.EXPECT().GetMemberForBoard(testBoardID, "user-id").Return(nil, model.NewErrNotFound("member"))
+	th.Store.EXPECT().SaveCardChanges(gomock.Any(), "user-id", gomock.Any()).DoAndReturn(
+		func(changes *model.CardChanges, userID string, check model.CardsCheck) error {
+			// the check runs on the cards read by the store
+			require.Equal(t, []string{testBoardID}, changes.BoardIDs)
+			require.Equal(t, patches, changes.BlockPatches)
+			return check(testBoardID, existing())
+		},
+	)
+
+	_, err := th.App.PatchBlocks("team-id", patches, "user-id")
+	require.True(t, model.IsErrWIPLimitExceeded(err))
+
+	// the patch must not modify the current version of the card
+	require.Equal(t, "todo", oldBlocks[1].Fields["properties"].(map[string]interface{})["status"])
+}
@@ -0,0 +1,296 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/mattermost/focalboard/server/api"
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// NewLocalClient returns a client for the admin API of a server running in
+// local mode, which is only served on its unix socket.
+func NewLocalClient(socketPath string) *Client {
+	c := NewClient("http://_", "")
+	c.HTTPClient = &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	return c
+}
+
+func (c *Client) GetAdminRoute() string {
+	return "/admin"
+}
+
+func (c *Client) AdminGetUsers() ([]*api.AdminUser, *Response) {
+	r, err := c.DoAPIGet(c.GetAdminRoute()+"/users", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var users []*api.AdminUser
+	if jsonErr := json.NewDecoder(r.Body).Decode(&users); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return users, BuildResponse(r)
+}
+
+func (c *Client) AdminCreateUser(data *api.AdminCreateUserData) (*api.AdminUser, *Response) {
+	r, err := c.DoAPIPost(c.GetAdminRoute()+"/users", toJSON(data))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var user *api.AdminUser
+	if jsonErr := json.NewDecoder(r.Body).Decode(&user); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return user, BuildResponse(r)
+}
+
+func (c *Client) AdminSetPassword(username, password string) *Response {
+	data := &api.AdminSetPasswordData{Password: password}
+	r, err := c.DoAPIPost(c.GetAdminRoute()+"/users/"+url.PathEscape(username)+"/password", toJSON(data))
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r)
+}
+
+func (c *Client) AdminDeactivateUser(username string) *Response {
+	r, err := c.DoAPIPost(c.GetAdminRoute()+"/users/"+url.PathEscape(username)+"/deactivate", "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r)
+}
+
+func (c *Client) AdminRevokeUserSessions(username string) (int64, *Response) {
+	r, err := c.DoAPIDelete(c.GetAdminRoute()+"/users/"+url.PathEscape(username)+"/sessions", "")
+	if err != nil {
+		return 0, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result api.AdminRevokeSessionsResponse
+	if jsonErr := json.NewDecoder(r.Body).Decode(&result); jsonErr != nil {
+		return 0, BuildErrorResponse(r, jsonErr)
+	}
+	return result.Revoked, BuildResponse(r)
+}
+
+func (c *Client) AdminGetTeams() ([]*model.Team, *Response) {
+	r, err := c.DoAPIGet(c.GetAdminRoute()+"/teams", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var teams []*model.Team
+	if jsonErr := json.NewDecoder(r.Body).Decode(&teams); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return teams, BuildResponse(r)
+}
+
+func (c *Client) AdminGetBoards(teamID string) ([]*model.Board, *Response) {
+	r, err := c.DoAPIGet(c.GetAdminRoute()+"/teams/"+teamID+"/boards", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) AdminTransferBoardOwnership(boardID, username string) (*model.Board, *Response) {
+	data := &api.AdminTransferBoardOwnershipData{Username: username}
+	r, err := c.DoAPIPost(c.GetAdminRoute()+"/boards/"+boardID+"/owner", toJSON(data))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
+// AdminExportTeam writes the archive of all the boards of a team to w. Only
+// the filters of opts are used, and it may be nil.
+func (c *Client) AdminExportTeam(teamID string, opts *model.ExportArchiveOptions, w io.Writer) *Response {
+	r, err := c.DoAPIGet(c.GetAdminRoute()+"/teams/"+teamID+"/export"+exportArchiveQuery(opts), "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	if _, err := io.Copy(w, r.Body); err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	return BuildResponse(r)
+}
+
+// AdminImport imports an archive in a team. The imported boards are owned
+// by the given user.
+func (c *Client) AdminImport(teamID, username string, data io.Reader) *Response {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file")
+	if err != nil {
+		return &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return &Response{Error: err}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	route := c.GetAdminRoute() + "/teams/" + teamID + "/import?username=" + url.QueryEscape(username)
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+route, body, "", opt)
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r)
+}
+
+// AdminBackup writes a backup of all the data of the server to w.
+func (c *Client) AdminBackup(w io.Writer) *Response {
+	r, err := c.DoAPIGet(c.GetAdminRoute()+"/backup", "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	if _, err := io.Copy(w, r.Body); err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	return BuildResponse(r)
+}
+
+// AdminRestore loads a backup into the server, which must not have any
+// users or boards.
+func (c *Client) AdminRestore(data io.Reader) (*model.BackupManifest, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, "backup.zip")
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetAdminRoute()+"/restore", body, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var manifest model.BackupManifest
+	if jsonErr := json.NewDecoder(r.Body).Decode(&manifest); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return &manifest, BuildResponse(r)
+}
+
+// AdminRunDataRetention deletes the boards not modified for the given
+// number of days, or for the configured retention period if zero.
+func (c *Client) AdminRunDataRetention(days int) (int64, *Response) {
+	data := &api.AdminDataRetentionData{Days: days}
+	r, err := c.DoAPIPost(c.GetAdminRoute()+"/data-retention", toJSON(data))
+	if err != nil {
+		return 0, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result api.AdminDataRetentionResponse
+	if jsonErr := json.NewDecoder(r.Body).Decode(&result); jsonErr != nil {
+		return 0, BuildErrorResponse(r, jsonErr)
+	}
+	return result.Deleted, BuildResponse(r)
+}
+
+func (c *Client) AdminCleanUpOrphanFiles() (*model.OrphanFilesCleanup, *Response) {
+	r, err := c.DoAPIPost(c.GetAdminRoute()+"/files/cleanup", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result model.OrphanFilesCleanup
+	if jsonErr := json.NewDecoder(r.Body).Decode(&result); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return &result, BuildResponse(r)
+}
+
+func (c *Client) AdminGetSystemSettings() (map[string]string, *Response) {
+	r, err := c.DoAPIGet(c.GetAdminRoute()+"/settings", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var settings map[string]string
+	if jsonErr := json.NewDecoder(r.Body).Decode(&settings); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return settings, BuildResponse(r)
+}
+
+func (c *Client) AdminGetAuditRecords(opts model.QueryAuditRecordsOptions) (*model.AuditRecordsList, *Response) {
+	query := url.Values{}
+	if opts.UserID != "" {
+		query.Set("user_id", opts.UserID)
+	}
+	if opts.TeamID != "" {
+		query.Set("team_id", opts.TeamID)
+	}
+	if opts.BoardID != "" {
+		query.Set("board_id", opts.BoardID)
+	}
+	if opts.Event != "" {
+		query.Set("event", opts.Event)
+	}
+	query.Set("page", strconv.Itoa(opts.Page))
+	query.Set("per_page", strconv.Itoa(opts.PerPage))
+
+	r, err := c.DoAPIGet(c.GetAdminRoute()+"/audit?"+query.Encode(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var records *model.AuditRecordsList
+	if jsonErr := json.NewDecoder(r.Body).Decode(&records); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return records, BuildResponse(r)
+}
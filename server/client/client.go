@@ -8,6 +8,8 @@ import (
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/mattermost/focalboard/server/api"
@@ -39,6 +41,29 @@ func BuildResponse(r *http.Response) *Response {
 	}
 }
 
+// Etag returns the ETag of the response, if any.
+func (r *Response) Etag() string {
+	if r.Header == nil {
+		return ""
+	}
+	return r.Header.Get("ETag")
+}
+
+// ExceededWIPLimits returns the WIP limits exceeded by a change the board
+// allowed, if any.
+func (r *Response) ExceededWIPLimits() []*model.WIPLimitStatus {
+	if r.Header == nil {
+		return nil
+	}
+	value := r.Header.Get(api.HeaderExceededWIPLimits)
+	if value == "" {
+		return nil
+	}
+	var exceeded []*model.WIPLimitStatus
+	_ = json.Unmarshal([]byte(value), &exceeded)
+	return exceeded
+}
+
 func BuildErrorResponse(r *http.Response, err error) *Response {
 	statusCode := 0
 	header := make(http.Header)
@@ -109,14 +134,32 @@ func (c *Client) DoAPIRequest(method, url, data, etag string) (*http.Response, e
 	return c.doAPIRequestReader(method, url, strings.NewReader(data), etag)
 }
 
+// DoAPIPatchIfMatch sends a PATCH request that is only applied by the
+// server if the resource still matches the given ETag.
+func (c *Client) DoAPIPatchIfMatch(url, data, etag string) (*http.Response, error) {
+	return c.doAPIRequestReader(http.MethodPatch, c.APIURL+url, strings.NewReader(data), "", withIfMatch(etag))
+}
+
 type requestOption func(r *http.Request)
 
-func (c *Client) doAPIRequestReader(method, url string, data io.Reader, _ /* etag */ string, opts ...requestOption) (*http.Response, error) {
+func withIfMatch(etag string) requestOption {
+	return func(r *http.Request) {
+		if etag != "" {
+			r.Header.Set("If-Match", etag)
+		}
+	}
+}
+
+func (c *Client) doAPIRequestReader(method, url string, data io.Reader, etag string, opts ...requestOption) (*http.Response, error) {
 	rq, err := http.NewRequest(method, url, data)
 	if err != nil {
 		return nil, err
 	}
 
+	if etag != "" {
+		rq.Header.Set("If-None-Match", etag)
+	}
+
 	for _, opt := range opts {
 		opt(rq)
 	}
@@ -176,6 +219,10 @@ func (c *Client) GetBoardMetadataRoute(boardID string) string {
 	return fmt.Sprintf("%s/%s/metadata", c.GetBoardsRoute(), boardID)
 }
 
+func (c *Client) GetBoardAuditRoute(boardID string) string {
+	return fmt.Sprintf("%s/%s/audit", c.GetBoardsRoute(), boardID)
+}
+
 func (c *Client) GetJoinBoardRoute(boardID string) string {
 	return fmt.Sprintf("%s/%s/join", c.GetBoardsRoute(), boardID)
 }
@@ -236,6 +283,42 @@ func (c *Client) GetUserBoardsInsights(teamID string, userID string, timeRange s
 	return boardInsightsList, BuildResponse(r)
 }
 
+// GetBoardChart computes a chart of a board. Options left empty use the
+// server defaults.
+func (c *Client) GetBoardChart(boardID string, opts *model.BoardChartOptions) (*model.BoardChart, *Response) {
+	query := url.Values{}
+	query.Set("chart", opts.Chart)
+	query.Set("property_id", opts.PropertyID)
+	params := map[string]string{
+		"start_value":      opts.StartValue,
+		"end_value":        opts.EndValue,
+		"date_property_id": opts.DatePropertyID,
+		"interval":         opts.Interval,
+	}
+	for key, value := range params {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if opts.From != 0 {
+		query.Set("from", strconv.FormatInt(opts.From, 10))
+	}
+	if opts.To != 0 {
+		query.Set("to", strconv.FormatInt(opts.To, 10))
+	}
+	if opts.Location != nil {
+		query.Set("time_zone", opts.Location.String())
+	}
+
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/insights?"+query.Encode(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardChartFromJSON(r.Body), BuildResponse(r)
+}
+
 func (c *Client) GetBlocksForBoard(boardID string) ([]model.Block, *Response) {
 	r, err := c.DoAPIGet(c.GetBlocksRoute(boardID), "")
 	if err != nil {
@@ -256,6 +339,70 @@ func (c *Client) GetAllBlocksForBoard(boardID string) ([]model.Block, *Response)
 	return model.BlocksFromJSON(r.Body), BuildResponse(r)
 }
 
+// GetBlocksPage returns a page of the blocks of a board. An empty cursor
+// fetches the first page, a non-zero since requests a delta sync.
+func (c *Client) GetBlocksPage(boardID, cursor string, since int64, perPage int) (*model.BlocksPage, *Response) {
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(perPage))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	if since != 0 {
+		query.Set("since", strconv.FormatInt(since, 10))
+	}
+
+	r, err := c.DoAPIGet(c.GetBlocksRoute(boardID)+"?"+query.Encode(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var page *model.BlocksPage
+	if jsonErr := json.NewDecoder(r.Body).Decode(&page); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return page, BuildResponse(r)
+}
+
+// ForEachBlocksPage fetches all the pages of the blocks of a board, calling
+// fn for each of them. Iteration stops at the first error returned by fn.
+func (c *Client) ForEachBlocksPage(boardID string, since int64, perPage int, fn func(page *model.BlocksPage) error) *Response {
+	cursor := ""
+	for {
+		page, resp := c.GetBlocksPage(boardID, cursor, since, perPage)
+		if resp.Error != nil {
+			return resp
+		}
+
+		if err := fn(page); err != nil {
+			resp.Error = err
+			return resp
+		}
+
+		if !page.HasNext {
+			return resp
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// SyncBlocksForBoard fetches all the blocks of a board updated at or after
+// since, and the ones deleted since then, in a single merged page. Pass
+// page.NextSince(since) as since to the next sync; zero fetches all the
+// blocks of the board.
+func (c *Client) SyncBlocksForBoard(boardID string, since int64, perPage int) (*model.BlocksPage, *Response) {
+	result := &model.BlocksPage{Blocks: []model.Block{}}
+	resp := c.ForEachBlocksPage(boardID, since, perPage, func(page *model.BlocksPage) error {
+		result.Blocks = append(result.Blocks, page.Blocks...)
+		result.Deleted = append(result.Deleted, page.Deleted...)
+		return nil
+	})
+	if resp.Error != nil {
+		return nil, resp
+	}
+	return result, resp
+}
+
 func (c *Client) PatchBlock(boardID, blockID string, blockPatch *model.BlockPatch) (bool, *Response) {
 	r, err := c.DoAPIPatch(c.GetBlockRoute(boardID, blockID), toJSON(blockPatch))
 	if err != nil {
@@ -266,6 +413,33 @@ func (c *Client) PatchBlock(boardID, blockID string, blockPatch *model.BlockPatc
 	return true, BuildResponse(r)
 }
 
+// PatchBlockIfMatch patches a block only if it still matches the given
+// ETag. The server responds with 412 Precondition Failed otherwise.
+func (c *Client) PatchBlockIfMatch(boardID, blockID string, blockPatch *model.BlockPatch, etag string) (bool, *Response) {
+	r, err := c.DoAPIPatchIfMatch(c.GetBlockRoute(boardID, blockID), toJSON(blockPatch), etag)
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+// GetBlocksForBoardIfNoneMatch returns the blocks of a board, or nil blocks
+// and a 304 status code if they still match the given ETag.
+func (c *Client) GetBlocksForBoardIfNoneMatch(boardID, etag string) ([]model.Block, *Response) {
+	r, err := c.DoAPIGet(c.GetBlocksRoute(boardID), etag)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	if r.StatusCode == http.StatusNotModified {
+		return nil, BuildResponse(r)
+	}
+	return model.BlocksFromJSON(r.Body), BuildResponse(r)
+}
+
 func (c *Client) DuplicateBoard(boardID string, asTemplate bool, teamID string) (*model.BoardsAndBlocks, *Response) {
 	queryParams := "?asTemplate=false&"
 	if asTemplate {
@@ -283,6 +457,66 @@ func (c *Client) DuplicateBoard(boardID string, asTemplate bool, teamID string)
 	return model.BoardsAndBlocksFromJSON(r.Body), BuildResponse(r)
 }
 
+func (c *Client) MoveCard(boardID, cardID, destBoardID string) (*model.CardTransferResult, *Response) {
+	return c.transferCard(c.GetBlockRoute(boardID, cardID)+"/move", destBoardID)
+}
+
+func (c *Client) CopyCard(boardID, cardID, destBoardID string) (*model.CardTransferResult, *Response) {
+	return c.transferCard(c.GetBlockRoute(boardID, cardID)+"/copy", destBoardID)
+}
+
+func (c *Client) transferCard(route, destBoardID string) (*model.CardTransferResult, *Response) {
+	r, err := c.DoAPIPost(route, toJSON(model.CardTransferRequest{BoardID: destBoardID}))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.CardTransferResultFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) ArchiveCard(boardID, cardID string) (*model.Block, *Response) {
+	return c.setCardArchived(c.GetBlockRoute(boardID, cardID) + "/archive")
+}
+
+func (c *Client) UnarchiveCard(boardID, cardID string) (*model.Block, *Response) {
+	return c.setCardArchived(c.GetBlockRoute(boardID, cardID) + "/unarchive")
+}
+
+func (c *Client) setCardArchived(route string) (*model.Block, *Response) {
+	r, err := c.DoAPIPost(route, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var block *model.Block
+	_ = json.NewDecoder(r.Body).Decode(&block)
+	return block, BuildResponse(r)
+}
+
+// GetAllBlocksForBoardWithArchived returns all the blocks of a board
+// including the archived cards and their child blocks.
+func (c *Client) GetAllBlocksForBoardWithArchived(boardID string) ([]model.Block, *Response) {
+	r, err := c.DoAPIGet(c.GetAllBlocksRoute(boardID)+"&include_archived=true", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BlocksFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) BulkCardOperation(boardID string, op *model.BulkCardOperation) (*model.BulkCardResult, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/cards/bulk", toJSON(op))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BulkCardResultFromJSON(r.Body), BuildResponse(r)
+}
+
 func (c *Client) DuplicateBlock(boardID, blockID string, asTemplate bool) (bool, *Response) {
 	queryParams := "?asTemplate=false"
 	if asTemplate {
@@ -380,6 +614,66 @@ func (c *Client) GetUserCategoryBoards(teamID string) ([]model.CategoryBoards, *
 	return categoryBoards, BuildResponse(r)
 }
 
+func (c *Client) GetSharedCategoriesRoute(teamID string) string {
+	return fmt.Sprintf("%s/shared-categories", c.GetTeamRoute(teamID))
+}
+
+func (c *Client) GetSharedCategoryRoute(teamID, categoryID string) string {
+	return fmt.Sprintf("%s/%s", c.GetSharedCategoriesRoute(teamID), categoryID)
+}
+
+func (c *Client) GetSharedCategoryBoards(teamID string) ([]model.CategoryBoards, *Response) {
+	r, err := c.DoAPIGet(c.GetSharedCategoriesRoute(teamID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var categoryBoards []model.CategoryBoards
+	_ = json.NewDecoder(r.Body).Decode(&categoryBoards)
+	return categoryBoards, BuildResponse(r)
+}
+
+func (c *Client) CreateSharedCategory(category model.Category) (*model.Category, *Response) {
+	r, err := c.DoAPIPost(c.GetSharedCategoriesRoute(category.TeamID), toJSON(category))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.CategoryFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) UpdateSharedCategory(category model.Category) (*model.Category, *Response) {
+	r, err := c.DoAPIPut(c.GetSharedCategoryRoute(category.TeamID, category.ID), toJSON(category))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.CategoryFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) DeleteSharedCategory(teamID, categoryID string) *Response {
+	r, err := c.DoAPIDelete(c.GetSharedCategoryRoute(teamID, categoryID), "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r)
+}
+
+func (c *Client) UpdateSharedCategoryBoard(teamID, categoryID, boardID string) *Response {
+	r, err := c.DoAPIPost(fmt.Sprintf("%s/boards/%s", c.GetSharedCategoryRoute(teamID, categoryID), boardID), "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r)
+}
+
 func (c *Client) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks) (*model.BoardsAndBlocks, *Response) {
 	r, err := c.DoAPIPatch(c.GetBoardsAndBlocksRoute(), toJSON(pbab))
 	if err != nil {
@@ -490,6 +784,42 @@ func (c *Client) GetUserID() string {
 	return me.ID
 }
 
+func (c *Client) GetMyCards(opts model.QueryUserCardsOptions) (*model.UserCardsList, *Response) {
+	query := url.Values{}
+	if opts.TeamID != "" {
+		query.Set("team_id", opts.TeamID)
+	}
+	if opts.Status != "" {
+		query.Set("status", opts.Status)
+	}
+	if opts.DueAfter != 0 {
+		query.Set("due_after", strconv.FormatInt(opts.DueAfter, 10))
+	}
+	if opts.DueBefore != 0 {
+		query.Set("due_before", strconv.FormatInt(opts.DueBefore, 10))
+	}
+	if opts.SortBy != "" {
+		query.Set("sort", opts.SortBy)
+	}
+	if opts.Descending {
+		query.Set("desc", "true")
+	}
+	query.Set("page", strconv.Itoa(opts.Page))
+	query.Set("per_page", strconv.Itoa(opts.PerPage))
+
+	r, err := c.DoAPIGet(c.GetMeRoute()+"/cards?"+query.Encode(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var cards *model.UserCardsList
+	if jsonErr := json.NewDecoder(r.Body).Decode(&cards); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return cards, BuildResponse(r)
+}
+
 func (c *Client) GetUserRoute(id string) string {
 	return fmt.Sprintf("/users/%s", id)
 }
@@ -542,6 +872,18 @@ func (c *Client) PatchBoard(boardID string, patch *model.BoardPatch) (*model.Boa
 	return model.BoardFromJSON(r.Body), BuildResponse(r)
 }
 
+// PatchBoardIfMatch patches a board only if it still matches the given
+// ETag. The server responds with 412 Precondition Failed otherwise.
+func (c *Client) PatchBoardIfMatch(boardID string, patch *model.BoardPatch, etag string) (*model.Board, *Response) {
+	r, err := c.DoAPIPatchIfMatch(c.GetBoardRoute(boardID), toJSON(patch), etag)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
 func (c *Client) DeleteBoard(boardID string) (bool, *Response) {
 	r, err := c.DoAPIDelete(c.GetBoardRoute(boardID), "")
 	if err != nil {
@@ -562,6 +904,24 @@ func (c *Client) UndeleteBoard(boardID string) (bool, *Response) {
 	return true, BuildResponse(r)
 }
 
+func (c *Client) ArchiveBoard(boardID string) (*model.Board, *Response) {
+	return c.setBoardArchived(c.GetBoardRoute(boardID) + "/archive")
+}
+
+func (c *Client) UnarchiveBoard(boardID string) (*model.Board, *Response) {
+	return c.setBoardArchived(c.GetBoardRoute(boardID) + "/unarchive")
+}
+
+func (c *Client) setBoardArchived(route string) (*model.Board, *Response) {
+	r, err := c.DoAPIPost(route, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
 func (c *Client) GetBoard(boardID, readToken string) (*model.Board, *Response) {
 	url := c.GetBoardRoute(boardID)
 	if readToken != "" {
@@ -577,6 +937,21 @@ func (c *Client) GetBoard(boardID, readToken string) (*model.Board, *Response) {
 	return model.BoardFromJSON(r.Body), BuildResponse(r)
 }
 
+// GetBoardIfNoneMatch returns a board, or a nil board and a 304 status code
+// if it still matches the given ETag.
+func (c *Client) GetBoardIfNoneMatch(boardID, etag string) (*model.Board, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID), etag)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	if r.StatusCode == http.StatusNotModified {
+		return nil, BuildResponse(r)
+	}
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
 func (c *Client) GetBoardMetadata(boardID, readToken string) (*model.BoardMetadata, *Response) {
 	url := c.GetBoardMetadataRoute(boardID)
 	if readToken != "" {
@@ -592,6 +967,36 @@ func (c *Client) GetBoardMetadata(boardID, readToken string) (*model.BoardMetada
 	return model.BoardMetadataFromJSON(r.Body), BuildResponse(r)
 }
 
+func (c *Client) GetBoardAuditRecords(boardID string, opts model.QueryAuditRecordsOptions) (*model.AuditRecordsList, *Response) {
+	query := url.Values{}
+	if opts.UserID != "" {
+		query.Set("user_id", opts.UserID)
+	}
+	if opts.Event != "" {
+		query.Set("event", opts.Event)
+	}
+	if opts.Since != 0 {
+		query.Set("since", strconv.FormatInt(opts.Since, 10))
+	}
+	if opts.Until != 0 {
+		query.Set("until", strconv.FormatInt(opts.Until, 10))
+	}
+	query.Set("page", strconv.Itoa(opts.Page))
+	query.Set("per_page", strconv.Itoa(opts.PerPage))
+
+	r, err := c.DoAPIGet(c.GetBoardAuditRoute(boardID)+"?"+query.Encode(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var records *model.AuditRecordsList
+	if jsonErr := json.NewDecoder(r.Body).Decode(&records); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return records, BuildResponse(r)
+}
+
 func (c *Client) GetBoardsForTeam(teamID string) ([]*model.Board, *Response) {
 	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards", "")
 	if err != nil {
@@ -602,6 +1007,18 @@ func (c *Client) GetBoardsForTeam(teamID string) ([]*model.Board, *Response) {
 	return model.BoardsFromJSON(r.Body), BuildResponse(r)
 }
 
+// GetBoardsForTeamWithArchived returns the boards of a team including the
+// archived ones.
+func (c *Client) GetBoardsForTeamWithArchived(teamID string) ([]*model.Board, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards?include_archived=true", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
 func (c *Client) SearchBoardsForTeam(teamID, term string) ([]*model.Board, *Response) {
 	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards/search?q="+term, "")
 	if err != nil {
@@ -706,6 +1123,80 @@ func (c *Client) TeamUploadFile(teamID, boardID string, data io.Reader) (*api.Fi
 	return fileUploadResponse, BuildResponse(r)
 }
 
+func (c *Client) GetFileRoute(teamID, boardID, fileID string) string {
+	return fmt.Sprintf("/files/teams/%s/%s/%s", teamID, boardID, fileID)
+}
+
+// GetFile writes the content of an uploaded file to w. The size is empty
+// to get the original file, or thumb or preview to get the thumbnail or the
+// preview of an image.
+func (c *Client) GetFile(teamID, boardID, fileID, size string, w io.Writer) *Response {
+	route := c.GetFileRoute(teamID, boardID, fileID)
+	if size != "" {
+		route += "?size=" + url.QueryEscape(size)
+	}
+
+	r, err := c.DoAPIGet(route, "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	if _, err := io.Copy(w, r.Body); err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	return BuildResponse(r)
+}
+
+func (c *Client) GetAttachmentsRoute(boardID string) string {
+	return fmt.Sprintf("%s/attachments", c.GetBoardRoute(boardID))
+}
+
+// GetBoardAttachments returns the files attached to a board, or to a card
+// if parentID isn't empty.
+func (c *Client) GetBoardAttachments(boardID, parentID string) ([]model.Attachment, *Response) {
+	route := c.GetAttachmentsRoute(boardID)
+	if parentID != "" {
+		route += "?parent_id=" + url.QueryEscape(parentID)
+	}
+
+	r, err := c.DoAPIGet(route, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var attachments []model.Attachment
+	if jsonErr := json.NewDecoder(r.Body).Decode(&attachments); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return attachments, BuildResponse(r)
+}
+
+func (c *Client) DeleteAttachment(boardID, fileID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetAttachmentsRoute(boardID)+"/"+fileID, "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetTeamStorageUsage(teamID string) (*model.TeamStorageUsage, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/storage", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var usage *model.TeamStorageUsage
+	if jsonErr := json.NewDecoder(r.Body).Decode(&usage); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return usage, BuildResponse(r)
+}
+
 func (c *Client) GetSubscriptionsRoute() string {
 	return "/subscriptions"
 }
@@ -778,6 +1269,53 @@ func (c *Client) ExportBoardArchive(boardID string) ([]byte, *Response) {
 	return buf, BuildResponse(r)
 }
 
+// ExportTeamArchive writes the archive of the boards of the current user in a
+// team to w. Only the filters of opts are used, and it may be nil.
+func (c *Client) ExportTeamArchive(teamID string, opts *model.ExportArchiveOptions, w io.Writer) *Response {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/archive/export"+exportArchiveQuery(opts), "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	if _, err := io.Copy(w, r.Body); err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	return BuildResponse(r)
+}
+
+// exportArchiveQuery returns the query string for the filters of an archive
+// export.
+func exportArchiveQuery(opts *model.ExportArchiveOptions) string {
+	if opts == nil {
+		return ""
+	}
+
+	query := url.Values{}
+	if opts.ModifiedSince != 0 {
+		query.Set("since", strconv.FormatInt(opts.ModifiedSince, 10))
+	}
+	if opts.Files != "" {
+		query.Set("files", string(opts.Files))
+	}
+	flags := map[string]bool{
+		"exclude_comments": opts.ExcludeComments,
+		"include_deleted":  opts.IncludeDeleted,
+		"include_members":  opts.IncludeMembers,
+		"include_history":  opts.IncludeHistory,
+	}
+	for key, value := range flags {
+		if value {
+			query.Set(key, api.True)
+		}
+	}
+
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
 func (c *Client) ImportArchive(teamID string, data io.Reader) *Response {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
@@ -803,6 +1341,248 @@ func (c *Client) ImportArchive(teamID string, data io.Reader) *Response {
 	return BuildResponse(r)
 }
 
+// ImportExternal imports the export of another tool, such as Trello, in a
+// team.
+func (c *Client) ImportExternal(teamID, format string, data io.Reader) (*model.ImportReport, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file")
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetTeamRoute(teamID)+"/archive/import/"+format, body, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var report *model.ImportReport
+	if jsonErr := json.NewDecoder(r.Body).Decode(&report); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return report, BuildResponse(r)
+}
+
+func (c *Client) GetImportJobsRoute(teamID string) string {
+	return c.GetTeamRoute(teamID) + "/archive/import-jobs"
+}
+
+// CreateImportJob starts importing an archive in the background, or only
+// validating it in a dry run.
+func (c *Client) CreateImportJob(teamID string, data io.Reader, dryRun bool) (*model.ImportJob, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file")
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	route := c.GetImportJobsRoute(teamID)
+	if dryRun {
+		route += "?dry_run=true"
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+route, body, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.ImportJobFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetImportJobs(teamID string) ([]*model.ImportJob, *Response) {
+	r, err := c.DoAPIGet(c.GetImportJobsRoute(teamID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var jobs []*model.ImportJob
+	if jsonErr := json.NewDecoder(r.Body).Decode(&jobs); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return jobs, BuildResponse(r)
+}
+
+func (c *Client) GetImportJob(teamID, jobID string) (*model.ImportJob, *Response) {
+	r, err := c.DoAPIGet(c.GetImportJobsRoute(teamID)+"/"+jobID, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.ImportJobFromJSON(r.Body), BuildResponse(r)
+}
+
+// ResumeImportJob resumes a failed or interrupted import job.
+func (c *Client) ResumeImportJob(teamID, jobID string) (*model.ImportJob, *Response) {
+	r, err := c.DoAPIPost(c.GetImportJobsRoute(teamID)+"/"+jobID+"/resume", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.ImportJobFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetTemplateRoute(templateID string) string {
+	return fmt.Sprintf("/templates/%s", templateID)
+}
+
+func (c *Client) GetTemplateLibrary(teamID, category, tag string) ([]*model.Template, *Response) {
+	query := url.Values{}
+	if category != "" {
+		query.Set("category", category)
+	}
+	if tag != "" {
+		query.Set("tag", tag)
+	}
+
+	route := c.GetTeamRoute(teamID) + "/templates/library"
+	if len(query) != 0 {
+		route += "?" + query.Encode()
+	}
+
+	r, err := c.DoAPIGet(route, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.TemplatesFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetTemplate(templateID string) (*model.Template, *Response) {
+	r, err := c.DoAPIGet(c.GetTemplateRoute(templateID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.TemplateFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) PublishTemplate(boardID string, opts *model.PublishTemplateOptions) (*model.Template, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/publish", toJSON(opts))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.TemplateFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) PatchTemplate(templateID string, patch *model.TemplatePatch) (*model.Template, *Response) {
+	r, err := c.DoAPIPatch(c.GetTemplateRoute(templateID), toJSON(patch))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.TemplateFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) UnpublishTemplate(templateID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetTemplateRoute(templateID), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) PropagateTemplate(templateID string) (*model.TemplatePropagationReport, *Response) {
+	r, err := c.DoAPIPost(c.GetTemplateRoute(templateID)+"/propagate", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var report *model.TemplatePropagationReport
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return report, BuildResponse(r)
+}
+
+func (c *Client) UpdateBoardFromTemplate(boardID string) (*model.TemplateBoardUpdate, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/template/update", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var update *model.TemplateBoardUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return update, BuildResponse(r)
+}
+
+func (c *Client) ExportTemplate(templateID string) ([]byte, *Response) {
+	r, err := c.DoAPIGet(c.GetTemplateRoute(templateID)+"/export", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	buf, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return buf, BuildResponse(r)
+}
+
+func (c *Client) ImportTemplates(teamID string, data io.Reader, category string, tags []string) ([]*model.Template, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file")
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+	if err = writer.WriteField("category", category); err != nil {
+		return nil, &Response{Error: err}
+	}
+	if err = writer.WriteField("tags", strings.Join(tags, ",")); err != nil {
+		return nil, &Response{Error: err}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetTeamRoute(teamID)+"/templates/import", body, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.TemplatesFromJSON(r.Body), BuildResponse(r)
+}
+
 func (c *Client) GetLimits() (*model.BoardsCloudLimits, *Response) {
 	r, err := c.DoAPIGet("/limits", "")
 	if err != nil {
@@ -818,3 +1598,88 @@ func (c *Client) GetLimits() (*model.BoardsCloudLimits, *Response) {
 
 	return limits, BuildResponse(r)
 }
+
+// Feeds
+
+func (c *Client) GetFeedTokenRoute() string {
+	return "/users/me/feed-token"
+}
+
+func (c *Client) GetFeedToken() (*model.FeedToken, *Response) {
+	r, err := c.DoAPIGet(c.GetFeedTokenRoute(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var feedToken *model.FeedToken
+	if jsonErr := json.NewDecoder(r.Body).Decode(&feedToken); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return feedToken, BuildResponse(r)
+}
+
+func (c *Client) RegenerateFeedToken() (*model.FeedToken, *Response) {
+	r, err := c.DoAPIPost(c.GetFeedTokenRoute(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var feedToken *model.FeedToken
+	if jsonErr := json.NewDecoder(r.Body).Decode(&feedToken); jsonErr != nil {
+		return nil, BuildErrorResponse(r, jsonErr)
+	}
+	return feedToken, BuildResponse(r)
+}
+
+func (c *Client) RevokeFeedToken() (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetFeedTokenRoute(), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+// GetBoardFeedURL returns the URL of the calendar feed of a board, outside
+// the API path. If propertyID is empty, the first date property is used.
+func (c *Client) GetBoardFeedURL(boardID, token, propertyID string) string {
+	query := url.Values{}
+	query.Set("token", token)
+	if propertyID != "" {
+		query.Set("property", propertyID)
+	}
+	return fmt.Sprintf("%s/feeds/boards/%s.ics?%s", c.URL, boardID, query.Encode())
+}
+
+// GetUserFeedURL returns the URL of the calendar feed of the cards assigned
+// to a user, outside the API path.
+func (c *Client) GetUserFeedURL(userID, token string) string {
+	query := url.Values{}
+	query.Set("token", token)
+	return fmt.Sprintf("%s/feeds/users/%s.ics?%s", c.URL, userID, query.Encode())
+}
+
+func (c *Client) GetBoardFeed(boardID, token, propertyID string) ([]byte, *Response) {
+	return c.getFeed(c.GetBoardFeedURL(boardID, token, propertyID))
+}
+
+func (c *Client) GetUserFeed(userID, token string) ([]byte, *Response) {
+	return c.getFeed(c.GetUserFeedURL(userID, token))
+}
+
+func (c *Client) getFeed(feedURL string) ([]byte, *Response) {
+	r, err := c.doAPIRequestReader(http.MethodGet, feedURL, nil, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	buf, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return buf, BuildResponse(r)
+}
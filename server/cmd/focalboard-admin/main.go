@@ -0,0 +1,449 @@
+// Command line tool to administer a Focalboard server running in local mode.
+//
+// The tool talks to the admin API served on the local mode unix socket, so
+// it must run on the same host as the server, as a user allowed to access
+// the socket.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mattermost/focalboard/server/api"
+	"github.com/mattermost/focalboard/server/client"
+	"github.com/mattermost/focalboard/server/model"
+)
+
+const defaultSocketLocation = "/var/tmp/focalboard_local.socket"
+
+var errUsage = errors.New("invalid arguments")
+
+type command struct {
+	usage string
+	run   func(c *client.Client, args []string) error
+}
+
+var commands = map[string]command{
+	"users list":                {"users list", listUsers},
+	"users create":              {"users create <username> <email> <password>", createUser},
+	"users set-password":        {"users set-password <username> <password>", setPassword},
+	"users deactivate":          {"users deactivate <username>", deactivateUser},
+	"users revoke-sessions":     {"users revoke-sessions <username>", revokeSessions},
+	"teams list":                {"teams list", listTeams},
+	"boards list":               {"boards list <teamID>", listBoards},
+	"boards transfer-ownership": {"boards transfer-ownership <boardID> <username>", transferBoardOwnership},
+	"data-retention run":        {"data-retention run [days]", runDataRetention},
+	"files cleanup":             {"files cleanup", cleanUpOrphanFiles},
+	"export":                    {"export [-since time] [-exclude-comments] [-files include|exclude|reference] [-include-deleted] [-include-members] [-include-history] <teamID> <file>", exportTeam},
+	"import":                    {"import <teamID> <file> <owner username>", importArchive},
+	"backup":                    {"backup <file>", backup},
+	"restore":                   {"restore <file>", restore},
+	"settings":                  {"settings", showSettings},
+	"audit":                     {"audit [-user userID] [-team teamID] [-board boardID] [-event event] [-page n] [-per-page n]", listAuditRecords},
+}
+
+func main() {
+	socket := flag.String("socket", defaultSocketLocation, "location of the local mode socket of the server")
+	flag.Usage = usage
+	flag.Parse()
+
+	cmd, args, ok := findCommand(flag.Args())
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	err := cmd.run(client.NewLocalClient(*socket), args)
+	if errors.Is(err, errUsage) {
+		fmt.Fprintf(os.Stderr, "usage: focalboard-admin %s\n", cmd.usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// findCommand matches the longest command name with the first arguments.
+func findCommand(args []string) (command, []string, bool) {
+	for n := 2; n > 0; n-- {
+		if len(args) < n {
+			continue
+		}
+		name := args[0]
+		if n == 2 {
+			name += " " + args[1]
+		}
+		if cmd, ok := commands[name]; ok {
+			return cmd, args[n:], true
+		}
+	}
+	return command{}, nil, false
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: focalboard-admin [-socket path] <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nflags:")
+	flag.PrintDefaults()
+}
+
+func responseError(resp *client.Response) error {
+	if resp.Error == nil {
+		return nil
+	}
+	if resp.StatusCode != 0 {
+		return fmt.Errorf("%w (status %d)", resp.Error, resp.StatusCode)
+	}
+	return resp.Error
+}
+
+func newTable() *tabwriter.Writer {
+	return tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+}
+
+func formatMillis(millis int64) string {
+	if millis == 0 {
+		return "-"
+	}
+	return time.UnixMilli(millis).Format(time.RFC3339)
+}
+
+func listUsers(c *client.Client, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	users, resp := c.AdminGetUsers()
+	if err := responseError(resp); err != nil {
+		return err
+	}
+
+	table := newTable()
+	fmt.Fprintln(table, "ID\tUSERNAME\tEMAIL\tCREATED\tDEACTIVATED")
+	for _, user := range users {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n",
+			user.ID, user.Username, user.Email, formatMillis(user.CreateAt), formatMillis(user.DeleteAt))
+	}
+	return table.Flush()
+}
+
+func createUser(c *client.Client, args []string) error {
+	if len(args) != 3 {
+		return errUsage
+	}
+	user, resp := c.AdminCreateUser(&api.AdminCreateUserData{
+		Username: args[0],
+		Email:    args[1],
+		Password: args[2],
+	})
+	if err := responseError(resp); err != nil {
+		return err
+	}
+	fmt.Printf("created user %s (%s)\n", user.Username, user.ID)
+	return nil
+}
+
+func setPassword(c *client.Client, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	if err := responseError(c.AdminSetPassword(args[0], args[1])); err != nil {
+		return err
+	}
+	fmt.Printf("password of %s updated\n", args[0])
+	return nil
+}
+
+func deactivateUser(c *client.Client, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	if err := responseError(c.AdminDeactivateUser(args[0])); err != nil {
+		return err
+	}
+	fmt.Printf("user %s deactivated\n", args[0])
+	return nil
+}
+
+func revokeSessions(c *client.Client, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	revoked, resp := c.AdminRevokeUserSessions(args[0])
+	if err := responseError(resp); err != nil {
+		return err
+	}
+	fmt.Printf("%d session(s) of %s revoked\n", revoked, args[0])
+	return nil
+}
+
+func listTeams(c *client.Client, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	teams, resp := c.AdminGetTeams()
+	if err := responseError(resp); err != nil {
+		return err
+	}
+
+	table := newTable()
+	fmt.Fprintln(table, "ID\tTITLE\tUPDATED")
+	for _, team := range teams {
+		fmt.Fprintf(table, "%s\t%s\t%s\n", team.ID, team.Title, formatMillis(team.UpdateAt))
+	}
+	return table.Flush()
+}
+
+func listBoards(c *client.Client, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	boards, resp := c.AdminGetBoards(args[0])
+	if err := responseError(resp); err != nil {
+		return err
+	}
+
+	table := newTable()
+	fmt.Fprintln(table, "ID\tTITLE\tTYPE\tTEMPLATE\tOWNER\tUPDATED")
+	for _, board := range boards {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%t\t%s\t%s\n",
+			board.ID, board.Title, board.Type, board.IsTemplate, board.CreatedBy, formatMillis(board.UpdateAt))
+	}
+	return table.Flush()
+}
+
+func transferBoardOwnership(c *client.Client, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	board, resp := c.AdminTransferBoardOwnership(args[0], args[1])
+	if err := responseError(resp); err != nil {
+		return err
+	}
+	fmt.Printf("board %s is now owned by %s\n", board.ID, args[1])
+	return nil
+}
+
+func runDataRetention(c *client.Client, args []string) error {
+	if len(args) > 1 {
+		return errUsage
+	}
+	days := 0
+	if len(args) == 1 {
+		if _, err := fmt.Sscan(args[0], &days); err != nil || days < 1 {
+			return errUsage
+		}
+	}
+	deleted, resp := c.AdminRunDataRetention(days)
+	if err := responseError(resp); err != nil {
+		return err
+	}
+	fmt.Printf("%d record(s) deleted\n", deleted)
+	return nil
+}
+
+func cleanUpOrphanFiles(c *client.Client, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	result, resp := c.AdminCleanUpOrphanFiles()
+	if err := responseError(resp); err != nil {
+		return err
+	}
+	fmt.Printf("%d file record(s) and %d file(s) deleted, %d bytes freed\n", result.FileInfos, result.Files, result.Bytes)
+	return nil
+}
+
+func exportTeam(c *client.Client, args []string) error {
+	var opts model.ExportArchiveOptions
+	var since, files string
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&since, "since", "", "")
+	flags.StringVar(&files, "files", "", "")
+	flags.BoolVar(&opts.ExcludeComments, "exclude-comments", false, "")
+	flags.BoolVar(&opts.IncludeDeleted, "include-deleted", false, "")
+	flags.BoolVar(&opts.IncludeMembers, "include-members", false, "")
+	flags.BoolVar(&opts.IncludeHistory, "include-history", false, "")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 2 {
+		return errUsage
+	}
+
+	var err error
+	if opts.ModifiedSince, err = parseTime(since); err != nil {
+		return err
+	}
+	if opts.Files, err = model.ExportFilesModeFromString(files); err != nil {
+		return err
+	}
+
+	teamID, filename := flags.Arg(0), flags.Arg(1)
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := responseError(c.AdminExportTeam(teamID, &opts, f)); err != nil {
+		f.Close()
+		os.Remove(filename)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("team %s exported to %s\n", teamID, filename)
+	return nil
+}
+
+// parseTime reads a time given either in RFC 3339 format or in milliseconds
+// since the epoch, and returns it in milliseconds.
+func parseTime(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UnixMilli(), nil
+	}
+	var millis int64
+	if _, err := fmt.Sscan(s, &millis); err != nil {
+		return 0, fmt.Errorf("invalid time %q, must be RFC 3339 or milliseconds since the epoch: %w", s, err)
+	}
+	return millis, nil
+}
+
+func importArchive(c *client.Client, args []string) error {
+	if len(args) != 3 {
+		return errUsage
+	}
+	f, err := os.Open(args[1])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := responseError(c.AdminImport(args[0], args[2], f)); err != nil {
+		return err
+	}
+	fmt.Printf("%s imported in team %s\n", args[1], args[0])
+	return nil
+}
+
+func backup(c *client.Client, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	f, err := os.Create(args[0])
+	if err != nil {
+		return err
+	}
+	if err := responseError(c.AdminBackup(f)); err != nil {
+		f.Close()
+		os.Remove(args[0])
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("backup written to %s\n", args[0])
+	return nil
+}
+
+func restore(c *client.Client, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	manifest, resp := c.AdminRestore(f)
+	if err := responseError(resp); err != nil {
+		return err
+	}
+
+	w := newTable()
+	fmt.Fprintln(w, "TABLE\tROWS")
+	tables := make([]string, 0, len(manifest.Tables))
+	for table := range manifest.Tables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Fprintf(w, "%s\t%d\n", table, manifest.Tables[table])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("backup of %s restored with %d file(s), restart the server to use it\n", formatMillis(manifest.CreateAt), manifest.Files)
+	return nil
+}
+
+func showSettings(c *client.Client, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	settings, resp := c.AdminGetSystemSettings()
+	if err := responseError(resp); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	table := newTable()
+	fmt.Fprintln(table, "NAME\tVALUE")
+	for _, name := range names {
+		fmt.Fprintf(table, "%s\t%s\n", name, settings[name])
+	}
+	return table.Flush()
+}
+
+func listAuditRecords(c *client.Client, args []string) error {
+	var opts model.QueryAuditRecordsOptions
+	flags := flag.NewFlagSet("audit", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&opts.UserID, "user", "", "")
+	flags.StringVar(&opts.TeamID, "team", "", "")
+	flags.StringVar(&opts.BoardID, "board", "", "")
+	flags.StringVar(&opts.Event, "event", "", "")
+	flags.IntVar(&opts.Page, "page", 0, "")
+	flags.IntVar(&opts.PerPage, "per-page", model.AuditRecordsDefaultPerPage, "")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+		return errUsage
+	}
+
+	list, resp := c.AdminGetAuditRecords(opts)
+	if err := responseError(resp); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	for _, record := range list.Items {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	if list.HasNext {
+		fmt.Fprintf(os.Stderr, "more records available with -page %d\n", opts.Page+1)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package integrationtests
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/api"
+	"github.com/mattermost/focalboard/server/client"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestHelperWithLocalAdmin(t *testing.T) (*TestHelper, *client.Client) {
+	return startTestHelperWithLocalAdmin(t, true)
+}
+
+// startTestHelperWithLocalAdmin starts a server with the local mode enabled,
+// and registers the users of the helper if initBasic is true.
+func startTestHelperWithLocalAdmin(t *testing.T, initBasic bool) (*TestHelper, *client.Client) {
+	th := SetupTestHelper(t)
+	socket := filepath.Join(t.TempDir(), "focalboard_local.socket")
+	th.Server.Config().EnableLocalMode = true
+	th.Server.Config().LocalModeSocketLocation = socket
+	if initBasic {
+		th.InitBasic()
+	} else {
+		th.Start()
+	}
+
+	// the local mode server starts after the web server
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(socket)
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	return th, client.NewLocalClient(socket)
+}
+
+func TestAdminAPI(t *testing.T) {
+	th, admin := setupTestHelperWithLocalAdmin(t)
+	defer th.TearDown()
+
+	t.Run("only served on the local socket", func(t *testing.T) {
+		r, err := th.Client.DoAPIGet("/admin/users", "")
+		require.Error(t, err)
+		require.NotEqual(t, http.StatusOK, r.StatusCode)
+	})
+
+	t.Run("users", func(t *testing.T) {
+		user, resp := admin.AdminCreateUser(&api.AdminCreateUserData{
+			Username: "local-user",
+			Email:    "local-user@sample.com",
+			Password: password,
+		})
+		th.CheckOK(resp)
+		require.Equal(t, "local-user", user.Username)
+		require.Equal(t, "local-user@sample.com", user.Email)
+
+		users, resp := admin.AdminGetUsers()
+		th.CheckOK(resp)
+		usernames := make([]string, len(users))
+		for i := range users {
+			usernames[i] = users[i].Username
+		}
+		require.Contains(t, usernames, "local-user")
+		require.Contains(t, usernames, th.GetUser1().Username)
+
+		th.CheckOK(admin.AdminSetPassword("local-user", "new"+password))
+		c := client.NewClient(th.Server.Config().ServerRoot, "")
+		th.Login(c, "local-user", "new"+password)
+
+		revoked, resp := admin.AdminRevokeUserSessions("local-user")
+		th.CheckOK(resp)
+		require.Equal(t, int64(1), revoked)
+		_, resp = c.GetMe()
+		th.CheckUnauthorized(resp)
+
+		th.CheckOK(admin.AdminDeactivateUser("local-user"))
+		_, resp = c.Login(&model.LoginRequest{Type: "normal", Username: "local-user", Password: "new" + password})
+		require.Error(t, resp.Error)
+
+		th.CheckNotFound(admin.AdminDeactivateUser("local-user"))
+		th.CheckNotFound(admin.AdminSetPassword("unknown-user", password))
+	})
+
+	t.Run("teams and boards", func(t *testing.T) {
+		board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		teams, resp := admin.AdminGetTeams()
+		th.CheckOK(resp)
+		require.NotNil(t, teams)
+
+		boards, resp := admin.AdminGetBoards(testTeamID)
+		th.CheckOK(resp)
+		ids := make([]string, len(boards))
+		for i := range boards {
+			ids[i] = boards[i].ID
+		}
+		require.Contains(t, ids, board.ID)
+
+		transferred, resp := admin.AdminTransferBoardOwnership(board.ID, th.GetUser2().Username)
+		th.CheckOK(resp)
+		require.Equal(t, th.GetUser2().ID, transferred.CreatedBy)
+
+		// the new owner is an admin of the board
+		member, err := th.Server.App().GetMemberForBoard(board.ID, th.GetUser2().ID)
+		require.NoError(t, err)
+		require.True(t, member.SchemeAdmin)
+
+		_, resp = admin.AdminTransferBoardOwnership(board.ID, "unknown-user")
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("export and import", func(t *testing.T) {
+		// boards without blocks can't be imported, so the exported team
+		// only holds a board with a card
+		board := th.CreateBoard("export-team-id", model.BoardTypeOpen)
+		_, resp := th.Client.InsertBlocks(board.ID, []model.Block{{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			Title:    "card",
+			CreateAt: utils.GetMillis(),
+			UpdateAt: utils.GetMillis(),
+		}})
+		th.CheckOK(resp)
+
+		var archive bytes.Buffer
+		th.CheckOK(admin.AdminExportTeam("export-team-id", nil, &archive))
+		require.NotZero(t, archive.Len())
+
+		th.CheckBadRequest(admin.AdminImport("other-team-id", "", bytes.NewReader(archive.Bytes())))
+		th.CheckOK(admin.AdminImport("other-team-id", th.GetUser1().Username, bytes.NewReader(archive.Bytes())))
+
+		boards, resp := admin.AdminGetBoards("other-team-id")
+		th.CheckOK(resp)
+		titles := make([]string, len(boards))
+		for i := range boards {
+			titles[i] = boards[i].Title
+		}
+		require.Contains(t, titles, board.Title)
+	})
+
+	t.Run("data retention", func(t *testing.T) {
+		deleted, resp := admin.AdminRunDataRetention(30)
+		th.CheckOK(resp)
+		require.Zero(t, deleted)
+
+		// the retention period defaults to the configured one, which isn't set
+		_, resp = admin.AdminRunDataRetention(0)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("orphan files cleanup", func(t *testing.T) {
+		// the files uploaded by the tests are within the grace period
+		result, resp := admin.AdminCleanUpOrphanFiles()
+		th.CheckOK(resp)
+		require.Zero(t, result.FileInfos)
+	})
+
+	t.Run("settings and audit", func(t *testing.T) {
+		settings, resp := admin.AdminGetSystemSettings()
+		th.CheckOK(resp)
+		require.NotEmpty(t, settings)
+
+		records, resp := admin.AdminGetAuditRecords(model.QueryAuditRecordsOptions{Event: "adminCreateUser"})
+		th.CheckOK(resp)
+		require.NotEmpty(t, records.Items)
+	})
+}
+
+func TestAdminBackupRestore(t *testing.T) {
+	th, admin := setupTestHelperWithLocalAdmin(t)
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	card := model.Block{
+		ID:       utils.NewID(utils.IDTypeCard),
+		BoardID:  board.ID,
+		ParentID: board.ID,
+		Type:     model.TypeCard,
+		Title:    "card",
+		CreateAt: utils.GetMillis(),
+		UpdateAt: utils.GetMillis(),
+	}
+	blocks, resp := th.Client.InsertBlocks(board.ID, []model.Block{card})
+	th.CheckOK(resp)
+	file, resp := th.Client.TeamUploadFile(testTeamID, board.ID, bytes.NewBufferString("file content"))
+	th.CheckOK(resp)
+
+	var backup bytes.Buffer
+	th.CheckOK(admin.AdminBackup(&backup))
+
+	// the server has users, so the backup can't be restored into it
+	_, resp = admin.AdminRestore(bytes.NewReader(backup.Bytes()))
+	th.CheckConflict(resp)
+
+	user1 := th.GetUser1()
+	th.TearDown()
+
+	th, admin = startTestHelperWithLocalAdmin(t, false)
+	defer th.TearDown()
+
+	_, resp = admin.AdminRestore(bytes.NewBufferString("not a backup"))
+	th.CheckBadRequest(resp)
+
+	manifest, resp := admin.AdminRestore(bytes.NewReader(backup.Bytes()))
+	th.CheckOK(resp)
+	require.Equal(t, model.BackupFormatVersion, manifest.Version)
+	require.Equal(t, int64(2), manifest.Tables["users"])
+	// the files directory is shared with the other tests
+	require.GreaterOrEqual(t, manifest.Files, 1)
+	require.NotContains(t, manifest.Tables, "sessions")
+
+	// sessions aren't restored, but the users can log in again
+	th.Login(th.Client, user1Username, password)
+	me, resp := th.Client.GetMe()
+	th.CheckOK(resp)
+	require.Equal(t, user1.ID, me.ID)
+
+	restoredBoard, resp := th.Client.GetBoard(board.ID, "")
+	th.CheckOK(resp)
+	require.Equal(t, board.Title, restoredBoard.Title)
+
+	restoredBlocks, resp := th.Client.GetAllBlocksForBoard(board.ID)
+	th.CheckOK(resp)
+	require.Len(t, restoredBlocks, 1)
+	require.Equal(t, blocks[0].ID, restoredBlocks[0].ID)
+
+	content, err := os.ReadFile(filepath.Join(th.Server.Config().FilesPath, testTeamID, board.ID, file.FileID))
+	require.NoError(t, err)
+	require.Equal(t, "file content", string(content))
+
+	// the server isn't empty anymore
+	_, resp = admin.AdminRestore(bytes.NewReader(backup.Bytes()))
+	th.CheckConflict(resp)
+}
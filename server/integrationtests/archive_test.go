@@ -0,0 +1,194 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveCard(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	now := utils.GetMillis()
+	card := model.Block{
+		ID:       "card",
+		BoardID:  board.ID,
+		ParentID: board.ID,
+		Type:     model.TypeCard,
+		CreateAt: now,
+		UpdateAt: now,
+	}
+	newBlocks, resp := th.Client.InsertBlocks(board.ID, []model.Block{card})
+	th.CheckOK(resp)
+	cardID := newBlocks[0].ID
+	text := model.Block{
+		ID:       "text",
+		BoardID:  board.ID,
+		ParentID: cardID,
+		Type:     model.TypeText,
+		CreateAt: now,
+		UpdateAt: now,
+	}
+	newBlocks, resp = th.Client.InsertBlocks(board.ID, []model.Block{text})
+	th.CheckOK(resp)
+	textID := newBlocks[0].ID
+
+	blockIDs := func(blocks []model.Block) []string {
+		ids := make([]string, len(blocks))
+		for i := range blocks {
+			ids[i] = blocks[i].ID
+		}
+		return ids
+	}
+
+	t.Run("archived cards are hidden from the board", func(t *testing.T) {
+		archived, resp := th.Client.ArchiveCard(board.ID, cardID)
+		th.CheckOK(resp)
+		require.NotZero(t, archived.ArchiveAt)
+		require.Zero(t, archived.DeleteAt)
+
+		blocks, resp := th.Client.GetAllBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		require.NotContains(t, blockIDs(blocks), cardID)
+		require.NotContains(t, blockIDs(blocks), textID)
+
+		blocks, resp = th.Client.GetAllBlocksForBoardWithArchived(board.ID)
+		th.CheckOK(resp)
+		require.Contains(t, blockIDs(blocks), cardID)
+		require.Contains(t, blockIDs(blocks), textID)
+
+		// archiving again keeps the archived time
+		again, resp := th.Client.ArchiveCard(board.ID, cardID)
+		th.CheckOK(resp)
+		require.Equal(t, archived.ArchiveAt, again.ArchiveAt)
+	})
+
+	t.Run("unarchived cards are shown again", func(t *testing.T) {
+		restored, resp := th.Client.UnarchiveCard(board.ID, cardID)
+		th.CheckOK(resp)
+		require.Zero(t, restored.ArchiveAt)
+
+		blocks, resp := th.Client.GetAllBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		require.Contains(t, blockIDs(blocks), cardID)
+		require.Contains(t, blockIDs(blocks), textID)
+	})
+
+	t.Run("only cards can be archived", func(t *testing.T) {
+		_, resp := th.Client.ArchiveCard(board.ID, textID)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("card of another board", func(t *testing.T) {
+		otherBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		_, resp := th.Client.ArchiveCard(otherBoard.ID, cardID)
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("archiving is audited", func(t *testing.T) {
+		// records are persisted asynchronously
+		for _, event := range []string{"archiveCard", "unarchiveCard"} {
+			opts := model.QueryAuditRecordsOptions{Event: event}
+			require.Eventually(t, func() bool {
+				records, resp := th.Client.GetBoardAuditRecords(board.ID, opts)
+				th.CheckOK(resp)
+				return len(records.Items) > 0
+			}, 5*time.Second, 50*time.Millisecond)
+		}
+	})
+}
+
+func TestArchiveBoard(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	title := "archived project"
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{Title: &title})
+	th.CheckOK(resp)
+
+	boardIDs := func(boards []*model.Board) []string {
+		ids := make([]string, len(boards))
+		for i := range boards {
+			ids[i] = boards[i].ID
+		}
+		return ids
+	}
+
+	t.Run("archived boards are hidden from the board list", func(t *testing.T) {
+		archived, resp := th.Client.ArchiveBoard(board.ID)
+		th.CheckOK(resp)
+		require.NotZero(t, archived.ArchiveAt)
+
+		boards, resp := th.Client.GetBoardsForTeam(testTeamID)
+		th.CheckOK(resp)
+		require.NotContains(t, boardIDs(boards), board.ID)
+
+		boards, resp = th.Client.GetBoardsForTeamWithArchived(testTeamID)
+		th.CheckOK(resp)
+		require.Contains(t, boardIDs(boards), board.ID)
+
+		// but they are still found and can be opened
+		boards, resp = th.Client.SearchBoardsForTeam(testTeamID, "archived")
+		th.CheckOK(resp)
+		require.Contains(t, boardIDs(boards), board.ID)
+
+		fetched, resp := th.Client.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+		require.Equal(t, archived.ArchiveAt, fetched.ArchiveAt)
+	})
+
+	t.Run("unarchived boards are listed again", func(t *testing.T) {
+		restored, resp := th.Client.UnarchiveBoard(board.ID)
+		th.CheckOK(resp)
+		require.Zero(t, restored.ArchiveAt)
+
+		boards, resp := th.Client.GetBoardsForTeam(testTeamID)
+		th.CheckOK(resp)
+		require.Contains(t, boardIDs(boards), board.ID)
+	})
+}
+
+func TestAutoArchive(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "done", "value": "Done", "autoArchiveDays": 30},
+			}},
+		},
+	})
+	th.CheckOK(resp)
+
+	now := utils.GetMillis()
+	newBlocks, resp := th.Client.InsertBlocks(board.ID, []model.Block{{
+		ID:       "card",
+		BoardID:  board.ID,
+		ParentID: board.ID,
+		Type:     model.TypeCard,
+		CreateAt: now,
+		UpdateAt: now,
+		Fields:   map[string]interface{}{"properties": map[string]interface{}{"status": "done"}},
+	}})
+	th.CheckOK(resp)
+
+	// the card has just been moved to Done
+	count, err := th.Server.App().RunAutoArchive()
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	blocks, resp := th.Client.GetAllBlocksForBoard(board.ID)
+	th.CheckOK(resp)
+	require.Len(t, blocks, 1)
+	require.Equal(t, newBlocks[0].ID, blocks[0].ID)
+	require.Zero(t, blocks[0].ArchiveAt)
+}
@@ -0,0 +1,61 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func TestGetBoardAuditRecords(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard("team-id", model.BoardTypeOpen)
+
+	title := "New title"
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{Title: &title})
+	th.CheckOK(resp)
+
+	t.Run("a non authenticated user should be rejected", func(t *testing.T) {
+		th.Logout(th.Client)
+		defer th.Login1()
+
+		records, resp := th.Client.GetBoardAuditRecords(board.ID, model.QueryAuditRecordsOptions{})
+		th.CheckUnauthorized(resp)
+		require.Nil(t, records)
+	})
+
+	t.Run("a user that is not a board admin should be rejected", func(t *testing.T) {
+		records, resp := th.Client2.GetBoardAuditRecords(board.ID, model.QueryAuditRecordsOptions{})
+		th.CheckForbidden(resp)
+		require.Nil(t, records)
+	})
+
+	t.Run("a board admin should get the board records", func(t *testing.T) {
+		opts := model.QueryAuditRecordsOptions{Event: "patchBoard"}
+
+		// records are persisted asynchronously
+		var records *model.AuditRecordsList
+		require.Eventually(t, func() bool {
+			records, resp = th.Client.GetBoardAuditRecords(board.ID, opts)
+			th.CheckOK(resp)
+			return len(records.Items) == 1
+		}, 5*time.Second, 50*time.Millisecond)
+
+		record := records.Items[0]
+		require.Equal(t, board.ID, record.BoardID)
+		require.Equal(t, th.GetUser1().ID, record.UserID)
+		require.Equal(t, "success", record.Status)
+		require.False(t, records.HasNext)
+	})
+
+	t.Run("records can be paginated", func(t *testing.T) {
+		records, resp := th.Client.GetBoardAuditRecords(board.ID, model.QueryAuditRecordsOptions{PerPage: 1})
+		th.CheckOK(resp)
+		require.Len(t, records.Items, 1)
+		require.True(t, records.HasNext)
+	})
+}
@@ -52,6 +52,149 @@ func TestGetBlocks(t *testing.T) {
 	require.Contains(t, blockIDs, blockID2)
 }
 
+func TestGetBlocksPaginated(t *testing.T) {
+	th := SetupTestHelperWithToken(t).Start()
+	defer th.TearDown()
+
+	board := th.CreateBoard("team-id", model.BoardTypeOpen)
+
+	newBlocks := []model.Block{}
+	for i := 0; i < 5; i++ {
+		newBlocks = append(newBlocks, model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			CreateAt: 1,
+			UpdateAt: 1,
+			Type:     model.TypeCard,
+		})
+	}
+	newBlocks, resp := th.Client.InsertBlocks(board.ID, newBlocks)
+	require.NoError(t, resp.Error)
+	require.Len(t, newBlocks, 5)
+
+	var since int64
+
+	t.Run("iterate all pages", func(t *testing.T) {
+		pages := 0
+		resp := th.Client.ForEachBlocksPage(board.ID, 0, 2, func(page *model.BlocksPage) error {
+			pages++
+			return nil
+		})
+		require.NoError(t, resp.Error)
+		require.Equal(t, 3, pages)
+
+		page, resp := th.Client.SyncBlocksForBoard(board.ID, 0, 2)
+		require.NoError(t, resp.Error)
+		require.Len(t, page.Blocks, 5)
+		require.Empty(t, page.Deleted)
+		since = page.NextSince(0)
+	})
+
+	t.Run("delta sync returns updated and deleted blocks", func(t *testing.T) {
+		time.Sleep(10 * time.Millisecond)
+
+		title := "Updated title"
+		_, resp := th.Client.PatchBlock(board.ID, newBlocks[0].ID, &model.BlockPatch{Title: &title})
+		require.NoError(t, resp.Error)
+		_, resp = th.Client.DeleteBlock(board.ID, newBlocks[1].ID)
+		require.NoError(t, resp.Error)
+
+		page, resp := th.Client.SyncBlocksForBoard(board.ID, since, 2)
+		require.NoError(t, resp.Error)
+		require.Len(t, page.Blocks, 1)
+		require.Equal(t, newBlocks[0].ID, page.Blocks[0].ID)
+		require.Equal(t, title, page.Blocks[0].Title)
+		require.Len(t, page.Deleted, 1)
+		require.Equal(t, newBlocks[1].ID, page.Deleted[0].ID)
+
+		// the changes already synced aren't returned again
+		since = page.NextSince(since)
+		page, resp = th.Client.SyncBlocksForBoard(board.ID, since, 2)
+		require.NoError(t, resp.Error)
+		require.Empty(t, page.Blocks)
+		require.Empty(t, page.Deleted)
+		require.Equal(t, since, page.NextSince(since))
+	})
+
+	t.Run("an invalid cursor should be rejected", func(t *testing.T) {
+		page, resp := th.Client.GetBlocksPage(board.ID, "invalid", 0, 2)
+		th.CheckBadRequest(resp)
+		require.Nil(t, page)
+	})
+}
+
+func TestBlockEtags(t *testing.T) {
+	th := SetupTestHelperWithToken(t).Start()
+	defer th.TearDown()
+
+	board := th.CreateBoard("team-id", model.BoardTypeOpen)
+
+	newBlocks, resp := th.Client.InsertBlocks(board.ID, []model.Block{{
+		ID:       utils.NewID(utils.IDTypeBlock),
+		BoardID:  board.ID,
+		CreateAt: 1,
+		UpdateAt: 1,
+		Type:     model.TypeCard,
+	}})
+	require.NoError(t, resp.Error)
+	require.Len(t, newBlocks, 1)
+	blockID := newBlocks[0].ID
+
+	blocks, resp := th.Client.GetBlocksForBoardIfNoneMatch(board.ID, "")
+	th.CheckOK(resp)
+	require.Len(t, blocks, 1)
+	listEtag := resp.Etag()
+	require.NotEmpty(t, listEtag)
+
+	t.Run("unmodified blocks should return not modified", func(t *testing.T) {
+		blocks, resp := th.Client.GetBlocksForBoardIfNoneMatch(board.ID, listEtag)
+		th.CheckNotModified(resp)
+		require.Nil(t, blocks)
+
+		blocks, resp = th.Client.GetBlocksForBoardIfNoneMatch(board.ID, "W/"+listEtag)
+		th.CheckNotModified(resp)
+		require.Nil(t, blocks)
+	})
+
+	r, err := th.Client.DoAPIGet(th.Client.GetBlocksRoute(board.ID)+"?block_id="+blockID, "")
+	require.NoError(t, err)
+	r.Body.Close()
+	blockEtag := r.Header.Get("ETag")
+	require.NotEmpty(t, blockEtag)
+
+	t.Run("a patch with a weak etag should fail", func(t *testing.T) {
+		title := "weak title"
+		_, resp := th.Client.PatchBlockIfMatch(board.ID, blockID, &model.BlockPatch{Title: &title}, "W/"+blockEtag)
+		th.CheckPreconditionFailed(resp)
+	})
+
+	t.Run("a patch with a matching etag should succeed", func(t *testing.T) {
+		time.Sleep(5 * time.Millisecond)
+
+		title := "new title"
+		_, resp := th.Client.PatchBlockIfMatch(board.ID, blockID, &model.BlockPatch{Title: &title}, blockEtag)
+		th.CheckOK(resp)
+		require.NotEqual(t, blockEtag, resp.Etag())
+	})
+
+	t.Run("a patch with a stale etag should fail", func(t *testing.T) {
+		title := "stale title"
+		_, resp := th.Client.PatchBlockIfMatch(board.ID, blockID, &model.BlockPatch{Title: &title}, blockEtag)
+		th.CheckPreconditionFailed(resp)
+
+		block, err := th.Server.App().GetBlockByID(blockID)
+		require.NoError(t, err)
+		require.Equal(t, "new title", block.Title)
+	})
+
+	t.Run("modified blocks should be returned", func(t *testing.T) {
+		blocks, resp := th.Client.GetBlocksForBoardIfNoneMatch(board.ID, listEtag)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 1)
+		require.NotEqual(t, listEtag, resp.Etag())
+	})
+}
+
 func TestPostBlock(t *testing.T) {
 	th := SetupTestHelperWithToken(t).Start()
 	defer th.TearDown()
@@ -257,6 +400,58 @@ func TestPatchBlock(t *testing.T) {
 		require.Equal(t, "test value 2", updatedBlock.Fields["test2"])
 		require.Equal(t, nil, updatedBlock.Fields["test3"])
 	})
+
+	t.Run("Patch a block with a stale update time", func(t *testing.T) {
+		blocks, resp := th.Client.GetBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 1)
+		currentUpdateAt := blocks[0].UpdateAt
+
+		staleUpdateAt := currentUpdateAt - 1
+		newTitle := "Stale title"
+		_, resp = th.Client.PatchBlock(board.ID, blockID, &model.BlockPatch{
+			Title:            &newTitle,
+			ExpectedUpdateAt: &staleUpdateAt,
+		})
+		th.CheckConflict(resp)
+
+		newTitle = "Fresh title"
+		_, resp = th.Client.PatchBlock(board.ID, blockID, &model.BlockPatch{
+			Title:            &newTitle,
+			ExpectedUpdateAt: &currentUpdateAt,
+		})
+		th.CheckOK(resp)
+
+		blocks, resp = th.Client.GetBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 1)
+		require.Equal(t, "Fresh title", blocks[0].Title)
+	})
+
+	t.Run("Patch a block merging nested fields", func(t *testing.T) {
+		blockPatch := &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"prop1": "value1", "prop2": "value2"},
+			},
+		}
+		_, resp := th.Client.PatchBlock(board.ID, blockID, blockPatch)
+		th.CheckOK(resp)
+
+		blockPatch = &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"prop2": "updated", "prop1": nil},
+			},
+			MergeFields: true,
+		}
+		_, resp = th.Client.PatchBlock(board.ID, blockID, blockPatch)
+		th.CheckOK(resp)
+
+		blocks, resp := th.Client.GetBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 1)
+		require.Equal(t, map[string]interface{}{"prop2": "updated"}, blocks[0].Fields["properties"])
+		require.Equal(t, "test value 2", blocks[0].Fields["test2"])
+	})
 }
 
 func TestDeleteBlock(t *testing.T) {
@@ -0,0 +1,115 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardChart(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do"},
+					map[string]interface{}{"id": "doing", "value": "Doing"},
+					map[string]interface{}{"id": "done", "value": "Done"},
+				},
+			},
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+		},
+	})
+	th.CheckOK(resp)
+
+	newCard := func(status string) model.Block {
+		return model.Block{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			CreateAt: 1,
+			UpdateAt: 1,
+			Fields: map[string]interface{}{
+				"properties": map[string]interface{}{"status": status},
+			},
+		}
+	}
+	cards, resp := th.Client.InsertBlocks(board.ID, []model.Block{newCard("todo"), newCard("doing"), newCard("todo")})
+	th.CheckOK(resp)
+	require.Len(t, cards, 3)
+
+	setStatus := func(cardID, status string) {
+		_, resp := th.Client.PatchBlock(board.ID, cardID, &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"status": status},
+			},
+		})
+		th.CheckOK(resp)
+	}
+	setStatus(cards[0].ID, "doing")
+	setStatus(cards[1].ID, "done")
+	setStatus(cards[0].ID, "done")
+
+	now := utils.GetMillis()
+	opts := func(chart string) *model.BoardChartOptions {
+		return &model.BoardChartOptions{
+			Chart:      chart,
+			PropertyID: "status",
+			StartValue: "doing",
+			EndValue:   "done",
+			From:       now - 24*time.Hour.Milliseconds(),
+			To:         now,
+			Location:   time.UTC,
+		}
+	}
+
+	t.Run("cumulative flow", func(t *testing.T) {
+		chart, resp := th.Client.GetBoardChart(board.ID, opts(model.BoardChartCumulativeFlow))
+		th.CheckOK(resp)
+		require.Equal(t, model.BoardChartIntervalDay, chart.Interval)
+		require.NotEmpty(t, chart.CumulativeFlow)
+
+		latest := chart.CumulativeFlow[len(chart.CumulativeFlow)-1]
+		require.Equal(t, map[string]int{"todo": 1, "done": 2}, latest.Counts)
+	})
+
+	t.Run("cycle time", func(t *testing.T) {
+		chart, resp := th.Client.GetBoardChart(board.ID, opts(model.BoardChartCycleTime))
+		th.CheckOK(resp)
+		require.NotNil(t, chart.CycleTime)
+		require.Equal(t, 2, chart.CycleTime.Count)
+	})
+
+	t.Run("throughput", func(t *testing.T) {
+		chart, resp := th.Client.GetBoardChart(board.ID, opts(model.BoardChartThroughput))
+		th.CheckOK(resp)
+		require.Equal(t, model.BoardChartIntervalWeek, chart.Interval)
+
+		total := 0
+		for _, point := range chart.Throughput {
+			total += point.Count
+		}
+		require.Equal(t, 2, total)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		chartOpts := opts(model.BoardChartCumulativeFlow)
+		chartOpts.PropertyID = "estimate"
+		_, resp := th.Client.GetBoardChart(board.ID, chartOpts)
+		th.CheckBadRequest(resp)
+
+		chartOpts = opts(model.BoardChartBurndown)
+		_, resp = th.Client.GetBoardChart(board.ID, chartOpts)
+		th.CheckBadRequest(resp)
+	})
+}
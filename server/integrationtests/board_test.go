@@ -996,6 +996,60 @@ func TestPatchBoard(t *testing.T) {
 	})
 }
 
+func TestBoardEtags(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+	rBoard, resp := th.Client.GetBoardIfNoneMatch(board.ID, "")
+	th.CheckOK(resp)
+	require.NotNil(t, rBoard)
+	etag := resp.Etag()
+	require.NotEmpty(t, etag)
+
+	t.Run("an unmodified board should return not modified", func(t *testing.T) {
+		rBoard, resp := th.Client.GetBoardIfNoneMatch(board.ID, etag)
+		th.CheckNotModified(resp)
+		require.Nil(t, rBoard)
+		require.Equal(t, etag, resp.Etag())
+	})
+
+	t.Run("a patch with a weak etag should fail", func(t *testing.T) {
+		weakTitle := "weak title"
+		rBoard, resp := th.Client.PatchBoardIfMatch(board.ID, &model.BoardPatch{Title: &weakTitle}, "W/"+etag)
+		th.CheckPreconditionFailed(resp)
+		require.Nil(t, rBoard)
+	})
+
+	t.Run("a patch with a matching etag should succeed", func(t *testing.T) {
+		time.Sleep(5 * time.Millisecond)
+
+		newTitle := "new title"
+		rBoard, resp := th.Client.PatchBoardIfMatch(board.ID, &model.BoardPatch{Title: &newTitle}, etag)
+		th.CheckOK(resp)
+		require.Equal(t, newTitle, rBoard.Title)
+		require.NotEqual(t, etag, resp.Etag())
+	})
+
+	t.Run("a patch with a stale etag should fail", func(t *testing.T) {
+		staleTitle := "stale title"
+		rBoard, resp := th.Client.PatchBoardIfMatch(board.ID, &model.BoardPatch{Title: &staleTitle}, etag)
+		th.CheckPreconditionFailed(resp)
+		require.Nil(t, rBoard)
+
+		dbBoard, err := th.Server.App().GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, "new title", dbBoard.Title)
+	})
+
+	t.Run("a modified board should be returned", func(t *testing.T) {
+		rBoard, resp := th.Client.GetBoardIfNoneMatch(board.ID, etag)
+		th.CheckOK(resp)
+		require.Equal(t, "new title", rBoard.Title)
+	})
+}
+
 func TestDeleteBoard(t *testing.T) {
 	teamID := testTeamID
 
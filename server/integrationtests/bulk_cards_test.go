@@ -0,0 +1,271 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkCardOperation(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "done", "value": "Done"},
+			}},
+			{"id": "tags", "name": "Tags", "type": "multiSelect", "options": []interface{}{
+				map[string]interface{}{"id": "bug", "value": "Bug"},
+				map[string]interface{}{"id": "ui", "value": "UI"},
+			}},
+			{"id": "owner", "name": "Owner", "type": "person"},
+		},
+	})
+	th.CheckOK(resp)
+
+	createCards := func(boardID string, statuses ...string) []string {
+		now := utils.GetMillis()
+		blocks := make([]model.Block, len(statuses))
+		for i, status := range statuses {
+			blocks[i] = model.Block{
+				ID:       utils.NewID(utils.IDTypeCard),
+				BoardID:  boardID,
+				ParentID: boardID,
+				Type:     model.TypeCard,
+				CreateAt: now,
+				UpdateAt: now,
+				Fields: map[string]interface{}{
+					"properties": map[string]interface{}{"status": status, "tags": []interface{}{"bug"}},
+				},
+			}
+		}
+		newBlocks, resp := th.Client.InsertBlocks(boardID, blocks)
+		th.CheckOK(resp)
+		ids := make([]string, len(newBlocks))
+		for i := range newBlocks {
+			ids[i] = newBlocks[i].ID
+		}
+		return ids
+	}
+
+	getBlocks := func(boardID string) map[string]model.Block {
+		blocks, resp := th.Client.GetAllBlocksForBoardWithArchived(boardID)
+		th.CheckOK(resp)
+		byID := map[string]model.Block{}
+		for _, block := range blocks {
+			byID[block.ID] = block
+		}
+		return byID
+	}
+	properties := func(block model.Block) map[string]interface{} {
+		return block.Fields["properties"].(map[string]interface{})
+	}
+
+	t.Run("set a property and edit options by ID", func(t *testing.T) {
+		cardIDs := createCards(board.ID, "todo", "todo")
+
+		result, resp := th.Client.BulkCardOperation(board.ID, &model.BulkCardOperation{
+			CardIDs:    cardIDs,
+			Action:     model.BulkCardActionSetProperty,
+			PropertyID: "status",
+			Value:      "done",
+		})
+		th.CheckOK(resp)
+		require.ElementsMatch(t, cardIDs, result.CardIDs)
+		require.Len(t, result.Blocks, 2)
+
+		result, resp = th.Client.BulkCardOperation(board.ID, &model.BulkCardOperation{
+			CardIDs:    cardIDs,
+			Action:     model.BulkCardActionAddOption,
+			PropertyID: "tags",
+			Value:      "ui",
+		})
+		th.CheckOK(resp)
+		require.Len(t, result.Blocks, 2)
+
+		_, resp = th.Client.BulkCardOperation(board.ID, &model.BulkCardOperation{
+			CardIDs:    cardIDs,
+			Action:     model.BulkCardActionAssignPerson,
+			PropertyID: "owner",
+			Value:      th.GetUser1().ID,
+		})
+		th.CheckOK(resp)
+
+		blocks := getBlocks(board.ID)
+		for _, cardID := range cardIDs {
+			props := properties(blocks[cardID])
+			require.Equal(t, "done", props["status"])
+			require.Equal(t, []interface{}{"bug", "ui"}, props["tags"])
+			require.Equal(t, th.GetUser1().ID, props["owner"])
+		}
+
+		// nothing changes if the cards already have the result
+		result, resp = th.Client.BulkCardOperation(board.ID, &model.BulkCardOperation{
+			CardIDs:    cardIDs,
+			Action:     model.BulkCardActionRemoveOption,
+			PropertyID: "status",
+			Value:      "todo",
+		})
+		th.CheckOK(resp)
+		require.Len(t, result.CardIDs, 2)
+		require.Empty(t, result.Blocks)
+	})
+
+	t.Run("archive the cards of a view", func(t *testing.T) {
+		otherBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		_, resp := th.Client.PatchBoard(otherBoard.ID, &model.BoardPatch{UpdatedCardProperties: board.CardProperties})
+		th.CheckOK(resp)
+		cardIDs := createCards(otherBoard.ID, "done", "todo", "done")
+
+		view := model.Block{
+			ID:       utils.NewID(utils.IDTypeView),
+			BoardID:  otherBoard.ID,
+			ParentID: otherBoard.ID,
+			Type:     model.TypeView,
+			CreateAt: utils.GetMillis(),
+			UpdateAt: utils.GetMillis(),
+			Fields: map[string]interface{}{"filter": map[string]interface{}{
+				"operation": "and",
+				"filters": []interface{}{
+					map[string]interface{}{"propertyId": "status", "condition": "includes", "values": []interface{}{"done"}},
+				},
+			}},
+		}
+		views, resp := th.Client.InsertBlocks(otherBoard.ID, []model.Block{view})
+		th.CheckOK(resp)
+
+		result, resp := th.Client.BulkCardOperation(otherBoard.ID, &model.BulkCardOperation{
+			ViewID: views[0].ID,
+			Action: model.BulkCardActionArchive,
+		})
+		th.CheckOK(resp)
+		require.ElementsMatch(t, []string{cardIDs[0], cardIDs[2]}, result.CardIDs)
+
+		blocks := getBlocks(otherBoard.ID)
+		require.NotZero(t, blocks[cardIDs[0]].ArchiveAt)
+		require.Zero(t, blocks[cardIDs[1]].ArchiveAt)
+		require.NotZero(t, blocks[cardIDs[2]].ArchiveAt)
+
+		// archived cards are no longer selected by the view
+		result, resp = th.Client.BulkCardOperation(otherBoard.ID, &model.BulkCardOperation{
+			ViewID: views[0].ID,
+			Action: model.BulkCardActionDelete,
+		})
+		th.CheckOK(resp)
+		require.Empty(t, result.CardIDs)
+	})
+
+	t.Run("move cards to another board", func(t *testing.T) {
+		destBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		cardIDs := createCards(board.ID, "todo", "done")
+
+		result, resp := th.Client.BulkCardOperation(board.ID, &model.BulkCardOperation{
+			CardIDs: cardIDs,
+			Action:  model.BulkCardActionMoveToBoard,
+			BoardID: destBoard.ID,
+		})
+		th.CheckOK(resp)
+		require.Len(t, result.Blocks, 2)
+		require.Len(t, result.UnmappedProperties, 4)
+
+		sourceBlocks := getBlocks(board.ID)
+		destBlocks := getBlocks(destBoard.ID)
+		for _, cardID := range cardIDs {
+			require.NotContains(t, sourceBlocks, cardID)
+			require.Contains(t, destBlocks, cardID)
+		}
+	})
+
+	t.Run("delete cards and log a single audit record", func(t *testing.T) {
+		cardIDs := createCards(board.ID, "todo", "todo", "todo")
+
+		result, resp := th.Client.BulkCardOperation(board.ID, &model.BulkCardOperation{
+			CardIDs: cardIDs,
+			Action:  model.BulkCardActionDelete,
+		})
+		th.CheckOK(resp)
+		require.Len(t, result.CardIDs, 3)
+
+		blocks := getBlocks(board.ID)
+		for _, cardID := range cardIDs {
+			require.NotContains(t, blocks, cardID)
+		}
+
+		// records are persisted asynchronously
+		opts := model.QueryAuditRecordsOptions{Event: "bulkCardOperation"}
+		require.Eventually(t, func() bool {
+			records, resp := th.Client.GetBoardAuditRecords(board.ID, opts)
+			th.CheckOK(resp)
+			for _, record := range records.Items {
+				if record.Meta["action"] == model.BulkCardActionDelete {
+					return true
+				}
+			}
+			return false
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("invalid operations", func(t *testing.T) {
+		cardIDs := createCards(board.ID, "todo")
+
+		_, resp := th.Client.BulkCardOperation(board.ID, &model.BulkCardOperation{Action: model.BulkCardActionDelete})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.BulkCardOperation(board.ID, &model.BulkCardOperation{
+			CardIDs:    cardIDs,
+			Action:     model.BulkCardActionAddOption,
+			PropertyID: "tags",
+			Value:      "feature",
+		})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.BulkCardOperation(board.ID, &model.BulkCardOperation{
+			CardIDs:    cardIDs,
+			Action:     model.BulkCardActionAddOption,
+			PropertyID: "owner",
+			Value:      "bug",
+		})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.BulkCardOperation(board.ID, &model.BulkCardOperation{
+			CardIDs: []string{"not-a-card"},
+			Action:  model.BulkCardActionArchive,
+		})
+		th.CheckNotFound(resp)
+
+		_, resp = th.Client.BulkCardOperation(board.ID, &model.BulkCardOperation{
+			ViewID: "not-a-view",
+			Action: model.BulkCardActionArchive,
+		})
+		th.CheckNotFound(resp)
+
+		// the whole operation fails when one of the cards is invalid
+		blocks := getBlocks(board.ID)
+		require.Zero(t, blocks[cardIDs[0]].ArchiveAt)
+	})
+
+	t.Run("users without access to the board", func(t *testing.T) {
+		cardIDs := createCards(board.ID, "todo")
+		privateBoard := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		_, resp := th.Client2.BulkCardOperation(privateBoard.ID, &model.BulkCardOperation{
+			CardIDs: cardIDs,
+			Action:  model.BulkCardActionArchive,
+		})
+		th.CheckForbidden(resp)
+
+		destBoard := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+		_, resp = th.Client2.BulkCardOperation(board.ID, &model.BulkCardOperation{
+			CardIDs: cardIDs,
+			Action:  model.BulkCardActionMoveToBoard,
+			BoardID: destBoard.ID,
+		})
+		th.CheckForbidden(resp)
+	})
+}
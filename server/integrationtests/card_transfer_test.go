@@ -0,0 +1,155 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardTransfer(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	sourceBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	destBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+	_, resp := th.Client.PatchBoard(sourceBoard.ID, &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "s-status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "s-todo", "value": "To do"},
+				map[string]interface{}{"id": "s-blocked", "value": "Blocked"},
+			}},
+			{"id": "s-notes", "name": "Notes", "type": "text"},
+		},
+	})
+	th.CheckOK(resp)
+	_, resp = th.Client.PatchBoard(destBoard.ID, &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "d-status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "d-todo", "value": "To Do"},
+			}},
+		},
+	})
+	th.CheckOK(resp)
+
+	createCard := func(status string) (string, []model.Block) {
+		now := utils.GetMillis()
+		cardID := utils.NewID(utils.IDTypeCard)
+		blocks := []model.Block{
+			{
+				ID:       cardID,
+				BoardID:  sourceBoard.ID,
+				ParentID: sourceBoard.ID,
+				Type:     model.TypeCard,
+				CreateAt: now,
+				UpdateAt: now,
+				Fields: map[string]interface{}{
+					"properties": map[string]interface{}{"s-status": status, "s-notes": "some notes"},
+				},
+			},
+			{
+				ID:       utils.NewID(utils.IDTypeBlock),
+				BoardID:  sourceBoard.ID,
+				ParentID: cardID,
+				Type:     model.TypeText,
+				Title:    "description",
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			{
+				ID:       utils.NewID(utils.IDTypeBlock),
+				BoardID:  sourceBoard.ID,
+				ParentID: cardID,
+				Type:     model.TypeComment,
+				Title:    "a comment",
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		}
+		newBlocks, resp := th.Client.InsertBlocks(sourceBoard.ID, blocks)
+		th.CheckOK(resp)
+		require.Len(t, newBlocks, 3)
+		return newBlocks[0].ID, newBlocks
+	}
+
+	blockIDs := func(boardID string) map[string]model.Block {
+		blocks, resp := th.Client.GetAllBlocksForBoard(boardID)
+		th.CheckOK(resp)
+		byID := map[string]model.Block{}
+		for _, block := range blocks {
+			byID[block.ID] = block
+		}
+		return byID
+	}
+
+	t.Run("move a card", func(t *testing.T) {
+		cardID, blocks := createCard("s-todo")
+
+		result, resp := th.Client.MoveCard(sourceBoard.ID, cardID, destBoard.ID)
+		th.CheckOK(resp)
+		require.Len(t, result.Blocks, 3)
+		require.Equal(t, cardID, result.Blocks[0].ID)
+		require.Equal(t, destBoard.ID, result.Blocks[0].ParentID)
+		require.Equal(t, map[string]interface{}{"d-status": "d-todo"}, result.Blocks[0].Fields["properties"])
+		require.Len(t, result.UnmappedProperties, 1)
+		require.Equal(t, "s-notes", result.UnmappedProperties[0].PropertyID)
+		require.Equal(t, cardID, result.UnmappedProperties[0].CardID)
+		require.Equal(t, model.CardTransferNoMatchingProperty, result.UnmappedProperties[0].Reason)
+
+		sourceBlocks := blockIDs(sourceBoard.ID)
+		destBlocks := blockIDs(destBoard.ID)
+		for _, block := range blocks {
+			require.NotContains(t, sourceBlocks, block.ID)
+			require.Contains(t, destBlocks, block.ID)
+			require.Equal(t, destBoard.ID, destBlocks[block.ID].BoardID)
+		}
+	})
+
+	t.Run("copy a card", func(t *testing.T) {
+		cardID, blocks := createCard("s-blocked")
+
+		result, resp := th.Client.CopyCard(sourceBoard.ID, cardID, destBoard.ID)
+		th.CheckOK(resp)
+		// comments are not copied
+		require.Len(t, result.Blocks, 2)
+		newCardID := result.Blocks[0].ID
+		require.NotEqual(t, cardID, newCardID)
+		require.Equal(t, newCardID, result.Blocks[1].ParentID)
+		require.Equal(t, map[string]interface{}{}, result.Blocks[0].Fields["properties"])
+		require.Len(t, result.UnmappedProperties, 2)
+		require.Equal(t, model.CardTransferUnknownOption, result.UnmappedProperties[0].Reason)
+		require.Equal(t, newCardID, result.UnmappedProperties[0].CardID)
+
+		sourceBlocks := blockIDs(sourceBoard.ID)
+		for _, block := range blocks {
+			require.Contains(t, sourceBlocks, block.ID)
+		}
+		require.Contains(t, blockIDs(destBoard.ID), newCardID)
+	})
+
+	t.Run("invalid transfers", func(t *testing.T) {
+		cardID, blocks := createCard("s-todo")
+
+		_, resp := th.Client.MoveCard(sourceBoard.ID, cardID, sourceBoard.ID)
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.MoveCard(sourceBoard.ID, blocks[1].ID, destBoard.ID)
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.MoveCard(destBoard.ID, cardID, sourceBoard.ID)
+		th.CheckNotFound(resp)
+
+		_, resp = th.Client.MoveCard(sourceBoard.ID, cardID, "")
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("destination board permissions", func(t *testing.T) {
+		cardID, _ := createCard("s-todo")
+		privateBoard := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		_, resp := th.Client2.CopyCard(sourceBoard.ID, cardID, privateBoard.ID)
+		th.CheckForbidden(resp)
+	})
+}
@@ -73,6 +73,9 @@ func (*FakePermissionPluginAPI) HasPermissionToTeam(userID string, teamID string
 	if teamID == "empty-team" {
 		return false
 	}
+	if permission.Id == model.PermissionManageTeam.Id {
+		return userID == userAdmin
+	}
 	return true
 }
 
@@ -120,6 +123,7 @@ func getTestConfig() (*config.Configuration, error) {
 		LoggingCfgJSON:    logging,
 		SessionExpireTime: int64(30 * time.Second),
 		AuthMode:          "native",
+		EnableAuditDB:     true,
 	}, nil
 }
 
@@ -429,6 +433,11 @@ func (th *TestHelper) CheckOK(r *client.Response) {
 	require.NoError(th.T, r.Error)
 }
 
+func (th *TestHelper) CheckAccepted(r *client.Response) {
+	require.Equal(th.T, http.StatusAccepted, r.StatusCode)
+	require.NoError(th.T, r.Error)
+}
+
 func (th *TestHelper) CheckBadRequest(r *client.Response) {
 	require.Equal(th.T, http.StatusBadRequest, r.StatusCode)
 	require.Error(th.T, r.Error)
@@ -458,3 +467,18 @@ func (th *TestHelper) CheckNotImplemented(r *client.Response) {
 	require.Equal(th.T, http.StatusNotImplemented, r.StatusCode)
 	require.Error(th.T, r.Error)
 }
+
+func (th *TestHelper) CheckNotModified(r *client.Response) {
+	require.Equal(th.T, http.StatusNotModified, r.StatusCode)
+	require.NoError(th.T, r.Error)
+}
+
+func (th *TestHelper) CheckPreconditionFailed(r *client.Response) {
+	require.Equal(th.T, http.StatusPreconditionFailed, r.StatusCode)
+	require.Error(th.T, r.Error)
+}
+
+func (th *TestHelper) CheckConflict(r *client.Response) {
+	require.Equal(th.T, http.StatusConflict, r.StatusCode)
+	require.Error(th.T, r.Error)
+}
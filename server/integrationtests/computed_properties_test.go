@@ -0,0 +1,179 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputedProperties(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+	patch := &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+			{"id": "spent", "name": "Spent", "type": "number"},
+			{"id": "remaining", "name": "Remaining", "type": model.PropertyTypeFormula, "formula": `prop("Estimate") - prop("Spent")`},
+			{"id": "subtaskEstimate", "name": "Subtask estimate", "type": model.PropertyTypeRollup, "rollup": map[string]interface{}{
+				"targetPropertyId": "estimate",
+				"function":         model.RollupFunctionSum,
+			}},
+		},
+	}
+	_, resp := th.Client.PatchBoard(board.ID, patch)
+	th.CheckOK(resp)
+
+	t.Run("invalid formulas are rejected", func(t *testing.T) {
+		invalidPatch := &model.BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "broken", "name": "Broken", "type": model.PropertyTypeFormula, "formula": `prop("Unknown") * 2`},
+			},
+		}
+		_, resp := th.Client.PatchBoard(board.ID, invalidPatch)
+		th.CheckBadRequest(resp)
+	})
+
+	now := utils.GetMillis()
+	cardID := utils.NewID(utils.IDTypeCard)
+	card := model.Block{
+		ID:       cardID,
+		BoardID:  board.ID,
+		ParentID: board.ID,
+		Type:     model.TypeCard,
+		CreateAt: now,
+		UpdateAt: now,
+		Fields: map[string]interface{}{
+			"properties": map[string]interface{}{"estimate": "8", "spent": "3"},
+		},
+	}
+	childCard := model.Block{
+		ID:       utils.NewID(utils.IDTypeCard),
+		BoardID:  board.ID,
+		ParentID: cardID,
+		Type:     model.TypeCard,
+		CreateAt: now,
+		UpdateAt: now,
+		Fields: map[string]interface{}{
+			"properties": map[string]interface{}{"estimate": "2"},
+		},
+	}
+	// the server assigns new IDs to the inserted blocks
+	newBlocks, resp := th.Client.InsertBlocks(board.ID, []model.Block{card, childCard})
+	th.CheckOK(resp)
+	require.Len(t, newBlocks, 2)
+	cardID = newBlocks[0].ID
+	childCardID := newBlocks[1].ID
+	require.Equal(t, cardID, newBlocks[1].ParentID)
+
+	getProperties := func(blockID string) map[string]interface{} {
+		blocks, resp := th.Client.GetAllBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		for _, block := range blocks {
+			if block.ID == blockID {
+				properties, _ := block.Fields["properties"].(map[string]interface{})
+				return properties
+			}
+		}
+		require.FailNow(t, "block not found", blockID)
+		return nil
+	}
+
+	properties := getProperties(cardID)
+	require.Equal(t, "5", properties["remaining"])
+	require.Equal(t, "2", properties["subtaskEstimate"])
+
+	t.Run("values are recomputed when a card changes", func(t *testing.T) {
+		_, resp := th.Client.PatchBlock(board.ID, cardID, &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"estimate": "8", "spent": "6"},
+			},
+		})
+		th.CheckOK(resp)
+
+		_, resp = th.Client.PatchBlock(board.ID, childCardID, &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"estimate": "4"},
+			},
+		})
+		th.CheckOK(resp)
+
+		properties := getProperties(cardID)
+		require.Equal(t, "2", properties["remaining"])
+		require.Equal(t, "4", properties["subtaskEstimate"])
+	})
+
+	t.Run("values are kept when another property changes", func(t *testing.T) {
+		_, resp := th.Client.PatchBlock(board.ID, cardID, &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"notes": "merged"},
+			},
+			MergeFields: true,
+		})
+		th.CheckOK(resp)
+
+		// replacing the properties drops the computed values, which are
+		// computed again
+		_, resp = th.Client.PatchBlock(board.ID, cardID, &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"estimate": "8", "spent": "6", "notes": "replaced"},
+			},
+		})
+		th.CheckOK(resp)
+
+		properties := getProperties(cardID)
+		require.Equal(t, "replaced", properties["notes"])
+		require.Equal(t, "2", properties["remaining"])
+		require.Equal(t, "4", properties["subtaskEstimate"])
+	})
+
+	t.Run("values are recomputed when a formula changes", func(t *testing.T) {
+		_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "remaining", "name": "Remaining", "type": model.PropertyTypeFormula, "formula": `prop("Estimate") * 10`},
+			},
+		})
+		th.CheckOK(resp)
+
+		properties := getProperties(cardID)
+		require.Equal(t, "80", properties["remaining"])
+	})
+
+	t.Run("values are recomputed on both boards when a card moves", func(t *testing.T) {
+		destBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		_, resp := th.Client.PatchBoard(destBoard.ID, &model.BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "destEstimate", "name": "Estimate", "type": "number"},
+				{"id": "double", "name": "Double", "type": model.PropertyTypeFormula, "formula": `prop("Estimate") * 2`},
+			},
+		})
+		th.CheckOK(resp)
+
+		now := utils.GetMillis()
+		newBlocks, resp := th.Client.InsertBlocks(board.ID, []model.Block{{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			ParentID: cardID,
+			Type:     model.TypeCard,
+			CreateAt: now,
+			UpdateAt: now,
+			Fields: map[string]interface{}{
+				"properties": map[string]interface{}{"estimate": "6"},
+			},
+		}})
+		th.CheckOK(resp)
+		require.Equal(t, "10", getProperties(cardID)["subtaskEstimate"])
+
+		result, resp := th.Client.MoveCard(board.ID, newBlocks[0].ID, destBoard.ID)
+		th.CheckOK(resp)
+		require.Len(t, result.Blocks, 1)
+		movedProperties, _ := result.Blocks[0].Fields["properties"].(map[string]interface{})
+		require.Equal(t, "12", movedProperties["double"])
+
+		require.Equal(t, "4", getProperties(cardID)["subtaskEstimate"])
+	})
+}
@@ -1,8 +1,14 @@
 package integrationtests
 
 import (
+	"archive/zip"
+	"bufio"
 	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/utils"
@@ -64,3 +70,261 @@ func TestExportBoard(t *testing.T) {
 		require.Equal(t, block.Title, blocksImported[0].Title)
 	})
 }
+
+// readArchive returns the lines of the board.jsonl files of an archive by
+// type, and the names of the other files it contains.
+func readArchive(t *testing.T, data []byte) (map[string][]json.RawMessage, []string) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	lines := map[string][]json.RawMessage{}
+	var files []string
+	for _, f := range zr.File {
+		if f.Name == "version.json" {
+			continue
+		}
+		if path.Base(f.Name) != "board.jsonl" {
+			files = append(files, f.Name)
+			continue
+		}
+		r, err := f.Open()
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			var line model.ArchiveLine
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines[line.Type] = append(lines[line.Type], line.Data)
+		}
+		require.NoError(t, scanner.Err())
+		require.NoError(t, r.Close())
+	}
+	return lines, files
+}
+
+func archiveBlockTitles(t *testing.T, lines []json.RawMessage) []string {
+	titles := make([]string, 0, len(lines))
+	for _, data := range lines {
+		var block model.Block
+		require.NoError(t, json.Unmarshal(data, &block))
+		titles = append(titles, block.Title)
+	}
+	return titles
+}
+
+func TestExportTeamArchiveOptions(t *testing.T) {
+	const teamID = "team-id"
+
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(teamID, model.BoardTypeOpen)
+	unchangedBoard := th.CreateBoard(teamID, model.BoardTypeOpen)
+
+	file, resp := th.Client.TeamUploadFile(teamID, board.ID, bytes.NewBuffer([]byte("test")))
+	th.CheckOK(resp)
+
+	now := utils.GetMillis()
+	newBlock := func(id, parentID string, blockType model.BlockType, title string) model.Block {
+		return model.Block{
+			ID:       id,
+			BoardID:  board.ID,
+			ParentID: parentID,
+			Type:     blockType,
+			Title:    title,
+			Fields:   map[string]interface{}{},
+			CreateAt: now,
+			UpdateAt: now,
+		}
+	}
+	image := newBlock("image", "card", model.TypeImage, "")
+	image.Fields["fileId"] = file.FileID
+	blocks, resp := th.Client.InsertBlocks(board.ID, []model.Block{
+		newBlock("card", board.ID, model.TypeCard, "Card"),
+		newBlock("comment", "card", model.TypeComment, "Comment"),
+		image,
+	})
+	th.CheckOK(resp)
+	cardID, commentID := blocks[0].ID, blocks[1].ID
+
+	otherCard := newBlock("other-card", unchangedBoard.ID, model.TypeCard, "Other card")
+	otherCard.BoardID = unchangedBoard.ID
+	_, resp = th.Client.InsertBlocks(unchangedBoard.ID, []model.Block{otherCard})
+	th.CheckOK(resp)
+
+	export := func(opts *model.ExportArchiveOptions) (map[string][]json.RawMessage, []string) {
+		var buf bytes.Buffer
+		th.CheckOK(th.Client.ExportTeamArchive(teamID, opts, &buf))
+		return readArchive(t, buf.Bytes())
+	}
+
+	t.Run("default options", func(t *testing.T) {
+		lines, files := export(nil)
+		require.Len(t, lines["board"], 2)
+		require.ElementsMatch(t, []string{"Card", "Comment", "", "Other card"}, archiveBlockTitles(t, lines["block"]))
+		require.Equal(t, []string{board.ID + "/" + file.FileID}, files)
+		require.Empty(t, lines["board_member"])
+		require.Empty(t, lines["block_history"])
+	})
+
+	t.Run("exclude comments and files", func(t *testing.T) {
+		lines, files := export(&model.ExportArchiveOptions{
+			ExcludeComments: true,
+			Files:           model.ExportFilesExclude,
+		})
+		require.ElementsMatch(t, []string{"Card", "Other card"}, archiveBlockTitles(t, lines["block"]))
+		require.Empty(t, files)
+	})
+
+	t.Run("files by reference", func(t *testing.T) {
+		lines, files := export(&model.ExportArchiveOptions{Files: model.ExportFilesReference})
+		require.Len(t, lines["block"], 4)
+		require.Empty(t, files)
+	})
+
+	t.Run("members and history", func(t *testing.T) {
+		lines, _ := export(&model.ExportArchiveOptions{
+			IncludeMembers: true,
+			IncludeHistory: true,
+		})
+		require.Len(t, lines["board_member"], 2)
+		require.Len(t, lines["board_history"], 2)
+		require.Len(t, lines["block_history"], 4)
+
+		var member model.BoardMember
+		require.NoError(t, json.Unmarshal(lines["board_member"][0], &member))
+		require.Equal(t, th.GetUser1().ID, member.UserID)
+	})
+
+	t.Run("changes since a time, with deleted blocks", func(t *testing.T) {
+		time.Sleep(10 * time.Millisecond)
+		since := utils.GetMillis()
+		time.Sleep(10 * time.Millisecond)
+
+		title := "Updated card"
+		_, resp := th.Client.PatchBlock(board.ID, cardID, &model.BlockPatch{Title: &title})
+		th.CheckOK(resp)
+		_, resp = th.Client.DeleteBlock(board.ID, commentID)
+		th.CheckOK(resp)
+
+		lines, files := export(&model.ExportArchiveOptions{
+			ModifiedSince:  since,
+			IncludeDeleted: true,
+		})
+		require.Len(t, lines["board"], 1)
+		require.Equal(t, []string{title}, archiveBlockTitles(t, lines["block"]))
+		require.Empty(t, files)
+
+		require.Len(t, lines["block_tombstone"], 1)
+		var tombstone model.BlockTombstone
+		require.NoError(t, json.Unmarshal(lines["block_tombstone"][0], &tombstone))
+		require.Equal(t, commentID, tombstone.ID)
+
+		var exportedBoard model.Board
+		require.NoError(t, json.Unmarshal(lines["board"][0], &exportedBoard))
+		require.Equal(t, board.ID, exportedBoard.ID)
+		require.NotEqual(t, unchangedBoard.ID, exportedBoard.ID)
+	})
+
+	t.Run("archives with backup lines can be imported", func(t *testing.T) {
+		var buf bytes.Buffer
+		th.CheckOK(th.Client.ExportTeamArchive(teamID, &model.ExportArchiveOptions{
+			IncludeDeleted: true,
+			IncludeMembers: true,
+			IncludeHistory: true,
+		}, &buf))
+
+		resp := th.Client.ImportArchive(model.GlobalTeamID, &buf)
+		th.CheckOK(resp)
+
+		boards, err := th.Server.App().GetBoardsForUserAndTeam(th.GetUser1().ID, model.GlobalTeamID)
+		require.NoError(t, err)
+		require.Len(t, boards, 2)
+	})
+
+	t.Run("invalid files mode", func(t *testing.T) {
+		var buf bytes.Buffer
+		th.CheckBadRequest(th.Client.ExportTeamArchive(teamID, &model.ExportArchiveOptions{Files: "all"}, &buf))
+	})
+}
+
+func TestImportExternal(t *testing.T) {
+	trelloExport := `{
+		"id": "trello-board",
+		"name": "Trello roadmap",
+		"lists": [{"id": "list1", "name": "To do", "pos": 1}],
+		"members": [
+			{"id": "member1", "fullName": "User One", "username": "user1"},
+			{"id": "member2", "fullName": "Someone Else", "username": "someone"}
+		],
+		"cards": [
+			{"id": "card1", "name": "First card", "desc": "Details", "idList": "list1", "idMembers": ["member1", "member2"]},
+			{"id": "card2", "name": "Second card", "idList": "list1"}
+		],
+		"actions": [
+			{"id": "action1", "type": "commentCard", "date": "2022-05-01T09:00:00.000Z",
+			 "data": {"text": "Nice", "card": {"id": "card1"}}, "memberCreator": {"username": "someone"}}
+		]
+	}`
+
+	t.Run("import a Trello board", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		report, resp := th.Client.ImportExternal(model.GlobalTeamID, "trello", strings.NewReader(trelloExport))
+		th.CheckOK(resp)
+		require.NotNil(t, report)
+		require.Equal(t, "trello", report.Format)
+		require.Equal(t, 2, report.Cards)
+		require.Len(t, report.BoardIDs, 1)
+		require.Len(t, report.Items, 1)
+		require.Equal(t, "user", report.Items[0].Kind)
+		require.Equal(t, "Someone Else", report.Items[0].Title)
+
+		board, resp := th.Client.GetBoard(report.BoardIDs[0], "")
+		th.CheckOK(resp)
+		require.Equal(t, "Trello roadmap", board.Title)
+		require.Equal(t, th.GetUser1().ID, board.CreatedBy)
+
+		blocks, resp := th.Client.GetAllBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		var cards, comments int
+		for _, block := range blocks {
+			switch block.Type {
+			case model.TypeCard:
+				cards++
+			case model.TypeComment:
+				comments++
+				require.Contains(t, block.Title, "Nice")
+			}
+		}
+		require.Equal(t, 2, cards)
+		require.Equal(t, 1, comments)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		report, resp := th.Client.ImportExternal(model.GlobalTeamID, "monday", strings.NewReader("{}"))
+		th.CheckBadRequest(resp)
+		require.Nil(t, report)
+	})
+
+	t.Run("invalid export", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		report, resp := th.Client.ImportExternal(model.GlobalTeamID, "jira", strings.NewReader("Key,Status\nWEB-1,Done\n"))
+		th.CheckBadRequest(resp)
+		require.Nil(t, report)
+	})
+
+	t.Run("not authenticated", func(t *testing.T) {
+		th := SetupTestHelper(t).Start()
+		defer th.TearDown()
+
+		report, resp := th.Client.ImportExternal(model.GlobalTeamID, "trello", strings.NewReader(trelloExport))
+		th.CheckUnauthorized(resp)
+		require.Nil(t, report)
+	})
+}
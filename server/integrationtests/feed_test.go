@@ -0,0 +1,177 @@
+package integrationtests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedToken(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	_, resp := th.Client.GetFeedToken()
+	th.CheckNotFound(resp)
+
+	feedToken, resp := th.Client.RegenerateFeedToken()
+	th.CheckOK(resp)
+	require.Equal(t, th.GetUser1().ID, feedToken.UserID)
+	require.NotEmpty(t, feedToken.Token)
+
+	saved, resp := th.Client.GetFeedToken()
+	th.CheckOK(resp)
+	require.Equal(t, feedToken.Token, saved.Token)
+
+	// a new token revokes the previous one
+	newToken, resp := th.Client.RegenerateFeedToken()
+	th.CheckOK(resp)
+	require.NotEqual(t, feedToken.Token, newToken.Token)
+
+	_, resp = th.Client.RevokeFeedToken()
+	th.CheckOK(resp)
+	_, resp = th.Client.GetFeedToken()
+	th.CheckNotFound(resp)
+	_, resp = th.Client.RevokeFeedToken()
+	th.CheckNotFound(resp)
+}
+
+func TestCalendarFeeds(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	me := th.GetUser1()
+	board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+	title := "Roadmap"
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{
+		Title: &title,
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "owner", "name": "Owner", "type": "person"},
+			{"id": "due", "name": "Due", "type": "date"},
+		},
+	})
+	th.CheckOK(resp)
+
+	now := utils.GetMillis()
+	card := func(title string, properties map[string]interface{}) model.Block {
+		return model.Block{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			Title:    title,
+			CreateAt: now,
+			UpdateAt: now,
+			Fields:   map[string]interface{}{"properties": properties},
+		}
+	}
+	cards, resp := th.Client.InsertBlocks(board.ID, []model.Block{
+		// from 2022-01-14 to 2022-01-16, without time
+		card("Launch", map[string]interface{}{"owner": me.ID, "due": `{"from":1642161600000,"to":1642334400000}`}),
+		card("Review", map[string]interface{}{"owner": "other-user-id", "due": `{"from":1642671000000,"includeTime":true}`}),
+		card("Someday", map[string]interface{}{"owner": me.ID}),
+	})
+	th.CheckOK(resp)
+
+	feedToken, resp := th.Client.RegenerateFeedToken()
+	th.CheckOK(resp)
+
+	t.Run("board feed without session nor CSRF header", func(t *testing.T) {
+		r, err := http.Get(th.Client.GetBoardFeedURL(board.ID, feedToken.Token, ""))
+		require.NoError(t, err)
+		defer r.Body.Close()
+		require.Equal(t, http.StatusOK, r.StatusCode)
+		require.Equal(t, "text/calendar; charset=utf-8", r.Header.Get("Content-Type"))
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		// the long lines are folded
+		content := strings.ReplaceAll(string(body), "\r\n ", "")
+		require.Contains(t, content, "X-WR-CALNAME:Roadmap\r\n")
+		require.Contains(t, content, "SUMMARY:Launch\r\n")
+		require.Contains(t, content, "DTSTART;VALUE=DATE:20220114\r\n")
+		require.Contains(t, content, "DTEND;VALUE=DATE:20220117\r\n")
+		require.Contains(t, content, "URL:"+utils.MakeCardLink(th.Server.Config().ServerRoot, testTeamID, board.ID, cards[0].ID)+"\r\n")
+		require.Contains(t, content, "Assignees: @"+me.Username)
+		require.Contains(t, content, "SUMMARY:Review\r\n")
+		require.Contains(t, content, "DTSTART:20220120T093000Z\r\n")
+		require.NotContains(t, content, "Someday")
+	})
+
+	t.Run("board feed errors", func(t *testing.T) {
+		_, resp := th.Client.GetBoardFeed(board.ID, feedToken.Token, "owner")
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.GetBoardFeed(board.ID, "invalid-token", "")
+		th.CheckUnauthorized(resp)
+
+		// the feeds follow the permissions of the user of the token
+		otherToken, resp := th.Client2.RegenerateFeedToken()
+		th.CheckOK(resp)
+		_, resp = th.Client2.GetBoardFeed(board.ID, otherToken.Token, "")
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("feed of the cards assigned to the user", func(t *testing.T) {
+		body, resp := th.Client.GetUserFeed(me.ID, feedToken.Token)
+		th.CheckOK(resp)
+		content := string(body)
+		require.Contains(t, content, "SUMMARY:Launch\r\n")
+		require.NotContains(t, content, "SUMMARY:Review")
+
+		_, resp = th.Client.GetUserFeed(th.GetUser2().ID, feedToken.Token)
+		th.CheckUnauthorized(resp)
+	})
+
+	t.Run("revoked token", func(t *testing.T) {
+		_, resp := th.Client.RevokeFeedToken()
+		th.CheckOK(resp)
+
+		_, resp = th.Client.GetBoardFeed(board.ID, feedToken.Token, "")
+		th.CheckUnauthorized(resp)
+		_, resp = th.Client.GetUserFeed(me.ID, feedToken.Token)
+		th.CheckUnauthorized(resp)
+	})
+}
+
+func TestCalendarFeedsOfDeactivatedUsers(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+	t.Run("the token of a deactivated user is rejected", func(t *testing.T) {
+		feedToken, resp := th.Client2.RegenerateFeedToken()
+		th.CheckOK(resp)
+		userID := th.GetUser2().ID
+		_, resp = th.Client2.GetUserFeed(userID, feedToken.Token)
+		th.CheckOK(resp)
+
+		// the user keeps their token when deactivated outside the admin API
+		require.NoError(t, th.Server.Store().DeactivateUser(userID))
+
+		_, resp = th.Client2.GetUserFeed(userID, feedToken.Token)
+		th.CheckUnauthorized(resp)
+	})
+
+	t.Run("deactivating a user revokes their token", func(t *testing.T) {
+		feedToken, resp := th.Client.RegenerateFeedToken()
+		th.CheckOK(resp)
+		_, resp = th.Client.GetBoardFeed(board.ID, feedToken.Token, "")
+		th.CheckOK(resp)
+
+		me := th.GetUser1()
+		require.NoError(t, th.Server.App().DeactivateUser(me.Username))
+
+		_, err := th.Server.Store().GetFeedToken(me.ID)
+		require.True(t, model.IsErrNotFound(err))
+		_, resp = th.Client.GetBoardFeed(board.ID, feedToken.Token, "")
+		th.CheckUnauthorized(resp)
+		_, resp = th.Client.GetUserFeed(me.ID, feedToken.Token)
+		th.CheckUnauthorized(resp)
+	})
+}
@@ -0,0 +1,165 @@
+package integrationtests
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeClamd starts a server answering the scans like clamd, finding a
+// threat in the files containing "EICAR". Each scan waits for a value on the
+// returned channel before answering.
+func startFakeClamd(t *testing.T) (string, chan struct{}) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	proceed := make(chan struct{})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go answerFakeClamdScan(conn, proceed)
+		}
+	}()
+	return listener.Addr().String(), proceed
+}
+
+func answerFakeClamdScan(conn net.Conn, proceed chan struct{}) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	if command, err := r.ReadString(0); err != nil || command != "zINSTREAM\x00" {
+		return
+	}
+
+	var stream bytes.Buffer
+	for {
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return
+		}
+		if size == 0 {
+			break
+		}
+		if _, err := io.CopyN(&stream, r, int64(size)); err != nil {
+			return
+		}
+	}
+
+	<-proceed
+	if bytes.Contains(stream.Bytes(), []byte("EICAR")) {
+		_, _ = io.WriteString(conn, "stream: Eicar-Test-Signature FOUND\x00")
+	} else {
+		_, _ = io.WriteString(conn, "stream: OK\x00")
+	}
+}
+
+func TestFileScans(t *testing.T) {
+	const (
+		testTeamID = "team-id"
+	)
+
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	address, proceed := startFakeClamd(t)
+	config := th.Server.App().GetConfig()
+	config.FileScanner = scanner.TypeClamAV
+	config.ClamAVAddress = address
+	th.Server.App().SetConfig(config)
+	defer func() {
+		config.FileScanner = scanner.TypeNone
+		th.Server.App().SetConfig(config)
+	}()
+
+	testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+	getFileStatus := func(fileID string) int {
+		var content bytes.Buffer
+		return th.Client.GetFile(testTeamID, testBoard.ID, fileID, "", &content).StatusCode
+	}
+
+	t.Run("a clean file is served once scanned", func(t *testing.T) {
+		file, resp := th.Client.TeamUploadFile(testTeamID, testBoard.ID, bytes.NewBufferString("clean file"))
+		th.CheckOK(resp)
+
+		var content bytes.Buffer
+		resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, "", &content)
+		th.CheckConflict(resp)
+
+		proceed <- struct{}{}
+		require.Eventually(t, func() bool {
+			return getFileStatus(file.FileID) == http.StatusOK
+		}, 5*time.Second, 50*time.Millisecond)
+
+		content.Reset()
+		resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, "", &content)
+		th.CheckOK(resp)
+		require.Equal(t, "clean file", content.String())
+	})
+
+	t.Run("the thumbnail of an image is generated once it is scanned clean", func(t *testing.T) {
+		var img bytes.Buffer
+		require.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 300, 150))))
+		file, resp := th.Client.TeamUploadFile(testTeamID, testBoard.ID, bytes.NewReader(img.Bytes()))
+		th.CheckOK(resp)
+
+		fileInfo, err := th.Server.App().GetFileInfo(file.FileID)
+		require.NoError(t, err)
+		require.False(t, fileInfo.HasPreviewImage)
+		require.Zero(t, fileInfo.Width)
+
+		proceed <- struct{}{}
+		require.Eventually(t, func() bool {
+			return getFileStatus(file.FileID) == http.StatusOK
+		}, 5*time.Second, 50*time.Millisecond)
+
+		fileInfo, err = th.Server.App().GetFileInfo(file.FileID)
+		require.NoError(t, err)
+		require.True(t, fileInfo.HasPreviewImage)
+		require.Equal(t, 300, fileInfo.Width)
+		require.Equal(t, 150, fileInfo.Height)
+
+		var thumbnail bytes.Buffer
+		resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, "thumb", &thumbnail)
+		th.CheckOK(resp)
+		require.Equal(t, "image/jpeg", resp.Header.Get("Content-Type"))
+	})
+
+	t.Run("an infected file is never served", func(t *testing.T) {
+		file, resp := th.Client.TeamUploadFile(testTeamID, testBoard.ID, bytes.NewBufferString("EICAR test file"))
+		th.CheckOK(resp)
+
+		proceed <- struct{}{}
+		require.Eventually(t, func() bool {
+			return getFileStatus(file.FileID) == http.StatusForbidden
+		}, 5*time.Second, 50*time.Millisecond)
+
+		// even once the scans are disabled
+		config.FileScanner = scanner.TypeNone
+		th.Server.App().SetConfig(config)
+		defer func() {
+			config.FileScanner = scanner.TypeClamAV
+			th.Server.App().SetConfig(config)
+		}()
+
+		var content bytes.Buffer
+		resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, "", &content)
+		th.CheckForbidden(resp)
+		require.NotContains(t, content.String(), "EICAR")
+	})
+}
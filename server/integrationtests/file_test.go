@@ -2,9 +2,14 @@ package integrationtests
 
 import (
 	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
 	"testing"
 
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
 
 	"github.com/stretchr/testify/require"
 )
@@ -69,3 +74,225 @@ func TestUploadFile(t *testing.T) {
 		require.NotNil(t, file.FileID)
 	})
 }
+
+func TestGetFile(t *testing.T) {
+	const (
+		testTeamID = "team-id"
+	)
+
+	var img bytes.Buffer
+	rgba := image.NewRGBA(image.Rect(0, 0, 300, 150))
+	for x := 0; x < 300; x++ {
+		rgba.Set(x, x/2, color.RGBA{B: 255, A: 255})
+	}
+	require.NoError(t, png.Encode(&img, rgba))
+
+	t.Run("get an image and its thumbnail and preview", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		file, resp := th.Client.TeamUploadFile(testTeamID, testBoard.ID, bytes.NewReader(img.Bytes()))
+		th.CheckOK(resp)
+
+		var original bytes.Buffer
+		resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, "", &original)
+		th.CheckOK(resp)
+		require.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+		require.Empty(t, resp.Header.Get("Content-Disposition"))
+		require.Equal(t, img.Bytes(), original.Bytes())
+
+		var thumbnail bytes.Buffer
+		resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, "thumb", &thumbnail)
+		th.CheckOK(resp)
+		require.Equal(t, "image/jpeg", resp.Header.Get("Content-Type"))
+		decoded, err := jpeg.Decode(&thumbnail)
+		require.NoError(t, err)
+		require.Equal(t, image.Rect(0, 0, 120, 60), decoded.Bounds())
+
+		var preview bytes.Buffer
+		resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, "preview", &preview)
+		th.CheckOK(resp)
+		decoded, err = jpeg.Decode(&preview)
+		require.NoError(t, err)
+		require.Equal(t, image.Rect(0, 0, 300, 150), decoded.Bounds())
+
+		fileInfo, err := th.Server.App().GetFileInfo(file.FileID)
+		require.NoError(t, err)
+		require.Equal(t, "image/png", fileInfo.MimeType)
+		require.Equal(t, 300, fileInfo.Width)
+		require.Equal(t, 150, fileInfo.Height)
+		require.True(t, fileInfo.HasPreviewImage)
+	})
+
+	t.Run("files without thumbnail are served in full and downloaded", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		file, resp := th.Client.TeamUploadFile(testTeamID, testBoard.ID, bytes.NewBufferString("<html><script>alert(1)</script></html>"))
+		th.CheckOK(resp)
+
+		var content bytes.Buffer
+		resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, "thumb", &content)
+		th.CheckOK(resp)
+		require.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+		require.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+		require.Equal(t, "attachment; filename=file", resp.Header.Get("Content-Disposition"))
+		require.Equal(t, "<html><script>alert(1)</script></html>", content.String())
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		file, resp := th.Client.TeamUploadFile(testTeamID, testBoard.ID, bytes.NewReader(img.Bytes()))
+		th.CheckOK(resp)
+
+		var content bytes.Buffer
+		resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, "huge", &content)
+		th.CheckBadRequest(resp)
+	})
+}
+
+func TestAttachments(t *testing.T) {
+	const (
+		testTeamID = "team-id"
+	)
+
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	file1, resp := th.Client.TeamUploadFile(testTeamID, testBoard.ID, bytes.NewBufferString("first file"))
+	th.CheckOK(resp)
+	file2, resp := th.Client.TeamUploadFile(testTeamID, testBoard.ID, bytes.NewBufferString("second"))
+	th.CheckOK(resp)
+
+	now := utils.GetMillis()
+	newBlock := func(id, parentID string, blockType model.BlockType, fileID string) model.Block {
+		block := model.Block{
+			ID:       id,
+			BoardID:  testBoard.ID,
+			ParentID: parentID,
+			Type:     blockType,
+			Fields:   map[string]interface{}{},
+			CreateAt: now,
+			UpdateAt: now,
+		}
+		if fileID != "" {
+			block.Fields["fileId"] = fileID
+		}
+		return block
+	}
+	blocks, resp := th.Client.InsertBlocks(testBoard.ID, []model.Block{
+		newBlock("card-1", testBoard.ID, model.TypeCard, ""),
+		newBlock("card-2", testBoard.ID, model.TypeCard, ""),
+		newBlock("image-1", "card-1", model.TypeImage, file1.FileID),
+		newBlock("image-2", "card-2", model.TypeImage, file2.FileID),
+	})
+	th.CheckOK(resp)
+	card1ID := blocks[0].ID
+
+	t.Run("list the attachments of a board and a card", func(t *testing.T) {
+		attachments, resp := th.Client.GetBoardAttachments(testBoard.ID, "")
+		th.CheckOK(resp)
+		require.Len(t, attachments, 2)
+
+		attachments, resp = th.Client.GetBoardAttachments(testBoard.ID, card1ID)
+		th.CheckOK(resp)
+		require.Len(t, attachments, 1)
+		require.Equal(t, file1.FileID, attachments[0].FileID)
+		require.Equal(t, card1ID, attachments[0].ParentID)
+		require.Equal(t, int64(len("first file")), attachments[0].Size)
+		require.Equal(t, "text/plain; charset=utf-8", attachments[0].MimeType)
+	})
+
+	t.Run("storage usage of the team", func(t *testing.T) {
+		usage, resp := th.Client.GetTeamStorageUsage(testTeamID)
+		th.CheckOK(resp)
+		require.Equal(t, int64(len("first file")+len("second")), usage.Used)
+		require.Zero(t, usage.Quota)
+	})
+
+	t.Run("users without access to the board", func(t *testing.T) {
+		_, resp := th.Client2.GetBoardAttachments(testBoard.ID, "")
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.DeleteAttachment(testBoard.ID, file1.FileID)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("delete an attachment", func(t *testing.T) {
+		_, resp := th.Client.DeleteAttachment(testBoard.ID, file1.FileID)
+		th.CheckOK(resp)
+
+		attachments, resp := th.Client.GetBoardAttachments(testBoard.ID, "")
+		th.CheckOK(resp)
+		require.Len(t, attachments, 1)
+		require.Equal(t, file2.FileID, attachments[0].FileID)
+
+		_, resp = th.Client.DeleteAttachment(testBoard.ID, file1.FileID)
+		th.CheckNotFound(resp)
+
+		// the file is kept, as the image block can be restored
+		_, resp = th.Client.UndeleteBlock(testBoard.ID, blocks[2].ID)
+		th.CheckOK(resp)
+		attachments, resp = th.Client.GetBoardAttachments(testBoard.ID, "")
+		th.CheckOK(resp)
+		require.Len(t, attachments, 2)
+
+		var content bytes.Buffer
+		resp = th.Client.GetFile(testTeamID, testBoard.ID, file1.FileID, "", &content)
+		th.CheckOK(resp)
+		require.Equal(t, "first file", content.String())
+	})
+
+	t.Run("copied files count in the storage quota", func(t *testing.T) {
+		config := th.Server.App().GetConfig()
+		config.TeamStorageQuota = int64(len("first file") + len("second") + len("first file"))
+		th.Server.App().SetConfig(config)
+		defer func() {
+			config.TeamStorageQuota = 0
+			th.Server.App().SetConfig(config)
+		}()
+
+		copied, resp := th.Client.CopyCard(testBoard.ID, card1ID, testBoard.ID)
+		th.CheckOK(resp)
+		require.Len(t, copied.Blocks, 2)
+
+		usage, resp := th.Client.GetTeamStorageUsage(testTeamID)
+		th.CheckOK(resp)
+		require.Equal(t, config.TeamStorageQuota, usage.Used)
+
+		_, resp = th.Client.CopyCard(testBoard.ID, card1ID, testBoard.ID)
+		th.CheckRequestEntityTooLarge(resp)
+
+	})
+
+	t.Run("upload exceeding the storage quota of the team", func(t *testing.T) {
+		usage, resp := th.Client.GetTeamStorageUsage(testTeamID)
+		th.CheckOK(resp)
+		used := usage.Used
+
+		config := th.Server.App().GetConfig()
+		config.TeamStorageQuota = used + int64(len("small"))
+		th.Server.App().SetConfig(config)
+		defer func() {
+			config.TeamStorageQuota = 0
+			th.Server.App().SetConfig(config)
+		}()
+
+		_, resp = th.Client.TeamUploadFile(testTeamID, testBoard.ID, bytes.NewBufferString("too large"))
+		th.CheckRequestEntityTooLarge(resp)
+
+		_, resp = th.Client.TeamUploadFile(testTeamID, testBoard.ID, bytes.NewBufferString("small"))
+		th.CheckOK(resp)
+
+		usage, resp = th.Client.GetTeamStorageUsage(testTeamID)
+		th.CheckOK(resp)
+		require.Equal(t, used+int64(len("small")), usage.Used)
+		require.Equal(t, config.TeamStorageQuota, usage.Quota)
+	})
+}
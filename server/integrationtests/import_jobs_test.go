@@ -0,0 +1,261 @@
+package integrationtests
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/client"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+type testArchiveBoard struct {
+	dir   string
+	lines []string
+}
+
+func makeTestArchive(t *testing.T, boards ...testArchiveBoard) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	w, err := zw.Create("version.json")
+	require.NoError(t, err)
+	_, err = w.Write([]byte(`{"version":2,"date":1660000000000}`))
+	require.NoError(t, err)
+
+	for _, board := range boards {
+		w, err := zw.Create(board.dir + "/board.jsonl")
+		require.NoError(t, err)
+		for _, line := range board.lines {
+			_, err = w.Write([]byte(line + "\n"))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func validTestArchiveBoard(dir, title string) testArchiveBoard {
+	return testArchiveBoard{
+		dir: dir,
+		lines: []string{
+			fmt.Sprintf(`{"type":"board","data":{"id":"%s","title":"%s","type":"P","cardProperties":[]}}`, dir, title),
+			fmt.Sprintf(`{"type":"block","data":{"id":"%s-card","boardId":"%s","parentId":"%s","type":"card","title":"A card","fields":{}}}`, dir, dir, dir),
+		},
+	}
+}
+
+func (th *TestHelper) waitForImportJob(teamID, jobID string) *model.ImportJob {
+	var job *model.ImportJob
+	require.Eventually(th.T, func() bool {
+		var resp *client.Response
+		job, resp = th.Client.GetImportJob(teamID, jobID)
+		th.CheckOK(resp)
+		return job.IsFinished()
+	}, 10*time.Second, 50*time.Millisecond)
+	return job
+}
+
+func TestImportJobs(t *testing.T) {
+	teamID := model.GlobalTeamID
+
+	t.Run("import an archive in the background", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		archive := makeTestArchive(t, validTestArchiveBoard("board-1", "First board"), validTestArchiveBoard("board-2", "Second board"))
+
+		job, resp := th.Client.CreateImportJob(teamID, bytes.NewReader(archive), false)
+		th.CheckAccepted(resp)
+		require.NotNil(t, job)
+		require.NotEmpty(t, job.ID)
+		require.Equal(t, th.GetUser1().ID, job.CreatedBy)
+		require.EqualValues(t, len(archive), job.Size)
+
+		job = th.waitForImportJob(teamID, job.ID)
+		require.Equal(t, model.ImportJobStatusCompleted, job.Status)
+		require.Empty(t, job.Error)
+		require.Equal(t, 100, job.Progress)
+		require.Equal(t, 2, job.BoardsProcessed)
+		require.Len(t, job.ImportedBoards, 2)
+
+		board, resp := th.Client.GetBoard(job.ImportedBoards["board-1"], "")
+		th.CheckOK(resp)
+		require.Equal(t, "First board", board.Title)
+
+		blocks, resp := th.Client.GetAllBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 1)
+		require.Equal(t, "A card", blocks[0].Title)
+
+		jobs, resp := th.Client.GetImportJobs(teamID)
+		th.CheckOK(resp)
+		require.Len(t, jobs, 1)
+		require.Equal(t, job.ID, jobs[0].ID)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		archive := makeTestArchive(t, validTestArchiveBoard("board-1", "First board"))
+
+		job, resp := th.Client.CreateImportJob(teamID, bytes.NewReader(archive), true)
+		th.CheckAccepted(resp)
+		require.True(t, job.DryRun)
+
+		job = th.waitForImportJob(teamID, job.ID)
+		require.Equal(t, model.ImportJobStatusCompleted, job.Status)
+		require.Equal(t, 1, job.BoardsProcessed)
+		require.Empty(t, job.ImportedBoards)
+
+		boards, err := th.Server.App().GetBoardsForUserAndTeam(th.GetUser1().ID, teamID)
+		require.NoError(t, err)
+		require.Empty(t, boards)
+	})
+
+	t.Run("dry run of an invalid archive", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		archive := makeTestArchive(t, testArchiveBoard{
+			dir:   "board-1",
+			lines: []string{`{"type":"board","data":{"id":"board-1","title":"No blocks","type":"P"}}`},
+		})
+
+		job, resp := th.Client.CreateImportJob(teamID, bytes.NewReader(archive), true)
+		th.CheckAccepted(resp)
+
+		job = th.waitForImportJob(teamID, job.ID)
+		require.Equal(t, model.ImportJobStatusFailed, job.Status)
+		require.Contains(t, job.Error, "board-1")
+	})
+
+	t.Run("skipped lines and resume", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		withUnsupportedLine := validTestArchiveBoard("board-1", "First board")
+		withUnsupportedLine.lines = append(withUnsupportedLine.lines, `{"type":"reaction","data":{}}`)
+		broken := testArchiveBoard{
+			dir:   "board-2",
+			lines: []string{`{"type":"board","data":{"id":"board-2","title":"Broken"}}`, `{not json`},
+		}
+		archive := makeTestArchive(t, withUnsupportedLine, broken)
+
+		job, resp := th.Client.CreateImportJob(teamID, bytes.NewReader(archive), false)
+		th.CheckAccepted(resp)
+
+		job = th.waitForImportJob(teamID, job.ID)
+		require.Equal(t, model.ImportJobStatusFailed, job.Status)
+		require.Contains(t, job.Error, "board-2")
+		require.Len(t, job.ImportedBoards, 1)
+		require.Contains(t, job.ImportedBoards, "board-1")
+		require.Equal(t, []model.ImportSkippedLine{{
+			Board:  "board-1",
+			Line:   3,
+			Type:   "reaction",
+			Reason: "unsupported archive line type; got reaction, line 3",
+		}}, job.SkippedLines)
+
+		// the boards committed before the failure are kept, and not
+		// imported again when resuming
+		resumed, resp := th.Client.ResumeImportJob(teamID, job.ID)
+		th.CheckAccepted(resp)
+		require.Equal(t, model.ImportJobStatusPending, resumed.Status)
+
+		job = th.waitForImportJob(teamID, job.ID)
+		require.Equal(t, model.ImportJobStatusFailed, job.Status)
+		require.Len(t, job.ImportedBoards, 1)
+		require.Len(t, job.SkippedLines, 1)
+
+		boards, err := th.Server.App().GetBoardsForUserAndTeam(th.GetUser1().ID, teamID)
+		require.NoError(t, err)
+		require.Len(t, boards, 1)
+		require.Equal(t, "First board", boards[0].Title)
+	})
+
+	t.Run("lines of the export options are reported, but not imported", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		withExportOptions := validTestArchiveBoard("board-1", "First board")
+		withExportOptions.lines = append(withExportOptions.lines,
+			`{"type":"block_tombstone","data":{"id":"deleted-card","boardId":"board-1"}}`,
+			`{"type":"board_member","data":{"boardId":"board-1","userId":"other-user","schemeAdmin":true}}`,
+			`{"type":"board_member","data":{"boardId":"board-1","userId":"another-user","schemeViewer":true}}`,
+		)
+		archive := makeTestArchive(t, withExportOptions)
+
+		job, resp := th.Client.CreateImportJob(teamID, bytes.NewReader(archive), false)
+		th.CheckAccepted(resp)
+
+		job = th.waitForImportJob(teamID, job.ID)
+		require.Equal(t, model.ImportJobStatusCompleted, job.Status)
+		require.Len(t, job.ImportedBoards, 1)
+		require.Equal(t, []model.ImportSkippedLine{{
+			Board:  "board-1",
+			Line:   3,
+			Type:   "block_tombstone",
+			Reason: "archive line type not imported into new boards; got block_tombstone, line 3",
+		}, {
+			Board:  "board-1",
+			Line:   4,
+			Type:   "board_member",
+			Reason: "archive line type not imported into new boards; got board_member, line 4",
+		}}, job.SkippedLines)
+
+		members, err := th.Server.App().GetMembersForBoard(job.ImportedBoards["board-1"])
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		require.Equal(t, th.GetUser1().ID, members[0].UserID)
+	})
+
+	t.Run("completed jobs cannot be resumed", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		archive := makeTestArchive(t, validTestArchiveBoard("board-1", "First board"))
+		job, resp := th.Client.CreateImportJob(teamID, bytes.NewReader(archive), false)
+		th.CheckAccepted(resp)
+		th.waitForImportJob(teamID, job.ID)
+
+		resumed, resp := th.Client.ResumeImportJob(teamID, job.ID)
+		th.CheckConflict(resp)
+		require.Nil(t, resumed)
+	})
+
+	t.Run("jobs are only visible to the user that started them", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		archive := makeTestArchive(t, validTestArchiveBoard("board-1", "First board"))
+		job, resp := th.Client.CreateImportJob(teamID, bytes.NewReader(archive), true)
+		th.CheckAccepted(resp)
+		th.waitForImportJob(teamID, job.ID)
+
+		other, resp := th.Client2.GetImportJob(teamID, job.ID)
+		th.CheckNotFound(resp)
+		require.Nil(t, other)
+
+		_, resp = th.Client2.ResumeImportJob(teamID, job.ID)
+		th.CheckNotFound(resp)
+
+		jobs, resp := th.Client2.GetImportJobs(teamID)
+		th.CheckOK(resp)
+		require.Empty(t, jobs)
+	})
+
+	t.Run("not authenticated", func(t *testing.T) {
+		th := SetupTestHelper(t).Start()
+		defer th.TearDown()
+
+		archive := makeTestArchive(t, validTestArchiveBoard("board-1", "First board"))
+		job, resp := th.Client.CreateImportJob(teamID, bytes.NewReader(archive), false)
+		th.CheckUnauthorized(resp)
+		require.Nil(t, job)
+	})
+}
@@ -262,6 +262,9 @@ func runTestCases(t *testing.T, ttCases []TestCase, testData TestData, clients C
 				require.Fail(t, "Unreplaced tokens in url", url)
 			}
 
+			body := strings.ReplaceAll(tc.body, "{PRIVATE_BOARD_ID}", testData.privateBoard.ID)
+			body = strings.ReplaceAll(body, "{PUBLIC_BOARD_ID}", testData.publicBoard.ID)
+
 			var response *http.Response
 			var err error
 			switch tc.method {
@@ -269,16 +272,16 @@ func runTestCases(t *testing.T, ttCases []TestCase, testData TestData, clients C
 				response, err = reqClient.DoAPIGet(url, "")
 				defer response.Body.Close()
 			case methodPost:
-				response, err = reqClient.DoAPIPost(url, tc.body)
+				response, err = reqClient.DoAPIPost(url, body)
 				defer response.Body.Close()
 			case methodPatch:
-				response, err = reqClient.DoAPIPatch(url, tc.body)
+				response, err = reqClient.DoAPIPatch(url, body)
 				defer response.Body.Close()
 			case methodPut:
-				response, err = reqClient.DoAPIPut(url, tc.body)
+				response, err = reqClient.DoAPIPut(url, body)
 				defer response.Body.Close()
 			case methodDelete:
-				response, err = reqClient.DoAPIDelete(url, tc.body)
+				response, err = reqClient.DoAPIDelete(url, body)
 				defer response.Body.Close()
 			}
 
@@ -1525,6 +1528,243 @@ func TestPermissionsGetBoardMembers(t *testing.T) {
 	})
 }
 
+func TestPermissionsGetBoardAuditRecords(t *testing.T) {
+	ttCases := []TestCase{
+		{"/boards/{PRIVATE_BOARD_ID}/audit", methodGet, "", userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/audit", methodGet, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/audit", methodGet, "", userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/audit", methodGet, "", userViewer, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/audit", methodGet, "", userCommenter, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/audit", methodGet, "", userEditor, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/audit", methodGet, "", userAdmin, http.StatusOK, 1},
+
+		{"/boards/{PUBLIC_BOARD_ID}/audit", methodGet, "", userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/audit", methodGet, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/audit", methodGet, "", userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/audit", methodGet, "", userViewer, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/audit", methodGet, "", userCommenter, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/audit", methodGet, "", userEditor, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/audit", methodGet, "", userAdmin, http.StatusOK, 1},
+	}
+
+	t.Run("plugin", func(t *testing.T) {
+		th := SetupTestHelperPluginMode(t)
+		defer th.TearDown()
+		clients := setupClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+	t.Run("local", func(t *testing.T) {
+		th := SetupTestHelperLocalMode(t)
+		defer th.TearDown()
+		clients := setupLocalClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+}
+
+func TestPermissionsGetBoardChart(t *testing.T) {
+	// the test boards have no card properties, so the chart options are
+	// rejected once the permissions are checked
+	ttCases := []TestCase{
+		{"/boards/{PRIVATE_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userViewer, http.StatusBadRequest, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userCommenter, http.StatusBadRequest, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userEditor, http.StatusBadRequest, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userAdmin, http.StatusBadRequest, 0},
+
+		{"/boards/{PUBLIC_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userViewer, http.StatusBadRequest, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userCommenter, http.StatusBadRequest, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userEditor, http.StatusBadRequest, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/insights?chart=cumulativeFlow&property_id=status", methodGet, "", userAdmin, http.StatusBadRequest, 0},
+	}
+
+	t.Run("plugin", func(t *testing.T) {
+		th := SetupTestHelperPluginMode(t)
+		defer th.TearDown()
+		clients := setupClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+	t.Run("local", func(t *testing.T) {
+		th := SetupTestHelperLocalMode(t)
+		defer th.TearDown()
+		clients := setupLocalClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+}
+
+func TestPermissionsCopyCard(t *testing.T) {
+	body := toJSON(t, model.CardTransferRequest{BoardID: "{PRIVATE_BOARD_ID}"})
+	ttCases := []TestCase{
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/copy", methodPost, body, userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/copy", methodPost, body, userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/copy", methodPost, body, userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/copy", methodPost, body, userViewer, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/copy", methodPost, body, userCommenter, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/copy", methodPost, body, userEditor, http.StatusOK, 1},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/copy", methodPost, body, userAdmin, http.StatusOK, 1},
+	}
+
+	t.Run("plugin", func(t *testing.T) {
+		th := SetupTestHelperPluginMode(t)
+		defer th.TearDown()
+		clients := setupClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+	t.Run("local", func(t *testing.T) {
+		th := SetupTestHelperLocalMode(t)
+		defer th.TearDown()
+		clients := setupLocalClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+}
+
+func TestPermissionsBulkCardOperation(t *testing.T) {
+	body := toJSON(t, model.BulkCardOperation{CardIDs: []string{"block-3"}, Action: model.BulkCardActionArchive})
+	ttCases := []TestCase{
+		{"/boards/{PUBLIC_BOARD_ID}/cards/bulk", methodPost, body, userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/cards/bulk", methodPost, body, userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/cards/bulk", methodPost, body, userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/cards/bulk", methodPost, body, userViewer, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/cards/bulk", methodPost, body, userCommenter, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/cards/bulk", methodPost, body, userEditor, http.StatusOK, 1},
+		{"/boards/{PUBLIC_BOARD_ID}/cards/bulk", methodPost, body, userAdmin, http.StatusOK, 1},
+	}
+
+	t.Run("plugin", func(t *testing.T) {
+		th := SetupTestHelperPluginMode(t)
+		defer th.TearDown()
+		clients := setupClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+	t.Run("local", func(t *testing.T) {
+		th := SetupTestHelperLocalMode(t)
+		defer th.TearDown()
+		clients := setupLocalClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+}
+
+func TestPermissionsArchiveCard(t *testing.T) {
+	ttCases := []TestCase{
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/archive", methodPost, "", userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/archive", methodPost, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/archive", methodPost, "", userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/archive", methodPost, "", userViewer, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/archive", methodPost, "", userCommenter, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/archive", methodPost, "", userEditor, http.StatusOK, 1},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/archive", methodPost, "", userAdmin, http.StatusOK, 1},
+
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/unarchive", methodPost, "", userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/unarchive", methodPost, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/unarchive", methodPost, "", userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/unarchive", methodPost, "", userViewer, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/unarchive", methodPost, "", userCommenter, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/unarchive", methodPost, "", userEditor, http.StatusOK, 1},
+		{"/boards/{PUBLIC_BOARD_ID}/blocks/block-3/unarchive", methodPost, "", userAdmin, http.StatusOK, 1},
+	}
+
+	t.Run("plugin", func(t *testing.T) {
+		th := SetupTestHelperPluginMode(t)
+		defer th.TearDown()
+		clients := setupClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+	t.Run("local", func(t *testing.T) {
+		th := SetupTestHelperLocalMode(t)
+		defer th.TearDown()
+		clients := setupLocalClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+}
+
+func TestPermissionsArchiveBoard(t *testing.T) {
+	ttCases := []TestCase{
+		{"/boards/{PUBLIC_BOARD_ID}/archive", methodPost, "", userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/archive", methodPost, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/archive", methodPost, "", userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/archive", methodPost, "", userViewer, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/archive", methodPost, "", userCommenter, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/archive", methodPost, "", userEditor, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/archive", methodPost, "", userAdmin, http.StatusOK, 1},
+
+		{"/boards/{PUBLIC_BOARD_ID}", methodPatch, `{"archiveAt": 0}`, userEditor, http.StatusForbidden, 0},
+
+		{"/boards/{PUBLIC_BOARD_ID}/unarchive", methodPost, "", userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/unarchive", methodPost, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/unarchive", methodPost, "", userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/unarchive", methodPost, "", userViewer, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/unarchive", methodPost, "", userCommenter, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/unarchive", methodPost, "", userEditor, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/unarchive", methodPost, "", userAdmin, http.StatusOK, 1},
+	}
+
+	t.Run("plugin", func(t *testing.T) {
+		th := SetupTestHelperPluginMode(t)
+		defer th.TearDown()
+		clients := setupClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+	t.Run("local", func(t *testing.T) {
+		th := SetupTestHelperLocalMode(t)
+		defer th.TearDown()
+		clients := setupLocalClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+}
+
+func TestPermissionsPublishTemplate(t *testing.T) {
+	ttCases := []TestCase{
+		{"/boards/{PRIVATE_BOARD_ID}/publish", methodPost, "{}", userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/publish", methodPost, "{}", userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/publish", methodPost, "{}", userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/publish", methodPost, "{}", userViewer, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/publish", methodPost, "{}", userCommenter, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/publish", methodPost, "{}", userEditor, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/publish", methodPost, "{}", userAdmin, http.StatusOK, 1},
+
+		{"/boards/{PUBLIC_BOARD_ID}/publish", methodPost, "{}", userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/publish", methodPost, "{}", userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/publish", methodPost, "{}", userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/publish", methodPost, "{}", userViewer, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/publish", methodPost, "{}", userCommenter, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/publish", methodPost, "{}", userEditor, http.StatusForbidden, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/publish", methodPost, "{}", userAdmin, http.StatusOK, 1},
+
+		{"/boards/{PUBLIC_TEMPLATE_ID}/publish", methodPost, "{}", userAdmin, http.StatusBadRequest, 0},
+	}
+
+	t.Run("plugin", func(t *testing.T) {
+		th := SetupTestHelperPluginMode(t)
+		defer th.TearDown()
+		clients := setupClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+	t.Run("local", func(t *testing.T) {
+		th := SetupTestHelperLocalMode(t)
+		defer th.TearDown()
+		clients := setupLocalClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+}
+
 func TestPermissionsCreateBoardMembers(t *testing.T) {
 	ttCasesF := func(testData TestData) []TestCase {
 		boardMemberJSON := func(boardID string) string {
@@ -2296,6 +2536,33 @@ func TestPermissionsGetMyMemberships(t *testing.T) {
 	})
 }
 
+func TestPermissionsGetMyCards(t *testing.T) {
+	ttCases := []TestCase{
+		{"/users/me/cards", methodGet, "", userAnon, http.StatusUnauthorized, 0},
+		{"/users/me/cards", methodGet, "", userNoTeamMember, http.StatusOK, 1},
+		{"/users/me/cards", methodGet, "", userTeamMember, http.StatusOK, 1},
+		{"/users/me/cards", methodGet, "", userViewer, http.StatusOK, 1},
+		{"/users/me/cards", methodGet, "", userCommenter, http.StatusOK, 1},
+		{"/users/me/cards", methodGet, "", userEditor, http.StatusOK, 1},
+		{"/users/me/cards", methodGet, "", userAdmin, http.StatusOK, 1},
+	}
+
+	t.Run("plugin", func(t *testing.T) {
+		th := SetupTestHelperPluginMode(t)
+		defer th.TearDown()
+		clients := setupClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+	t.Run("local", func(t *testing.T) {
+		th := SetupTestHelperLocalMode(t)
+		defer th.TearDown()
+		clients := setupLocalClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, ttCases, testData, clients)
+	})
+}
+
 func TestPermissionsGetUser(t *testing.T) {
 	ttCases := []TestCase{
 		{"/users/{USER_NO_TEAM_MEMBER_ID}", methodGet, "", userAnon, http.StatusUnauthorized, 0},
@@ -3009,7 +3276,7 @@ func TestPermissionsGetFile(t *testing.T) {
 		clients := setupClients(th)
 		testData := setupData(t, th)
 
-		newFileID, err := th.Server.App().SaveFile(bytes.NewBuffer([]byte("test")), "test-team", testData.privateBoard.ID, "test.png")
+		newFileID, err := th.Server.App().SaveFile(bytes.NewBuffer([]byte("test")), "test-team", testData.privateBoard.ID, "test.png", userAdminID)
 		require.NoError(t, err)
 
 		ttCases := ttCasesF()
@@ -3024,7 +3291,7 @@ func TestPermissionsGetFile(t *testing.T) {
 		clients := setupLocalClients(th)
 		testData := setupData(t, th)
 
-		newFileID, err := th.Server.App().SaveFile(bytes.NewBuffer([]byte("test")), "test-team", testData.privateBoard.ID, "test.png")
+		newFileID, err := th.Server.App().SaveFile(bytes.NewBuffer([]byte("test")), "test-team", testData.privateBoard.ID, "test.png", userAdminID)
 		require.NoError(t, err)
 
 		ttCases := ttCasesF()
@@ -3532,3 +3799,69 @@ func TestPermissionsChannel(t *testing.T) {
 		runTestCases(t, ttCases, testData, clients)
 	})
 }
+
+func TestPermissionsSharedCategories(t *testing.T) {
+	sharedCategory := toJSON(t, model.Category{
+		Name:   "Shared category",
+		TeamID: "test-team",
+		Type:   model.CategoryTypeShared,
+	})
+
+	createCases := func(status int) []TestCase {
+		return []TestCase{
+			{"/teams/test-team/shared-categories", methodPost, sharedCategory, userAnon, http.StatusUnauthorized, 0},
+			{"/teams/test-team/shared-categories", methodPost, sharedCategory, userNoTeamMember, status, 1},
+			{"/teams/test-team/shared-categories", methodPost, sharedCategory, userTeamMember, status, 1},
+			{"/teams/test-team/shared-categories", methodPost, sharedCategory, userViewer, status, 1},
+			{"/teams/test-team/shared-categories", methodPost, sharedCategory, userCommenter, status, 1},
+			{"/teams/test-team/shared-categories", methodPost, sharedCategory, userEditor, status, 1},
+			{"/teams/test-team/shared-categories", methodPost, sharedCategory, userAdmin, http.StatusOK, 1},
+
+			{"/teams/test-team/categories", methodPost, sharedCategory, userAdmin, http.StatusBadRequest, 0},
+		}
+	}
+
+	boardCases := func(categoryID string, memberStatus, nonMemberStatus int) []TestCase {
+		url := "/teams/test-team/shared-categories/" + categoryID + "/boards/{PUBLIC_BOARD_ID}"
+		return []TestCase{
+			{url, methodPost, "", userAnon, http.StatusUnauthorized, 0},
+			{url, methodPost, "", userNoTeamMember, nonMemberStatus, 0},
+			{url, methodPost, "", userTeamMember, nonMemberStatus, 0},
+			{url, methodPost, "", userViewer, memberStatus, 0},
+			{url, methodPost, "", userCommenter, memberStatus, 0},
+			{url, methodPost, "", userEditor, memberStatus, 0},
+			{url, methodPost, "", userAdmin, http.StatusOK, 0},
+		}
+	}
+
+	createSharedCategory := func(t *testing.T, th *TestHelper) *model.Category {
+		category, err := th.Server.App().CreateCategory(&model.Category{
+			Name:   "Shared category",
+			TeamID: "test-team",
+			Type:   model.CategoryTypeShared,
+		})
+		require.NoError(t, err)
+		return category
+	}
+
+	t.Run("plugin", func(t *testing.T) {
+		th := SetupTestHelperPluginMode(t)
+		defer th.TearDown()
+		clients := setupClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, createCases(http.StatusForbidden), testData, clients)
+
+		category := createSharedCategory(t, th)
+		runTestCases(t, boardCases(category.ID, http.StatusForbidden, http.StatusForbidden), testData, clients)
+	})
+	t.Run("local", func(t *testing.T) {
+		th := SetupTestHelperLocalMode(t)
+		defer th.TearDown()
+		clients := setupLocalClients(th)
+		testData := setupData(t, th)
+		runTestCases(t, createCases(http.StatusOK), testData, clients)
+
+		category := createSharedCategory(t, th)
+		runTestCases(t, boardCases(category.ID, http.StatusOK, http.StatusForbidden), testData, clients)
+	})
+}
@@ -0,0 +1,105 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyValidation(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board, err := th.Server.App().CreateBoard(&model.Board{
+		Title:  "board with validated properties",
+		Type:   model.BoardTypeOpen,
+		TeamID: testTeamID,
+	}, th.GetUser1().ID, true)
+	require.NoError(t, err)
+
+	_, err = th.Server.App().AddMemberToBoard(&model.BoardMember{
+		UserID:       th.GetUser2().ID,
+		BoardID:      board.ID,
+		SchemeEditor: true,
+	})
+	require.NoError(t, err)
+
+	patch := &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "required": true, "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+			}},
+			{"id": "estimate", "name": "Estimate", "type": "number", "min": 0, "max": 10},
+		},
+	}
+	_, resp := th.Client.PatchBoard(board.ID, patch)
+	th.CheckOK(resp)
+
+	newCard := func(properties map[string]interface{}) model.Block {
+		now := utils.GetMillis()
+		return model.Block{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			CreateAt: now,
+			UpdateAt: now,
+			Fields:   map[string]interface{}{"properties": properties},
+		}
+	}
+
+	t.Run("invalid constraints are rejected", func(t *testing.T) {
+		invalidPatch := &model.BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "code", "name": "Code", "type": "text", "pattern": "(["},
+			},
+		}
+		_, resp := th.Client.PatchBoard(board.ID, invalidPatch)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("lenient boards accept invalid values", func(t *testing.T) {
+		_, resp := th.Client.InsertBlocks(board.ID, []model.Block{newCard(map[string]interface{}{"estimate": "20"})})
+		th.CheckOK(resp)
+	})
+
+	t.Run("only board admins can change the mode", func(t *testing.T) {
+		modePatch := &model.BoardPatch{
+			UpdatedProperties: map[string]interface{}{model.BoardPropertyPropertyValidation: model.PropertyValidationStrict},
+		}
+		_, resp := th.Client2.PatchBoard(board.ID, modePatch)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client.PatchBoard(board.ID, modePatch)
+		th.CheckOK(resp)
+	})
+
+	t.Run("strict boards reject invalid values", func(t *testing.T) {
+		_, resp := th.Client2.InsertBlocks(board.ID, []model.Block{newCard(map[string]interface{}{"estimate": "5"})})
+		th.CheckBadRequest(resp)
+		require.Contains(t, resp.Error.Error(), model.PropertyValidationRequired)
+
+		_, resp = th.Client2.InsertBlocks(board.ID, []model.Block{newCard(map[string]interface{}{"status": "done"})})
+		th.CheckBadRequest(resp)
+		require.Contains(t, resp.Error.Error(), model.PropertyValidationUnknownOption)
+
+		blocks, resp := th.Client2.InsertBlocks(board.ID, []model.Block{newCard(map[string]interface{}{"status": "todo", "estimate": "5"})})
+		th.CheckOK(resp)
+		require.Len(t, blocks, 1)
+
+		outOfRange := &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{"status": "todo", "estimate": "11"}},
+		}
+		_, resp = th.Client2.PatchBlock(board.ID, blocks[0].ID, outOfRange)
+		th.CheckBadRequest(resp)
+		require.Contains(t, resp.Error.Error(), model.PropertyValidationAboveMaximum)
+
+		inRange := &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{"status": "todo", "estimate": "10"}},
+		}
+		_, resp = th.Client2.PatchBlock(board.ID, blocks[0].ID, inRange)
+		th.CheckOK(resp)
+	})
+}
@@ -0,0 +1,189 @@
+package integrationtests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedCategories(t *testing.T) {
+	t.Run("create a shared category", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		category, resp := th.Client.CreateSharedCategory(model.Category{
+			Name:   "Engineering",
+			TeamID: testTeamID,
+		})
+		th.CheckOK(resp)
+		require.NotNil(t, category)
+		require.Equal(t, model.CategoryTypeShared, category.Type)
+		require.Empty(t, category.UserID)
+
+		sharedCategories, resp := th.Client.GetSharedCategoryBoards(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, sharedCategories, 1)
+		require.Equal(t, category.ID, sharedCategories[0].ID)
+
+		// shared categories are listed after the user's ones for both users
+		privateCategory, resp := th.Client.CreateCategory(model.Category{
+			Name:   "Mine",
+			UserID: th.GetUser1().ID,
+			TeamID: testTeamID,
+		})
+		th.CheckOK(resp)
+
+		categories, resp := th.Client.GetUserCategoryBoards(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, categories, 2)
+		require.Equal(t, privateCategory.ID, categories[0].ID)
+		require.Equal(t, category.ID, categories[1].ID)
+
+		categories, resp = th.Client2.GetUserCategoryBoards(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, categories, 1)
+		require.Equal(t, category.ID, categories[0].ID)
+	})
+
+	t.Run("new boards are placed in the default category", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		category, resp := th.Client.CreateSharedCategory(model.Category{
+			Name:      "Inbox",
+			TeamID:    testTeamID,
+			IsDefault: true,
+		})
+		th.CheckOK(resp)
+		require.True(t, category.IsDefault)
+
+		board, resp := th.Client.CreateBoard(&model.Board{
+			Title:  "New board",
+			Type:   model.BoardTypeOpen,
+			TeamID: testTeamID,
+		})
+		th.CheckOK(resp)
+
+		template, resp := th.Client.CreateBoard(&model.Board{
+			Title:      "New template",
+			Type:       model.BoardTypeOpen,
+			TeamID:     testTeamID,
+			IsTemplate: true,
+		})
+		th.CheckOK(resp)
+
+		sharedCategories, resp := th.Client.GetSharedCategoryBoards(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, sharedCategories, 1)
+		require.Contains(t, sharedCategories[0].BoardIDs, board.ID)
+		require.NotContains(t, sharedCategories[0].BoardIDs, template.ID)
+
+		// only one category can be the default of the team
+		other, resp := th.Client.CreateSharedCategory(model.Category{
+			Name:      "Other",
+			TeamID:    testTeamID,
+			IsDefault: true,
+		})
+		th.CheckOK(resp)
+
+		sharedCategories, resp = th.Client.GetSharedCategoryBoards(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, sharedCategories, 2)
+		for _, sharedCategory := range sharedCategories {
+			require.Equal(t, sharedCategory.ID == other.ID, sharedCategory.IsDefault)
+		}
+	})
+
+	t.Run("move boards between shared categories", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		category, resp := th.Client.CreateSharedCategory(model.Category{Name: "Shared", TeamID: testTeamID})
+		th.CheckOK(resp)
+
+		board, resp := th.Client.CreateBoard(&model.Board{Title: "Board", Type: model.BoardTypeOpen, TeamID: testTeamID})
+		th.CheckOK(resp)
+
+		privateBoard, resp := th.Client.CreateBoard(&model.Board{Title: "Private", Type: model.BoardTypePrivate, TeamID: testTeamID})
+		th.CheckOK(resp)
+
+		resp = th.Client.UpdateSharedCategoryBoard(testTeamID, category.ID, board.ID)
+		th.CheckOK(resp)
+		resp = th.Client.UpdateSharedCategoryBoard(testTeamID, category.ID, privateBoard.ID)
+		th.CheckOK(resp)
+
+		sharedCategories, resp := th.Client.GetSharedCategoryBoards(testTeamID)
+		th.CheckOK(resp)
+		require.ElementsMatch(t, []string{board.ID, privateBoard.ID}, sharedCategories[0].BoardIDs)
+
+		// boards the user can't see are not listed
+		sharedCategories, resp = th.Client2.GetSharedCategoryBoards(testTeamID)
+		th.CheckOK(resp)
+		require.Equal(t, []string{board.ID}, sharedCategories[0].BoardIDs)
+
+		resp = th.Client.UpdateSharedCategoryBoard(testTeamID, "0", board.ID)
+		th.CheckOK(resp)
+
+		sharedCategories, resp = th.Client.GetSharedCategoryBoards(testTeamID)
+		th.CheckOK(resp)
+		require.Equal(t, []string{privateBoard.ID}, sharedCategories[0].BoardIDs)
+
+		// private categories can't be used through the shared API
+		privateCategory, resp := th.Client.CreateCategory(model.Category{Name: "Mine", UserID: th.GetUser1().ID, TeamID: testTeamID})
+		th.CheckOK(resp)
+
+		resp = th.Client.UpdateSharedCategoryBoard(testTeamID, privateCategory.ID, board.ID)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("nested categories", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		parent, resp := th.Client.CreateSharedCategory(model.Category{Name: "Parent", TeamID: testTeamID, SortOrder: 1})
+		th.CheckOK(resp)
+
+		child, resp := th.Client.CreateSharedCategory(model.Category{Name: "Child", TeamID: testTeamID, ParentID: parent.ID, SortOrder: 2})
+		th.CheckOK(resp)
+		require.Equal(t, parent.ID, child.ParentID)
+
+		grandchild, resp := th.Client.CreateSharedCategory(model.Category{Name: "Grandchild", TeamID: testTeamID, ParentID: child.ID, SortOrder: 3})
+		th.CheckOK(resp)
+
+		// the nesting can't create cycles
+		parent.ParentID = grandchild.ID
+		_, resp = th.Client.UpdateSharedCategory(*parent)
+		th.CheckBadRequest(resp)
+
+		// a shared category can't be nested in a private one
+		privateCategory, resp := th.Client.CreateCategory(model.Category{Name: "Mine", UserID: th.GetUser1().ID, TeamID: testTeamID})
+		th.CheckOK(resp)
+		_, resp = th.Client.CreateSharedCategory(model.Category{Name: "Invalid", TeamID: testTeamID, ParentID: privateCategory.ID})
+		th.CheckBadRequest(resp)
+
+		// deleting a category moves its children to its parent
+		resp = th.Client.DeleteSharedCategory(testTeamID, child.ID)
+		th.CheckOK(resp)
+
+		sharedCategories, resp := th.Client.GetSharedCategoryBoards(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, sharedCategories, 2)
+		require.Equal(t, parent.ID, sharedCategories[0].ID)
+		require.Equal(t, grandchild.ID, sharedCategories[1].ID)
+		require.Equal(t, parent.ID, sharedCategories[1].ParentID)
+	})
+
+	t.Run("shared categories can't be deleted through the user API", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		category, resp := th.Client.CreateSharedCategory(model.Category{Name: "Shared", TeamID: testTeamID})
+		th.CheckOK(resp)
+
+		r, err := th.Client.DoAPIDelete(th.Client.GetTeamRoute(testTeamID)+"/categories/"+category.ID, "")
+		require.Error(t, err)
+		require.Equal(t, http.StatusForbidden, r.StatusCode)
+	})
+}
@@ -0,0 +1,266 @@
+package integrationtests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func TestTemplateLibrary(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	teamID := "team-id"
+	board := th.CreateBoard(teamID, model.BoardTypeOpen)
+
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do", "color": "propColorGray"},
+				},
+			},
+		},
+	})
+	th.CheckOK(resp)
+
+	boardView := model.Block{
+		ID:       utils.NewID(utils.IDTypeView),
+		BoardID:  board.ID,
+		ParentID: board.ID,
+		Type:     model.TypeView,
+		Title:    "Board view",
+		Fields:   map[string]interface{}{"viewType": "board"},
+		CreateAt: 1,
+		UpdateAt: 1,
+	}
+	_, resp = th.Client.InsertBlocks(board.ID, []model.Block{boardView})
+	th.CheckOK(resp)
+
+	opts := &model.PublishTemplateOptions{
+		Title:    "Sprint planning",
+		Category: "Engineering",
+		Tags:     []string{"sprint"},
+	}
+
+	t.Run("a user that is not a board admin cannot publish the board", func(t *testing.T) {
+		template, resp := th.Client2.PublishTemplate(board.ID, opts)
+		th.CheckForbidden(resp)
+		require.Nil(t, template)
+	})
+
+	template, resp := th.Client.PublishTemplate(board.ID, opts)
+	th.CheckOK(resp)
+	require.NotNil(t, template)
+	require.Equal(t, 1, template.Version)
+	require.Equal(t, board.ID, template.SourceBoardID)
+	require.Equal(t, "Engineering", template.Category)
+	require.Equal(t, []string{"sprint"}, template.Tags)
+	require.NotNil(t, template.Board)
+	require.True(t, template.Board.IsTemplate)
+	require.Equal(t, 1, template.Board.TemplateVersion)
+	require.Equal(t, model.BoardTypeOpen, template.Board.Type)
+	require.Equal(t, "Sprint planning", template.Board.Title)
+
+	t.Run("a template board cannot be published", func(t *testing.T) {
+		_, resp := th.Client.PublishTemplate(template.BoardID, &model.PublishTemplateOptions{})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("team members can browse the library", func(t *testing.T) {
+		templates, resp := th.Client2.GetTemplateLibrary(teamID, "", "")
+		th.CheckOK(resp)
+		require.Len(t, templates, 1)
+		require.Equal(t, template.ID, templates[0].ID)
+
+		templates, resp = th.Client2.GetTemplateLibrary(teamID, "Engineering", "SPRINT")
+		th.CheckOK(resp)
+		require.Len(t, templates, 1)
+
+		templates, resp = th.Client2.GetTemplateLibrary(teamID, "Product", "")
+		th.CheckOK(resp)
+		require.Empty(t, templates)
+
+		rTemplate, resp := th.Client2.GetTemplate(template.ID)
+		th.CheckOK(resp)
+		require.Equal(t, template.BoardID, rTemplate.BoardID)
+	})
+
+	// a board created from the template is linked to it
+	bab, resp := th.Client2.DuplicateBoard(template.BoardID, false, teamID)
+	th.CheckOK(resp)
+	require.Len(t, bab.Boards, 1)
+	derivedBoard := bab.Boards[0]
+
+	// publish a new version with an extra property, option and view
+	_, resp = th.Client.PatchBoard(board.ID, &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do", "color": "propColorGray"},
+					map[string]interface{}{"id": "done", "value": "Done", "color": "propColorGreen"},
+				},
+			},
+			{"id": "estimate", "name": "Estimate", "type": "number", "options": []interface{}{}},
+		},
+	})
+	th.CheckOK(resp)
+
+	tableView := boardView
+	tableView.ID = utils.NewID(utils.IDTypeView)
+	tableView.Title = "Table view"
+	tableView.Fields = map[string]interface{}{"viewType": "table"}
+	_, resp = th.Client.InsertBlocks(board.ID, []model.Block{tableView})
+	th.CheckOK(resp)
+
+	newVersion, resp := th.Client.PublishTemplate(board.ID, &model.PublishTemplateOptions{})
+	th.CheckOK(resp)
+	require.Equal(t, template.ID, newVersion.ID)
+	require.Equal(t, 2, newVersion.Version)
+	require.NotEqual(t, template.BoardID, newVersion.BoardID)
+	require.Equal(t, "Engineering", newVersion.Category)
+	require.Equal(t, 2, newVersion.Board.TemplateVersion)
+
+	t.Run("the previous version board is deleted", func(t *testing.T) {
+		_, resp := th.Client.GetBoard(template.BoardID, "")
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("a user that is not a template admin cannot propagate it", func(t *testing.T) {
+		report, resp := th.Client2.PropagateTemplate(template.ID)
+		th.CheckForbidden(resp)
+		require.Nil(t, report)
+	})
+
+	t.Run("boards the user can't modify are skipped", func(t *testing.T) {
+		report, resp := th.Client.PropagateTemplate(template.ID)
+		th.CheckOK(resp)
+		require.Empty(t, report.Boards)
+		require.Equal(t, []string{derivedBoard.ID}, report.SkippedBoardIDs)
+
+		rBoard, resp := th.Client2.GetBoard(derivedBoard.ID, "")
+		th.CheckOK(resp)
+		require.Len(t, rBoard.CardProperties, 1)
+	})
+
+	t.Run("propagate the new version to derived boards", func(t *testing.T) {
+		_, resp := th.Client2.AddMemberToBoard(&model.BoardMember{
+			BoardID:      derivedBoard.ID,
+			UserID:       th.GetUser1().ID,
+			SchemeEditor: true,
+		})
+		th.CheckOK(resp)
+
+		report, resp := th.Client.PropagateTemplate(template.ID)
+		th.CheckOK(resp)
+		require.Equal(t, 2, report.Version)
+		require.Len(t, report.Boards, 1)
+		require.Empty(t, report.SkippedBoardIDs)
+
+		update := report.Boards[0]
+		require.Equal(t, derivedBoard.ID, update.BoardID)
+		require.Equal(t, []string{"estimate"}, update.AddedProperties)
+		require.Equal(t, []string{"status"}, update.UpdatedProperties)
+		require.Len(t, update.AddedViews, 1)
+
+		rBoard, resp := th.Client2.GetBoard(derivedBoard.ID, "")
+		th.CheckOK(resp)
+		require.Len(t, rBoard.CardProperties, 2)
+
+		blocks, resp := th.Client2.GetAllBlocksForBoard(derivedBoard.ID)
+		th.CheckOK(resp)
+		viewTitles := []string{}
+		for _, block := range blocks {
+			if block.Type == model.TypeView {
+				viewTitles = append(viewTitles, block.Title)
+			}
+		}
+		require.ElementsMatch(t, []string{"Board view", "Table view"}, viewTitles)
+
+		// boards already on the current version are skipped
+		report, resp = th.Client.PropagateTemplate(template.ID)
+		th.CheckOK(resp)
+		require.Empty(t, report.Boards)
+
+		update, resp = th.Client2.UpdateBoardFromTemplate(derivedBoard.ID)
+		th.CheckOK(resp)
+		require.False(t, update.HasChanges())
+	})
+
+	t.Run("update the template metadata", func(t *testing.T) {
+		category := "Product"
+		_, resp := th.Client2.PatchTemplate(template.ID, &model.TemplatePatch{Category: &category})
+		th.CheckForbidden(resp)
+
+		rTemplate, resp := th.Client.PatchTemplate(template.ID, &model.TemplatePatch{Category: &category, Tags: []string{"roadmap"}})
+		th.CheckOK(resp)
+		require.Equal(t, "Product", rTemplate.Category)
+		require.Equal(t, []string{"roadmap"}, rTemplate.Tags)
+
+		_, resp = th.Client.PatchTemplate(template.ID, &model.TemplatePatch{Tags: []string{""}})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("export and import a template", func(t *testing.T) {
+		archive, resp := th.Client.ExportTemplate(template.ID)
+		th.CheckOK(resp)
+		require.NotEmpty(t, archive)
+
+		templates, resp := th.Client.ImportTemplates(teamID, bytes.NewReader(archive), "Ignored", nil)
+		th.CheckOK(resp)
+		require.Len(t, templates, 1)
+
+		imported := templates[0]
+		require.NotEqual(t, template.ID, imported.ID)
+		require.Equal(t, "Product", imported.Category)
+		require.Equal(t, []string{"roadmap"}, imported.Tags)
+		require.Equal(t, 2, imported.Version)
+		require.True(t, imported.Board.IsTemplate)
+		require.Equal(t, 2, imported.Board.TemplateVersion)
+
+		blocks, resp := th.Client.GetAllBlocksForBoard(imported.BoardID)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 2)
+	})
+
+	t.Run("import a board archive as a template", func(t *testing.T) {
+		archive, resp := th.Client.ExportBoardArchive(board.ID)
+		th.CheckOK(resp)
+
+		templates, resp := th.Client.ImportTemplates(teamID, bytes.NewReader(archive), "Imported", []string{"legacy"})
+		th.CheckOK(resp)
+		require.Len(t, templates, 1)
+		require.Equal(t, "Imported", templates[0].Category)
+		require.Equal(t, []string{"legacy"}, templates[0].Tags)
+		require.Equal(t, 1, templates[0].Version)
+		require.True(t, templates[0].Board.IsTemplate)
+	})
+
+	t.Run("unpublish a template", func(t *testing.T) {
+		_, resp := th.Client2.UnpublishTemplate(template.ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client.UnpublishTemplate(template.ID)
+		th.CheckOK(resp)
+
+		_, resp = th.Client.GetTemplate(template.ID)
+		th.CheckNotFound(resp)
+
+		_, resp = th.Client.GetBoard(newVersion.BoardID, "")
+		th.CheckNotFound(resp)
+
+		// boards created from the template are kept
+		_, resp = th.Client2.GetBoard(derivedBoard.ID, "")
+		th.CheckOK(resp)
+	})
+}
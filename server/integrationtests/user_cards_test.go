@@ -0,0 +1,119 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMyCards(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	me := th.GetUser1()
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	title := "My project"
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{
+		Title: &title,
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "owner", "name": "Owner", "type": "person"},
+			{"id": "due", "name": "Due", "type": "date"},
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "done", "value": "Done"},
+			}},
+		},
+	})
+	th.CheckOK(resp)
+
+	now := utils.GetMillis()
+	card := func(title string, properties map[string]interface{}) model.Block {
+		return model.Block{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			Title:    title,
+			CreateAt: now,
+			UpdateAt: now,
+			Fields:   map[string]interface{}{"properties": properties},
+		}
+	}
+	_, resp = th.Client.InsertBlocks(board.ID, []model.Block{
+		card("write the spec", map[string]interface{}{"owner": me.ID, "status": "done", "due": `{"from":1000}`}),
+		card("review the spec", map[string]interface{}{"owner": me.ID, "status": "todo", "due": `{"from":2000}`}),
+		card("ship it", map[string]interface{}{"owner": "other-user-id"}),
+	})
+	th.CheckOK(resp)
+
+	titles := func(list *model.UserCardsList) []string {
+		titles := make([]string, len(list.Items))
+		for i, item := range list.Items {
+			titles[i] = item.Card.Title
+		}
+		return titles
+	}
+
+	t.Run("cards created by or assigned to the user", func(t *testing.T) {
+		list, resp := th.Client.GetMyCards(model.QueryUserCardsOptions{SortBy: model.UserCardsSortTitle})
+		th.CheckOK(resp)
+		// the cards are inserted by the user, so they are all listed
+		require.Equal(t, []string{"review the spec", "ship it", "write the spec"}, titles(list))
+		require.Equal(t, "My project", list.Items[0].BoardTitle)
+		require.Equal(t, testTeamID, list.Items[0].TeamID)
+		require.Equal(t, int64(2000), list.Items[0].DueAt)
+
+		// users that aren't members of the board have no cards
+		list, resp = th.Client2.GetMyCards(model.QueryUserCardsOptions{})
+		th.CheckOK(resp)
+		require.Empty(t, list.Items)
+	})
+
+	t.Run("filters and sort", func(t *testing.T) {
+		list, resp := th.Client.GetMyCards(model.QueryUserCardsOptions{Status: "done"})
+		th.CheckOK(resp)
+		require.Equal(t, []string{"write the spec"}, titles(list))
+
+		list, resp = th.Client.GetMyCards(model.QueryUserCardsOptions{DueAfter: 1500})
+		th.CheckOK(resp)
+		require.Equal(t, []string{"review the spec"}, titles(list))
+
+		list, resp = th.Client.GetMyCards(model.QueryUserCardsOptions{SortBy: model.UserCardsSortDueAt, Descending: true})
+		th.CheckOK(resp)
+		require.Equal(t, []string{"review the spec", "write the spec", "ship it"}, titles(list))
+
+		list, resp = th.Client.GetMyCards(model.QueryUserCardsOptions{TeamID: "other-team-id"})
+		th.CheckOK(resp)
+		require.Empty(t, list.Items)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		list, resp := th.Client.GetMyCards(model.QueryUserCardsOptions{SortBy: model.UserCardsSortTitle, PerPage: 2})
+		th.CheckOK(resp)
+		require.True(t, list.HasNext)
+		require.Equal(t, []string{"review the spec", "ship it"}, titles(list))
+
+		list, resp = th.Client.GetMyCards(model.QueryUserCardsOptions{SortBy: model.UserCardsSortTitle, Page: 1, PerPage: 2})
+		th.CheckOK(resp)
+		require.False(t, list.HasNext)
+		require.Equal(t, []string{"write the spec"}, titles(list))
+	})
+
+	t.Run("archived cards are left out", func(t *testing.T) {
+		list, resp := th.Client.GetMyCards(model.QueryUserCardsOptions{Status: "done"})
+		th.CheckOK(resp)
+		_, resp = th.Client.ArchiveCard(board.ID, list.Items[0].Card.ID)
+		th.CheckOK(resp)
+
+		list, resp = th.Client.GetMyCards(model.QueryUserCardsOptions{Status: "done"})
+		th.CheckOK(resp)
+		require.Empty(t, list.Items)
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		_, resp := th.Client.GetMyCards(model.QueryUserCardsOptions{SortBy: "priority"})
+		th.CheckBadRequest(resp)
+	})
+}
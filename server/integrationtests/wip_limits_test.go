@@ -0,0 +1,126 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWIPLimits(t *testing.T) {
+	th := SetupTestHelperWithLicense(t, LicenseEnterprise).InitBasic()
+	defer th.TearDown()
+
+	board, err := th.Server.App().CreateBoard(&model.Board{
+		Title:  "board with WIP limits",
+		Type:   model.BoardTypeOpen,
+		TeamID: testTeamID,
+	}, th.GetUser1().ID, true)
+	require.NoError(t, err)
+
+	_, err = th.Server.App().AddMemberToBoard(&model.BoardMember{
+		UserID:       th.GetUser2().ID,
+		BoardID:      board.ID,
+		SchemeEditor: true,
+	})
+	require.NoError(t, err)
+
+	patch := &model.BoardPatch{
+		UpdatedProperties: map[string]interface{}{
+			model.BoardPropertyWIPLimitMode: model.WIPLimitModeStrict,
+		},
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "doing", "value": "Doing", "wipLimit": 1},
+			}},
+		},
+	}
+	_, resp := th.Client.PatchBoard(board.ID, patch)
+	th.CheckOK(resp)
+
+	newCard := func(status string) model.Block {
+		now := utils.GetMillis()
+		return model.Block{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			CreateAt: now,
+			UpdateAt: now,
+			Fields: map[string]interface{}{
+				"properties": map[string]interface{}{"status": status},
+			},
+		}
+	}
+
+	t.Run("only board admins can change the mode", func(t *testing.T) {
+		modePatch := &model.BoardPatch{
+			UpdatedProperties: map[string]interface{}{model.BoardPropertyWIPLimitMode: model.WIPLimitModeWarn},
+		}
+		_, resp := th.Client2.PatchBoard(board.ID, modePatch)
+		th.CheckForbidden(resp)
+
+		invalidPatch := &model.BoardPatch{
+			UpdatedProperties: map[string]interface{}{model.BoardPropertyWIPLimitMode: "sometimes"},
+		}
+		_, resp = th.Client.PatchBoard(board.ID, invalidPatch)
+		th.CheckBadRequest(resp)
+	})
+
+	var todoCardID string
+	t.Run("strict limits are enforced for board members", func(t *testing.T) {
+		blocks, resp := th.Client2.InsertBlocks(board.ID, []model.Block{newCard("doing"), newCard("todo")})
+		th.CheckOK(resp)
+		require.Len(t, blocks, 2)
+		require.Empty(t, resp.ExceededWIPLimits())
+		todoCardID = blocks[1].ID
+
+		_, resp = th.Client2.InsertBlocks(board.ID, []model.Block{newCard("doing")})
+		th.CheckBadRequest(resp)
+		require.Contains(t, resp.Error.Error(), "WIP limit exceeded")
+
+		movePatch := &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{"status": "doing"}},
+		}
+		_, resp = th.Client2.PatchBlock(board.ID, todoCardID, movePatch)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("board admins can exceed the limits", func(t *testing.T) {
+		_, resp := th.Client.InsertBlocks(board.ID, []model.Block{newCard("doing")})
+		th.CheckOK(resp)
+		require.Equal(t, []*model.WIPLimitStatus{
+			{PropertyID: "status", OptionID: "doing", Limit: 1, Count: 2},
+		}, resp.ExceededWIPLimits())
+
+		metadata, resp := th.Client.GetBoardMetadata(board.ID, "")
+		th.CheckOK(resp)
+		require.Equal(t, []*model.WIPLimitStatus{
+			{PropertyID: "status", OptionID: "doing", Limit: 1, Count: 2},
+		}, metadata.WIPLimits)
+	})
+
+	t.Run("limits only warn on lenient boards", func(t *testing.T) {
+		modePatch := &model.BoardPatch{
+			UpdatedProperties: map[string]interface{}{model.BoardPropertyWIPLimitMode: model.WIPLimitModeWarn},
+		}
+		_, resp := th.Client.PatchBoard(board.ID, modePatch)
+		th.CheckOK(resp)
+
+		movePatch := &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{"status": "doing"}},
+		}
+		_, resp = th.Client2.PatchBlock(board.ID, todoCardID, movePatch)
+		th.CheckOK(resp)
+		require.Equal(t, []*model.WIPLimitStatus{
+			{PropertyID: "status", OptionID: "doing", Limit: 1, Count: 3},
+		}, resp.ExceededWIPLimits())
+
+		metadata, resp := th.Client.GetBoardMetadata(board.ID, "")
+		th.CheckOK(resp)
+		require.Len(t, metadata.WIPLimits, 1)
+		require.Equal(t, 3, metadata.WIPLimits[0].Count)
+	})
+}
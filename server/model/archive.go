@@ -0,0 +1,144 @@
+package model
+
+import (
+	"sort"
+)
+
+const millisPerDay = 24 * 60 * 60 * 1000
+
+// AutoArchiveRule archives the cards that have had an option of a select
+// property for more than a number of days
+type AutoArchiveRule struct {
+	PropertyID string
+	OptionID   string
+	Days       int
+}
+
+// AutoArchiveRules returns the rules set on the options of the schema,
+// ordered by property and option index.
+func (s PropSchema) AutoArchiveRules() []*AutoArchiveRule {
+	var rules []*AutoArchiveRule
+	for _, pd := range s {
+		if pd.Type != "select" {
+			continue
+		}
+		for _, option := range pd.Options {
+			if option.AutoArchiveDays > 0 {
+				rules = append(rules, &AutoArchiveRule{PropertyID: pd.ID, OptionID: option.ID, Days: option.AutoArchiveDays})
+			}
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		if a.PropertyID != b.PropertyID {
+			return s[a.PropertyID].Index < s[b.PropertyID].Index
+		}
+		return s[a.PropertyID].Options[a.OptionID].Index < s[b.PropertyID].Options[b.OptionID].Index
+	})
+	return rules
+}
+
+// AutoArchivePropertyIDs returns the properties the rules depend on.
+func AutoArchivePropertyIDs(rules []*AutoArchiveRule) []string {
+	var propertyIDs []string
+	seen := map[string]bool{}
+	for _, rule := range rules {
+		if !seen[rule.PropertyID] {
+			seen[rule.PropertyID] = true
+			propertyIDs = append(propertyIDs, rule.PropertyID)
+		}
+	}
+	return propertyIDs
+}
+
+// CardsToAutoArchive returns the unarchived cards that the rules archive
+// at the given time. The time a card got an option is taken from the card
+// history, which must be ordered by card and update time. Cards without
+// history use their last update time.
+func CardsToAutoArchive(rules []*AutoArchiveRule, cards []Block, history []*CardHistoryEntry, now int64) []Block {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	timelines := map[string]cardTimeline{}
+	for _, entry := range history {
+		timelines[entry.CardID] = append(timelines[entry.CardID], entry)
+	}
+
+	var archived []Block
+	for i := range cards {
+		card := cards[i]
+		if card.Type != TypeCard || card.ArchiveAt != 0 || card.DeleteAt != 0 {
+			continue
+		}
+		properties := cardProperties(&card)
+		for _, rule := range rules {
+			if optionID, _ := properties[rule.PropertyID].(string); optionID != rule.OptionID {
+				continue
+			}
+			since := card.UpdateAt
+			if transitions := timelines[card.ID].transitionsTo(rule.PropertyID, rule.OptionID); len(transitions) > 0 {
+				since = transitions[len(transitions)-1]
+			}
+			if now-since > int64(rule.Days)*millisPerDay {
+				archived = append(archived, card)
+				break
+			}
+		}
+	}
+	return archived
+}
+
+// ArchivedCardBlockIDs returns the IDs of the archived cards and of their
+// descendants, at any depth.
+func ArchivedCardBlockIDs(blocks []Block) map[string]bool {
+	archivedIDs := map[string]bool{}
+	children := map[string][]string{}
+	var pending []string
+	for i := range blocks {
+		children[blocks[i].ParentID] = append(children[blocks[i].ParentID], blocks[i].ID)
+		if blocks[i].Type == TypeCard && blocks[i].ArchiveAt != 0 {
+			pending = append(pending, blocks[i].ID)
+		}
+	}
+
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if archivedIDs[id] {
+			continue
+		}
+		archivedIDs[id] = true
+		pending = append(pending, children[id]...)
+	}
+	return archivedIDs
+}
+
+// WithoutArchivedCards returns the blocks that aren't archived cards or
+// the descendants of an archived card.
+func WithoutArchivedCards(blocks []Block) []Block {
+	archivedIDs := ArchivedCardBlockIDs(blocks)
+	if len(archivedIDs) == 0 {
+		return blocks
+	}
+
+	visible := make([]Block, 0, len(blocks)-len(archivedIDs))
+	for i := range blocks {
+		if !archivedIDs[blocks[i].ID] {
+			visible = append(visible, blocks[i])
+		}
+	}
+	return visible
+}
+
+// WithoutArchivedBoards returns the boards that aren't archived.
+func WithoutArchivedBoards(boards []*Board) []*Board {
+	visible := make([]*Board, 0, len(boards))
+	for _, board := range boards {
+		if board.ArchiveAt == 0 {
+			visible = append(visible, board)
+		}
+	}
+	return visible
+}
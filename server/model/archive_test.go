@@ -0,0 +1,126 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func autoArchiveTestBoard() *Board {
+	return &Board{
+		ID: "board-id",
+		CardProperties: []map[string]interface{}{
+			{"id": "priority", "name": "Priority", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "low", "value": "Low", "autoArchiveDays": float64(90)},
+			}},
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "done", "value": "Done", "autoArchiveDays": float64(30)},
+				map[string]interface{}{"id": "wontfix", "value": "Won't fix", "autoArchiveDays": float64(7)},
+			}},
+		},
+	}
+}
+
+func TestAutoArchiveRules(t *testing.T) {
+	schema, err := ParsePropertySchema(autoArchiveTestBoard())
+	require.NoError(t, err)
+
+	rules := schema.AutoArchiveRules()
+	require.Equal(t, []*AutoArchiveRule{
+		{PropertyID: "priority", OptionID: "low", Days: 90},
+		{PropertyID: "status", OptionID: "done", Days: 30},
+		{PropertyID: "status", OptionID: "wontfix", Days: 7},
+	}, rules)
+	require.Equal(t, []string{"priority", "status"}, AutoArchivePropertyIDs(rules))
+
+	schema, err = ParsePropertySchema(&Board{})
+	require.NoError(t, err)
+	require.Empty(t, schema.AutoArchiveRules())
+}
+
+func TestCardsToAutoArchive(t *testing.T) {
+	schema, err := ParsePropertySchema(autoArchiveTestBoard())
+	require.NoError(t, err)
+	rules := schema.AutoArchiveRules()
+
+	day := int64(millisPerDay)
+	now := 100 * day
+	card := func(id, status string, updateAt int64) Block {
+		return Block{
+			ID:       id,
+			Type:     TypeCard,
+			UpdateAt: updateAt,
+			Fields:   map[string]interface{}{"properties": map[string]interface{}{"status": status}},
+		}
+	}
+	entry := func(cardID, status string, updateAt int64) *CardHistoryEntry {
+		return &CardHistoryEntry{CardID: cardID, UpdateAt: updateAt, Values: map[string]string{"status": status}}
+	}
+
+	archivedCard := card("archived", "done", 10*day)
+	archivedCard.ArchiveAt = 50 * day
+
+	cards := []Block{
+		card("done-long-ago", "done", 90*day),
+		card("done-recently", "done", 90*day),
+		card("done-again", "done", 90*day),
+		card("wontfix", "wontfix", 92*day),
+		card("todo", "todo", 10*day),
+		card("no-history", "done", 60*day),
+		archivedCard,
+		{ID: "view", Type: TypeView, UpdateAt: 10 * day},
+	}
+	history := []*CardHistoryEntry{
+		// edited in Done without leaving it
+		entry("done-long-ago", "todo", 5*day),
+		entry("done-long-ago", "done", 20*day),
+		entry("done-long-ago", "done", 90*day),
+		entry("done-recently", "todo", 5*day),
+		entry("done-recently", "done", 80*day),
+		// moved out of Done and back
+		entry("done-again", "done", 5*day),
+		entry("done-again", "todo", 50*day),
+		entry("done-again", "done", 75*day),
+		entry("wontfix", "wontfix", 92*day),
+		entry("todo", "todo", 10*day),
+	}
+
+	archived := CardsToAutoArchive(rules, cards, history, now)
+	ids := make([]string, len(archived))
+	for i := range archived {
+		ids[i] = archived[i].ID
+	}
+	require.Equal(t, []string{"done-long-ago", "wontfix", "no-history"}, ids)
+
+	require.Empty(t, CardsToAutoArchive(nil, cards, history, now))
+}
+
+func TestWithoutArchivedCards(t *testing.T) {
+	blocks := []Block{
+		{ID: "board-view", Type: TypeView},
+		{ID: "card", Type: TypeCard},
+		{ID: "card-text", ParentID: "card", Type: TypeText},
+		{ID: "archived-card", Type: TypeCard, ArchiveAt: 1000},
+		{ID: "archived-card-text", ParentID: "archived-card", Type: TypeText},
+		{ID: "archived-card-comment", ParentID: "archived-card", Type: TypeComment},
+		{ID: "archived-card-nested-text", ParentID: "archived-card-text", Type: TypeText},
+	}
+
+	visible := WithoutArchivedCards(blocks)
+	require.Len(t, visible, 3)
+	for _, block := range visible {
+		require.NotContains(t, block.ID, "archived")
+	}
+
+	require.Equal(t, blocks[:3], WithoutArchivedCards(blocks[:3]))
+}
+
+func TestWithoutArchivedBoards(t *testing.T) {
+	boards := []*Board{
+		{ID: "board"},
+		{ID: "archived-board", ArchiveAt: 1000},
+	}
+	require.Equal(t, []*Board{boards[0]}, WithoutArchivedBoards(boards))
+}
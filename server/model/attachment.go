@@ -0,0 +1,99 @@
+package model
+
+import "errors"
+
+var ErrTeamStorageQuotaExceeded = errors.New("the storage quota of the team is exceeded")
+
+// Attachment is a file uploaded in a board, with the image block that
+// displays it
+// swagger:model
+type Attachment struct {
+	// The ID of the file, used to download it
+	// required: true
+	FileID string `json:"fileId"`
+
+	// The ID of the image block referencing the file
+	// required: true
+	BlockID string `json:"blockId"`
+
+	// The ID of the parent of the image block, usually a card
+	// required: true
+	ParentID string `json:"parentId"`
+
+	// The ID of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The original name of the file
+	// required: false
+	Name string `json:"name"`
+
+	// The extension of the file, including the dot
+	// required: false
+	Extension string `json:"extension"`
+
+	// The MIME type of the file
+	// required: false
+	MimeType string `json:"mimeType"`
+
+	// The size of the file in bytes
+	// required: false
+	Size int64 `json:"size"`
+
+	// The width of an image, in pixels
+	// required: false
+	Width int `json:"width,omitempty"`
+
+	// The height of an image, in pixels
+	// required: false
+	Height int `json:"height,omitempty"`
+
+	// If true, the file has a thumbnail and a preview
+	// required: false
+	HasPreviewImage bool `json:"hasPreviewImage"`
+
+	// The status of the malware scan of the file, empty if it wasn't scanned
+	// required: false
+	ScanStatus string `json:"scanStatus,omitempty"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The ID of the user that added the file
+	// required: true
+	CreatedBy string `json:"createdBy"`
+}
+
+// TeamStorageUsage is the size of the files uploaded in the boards of a team
+// swagger:model
+type TeamStorageUsage struct {
+	// The ID of the team
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The size of the files in bytes
+	// required: true
+	Used int64 `json:"used"`
+
+	// The maximum size of the files in bytes, zero if unlimited
+	// required: true
+	Quota int64 `json:"quota"`
+}
+
+// OrphanFilesCleanup is the result of a purge of the files that aren't
+// referenced by any block
+// swagger:model
+type OrphanFilesCleanup struct {
+	// The number of file records deleted
+	// required: true
+	FileInfos int `json:"fileInfos"`
+
+	// The number of files deleted from the files storage
+	// required: true
+	Files int `json:"files"`
+
+	// The size of the deleted files in bytes
+	// required: true
+	Bytes int64 `json:"bytes"`
+}
@@ -0,0 +1,102 @@
+package model
+
+const (
+	// AuditRecordsDefaultPerPage is the default page size used when querying audit records.
+	AuditRecordsDefaultPerPage = 100
+
+	// AuditRecordsMaxPerPage is the maximum page size allowed when querying audit records.
+	AuditRecordsMaxPerPage = 1000
+)
+
+// AuditRecord is an audit record persisted by the database audit target
+// swagger:model
+type AuditRecord struct {
+	// The id of the record
+	// required: true
+	ID string `json:"id"`
+
+	// The audit level (auth, mod, read)
+	// required: true
+	Level string `json:"level"`
+
+	// The event that was audited
+	// required: true
+	Event string `json:"event"`
+
+	// The status of the event (success, attempt, fail)
+	// required: true
+	Status string `json:"status"`
+
+	// The id of the user that triggered the event
+	// required: false
+	UserID string `json:"userId"`
+
+	// The id of the session used to trigger the event
+	// required: false
+	SessionID string `json:"sessionId"`
+
+	// The client (user agent) used to trigger the event
+	// required: false
+	Client string `json:"client"`
+
+	// The IP address of the client
+	// required: false
+	IPAddress string `json:"ipAddress"`
+
+	// The API path of the request
+	// required: false
+	APIPath string `json:"apiPath"`
+
+	// The id of the team the event relates to, if any
+	// required: false
+	TeamID string `json:"teamId"`
+
+	// The id of the board the event relates to, if any
+	// required: false
+	BoardID string `json:"boardId"`
+
+	// Additional metadata of the event
+	// required: false
+	Meta map[string]interface{} `json:"meta"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+}
+
+// QueryAuditRecordsOptions are query options that can be passed to GetAuditRecords.
+type QueryAuditRecordsOptions struct {
+	UserID  string // if not empty then filter for records of this user
+	TeamID  string // if not empty then filter for records of this team
+	BoardID string // if not empty then filter for records of this board
+	Event   string // if not empty then filter for records of this event
+	Since   int64  // if non-zero then filter for records created at or after Since
+	Until   int64  // if non-zero then filter for records created before Until
+	Page    int    // page number to select when paginating
+	PerPage int    // number of records to return per page
+}
+
+// AuditRecordsList is a response type with pagination support.
+// swagger:model
+type AuditRecordsList struct {
+	// True if there are more records to fetch
+	// required: true
+	HasNext bool `json:"hasNext"`
+
+	// The audit records of the page, newest first
+	// required: true
+	Items []*AuditRecord `json:"items"`
+}
+
+// GetAuditRecordsListWithPagination checks if there is another page that can
+// be fetched based on the given limit. The given list is expected to contain
+// up to limit+1 records.
+func GetAuditRecordsListWithPagination(records []*AuditRecord, limit int) *AuditRecordsList {
+	var hasNext bool
+	if limit != 0 && len(records) == limit+1 {
+		hasNext = true
+		records = records[:len(records)-1]
+	}
+
+	return &AuditRecordsList{HasNext: hasNext, Items: records}
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BackupFormatVersion is the version of the format of the backups of a
+// server, described in docs/backup-format.md.
+const BackupFormatVersion = 1
+
+var ErrRestoreNotEmpty = errors.New("a backup can only be restored into a server without users or boards")
+
+// BackupManifest is the content of the `manifest.json` file of a backup.
+// swagger:model
+type BackupManifest struct {
+	// The version of the backup format
+	// required: true
+	Version int `json:"version"`
+
+	// The version of the database schema the backup was taken with
+	// required: true
+	SchemaVersion int `json:"schemaVersion"`
+
+	// The database type of the server the backup was taken from
+	// required: true
+	DBType string `json:"dbType"`
+
+	// The time of the backup in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The number of rows of each table in the backup
+	// required: true
+	Tables map[string]int64 `json:"tables"`
+
+	// The number of files in the backup
+	// required: true
+	Files int `json:"files"`
+}
+
+// TableBatch is a batch of rows of a database table, used to back up and
+// restore the data of a server. The values of each row are in the order of
+// the columns.
+type TableBatch struct {
+	Table   string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// ErrUnsupportedBackup is an error returned when trying to restore a backup
+// with a format or a schema version that this server does not support.
+type ErrUnsupportedBackup struct {
+	what string
+	got  int
+	want int
+}
+
+// NewErrUnsupportedBackup creates a ErrUnsupportedBackup error.
+func NewErrUnsupportedBackup(what string, got int, want int) ErrUnsupportedBackup {
+	return ErrUnsupportedBackup{
+		what: what,
+		got:  got,
+		want: want,
+	}
+}
+
+func (e ErrUnsupportedBackup) Error() string {
+	return fmt.Sprintf("unsupported backup %s; got %d, want %d", e.what, e.got, e.want)
+}
@@ -55,6 +55,10 @@ type Block struct {
 	// required: false
 	DeleteAt int64 `json:"deleteAt"`
 
+	// The archived time in miliseconds since the current epoch. Set to indicate this card is archived
+	// required: false
+	ArchiveAt int64 `json:"archiveAt"`
+
 	// Deprecated. The workspace id that the block belongs to
 	// required: false
 	WorkspaceID string `json:"-"`
@@ -98,6 +102,21 @@ type BlockPatch struct {
 	// The board id that the block belongs to
 	// required: false
 	BoardID *string `json:"boardId"`
+
+	// The archived time of the block, zero to restore an archived block
+	// required: false
+	ArchiveAt *int64 `json:"archiveAt,omitempty"`
+
+	// The update time the block is expected to have. If set and the block
+	// has been modified since, the patch is rejected with a conflict error
+	// required: false
+	ExpectedUpdateAt *int64 `json:"expectedUpdateAt,omitempty"`
+
+	// If true, updated fields holding objects, like the card properties,
+	// are merged key by key into the existing ones instead of replacing
+	// them. Keys set to null are removed
+	// required: false
+	MergeFields bool `json:"mergeFields,omitempty"`
 }
 
 // BlockPatchBatch is a batch of IDs and patches for modify blocks
@@ -110,6 +129,27 @@ type BlockPatchBatch struct {
 	BlockPatches []BlockPatch `json:"block_patches"`
 }
 
+// CardChanges is a set of block changes saved in a single transaction
+// together with a check of the cards of the changed boards.
+type CardChanges struct {
+	// The boards whose cards are checked before the changes are saved
+	BoardIDs []string
+
+	// The new blocks
+	InsertedBlocks []Block
+
+	// The patches of existing blocks
+	BlockPatches *BlockPatchBatch
+
+	// The new version of existing blocks moved to another board
+	MovedBlocks []Block
+}
+
+// CardsCheck is a callback that checks the cards of a board before a set
+// of card changes is saved. cards are the cards of the board at the time
+// of the check. Returning an error cancels the changes.
+type CardsCheck func(boardID string, cards []Block) error
+
 // BoardModifier is a callback that can modify each board during an import.
 // A cache of arbitrary data will be passed for each call and any changes
 // to the cache will be preserved for the next call.
@@ -165,7 +205,14 @@ func (p *BlockPatch) Patch(block *Block) *Block {
 		block.Title = *p.Title
 	}
 
+	if p.ArchiveAt != nil {
+		block.ArchiveAt = *p.ArchiveAt
+	}
+
 	for key, field := range p.UpdatedFields {
+		if p.MergeFields {
+			field = mergeField(block.Fields[key], field)
+		}
 		block.Fields[key] = field
 	}
 
@@ -176,6 +223,29 @@ func (p *BlockPatch) Patch(block *Block) *Block {
 	return block
 }
 
+// mergeField merges the keys of an updated object field into the existing
+// one. Non object values replace the existing value.
+func mergeField(existing, updated interface{}) interface{} {
+	updatedMap, ok := updated.(map[string]interface{})
+	if !ok {
+		return updated
+	}
+	existingMap, _ := existing.(map[string]interface{})
+
+	merged := make(map[string]interface{}, len(existingMap)+len(updatedMap))
+	for key, value := range existingMap {
+		merged[key] = value
+	}
+	for key, value := range updatedMap {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
 // QuerySubtreeOptions are query options that can be passed to GetSubTree methods.
 type QuerySubtreeOptions struct {
 	BeforeUpdateAt int64  // if non-zero then filter for records with update_at less than BeforeUpdateAt
@@ -295,6 +295,47 @@ func TestGenerateBlockIDs(t *testing.T) {
 	})
 }
 
+func TestBlockPatchMergeFields(t *testing.T) {
+	newBlock := func() *Block {
+		return &Block{
+			Fields: map[string]interface{}{
+				"icon":       "a",
+				"properties": map[string]interface{}{"p1": "v1", "p2": "v2"},
+			},
+		}
+	}
+
+	t.Run("replace fields by default", func(t *testing.T) {
+		patch := &BlockPatch{
+			UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{"p3": "v3"}},
+		}
+		block := patch.Patch(newBlock())
+		require.Equal(t, map[string]interface{}{"p3": "v3"}, block.Fields["properties"])
+	})
+
+	t.Run("merge object fields", func(t *testing.T) {
+		patch := &BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"icon":       "b",
+				"properties": map[string]interface{}{"p2": "new", "p3": "v3", "p1": nil},
+			},
+			MergeFields: true,
+		}
+		block := patch.Patch(newBlock())
+		require.Equal(t, "b", block.Fields["icon"])
+		require.Equal(t, map[string]interface{}{"p2": "new", "p3": "v3"}, block.Fields["properties"])
+	})
+
+	t.Run("merge into a missing field", func(t *testing.T) {
+		patch := &BlockPatch{
+			UpdatedFields: map[string]interface{}{"other": map[string]interface{}{"k": "v", "removed": nil}},
+			MergeFields:   true,
+		}
+		block := patch.Patch(newBlock())
+		require.Equal(t, map[string]interface{}{"k": "v"}, block.Fields["other"])
+	})
+}
+
 func TestStampModificationMetadata(t *testing.T) {
 	t.Run("base case", func(t *testing.T) {
 		block := Block{}
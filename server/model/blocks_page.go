@@ -0,0 +1,122 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	// BlocksPageDefaultPerPage is the default page size used when paginating blocks.
+	BlocksPageDefaultPerPage = 500
+
+	// BlocksPageMaxPerPage is the maximum page size allowed when paginating blocks.
+	BlocksPageMaxPerPage = 5000
+)
+
+var ErrInvalidBlocksCursor = errors.New("invalid blocks cursor")
+
+// BlocksCursor identifies the position of a block in the (update_at, id)
+// ordering used to paginate the blocks of a board. Cursors stay valid when
+// blocks are modified between requests: modified blocks move past the cursor
+// and are returned again in a later page.
+type BlocksCursor struct {
+	UpdateAt int64
+	ID       string
+}
+
+// Encode returns the opaque string representation of the cursor.
+func (c BlocksCursor) Encode() string {
+	raw := strconv.FormatInt(c.UpdateAt, 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeBlocksCursor parses a cursor previously returned by Encode.
+func DecodeBlocksCursor(cursor string) (*BlocksCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidBlocksCursor
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, ErrInvalidBlocksCursor
+	}
+
+	updateAt, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidBlocksCursor
+	}
+
+	return &BlocksCursor{UpdateAt: updateAt, ID: parts[1]}, nil
+}
+
+// QueryBlocksPageOptions are query options that can be passed to GetBlocksPage.
+type QueryBlocksPageOptions struct {
+	ParentID     string        // if not empty then filter for blocks with this parent
+	BlockType    BlockType     // if not empty then filter for blocks of this type
+	UpdatedSince int64         // if non-zero then filter for blocks with update_at greater or equal than UpdatedSince
+	Cursor       *BlocksCursor // if not nil then return the blocks after the cursor
+	PerPage      int           // number of blocks to return per page
+}
+
+// BlockTombstone marks a block that has been deleted.
+// swagger:model
+type BlockTombstone struct {
+	// The id of the deleted block
+	// required: true
+	ID string `json:"id"`
+
+	// The id of the deleted block's parent block
+	// required: false
+	ParentID string `json:"parentId"`
+
+	// The type of the deleted block
+	// required: true
+	Type BlockType `json:"type"`
+
+	// The deletion time in milliseconds since the current epoch
+	// required: true
+	DeleteAt int64 `json:"deleteAt"`
+}
+
+// BlocksPage is a page of the blocks of a board
+// swagger:model
+type BlocksPage struct {
+	// The blocks of the page, ordered by update time
+	// required: true
+	Blocks []Block `json:"blocks"`
+
+	// The blocks deleted since the requested time. Only set on the first
+	// page of a delta sync request
+	// required: false
+	Deleted []BlockTombstone `json:"deleted,omitempty"`
+
+	// True if there are more blocks to fetch
+	// required: true
+	HasNext bool `json:"hasNext"`
+
+	// The cursor to pass to fetch the next page, empty on the last page
+	// required: false
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// NextSince returns the time to pass as since to the next delta sync, given
+// the since of the sync that returned the page. As since is inclusive, it is
+// one millisecond after the latest update or deletion contained in the page,
+// so that these changes aren't returned again. An empty page keeps since.
+func (p *BlocksPage) NextSince(since int64) int64 {
+	latest := since - 1
+	for _, block := range p.Blocks {
+		if block.UpdateAt > latest {
+			latest = block.UpdateAt
+		}
+	}
+	for _, tombstone := range p.Deleted {
+		if tombstone.DeleteAt > latest {
+			latest = tombstone.DeleteAt
+		}
+	}
+	return latest + 1
+}
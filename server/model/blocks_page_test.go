@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocksCursor(t *testing.T) {
+	t.Run("encode and decode", func(t *testing.T) {
+		cursor := BlocksCursor{UpdateAt: 1234567890123, ID: "block:id"}
+
+		decoded, err := DecodeBlocksCursor(cursor.Encode())
+		require.NoError(t, err)
+		require.Equal(t, cursor, *decoded)
+	})
+
+	t.Run("invalid cursors", func(t *testing.T) {
+		for _, cursor := range []string{"", "not base64!", "MTIz", "YWJjOmlk"} {
+			_, err := DecodeBlocksCursor(cursor)
+			require.ErrorIs(t, err, ErrInvalidBlocksCursor, cursor)
+		}
+	})
+}
+
+func TestBlocksPageNextSince(t *testing.T) {
+	page := &BlocksPage{
+		Blocks:  []Block{{UpdateAt: 10}, {UpdateAt: 30}},
+		Deleted: []BlockTombstone{{DeleteAt: 20}},
+	}
+	require.EqualValues(t, 31, page.NextSince(0))
+
+	page.Deleted = append(page.Deleted, BlockTombstone{DeleteAt: 40})
+	require.EqualValues(t, 41, page.NextSince(5))
+
+	// an empty page keeps the previous since
+	require.EqualValues(t, 50, (&BlocksPage{}).NextSince(50))
+	require.EqualValues(t, 0, (&BlocksPage{}).NextSince(0))
+}
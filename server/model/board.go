@@ -96,6 +96,10 @@ type Board struct {
 	// The deleted time in miliseconds since the current epoch. Set to indicate this block is deleted
 	// required: false
 	DeleteAt int64 `json:"deleteAt"`
+
+	// The archived time in miliseconds since the current epoch. Set to indicate this board is archived
+	// required: false
+	ArchiveAt int64 `json:"archiveAt"`
 }
 
 // BoardPatch is a patch for modify boards
@@ -144,6 +148,15 @@ type BoardPatch struct {
 	// The board removed card properties
 	// required: false
 	DeletedCardProperties []string `json:"deletedCardProperties"`
+
+	// The archived time of the board, zero to restore an archived board
+	// required: false
+	ArchiveAt *int64 `json:"archiveAt,omitempty"`
+
+	// The update time the board is expected to have. If set and the board
+	// has been modified since, the patch is rejected with a conflict error
+	// required: false
+	ExpectedUpdateAt *int64 `json:"expectedUpdateAt,omitempty"`
 }
 
 // BoardMember stores the information of the membership of a user on a board
@@ -208,6 +221,10 @@ type BoardMetadata struct {
 	// The ID of the user that last modified the most recently modified descendant
 	// required: true
 	LastModifiedBy string `json:"lastModifiedBy"`
+
+	// The card counts of the options with a WIP limit
+	// required: true
+	WIPLimits []*WIPLimitStatus `json:"wipLimits"`
 }
 
 func BoardFromJSON(data io.Reader) *Board {
@@ -270,6 +287,13 @@ func (p *BoardPatch) Patch(board *Board) *Board {
 		board.ChannelID = *p.ChannelID
 	}
 
+	if p.ArchiveAt != nil {
+		board.ArchiveAt = *p.ArchiveAt
+	}
+
+	if len(p.UpdatedProperties) != 0 && board.Properties == nil {
+		board.Properties = map[string]interface{}{}
+	}
 	for key, property := range p.UpdatedProperties {
 		board.Properties[key] = property
 	}
@@ -346,9 +370,35 @@ func (p *BoardPatch) IsValid() error {
 		return InvalidBoardErr{"invalid-board-minimum-role"}
 	}
 
+	if mode, ok := p.UpdatedProperties[BoardPropertyWIPLimitMode]; ok {
+		if s, _ := mode.(string); !IsWIPLimitModeValid(s) {
+			return InvalidBoardErr{"invalid-wip-limit-mode"}
+		}
+	}
+
+	if mode, ok := p.UpdatedProperties[BoardPropertyPropertyValidation]; ok {
+		if s, _ := mode.(string); !IsPropertyValidationModeValid(s) {
+			return InvalidBoardErr{"invalid-property-validation-mode"}
+		}
+	}
+
 	return nil
 }
 
+// changesProperty returns true if the patch updates or removes the board
+// property.
+func (p *BoardPatch) changesProperty(key string) bool {
+	if _, ok := p.UpdatedProperties[key]; ok {
+		return true
+	}
+	for _, deleted := range p.DeletedProperties {
+		if deleted == key {
+			return true
+		}
+	}
+	return false
+}
+
 type InvalidBoardErr struct {
 	msg string
 }
@@ -0,0 +1,480 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	BoardChartCumulativeFlow = "cumulativeFlow"
+	BoardChartCycleTime      = "cycleTime"
+	BoardChartThroughput     = "throughput"
+	BoardChartBurndown       = "burndown"
+
+	BoardChartIntervalDay  = "day"
+	BoardChartIntervalWeek = "week"
+
+	// BoardChartMaxBuckets is the maximum number of points a chart can have.
+	BoardChartMaxBuckets = 400
+)
+
+var ErrInvalidBoardChartOptions = errors.New("invalid board chart options")
+
+// BoardChartOptions are the options used to compute a chart from the card
+// history of a board.
+type BoardChartOptions struct {
+	// The chart to compute: cumulativeFlow, cycleTime, throughput or burndown
+	Chart string
+
+	// The select property the cards are grouped by
+	PropertyID string
+
+	// The property value where the cycle time starts
+	StartValue string
+
+	// The property value marking a card as done
+	EndValue string
+
+	// The date property the burndown is computed against
+	DatePropertyID string
+
+	// The time range of the chart, in milliseconds since the epoch
+	From int64
+	To   int64
+
+	// The length of the chart buckets: day or week
+	Interval string
+
+	// The location used to align the buckets to days and weeks
+	Location *time.Location
+}
+
+// IsValid checks that the options contain what the chart needs.
+func (o *BoardChartOptions) IsValid() error {
+	if o.PropertyID == "" {
+		return fmt.Errorf("%w: missing property", ErrInvalidBoardChartOptions)
+	}
+	if o.From <= 0 || o.To <= 0 || o.From > o.To {
+		return fmt.Errorf("%w: invalid time range", ErrInvalidBoardChartOptions)
+	}
+	if o.Interval != BoardChartIntervalDay && o.Interval != BoardChartIntervalWeek {
+		return fmt.Errorf("%w: invalid interval %q", ErrInvalidBoardChartOptions, o.Interval)
+	}
+
+	for _, propertyID := range o.PropertyIDs() {
+		if strings.ContainsAny(propertyID, "\"\\") {
+			return fmt.Errorf("%w: invalid property ID", ErrInvalidBoardChartOptions)
+		}
+	}
+
+	switch o.Chart {
+	case BoardChartCumulativeFlow:
+	case BoardChartCycleTime:
+		if o.StartValue == "" || o.EndValue == "" {
+			return fmt.Errorf("%w: cycle time needs a start and an end value", ErrInvalidBoardChartOptions)
+		}
+	case BoardChartThroughput:
+		if o.EndValue == "" {
+			return fmt.Errorf("%w: throughput needs an end value", ErrInvalidBoardChartOptions)
+		}
+	case BoardChartBurndown:
+		if o.EndValue == "" || o.DatePropertyID == "" {
+			return fmt.Errorf("%w: burndown needs an end value and a date property", ErrInvalidBoardChartOptions)
+		}
+	default:
+		return fmt.Errorf("%w: unknown chart %q", ErrInvalidBoardChartOptions, o.Chart)
+	}
+
+	if len(o.buckets()) > BoardChartMaxBuckets {
+		return fmt.Errorf("%w: too many points, use a shorter time range or a longer interval", ErrInvalidBoardChartOptions)
+	}
+	return nil
+}
+
+// PropertyIDs returns the card properties the chart reads from the history.
+func (o *BoardChartOptions) PropertyIDs() []string {
+	if o.Chart == BoardChartBurndown {
+		return []string{o.PropertyID, o.DatePropertyID}
+	}
+	return []string{o.PropertyID}
+}
+
+// buckets returns the start times of the chart buckets. The first bucket
+// starts at the beginning of the day or week containing From.
+func (o *BoardChartOptions) buckets() []int64 {
+	location := o.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	from := time.UnixMilli(o.From).In(location)
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location)
+	days := 1
+	if o.Interval == BoardChartIntervalWeek {
+		days = 7
+		// weeks start on Monday
+		offset := (int(start.Weekday()) + 6) % 7
+		start = start.AddDate(0, 0, -offset)
+	}
+
+	var buckets []int64
+	for t := start; t.UnixMilli() <= o.To; t = t.AddDate(0, 0, days) {
+		buckets = append(buckets, t.UnixMilli())
+		if len(buckets) > BoardChartMaxBuckets {
+			break
+		}
+	}
+	return buckets
+}
+
+// CardHistoryEntry is a version of a card taken from the block history,
+// with the values of the properties a chart needs.
+type CardHistoryEntry struct {
+	CardID   string
+	UpdateAt int64
+	DeleteAt int64
+
+	// The property values of this version, keyed by property ID
+	Values map[string]string
+}
+
+// BoardChart is a chart computed from the card history of a board
+// swagger:model
+type BoardChart struct {
+	// The ID of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The chart: cumulativeFlow, cycleTime, throughput or burndown
+	// required: true
+	Chart string `json:"chart"`
+
+	// The start of the time range, in milliseconds since the epoch
+	// required: true
+	From int64 `json:"from"`
+
+	// The end of the time range, in milliseconds since the epoch
+	// required: true
+	To int64 `json:"to"`
+
+	// The length of the chart buckets: day or week
+	// required: true
+	Interval string `json:"interval"`
+
+	// The points of the cumulative flow chart
+	// required: false
+	CumulativeFlow []*CumulativeFlowPoint `json:"cumulativeFlow,omitempty"`
+
+	// The cycle time of the cards finished in the time range
+	// required: false
+	CycleTime *CycleTimeReport `json:"cycleTime,omitempty"`
+
+	// The points of the throughput chart
+	// required: false
+	Throughput []*ThroughputPoint `json:"throughput,omitempty"`
+
+	// The points of the burndown chart
+	// required: false
+	Burndown []*BurndownPoint `json:"burndown,omitempty"`
+}
+
+// CumulativeFlowPoint is the number of cards per property value at the end
+// of a bucket
+// swagger:model
+type CumulativeFlowPoint struct {
+	// The start of the bucket, in milliseconds since the epoch
+	// required: true
+	Timestamp int64 `json:"timestamp"`
+
+	// The number of cards keyed by property value. Cards without a value
+	// are counted under an empty key
+	// required: true
+	Counts map[string]int `json:"counts"`
+}
+
+// CycleTimeReport is the time cards took to go from the start value to the
+// end value
+// swagger:model
+type CycleTimeReport struct {
+	// The number of cards finished in the time range
+	// required: true
+	Count int `json:"count"`
+
+	// The average cycle time, in milliseconds
+	// required: true
+	Average int64 `json:"average"`
+
+	// The median cycle time, in milliseconds
+	// required: true
+	Median int64 `json:"median"`
+
+	// The 85th percentile of the cycle time, in milliseconds
+	// required: true
+	Percentile85 int64 `json:"percentile85"`
+
+	// The cycle time of each card, ordered by finish time
+	// required: true
+	Cards []*CardCycleTime `json:"cards"`
+}
+
+// CardCycleTime is the cycle time of a card
+// swagger:model
+type CardCycleTime struct {
+	// The ID of the card
+	// required: true
+	CardID string `json:"cardId"`
+
+	// When the card got the start value, in milliseconds since the epoch
+	// required: true
+	StartedAt int64 `json:"startedAt"`
+
+	// When the card got the end value, in milliseconds since the epoch
+	// required: true
+	FinishedAt int64 `json:"finishedAt"`
+
+	// The cycle time, in milliseconds
+	// required: true
+	Duration int64 `json:"duration"`
+}
+
+// ThroughputPoint is the number of cards that got the end value during a
+// bucket
+// swagger:model
+type ThroughputPoint struct {
+	// The start of the bucket, in milliseconds since the epoch
+	// required: true
+	Timestamp int64 `json:"timestamp"`
+
+	// The number of cards finished in the bucket
+	// required: true
+	Count int `json:"count"`
+}
+
+// BurndownPoint is the number of unfinished cards at the end of a bucket
+// swagger:model
+type BurndownPoint struct {
+	// The start of the bucket, in milliseconds since the epoch
+	// required: true
+	Timestamp int64 `json:"timestamp"`
+
+	// The number of cards in scope without the end value
+	// required: true
+	Remaining int `json:"remaining"`
+
+	// The number of remaining cards of a linear burndown
+	// required: true
+	Ideal float64 `json:"ideal"`
+}
+
+func BoardChartFromJSON(data io.Reader) *BoardChart {
+	var chart *BoardChart
+	_ = json.NewDecoder(data).Decode(&chart)
+	return chart
+}
+
+// cardTimeline holds the versions of a card in chronological order.
+type cardTimeline []*CardHistoryEntry
+
+// at returns the version of the card at the given time, or nil if the card
+// didn't exist or was deleted.
+func (t cardTimeline) at(timestamp int64) *CardHistoryEntry {
+	i := sort.Search(len(t), func(i int) bool { return t[i].UpdateAt > timestamp })
+	if i == 0 || t[i-1].DeleteAt != 0 {
+		return nil
+	}
+	return t[i-1]
+}
+
+// transitionsTo returns the times the card got the given property value.
+func (t cardTimeline) transitionsTo(propertyID, value string) []int64 {
+	var transitions []int64
+	previous := ""
+	for _, entry := range t {
+		if entry.DeleteAt != 0 {
+			previous = ""
+			continue
+		}
+		current := entry.Values[propertyID]
+		if current == value && previous != value {
+			transitions = append(transitions, entry.UpdateAt)
+		}
+		previous = current
+	}
+	return transitions
+}
+
+// ComputeBoardChart computes a chart from the card history of a board. The
+// history entries must be ordered by card and update time.
+func ComputeBoardChart(boardID string, opts *BoardChartOptions, history []*CardHistoryEntry) *BoardChart {
+	timelines := map[string]cardTimeline{}
+	var cardIDs []string
+	for _, entry := range history {
+		if _, ok := timelines[entry.CardID]; !ok {
+			cardIDs = append(cardIDs, entry.CardID)
+		}
+		timelines[entry.CardID] = append(timelines[entry.CardID], entry)
+	}
+
+	chart := &BoardChart{
+		BoardID:  boardID,
+		Chart:    opts.Chart,
+		From:     opts.From,
+		To:       opts.To,
+		Interval: opts.Interval,
+	}
+
+	buckets := opts.buckets()
+	bucketEnd := func(i int) int64 {
+		if i+1 < len(buckets) && buckets[i+1]-1 < opts.To {
+			return buckets[i+1] - 1
+		}
+		return opts.To
+	}
+
+	switch opts.Chart {
+	case BoardChartCumulativeFlow:
+		chart.CumulativeFlow = make([]*CumulativeFlowPoint, 0, len(buckets))
+		for i, bucket := range buckets {
+			point := &CumulativeFlowPoint{Timestamp: bucket, Counts: map[string]int{}}
+			for _, cardID := range cardIDs {
+				if entry := timelines[cardID].at(bucketEnd(i)); entry != nil {
+					point.Counts[entry.Values[opts.PropertyID]]++
+				}
+			}
+			chart.CumulativeFlow = append(chart.CumulativeFlow, point)
+		}
+
+	case BoardChartCycleTime:
+		chart.CycleTime = computeCycleTime(opts, cardIDs, timelines)
+
+	case BoardChartThroughput:
+		chart.Throughput = make([]*ThroughputPoint, 0, len(buckets))
+		for _, bucket := range buckets {
+			chart.Throughput = append(chart.Throughput, &ThroughputPoint{Timestamp: bucket})
+		}
+		for _, cardID := range cardIDs {
+			for _, finishedAt := range timelines[cardID].transitionsTo(opts.PropertyID, opts.EndValue) {
+				if finishedAt < opts.From || finishedAt > opts.To {
+					continue
+				}
+				i := sort.Search(len(buckets), func(i int) bool { return buckets[i] > finishedAt }) - 1
+				if i >= 0 {
+					chart.Throughput[i].Count++
+				}
+			}
+		}
+
+	case BoardChartBurndown:
+		chart.Burndown = computeBurndown(opts, cardIDs, timelines, buckets, bucketEnd)
+	}
+
+	return chart
+}
+
+func computeCycleTime(opts *BoardChartOptions, cardIDs []string, timelines map[string]cardTimeline) *CycleTimeReport {
+	report := &CycleTimeReport{Cards: []*CardCycleTime{}}
+	for _, cardID := range cardIDs {
+		timeline := timelines[cardID]
+		starts := timeline.transitionsTo(opts.PropertyID, opts.StartValue)
+		if len(starts) == 0 {
+			continue
+		}
+		for _, finishedAt := range timeline.transitionsTo(opts.PropertyID, opts.EndValue) {
+			if finishedAt < starts[0] {
+				continue
+			}
+			if finishedAt >= opts.From && finishedAt <= opts.To {
+				report.Cards = append(report.Cards, &CardCycleTime{
+					CardID:     cardID,
+					StartedAt:  starts[0],
+					FinishedAt: finishedAt,
+					Duration:   finishedAt - starts[0],
+				})
+			}
+			break
+		}
+	}
+
+	sort.Slice(report.Cards, func(i, j int) bool {
+		return report.Cards[i].FinishedAt < report.Cards[j].FinishedAt
+	})
+
+	report.Count = len(report.Cards)
+	if report.Count == 0 {
+		return report
+	}
+
+	durations := make([]int64, 0, report.Count)
+	var total int64
+	for _, card := range report.Cards {
+		durations = append(durations, card.Duration)
+		total += card.Duration
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	report.Average = total / int64(report.Count)
+	report.Median = durations[(len(durations)-1)/2]
+	if len(durations)%2 == 0 {
+		report.Median = (durations[len(durations)/2-1] + durations[len(durations)/2]) / 2
+	}
+	report.Percentile85 = durations[(len(durations)*85+99)/100-1]
+	return report
+}
+
+func computeBurndown(opts *BoardChartOptions, cardIDs []string, timelines map[string]cardTimeline, buckets []int64, bucketEnd func(int) int64) []*BurndownPoint {
+	// the cards in scope are the ones whose latest date falls in the range
+	var scope []string
+	for _, cardID := range cardIDs {
+		timeline := timelines[cardID]
+		latest := timeline[len(timeline)-1]
+		if latest.DeleteAt != 0 {
+			continue
+		}
+		date, ok := parseDatePropertyFrom(latest.Values[opts.DatePropertyID])
+		if ok && date >= opts.From && date <= opts.To {
+			scope = append(scope, cardID)
+		}
+	}
+
+	points := make([]*BurndownPoint, 0, len(buckets))
+	duration := float64(opts.To - opts.From)
+	for i, bucket := range buckets {
+		point := &BurndownPoint{Timestamp: bucket}
+		end := bucketEnd(i)
+		for _, cardID := range scope {
+			entry := timelines[cardID].at(end)
+			if entry != nil && entry.Values[opts.PropertyID] != opts.EndValue {
+				point.Remaining++
+			}
+		}
+
+		elapsed := float64(end - opts.From)
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		if duration > 0 {
+			point.Ideal = float64(len(scope)) * (1 - elapsed/duration)
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+// parseDatePropertyFrom returns the start of a date property value, stored
+// as a JSON object with from and to timestamps.
+func parseDatePropertyFrom(value string) (int64, bool) {
+	if !strings.HasPrefix(value, "{") {
+		return 0, false
+	}
+	var date map[string]int64
+	if err := json.Unmarshal([]byte(value), &date); err != nil {
+		return 0, false
+	}
+	from, ok := date["from"]
+	return from, ok
+}
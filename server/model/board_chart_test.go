@@ -0,0 +1,174 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chartTestTime(day, hour int) int64 {
+	return time.Date(2022, 8, day, hour, 0, 0, 0, time.UTC).UnixMilli()
+}
+
+func chartTestEntry(cardID string, updateAt int64, status string) *CardHistoryEntry {
+	return &CardHistoryEntry{
+		CardID:   cardID,
+		UpdateAt: updateAt,
+		Values:   map[string]string{"status": status, "due": `{"from":` + "1660608000000" + `}`},
+	}
+}
+
+// chartTestHistory returns the history of three cards between Monday
+// August 1st and Friday August 5th 2022.
+func chartTestHistory() []*CardHistoryEntry {
+	deleted := chartTestEntry("card3", chartTestTime(3, 12), "todo")
+	deleted.DeleteAt = deleted.UpdateAt
+
+	return []*CardHistoryEntry{
+		chartTestEntry("card1", chartTestTime(1, 10), "todo"),
+		chartTestEntry("card1", chartTestTime(2, 10), "doing"),
+		chartTestEntry("card1", chartTestTime(4, 10), "done"),
+		chartTestEntry("card2", chartTestTime(2, 9), "doing"),
+		chartTestEntry("card2", chartTestTime(3, 9), "done"),
+		chartTestEntry("card3", chartTestTime(1, 12), "todo"),
+		deleted,
+	}
+}
+
+func TestBoardChartOptionsIsValid(t *testing.T) {
+	valid := func() *BoardChartOptions {
+		return &BoardChartOptions{
+			Chart:      BoardChartCycleTime,
+			PropertyID: "status",
+			StartValue: "doing",
+			EndValue:   "done",
+			From:       chartTestTime(1, 0),
+			To:         chartTestTime(5, 0),
+			Interval:   BoardChartIntervalDay,
+		}
+	}
+	require.NoError(t, valid().IsValid())
+
+	testCases := []struct {
+		name   string
+		modify func(o *BoardChartOptions)
+	}{
+		{"missing property", func(o *BoardChartOptions) { o.PropertyID = "" }},
+		{"invalid property", func(o *BoardChartOptions) { o.PropertyID = `status"` }},
+		{"inverted range", func(o *BoardChartOptions) { o.From, o.To = o.To, o.From }},
+		{"unknown interval", func(o *BoardChartOptions) { o.Interval = "month" }},
+		{"unknown chart", func(o *BoardChartOptions) { o.Chart = "pie" }},
+		{"cycle time without start value", func(o *BoardChartOptions) { o.StartValue = "" }},
+		{"burndown without date property", func(o *BoardChartOptions) { o.Chart = BoardChartBurndown }},
+		{"too many points", func(o *BoardChartOptions) { o.From = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli() }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := valid()
+			tc.modify(opts)
+			require.ErrorIs(t, opts.IsValid(), ErrInvalidBoardChartOptions)
+		})
+	}
+}
+
+func TestBoardChartBuckets(t *testing.T) {
+	t.Run("days in a time zone", func(t *testing.T) {
+		location, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+
+		opts := &BoardChartOptions{
+			From:     chartTestTime(1, 12),
+			To:       chartTestTime(3, 12),
+			Interval: BoardChartIntervalDay,
+			Location: location,
+		}
+		buckets := opts.buckets()
+		require.Len(t, buckets, 3)
+		require.Equal(t, time.Date(2022, 8, 1, 0, 0, 0, 0, location).UnixMilli(), buckets[0])
+	})
+
+	t.Run("weeks start on Monday", func(t *testing.T) {
+		opts := &BoardChartOptions{
+			From:     chartTestTime(10, 12),
+			To:       chartTestTime(20, 12),
+			Interval: BoardChartIntervalWeek,
+		}
+		require.Equal(t, []int64{chartTestTime(8, 0), chartTestTime(15, 0)}, opts.buckets())
+	})
+}
+
+func TestComputeBoardChart(t *testing.T) {
+	opts := &BoardChartOptions{
+		PropertyID:     "status",
+		StartValue:     "doing",
+		EndValue:       "done",
+		DatePropertyID: "due",
+		From:           chartTestTime(1, 0),
+		To:             chartTestTime(5, 23),
+		Interval:       BoardChartIntervalDay,
+	}
+
+	t.Run("cumulative flow", func(t *testing.T) {
+		opts.Chart = BoardChartCumulativeFlow
+		chart := ComputeBoardChart("board-id", opts, chartTestHistory())
+		require.Len(t, chart.CumulativeFlow, 5)
+
+		expected := []map[string]int{
+			{"todo": 2},
+			{"doing": 2, "todo": 1},
+			{"doing": 1, "done": 1},
+			{"done": 2},
+			{"done": 2},
+		}
+		for i, point := range chart.CumulativeFlow {
+			assert.Equal(t, chartTestTime(i+1, 0), point.Timestamp)
+			assert.Equal(t, expected[i], point.Counts)
+		}
+	})
+
+	t.Run("cycle time", func(t *testing.T) {
+		opts.Chart = BoardChartCycleTime
+		chart := ComputeBoardChart("board-id", opts, chartTestHistory())
+		report := chart.CycleTime
+		require.NotNil(t, report)
+		require.Equal(t, 2, report.Count)
+
+		require.Equal(t, "card2", report.Cards[0].CardID)
+		require.Equal(t, 24*time.Hour.Milliseconds(), report.Cards[0].Duration)
+		require.Equal(t, "card1", report.Cards[1].CardID)
+		require.Equal(t, 48*time.Hour.Milliseconds(), report.Cards[1].Duration)
+
+		require.Equal(t, 36*time.Hour.Milliseconds(), report.Average)
+		require.Equal(t, 36*time.Hour.Milliseconds(), report.Median)
+		require.Equal(t, 48*time.Hour.Milliseconds(), report.Percentile85)
+	})
+
+	t.Run("throughput", func(t *testing.T) {
+		opts.Chart = BoardChartThroughput
+		chart := ComputeBoardChart("board-id", opts, chartTestHistory())
+		counts := make([]int, 0, len(chart.Throughput))
+		for _, point := range chart.Throughput {
+			counts = append(counts, point.Count)
+		}
+		require.Equal(t, []int{0, 0, 1, 1, 0}, counts)
+	})
+
+	t.Run("burndown", func(t *testing.T) {
+		opts.Chart = BoardChartBurndown
+		opts.To = chartTestTime(16, 0)
+		defer func() { opts.To = chartTestTime(5, 23) }()
+
+		chart := ComputeBoardChart("board-id", opts, chartTestHistory())
+		require.Len(t, chart.Burndown, 16)
+		remaining := make([]int, 0, 5)
+		for _, point := range chart.Burndown[:5] {
+			remaining = append(remaining, point.Remaining)
+		}
+		require.Equal(t, []int{1, 2, 1, 0, 0}, remaining)
+
+		require.InDelta(t, 2*(1-float64(chartTestTime(2, 0)-1-opts.From)/float64(opts.To-opts.From)), chart.Burndown[0].Ideal, 0.0001)
+		require.Zero(t, chart.Burndown[15].Ideal)
+	})
+}
@@ -0,0 +1,220 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+)
+
+const (
+	BulkCardActionSetProperty  = "setProperty"
+	BulkCardActionAddOption    = "addOption"
+	BulkCardActionRemoveOption = "removeOption"
+	BulkCardActionAssignPerson = "assignPerson"
+	BulkCardActionMoveToBoard  = "moveToBoard"
+	BulkCardActionArchive      = "archive"
+	BulkCardActionDelete       = "delete"
+)
+
+// MaxBulkCards is the maximum number of cards a bulk operation can change.
+const MaxBulkCards = 1000
+
+var (
+	ErrBulkCardsNoSelection     = errors.New("either card IDs or a view ID is required")
+	ErrBulkCardsTooManyCards    = errors.New("too many cards selected for a bulk operation")
+	ErrBulkCardsUnknownAction   = errors.New("unknown bulk card action")
+	ErrBulkCardsMissingProperty = errors.New("the action requires a property ID")
+	ErrBulkCardsMissingValue    = errors.New("the action requires a value")
+	ErrBulkCardsMissingBoard    = errors.New("the action requires a destination board ID")
+	ErrBulkCardsInvalidProperty = errors.New("the property doesn't support the action")
+	ErrBulkCardsUnknownOption   = errors.New("the value must be an option ID of the property")
+)
+
+// BulkCardOperation is an action applied at once to a selection of cards
+// of a board
+// swagger:model
+type BulkCardOperation struct {
+	// The IDs of the cards to change. Either CardIDs or ViewID is required
+	// required: false
+	CardIDs []string `json:"cardIds"`
+
+	// The ID of a view of the board, whose filter selects the cards to change
+	// required: false
+	ViewID string `json:"viewId"`
+
+	// The action to apply: setProperty, addOption, removeOption,
+	// assignPerson, moveToBoard, archive or delete
+	// required: true
+	Action string `json:"action"`
+
+	// The ID of the property changed by setProperty, addOption,
+	// removeOption and assignPerson
+	// required: false
+	PropertyID string `json:"propertyId"`
+
+	// The value set by setProperty, the option ID added or removed by
+	// addOption and removeOption, or the user ID assigned by assignPerson
+	// required: false
+	Value interface{} `json:"value"`
+
+	// The destination board of moveToBoard
+	// required: false
+	BoardID string `json:"boardId"`
+}
+
+func BulkCardOperationFromJSON(data io.Reader) (*BulkCardOperation, error) {
+	var op *BulkCardOperation
+	if err := json.NewDecoder(data).Decode(&op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// IsValid checks that the operation selects cards and has the parameters
+// its action needs.
+func (o *BulkCardOperation) IsValid() error {
+	if len(o.CardIDs) == 0 && o.ViewID == "" {
+		return ErrBulkCardsNoSelection
+	}
+	if len(o.CardIDs) > MaxBulkCards {
+		return ErrBulkCardsTooManyCards
+	}
+
+	switch o.Action {
+	case BulkCardActionSetProperty:
+		if o.PropertyID == "" {
+			return ErrBulkCardsMissingProperty
+		}
+	case BulkCardActionAddOption, BulkCardActionRemoveOption, BulkCardActionAssignPerson:
+		if o.PropertyID == "" {
+			return ErrBulkCardsMissingProperty
+		}
+		if value, ok := o.Value.(string); !ok || value == "" {
+			return ErrBulkCardsMissingValue
+		}
+	case BulkCardActionMoveToBoard:
+		if o.BoardID == "" {
+			return ErrBulkCardsMissingBoard
+		}
+	case BulkCardActionArchive, BulkCardActionDelete:
+	default:
+		return ErrBulkCardsUnknownAction
+	}
+	return nil
+}
+
+// PatchCard returns the patch applying a property action to the card, or
+// nil if the card already has the result of the action. pd is the
+// property the action changes.
+func (o *BulkCardOperation) PatchCard(card *Block, pd PropDef) (*BlockPatch, error) {
+	current := cardProperties(card)[pd.ID]
+	var value interface{}
+
+	switch o.Action {
+	case BulkCardActionSetProperty:
+		if pd.isReadOnly() {
+			return nil, ErrBulkCardsInvalidProperty
+		}
+		value = o.Value
+
+	case BulkCardActionAddOption, BulkCardActionRemoveOption:
+		if pd.Type != "select" && pd.Type != "multiSelect" {
+			return nil, ErrBulkCardsInvalidProperty
+		}
+		optionID, _ := o.Value.(string)
+		if _, ok := pd.Options[optionID]; !ok {
+			return nil, ErrBulkCardsUnknownOption
+		}
+		value = toggleListValue(pd.Type == "select", current, optionID, o.Action == BulkCardActionAddOption)
+
+	case BulkCardActionAssignPerson:
+		if pd.Type != "person" && pd.Type != "multiPerson" {
+			return nil, ErrBulkCardsInvalidProperty
+		}
+		userID, _ := o.Value.(string)
+		value = toggleListValue(pd.Type == "person", current, userID, true)
+
+	default:
+		return nil, ErrBulkCardsUnknownAction
+	}
+
+	if propertyValuesEqual(current, value) {
+		return nil, nil
+	}
+	// cleared values are removed from the card, as the clients do
+	if isEmptyPropertyValue(value) {
+		value = nil
+	}
+	expectedUpdateAt := card.UpdateAt
+	return &BlockPatch{
+		UpdatedFields:    map[string]interface{}{"properties": map[string]interface{}{pd.ID: value}},
+		MergeFields:      true,
+		ExpectedUpdateAt: &expectedUpdateAt,
+	}, nil
+}
+
+// toggleListValue adds or removes id from a property value. Single value
+// properties are replaced on add and cleared on remove if they hold id.
+func toggleListValue(single bool, current interface{}, id string, add bool) interface{} {
+	if single {
+		if add {
+			return id
+		}
+		if current == id {
+			return ""
+		}
+		return current
+	}
+
+	ids, _ := stringList(current)
+	result := make([]interface{}, 0, len(ids)+1)
+	found := false
+	for _, existing := range ids {
+		if existing == id {
+			found = true
+			if !add {
+				continue
+			}
+		}
+		result = append(result, existing)
+	}
+	if add && !found {
+		result = append(result, id)
+	}
+	return result
+}
+
+func propertyValuesEqual(a, b interface{}) bool {
+	if isEmptyPropertyValue(a) && isEmptyPropertyValue(b) {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// BulkCardResult holds the outcome of a bulk card operation
+// swagger:model
+type BulkCardResult struct {
+	// The IDs of the selected cards
+	// required: true
+	CardIDs []string `json:"cardIds"`
+
+	// The changed blocks, on the destination board for moveToBoard. Empty
+	// for delete
+	// required: true
+	Blocks []Block `json:"blocks"`
+
+	// The property values dropped by moveToBoard
+	// required: true
+	UnmappedProperties []*UnmappedCardProperty `json:"unmappedProperties"`
+
+	// The WIP limits exceeded by the operation
+	// required: false
+	ExceededWIPLimits []*WIPLimitStatus `json:"exceededWipLimits,omitempty"`
+}
+
+func BulkCardResultFromJSON(data io.Reader) *BulkCardResult {
+	var result *BulkCardResult
+	_ = json.NewDecoder(data).Decode(&result)
+	return result
+}
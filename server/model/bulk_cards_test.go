@@ -0,0 +1,107 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkCardOperationIsValid(t *testing.T) {
+	testCases := []struct {
+		name string
+		op   BulkCardOperation
+		err  error
+	}{
+		{"set property", BulkCardOperation{CardIDs: []string{"c1"}, Action: BulkCardActionSetProperty, PropertyID: "p"}, nil},
+		{"archive by view", BulkCardOperation{ViewID: "v1", Action: BulkCardActionArchive}, nil},
+		{"no selection", BulkCardOperation{Action: BulkCardActionDelete}, ErrBulkCardsNoSelection},
+		{"too many cards", BulkCardOperation{CardIDs: make([]string, MaxBulkCards+1), Action: BulkCardActionDelete}, ErrBulkCardsTooManyCards},
+		{"unknown action", BulkCardOperation{CardIDs: []string{"c1"}, Action: "duplicate"}, ErrBulkCardsUnknownAction},
+		{"missing property", BulkCardOperation{CardIDs: []string{"c1"}, Action: BulkCardActionSetProperty}, ErrBulkCardsMissingProperty},
+		{"missing option", BulkCardOperation{CardIDs: []string{"c1"}, Action: BulkCardActionAddOption, PropertyID: "p"}, ErrBulkCardsMissingValue},
+		{"missing board", BulkCardOperation{CardIDs: []string{"c1"}, Action: BulkCardActionMoveToBoard}, ErrBulkCardsMissingBoard},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.err, tc.op.IsValid())
+		})
+	}
+}
+
+func TestBulkCardOperationPatchCard(t *testing.T) {
+	schema, err := ParsePropertySchema(&Board{
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "done", "value": "Done"},
+			}},
+			{"id": "tags", "name": "Tags", "type": "multiSelect", "options": []interface{}{
+				map[string]interface{}{"id": "bug", "value": "Bug"},
+				map[string]interface{}{"id": "ui", "value": "UI"},
+			}},
+			{"id": "reviewers", "name": "Reviewers", "type": "multiPerson"},
+			{"id": "created", "name": "Created", "type": "createdTime"},
+		},
+	})
+	require.NoError(t, err)
+
+	card := &Block{
+		ID:       "card-id",
+		Type:     TypeCard,
+		UpdateAt: 42,
+		Fields: map[string]interface{}{"properties": map[string]interface{}{
+			"status": "todo",
+			"tags":   []interface{}{"bug"},
+		}},
+	}
+	patchedValue := func(patch *BlockPatch, propertyID string) interface{} {
+		return patch.UpdatedFields["properties"].(map[string]interface{})[propertyID]
+	}
+
+	t.Run("set property", func(t *testing.T) {
+		op := &BulkCardOperation{Action: BulkCardActionSetProperty, PropertyID: "status", Value: "done"}
+		patch, err := op.PatchCard(card, schema["status"])
+		require.NoError(t, err)
+		require.Equal(t, "done", patchedValue(patch, "status"))
+		require.True(t, patch.MergeFields)
+		require.Equal(t, int64(42), *patch.ExpectedUpdateAt)
+	})
+
+	t.Run("add and remove options", func(t *testing.T) {
+		op := &BulkCardOperation{Action: BulkCardActionAddOption, PropertyID: "tags", Value: "ui"}
+		patch, err := op.PatchCard(card, schema["tags"])
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"bug", "ui"}, patchedValue(patch, "tags"))
+
+		op = &BulkCardOperation{Action: BulkCardActionRemoveOption, PropertyID: "tags", Value: "bug"}
+		patch, err = op.PatchCard(card, schema["tags"])
+		require.NoError(t, err)
+		require.Nil(t, patchedValue(patch, "tags"))
+
+		op = &BulkCardOperation{Action: BulkCardActionRemoveOption, PropertyID: "status", Value: "done"}
+		patch, err = op.PatchCard(card, schema["status"])
+		require.NoError(t, err)
+		require.Nil(t, patch, "the card doesn't hold the option")
+	})
+
+	t.Run("assign person", func(t *testing.T) {
+		op := &BulkCardOperation{Action: BulkCardActionAssignPerson, PropertyID: "reviewers", Value: "user-id"}
+		patch, err := op.PatchCard(card, schema["reviewers"])
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"user-id"}, patchedValue(patch, "reviewers"))
+	})
+
+	t.Run("unsupported properties", func(t *testing.T) {
+		op := &BulkCardOperation{Action: BulkCardActionAddOption, PropertyID: "reviewers", Value: "bug"}
+		_, err := op.PatchCard(card, schema["reviewers"])
+		require.ErrorIs(t, err, ErrBulkCardsInvalidProperty)
+
+		op = &BulkCardOperation{Action: BulkCardActionAddOption, PropertyID: "tags", Value: "feature"}
+		_, err = op.PatchCard(card, schema["tags"])
+		require.ErrorIs(t, err, ErrBulkCardsUnknownOption)
+
+		op = &BulkCardOperation{Action: BulkCardActionSetProperty, PropertyID: "created", Value: "1"}
+		_, err = op.PatchCard(card, schema["created"])
+		require.ErrorIs(t, err, ErrBulkCardsInvalidProperty)
+	})
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidViewFilter = errors.New("invalid view filter")
+
+const (
+	FilterOperationAnd = "and"
+	FilterOperationOr  = "or"
+
+	FilterConditionIncludes    = "includes"
+	FilterConditionNotIncludes = "notIncludes"
+	FilterConditionIsEmpty     = "isEmpty"
+	FilterConditionIsNotEmpty  = "isNotEmpty"
+)
+
+// CardFilter is the filter of a view, as saved by the clients. It is
+// either a group, with an operation and nested filters, or a clause on a
+// card property.
+type CardFilter struct {
+	Operation string        `json:"operation,omitempty"`
+	Filters   []*CardFilter `json:"filters,omitempty"`
+
+	PropertyID string   `json:"propertyId,omitempty"`
+	Condition  string   `json:"condition,omitempty"`
+	Values     []string `json:"values,omitempty"`
+}
+
+// ParseViewFilter returns the filter of a view block, or nil if the view
+// has none.
+func ParseViewFilter(view *Block) (*CardFilter, error) {
+	raw, ok := view.Fields["filter"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var filter *CardFilter
+	if err := json.Unmarshal(data, &filter); err != nil {
+		return nil, fmt.Errorf("view %s: %w: %s", view.ID, ErrInvalidViewFilter, err.Error())
+	}
+	return filter, nil
+}
+
+func (f *CardFilter) isGroup() bool {
+	return f.Operation != "" || f.Filters != nil
+}
+
+// Matches returns true if the card meets the filter. It follows the rules
+// of the clients: an empty group matches every card, and so does a clause
+// without values for the includes conditions.
+func (f *CardFilter) Matches(card *Block) bool {
+	if f == nil {
+		return true
+	}
+	if !f.isGroup() {
+		return f.clauseMatches(card)
+	}
+	if len(f.Filters) == 0 {
+		return true
+	}
+
+	if f.Operation == FilterOperationOr {
+		for _, filter := range f.Filters {
+			if filter.Matches(card) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, filter := range f.Filters {
+		if !filter.Matches(card) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *CardFilter) clauseMatches(card *Block) bool {
+	value := cardProperties(card)[f.PropertyID]
+
+	switch f.Condition {
+	case FilterConditionIncludes, FilterConditionNotIncludes:
+		if len(f.Values) == 0 {
+			return true
+		}
+		included := false
+		for _, v := range f.Values {
+			if propertyValueIncludes(value, v) {
+				included = true
+				break
+			}
+		}
+		return included == (f.Condition == FilterConditionIncludes)
+	case FilterConditionIsEmpty:
+		return isEmptyPropertyValue(value)
+	case FilterConditionIsNotEmpty:
+		return !isEmptyPropertyValue(value)
+	}
+	// unknown conditions are ignored, as the clients do
+	return true
+}
+
+func propertyValueIncludes(value interface{}, v string) bool {
+	if list, ok := value.([]interface{}); ok {
+		for _, item := range list {
+			if item == v {
+				return true
+			}
+		}
+		return false
+	}
+	return value == v
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardFilter(t *testing.T) {
+	card := &Block{
+		ID:   "card-id",
+		Type: TypeCard,
+		Fields: map[string]interface{}{"properties": map[string]interface{}{
+			"status": "todo",
+			"tags":   []interface{}{"bug", "ui"},
+		}},
+	}
+
+	testCases := []struct {
+		name    string
+		filter  map[string]interface{}
+		matches bool
+	}{
+		{"no filter", nil, true},
+		{"empty group", map[string]interface{}{"operation": "and", "filters": []interface{}{}}, true},
+		{"includes", clauseFilter("status", FilterConditionIncludes, "todo", "done"), true},
+		{"includes list value", clauseFilter("tags", FilterConditionIncludes, "ui"), true},
+		{"includes without values", clauseFilter("status", FilterConditionIncludes), true},
+		{"not includes", clauseFilter("tags", FilterConditionNotIncludes, "bug"), false},
+		{"is empty", clauseFilter("owner", FilterConditionIsEmpty), true},
+		{"is not empty", clauseFilter("owner", FilterConditionIsNotEmpty), false},
+		{"and group", map[string]interface{}{"operation": "and", "filters": []interface{}{
+			clauseFilter("status", FilterConditionIncludes, "todo"),
+			clauseFilter("tags", FilterConditionIncludes, "feature"),
+		}}, false},
+		{"or group", map[string]interface{}{"operation": "or", "filters": []interface{}{
+			clauseFilter("status", FilterConditionIncludes, "done"),
+			map[string]interface{}{"operation": "and", "filters": []interface{}{
+				clauseFilter("tags", FilterConditionIncludes, "bug"),
+			}},
+		}}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			view := &Block{ID: "view-id", Type: TypeView, Fields: map[string]interface{}{}}
+			if tc.filter != nil {
+				view.Fields["filter"] = tc.filter
+			}
+			filter, err := ParseViewFilter(view)
+			require.NoError(t, err)
+			require.Equal(t, tc.matches, filter.Matches(card))
+		})
+	}
+
+	t.Run("invalid filter", func(t *testing.T) {
+		view := &Block{ID: "view-id", Type: TypeView, Fields: map[string]interface{}{"filter": "status is todo"}}
+		_, err := ParseViewFilter(view)
+		require.ErrorIs(t, err, ErrInvalidViewFilter)
+	})
+}
+
+func clauseFilter(propertyID, condition string, values ...string) map[string]interface{} {
+	list := make([]interface{}, len(values))
+	for i, v := range values {
+		list[i] = v
+	}
+	return map[string]interface{}{"propertyId": propertyID, "condition": condition, "values": list}
+}
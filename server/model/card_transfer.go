@@ -0,0 +1,198 @@
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	CardTransferNoMatchingProperty = "noMatchingProperty"
+	CardTransferTypeMismatch       = "typeMismatch"
+	CardTransferUnknownOption      = "unknownOption"
+	CardTransferRelation           = "relation"
+)
+
+// CardTransferRequest is the destination of a card moved or copied to
+// another board
+// swagger:model
+type CardTransferRequest struct {
+	// The ID of the destination board
+	// required: true
+	BoardID string `json:"boardId"`
+}
+
+// CardTransferResult holds the blocks of a moved or copied card and the
+// property values that couldn't be mapped to the destination board
+// swagger:model
+type CardTransferResult struct {
+	// The card and its child blocks on the destination board
+	// required: true
+	Blocks []Block `json:"blocks"`
+
+	// The property values dropped during the transfer
+	// required: true
+	UnmappedProperties []*UnmappedCardProperty `json:"unmappedProperties"`
+
+	// The WIP limits of the destination board exceeded by the transfer
+	// required: false
+	ExceededWIPLimits []*WIPLimitStatus `json:"exceededWipLimits,omitempty"`
+}
+
+func CardTransferResultFromJSON(data io.Reader) *CardTransferResult {
+	var result *CardTransferResult
+	_ = json.NewDecoder(data).Decode(&result)
+	return result
+}
+
+// UnmappedCardProperty is a card property value that has no equivalent on
+// the destination board
+// swagger:model
+type UnmappedCardProperty struct {
+	// The ID of the card, on the destination board
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The ID of the property on the source board
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	// The name of the property on the source board
+	// required: true
+	PropertyName string `json:"propertyName"`
+
+	// The dropped value
+	// required: true
+	Value interface{} `json:"value"`
+
+	// Why the value was dropped: noMatchingProperty, typeMismatch,
+	// unknownOption or relation
+	// required: true
+	Reason string `json:"reason"`
+}
+
+// textPropertyTypes can hold each other's values.
+var textPropertyTypes = map[string]bool{"text": true, "url": true, "email": true, "phone": true}
+
+// MapCardProperties maps the property values of a card from the source
+// schema to the destination schema. Properties are matched by name and
+// options by label, ignoring case. Values of read only properties are
+// left out, as the destination board computes its own. The unmapped
+// values are sorted by property index and don't have a card ID set.
+func MapCardProperties(source, dest PropSchema, properties map[string]interface{}) (map[string]interface{}, []*UnmappedCardProperty) {
+	destByName := make(map[string]PropDef, len(dest))
+	for _, pd := range dest {
+		name := strings.ToLower(strings.TrimSpace(pd.Name))
+		if existing, ok := destByName[name]; !ok || pd.Index < existing.Index {
+			destByName[name] = pd
+		}
+	}
+
+	mapped := map[string]interface{}{}
+	var unmapped []*UnmappedCardProperty
+	for propertyID, value := range properties {
+		sd, ok := source[propertyID]
+		if (ok && sd.isReadOnly()) || isEmptyPropertyValue(value) {
+			continue
+		}
+
+		report := func(reason string) {
+			unmapped = append(unmapped, &UnmappedCardProperty{
+				PropertyID:   propertyID,
+				PropertyName: sd.Name,
+				Value:        value,
+				Reason:       reason,
+			})
+		}
+
+		if !ok {
+			report(CardTransferNoMatchingProperty)
+			continue
+		}
+		dd, ok := destByName[strings.ToLower(strings.TrimSpace(sd.Name))]
+		if !ok {
+			report(CardTransferNoMatchingProperty)
+			continue
+		}
+
+		switch {
+		case sd.Type == PropertyTypeRelation:
+			// related cards stay on the source board
+			report(CardTransferRelation)
+
+		case sd.Type == "select" || sd.Type == "multiSelect":
+			if (dd.Type != "select" && dd.Type != "multiSelect") || (sd.Type == "multiSelect" && dd.Type == "select") {
+				report(CardTransferTypeMismatch)
+				continue
+			}
+			optionIDs, allMapped := mapOptions(sd, dd, value)
+			if !allMapped {
+				report(CardTransferUnknownOption)
+			}
+			if len(optionIDs) == 0 {
+				continue
+			}
+			if dd.Type == "select" {
+				mapped[dd.ID] = optionIDs[0]
+			} else {
+				mapped[dd.ID] = optionIDs
+			}
+
+		case sd.Type == dd.Type, textPropertyTypes[sd.Type] && textPropertyTypes[dd.Type]:
+			mapped[dd.ID] = value
+
+		default:
+			report(CardTransferTypeMismatch)
+		}
+	}
+
+	// values of unknown properties go last
+	sort.Slice(unmapped, func(i, j int) bool {
+		a, aKnown := source[unmapped[i].PropertyID]
+		b, bKnown := source[unmapped[j].PropertyID]
+		if aKnown != bKnown {
+			return aKnown
+		}
+		if a.Index != b.Index {
+			return a.Index < b.Index
+		}
+		return unmapped[i].PropertyID < unmapped[j].PropertyID
+	})
+	return mapped, unmapped
+}
+
+// mapOptions returns the IDs of the destination options with the labels
+// of the source option IDs in value, and whether all of them matched.
+func mapOptions(source, dest PropDef, value interface{}) ([]interface{}, bool) {
+	var sourceIDs []string
+	if id, ok := value.(string); ok {
+		sourceIDs = []string{id}
+	} else if ids, ok := stringList(value); ok {
+		sourceIDs = ids
+	} else {
+		return nil, false
+	}
+
+	destByLabel := make(map[string]string, len(dest.Options))
+	for _, option := range dest.Options {
+		destByLabel[strings.ToLower(strings.TrimSpace(option.Value))] = option.ID
+	}
+
+	allMapped := true
+	optionIDs := make([]interface{}, 0, len(sourceIDs))
+	for _, id := range sourceIDs {
+		option, ok := source.Options[id]
+		if !ok {
+			allMapped = false
+			continue
+		}
+		destID, ok := destByLabel[strings.ToLower(strings.TrimSpace(option.Value))]
+		if !ok {
+			allMapped = false
+			continue
+		}
+		optionIDs = append(optionIDs, destID)
+	}
+	return optionIDs, allMapped
+}
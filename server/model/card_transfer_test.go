@@ -0,0 +1,85 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapCardProperties(t *testing.T) {
+	source, err := ParsePropertySchema(&Board{
+		CardProperties: []map[string]interface{}{
+			{"id": "s-status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "s-todo", "value": "To do"},
+				map[string]interface{}{"id": "s-blocked", "value": "Blocked"},
+			}},
+			{"id": "s-tags", "name": "Tags", "type": "multiSelect", "options": []interface{}{
+				map[string]interface{}{"id": "s-bug", "value": "Bug"},
+				map[string]interface{}{"id": "s-ui", "value": "UI"},
+			}},
+			{"id": "s-estimate", "name": "Estimate", "type": "number"},
+			{"id": "s-link", "name": "Link", "type": "url"},
+			{"id": "s-owner", "name": "Owner", "type": "person"},
+			{"id": "s-related", "name": "Related", "type": PropertyTypeRelation},
+			{"id": "s-notes", "name": "Notes", "type": "text"},
+			{"id": "s-created", "name": "Created", "type": "createdTime"},
+		},
+	})
+	require.NoError(t, err)
+
+	dest, err := ParsePropertySchema(&Board{
+		CardProperties: []map[string]interface{}{
+			{"id": "d-status", "name": "status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "d-todo", "value": "to do"},
+			}},
+			{"id": "d-tags", "name": "Tags", "type": "multiSelect", "options": []interface{}{
+				map[string]interface{}{"id": "d-bug", "value": "Bug"},
+			}},
+			{"id": "d-estimate", "name": "Estimate", "type": "text"},
+			{"id": "d-link", "name": "Link", "type": "text"},
+			{"id": "d-owner", "name": "Owner", "type": "person"},
+			{"id": "d-related", "name": "Related", "type": PropertyTypeRelation},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("values are mapped by name and label", func(t *testing.T) {
+		mapped, unmapped := MapCardProperties(source, dest, map[string]interface{}{
+			"s-status":  "s-todo",
+			"s-tags":    []interface{}{"s-bug"},
+			"s-link":    "https://example.com",
+			"s-owner":   "user-id",
+			"s-created": "ignored",
+			"s-notes":   "",
+		})
+		require.Empty(t, unmapped)
+		require.Equal(t, map[string]interface{}{
+			"d-status": "d-todo",
+			"d-tags":   []interface{}{"d-bug"},
+			"d-link":   "https://example.com",
+			"d-owner":  "user-id",
+		}, mapped)
+	})
+
+	t.Run("unmappable values are reported", func(t *testing.T) {
+		mapped, unmapped := MapCardProperties(source, dest, map[string]interface{}{
+			"s-status":   "s-blocked",
+			"s-tags":     []interface{}{"s-bug", "s-ui"},
+			"s-estimate": "5",
+			"s-related":  []interface{}{"card-id"},
+			"s-notes":    "notes",
+			"s-deleted":  "stale value",
+		})
+		require.Equal(t, map[string]interface{}{
+			"d-tags": []interface{}{"d-bug"},
+		}, mapped)
+		require.Equal(t, []*UnmappedCardProperty{
+			{PropertyID: "s-status", PropertyName: "Status", Value: "s-blocked", Reason: CardTransferUnknownOption},
+			{PropertyID: "s-tags", PropertyName: "Tags", Value: []interface{}{"s-bug", "s-ui"}, Reason: CardTransferUnknownOption},
+			{PropertyID: "s-estimate", PropertyName: "Estimate", Value: "5", Reason: CardTransferTypeMismatch},
+			{PropertyID: "s-related", PropertyName: "Related", Value: []interface{}{"card-id"}, Reason: CardTransferRelation},
+			{PropertyID: "s-notes", PropertyName: "Notes", Value: "notes", Reason: CardTransferNoMatchingProperty},
+			{PropertyID: "s-deleted", Value: "stale value", Reason: CardTransferNoMatchingProperty},
+		}, unmapped)
+	})
+}
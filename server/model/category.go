@@ -8,6 +8,14 @@ import (
 	"github.com/mattermost/focalboard/server/utils"
 )
 
+const (
+	// CategoryTypePrivate is a category owned by a single user.
+	CategoryTypePrivate = "private"
+
+	// CategoryTypeShared is a category defined for the whole team.
+	CategoryTypeShared = "shared"
+)
+
 // Category is a board category
 // swagger:model
 type Category struct {
@@ -42,12 +50,36 @@ type Category struct {
 	// Category's state in client side
 	// required: true
 	Collapsed bool `json:"collapsed"`
+
+	// The type of the category, private to a user or shared with the whole team
+	// required: false
+	Type string `json:"type"`
+
+	// The ID of the parent category when this category is nested. Must be of the same type
+	// required: false
+	ParentID string `json:"parentID"`
+
+	// The position of the category among its siblings
+	// required: false
+	SortOrder int64 `json:"sortOrder"`
+
+	// Whether new boards of the team are placed in this category. Only one shared category per team can be the default
+	// required: false
+	IsDefault bool `json:"isDefault"`
 }
 
 func (c *Category) Hydrate() {
 	c.ID = utils.NewID(utils.IDTypeNone)
 	c.CreateAt = utils.GetMillis()
 	c.UpdateAt = c.CreateAt
+	if c.Type == "" {
+		c.Type = CategoryTypePrivate
+	}
+}
+
+// IsShared returns true if the category is shared with the whole team.
+func (c *Category) IsShared() bool {
+	return c.Type == CategoryTypeShared
 }
 
 func (c *Category) IsValid() error {
@@ -59,8 +91,24 @@ func (c *Category) IsValid() error {
 		return newErrInvalidCategory("category name cannot be empty")
 	}
 
-	if strings.TrimSpace(c.UserID) == "" {
-		return newErrInvalidCategory("category user ID cannot be empty")
+	switch c.Type {
+	case "", CategoryTypePrivate:
+		if strings.TrimSpace(c.UserID) == "" {
+			return newErrInvalidCategory("category user ID cannot be empty")
+		}
+		if c.IsDefault {
+			return newErrInvalidCategory("only shared categories can be the default category")
+		}
+	case CategoryTypeShared:
+		if c.UserID != "" {
+			return newErrInvalidCategory("shared categories cannot belong to a user")
+		}
+	default:
+		return newErrInvalidCategory("invalid category type")
+	}
+
+	if c.ParentID != "" && c.ParentID == c.ID {
+		return newErrInvalidCategory("category cannot be its own parent")
 	}
 
 	if strings.TrimSpace(c.TeamID) == "" {
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryIsValid(t *testing.T) {
+	t.Run("private category", func(t *testing.T) {
+		category := &Category{ID: "category-id", Name: "Category", UserID: "user-id", TeamID: "team-id"}
+		require.NoError(t, category.IsValid())
+
+		category.UserID = ""
+		require.Error(t, category.IsValid())
+	})
+
+	t.Run("private category cannot be the default", func(t *testing.T) {
+		category := &Category{ID: "category-id", Name: "Category", UserID: "user-id", TeamID: "team-id", IsDefault: true}
+		require.Error(t, category.IsValid())
+	})
+
+	t.Run("shared category", func(t *testing.T) {
+		category := &Category{ID: "category-id", Name: "Category", TeamID: "team-id", Type: CategoryTypeShared, IsDefault: true}
+		require.NoError(t, category.IsValid())
+
+		category.UserID = "user-id"
+		require.Error(t, category.IsValid())
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		category := &Category{ID: "category-id", Name: "Category", UserID: "user-id", TeamID: "team-id", Type: "invalid"}
+		require.Error(t, category.IsValid())
+	})
+
+	t.Run("category cannot be its own parent", func(t *testing.T) {
+		category := &Category{ID: "category-id", Name: "Category", UserID: "user-id", TeamID: "team-id", ParentID: "category-id"}
+		require.Error(t, category.IsValid())
+	})
+}
+
+func TestCategoryHydrate(t *testing.T) {
+	category := &Category{Name: "Category"}
+	category.Hydrate()
+	require.NotEmpty(t, category.ID)
+	require.Equal(t, CategoryTypePrivate, category.Type)
+
+	shared := &Category{Name: "Category", Type: CategoryTypeShared}
+	shared.Hydrate()
+	require.Equal(t, CategoryTypeShared, shared.Type)
+}
@@ -0,0 +1,570 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	PropertyTypeFormula  = "formula"
+	PropertyTypeRollup   = "rollup"
+	PropertyTypeRelation = "relation"
+)
+
+const (
+	RollupFunctionCount       = "count"
+	RollupFunctionCountValues = "countValues"
+	RollupFunctionSum         = "sum"
+	RollupFunctionAvg         = "avg"
+	RollupFunctionMin         = "min"
+	RollupFunctionMax         = "max"
+)
+
+var ErrInvalidRollup = errors.New("invalid rollup property")
+
+// RollupDef is the configuration of a rollup property, which aggregates a
+// property across the cards related to a card.
+type RollupDef struct {
+	// The relation property holding the IDs of the related cards. If empty,
+	// the related cards are the child cards of the card
+	RelationPropertyID string `json:"relationPropertyId"`
+
+	// The property aggregated on the related cards
+	TargetPropertyID string `json:"targetPropertyId"`
+
+	// The aggregation function: count, countValues, sum, avg, min or max
+	Function string `json:"function"`
+}
+
+// IsComputed returns true if the property value is computed by the server.
+func (pd PropDef) IsComputed() bool {
+	return pd.Type == PropertyTypeFormula || pd.Type == PropertyTypeRollup
+}
+
+// HasComputedProperties returns true if the schema has formula or rollup
+// properties.
+func (s PropSchema) HasComputedProperties() bool {
+	for _, pd := range s {
+		if pd.IsComputed() {
+			return true
+		}
+	}
+	return false
+}
+
+// findProperty looks up a property by ID or, failing that, by name
+// ignoring case.
+func (s PropSchema) findProperty(nameOrID string) (PropDef, bool) {
+	if pd, ok := s[nameOrID]; ok {
+		return pd, true
+	}
+	for _, pd := range s {
+		if strings.EqualFold(pd.Name, nameOrID) {
+			return pd, true
+		}
+	}
+	return PropDef{}, false
+}
+
+// ValidateComputedProperties checks that the formulas of the schema parse
+// and that formulas and rollups reference existing properties.
+func (s PropSchema) ValidateComputedProperties() error {
+	for _, pd := range s {
+		switch pd.Type {
+		case PropertyTypeFormula:
+			formula, err := ParseFormula(pd.Formula)
+			if err != nil {
+				return fmt.Errorf("property %s: %w", pd.Name, err)
+			}
+			for _, name := range formula.Properties() {
+				if _, ok := s.findProperty(name); !ok {
+					return fmt.Errorf("property %s: %w: %s", pd.Name, ErrFormulaUnknownProp, name)
+				}
+			}
+
+		case PropertyTypeRollup:
+			if err := s.validateRollup(pd); err != nil {
+				return fmt.Errorf("property %s: %w", pd.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s PropSchema) validateRollup(pd PropDef) error {
+	if pd.Rollup == nil {
+		return ErrInvalidRollup
+	}
+
+	switch pd.Rollup.Function {
+	case RollupFunctionCount:
+	case RollupFunctionCountValues, RollupFunctionSum, RollupFunctionAvg, RollupFunctionMin, RollupFunctionMax:
+		if _, ok := s[pd.Rollup.TargetPropertyID]; !ok {
+			return fmt.Errorf("%w: unknown target property", ErrInvalidRollup)
+		}
+	default:
+		return fmt.Errorf("%w: unknown function %q", ErrInvalidRollup, pd.Rollup.Function)
+	}
+
+	if pd.Rollup.RelationPropertyID != "" {
+		relation, ok := s[pd.Rollup.RelationPropertyID]
+		if !ok || relation.Type != PropertyTypeRelation {
+			return fmt.Errorf("%w: unknown relation property", ErrInvalidRollup)
+		}
+	}
+	return nil
+}
+
+// PropertyEvaluator computes the formula and rollup properties of the
+// cards of a board. Values are cached, so an evaluator must not be reused
+// after the cards change.
+type PropertyEvaluator struct {
+	schema   PropSchema
+	formulas map[string]*Formula
+	cards    map[string]*Block
+	children map[string][]*Block
+	values   map[string]interface{}
+	errors   map[string]error
+	visiting map[string]bool
+	clock    time.Time
+}
+
+// NewPropertyEvaluator creates an evaluator for the given cards. Formulas
+// that reference the current time are evaluated with now.
+func NewPropertyEvaluator(schema PropSchema, cards []Block, now time.Time) *PropertyEvaluator {
+	e := &PropertyEvaluator{
+		schema:   schema,
+		formulas: map[string]*Formula{},
+		cards:    make(map[string]*Block, len(cards)),
+		children: map[string][]*Block{},
+		values:   map[string]interface{}{},
+		errors:   map[string]error{},
+		visiting: map[string]bool{},
+		clock:    now,
+	}
+
+	for i := range cards {
+		card := &cards[i]
+		e.cards[card.ID] = card
+		if card.ParentID != "" && card.ParentID != card.BoardID {
+			e.children[card.ParentID] = append(e.children[card.ParentID], card)
+		}
+	}
+
+	return e
+}
+
+// ComputeCard returns the values of the computed properties of a card,
+// keyed by property ID and formatted the way they are stored in the card
+// properties. Properties that can't be computed have an empty value.
+func (e *PropertyEvaluator) ComputeCard(cardID string) map[string]string {
+	card, ok := e.cards[cardID]
+	if !ok {
+		return nil
+	}
+
+	computed := map[string]string{}
+	for _, pd := range e.schema {
+		if !pd.IsComputed() {
+			continue
+		}
+		value, err := e.value(card, pd)
+		if err != nil {
+			computed[pd.ID] = ""
+			continue
+		}
+		computed[pd.ID] = FormatFormulaValue(value)
+	}
+	return computed
+}
+
+func (e *PropertyEvaluator) value(card *Block, pd PropDef) (interface{}, error) {
+	if !pd.IsComputed() {
+		return e.rawValue(card, pd), nil
+	}
+
+	key := card.ID + "/" + pd.ID
+	if value, ok := e.values[key]; ok {
+		return value, nil
+	}
+	if err, ok := e.errors[key]; ok {
+		return nil, err
+	}
+	if e.visiting[key] {
+		return nil, ErrFormulaCycle
+	}
+
+	e.visiting[key] = true
+	var value interface{}
+	var err error
+	if pd.Type == PropertyTypeFormula {
+		value, err = e.evalFormula(card, pd)
+	} else {
+		value, err = e.evalRollup(card, pd)
+	}
+	delete(e.visiting, key)
+
+	if err != nil {
+		e.errors[key] = err
+		return nil, err
+	}
+	e.values[key] = value
+	return value, nil
+}
+
+func (e *PropertyEvaluator) evalFormula(card *Block, pd PropDef) (interface{}, error) {
+	formula, ok := e.formulas[pd.ID]
+	if !ok {
+		var err error
+		if formula, err = ParseFormula(pd.Formula); err != nil {
+			return nil, err
+		}
+		e.formulas[pd.ID] = formula
+	}
+	return formula.eval(&cardFormulaContext{evaluator: e, card: card})
+}
+
+func (e *PropertyEvaluator) evalRollup(card *Block, pd PropDef) (interface{}, error) {
+	if err := e.schema.validateRollup(pd); err != nil {
+		return nil, err
+	}
+
+	related := e.relatedCards(card, pd.Rollup.RelationPropertyID)
+	if pd.Rollup.Function == RollupFunctionCount {
+		return float64(len(related)), nil
+	}
+
+	target := e.schema[pd.Rollup.TargetPropertyID]
+	var values []interface{}
+	for _, relatedCard := range related {
+		value, err := e.value(relatedCard, target)
+		if errors.Is(err, ErrFormulaCycle) {
+			return nil, err
+		}
+		if err != nil || value == nil || value == "" {
+			continue
+		}
+		values = append(values, value)
+	}
+
+	if pd.Rollup.Function == RollupFunctionCountValues {
+		return float64(len(values)), nil
+	}
+
+	var numbers []float64
+	for _, value := range values {
+		if number, err := formulaNumber(value); err == nil {
+			numbers = append(numbers, number)
+		}
+	}
+
+	switch pd.Rollup.Function {
+	case RollupFunctionSum:
+		sum := 0.0
+		for _, number := range numbers {
+			sum += number
+		}
+		return sum, nil
+	case RollupFunctionAvg:
+		if len(numbers) == 0 {
+			return nil, nil
+		}
+		sum := 0.0
+		for _, number := range numbers {
+			sum += number
+		}
+		return sum / float64(len(numbers)), nil
+	}
+
+	args := make([]interface{}, len(numbers))
+	for i, number := range numbers {
+		args[i] = number
+	}
+	if pd.Rollup.Function == RollupFunctionMin {
+		return formulaFunctions["min"].call(nil, args)
+	}
+	return formulaFunctions["max"].call(nil, args)
+}
+
+// relatedCards returns the cards referenced by a relation property of the
+// card, or its child cards if no relation is set.
+func (e *PropertyEvaluator) relatedCards(card *Block, relationPropertyID string) []*Block {
+	if relationPropertyID == "" {
+		return e.children[card.ID]
+	}
+
+	cardIDs := relationCardIDs(card, relationPropertyID)
+	related := make([]*Block, 0, len(cardIDs))
+	for _, id := range cardIDs {
+		if relatedCard, ok := e.cards[id]; ok && relatedCard.ID != card.ID {
+			related = append(related, relatedCard)
+		}
+	}
+	return related
+}
+
+// relationCardIDs returns the IDs stored in a relation property of a card.
+func relationCardIDs(card *Block, relationPropertyID string) []string {
+	var cardIDs []string
+	switch value := cardPropertyValue(card, relationPropertyID).(type) {
+	case []interface{}:
+		for _, id := range value {
+			if s, ok := id.(string); ok {
+				cardIDs = append(cardIDs, s)
+			}
+		}
+	case string:
+		for _, id := range strings.Split(value, ",") {
+			cardIDs = append(cardIDs, strings.TrimSpace(id))
+		}
+	}
+	return cardIDs
+}
+
+// DependentCardIDs returns the IDs of the cards whose computed properties
+// may change after the given cards changed: the cards themselves, their
+// parents and the cards aggregating them in a rollup, recursively. Passing
+// the previous version of a card also covers its previous parent, so the
+// changed cards may be deleted or moved to another board.
+func (e *PropertyEvaluator) DependentCardIDs(changed []Block) []string {
+	// dependents maps a card ID to the cards aggregating it in a rollup
+	dependents := map[string][]string{}
+	for _, pd := range e.schema {
+		if pd.Type != PropertyTypeRollup || pd.Rollup == nil {
+			continue
+		}
+		for _, card := range e.cards {
+			if pd.Rollup.RelationPropertyID == "" {
+				if card.ParentID != "" && card.ParentID != card.BoardID {
+					dependents[card.ID] = append(dependents[card.ID], card.ParentID)
+				}
+				continue
+			}
+			for _, id := range relationCardIDs(card, pd.Rollup.RelationPropertyID) {
+				dependents[id] = append(dependents[id], card.ID)
+			}
+		}
+	}
+
+	var pending []string
+	for _, block := range changed {
+		pending = append(pending, block.ID)
+		if block.ParentID != "" && block.ParentID != block.BoardID {
+			pending = append(pending, block.ParentID)
+		}
+	}
+
+	visited := map[string]bool{}
+	var cardIDs []string
+	for len(pending) != 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		if _, ok := e.cards[id]; ok {
+			cardIDs = append(cardIDs, id)
+		}
+		pending = append(pending, dependents[id]...)
+	}
+	return cardIDs
+}
+
+// CardChangeAffectsComputedProperties returns true if the change of a card
+// from previous to card can change a computed property, because a property
+// read by a formula or a rollup, a stored computed value or the parent of
+// the card changed. A nil previous or card stands for an added or a deleted
+// card.
+func (s PropSchema) CardChangeAffectsComputedProperties(previous, card *Block) bool {
+	if !s.HasComputedProperties() {
+		return false
+	}
+	if previous == nil || card == nil || previous.ParentID != card.ParentID {
+		return true
+	}
+
+	compared := s.computedInputs()
+	for _, pd := range s {
+		if pd.IsComputed() {
+			// a patch replacing the properties may drop the computed values
+			compared[pd.ID] = true
+		}
+	}
+
+	for propertyID := range compared {
+		switch s[propertyID].Type {
+		case "updatedTime", "updatedBy":
+			// these change with any update of the card
+			return true
+		}
+		if !reflect.DeepEqual(cardPropertyValue(previous, propertyID), cardPropertyValue(card, propertyID)) {
+			return true
+		}
+	}
+	return false
+}
+
+// computedInputs returns the IDs of the properties read by the formula and
+// rollup properties of the schema.
+func (s PropSchema) computedInputs() map[string]bool {
+	inputs := map[string]bool{}
+	for _, pd := range s {
+		switch pd.Type {
+		case PropertyTypeFormula:
+			formula, err := ParseFormula(pd.Formula)
+			if err != nil {
+				continue
+			}
+			for _, name := range formula.Properties() {
+				if input, ok := s.findProperty(name); ok {
+					inputs[input.ID] = true
+				}
+			}
+
+		case PropertyTypeRollup:
+			if pd.Rollup == nil {
+				continue
+			}
+			if pd.Rollup.TargetPropertyID != "" {
+				inputs[pd.Rollup.TargetPropertyID] = true
+			}
+			if pd.Rollup.RelationPropertyID != "" {
+				inputs[pd.Rollup.RelationPropertyID] = true
+			}
+		}
+	}
+	return inputs
+}
+
+// rawValue converts the stored value of a regular property to a value
+// formulas can operate on.
+func (e *PropertyEvaluator) rawValue(card *Block, pd PropDef) interface{} {
+	switch pd.Type {
+	case "createdTime":
+		return time.UnixMilli(card.CreateAt).UTC()
+	case "updatedTime":
+		return time.UnixMilli(card.UpdateAt).UTC()
+	case "createdBy":
+		return card.CreatedBy
+	case "updatedBy":
+		return card.ModifiedBy
+	}
+
+	v := cardPropertyValue(card, pd.ID)
+	if v == nil || v == "" {
+		return nil
+	}
+
+	switch pd.Type {
+	case "number":
+		number, err := formulaNumber(v)
+		if err != nil {
+			return nil
+		}
+		return number
+
+	case "checkbox":
+		return v == "true" || v == true
+
+	case "select":
+		id, _ := v.(string)
+		opt, ok := pd.Options[id]
+		if !ok {
+			return nil
+		}
+		return opt.Value
+
+	case "multiSelect":
+		ids, _ := v.([]interface{})
+		values := make([]string, 0, len(ids))
+		for _, id := range ids {
+			s, _ := id.(string)
+			if opt, ok := pd.Options[s]; ok {
+				values = append(values, opt.Value)
+			}
+		}
+		return strings.Join(values, ", ")
+
+	case "date":
+		s, _ := v.(string)
+		var m map[string]int64
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			return nil
+		}
+		from, ok := m["from"]
+		if !ok {
+			return nil
+		}
+		return time.UnixMilli(from).UTC()
+
+	case PropertyTypeRelation:
+		ids, ok := v.([]interface{})
+		if !ok {
+			return fmt.Sprintf("%v", v)
+		}
+		values := make([]string, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, fmt.Sprintf("%v", id))
+		}
+		return strings.Join(values, ", ")
+	}
+
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func cardPropertyValue(card *Block, propertyID string) interface{} {
+	properties, ok := card.Fields["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return properties[propertyID]
+}
+
+type cardFormulaContext struct {
+	evaluator *PropertyEvaluator
+	card      *Block
+}
+
+func (c *cardFormulaContext) propertyValue(name string) (interface{}, error) {
+	pd, ok := c.evaluator.schema.findProperty(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFormulaUnknownProp, name)
+	}
+	return c.evaluator.value(c.card, pd)
+}
+
+func (c *cardFormulaContext) now() time.Time {
+	return c.evaluator.clock
+}
+
+// ValidateCardProperties checks the formula and rollup properties and the
+// property constraints the board would have once the patch is applied.
+func (p *BoardPatch) ValidateCardProperties(board *Board) error {
+	if len(p.UpdatedCardProperties) == 0 {
+		return nil
+	}
+
+	cardPropertiesPatch := &BoardPatch{
+		UpdatedCardProperties: p.UpdatedCardProperties,
+		DeletedCardProperties: p.DeletedCardProperties,
+	}
+	patched := cardPropertiesPatch.Patch(&Board{CardProperties: board.CardProperties})
+
+	schema, err := ParsePropertySchema(patched)
+	if err != nil {
+		return err
+	}
+	if err := schema.ValidateComputedProperties(); err != nil {
+		return err
+	}
+	return schema.ValidatePropertyConstraints()
+}
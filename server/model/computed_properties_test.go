@@ -0,0 +1,222 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func computedPropertiesTestBoard() *Board {
+	return &Board{
+		ID: "board-id",
+		CardProperties: []map[string]interface{}{
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+			{"id": "spent", "name": "Spent", "type": "number"},
+			{"id": "related", "name": "Related", "type": PropertyTypeRelation},
+			{"id": "remaining", "name": "Remaining", "type": PropertyTypeFormula, "formula": `prop("Estimate") - prop("Spent")`},
+			{"id": "status", "name": "Status", "type": PropertyTypeFormula, "formula": `if(prop("Remaining") > 0, "open", "done")`},
+			{"id": "subtasks", "name": "Subtasks", "type": PropertyTypeRollup, "rollup": map[string]interface{}{
+				"function": RollupFunctionCount,
+			}},
+			{"id": "subtaskEstimate", "name": "Subtask estimate", "type": PropertyTypeRollup, "rollup": map[string]interface{}{
+				"targetPropertyId": "estimate",
+				"function":         RollupFunctionSum,
+			}},
+			{"id": "relatedRemaining", "name": "Related remaining", "type": PropertyTypeRollup, "rollup": map[string]interface{}{
+				"relationPropertyId": "related",
+				"targetPropertyId":   "remaining",
+				"function":           RollupFunctionMax,
+			}},
+		},
+	}
+}
+
+func computedPropertiesTestCard(id, parentID string, properties map[string]interface{}) Block {
+	return Block{
+		ID:       id,
+		BoardID:  "board-id",
+		ParentID: parentID,
+		Type:     TypeCard,
+		Fields:   map[string]interface{}{"properties": properties},
+	}
+}
+
+func TestPropertyEvaluator(t *testing.T) {
+	board := computedPropertiesTestBoard()
+	schema, err := ParsePropertySchema(board)
+	require.NoError(t, err)
+	require.True(t, schema.HasComputedProperties())
+	require.NoError(t, schema.ValidateComputedProperties())
+
+	cards := []Block{
+		computedPropertiesTestCard("card1", "board-id", map[string]interface{}{"estimate": "8", "spent": "3", "related": []interface{}{"card2", "card3"}}),
+		computedPropertiesTestCard("card2", "board-id", map[string]interface{}{"estimate": "2", "spent": "2"}),
+		computedPropertiesTestCard("card3", "board-id", map[string]interface{}{"estimate": "10", "spent": "1"}),
+		computedPropertiesTestCard("child1", "card1", map[string]interface{}{"estimate": "3"}),
+		computedPropertiesTestCard("child2", "card1", map[string]interface{}{"estimate": "4.5"}),
+	}
+
+	evaluator := NewPropertyEvaluator(schema, cards, time.Now())
+
+	t.Run("formulas and rollups", func(t *testing.T) {
+		require.Equal(t, map[string]string{
+			"remaining":        "5",
+			"status":           "open",
+			"subtasks":         "2",
+			"subtaskEstimate":  "7.5",
+			"relatedRemaining": "9",
+		}, evaluator.ComputeCard("card1"))
+	})
+
+	t.Run("no related cards", func(t *testing.T) {
+		require.Equal(t, map[string]string{
+			"remaining":        "0",
+			"status":           "done",
+			"subtasks":         "0",
+			"subtaskEstimate":  "0",
+			"relatedRemaining": "",
+		}, evaluator.ComputeCard("card2"))
+	})
+
+	t.Run("unknown card", func(t *testing.T) {
+		require.Nil(t, evaluator.ComputeCard("unknown"))
+	})
+}
+
+func TestPropertyEvaluatorCycle(t *testing.T) {
+	board := &Board{
+		ID: "board-id",
+		CardProperties: []map[string]interface{}{
+			{"id": "a", "name": "A", "type": PropertyTypeFormula, "formula": `prop("B") + 1`},
+			{"id": "b", "name": "B", "type": PropertyTypeFormula, "formula": `prop("A") + 1`},
+			{"id": "c", "name": "C", "type": PropertyTypeFormula, "formula": `2 * 3`},
+		},
+	}
+	schema, err := ParsePropertySchema(board)
+	require.NoError(t, err)
+
+	cards := []Block{computedPropertiesTestCard("card1", "board-id", map[string]interface{}{})}
+	computed := NewPropertyEvaluator(schema, cards, time.Now()).ComputeCard("card1")
+	require.Equal(t, map[string]string{"a": "", "b": "", "c": "6"}, computed)
+}
+
+func TestDependentCardIDs(t *testing.T) {
+	schema, err := ParsePropertySchema(computedPropertiesTestBoard())
+	require.NoError(t, err)
+
+	cards := []Block{
+		computedPropertiesTestCard("card1", "board-id", map[string]interface{}{"related": []interface{}{"card2"}}),
+		computedPropertiesTestCard("card2", "board-id", map[string]interface{}{}),
+		computedPropertiesTestCard("card3", "board-id", map[string]interface{}{}),
+		computedPropertiesTestCard("child1", "card1", map[string]interface{}{}),
+	}
+	evaluator := NewPropertyEvaluator(schema, cards, time.Now())
+
+	t.Run("child card", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"child1", "card1"}, evaluator.DependentCardIDs(cards[3:4]))
+	})
+
+	t.Run("related card", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"card2", "card1"}, evaluator.DependentCardIDs(cards[1:2]))
+	})
+
+	t.Run("independent card", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"card3"}, evaluator.DependentCardIDs(cards[2:3]))
+	})
+
+	t.Run("deleted card", func(t *testing.T) {
+		deleted := computedPropertiesTestCard("child2", "card3", map[string]interface{}{})
+		require.ElementsMatch(t, []string{"card3"}, evaluator.DependentCardIDs([]Block{deleted}))
+	})
+}
+
+func TestCardChangeAffectsComputedProperties(t *testing.T) {
+	schema, err := ParsePropertySchema(computedPropertiesTestBoard())
+	require.NoError(t, err)
+
+	previous := computedPropertiesTestCard("card1", "board-id", map[string]interface{}{"estimate": "8", "other": "a"})
+
+	t.Run("property feeding a formula", func(t *testing.T) {
+		card := computedPropertiesTestCard("card1", "board-id", map[string]interface{}{"estimate": "9", "other": "a"})
+		require.True(t, schema.CardChangeAffectsComputedProperties(&previous, &card))
+	})
+
+	t.Run("other property", func(t *testing.T) {
+		card := computedPropertiesTestCard("card1", "board-id", map[string]interface{}{"estimate": "8", "other": "b"})
+		require.False(t, schema.CardChangeAffectsComputedProperties(&previous, &card))
+	})
+
+	t.Run("dropped computed value", func(t *testing.T) {
+		previous := computedPropertiesTestCard("card1", "board-id", map[string]interface{}{"estimate": "8", "remaining": "8"})
+		card := computedPropertiesTestCard("card1", "board-id", map[string]interface{}{"estimate": "8"})
+		require.True(t, schema.CardChangeAffectsComputedProperties(&previous, &card))
+	})
+
+	t.Run("parent", func(t *testing.T) {
+		card := computedPropertiesTestCard("card1", "card2", map[string]interface{}{"estimate": "8", "other": "a"})
+		require.True(t, schema.CardChangeAffectsComputedProperties(&previous, &card))
+	})
+
+	t.Run("added and deleted cards", func(t *testing.T) {
+		require.True(t, schema.CardChangeAffectsComputedProperties(nil, &previous))
+		require.True(t, schema.CardChangeAffectsComputedProperties(&previous, nil))
+	})
+}
+
+func TestValidateCardProperties(t *testing.T) {
+	board := computedPropertiesTestBoard()
+
+	t.Run("valid formula", func(t *testing.T) {
+		patch := &BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "double", "name": "Double", "type": PropertyTypeFormula, "formula": `prop("Estimate") * 2`},
+			},
+		}
+		require.NoError(t, patch.ValidateCardProperties(board))
+	})
+
+	t.Run("invalid formula", func(t *testing.T) {
+		patch := &BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "double", "name": "Double", "type": PropertyTypeFormula, "formula": `prop("Estimate") *`},
+			},
+		}
+		require.ErrorIs(t, patch.ValidateCardProperties(board), ErrInvalidFormula)
+	})
+
+	t.Run("formula referencing a deleted property", func(t *testing.T) {
+		patch := &BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "double", "name": "Double", "type": PropertyTypeFormula, "formula": `prop("Spent") * 2`},
+			},
+			DeletedCardProperties: []string{"spent"},
+		}
+		require.ErrorIs(t, patch.ValidateCardProperties(board), ErrFormulaUnknownProp)
+	})
+
+	t.Run("rollup with an unknown function", func(t *testing.T) {
+		patch := &BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "total", "name": "Total", "type": PropertyTypeRollup, "rollup": map[string]interface{}{
+					"targetPropertyId": "estimate",
+					"function":         "median",
+				}},
+			},
+		}
+		require.ErrorIs(t, patch.ValidateCardProperties(board), ErrInvalidRollup)
+	})
+
+	t.Run("rollup over a non relation property", func(t *testing.T) {
+		patch := &BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "total", "name": "Total", "type": PropertyTypeRollup, "rollup": map[string]interface{}{
+					"relationPropertyId": "estimate",
+					"targetPropertyId":   "estimate",
+					"function":           RollupFunctionSum,
+				}},
+			},
+		}
+		require.ErrorIs(t, patch.ValidateCardProperties(board), ErrInvalidRollup)
+	})
+}
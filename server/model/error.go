@@ -79,3 +79,126 @@ func IsErrNotAllFound(err error) bool {
 	var na *ErrNotAllFound
 	return errors.As(err, &na)
 }
+
+// ErrBlockConflict is an error type that can be returned by store APIs
+// when a block patch expects a version of the block that is not the
+// current one.
+type ErrBlockConflict struct {
+	BlockID          string
+	ExpectedUpdateAt int64
+	UpdateAt         int64
+}
+
+func NewErrBlockConflict(blockID string, expectedUpdateAt, updateAt int64) *ErrBlockConflict {
+	return &ErrBlockConflict{
+		BlockID:          blockID,
+		ExpectedUpdateAt: expectedUpdateAt,
+		UpdateAt:         updateAt,
+	}
+}
+
+func (bc *ErrBlockConflict) Error() string {
+	return fmt.Sprintf("block {%s} has been modified (expected update_at %d, found %d)", bc.BlockID, bc.ExpectedUpdateAt, bc.UpdateAt)
+}
+
+// IsErrBlockConflict returns true if `err` is or wraps a ErrBlockConflict.
+func IsErrBlockConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var bc *ErrBlockConflict
+	return errors.As(err, &bc)
+}
+
+// ErrBoardConflict is an error type that can be returned by store APIs
+// when a board patch expects a version of the board that is not the
+// current one.
+type ErrBoardConflict struct {
+	BoardID          string
+	ExpectedUpdateAt int64
+	UpdateAt         int64
+}
+
+func NewErrBoardConflict(boardID string, expectedUpdateAt, updateAt int64) *ErrBoardConflict {
+	return &ErrBoardConflict{
+		BoardID:          boardID,
+		ExpectedUpdateAt: expectedUpdateAt,
+		UpdateAt:         updateAt,
+	}
+}
+
+func (bc *ErrBoardConflict) Error() string {
+	return fmt.Sprintf("board {%s} has been modified (expected update_at %d, found %d)", bc.BoardID, bc.ExpectedUpdateAt, bc.UpdateAt)
+}
+
+// IsErrBoardConflict returns true if `err` is or wraps a ErrBoardConflict.
+func IsErrBoardConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var bc *ErrBoardConflict
+	return errors.As(err, &bc)
+}
+
+// ErrWIPLimitExceeded is an error type that can be returned when a change
+// moves more cards into a board column than its WIP limit allows.
+type ErrWIPLimitExceeded struct {
+	Limits []*WIPLimitStatus
+}
+
+func NewErrWIPLimitExceeded(limits []*WIPLimitStatus) *ErrWIPLimitExceeded {
+	return &ErrWIPLimitExceeded{
+		Limits: limits,
+	}
+}
+
+func (wl *ErrWIPLimitExceeded) Error() string {
+	columns := make([]string, 0, len(wl.Limits))
+	for _, limit := range wl.Limits {
+		columns = append(columns, fmt.Sprintf("{%s} has %d cards, limit is %d", limit.OptionID, limit.Count, limit.Limit))
+	}
+	return "WIP limit exceeded: " + strings.Join(columns, ", ")
+}
+
+// IsErrWIPLimitExceeded returns true if `err` is or wraps a ErrWIPLimitExceeded.
+func IsErrWIPLimitExceeded(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var wl *ErrWIPLimitExceeded
+	return errors.As(err, &wl)
+}
+
+// ErrInvalidCardProperties is an error type that can be returned when a
+// change stores card property values that don't satisfy the board's card
+// properties.
+type ErrInvalidCardProperties struct {
+	Errors []*PropertyValidationError
+}
+
+func NewErrInvalidCardProperties(errs []*PropertyValidationError) *ErrInvalidCardProperties {
+	return &ErrInvalidCardProperties{
+		Errors: errs,
+	}
+}
+
+func (ip *ErrInvalidCardProperties) Error() string {
+	invalid := make([]string, 0, len(ip.Errors))
+	for _, e := range ip.Errors {
+		invalid = append(invalid, fmt.Sprintf("{%s} of card {%s}: %s", e.PropertyID, e.CardID, e.Reason))
+	}
+	return "invalid card properties: " + strings.Join(invalid, ", ")
+}
+
+// IsErrInvalidCardProperties returns true if `err` is or wraps a ErrInvalidCardProperties.
+func IsErrInvalidCardProperties(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var ip *ErrInvalidCardProperties
+	return errors.As(err, &ip)
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+)
+
+var (
+	ErrInvalidFeedToken    = errors.New("invalid feed token")
+	ErrFeedPropertyNotDate = errors.New("the property of the feed isn't a date property")
+)
+
+// FeedToken is the secret token authenticating the calendar feeds of a
+// user. Generating a new token revokes the previous one
+// swagger:model
+type FeedToken struct {
+	// The ID of the user
+	// required: true
+	UserID string `json:"userId"`
+
+	// The token, passed to the feeds as the token query parameter
+	// required: true
+	Token string `json:"token"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+}
+
+// CardDate is the value of a date property of a card. The dates without
+// time are stored at noon UTC.
+type CardDate struct {
+	From        int64 `json:"from"`
+	To          int64 `json:"to,omitempty"`
+	IncludeTime bool  `json:"includeTime,omitempty"`
+}
+
+// GetCardDate returns the value of a date property of a card, or nil if
+// the card has no date.
+func GetCardDate(card *Block, propertyID string) *CardDate {
+	properties, ok := card.Fields["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	value, ok := properties[propertyID].(string)
+	if !ok || value == "" {
+		return nil
+	}
+
+	var date CardDate
+	if err := json.Unmarshal([]byte(value), &date); err != nil || date.From == 0 {
+		return nil
+	}
+	if date.To < date.From {
+		date.To = 0
+	}
+	return &date
+}
+
+// GetCardAssignees returns the IDs of the users assigned to a card by its
+// person and multiPerson properties, in the order of the properties.
+func GetCardAssignees(card *Block, schema PropSchema) []string {
+	properties, ok := card.Fields["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	personProperties := []PropDef{}
+	for _, pd := range schema {
+		if pd.Type == "person" || pd.Type == "multiPerson" {
+			personProperties = append(personProperties, pd)
+		}
+	}
+	sort.Slice(personProperties, func(i, j int) bool {
+		return personProperties[i].Index < personProperties[j].Index
+	})
+
+	assignees := []string{}
+	seen := map[string]bool{}
+	add := func(v interface{}) {
+		if userID, ok := v.(string); ok && userID != "" && !seen[userID] {
+			seen[userID] = true
+			assignees = append(assignees, userID)
+		}
+	}
+	for _, pd := range personProperties {
+		switch value := properties[pd.ID].(type) {
+		case string:
+			add(value)
+		case []interface{}:
+			for _, v := range value {
+				add(v)
+			}
+		}
+	}
+	return assignees
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCardDate(t *testing.T) {
+	card := func(value interface{}) *Block {
+		return &Block{Fields: map[string]interface{}{"properties": map[string]interface{}{"due": value}}}
+	}
+
+	require.Equal(t, &CardDate{From: 100}, GetCardDate(card(`{"from":100}`), "due"))
+	require.Equal(t, &CardDate{From: 100, To: 200, IncludeTime: true}, GetCardDate(card(`{"from":100,"to":200,"includeTime":true}`), "due"))
+	// a range ending before its start is a single date
+	require.Equal(t, &CardDate{From: 100}, GetCardDate(card(`{"from":100,"to":50}`), "due"))
+
+	require.Nil(t, GetCardDate(card(`{"to":200}`), "due"))
+	require.Nil(t, GetCardDate(card("not a date"), "due"))
+	require.Nil(t, GetCardDate(card(""), "due"))
+	require.Nil(t, GetCardDate(card(100), "due"))
+	require.Nil(t, GetCardDate(card(`{"from":100}`), "other"))
+	require.Nil(t, GetCardDate(&Block{}, "due"))
+}
+
+func TestGetCardAssignees(t *testing.T) {
+	schema := PropSchema{
+		"reviewers": {ID: "reviewers", Index: 2, Type: "multiPerson"},
+		"owner":     {ID: "owner", Index: 0, Type: "person"},
+		"status":    {ID: "status", Index: 1, Type: "select"},
+	}
+	card := func(properties map[string]interface{}) *Block {
+		return &Block{Fields: map[string]interface{}{"properties": properties}}
+	}
+
+	t.Run("in the order of the properties, without duplicates", func(t *testing.T) {
+		assignees := GetCardAssignees(card(map[string]interface{}{
+			"reviewers": []interface{}{"user-2", "user-1", "user-3"},
+			"owner":     "user-1",
+			"status":    "user-4",
+		}), schema)
+		require.Equal(t, []string{"user-1", "user-2", "user-3"}, assignees)
+	})
+
+	t.Run("empty values", func(t *testing.T) {
+		assignees := GetCardAssignees(card(map[string]interface{}{
+			"reviewers": []interface{}{},
+			"owner":     "",
+		}), schema)
+		require.Empty(t, assignees)
+	})
+
+	t.Run("no properties", func(t *testing.T) {
+		require.Empty(t, GetCardAssignees(&Block{}, schema))
+	})
+}
@@ -0,0 +1,63 @@
+package model
+
+import "errors"
+
+const (
+	FileScanStatusPending  = "pending"
+	FileScanStatusClean    = "clean"
+	FileScanStatusInfected = "infected"
+	FileScanStatusFailed   = "failed"
+)
+
+var (
+	ErrFileQuarantined = errors.New("the file is quarantined until it is scanned")
+	ErrFileInfected    = errors.New("the file is infected")
+)
+
+// FileScan is the malware scan of a file uploaded in a board. The file
+// can't be downloaded until the scan finds it clean
+// swagger:model
+type FileScan struct {
+	// The name of the file, as referenced by the blocks
+	// required: true
+	FileID string `json:"fileId"`
+
+	// The ID of the board of the file
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the team of the board
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The ID of the user that uploaded the file
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The status of the scan: pending, clean, infected or failed
+	// required: true
+	Status string `json:"status"`
+
+	// The name of the malware found, or the error of a failed scan
+	// required: false
+	Result string `json:"result,omitempty"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last update time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// CheckDownload returns an error if the file can't be downloaded.
+func (s *FileScan) CheckDownload() error {
+	switch s.Status {
+	case FileScanStatusClean:
+		return nil
+	case FileScanStatusInfected:
+		return ErrFileInfected
+	}
+	return ErrFileQuarantined
+}
@@ -0,0 +1,813 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	ErrInvalidFormula       = errors.New("invalid formula")
+	ErrFormulaEvaluation    = errors.New("formula evaluation error")
+	ErrFormulaCycle         = errors.New("formula has a circular dependency")
+	ErrFormulaUnknownProp   = errors.New("formula references an unknown property")
+	ErrFormulaTypeMismatch  = errors.New("formula type mismatch")
+	ErrFormulaUnknownFunc   = errors.New("formula uses an unknown function")
+	ErrFormulaDivideByZero  = errors.New("formula divides by zero")
+	ErrFormulaInvalidLength = errors.New("formula too long")
+)
+
+// FormulaMaxLength is the maximum length of a formula expression.
+const FormulaMaxLength = 2000
+
+// formulaContext provides the values a formula can reference.
+type formulaContext interface {
+	propertyValue(name string) (interface{}, error)
+	now() time.Time
+}
+
+// formulaNode is a node of a parsed formula. Values are nil, float64,
+// string, bool or time.Time.
+type formulaNode interface {
+	eval(ctx formulaContext) (interface{}, error)
+}
+
+// Formula is a parsed formula expression.
+type Formula struct {
+	root       formulaNode
+	properties []string
+}
+
+// ParseFormula parses a formula expression. Properties are referenced by
+// name or ID with prop("Name").
+func ParseFormula(expression string) (*Formula, error) {
+	if len(expression) > FormulaMaxLength {
+		return nil, ErrFormulaInvalidLength
+	}
+
+	tokens, err := tokenizeFormula(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &formulaParser{tokens: tokens}
+	root, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != formulaTokenEOF {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidFormula, p.peek().text)
+	}
+
+	return &Formula{root: root, properties: p.properties}, nil
+}
+
+// Properties returns the names of the properties referenced by the formula.
+func (f *Formula) Properties() []string {
+	return f.properties
+}
+
+func (f *Formula) eval(ctx formulaContext) (interface{}, error) {
+	return f.root.eval(ctx)
+}
+
+// tokenizer
+
+type formulaTokenKind int
+
+const (
+	formulaTokenEOF formulaTokenKind = iota
+	formulaTokenNumber
+	formulaTokenString
+	formulaTokenIdent
+	formulaTokenOperator
+)
+
+type formulaToken struct {
+	kind formulaTokenKind
+	text string
+}
+
+var formulaOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "+", "-", "*", "/", "%", "<", ">", "!", "(", ")", ","}
+
+func tokenizeFormula(expression string) ([]formulaToken, error) {
+	var tokens []formulaToken
+	runes := []rune(expression)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, formulaToken{kind: formulaTokenNumber, text: string(runes[start:i])})
+
+		case r == '"':
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("%w: unterminated string", ErrInvalidFormula)
+			}
+			tokens = append(tokens, formulaToken{kind: formulaTokenString, text: sb.String()})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, formulaToken{kind: formulaTokenIdent, text: string(runes[start:i])})
+
+		default:
+			matched := false
+			for _, op := range formulaOperators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, formulaToken{kind: formulaTokenOperator, text: op})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("%w: unexpected character %q", ErrInvalidFormula, r)
+			}
+		}
+	}
+
+	return append(tokens, formulaToken{kind: formulaTokenEOF}), nil
+}
+
+// parser
+
+type formulaParser struct {
+	tokens     []formulaToken
+	pos        int
+	properties []string
+}
+
+func (p *formulaParser) peek() formulaToken {
+	return p.tokens[p.pos]
+}
+
+func (p *formulaParser) next() formulaToken {
+	token := p.tokens[p.pos]
+	if token.kind != formulaTokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+func (p *formulaParser) acceptOperator(ops ...string) (string, bool) {
+	token := p.peek()
+	isKeyword := token.kind == formulaTokenIdent
+	if token.kind != formulaTokenOperator && !isKeyword {
+		return "", false
+	}
+	for _, op := range ops {
+		if token.text == op || (isKeyword && strings.EqualFold(token.text, op)) {
+			p.next()
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *formulaParser) expectOperator(op string) error {
+	if _, ok := p.acceptOperator(op); !ok {
+		return fmt.Errorf("%w: expected %q", ErrInvalidFormula, op)
+	}
+	return nil
+}
+
+func (p *formulaParser) parseExpression() (formulaNode, error) {
+	return p.parseBinary(0)
+}
+
+var formulaPrecedence = [][]string{
+	{"||", "or"},
+	{"&&", "and"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *formulaParser) parseBinary(level int) (formulaNode, error) {
+	if level == len(formulaPrecedence) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.acceptOperator(formulaPrecedence[level]...)
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &formulaBinaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *formulaParser) parseUnary() (formulaNode, error) {
+	if op, ok := p.acceptOperator("-", "!", "not"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &formulaUnaryNode{op: op, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *formulaParser) parsePrimary() (formulaNode, error) {
+	token := p.next()
+
+	switch token.kind {
+	case formulaTokenNumber:
+		value, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid number %q", ErrInvalidFormula, token.text)
+		}
+		return &formulaLiteralNode{value: value}, nil
+
+	case formulaTokenString:
+		return &formulaLiteralNode{value: token.text}, nil
+
+	case formulaTokenOperator:
+		if token.text != "(" {
+			return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidFormula, token.text)
+		}
+		node, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOperator(")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+
+	case formulaTokenIdent:
+		switch strings.ToLower(token.text) {
+		case "true":
+			return &formulaLiteralNode{value: true}, nil
+		case "false":
+			return &formulaLiteralNode{value: false}, nil
+		}
+		return p.parseCall(token.text)
+	}
+
+	return nil, fmt.Errorf("%w: unexpected end of formula", ErrInvalidFormula)
+}
+
+func (p *formulaParser) parseCall(name string) (formulaNode, error) {
+	if err := p.expectOperator("("); err != nil {
+		return nil, err
+	}
+
+	var args []formulaNode
+	if _, ok := p.acceptOperator(")"); !ok {
+		for {
+			arg, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.acceptOperator(","); ok {
+				continue
+			}
+			if err := p.expectOperator(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	name = strings.ToLower(name)
+	if name == "prop" {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%w: prop expects a property name", ErrInvalidFormula)
+		}
+		literal, ok := args[0].(*formulaLiteralNode)
+		if !ok {
+			return nil, fmt.Errorf("%w: prop expects a property name", ErrInvalidFormula)
+		}
+		propName, ok := literal.value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: prop expects a property name", ErrInvalidFormula)
+		}
+		p.properties = append(p.properties, propName)
+		return &formulaPropNode{name: propName}, nil
+	}
+
+	fn, ok := formulaFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFormulaUnknownFunc, name)
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("%w: wrong number of arguments for %s", ErrInvalidFormula, name)
+	}
+
+	return &formulaCallNode{name: name, fn: fn, args: args}, nil
+}
+
+// nodes
+
+type formulaLiteralNode struct {
+	value interface{}
+}
+
+func (n *formulaLiteralNode) eval(formulaContext) (interface{}, error) {
+	return n.value, nil
+}
+
+type formulaPropNode struct {
+	name string
+}
+
+func (n *formulaPropNode) eval(ctx formulaContext) (interface{}, error) {
+	return ctx.propertyValue(n.name)
+}
+
+type formulaUnaryNode struct {
+	op      string
+	operand formulaNode
+}
+
+func (n *formulaUnaryNode) eval(ctx formulaContext) (interface{}, error) {
+	value, err := n.operand.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "-" {
+		if value == nil {
+			return nil, nil
+		}
+		number, err := formulaNumber(value)
+		if err != nil {
+			return nil, err
+		}
+		return -number, nil
+	}
+	return !formulaTruthy(value), nil
+}
+
+type formulaBinaryNode struct {
+	op    string
+	left  formulaNode
+	right formulaNode
+}
+
+func (n *formulaBinaryNode) eval(ctx formulaContext) (interface{}, error) {
+	left, err := n.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// logical operators short circuit
+	switch n.op {
+	case "||", "or":
+		if formulaTruthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return formulaTruthy(right), nil
+	case "&&", "and":
+		if !formulaTruthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return formulaTruthy(right), nil
+	}
+
+	right, err := n.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return formulaEqual(left, right), nil
+	case "!=":
+		return !formulaEqual(left, right), nil
+	case "<", "<=", ">", ">=":
+		return formulaCompare(n.op, left, right)
+	case "+":
+		return formulaAdd(left, right)
+	}
+
+	// arithmetic on empty values results in an empty value
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	a, err := formulaNumber(left)
+	if err != nil {
+		return nil, err
+	}
+	b, err := formulaNumber(right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, ErrFormulaDivideByZero
+		}
+		return a / b, nil
+	case "%":
+		if b == 0 {
+			return nil, ErrFormulaDivideByZero
+		}
+		return math.Mod(a, b), nil
+	}
+	return nil, fmt.Errorf("%w: unknown operator %s", ErrFormulaEvaluation, n.op)
+}
+
+type formulaCallNode struct {
+	name string
+	fn   formulaFunction
+	args []formulaNode
+}
+
+func (n *formulaCallNode) eval(ctx formulaContext) (interface{}, error) {
+	// if only evaluates the branch it returns
+	if n.name == "if" {
+		condition, err := n.args[0].eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if formulaTruthy(condition) {
+			return n.args[1].eval(ctx)
+		}
+		if len(n.args) > 2 {
+			return n.args[2].eval(ctx)
+		}
+		return nil, nil
+	}
+
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	return n.fn.call(ctx, args)
+}
+
+// functions
+
+type formulaFunction struct {
+	minArgs int
+	maxArgs int // -1 means unlimited
+	call    func(ctx formulaContext, args []interface{}) (interface{}, error)
+}
+
+var formulaFunctions = map[string]formulaFunction{
+	"if": {minArgs: 2, maxArgs: 3},
+	"empty": {minArgs: 1, maxArgs: 1, call: func(_ formulaContext, args []interface{}) (interface{}, error) {
+		return args[0] == nil || args[0] == "", nil
+	}},
+	"abs":   {minArgs: 1, maxArgs: 1, call: formulaMathFunction(math.Abs)},
+	"floor": {minArgs: 1, maxArgs: 1, call: formulaMathFunction(math.Floor)},
+	"ceil":  {minArgs: 1, maxArgs: 1, call: formulaMathFunction(math.Ceil)},
+	"sqrt":  {minArgs: 1, maxArgs: 1, call: formulaMathFunction(math.Sqrt)},
+	"round": {minArgs: 1, maxArgs: 2, call: func(_ formulaContext, args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		number, err := formulaNumber(args[0])
+		if err != nil {
+			return nil, err
+		}
+		digits := 0.0
+		if len(args) > 1 {
+			if digits, err = formulaNumber(args[1]); err != nil {
+				return nil, err
+			}
+		}
+		factor := math.Pow(10, math.Trunc(digits))
+		return math.Round(number*factor) / factor, nil
+	}},
+	"min": {minArgs: 1, maxArgs: -1, call: func(_ formulaContext, args []interface{}) (interface{}, error) {
+		return formulaAggregate(args, math.Min)
+	}},
+	"max": {minArgs: 1, maxArgs: -1, call: func(_ formulaContext, args []interface{}) (interface{}, error) {
+		return formulaAggregate(args, math.Max)
+	}},
+	"tonumber": {minArgs: 1, maxArgs: 1, call: func(_ formulaContext, args []interface{}) (interface{}, error) {
+		if args[0] == nil || args[0] == "" {
+			return nil, nil
+		}
+		return formulaNumber(args[0])
+	}},
+	"format": {minArgs: 1, maxArgs: 1, call: func(_ formulaContext, args []interface{}) (interface{}, error) {
+		return FormatFormulaValue(args[0]), nil
+	}},
+	"concat": {minArgs: 1, maxArgs: -1, call: func(_ formulaContext, args []interface{}) (interface{}, error) {
+		var sb strings.Builder
+		for _, arg := range args {
+			sb.WriteString(FormatFormulaValue(arg))
+		}
+		return sb.String(), nil
+	}},
+	"length": {minArgs: 1, maxArgs: 1, call: func(_ formulaContext, args []interface{}) (interface{}, error) {
+		return float64(len([]rune(FormatFormulaValue(args[0])))), nil
+	}},
+	"contains": {minArgs: 2, maxArgs: 2, call: func(_ formulaContext, args []interface{}) (interface{}, error) {
+		return strings.Contains(strings.ToLower(FormatFormulaValue(args[0])), strings.ToLower(FormatFormulaValue(args[1]))), nil
+	}},
+	"now": {minArgs: 0, maxArgs: 0, call: func(ctx formulaContext, _ []interface{}) (interface{}, error) {
+		return ctx.now(), nil
+	}},
+	"dateadd": {minArgs: 3, maxArgs: 3, call: func(_ formulaContext, args []interface{}) (interface{}, error) {
+		return formulaDateAdd(args, 1)
+	}},
+	"datesubtract": {minArgs: 3, maxArgs: 3, call: func(_ formulaContext, args []interface{}) (interface{}, error) {
+		return formulaDateAdd(args, -1)
+	}},
+	"datebetween": {minArgs: 3, maxArgs: 3, call: func(_ formulaContext, args []interface{}) (interface{}, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+		end, ok := args[0].(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("%w: dateBetween expects dates", ErrFormulaTypeMismatch)
+		}
+		start, ok := args[1].(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("%w: dateBetween expects dates", ErrFormulaTypeMismatch)
+		}
+		unit, err := formulaDateUnit(args[2])
+		if err != nil {
+			return nil, err
+		}
+		return math.Trunc(float64(end.Sub(start)) / float64(unit)), nil
+	}},
+}
+
+// formulaDateUnits holds the duration of the supported date units. Months
+// and years are handled separately when adding to dates.
+var formulaDateUnits = map[string]time.Duration{
+	"minutes": time.Minute,
+	"hours":   time.Hour,
+	"days":    24 * time.Hour,
+	"weeks":   7 * 24 * time.Hour,
+	"months":  30 * 24 * time.Hour,
+	"years":   365 * 24 * time.Hour,
+}
+
+func formulaDateUnit(value interface{}) (time.Duration, error) {
+	name, ok := value.(string)
+	if !ok {
+		return 0, fmt.Errorf("%w: invalid date unit", ErrFormulaTypeMismatch)
+	}
+	unit, ok := formulaDateUnits[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("%w: invalid date unit %q", ErrFormulaEvaluation, name)
+	}
+	return unit, nil
+}
+
+func formulaDateAdd(args []interface{}, sign int) (interface{}, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	date, ok := args[0].(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected a date", ErrFormulaTypeMismatch)
+	}
+	amount, err := formulaNumber(args[1])
+	if err != nil {
+		return nil, err
+	}
+	unit, err := formulaDateUnit(args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	count := sign * int(amount)
+	switch strings.ToLower(args[2].(string)) {
+	case "months":
+		return date.AddDate(0, count, 0), nil
+	case "years":
+		return date.AddDate(count, 0, 0), nil
+	}
+	return date.Add(time.Duration(count) * unit), nil
+}
+
+func formulaMathFunction(fn func(float64) float64) func(formulaContext, []interface{}) (interface{}, error) {
+	return func(_ formulaContext, args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		number, err := formulaNumber(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return fn(number), nil
+	}
+}
+
+func formulaAggregate(args []interface{}, fn func(float64, float64) float64) (interface{}, error) {
+	var result interface{}
+	for _, arg := range args {
+		if arg == nil {
+			continue
+		}
+		number, err := formulaNumber(arg)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = number
+			continue
+		}
+		result = fn(result.(float64), number)
+	}
+	return result, nil
+}
+
+// value helpers
+
+func formulaNumber(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q is not a number", ErrFormulaTypeMismatch, v)
+		}
+		return number, nil
+	case nil:
+		return 0, nil
+	}
+	return 0, fmt.Errorf("%w: expected a number", ErrFormulaTypeMismatch)
+}
+
+func formulaTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	}
+	return true
+}
+
+func formulaEqual(left, right interface{}) bool {
+	if l, ok := left.(time.Time); ok {
+		r, ok := right.(time.Time)
+		return ok && l.Equal(r)
+	}
+	if l, ok := left.(float64); ok {
+		r, err := formulaNumber(right)
+		return err == nil && right != nil && l == r
+	}
+	return left == right
+}
+
+func formulaCompare(op string, left, right interface{}) (interface{}, error) {
+	if left == nil || right == nil {
+		return false, nil
+	}
+
+	var cmp int
+	switch l := left.(type) {
+	case time.Time:
+		r, ok := right.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot compare a date", ErrFormulaTypeMismatch)
+		}
+		switch {
+		case l.Before(r):
+			cmp = -1
+		case l.After(r):
+			cmp = 1
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot compare a text", ErrFormulaTypeMismatch)
+		}
+		cmp = strings.Compare(l, r)
+	default:
+		a, err := formulaNumber(left)
+		if err != nil {
+			return nil, err
+		}
+		b, err := formulaNumber(right)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	}
+	return cmp >= 0, nil
+}
+
+func formulaAdd(left, right interface{}) (interface{}, error) {
+	_, leftIsString := left.(string)
+	_, rightIsString := right.(string)
+	if leftIsString || rightIsString {
+		return FormatFormulaValue(left) + FormatFormulaValue(right), nil
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	a, err := formulaNumber(left)
+	if err != nil {
+		return nil, err
+	}
+	b, err := formulaNumber(right)
+	if err != nil {
+		return nil, err
+	}
+	return a + b, nil
+}
+
+// FormatFormulaValue formats the result of a formula the way it is stored
+// in the card properties.
+func FormatFormulaValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format("January 02, 2006")
+	case string:
+		return v
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testFormulaContext struct {
+	values map[string]interface{}
+	clock  time.Time
+}
+
+func (c *testFormulaContext) propertyValue(name string) (interface{}, error) {
+	value, ok := c.values[name]
+	if !ok {
+		return nil, ErrFormulaUnknownProp
+	}
+	return value, nil
+}
+
+func (c *testFormulaContext) now() time.Time {
+	return c.clock
+}
+
+func TestFormula(t *testing.T) {
+	ctx := &testFormulaContext{
+		values: map[string]interface{}{
+			"Estimate": 5.0,
+			"Spent":    3.0,
+			"Status":   "Done",
+			"Empty":    nil,
+			"Due":      time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC),
+			"Start":    time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		clock: time.Date(2022, 3, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   string
+	}{
+		{"arithmetic precedence", `1 + 2 * 3`, "7"},
+		{"parentheses", `(1 + 2) * 3`, "9"},
+		{"unary minus", `-prop("Estimate") + 1`, "-4"},
+		{"properties", `prop("Estimate") - prop("Spent")`, "2"},
+		{"modulo", `7 % 4`, "3"},
+		{"division", `prop("Spent") / 2`, "1.5"},
+		{"string concatenation", `prop("Status") + " (" + prop("Estimate") + ")"`, "Done (5)"},
+		{"comparison", `prop("Estimate") > prop("Spent")`, "true"},
+		{"equality", `prop("Status") == "Done"`, "true"},
+		{"logical operators", `prop("Estimate") > 1 and not (prop("Spent") > 5)`, "true"},
+		{"conditional", `if(prop("Status") == "Done", "finished", "pending")`, "finished"},
+		{"conditional without else", `if(false, 1)`, ""},
+		{"empty", `empty(prop("Empty"))`, "true"},
+		{"arithmetic on empty values", `prop("Empty") * 2`, ""},
+		{"round", `round(10 / 3, 2)`, "3.33"},
+		{"min and max", `max(1, prop("Estimate"), 3) - min(4, 2)`, "3"},
+		{"concat and length", `length(concat("ab", 12))`, "4"},
+		{"contains", `contains(prop("Status"), "don")`, "true"},
+		{"date between", `dateBetween(prop("Due"), prop("Start"), "days")`, "9"},
+		{"date add", `dateAdd(prop("Start"), 2, "weeks")`, "March 15, 2022"},
+		{"date subtract months", `dateSubtract(prop("Due"), 1, "months")`, "February 10, 2022"},
+		{"now", `dateBetween(prop("Due"), now(), "days")`, "5"},
+		{"overdue", `if(now() > prop("Due"), "late", "on time")`, "on time"},
+		{"case insensitive keywords", `TRUE && False`, "false"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formula, err := ParseFormula(tc.expression)
+			require.NoError(t, err)
+
+			value, err := formula.eval(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, FormatFormulaValue(value))
+		})
+	}
+
+	t.Run("division by zero", func(t *testing.T) {
+		formula, err := ParseFormula(`prop("Estimate") / 0`)
+		require.NoError(t, err)
+
+		_, err = formula.eval(ctx)
+		require.ErrorIs(t, err, ErrFormulaDivideByZero)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		formula, err := ParseFormula(`prop("Status") * 2`)
+		require.NoError(t, err)
+
+		_, err = formula.eval(ctx)
+		require.ErrorIs(t, err, ErrFormulaTypeMismatch)
+	})
+}
+
+func TestParseFormula(t *testing.T) {
+	t.Run("referenced properties", func(t *testing.T) {
+		formula, err := ParseFormula(`prop("Estimate") * 2 + prop("Spent")`)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Estimate", "Spent"}, formula.Properties())
+	})
+
+	invalid := []string{
+		``,
+		`1 +`,
+		`(1 + 2`,
+		`"unterminated`,
+		`unknown(1)`,
+		`prop(1)`,
+		`prop("a", "b")`,
+		`if(true)`,
+		`1 2`,
+		`1 # 2`,
+		`Estimate + 1`,
+	}
+	for _, expression := range invalid {
+		_, err := ParseFormula(expression)
+		require.Error(t, err, expression)
+	}
+}
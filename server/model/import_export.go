@@ -38,14 +38,105 @@ type ExportArchiveOptions struct {
 	// BoardIDs is the list of boards to include in the archive.
 	// Empty slice means export all boards from workspace/team.
 	BoardIDs []string
+
+	// TemplateInfo maps template board IDs to the template metadata
+	// written alongside them in the archive.
+	TemplateInfo map[string]*TemplateArchiveInfo
+
+	// ModifiedSince, if non-zero, only exports the blocks updated since
+	// then, in milliseconds since the epoch. Boards with no such blocks
+	// and not updated themselves are left out of the archive.
+	ModifiedSince int64
+
+	// ExcludeComments leaves the comments of the cards out of the archive.
+	ExcludeComments bool
+
+	// Files sets how the files attached to blocks are exported. The
+	// default is to include them in the archive.
+	Files ExportFilesMode
+
+	// IncludeDeleted adds the blocks deleted since ModifiedSince, or ever
+	// if it is zero, as block_tombstone lines.
+	IncludeDeleted bool
+
+	// IncludeMembers adds the members of the boards as board_member lines.
+	IncludeMembers bool
+
+	// IncludeHistory adds the history of the boards and of their blocks as
+	// board_history and block_history lines.
+	//
+	// The lines added by IncludeDeleted, IncludeMembers and IncludeHistory
+	// describe the exported boards, and are not imported: importing an
+	// archive creates new boards, with new IDs and the importing user as
+	// their only member. They are reported as skipped lines instead.
+	IncludeHistory bool
 }
 
+// ExportFilesMode sets how the files attached to blocks are exported.
+type ExportFilesMode string
+
+const (
+	// ExportFilesInclude writes the files in the archive.
+	ExportFilesInclude ExportFilesMode = "include"
+
+	// ExportFilesExclude leaves out the files and the blocks they are
+	// attached to.
+	ExportFilesExclude ExportFilesMode = "exclude"
+
+	// ExportFilesReference keeps the blocks the files are attached to, but
+	// not the content of the files.
+	ExportFilesReference ExportFilesMode = "reference"
+)
+
+var ErrInvalidExportFilesMode = errors.New("invalid export files mode, must be include, exclude or reference")
+
+// ExportFilesModeFromString returns the files mode for a string. An empty
+// string is the default mode.
+func ExportFilesModeFromString(s string) (ExportFilesMode, error) {
+	switch mode := ExportFilesMode(s); mode {
+	case "", ExportFilesInclude:
+		return ExportFilesInclude, nil
+	case ExportFilesExclude, ExportFilesReference:
+		return mode, nil
+	}
+	return "", ErrInvalidExportFilesMode
+}
+
+// BoardImportedCallback is called for each board created by an archive
+// import, along with the template metadata found for it in the archive, if
+// any.
+type BoardImportedCallback func(boardID string, templateInfo *TemplateArchiveInfo) error
+
 // ImportArchiveOptions provides options when importing an archive.
 type ImportArchiveOptions struct {
 	TeamID        string
 	ModifiedBy    string
 	BoardModifier BoardModifier
 	BlockModifier BlockModifier
+	BoardImported BoardImportedCallback
+
+	// DryRun validates the archive without importing anything.
+	DryRun bool
+
+	// ImportedBoards maps the directories of the archive to the boards
+	// already imported from them, which are skipped. It is used to resume
+	// an import.
+	ImportedBoards map[string]string
+
+	// ImportJob is the job running the import, if any. The boards it
+	// imports are added to its ImportedBoards in the transaction creating
+	// them, so that they are skipped if the job is resumed after a crash.
+	ImportJob *ImportJob
+
+	// BoardProcessed is called once each board of the archive is committed,
+	// or validated in a dry run.
+	BoardProcessed func(dir string, boardID string) error
+
+	// LineSkipped is called for each line of the archive with an unsupported
+	// type. If nil, such lines fail the import. It is also called for the
+	// first line of each type written by the export options that is not
+	// imported, such lines never failing the import.
+	LineSkipped func(dir string, err ErrUnsupportedArchiveLineType)
 }
 
 // ErrUnsupportedArchiveVersion is an error returned when trying to import an
@@ -70,18 +161,80 @@ func (e ErrUnsupportedArchiveVersion) Error() string {
 // ErrUnsupportedArchiveLineType is an error returned when trying to import an
 // archive containing an unsupported line type.
 type ErrUnsupportedArchiveLineType struct {
-	line int
-	got  string
+	line   int
+	got    string
+	reason string
 }
 
 // NewErrUnsupportedArchiveLineType creates a ErrUnsupportedArchiveLineType error.
 func NewErrUnsupportedArchiveLineType(line int, got string) ErrUnsupportedArchiveLineType {
 	return ErrUnsupportedArchiveLineType{
-		line: line,
-		got:  got,
+		line:   line,
+		got:    got,
+		reason: "unsupported archive line type",
+	}
+}
+
+// NewErrNotImportedArchiveLineType creates a ErrUnsupportedArchiveLineType
+// error for a line type written by the export options, but not imported.
+func NewErrNotImportedArchiveLineType(line int, got string) ErrUnsupportedArchiveLineType {
+	return ErrUnsupportedArchiveLineType{
+		line:   line,
+		got:    got,
+		reason: "archive line type not imported into new boards",
 	}
 }
 
 func (e ErrUnsupportedArchiveLineType) Error() string {
-	return fmt.Sprintf("unsupported archive line type; got %s, line %d", e.got, e.line)
+	return fmt.Sprintf("%s; got %s, line %d", e.reason, e.got, e.line)
+}
+
+// Line returns the number of the unsupported line.
+func (e ErrUnsupportedArchiveLineType) Line() int {
+	return e.line
+}
+
+// Type returns the type of the unsupported line.
+func (e ErrUnsupportedArchiveLineType) Type() string {
+	return e.got
+}
+
+// ImportReport is the outcome of an import of another tool's export.
+// swagger:model
+type ImportReport struct {
+	// The format of the imported export
+	// required: true
+	Format string `json:"format"`
+
+	// The IDs of the created boards
+	// required: true
+	BoardIDs []string `json:"boardIds"`
+
+	// The number of imported cards
+	// required: true
+	Cards int `json:"cards"`
+
+	// The items of the export that could not be mapped, or only partially
+	// required: true
+	Items []ImportReportItem `json:"items"`
+}
+
+// ImportReportItem is an item of an export that could not be fully imported.
+// swagger:model
+type ImportReportItem struct {
+	// The kind of item, e.g. card, comment, attachment or customField
+	// required: true
+	Kind string `json:"kind"`
+
+	// The ID of the item in the export
+	// required: false
+	SourceID string `json:"sourceId,omitempty"`
+
+	// The name of the item in the export
+	// required: false
+	Title string `json:"title,omitempty"`
+
+	// Why the item could not be mapped
+	// required: true
+	Reason string `json:"reason"`
 }
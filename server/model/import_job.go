@@ -0,0 +1,130 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+const (
+	ImportJobStatusPending   = "pending"
+	ImportJobStatusRunning   = "running"
+	ImportJobStatusCompleted = "completed"
+	ImportJobStatusFailed    = "failed"
+)
+
+var ErrImportJobNotResumable = errors.New("only failed or interrupted import jobs can be resumed")
+
+// ImportJob is an archive import running in the background. Each board of
+// the archive is committed on its own, so a failed job can be resumed from
+// the first board that wasn't imported
+// swagger:model
+type ImportJob struct {
+	// The ID of the job
+	// required: true
+	ID string `json:"id"`
+
+	// The ID of the team the archive is imported into
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The ID of the user that started the import
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The status of the job: pending, running, completed or failed
+	// required: true
+	Status string `json:"status"`
+
+	// If true, the archive is only validated and nothing is imported
+	// required: true
+	DryRun bool `json:"dryRun"`
+
+	// The name of the uploaded archive
+	// required: false
+	Filename string `json:"filename"`
+
+	// The path of the uploaded archive in the files backend
+	// required: false
+	FilePath string `json:"-"`
+
+	// The size of the archive in bytes
+	// required: true
+	Size int64 `json:"size"`
+
+	// The number of bytes of the archive processed so far
+	// required: true
+	BytesRead int64 `json:"bytesRead"`
+
+	// The progress of the job, in percent
+	// required: true
+	Progress int `json:"progress"`
+
+	// The number of boards of the archive processed so far
+	// required: true
+	BoardsProcessed int `json:"boardsProcessed"`
+
+	// The imported boards, by their directory in the archive
+	// required: true
+	ImportedBoards map[string]string `json:"importedBoards"`
+
+	// The lines of the archive that were skipped
+	// required: true
+	SkippedLines []ImportSkippedLine `json:"skippedLines"`
+
+	// The error that failed the job, if any
+	// required: false
+	Error string `json:"error,omitempty"`
+
+	// The creation time in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// ImportSkippedLine is a line of an archive that was not imported.
+// swagger:model
+type ImportSkippedLine struct {
+	// The directory of the board in the archive
+	// required: true
+	Board string `json:"board"`
+
+	// The number of the line in the board file
+	// required: true
+	Line int `json:"line"`
+
+	// The type of the line
+	// required: true
+	Type string `json:"type"`
+
+	// Why the line was skipped
+	// required: true
+	Reason string `json:"reason"`
+}
+
+// SetBytesRead updates the number of bytes processed and the progress of
+// the job.
+func (j *ImportJob) SetBytesRead(bytesRead int64) {
+	j.BytesRead = bytesRead
+	j.Progress = 0
+	if j.Size > 0 {
+		j.Progress = int(bytesRead * 100 / j.Size)
+	}
+	if j.Progress > 100 {
+		j.Progress = 100
+	}
+}
+
+// IsFinished returns true if the job completed or failed.
+func (j *ImportJob) IsFinished() bool {
+	return j.Status == ImportJobStatusCompleted || j.Status == ImportJobStatusFailed
+}
+
+func ImportJobFromJSON(data io.Reader) *ImportJob {
+	var job *ImportJob
+	_ = json.NewDecoder(data).Decode(&job)
+	return job
+}
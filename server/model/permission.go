@@ -6,6 +6,7 @@ import (
 
 var (
 	PermissionViewTeam              = mmModel.PermissionViewTeam
+	PermissionManageTeam            = mmModel.PermissionManageTeam
 	PermissionReadChannel           = mmModel.PermissionReadChannel
 	PermissionViewMembers           = mmModel.PermissionViewMembers
 	PermissionCreatePublicChannel   = mmModel.PermissionCreatePublicChannel
@@ -41,10 +41,12 @@ type PropSchema map[string]PropDef
 
 // PropDefOption represents an option within a property definition.
 type PropDefOption struct {
-	ID    string `json:"id"`
-	Index int    `json:"index"`
-	Color string `json:"color"`
-	Value string `json:"value"`
+	ID              string `json:"id"`
+	Index           int    `json:"index"`
+	Color           string `json:"color"`
+	Value           string `json:"value"`
+	WIPLimit        int    `json:"wipLimit,omitempty"`
+	AutoArchiveDays int    `json:"autoArchiveDays,omitempty"`
 }
 
 // PropDef represents a property definition as defined in a board's Fields member.
@@ -54,6 +56,14 @@ type PropDef struct {
 	Name    string                   `json:"name"`
 	Type    string                   `json:"type"`
 	Options map[string]PropDefOption `json:"options"`
+	Formula string                   `json:"formula,omitempty"`
+	Rollup  *RollupDef               `json:"rollup,omitempty"`
+
+	// Constraints checked when card values are written
+	Required bool     `json:"required,omitempty"`
+	Pattern  string   `json:"pattern,omitempty"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
 }
 
 // GetValue resolves the value of a property if the passed value is an ID for an option,
@@ -157,6 +167,25 @@ func ParsePropertySchema(board *Board) (PropSchema, error) {
 			Name:    getMapString("name", prop),
 			Type:    getMapString("type", prop),
 			Options: make(map[string]PropDefOption),
+
+			Required: getMapBool("required", prop),
+			Pattern:  getMapString("pattern", prop),
+			Min:      getMapFloat("min", prop),
+			Max:      getMapFloat("max", prop),
+		}
+		if pd.Type == PropertyTypeFormula {
+			pd.Formula = getMapString("formula", prop)
+		}
+		if pd.Type == PropertyTypeRollup {
+			rollup, ok := prop["rollup"].(map[string]interface{})
+			if !ok {
+				return nil, ErrInvalidPropSchema
+			}
+			pd.Rollup = &RollupDef{
+				RelationPropertyID: getMapString("relationPropertyId", rollup),
+				TargetPropertyID:   getMapString("targetPropertyId", rollup),
+				Function:           getMapString("function", rollup),
+			}
 		}
 		optsIface, ok := prop["options"]
 		if ok {
@@ -175,6 +204,12 @@ func ParsePropertySchema(board *Board) (PropSchema, error) {
 					Value: getMapString("value", propOpt),
 					Color: getMapString("color", propOpt),
 				}
+				if wipLimit := getMapInt("wipLimit", propOpt); wipLimit > 0 {
+					po.WIPLimit = wipLimit
+				}
+				if days := getMapInt("autoArchiveDays", propOpt); days > 0 {
+					po.AutoArchiveDays = days
+				}
 				pd.Options[po.ID] = po
 			}
 		}
@@ -196,6 +231,39 @@ func getMapString(key string, m map[string]interface{}) string {
 	return s
 }
 
+func getMapInt(key string, m map[string]interface{}) int {
+	switch v := m[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case int64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+func getMapBool(key string, m map[string]interface{}) bool {
+	b, _ := m[key].(bool)
+	return b
+}
+
+func getMapFloat(key string, m map[string]interface{}) *float64 {
+	var f float64
+	switch v := m[key].(type) {
+	case float64:
+		f = v
+	case int:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	default:
+		return nil
+	}
+	return &f
+}
+
 // ParseProperties parses a block's `Fields` to extract the properties. Properties typically exist on
 // card blocks.  A resolver can optionally be provided to fetch usernames for `person` prop type.
 func ParseProperties(block *Block, schema PropSchema, resolver PropValueResolver) (BlockProperties, error) {
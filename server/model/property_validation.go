@@ -0,0 +1,338 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+const (
+	// BoardPropertyPropertyValidation is the board property holding how
+	// card property values are validated.
+	BoardPropertyPropertyValidation = "propertyValidation"
+
+	// PropertyValidationLenient accepts invalid card property values and
+	// only logs them. It is the default mode.
+	PropertyValidationLenient = "lenient"
+
+	// PropertyValidationStrict rejects changes that store invalid card
+	// property values or leave a required property empty.
+	PropertyValidationStrict = "strict"
+)
+
+const (
+	PropertyValidationRequired        = "required"
+	PropertyValidationUnknownProperty = "unknownProperty"
+	PropertyValidationInvalidType     = "invalidType"
+	PropertyValidationUnknownOption   = "unknownOption"
+	PropertyValidationInvalidDate     = "invalidDate"
+	PropertyValidationNotANumber      = "notANumber"
+	PropertyValidationBelowMinimum    = "belowMinimum"
+	PropertyValidationAboveMaximum    = "aboveMaximum"
+	PropertyValidationPatternMismatch = "patternMismatch"
+	PropertyValidationNotBoardMember  = "notBoardMember"
+)
+
+var ErrInvalidPropertyConstraint = errors.New("invalid property constraint")
+
+// PropertyValidationMode returns how the card property values of the board
+// are validated.
+func (b *Board) PropertyValidationMode() string {
+	if mode, _ := b.Properties[BoardPropertyPropertyValidation].(string); mode == PropertyValidationStrict {
+		return PropertyValidationStrict
+	}
+	return PropertyValidationLenient
+}
+
+// IsPropertyValidationModeValid returns true if mode is a known property
+// validation mode.
+func IsPropertyValidationModeValid(mode string) bool {
+	return mode == PropertyValidationLenient || mode == PropertyValidationStrict
+}
+
+// ChangesPropertyValidationMode returns true if the patch updates or
+// removes the property validation mode of the board.
+func (p *BoardPatch) ChangesPropertyValidationMode() bool {
+	return p.changesProperty(BoardPropertyPropertyValidation)
+}
+
+// PropertyValidationError describes a card property value that doesn't
+// satisfy the board's card properties
+// swagger:model
+type PropertyValidationError struct {
+	// The ID of the card
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The ID of the property
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	// Why the value is invalid, e.g. required, unknownOption or belowMinimum
+	// required: true
+	Reason string `json:"reason"`
+}
+
+// ValidatePropertyConstraints checks that the constraints of the
+// properties can be applied to their type.
+func (s PropSchema) ValidatePropertyConstraints() error {
+	for _, pd := range s {
+		if pd.Pattern != "" {
+			if _, err := regexp.Compile(pd.Pattern); err != nil {
+				return fmt.Errorf("property %s: %w: %s", pd.Name, ErrInvalidPropertyConstraint, err.Error())
+			}
+		}
+		if pd.Min != nil || pd.Max != nil {
+			if pd.Type != "number" {
+				return fmt.Errorf("property %s: %w: only number properties can have a range", pd.Name, ErrInvalidPropertyConstraint)
+			}
+			if pd.Min != nil && pd.Max != nil && *pd.Min > *pd.Max {
+				return fmt.Errorf("property %s: %w: min is greater than max", pd.Name, ErrInvalidPropertyConstraint)
+			}
+		}
+		if pd.Required && pd.isReadOnly() {
+			return fmt.Errorf("property %s: %w: the value is set by the server and can't be required", pd.Name, ErrInvalidPropertyConstraint)
+		}
+	}
+	return nil
+}
+
+// isReadOnly returns true if the value of the property is set by the
+// server rather than stored on the card.
+func (pd PropDef) isReadOnly() bool {
+	switch pd.Type {
+	case "createdTime", "createdBy", "updatedTime", "updatedBy":
+		return true
+	}
+	return pd.IsComputed()
+}
+
+// CardValidator checks card property values against a property schema.
+type CardValidator struct {
+	schema   PropSchema
+	members  map[string]bool
+	patterns map[string]*regexp.Regexp
+}
+
+// NewCardValidator creates a validator for the schema. members holds the
+// IDs of the board members, person values are not checked if it is nil.
+func NewCardValidator(schema PropSchema, members map[string]bool) *CardValidator {
+	return &CardValidator{
+		schema:   schema,
+		members:  members,
+		patterns: map[string]*regexp.Regexp{},
+	}
+}
+
+// NeedsMembers returns true if the schema has person properties, whose
+// values are checked against the board members.
+func (s PropSchema) NeedsMembers() bool {
+	for _, pd := range s {
+		if pd.Type == "person" || pd.Type == "multiPerson" {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the property values of a card. previous is the card
+// before the change, or nil for a new card. Only the values that the
+// change sets are checked, so existing invalid values don't block
+// unrelated edits. The errors are sorted by property index, with unknown
+// properties last.
+func (v *CardValidator) Validate(card *Block, previous *Block) []*PropertyValidationError {
+	if card.Type != TypeCard {
+		return nil
+	}
+
+	values := cardProperties(card)
+	var before map[string]interface{}
+	if previous != nil {
+		before = cardProperties(previous)
+	}
+
+	var errs []*PropertyValidationError
+	addErr := func(propertyID, reason string) {
+		errs = append(errs, &PropertyValidationError{CardID: card.ID, PropertyID: propertyID, Reason: reason})
+	}
+
+	for propertyID, value := range values {
+		if previous != nil && reflect.DeepEqual(before[propertyID], value) {
+			continue
+		}
+		pd, ok := v.schema[propertyID]
+		if !ok {
+			addErr(propertyID, PropertyValidationUnknownProperty)
+			continue
+		}
+		// values of read only properties are replaced by the server
+		if isEmptyPropertyValue(value) || pd.isReadOnly() {
+			continue
+		}
+		if reason := v.validateValue(pd, value); reason != "" {
+			addErr(propertyID, reason)
+		}
+	}
+
+	for _, pd := range v.schema {
+		if !pd.Required || !isEmptyPropertyValue(values[pd.ID]) {
+			continue
+		}
+		// a card that already missed the value can still be edited
+		if previous == nil || !isEmptyPropertyValue(before[pd.ID]) {
+			addErr(pd.ID, PropertyValidationRequired)
+		}
+	}
+
+	// unknown properties go last
+	sort.Slice(errs, func(i, j int) bool {
+		a, aKnown := v.schema[errs[i].PropertyID]
+		b, bKnown := v.schema[errs[j].PropertyID]
+		if aKnown != bKnown {
+			return aKnown
+		}
+		if a.Index != b.Index {
+			return a.Index < b.Index
+		}
+		return errs[i].PropertyID < errs[j].PropertyID
+	})
+	return errs
+}
+
+func (v *CardValidator) validateValue(pd PropDef, value interface{}) string {
+	switch pd.Type {
+	case "select":
+		id, ok := value.(string)
+		if !ok {
+			return PropertyValidationInvalidType
+		}
+		if _, ok := pd.Options[id]; !ok {
+			return PropertyValidationUnknownOption
+		}
+
+	case "multiSelect":
+		ids, ok := stringList(value)
+		if !ok {
+			return PropertyValidationInvalidType
+		}
+		for _, id := range ids {
+			if _, ok := pd.Options[id]; !ok {
+				return PropertyValidationUnknownOption
+			}
+		}
+
+	case "person":
+		id, ok := value.(string)
+		if !ok {
+			return PropertyValidationInvalidType
+		}
+		if v.members != nil && !v.members[id] {
+			return PropertyValidationNotBoardMember
+		}
+
+	case "multiPerson":
+		ids, ok := stringList(value)
+		if !ok {
+			return PropertyValidationInvalidType
+		}
+		for _, id := range ids {
+			if v.members != nil && !v.members[id] {
+				return PropertyValidationNotBoardMember
+			}
+		}
+
+	case PropertyTypeRelation:
+		if _, ok := stringList(value); !ok {
+			return PropertyValidationInvalidType
+		}
+
+	case "date":
+		date, ok := value.(string)
+		if !ok {
+			return PropertyValidationInvalidType
+		}
+		if _, err := pd.ParseDate(date); err != nil {
+			return PropertyValidationInvalidDate
+		}
+
+	case "checkbox":
+		switch value {
+		case true, false, "true", "false":
+		default:
+			return PropertyValidationInvalidType
+		}
+
+	case "number":
+		if _, isBool := value.(bool); isBool {
+			return PropertyValidationNotANumber
+		}
+		number, err := formulaNumber(value)
+		if err != nil {
+			return PropertyValidationNotANumber
+		}
+		if pd.Min != nil && number < *pd.Min {
+			return PropertyValidationBelowMinimum
+		}
+		if pd.Max != nil && number > *pd.Max {
+			return PropertyValidationAboveMaximum
+		}
+
+	default:
+		// text, url, email and phone
+		if _, ok := value.(string); !ok {
+			return PropertyValidationInvalidType
+		}
+	}
+
+	if pd.Pattern != "" {
+		s, ok := value.(string)
+		if !ok {
+			return PropertyValidationInvalidType
+		}
+		if pattern := v.pattern(pd); pattern != nil && !pattern.MatchString(s) {
+			return PropertyValidationPatternMismatch
+		}
+	}
+	return ""
+}
+
+func (v *CardValidator) pattern(pd PropDef) *regexp.Regexp {
+	if pattern, ok := v.patterns[pd.ID]; ok {
+		return pattern
+	}
+	// invalid patterns are rejected when the board is patched, ignore
+	// the ones saved before that
+	pattern, _ := regexp.Compile(pd.Pattern)
+	v.patterns[pd.ID] = pattern
+	return pattern
+}
+
+func isEmptyPropertyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func stringList(value interface{}) ([]string, bool) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		strs = append(strs, s)
+	}
+	return strs, true
+}
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func propertyValidationTestBoard() *Board {
+	return &Board{
+		ID: "board-id",
+		Properties: map[string]interface{}{
+			BoardPropertyPropertyValidation: PropertyValidationStrict,
+		},
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "required": true, "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "done", "value": "Done"},
+			}},
+			{"id": "tags", "name": "Tags", "type": "multiSelect", "options": []interface{}{
+				map[string]interface{}{"id": "bug", "value": "Bug"},
+			}},
+			{"id": "estimate", "name": "Estimate", "type": "number", "min": float64(0), "max": float64(100)},
+			{"id": "due", "name": "Due", "type": "date"},
+			{"id": "owner", "name": "Owner", "type": "person"},
+			{"id": "reviewers", "name": "Reviewers", "type": "multiPerson"},
+			{"id": "done", "name": "Done", "type": "checkbox"},
+			{"id": "ticket", "name": "Ticket", "type": "text", "pattern": `^[A-Z]+-\d+$`},
+			{"id": "created", "name": "Created", "type": "createdTime"},
+		},
+	}
+}
+
+func propertyValidationTestCard(properties map[string]interface{}) *Block {
+	return &Block{
+		ID:      "card-id",
+		BoardID: "board-id",
+		Type:    TypeCard,
+		Fields:  map[string]interface{}{"properties": properties},
+	}
+}
+
+func TestPropertyValidationMode(t *testing.T) {
+	require.Equal(t, PropertyValidationStrict, propertyValidationTestBoard().PropertyValidationMode())
+	require.Equal(t, PropertyValidationLenient, (&Board{}).PropertyValidationMode())
+
+	patch := &BoardPatch{UpdatedProperties: map[string]interface{}{BoardPropertyPropertyValidation: PropertyValidationLenient}}
+	require.True(t, patch.ChangesPropertyValidationMode())
+	require.False(t, patch.ChangesWIPLimitMode())
+	require.NoError(t, patch.IsValid())
+
+	patch = &BoardPatch{UpdatedProperties: map[string]interface{}{BoardPropertyPropertyValidation: "sometimes"}}
+	require.Error(t, patch.IsValid())
+}
+
+func TestValidatePropertyConstraints(t *testing.T) {
+	schema, err := ParsePropertySchema(propertyValidationTestBoard())
+	require.NoError(t, err)
+	require.NoError(t, schema.ValidatePropertyConstraints())
+	require.True(t, schema["status"].Required)
+	require.Equal(t, float64(100), *schema["estimate"].Max)
+
+	testCases := []struct {
+		name     string
+		property map[string]interface{}
+	}{
+		{"invalid pattern", map[string]interface{}{"id": "p", "name": "P", "type": "text", "pattern": "("}},
+		{"range on a text property", map[string]interface{}{"id": "p", "name": "P", "type": "text", "min": float64(1)}},
+		{"min greater than max", map[string]interface{}{"id": "p", "name": "P", "type": "number", "min": float64(2), "max": float64(1)}},
+		{"required read only property", map[string]interface{}{"id": "p", "name": "P", "type": "updatedBy", "required": true}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schema, err := ParsePropertySchema(&Board{CardProperties: []map[string]interface{}{tc.property}})
+			require.NoError(t, err)
+			require.True(t, errors.Is(schema.ValidatePropertyConstraints(), ErrInvalidPropertyConstraint))
+		})
+	}
+
+	t.Run("board patch", func(t *testing.T) {
+		patch := &BoardPatch{UpdatedCardProperties: []map[string]interface{}{testCases[0].property}}
+		require.ErrorIs(t, patch.ValidateCardProperties(&Board{}), ErrInvalidPropertyConstraint)
+	})
+}
+
+func TestCardValidator(t *testing.T) {
+	schema, err := ParsePropertySchema(propertyValidationTestBoard())
+	require.NoError(t, err)
+	validator := NewCardValidator(schema, map[string]bool{"user-1": true, "user-2": true})
+
+	t.Run("valid card", func(t *testing.T) {
+		card := propertyValidationTestCard(map[string]interface{}{
+			"status":    "todo",
+			"tags":      []interface{}{"bug"},
+			"estimate":  "42.5",
+			"due":       `{"from":1642161600000}`,
+			"owner":     "user-1",
+			"reviewers": []interface{}{"user-1", "user-2"},
+			"done":      "true",
+			"ticket":    "ABC-123",
+			"created":   "anything, it's replaced by the server",
+		})
+		require.Empty(t, validator.Validate(card, nil))
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		card := propertyValidationTestCard(map[string]interface{}{
+			"tags":      []interface{}{"feature"},
+			"estimate":  "101",
+			"due":       "tomorrow",
+			"owner":     "someone-else",
+			"reviewers": "user-1",
+			"done":      "yes",
+			"ticket":    "abc",
+			"unknown":   "value",
+		})
+		require.Equal(t, []*PropertyValidationError{
+			{CardID: "card-id", PropertyID: "status", Reason: PropertyValidationRequired},
+			{CardID: "card-id", PropertyID: "tags", Reason: PropertyValidationUnknownOption},
+			{CardID: "card-id", PropertyID: "estimate", Reason: PropertyValidationAboveMaximum},
+			{CardID: "card-id", PropertyID: "due", Reason: PropertyValidationInvalidDate},
+			{CardID: "card-id", PropertyID: "owner", Reason: PropertyValidationNotBoardMember},
+			{CardID: "card-id", PropertyID: "reviewers", Reason: PropertyValidationInvalidType},
+			{CardID: "card-id", PropertyID: "done", Reason: PropertyValidationInvalidType},
+			{CardID: "card-id", PropertyID: "ticket", Reason: PropertyValidationPatternMismatch},
+			{CardID: "card-id", PropertyID: "unknown", Reason: PropertyValidationUnknownProperty},
+		}, validator.Validate(card, nil))
+	})
+
+	t.Run("numbers", func(t *testing.T) {
+		require.Equal(t, PropertyValidationNotANumber,
+			validator.Validate(propertyValidationTestCard(map[string]interface{}{"status": "todo", "estimate": "many"}), nil)[0].Reason)
+		require.Equal(t, PropertyValidationBelowMinimum,
+			validator.Validate(propertyValidationTestCard(map[string]interface{}{"status": "todo", "estimate": float64(-1)}), nil)[0].Reason)
+	})
+
+	t.Run("only changed values are checked", func(t *testing.T) {
+		previous := propertyValidationTestCard(map[string]interface{}{"estimate": "500", "owner": "former-member"})
+		card := propertyValidationTestCard(map[string]interface{}{"estimate": "500", "owner": "former-member", "tags": []interface{}{"bug"}})
+		require.Empty(t, validator.Validate(card, previous))
+
+		// clearing a required value is rejected
+		previous = propertyValidationTestCard(map[string]interface{}{"status": "todo"})
+		card = propertyValidationTestCard(map[string]interface{}{"status": ""})
+		require.Equal(t, []*PropertyValidationError{
+			{CardID: "card-id", PropertyID: "status", Reason: PropertyValidationRequired},
+		}, validator.Validate(card, previous))
+	})
+
+	t.Run("person values without members", func(t *testing.T) {
+		validator := NewCardValidator(schema, nil)
+		card := propertyValidationTestCard(map[string]interface{}{"status": "done", "owner": "anyone"})
+		require.Empty(t, validator.Validate(card, nil))
+		require.True(t, schema.NeedsMembers())
+	})
+
+	t.Run("blocks that aren't cards", func(t *testing.T) {
+		block := propertyValidationTestCard(map[string]interface{}{"unknown": "value"})
+		block.Type = TypeView
+		require.Empty(t, validator.Validate(block, nil))
+	})
+}
@@ -0,0 +1,238 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	// TemplateCategoryMaxLength is the maximum length of a template category.
+	TemplateCategoryMaxLength = 100
+
+	// TemplateMaxTags is the maximum number of tags a template can have.
+	TemplateMaxTags = 20
+)
+
+var (
+	ErrTemplateCategoryTooLong = errors.New("template category too long")
+	ErrTemplateTooManyTags     = errors.New("template has too many tags")
+	ErrTemplateInvalidTag      = errors.New("template tag cannot be empty")
+)
+
+// Template is a board published to the template library of a team.
+// Every publication of the source board bumps the version of the
+// template and replaces the board holding its contents
+// swagger:model
+type Template struct {
+	// The ID of the template. It doesn't change between versions
+	// required: true
+	ID string `json:"id"`
+
+	// The ID of the team that the template belongs to
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The ID of the template board holding the current version
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the board the template was published from, if any
+	// required: false
+	SourceBoardID string `json:"sourceBoardId"`
+
+	// The category of the template in the library
+	// required: false
+	Category string `json:"category"`
+
+	// The tags of the template
+	// required: false
+	Tags []string `json:"tags"`
+
+	// The current version of the template
+	// required: true
+	Version int `json:"version"`
+
+	// The ID of the user that published the template
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The ID of the user that published the last version of the template
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// The creation time in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+
+	// The template board, only populated in API responses
+	// required: false
+	Board *Board `json:"board,omitempty"`
+}
+
+// HasTag returns true if the template is tagged with the given tag,
+// ignoring case.
+func (t *Template) HasTag(tag string) bool {
+	for _, templateTag := range t.Tags {
+		if strings.EqualFold(templateTag, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid checks the template metadata.
+func (t *Template) IsValid() error {
+	return validateTemplateMetadata(t.Category, t.Tags)
+}
+
+func validateTemplateMetadata(category string, tags []string) error {
+	if len(category) > TemplateCategoryMaxLength {
+		return ErrTemplateCategoryTooLong
+	}
+	if len(tags) > TemplateMaxTags {
+		return ErrTemplateTooManyTags
+	}
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			return ErrTemplateInvalidTag
+		}
+	}
+	return nil
+}
+
+// PublishTemplateOptions are the options used to publish a board as a
+// template. Publishing a board that was already published creates a new
+// version of the existing template
+// swagger:model
+type PublishTemplateOptions struct {
+	// The title of the template board. Defaults to the title of the source board
+	// required: false
+	Title string `json:"title"`
+
+	// The category of the template in the library
+	// required: false
+	Category string `json:"category"`
+
+	// The tags of the template
+	// required: false
+	Tags []string `json:"tags"`
+}
+
+// IsValid checks the publish options.
+func (o *PublishTemplateOptions) IsValid() error {
+	return validateTemplateMetadata(o.Category, o.Tags)
+}
+
+// TemplatePatch is a patch to modify the metadata of a template
+// swagger:model
+type TemplatePatch struct {
+	// The category of the template
+	// required: false
+	Category *string `json:"category"`
+
+	// The tags of the template, replacing the existing ones
+	// required: false
+	Tags []string `json:"tags"`
+}
+
+// Patch applies the template patch to the template.
+func (p *TemplatePatch) Patch(template *Template) *Template {
+	if p.Category != nil {
+		template.Category = *p.Category
+	}
+	if p.Tags != nil {
+		template.Tags = p.Tags
+	}
+	return template
+}
+
+// QueryTemplatesOptions are the query options used to list the templates
+// of a team.
+type QueryTemplatesOptions struct {
+	TeamID   string
+	Category string
+	Tag      string
+}
+
+func TemplateFromJSON(data io.Reader) *Template {
+	var template *Template
+	_ = json.NewDecoder(data).Decode(&template)
+	return template
+}
+
+func TemplatesFromJSON(data io.Reader) []*Template {
+	var templates []*Template
+	_ = json.NewDecoder(data).Decode(&templates)
+	return templates
+}
+
+// TemplateDerivedBoard links a board to the template it was created from.
+type TemplateDerivedBoard struct {
+	BoardID         string `json:"boardId"`
+	TemplateID      string `json:"templateId"`
+	TemplateVersion int    `json:"templateVersion"`
+	CreateAt        int64  `json:"createAt"`
+	UpdateAt        int64  `json:"updateAt"`
+}
+
+// TemplateArchiveInfo is the template metadata exported alongside a template
+// board in an archive.
+type TemplateArchiveInfo struct {
+	Category string   `json:"category"`
+	Tags     []string `json:"tags"`
+	Version  int      `json:"version"`
+}
+
+// TemplateBoardUpdate describes the changes applied to a board when it was
+// updated from its template
+// swagger:model
+type TemplateBoardUpdate struct {
+	// The ID of the updated board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The IDs of the card properties added to the board
+	// required: true
+	AddedProperties []string `json:"addedProperties"`
+
+	// The IDs of the card properties that received new options
+	// required: true
+	UpdatedProperties []string `json:"updatedProperties"`
+
+	// The IDs of the views added to the board
+	// required: true
+	AddedViews []string `json:"addedViews"`
+}
+
+// HasChanges returns true if the board was modified.
+func (u *TemplateBoardUpdate) HasChanges() bool {
+	return len(u.AddedProperties) != 0 || len(u.UpdatedProperties) != 0 || len(u.AddedViews) != 0
+}
+
+// TemplatePropagationReport is the result of pushing the current version of a
+// template to the boards created from it
+// swagger:model
+type TemplatePropagationReport struct {
+	// The ID of the template
+	// required: true
+	TemplateID string `json:"templateId"`
+
+	// The version of the template pushed to the boards
+	// required: true
+	Version int `json:"version"`
+
+	// The boards that were updated
+	// required: true
+	Boards []*TemplateBoardUpdate `json:"boards"`
+
+	// The IDs of the boards that were skipped, as the user can't modify
+	// their properties
+	// required: true
+	SkippedBoardIDs []string `json:"skippedBoardIds"`
+}
@@ -0,0 +1,207 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+)
+
+const (
+	// UserCardsDefaultPerPage is the default page size used when listing the cards of a user.
+	UserCardsDefaultPerPage = 50
+
+	// UserCardsMaxPerPage is the maximum page size allowed when listing the cards of a user.
+	UserCardsMaxPerPage = 500
+
+	UserCardsSortUpdateAt = "updateAt"
+	UserCardsSortCreateAt = "createAt"
+	UserCardsSortTitle    = "title"
+	UserCardsSortDueAt    = "dueAt"
+)
+
+var ErrInvalidUserCardsSort = errors.New("invalid user cards sort")
+
+// QueryUserCardsOptions are query options that can be passed to GetCardsForUser.
+type QueryUserCardsOptions struct {
+	TeamID     string // if not empty then filter for cards of the boards of this team
+	Status     string // if not empty then filter for cards with a select option of this name
+	DueAfter   int64  // if non-zero then filter for cards due at or after DueAfter
+	DueBefore  int64  // if non-zero then filter for cards due before DueBefore
+	SortBy     string // one of the UserCardsSort values, updateAt by default
+	Descending bool   // if true then sort in descending order
+	Page       int    // page number to select when paginating
+	PerPage    int    // number of cards to return per page
+}
+
+// IsValid checks the sort of the options.
+func (o QueryUserCardsOptions) IsValid() error {
+	switch o.SortBy {
+	case "", UserCardsSortUpdateAt, UserCardsSortCreateAt, UserCardsSortTitle, UserCardsSortDueAt:
+		return nil
+	}
+	return ErrInvalidUserCardsSort
+}
+
+// UserCardsStatusOption is a select option matching the status filter,
+// with the boards where the property has it.
+type UserCardsStatusOption struct {
+	PropertyID string
+	OptionID   string
+	BoardIDs   []string
+}
+
+// UserCardsQuery is the store query selecting the cards of a user. As card
+// properties are defined per board, the properties to check are given with
+// the boards where they are defined.
+type UserCardsQuery struct {
+	UserID   string
+	BoardIDs []string
+
+	// The person and multiPerson properties, keyed by property ID
+	PersonProperties map[string][]string
+
+	// The date property holding the due date of the cards, keyed by
+	// property ID. It is the first date property of each board
+	DueProperties map[string][]string
+
+	// If not nil then filter for cards with one of these options
+	StatusOptions []*UserCardsStatusOption
+
+	QueryUserCardsOptions
+}
+
+// NewUserCardsQuery builds the query selecting the cards of a user on the
+// given boards. It returns nil if a status is requested and no board has a
+// select option of this name, as no card can match.
+func NewUserCardsQuery(userID string, boards []*Board, opts QueryUserCardsOptions) (*UserCardsQuery, error) {
+	query := &UserCardsQuery{
+		UserID:                userID,
+		BoardIDs:              make([]string, 0, len(boards)),
+		PersonProperties:      map[string][]string{},
+		DueProperties:         map[string][]string{},
+		QueryUserCardsOptions: opts,
+	}
+	status := strings.TrimSpace(opts.Status)
+	statusOptions := map[[2]string]*UserCardsStatusOption{}
+
+	for _, board := range boards {
+		schema, err := ParsePropertySchema(board)
+		if err != nil {
+			return nil, err
+		}
+		query.BoardIDs = append(query.BoardIDs, board.ID)
+
+		var dueProperty *PropDef
+		for id, pd := range schema {
+			switch pd.Type {
+			case "person", "multiPerson":
+				query.PersonProperties[id] = append(query.PersonProperties[id], board.ID)
+			case "date":
+				if dueProperty == nil || pd.Index < dueProperty.Index {
+					pd := pd
+					dueProperty = &pd
+				}
+			case "select":
+				if status == "" {
+					continue
+				}
+				for _, option := range pd.Options {
+					if !strings.EqualFold(option.Value, status) {
+						continue
+					}
+					key := [2]string{id, option.ID}
+					if statusOptions[key] == nil {
+						statusOptions[key] = &UserCardsStatusOption{PropertyID: id, OptionID: option.ID}
+					}
+					statusOptions[key].BoardIDs = append(statusOptions[key].BoardIDs, board.ID)
+				}
+			}
+		}
+		if dueProperty != nil {
+			query.DueProperties[dueProperty.ID] = append(query.DueProperties[dueProperty.ID], board.ID)
+		}
+	}
+
+	if status != "" {
+		if len(statusOptions) == 0 {
+			return nil, nil
+		}
+		query.StatusOptions = make([]*UserCardsStatusOption, 0, len(statusOptions))
+		for _, option := range statusOptions {
+			query.StatusOptions = append(query.StatusOptions, option)
+		}
+		sort.Slice(query.StatusOptions, func(i, j int) bool {
+			a, b := query.StatusOptions[i], query.StatusOptions[j]
+			if a.PropertyID != b.PropertyID {
+				return a.PropertyID < b.PropertyID
+			}
+			return a.OptionID < b.OptionID
+		})
+	}
+	return query, nil
+}
+
+// UserCard is a card listed in the cards of a user, with the board it
+// belongs to
+// swagger:model
+type UserCard struct {
+	// The card
+	// required: true
+	Card Block `json:"card"`
+
+	// The title of the board of the card
+	// required: true
+	BoardTitle string `json:"boardTitle"`
+
+	// The icon of the board of the card
+	// required: false
+	BoardIcon string `json:"boardIcon"`
+
+	// The team of the board of the card
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The due date of the card in milliseconds since the current epoch,
+	// zero if it has none
+	// required: false
+	DueAt int64 `json:"dueAt"`
+}
+
+// UserCardsList is a response type with pagination support.
+// swagger:model
+type UserCardsList struct {
+	// True if there are more cards to fetch
+	// required: true
+	HasNext bool `json:"hasNext"`
+
+	// The cards of the page
+	// required: true
+	Items []*UserCard `json:"items"`
+}
+
+// CardDueAt returns the due date of a card, the end of the range of its date
+// property, or its start if it has no end. It returns zero if the card has
+// no date.
+func CardDueAt(card *Block, propertyID string) int64 {
+	properties, ok := card.Fields["properties"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	value, ok := properties[propertyID].(string)
+	if !ok || value == "" {
+		return 0
+	}
+
+	var date struct {
+		From int64 `json:"from"`
+		To   int64 `json:"to"`
+	}
+	if err := json.Unmarshal([]byte(value), &date); err != nil {
+		return 0
+	}
+	if date.To != 0 {
+		return date.To
+	}
+	return date.From
+}
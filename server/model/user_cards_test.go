@@ -0,0 +1,83 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUserCardsQuery(t *testing.T) {
+	boards := []*Board{
+		{
+			ID: "board-1",
+			CardProperties: []map[string]interface{}{
+				{"id": "owner", "name": "Owner", "type": "person"},
+				{"id": "start", "name": "Start", "type": "date"},
+				{"id": "end", "name": "End", "type": "date"},
+				{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+					map[string]interface{}{"id": "todo-1", "value": "To do"},
+					map[string]interface{}{"id": "done-1", "value": "Done"},
+				}},
+			},
+		},
+		{
+			ID: "board-2",
+			CardProperties: []map[string]interface{}{
+				{"id": "owner", "name": "Owner", "type": "person"},
+				{"id": "reviewers", "name": "Reviewers", "type": "multiPerson"},
+				{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+					map[string]interface{}{"id": "done-2", "value": "done"},
+				}},
+			},
+		},
+		{ID: "board-3"},
+	}
+
+	t.Run("properties of the boards", func(t *testing.T) {
+		query, err := NewUserCardsQuery("user-id", boards, QueryUserCardsOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "user-id", query.UserID)
+		require.Equal(t, []string{"board-1", "board-2", "board-3"}, query.BoardIDs)
+		require.Equal(t, map[string][]string{
+			"owner":     {"board-1", "board-2"},
+			"reviewers": {"board-2"},
+		}, query.PersonProperties)
+		// the first date property of the board holds the due date
+		require.Equal(t, map[string][]string{"start": {"board-1"}}, query.DueProperties)
+		require.Nil(t, query.StatusOptions)
+	})
+
+	t.Run("status options are matched by name", func(t *testing.T) {
+		query, err := NewUserCardsQuery("user-id", boards, QueryUserCardsOptions{Status: " DONE "})
+		require.NoError(t, err)
+		require.Equal(t, []*UserCardsStatusOption{
+			{PropertyID: "status", OptionID: "done-1", BoardIDs: []string{"board-1"}},
+			{PropertyID: "status", OptionID: "done-2", BoardIDs: []string{"board-2"}},
+		}, query.StatusOptions)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		query, err := NewUserCardsQuery("user-id", boards, QueryUserCardsOptions{Status: "Blocked"})
+		require.NoError(t, err)
+		require.Nil(t, query)
+	})
+}
+
+func TestQueryUserCardsOptionsIsValid(t *testing.T) {
+	require.NoError(t, QueryUserCardsOptions{}.IsValid())
+	require.NoError(t, QueryUserCardsOptions{SortBy: UserCardsSortDueAt}.IsValid())
+	require.ErrorIs(t, QueryUserCardsOptions{SortBy: "priority"}.IsValid(), ErrInvalidUserCardsSort)
+}
+
+func TestCardDueAt(t *testing.T) {
+	card := func(value interface{}) *Block {
+		return &Block{Fields: map[string]interface{}{"properties": map[string]interface{}{"due": value}}}
+	}
+
+	require.Equal(t, int64(100), CardDueAt(card(`{"from":100}`), "due"))
+	require.Equal(t, int64(200), CardDueAt(card(`{"from":100,"to":200}`), "due"))
+	require.Zero(t, CardDueAt(card("not a date"), "due"))
+	require.Zero(t, CardDueAt(card(""), "due"))
+	require.Zero(t, CardDueAt(card(`{"from":100}`), "other"))
+	require.Zero(t, CardDueAt(&Block{}, "due"))
+}
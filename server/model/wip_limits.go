@@ -0,0 +1,164 @@
+package model
+
+import (
+	"sort"
+)
+
+const (
+	// BoardPropertyWIPLimitMode is the board property holding how the WIP
+	// limits of the board are enforced.
+	BoardPropertyWIPLimitMode = "wipLimitMode"
+
+	// WIPLimitModeWarn allows changes that exceed a WIP limit. It is the
+	// default mode.
+	WIPLimitModeWarn = "warn"
+
+	// WIPLimitModeStrict rejects changes that exceed a WIP limit, unless
+	// they are made by a board admin.
+	WIPLimitModeStrict = "strict"
+)
+
+// WIPLimitMode returns how the WIP limits of the board are enforced.
+func (b *Board) WIPLimitMode() string {
+	if mode, _ := b.Properties[BoardPropertyWIPLimitMode].(string); mode == WIPLimitModeStrict {
+		return WIPLimitModeStrict
+	}
+	return WIPLimitModeWarn
+}
+
+// WIPLimitStatus is the number of cards that have an option of a select
+// property compared to the WIP limit of the option
+// swagger:model
+type WIPLimitStatus struct {
+	// The ID of the select property
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	// The ID of the option
+	// required: true
+	OptionID string `json:"optionId"`
+
+	// The maximum number of cards allowed
+	// required: true
+	Limit int `json:"limit"`
+
+	// The number of cards with the option
+	// required: true
+	Count int `json:"count"`
+}
+
+// IsExceeded returns true if more cards than allowed have the option.
+func (s *WIPLimitStatus) IsExceeded() bool {
+	return s.Count > s.Limit
+}
+
+// HasWIPLimits returns true if an option of the schema has a WIP limit.
+func (s PropSchema) HasWIPLimits() bool {
+	for _, pd := range s {
+		if pd.Type != "select" {
+			continue
+		}
+		for _, option := range pd.Options {
+			if option.WIPLimit > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WIPLimitStatuses counts the cards of each option with a WIP limit. The
+// statuses are ordered by property and option index.
+func (s PropSchema) WIPLimitStatuses(cards []Block) []*WIPLimitStatus {
+	var statuses []*WIPLimitStatus
+	byOption := map[string]*WIPLimitStatus{}
+	for _, pd := range s {
+		if pd.Type != "select" {
+			continue
+		}
+		for _, option := range pd.Options {
+			if option.WIPLimit <= 0 {
+				continue
+			}
+			status := &WIPLimitStatus{PropertyID: pd.ID, OptionID: option.ID, Limit: option.WIPLimit}
+			statuses = append(statuses, status)
+			byOption[pd.ID+"/"+option.ID] = status
+		}
+	}
+
+	for i := range cards {
+		if cards[i].Type != TypeCard {
+			continue
+		}
+		for propertyID, value := range cardProperties(&cards[i]) {
+			optionID, _ := value.(string)
+			if status, ok := byOption[propertyID+"/"+optionID]; ok {
+				status.Count++
+			}
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		a, b := statuses[i], statuses[j]
+		if a.PropertyID != b.PropertyID {
+			return s[a.PropertyID].Index < s[b.PropertyID].Index
+		}
+		return s[a.PropertyID].Options[a.OptionID].Index < s[b.PropertyID].Options[b.OptionID].Index
+	})
+	return statuses
+}
+
+// ExceededWIPLimits returns the WIP limits exceeded by moving cards into
+// an option. cards are the cards of the board once the change is applied
+// and previous maps the IDs of the changed cards to their version before
+// the change, or nil for new cards. Limits that were already exceeded
+// are only reported if a changed card moved into the option.
+func (s PropSchema) ExceededWIPLimits(cards []Block, changed []Block, previous map[string]*Block) []*WIPLimitStatus {
+	movedInto := map[string]bool{}
+	for i := range changed {
+		if changed[i].Type != TypeCard {
+			continue
+		}
+		var before map[string]interface{}
+		if old := previous[changed[i].ID]; old != nil {
+			before = cardProperties(old)
+		}
+		for propertyID, value := range cardProperties(&changed[i]) {
+			// only select values are strings, and only those have limits
+			optionID, ok := value.(string)
+			if !ok || optionID == "" {
+				continue
+			}
+			if previousID, _ := before[propertyID].(string); previousID != optionID {
+				movedInto[propertyID+"/"+optionID] = true
+			}
+		}
+	}
+	if len(movedInto) == 0 {
+		return nil
+	}
+
+	var exceeded []*WIPLimitStatus
+	for _, status := range s.WIPLimitStatuses(cards) {
+		if status.IsExceeded() && movedInto[status.PropertyID+"/"+status.OptionID] {
+			exceeded = append(exceeded, status)
+		}
+	}
+	return exceeded
+}
+
+func cardProperties(card *Block) map[string]interface{} {
+	properties, _ := card.Fields["properties"].(map[string]interface{})
+	return properties
+}
+
+// IsWIPLimitModeValid returns true if mode is a known WIP limit mode.
+func IsWIPLimitModeValid(mode string) bool {
+	return mode == WIPLimitModeWarn || mode == WIPLimitModeStrict
+}
+
+// ChangesWIPLimitMode returns true if the patch updates or removes the
+// WIP limit mode of the board.
+func (p *BoardPatch) ChangesWIPLimitMode() bool {
+	return p.changesProperty(BoardPropertyWIPLimitMode)
+}
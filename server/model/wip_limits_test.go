@@ -0,0 +1,124 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func wipLimitsTestBoard(mode string) *Board {
+	return &Board{
+		ID: "board-id",
+		Properties: map[string]interface{}{
+			BoardPropertyWIPLimitMode: mode,
+		},
+		CardProperties: []map[string]interface{}{
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "doing", "value": "Doing", "wipLimit": float64(2)},
+				map[string]interface{}{"id": "review", "value": "Review", "wipLimit": float64(1)},
+			}},
+		},
+	}
+}
+
+func wipLimitsTestCard(id, status string) Block {
+	return Block{
+		ID:      id,
+		BoardID: "board-id",
+		Type:    TypeCard,
+		Fields:  map[string]interface{}{"properties": map[string]interface{}{"status": status}},
+	}
+}
+
+func TestWIPLimitMode(t *testing.T) {
+	require.Equal(t, WIPLimitModeStrict, wipLimitsTestBoard(WIPLimitModeStrict).WIPLimitMode())
+	require.Equal(t, WIPLimitModeWarn, wipLimitsTestBoard(WIPLimitModeWarn).WIPLimitMode())
+	require.Equal(t, WIPLimitModeWarn, wipLimitsTestBoard("").WIPLimitMode())
+	require.Equal(t, WIPLimitModeWarn, (&Board{}).WIPLimitMode())
+
+	t.Run("board patch", func(t *testing.T) {
+		patch := &BoardPatch{UpdatedProperties: map[string]interface{}{BoardPropertyWIPLimitMode: WIPLimitModeStrict}}
+		require.True(t, patch.ChangesWIPLimitMode())
+		require.NoError(t, patch.IsValid())
+
+		patch = &BoardPatch{DeletedProperties: []string{BoardPropertyWIPLimitMode}}
+		require.True(t, patch.ChangesWIPLimitMode())
+
+		patch = &BoardPatch{UpdatedProperties: map[string]interface{}{"other": "value"}}
+		require.False(t, patch.ChangesWIPLimitMode())
+
+		patch = &BoardPatch{UpdatedProperties: map[string]interface{}{BoardPropertyWIPLimitMode: "sometimes"}}
+		require.Error(t, patch.IsValid())
+	})
+}
+
+func TestWIPLimitStatuses(t *testing.T) {
+	schema, err := ParsePropertySchema(wipLimitsTestBoard(WIPLimitModeStrict))
+	require.NoError(t, err)
+	require.True(t, schema.HasWIPLimits())
+
+	cards := []Block{
+		wipLimitsTestCard("card1", "doing"),
+		wipLimitsTestCard("card2", "doing"),
+		wipLimitsTestCard("card3", "doing"),
+		wipLimitsTestCard("card4", "todo"),
+		{ID: "view1", Type: TypeView, Fields: map[string]interface{}{"properties": map[string]interface{}{"status": "review"}}},
+	}
+
+	statuses := schema.WIPLimitStatuses(cards)
+	require.Equal(t, []*WIPLimitStatus{
+		{PropertyID: "status", OptionID: "doing", Limit: 2, Count: 3},
+		{PropertyID: "status", OptionID: "review", Limit: 1, Count: 0},
+	}, statuses)
+	require.True(t, statuses[0].IsExceeded())
+	require.False(t, statuses[1].IsExceeded())
+
+	t.Run("no limits", func(t *testing.T) {
+		schema, err := ParsePropertySchema(computedPropertiesTestBoard())
+		require.NoError(t, err)
+		require.False(t, schema.HasWIPLimits())
+		require.Empty(t, schema.WIPLimitStatuses(cards))
+	})
+}
+
+func TestExceededWIPLimits(t *testing.T) {
+	schema, err := ParsePropertySchema(wipLimitsTestBoard(WIPLimitModeStrict))
+	require.NoError(t, err)
+
+	existing := []Block{
+		wipLimitsTestCard("card1", "doing"),
+		wipLimitsTestCard("card2", "doing"),
+		wipLimitsTestCard("card3", "review"),
+	}
+
+	t.Run("new card into a full option", func(t *testing.T) {
+		added := wipLimitsTestCard("card4", "doing")
+		cards := append(append([]Block{}, existing...), added)
+		exceeded := schema.ExceededWIPLimits(cards, []Block{added}, nil)
+		require.Equal(t, []*WIPLimitStatus{{PropertyID: "status", OptionID: "doing", Limit: 2, Count: 3}}, exceeded)
+	})
+
+	t.Run("new card into an option with room", func(t *testing.T) {
+		added := wipLimitsTestCard("card4", "todo")
+		cards := append(append([]Block{}, existing...), added)
+		require.Empty(t, schema.ExceededWIPLimits(cards, []Block{added}, nil))
+	})
+
+	t.Run("card moved into a full option", func(t *testing.T) {
+		moved := wipLimitsTestCard("card1", "review")
+		cards := []Block{moved, existing[1], existing[2]}
+		previous := map[string]*Block{"card1": &existing[0]}
+		exceeded := schema.ExceededWIPLimits(cards, []Block{moved}, previous)
+		require.Equal(t, []*WIPLimitStatus{{PropertyID: "status", OptionID: "review", Limit: 1, Count: 2}}, exceeded)
+	})
+
+	t.Run("card that stays in an exceeded option", func(t *testing.T) {
+		overfull := append(append([]Block{}, existing...), wipLimitsTestCard("card4", "doing"))
+		edited := wipLimitsTestCard("card1", "doing")
+		edited.Title = "renamed"
+		previous := map[string]*Block{"card1": &overfull[0]}
+		require.Empty(t, schema.ExceededWIPLimits(overfull, []Block{edited}, previous))
+	})
+}
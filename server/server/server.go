@@ -19,6 +19,7 @@ import (
 	"github.com/mattermost/focalboard/server/auth"
 	appModel "github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/services/audit/dbtarget"
 	"github.com/mattermost/focalboard/server/services/config"
 	"github.com/mattermost/focalboard/server/services/metrics"
 	"github.com/mattermost/focalboard/server/services/notify"
@@ -26,6 +27,7 @@ import (
 	"github.com/mattermost/focalboard/server/services/scheduler"
 	"github.com/mattermost/focalboard/server/services/store"
 	"github.com/mattermost/focalboard/server/services/store/sqlstore"
+	"github.com/mattermost/focalboard/server/services/store/timerlayer"
 	"github.com/mattermost/focalboard/server/services/telemetry"
 	"github.com/mattermost/focalboard/server/services/webhook"
 	"github.com/mattermost/focalboard/server/utils"
@@ -40,6 +42,10 @@ import (
 const (
 	cleanupSessionTaskFrequency = 10 * time.Minute
 	updateMetricsTaskFrequency  = 15 * time.Minute
+	auditRetentionTaskFrequency = 24 * time.Hour
+	autoArchiveTaskFrequency    = 1 * time.Hour
+	cleanUpOrphanFilesFrequency = 24 * time.Hour
+	rescanFilesFrequency        = 10 * time.Minute
 
 	minSessionExpiryTime = int64(60 * 60 * 24 * 31) // 31 days
 
@@ -58,6 +64,10 @@ type Server struct {
 	metricsServer          *metrics.Service
 	metricsService         *metrics.Metrics
 	metricsUpdaterTask     *scheduler.ScheduledTask
+	auditRetentionTask     *scheduler.ScheduledTask
+	autoArchiveTask        *scheduler.ScheduledTask
+	cleanUpOrphanFilesTask *scheduler.ScheduledTask
+	rescanFilesTask        *scheduler.ScheduledTask
 	auditService           *audit.Audit
 	notificationService    *notify.Service
 	servicesStartStopMutex sync.Mutex
@@ -73,12 +83,24 @@ func New(params Params) (*Server, error) {
 		return nil, err
 	}
 
-	authenticator := auth.New(params.Cfg, params.DBStore, params.PermissionsService)
+	// Init metrics
+	instanceInfo := metrics.InstanceInfo{
+		Version:        appModel.CurrentVersion,
+		BuildNum:       appModel.BuildNumber,
+		Edition:        appModel.Edition,
+		InstallationID: os.Getenv("MM_CLOUD_INSTALLATION_ID"),
+	}
+	metricsService := metrics.NewMetrics(instanceInfo)
+
+	// the timer layer reports the duration of every store call
+	db := timerlayer.New(params.DBStore, metricsService)
+
+	authenticator := auth.New(params.Cfg, db, params.PermissionsService)
 
 	// if no ws adapter is provided, we spin up a websocket server
 	wsAdapter := params.WSAdapter
 	if wsAdapter == nil {
-		wsAdapter = ws.NewServer(authenticator, params.SingleUserToken, params.Cfg.AuthMode == MattermostAuthMod, params.Logger, params.DBStore)
+		wsAdapter = ws.NewServer(authenticator, params.SingleUserToken, params.Cfg.AuthMode == MattermostAuthMod, params.Logger, db, metricsService)
 	}
 
 	filesBackendSettings := filestore.FileBackendSettings{}
@@ -104,15 +126,6 @@ func New(params Params) (*Server, error) {
 
 	webhookClient := webhook.NewClient(params.Cfg, params.Logger)
 
-	// Init metrics
-	instanceInfo := metrics.InstanceInfo{
-		Version:        appModel.CurrentVersion,
-		BuildNum:       appModel.BuildNumber,
-		Edition:        appModel.Edition,
-		InstallationID: os.Getenv("MM_CLOUD_INSTALLATION_ID"),
-	}
-	metricsService := metrics.NewMetrics(instanceInfo)
-
 	// Init audit
 	auditService, errAudit := audit.NewAudit()
 	if errAudit != nil {
@@ -121,16 +134,19 @@ func New(params Params) (*Server, error) {
 	if err := auditService.Configure(params.Cfg.AuditCfgFile, params.Cfg.AuditCfgJSON); err != nil {
 		return nil, fmt.Errorf("unable to initialize the audit service: %w", err)
 	}
+	if params.Cfg.EnableAuditDB {
+		auditService.AddTarget(dbtarget.New(db, params.Logger, metricsService))
+	}
 
 	// Init notification services
-	notificationService, errNotify := initNotificationService(params.NotifyBackends, params.Logger)
+	notificationService, errNotify := initNotificationService(params.NotifyBackends, params.Logger, metricsService)
 	if errNotify != nil {
 		return nil, fmt.Errorf("cannot initialize notification service(s): %w", errNotify)
 	}
 
 	appServices := app.Services{
 		Auth:             authenticator,
-		Store:            params.DBStore,
+		Store:            db,
 		FilesBackend:     filesBackend,
 		Webhook:          webhookClient,
 		Metrics:          metricsService,
@@ -142,7 +158,7 @@ func New(params Params) (*Server, error) {
 	}
 	app := app.New(params.Cfg, wsAdapter, appServices)
 
-	focalboardAPI := api.NewAPI(app, params.SingleUserToken, params.Cfg.AuthMode, params.PermissionsService, params.Logger, auditService, params.IsPlugin)
+	focalboardAPI := api.NewAPI(app, params.SingleUserToken, params.Cfg.AuthMode, params.PermissionsService, params.Logger, auditService, metricsService, params.IsPlugin)
 
 	// Local router for admin APIs
 	localRouter := mux.NewRouter()
@@ -162,7 +178,7 @@ func New(params Params) (*Server, error) {
 	}
 	webServer.AddRoutes(focalboardAPI)
 
-	settings, err := params.DBStore.GetSystemSettings()
+	settings, err := db.GetSystemSettings()
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +187,7 @@ func New(params Params) (*Server, error) {
 	telemetryID := settings["TelemetryID"]
 	if len(telemetryID) == 0 {
 		telemetryID = utils.NewID(utils.IDTypeNone)
-		if err = params.DBStore.SetSystemSetting("TelemetryID", telemetryID); err != nil {
+		if err = db.SetSystemSetting("TelemetryID", telemetryID); err != nil {
 			return nil, err
 		}
 	}
@@ -189,7 +205,7 @@ func New(params Params) (*Server, error) {
 		config:              params.Cfg,
 		wsAdapter:           wsAdapter,
 		webServer:           webServer,
-		store:               params.DBStore,
+		store:               db,
 		filesBackend:        filesBackend,
 		telemetry:           telemetryService,
 		metricsServer:       metrics.NewMetricsServer(params.Cfg.PrometheusAddress, metricsService, params.Logger),
@@ -286,6 +302,39 @@ func (s *Server) Start() error {
 	// metricsUpdater()   Calling this immediately causes integration unit tests to fail.
 	s.metricsUpdaterTask = scheduler.CreateRecurringTask("updateMetrics", metricsUpdater, updateMetricsTaskFrequency)
 
+	// in plugin mode, the audit records are deleted by the data retention
+	// job of Mattermost
+	if s.config.AuthMode != MattermostAuthMod && s.config.EnableAuditDB && s.config.AuditRetentionDays > 0 {
+		s.auditRetentionTask = scheduler.CreateRecurringTask("auditRetention", func() {
+			if _, err := s.app.RunAuditRetention(s.config.AuditRetentionDays); err != nil {
+				s.logger.Error("Unable to delete the expired audit records", mlog.Err(err))
+			}
+		}, auditRetentionTaskFrequency)
+	}
+
+	s.autoArchiveTask = scheduler.CreateRecurringTask("autoArchive", func() {
+		if _, err := s.app.RunAutoArchive(); err != nil {
+			s.logger.Error("Unable to run the auto-archive rules", mlog.Err(err))
+		}
+	}, autoArchiveTaskFrequency)
+
+	// in plugin mode, the files are stored with the ones of Mattermost
+	if s.config.AuthMode != MattermostAuthMod {
+		s.cleanUpOrphanFilesTask = scheduler.CreateRecurringTask("cleanUpOrphanFiles", func() {
+			if _, err := s.app.CleanUpOrphanFiles(); err != nil {
+				s.logger.Error("Unable to clean up the orphan files", mlog.Err(err))
+			}
+		}, cleanUpOrphanFilesFrequency)
+	}
+
+	// the scanner may be enabled while the server runs, so the task runs
+	// even if the uploads aren't scanned yet
+	s.rescanFilesTask = scheduler.CreateRecurringTask("rescanFiles", func() {
+		if _, err := s.app.RescanFiles(); err != nil {
+			s.logger.Error("Unable to scan again the quarantined files", mlog.Err(err))
+		}
+	}, rescanFilesFrequency)
+
 	if s.config.Telemetry {
 		firstRun := utils.GetMillis()
 		s.telemetry.RunTelemetryJob(firstRun)
@@ -327,6 +376,22 @@ func (s *Server) Shutdown() error {
 		s.metricsUpdaterTask.Cancel()
 	}
 
+	if s.auditRetentionTask != nil {
+		s.auditRetentionTask.Cancel()
+	}
+
+	if s.autoArchiveTask != nil {
+		s.autoArchiveTask.Cancel()
+	}
+
+	if s.cleanUpOrphanFilesTask != nil {
+		s.cleanUpOrphanFilesTask.Cancel()
+	}
+
+	if s.rescanFilesTask != nil {
+		s.rescanFilesTask.Cancel()
+	}
+
 	if err := s.telemetry.Shutdown(); err != nil {
 		s.logger.Warn("Error occurred when shutting down telemetry", mlog.Err(err))
 	}
@@ -494,11 +559,11 @@ func initTelemetry(opts telemetryOptions) *telemetry.Service {
 	return telemetryService
 }
 
-func initNotificationService(backends []notify.Backend, logger mlog.LoggerIFace) (*notify.Service, error) {
+func initNotificationService(backends []notify.Backend, logger mlog.LoggerIFace, metricsService *metrics.Metrics) (*notify.Service, error) {
 	loggerBackend := notifylogger.New(logger, mlog.LvlDebug)
 
 	backends = append(backends, loggerBackend)
 
-	service, err := notify.New(logger, backends...)
+	service, err := notify.New(logger, metricsService, backends...)
 	return service, err
 }
@@ -28,9 +28,17 @@ var (
 	LevelRead   = mlog.Level{ID: 1002, Name: "read"}
 )
 
+// RecordTarget is a destination for audit records in addition to the log
+// targets configured via `(*Audit).Configure`, e.g. the database.
+type RecordTarget interface {
+	LogRecord(level mlog.Level, rec *Record)
+	Shutdown() error
+}
+
 // Audit provides auditing service.
 type Audit struct {
 	auditLogger *mlog.Logger
+	targets     []RecordTarget
 }
 
 // NewAudit creates a new Audit instance which can be configured via `(*Audit).Configure`.
@@ -59,9 +67,20 @@ func (a *Audit) Configure(cfgFile string, cfgEscaped string) error {
 // Shutdown shuts down the audit service after making best efforts to flush any
 // remaining records.
 func (a *Audit) Shutdown() error {
+	for _, target := range a.targets {
+		if err := target.Shutdown(); err != nil {
+			a.auditLogger.Error("Error shutting down audit record target", mlog.Err(err))
+		}
+	}
 	return a.auditLogger.Shutdown()
 }
 
+// AddTarget adds a record target which receives every record logged via
+// `(*Audit).LogRecord`. Targets must be added before the service is used.
+func (a *Audit) AddTarget(target RecordTarget) {
+	a.targets = append(a.targets, target)
+}
+
 // LogRecord emits an audit record with complete info.
 func (a *Audit) LogRecord(level mlog.Level, rec *Record) {
 	fields := make([]mlog.Field, 0, 7+len(rec.Meta))
@@ -79,4 +98,8 @@ func (a *Audit) LogRecord(level mlog.Level, rec *Record) {
 	}
 
 	a.auditLogger.Log(level, "audit "+rec.Event, fields...)
+
+	for _, target := range a.targets {
+		target.LogRecord(level, rec)
+	}
 }
@@ -0,0 +1,111 @@
+// Package dbtarget provides an audit record target that persists audit
+// records in the database so they can be queried via the API.
+package dbtarget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+const (
+	queueSize       = 1000
+	poolSize        = 2
+	shutdownTimeout = 5 * time.Second
+)
+
+// Store is the subset of the store used by the target.
+type Store interface {
+	InsertAuditRecord(record *model.AuditRecord) error
+}
+
+// Target writes audit records to the database asynchronously.
+type Target struct {
+	store  Store
+	queue  *utils.CallbackQueue
+	logger mlog.LoggerIFace
+}
+
+// New creates a new database audit record target.
+func New(store Store, logger mlog.LoggerIFace, metrics utils.CallbackQueueMetrics) *Target {
+	return &Target{
+		store:  store,
+		queue:  utils.NewCallbackQueue("auditDBTarget", queueSize, poolSize, logger, metrics),
+		logger: logger,
+	}
+}
+
+// LogRecord converts the audit record and queues it for insertion.
+func (t *Target) LogRecord(level mlog.Level, rec *audit.Record) {
+	record := RecordToModel(level, rec)
+	t.queue.Enqueue(func() error {
+		if err := t.store.InsertAuditRecord(record); err != nil {
+			t.logger.Error("Cannot persist audit record",
+				mlog.String("event", record.Event),
+				mlog.Err(err),
+			)
+			return err
+		}
+		return nil
+	})
+}
+
+// Shutdown flushes any queued records.
+func (t *Target) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if !t.queue.Shutdown(ctx) {
+		return fmt.Errorf("audit database target: %w", ctx.Err())
+	}
+	return nil
+}
+
+// RecordToModel converts an audit record into its persisted form. Well known
+// meta keys are used to populate the team and board ids.
+func RecordToModel(level mlog.Level, rec *audit.Record) *model.AuditRecord {
+	record := &model.AuditRecord{
+		ID:        utils.NewID(utils.IDTypeNone),
+		Level:     level.Name,
+		Event:     rec.Event,
+		Status:    rec.Status,
+		UserID:    rec.UserID,
+		SessionID: rec.SessionID,
+		Client:    rec.Client,
+		IPAddress: rec.IPAddress,
+		APIPath:   rec.APIPath,
+		Meta:      make(map[string]interface{}, len(rec.Meta)),
+		CreateAt:  utils.GetMillis(),
+	}
+
+	for _, meta := range rec.Meta {
+		switch meta.K {
+		case audit.KeyTeamID, "teamID":
+			if teamID, ok := meta.V.(string); ok && teamID != "unknown" {
+				record.TeamID = teamID
+			}
+		case "boardID", "board_id":
+			if boardID, ok := meta.V.(string); ok {
+				record.BoardID = boardID
+			}
+		}
+		record.Meta[meta.K] = serializableValue(meta.V)
+	}
+	return record
+}
+
+// serializableValue makes sure the value can be marshaled to JSON, falling
+// back to its string representation.
+func serializableValue(val interface{}) interface{} {
+	if _, err := json.Marshal(val); err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return val
+}
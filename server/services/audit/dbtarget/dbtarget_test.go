@@ -0,0 +1,67 @@
+package dbtarget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+type testStore struct {
+	records chan *model.AuditRecord
+}
+
+func (ts *testStore) InsertAuditRecord(record *model.AuditRecord) error {
+	ts.records <- record
+	return nil
+}
+
+func TestRecordToModel(t *testing.T) {
+	rec := &audit.Record{
+		APIPath:   "/api/v2/boards/board-id",
+		Event:     "patchBoard",
+		Status:    audit.Success,
+		UserID:    "user-id",
+		SessionID: "session-id",
+		Client:    "client",
+		IPAddress: "127.0.0.1",
+		Meta: []audit.Meta{
+			{K: audit.KeyTeamID, V: "unknown"},
+			{K: "teamID", V: "team-id"},
+			{K: "boardID", V: "board-id"},
+			{K: "channel", V: make(chan int)},
+		},
+	}
+
+	record := RecordToModel(audit.LevelModify, rec)
+	require.NotEmpty(t, record.ID)
+	assert.Equal(t, "mod", record.Level)
+	assert.Equal(t, "patchBoard", record.Event)
+	assert.Equal(t, audit.Success, record.Status)
+	assert.Equal(t, "user-id", record.UserID)
+	assert.Equal(t, "team-id", record.TeamID)
+	assert.Equal(t, "board-id", record.BoardID)
+	assert.Equal(t, "board-id", record.Meta["boardID"])
+	assert.IsType(t, "", record.Meta["channel"], "values that can't be serialized are stored as strings")
+	assert.NotZero(t, record.CreateAt)
+}
+
+func TestTarget(t *testing.T) {
+	logger, err := mlog.NewLogger()
+	require.NoError(t, err)
+
+	store := &testStore{records: make(chan *model.AuditRecord, 1)}
+	target := New(store, logger, nil)
+
+	target.LogRecord(audit.LevelRead, &audit.Record{Event: "getBoard"})
+	require.NoError(t, target.Shutdown())
+
+	record := <-store.records
+	assert.Equal(t, "getBoard", record.Event)
+	assert.Equal(t, "read", record.Level)
+}
@@ -38,6 +38,9 @@ type Configuration struct {
 	FilesS3Config            AmazonS3Config    `json:"filess3config" mapstructure:"filess3config"`
 	FilesPath                string            `json:"filespath" mapstructure:"filespath"`
 	MaxFileSize              int64             `json:"maxfilesize" mapstructure:"mafilesize"`
+	TeamStorageQuota         int64             `json:"teamstoragequota" mapstructure:"teamstoragequota"`
+	FileScanner              string            `json:"file_scanner" mapstructure:"file_scanner"`
+	ClamAVAddress            string            `json:"clamav_address" mapstructure:"clamav_address"`
 	Telemetry                bool              `json:"telemetry" mapstructure:"telemetry"`
 	TelemetryID              string            `json:"telemetryid" mapstructure:"telemetryid"`
 	PrometheusAddress        string            `json:"prometheusaddress" mapstructure:"prometheusaddress"`
@@ -59,8 +62,10 @@ type Configuration struct {
 	LoggingCfgFile string `json:"logging_cfg_file" mapstructure:"logging_cfg_file"`
 	LoggingCfgJSON string `json:"logging_cfg_json" mapstructure:"logging_cfg_json"`
 
-	AuditCfgFile string `json:"audit_cfg_file" mapstructure:"audit_cfg_file"`
-	AuditCfgJSON string `json:"audit_cfg_json" mapstructure:"audit_cfg_json"`
+	AuditCfgFile       string `json:"audit_cfg_file" mapstructure:"audit_cfg_file"`
+	AuditCfgJSON       string `json:"audit_cfg_json" mapstructure:"audit_cfg_json"`
+	EnableAuditDB      bool   `json:"enable_audit_db" mapstructure:"enable_audit_db"`
+	AuditRetentionDays int    `json:"audit_retention_days" mapstructure:"audit_retention_days"`
 
 	NotifyFreqCardSeconds  int `json:"notify_freq_card_seconds" mapstructure:"notify_freq_card_seconds"`
 	NotifyFreqBoardSeconds int `json:"notify_freq_board_seconds" mapstructure:"notify_freq_board_seconds"`
@@ -85,6 +90,9 @@ func ReadConfigFile(configFilePath string) (*Configuration, error) {
 	viper.SetDefault("WebPath", "./pack")
 	viper.SetDefault("FilesPath", "./files")
 	viper.SetDefault("FilesDriver", "local")
+	viper.SetDefault("TeamStorageQuota", 0) // no quota by default
+	viper.SetDefault("file_scanner", "")    // uploads aren't scanned by default
+	viper.SetDefault("clamav_address", "/var/run/clamav/clamd.ctl")
 	viper.SetDefault("Telemetry", true)
 	viper.SetDefault("TelemetryID", "")
 	viper.SetDefault("WebhookUpdate", nil)
@@ -99,8 +107,10 @@ func ReadConfigFile(configFilePath string) (*Configuration, error) {
 	viper.SetDefault("NotifyFreqCardSeconds", 120)    // 2 minutes after last card edit
 	viper.SetDefault("NotifyFreqBoardSeconds", 86400) // 1 day after last card edit
 	viper.SetDefault("EnableDataRetention", false)
-	viper.SetDefault("DataRetentionDays", 365) // 1 year is default
+	viper.SetDefault("data_retention_days", 365) // 1 year is default
 	viper.SetDefault("PrometheusAddress", "")
+	viper.SetDefault("enable_audit_db", false)
+	viper.SetDefault("audit_retention_days", 0) // audit records are kept forever by default
 	viper.SetDefault("TeammateNameDisplay", "username")
 
 	err := viper.ReadInConfig() // Find and read the config file
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readTestConfig(t *testing.T, content string) *Configuration {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	config, err := ReadConfigFile(path)
+	require.NoError(t, err)
+	return config
+}
+
+func TestReadConfigFileDefaults(t *testing.T) {
+	config := readTestConfig(t, `{"serverRoot": "http://localhost:8000"}`)
+
+	require.Equal(t, "http://localhost:8000", config.ServerRoot)
+	require.Equal(t, "./files", config.FilesPath)
+	require.Zero(t, config.TeamStorageQuota)
+	require.Empty(t, config.FileScanner)
+	require.Equal(t, "/var/run/clamav/clamd.ctl", config.ClamAVAddress)
+	require.False(t, config.EnableDataRetention)
+	require.Equal(t, 365, config.DataRetentionDays)
+	require.False(t, config.EnableAuditDB)
+	require.Zero(t, config.AuditRetentionDays)
+}
+
+func TestReadConfigFileSettings(t *testing.T) {
+	config := readTestConfig(t, `{
+		"teamstoragequota": 1048576,
+		"file_scanner": "clamav",
+		"clamav_address": "localhost:3310",
+		"data_retention_days": 30,
+		"enable_audit_db": true,
+		"audit_retention_days": 90
+	}`)
+
+	require.EqualValues(t, 1048576, config.TeamStorageQuota)
+	require.Equal(t, "clamav", config.FileScanner)
+	require.Equal(t, "localhost:3310", config.ClamAVAddress)
+	require.Equal(t, 30, config.DataRetentionDays)
+	require.True(t, config.EnableAuditDB)
+	require.Equal(t, 90, config.AuditRetentionDays)
+}
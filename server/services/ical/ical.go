@@ -0,0 +1,151 @@
+// Package ical writes calendars in the iCalendar format (RFC 5545), as
+// served to the calendar apps subscribing to the feeds of the boards.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	productID = "-//Mattermost//Focalboard//EN"
+
+	// maxLineLength is the maximum length of a line in octets, without
+	// its line break. Longer lines are folded.
+	maxLineLength = 75
+
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+)
+
+// Event is an event of a calendar. The all-day events last from the day of
+// their start to the day before their end.
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	URL          string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	LastModified time.Time
+}
+
+// Calendar is a calendar feed, refreshed by the calendar apps at the given
+// interval.
+type Calendar struct {
+	Name            string
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Write writes the calendar in the iCalendar format.
+func (c *Calendar) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	cw := &contentWriter{w: bw}
+
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:" + productID)
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		cw.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		duration := formatDuration(c.RefreshInterval)
+		cw.line("REFRESH-INTERVAL;VALUE=DURATION:" + duration)
+		cw.line("X-PUBLISHED-TTL:" + duration)
+	}
+	for i := range c.Events {
+		c.Events[i].write(cw)
+	}
+	cw.line("END:VCALENDAR")
+
+	if cw.err != nil {
+		return cw.err
+	}
+	return bw.Flush()
+}
+
+func (e *Event) write(cw *contentWriter) {
+	cw.line("BEGIN:VEVENT")
+	cw.line("UID:" + escapeText(e.UID))
+	cw.line("DTSTAMP:" + e.LastModified.UTC().Format(dateTimeFormat))
+	cw.line("LAST-MODIFIED:" + e.LastModified.UTC().Format(dateTimeFormat))
+	if e.AllDay {
+		cw.line("DTSTART;VALUE=DATE:" + e.Start.UTC().Format(dateFormat))
+		cw.line("DTEND;VALUE=DATE:" + e.End.UTC().Format(dateFormat))
+	} else {
+		cw.line("DTSTART:" + e.Start.UTC().Format(dateTimeFormat))
+		if e.End.After(e.Start) {
+			cw.line("DTEND:" + e.End.UTC().Format(dateTimeFormat))
+		}
+	}
+	cw.line("SUMMARY:" + escapeText(e.Summary))
+	if e.Description != "" {
+		cw.line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	if e.URL != "" {
+		cw.line("URL:" + e.URL)
+	}
+	cw.line("END:VEVENT")
+}
+
+// contentWriter writes the content lines of a calendar, keeping the first
+// error.
+type contentWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes a content line, folded to lines of at most maxLineLength
+// octets that don't split the UTF-8 characters.
+func (cw *contentWriter) line(s string) {
+	if cw.err != nil {
+		return
+	}
+
+	var sb strings.Builder
+	length := 0
+	for _, r := range s {
+		size := utf8.RuneLen(r)
+		if length+size > maxLineLength {
+			// the continuation lines start with a space
+			sb.WriteString("\r\n ")
+			length = 1
+		}
+		sb.WriteRune(r)
+		length += size
+	}
+	sb.WriteString("\r\n")
+
+	_, cw.err = cw.w.WriteString(sb.String())
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`;`, `\;`,
+	`,`, `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// escapeText escapes a value of type TEXT.
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// formatDuration formats a duration of type DURATION, in minutes.
+func formatDuration(d time.Duration) string {
+	minutes := int64(d / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	return fmt.Sprintf("PT%dM", minutes)
+}
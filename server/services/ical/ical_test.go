@@ -0,0 +1,78 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCalendar(t *testing.T) {
+	modified := time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC)
+	calendar := &Calendar{
+		Name:            "Roadmap, Q1",
+		RefreshInterval: 15 * time.Minute,
+		Events: []Event{
+			{
+				UID:          "card-1@focalboard",
+				Summary:      "Launch; then celebrate",
+				Description:  "Assignees: @alice\nsecond line",
+				URL:          "http://localhost/team/team-id/board-id/0/card-1",
+				Start:        time.Date(2022, 1, 14, 12, 0, 0, 0, time.UTC),
+				End:          time.Date(2022, 1, 17, 12, 0, 0, 0, time.UTC),
+				AllDay:       true,
+				LastModified: modified,
+			},
+			{
+				UID:          "card-2@focalboard",
+				Summary:      "Meeting",
+				Start:        time.Date(2022, 1, 14, 15, 0, 0, 0, time.FixedZone("CET", 3600)),
+				End:          time.Date(2022, 1, 14, 15, 0, 0, 0, time.FixedZone("CET", 3600)),
+				LastModified: modified,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, calendar.Write(&buf))
+	content := buf.String()
+
+	require.True(t, strings.HasPrefix(content, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(content, "END:VCALENDAR\r\n"))
+	assert.Contains(t, content, "X-WR-CALNAME:Roadmap\\, Q1\r\n")
+	assert.Contains(t, content, "REFRESH-INTERVAL;VALUE=DURATION:PT15M\r\n")
+	assert.Equal(t, 2, strings.Count(content, "BEGIN:VEVENT\r\n"))
+
+	assert.Contains(t, content, "DTSTART;VALUE=DATE:20220114\r\n")
+	assert.Contains(t, content, "DTEND;VALUE=DATE:20220117\r\n")
+	assert.Contains(t, content, "SUMMARY:Launch\\; then celebrate\r\n")
+	assert.Contains(t, content, "DESCRIPTION:Assignees: @alice\\nsecond line\r\n")
+	assert.Contains(t, content, "LAST-MODIFIED:20220110T083000Z\r\n")
+
+	// the times are in UTC, and an event without duration has no end
+	assert.Contains(t, content, "DTSTART:20220114T140000Z\r\n")
+	assert.NotContains(t, content, "DTEND:")
+}
+
+func TestFoldLines(t *testing.T) {
+	summary := strings.Repeat("é", 100)
+	calendar := &Calendar{Events: []Event{{UID: "card", Summary: summary}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, calendar.Write(&buf))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	unfolded := []string{}
+	for _, line := range lines {
+		require.LessOrEqual(t, len(line), maxLineLength)
+		if strings.HasPrefix(line, " ") {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		unfolded = append(unfolded, line)
+	}
+	assert.Contains(t, unfolded, "SUMMARY:"+summary)
+}
@@ -0,0 +1,285 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// asanaExport is the JSON export of an Asana project, as returned by its
+// API.
+type asanaExport struct {
+	Data []asanaTask `json:"data"`
+}
+
+type asanaTask struct {
+	GID         string     `json:"gid"`
+	Name        string     `json:"name"`
+	Notes       string     `json:"notes"`
+	Completed   bool       `json:"completed"`
+	DueOn       string     `json:"due_on"`
+	DueAt       string     `json:"due_at"`
+	Assignee    *asanaUser `json:"assignee"`
+	Projects    []asanaRef `json:"projects"`
+	Memberships []struct {
+		Project asanaRef `json:"project"`
+		Section asanaRef `json:"section"`
+	} `json:"memberships"`
+	Tags         []asanaRef         `json:"tags"`
+	CustomFields []asanaCustomField `json:"custom_fields"`
+	Subtasks     []asanaTask        `json:"subtasks"`
+}
+
+type asanaRef struct {
+	GID  string `json:"gid"`
+	Name string `json:"name"`
+}
+
+type asanaUser struct {
+	GID   string `json:"gid"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type asanaEnumOption struct {
+	GID   string `json:"gid"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type asanaCustomField struct {
+	GID             string            `json:"gid"`
+	Name            string            `json:"name"`
+	Type            string            `json:"type"`
+	EnumOptions     []asanaEnumOption `json:"enum_options"`
+	EnumValue       *asanaEnumOption  `json:"enum_value"`
+	MultiEnumValues []asanaEnumOption `json:"multi_enum_values"`
+	TextValue       *string           `json:"text_value"`
+	NumberValue     *float64          `json:"number_value"`
+	DisplayValue    *string           `json:"display_value"`
+}
+
+var asanaColors = map[string]string{
+	"red":           "propColorRed",
+	"orange":        "propColorOrange",
+	"yellow-orange": "propColorOrange",
+	"yellow":        "propColorYellow",
+	"yellow-green":  "propColorYellow",
+	"green":         "propColorGreen",
+	"blue-green":    "propColorGreen",
+	"aqua":          "propColorBlue",
+	"blue":          "propColorBlue",
+	"indigo":        "propColorBlue",
+	"purple":        "propColorPurple",
+	"magenta":       "propColorPurple",
+	"hot-pink":      "propColorPink",
+	"pink":          "propColorPink",
+	"cool-gray":     "propColorGray",
+}
+
+type asanaBoard struct {
+	board        *model.Board
+	section      *property
+	assignee     *property
+	due          *property
+	completed    *property
+	tags         *property
+	customFields map[string]*property
+}
+
+// convertAsana converts the JSON export of Asana projects, with a board per
+// project. Sections become the options of a select property.
+func convertAsana(r io.Reader, b *builder) error {
+	var input asanaExport
+	if err := json.NewDecoder(r).Decode(&input); err != nil {
+		return err
+	}
+
+	var projects []asanaRef
+	projectTasks := map[string][]asanaTask{}
+	for _, task := range input.Data {
+		taskProjects := task.Projects
+		if len(taskProjects) == 0 {
+			taskProjects = []asanaRef{{Name: "Asana import"}}
+		}
+		for _, project := range taskProjects {
+			if _, ok := projectTasks[project.GID]; !ok {
+				projects = append(projects, project)
+			}
+			projectTasks[project.GID] = append(projectTasks[project.GID], task)
+		}
+	}
+
+	reportedFields := map[string]bool{}
+	for _, project := range projects {
+		ab := newAsanaBoard(b, project, projectTasks[project.GID])
+		for _, task := range projectTasks[project.GID] {
+			convertAsanaTask(b, ab, project, task, reportedFields)
+		}
+	}
+	return nil
+}
+
+func newAsanaBoard(b *builder, project asanaRef, tasks []asanaTask) *asanaBoard {
+	board := b.addBoard(project.Name, "")
+	ab := &asanaBoard{
+		board:        board,
+		section:      b.addProperty(board, "Section", "select"),
+		assignee:     b.addProperty(board, "Assignee", "person"),
+		due:          b.addProperty(board, "Due date", "date"),
+		completed:    b.addProperty(board, "Completed", "checkbox"),
+		customFields: map[string]*property{},
+	}
+
+	for _, task := range tasks {
+		for _, membership := range task.Memberships {
+			if membership.Project.GID == project.GID && membership.Section.Name != "" {
+				ab.section.addOption(membership.Section.Name, b.nextColor())
+			}
+		}
+		if len(task.Tags) > 0 && ab.tags == nil {
+			ab.tags = b.addProperty(board, "Tags", "multiSelect")
+		}
+		for _, tag := range task.Tags {
+			ab.tags.addOption(tag.Name, b.nextColor())
+		}
+		for _, field := range task.CustomFields {
+			if _, ok := ab.customFields[field.GID]; ok {
+				continue
+			}
+			propertyType, ok := map[string]string{
+				"enum":       "select",
+				"multi_enum": "multiSelect",
+				"text":       "text",
+				"number":     "number",
+			}[field.Type]
+			if !ok {
+				continue
+			}
+			p := b.addProperty(board, field.Name, propertyType)
+			for _, option := range field.EnumOptions {
+				p.addOption(option.Name, asanaColor(b, option.Color))
+			}
+			ab.customFields[field.GID] = p
+		}
+	}
+
+	b.addView(board, ab.section)
+	return ab
+}
+
+func convertAsanaTask(b *builder, ab *asanaBoard, project asanaRef, task asanaTask, reportedFields map[string]bool) {
+	card := b.addCard(ab.board, task.Name)
+
+	for _, membership := range task.Memberships {
+		if membership.Project.GID == project.GID {
+			if optionID := ab.section.option(membership.Section.Name); optionID != "" {
+				setProperty(card, ab.section, optionID)
+			}
+		}
+	}
+
+	if task.Assignee != nil {
+		user := ExternalUser{ID: task.Assignee.GID, FullName: task.Assignee.Name, Email: task.Assignee.Email}
+		if userID := b.resolveUser(user); userID != "" {
+			setProperty(card, ab.assignee, userID)
+		}
+	}
+
+	if task.DueAt != "" || task.DueOn != "" {
+		if due, ok := asanaDueDate(task); ok {
+			setProperty(card, ab.due, dateValue(due))
+		} else {
+			b.report(KindDate, task.GID, task.Name, "invalid due date")
+		}
+	}
+
+	if task.Completed {
+		setProperty(card, ab.completed, "true")
+	}
+
+	if len(task.Tags) > 0 {
+		optionIDs := make([]string, 0, len(task.Tags))
+		for _, tag := range task.Tags {
+			optionIDs = append(optionIDs, ab.tags.option(tag.Name))
+		}
+		setProperty(card, ab.tags, optionIDs)
+	}
+
+	for _, field := range task.CustomFields {
+		p, ok := ab.customFields[field.GID]
+		if !ok {
+			if !reportedFields[field.GID] && field.DisplayValue != nil && *field.DisplayValue != "" {
+				reportedFields[field.GID] = true
+				b.report(KindCustomField, field.GID, field.Name, fmt.Sprintf("%s custom fields are not imported", field.Type))
+			}
+			continue
+		}
+		switch field.Type {
+		case "enum":
+			if field.EnumValue != nil {
+				setProperty(card, p, p.option(field.EnumValue.Name))
+			}
+		case "multi_enum":
+			if len(field.MultiEnumValues) > 0 {
+				optionIDs := make([]string, 0, len(field.MultiEnumValues))
+				for _, value := range field.MultiEnumValues {
+					optionIDs = append(optionIDs, p.option(value.Name))
+				}
+				setProperty(card, p, optionIDs)
+			}
+		case "text":
+			if field.TextValue != nil && *field.TextValue != "" {
+				setProperty(card, p, *field.TextValue)
+			}
+		case "number":
+			if field.NumberValue != nil {
+				setProperty(card, p, strconv.FormatFloat(*field.NumberValue, 'f', -1, 64))
+			}
+		}
+	}
+
+	if task.Notes != "" {
+		b.addContent(ab.board, card, model.TypeText, task.Notes, nil)
+	}
+
+	for _, subtask := range task.Subtasks {
+		b.addContent(ab.board, card, "checkbox", subtask.Name, map[string]interface{}{
+			"value": subtask.Completed,
+		})
+		if subtask.Notes != "" || len(subtask.Subtasks) > 0 {
+			b.report(KindCard, subtask.GID, subtask.Name, "the subtask was imported as a checkbox, without its details")
+		}
+	}
+}
+
+// asanaDueDate returns the due date of a task, which is either a date or a
+// time.
+func asanaDueDate(task asanaTask) (int64, bool) {
+	if task.DueAt != "" {
+		t, err := time.Parse(time.RFC3339, task.DueAt)
+		if err != nil {
+			return 0, false
+		}
+		return t.UnixMilli(), true
+	}
+	if task.DueOn != "" {
+		t, err := time.Parse("2006-01-02", task.DueOn)
+		if err != nil {
+			return 0, false
+		}
+		return t.UnixMilli(), true
+	}
+	return 0, false
+}
+
+func asanaColor(b *builder, color string) string {
+	if propColor, ok := asanaColors[color]; ok {
+		return propColor
+	}
+	return b.nextColor()
+}
@@ -0,0 +1,121 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+const asanaTasksExport = `{"data": [
+	{
+		"gid": "1", "name": "Design the logo", "notes": "Use the new colors", "completed": true,
+		"due_on": "2022-06-15",
+		"assignee": {"gid": "u1", "name": "Jane Doe", "email": "jane@example.com"},
+		"projects": [{"gid": "p1", "name": "Branding"}],
+		"memberships": [{"project": {"gid": "p1", "name": "Branding"}, "section": {"gid": "s1", "name": "Doing"}}],
+		"tags": [{"gid": "t1", "name": "design"}],
+		"custom_fields": [
+			{"gid": "f1", "name": "Priority", "type": "enum",
+			 "enum_options": [{"gid": "o1", "name": "High", "color": "red"}, {"gid": "o2", "name": "Low", "color": "green"}],
+			 "enum_value": {"gid": "o1", "name": "High", "color": "red"}},
+			{"gid": "f2", "name": "Cost", "type": "number", "number_value": 12.5},
+			{"gid": "f3", "name": "Owner", "type": "people", "display_value": "John Smith"}
+		],
+		"subtasks": [
+			{"gid": "2", "name": "Sketch", "completed": true},
+			{"gid": "3", "name": "Review", "completed": false, "notes": "With the team"}
+		]
+	},
+	{
+		"gid": "4", "name": "Print the cards", "due_at": "2022-06-20T14:00:00.000Z",
+		"assignee": {"gid": "u2", "name": "John Smith"},
+		"projects": [{"gid": "p1", "name": "Branding"}],
+		"memberships": [{"project": {"gid": "p1", "name": "Branding"}, "section": {"gid": "s2", "name": "Backlog"}}]
+	},
+	{"gid": "5", "name": "Loose task", "due_on": "tomorrow"}
+]}`
+
+func TestConvertAsana(t *testing.T) {
+	resolver := func(user ExternalUser) string {
+		if user.Email == "jane@example.com" {
+			return "user-jane"
+		}
+		return ""
+	}
+
+	result, err := Convert(FormatAsana, strings.NewReader(asanaTasksExport), Options{ResolveUser: resolver})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Report.Cards)
+
+	t.Run("a board per project", func(t *testing.T) {
+		require.Len(t, result.Boards, 2)
+		require.Equal(t, "Branding", result.Boards[0].Title)
+		require.Equal(t, "Asana import", result.Boards[1].Title)
+	})
+
+	board := result.Boards[0]
+	section := propertyByName(t, board, "Section")
+	assignee := propertyByName(t, board, "Assignee")
+	due := propertyByName(t, board, "Due date")
+	completed := propertyByName(t, board, "Completed")
+	tags := propertyByName(t, board, "Tags")
+	priority := propertyByName(t, board, "Priority")
+	cost := propertyByName(t, board, "Cost")
+
+	cards := cardsByTitle(t, result)
+
+	t.Run("card properties", func(t *testing.T) {
+		card := cards["Design the logo"]
+		require.Equal(t, "Doing", optionValue(section, cardProperty(card, section)))
+		require.Equal(t, "user-jane", cardProperty(card, assignee))
+		require.Equal(t, `{"from":1655251200000}`, cardProperty(card, due))
+		require.Equal(t, "true", cardProperty(card, completed))
+		require.Equal(t, "High", optionValue(priority, cardProperty(card, priority)))
+		require.Equal(t, "12.5", cardProperty(card, cost))
+
+		tagIDs := cardProperty(card, tags).([]string)
+		require.Len(t, tagIDs, 1)
+		require.Equal(t, "design", optionValue(tags, tagIDs[0]))
+
+		other := cards["Print the cards"]
+		require.Equal(t, "Backlog", optionValue(section, cardProperty(other, section)))
+		require.Equal(t, `{"from":1655733600000}`, cardProperty(other, due))
+		require.Nil(t, cardProperty(other, assignee))
+		require.Nil(t, cardProperty(other, completed))
+	})
+
+	t.Run("enum colors", func(t *testing.T) {
+		options := priority["options"].([]interface{})
+		require.Len(t, options, 2)
+		require.Equal(t, "propColorRed", options[0].(map[string]interface{})["color"])
+		require.Equal(t, "propColorGreen", options[1].(map[string]interface{})["color"])
+	})
+
+	t.Run("content", func(t *testing.T) {
+		var texts []string
+		var checkboxes []model.Block
+		for _, child := range childrenOf(result, cards["Design the logo"]) {
+			switch child.Type {
+			case model.TypeText:
+				texts = append(texts, child.Title)
+			case "checkbox":
+				checkboxes = append(checkboxes, child)
+			}
+		}
+		require.Equal(t, []string{"Use the new colors"}, texts)
+		require.Len(t, checkboxes, 2)
+		require.Equal(t, "Sketch", checkboxes[0].Title)
+		require.Equal(t, true, checkboxes[0].Fields["value"])
+		require.Equal(t, "Review", checkboxes[1].Title)
+		require.Equal(t, false, checkboxes[1].Fields["value"])
+	})
+
+	t.Run("report", func(t *testing.T) {
+		require.Equal(t, []string{"John Smith: no matching user, the user was left out"}, reportReasons(result, KindUser))
+		require.Equal(t, []string{"Owner: people custom fields are not imported"}, reportReasons(result, KindCustomField))
+		require.Equal(t, []string{"Review: the subtask was imported as a checkbox, without its details"}, reportReasons(result, KindCard))
+		require.Equal(t, []string{"Loose task: invalid due date"}, reportReasons(result, KindDate))
+	})
+}
@@ -0,0 +1,236 @@
+package importer
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+var optionColors = []string{
+	"propColorGray",
+	"propColorBrown",
+	"propColorOrange",
+	"propColorYellow",
+	"propColorGreen",
+	"propColorBlue",
+	"propColorPurple",
+	"propColorPink",
+	"propColorRed",
+}
+
+// builder accumulates the boards and blocks converted from an export.
+type builder struct {
+	opts       Options
+	result     *Result
+	now        int64
+	colorIndex int
+	users      map[string]string
+	blocks     []*model.Block
+}
+
+func newBuilder(format string, opts Options) *builder {
+	return &builder{
+		opts: opts,
+		result: &Result{
+			Report: &model.ImportReport{
+				Format:   format,
+				BoardIDs: []string{},
+				Items:    []model.ImportReportItem{},
+			},
+		},
+		now:   utils.GetMillis(),
+		users: map[string]string{},
+	}
+}
+
+// property is a card property of a board being built.
+type property struct {
+	ID      string
+	Type    string
+	options map[string]string
+	schema  map[string]interface{}
+}
+
+// option returns the ID of the option with the given value, or an empty
+// string.
+func (p *property) option(value string) string {
+	return p.options[strings.ToLower(strings.TrimSpace(value))]
+}
+
+// addOption adds an option to a select property if it doesn't exist yet.
+func (p *property) addOption(value, color string) string {
+	if id := p.option(value); id != "" {
+		return id
+	}
+	id := utils.NewID(utils.IDTypeNone)
+	p.options[strings.ToLower(strings.TrimSpace(value))] = id
+	options, _ := p.schema["options"].([]interface{})
+	p.schema["options"] = append(options, map[string]interface{}{
+		"id":    id,
+		"value": value,
+		"color": color,
+	})
+	return id
+}
+
+func (b *builder) report(kind, sourceID, title, reason string) {
+	b.result.Report.Items = append(b.result.Report.Items, model.ImportReportItem{
+		Kind:     kind,
+		SourceID: sourceID,
+		Title:    title,
+		Reason:   reason,
+	})
+}
+
+func (b *builder) nextColor() string {
+	color := optionColors[b.colorIndex%len(optionColors)]
+	b.colorIndex++
+	return color
+}
+
+// resolveUser returns the ID of the Focalboard user matching a user of the
+// export. Unknown users are reported once.
+func (b *builder) resolveUser(user ExternalUser) string {
+	key := user.ID + "|" + user.Username + "|" + user.FullName + "|" + user.Email
+	if userID, ok := b.users[key]; ok {
+		return userID
+	}
+
+	var userID string
+	if b.opts.ResolveUser != nil {
+		userID = b.opts.ResolveUser(user)
+	}
+	if userID == "" {
+		b.report(KindUser, user.ID, user.displayName(), "no matching user, the user was left out")
+	}
+	b.users[key] = userID
+	return userID
+}
+
+// addBoard adds a private board.
+func (b *builder) addBoard(title, description string) *model.Board {
+	board := &model.Board{
+		ID:              utils.NewID(utils.IDTypeBoard),
+		Type:            model.BoardTypePrivate,
+		Title:           title,
+		Description:     description,
+		ShowDescription: description != "",
+		CreateAt:        b.now,
+		UpdateAt:        b.now,
+		Properties:      map[string]interface{}{},
+		CardProperties:  []map[string]interface{}{},
+	}
+	b.result.Boards = append(b.result.Boards, board)
+	return board
+}
+
+// addProperty adds a card property to a board.
+func (b *builder) addProperty(board *model.Board, name, propertyType string) *property {
+	p := &property{
+		ID:      utils.NewID(utils.IDTypeNone),
+		Type:    propertyType,
+		options: map[string]string{},
+		schema: map[string]interface{}{
+			"name":    name,
+			"type":    propertyType,
+			"options": []interface{}{},
+		},
+	}
+	p.schema["id"] = p.ID
+	board.CardProperties = append(board.CardProperties, p.schema)
+	return p
+}
+
+// addSelectProperty adds a select or multiSelect property with the given
+// option values, skipping the empty and duplicate ones.
+func (b *builder) addSelectProperty(board *model.Board, name, propertyType string, values []string) *property {
+	p := b.addProperty(board, name, propertyType)
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			p.addOption(value, b.nextColor())
+		}
+	}
+	return p
+}
+
+// addView adds a board view grouped by the given property, if any.
+func (b *builder) addView(board *model.Board, groupBy *property) {
+	fields := map[string]interface{}{
+		"viewType":           "board",
+		"sortOptions":        []interface{}{},
+		"visiblePropertyIds": []interface{}{},
+		"visibleOptionIds":   []interface{}{},
+		"hiddenOptionIds":    []interface{}{},
+		"collapsedOptionIds": []interface{}{},
+		"filter":             map[string]interface{}{"operation": "and", "filters": []interface{}{}},
+		"cardOrder":          []interface{}{},
+		"columnWidths":       map[string]interface{}{},
+		"columnCalculations": map[string]interface{}{},
+		"kanbanCalculations": map[string]interface{}{},
+		"defaultTemplateId":  "",
+	}
+	if groupBy != nil {
+		fields["groupById"] = groupBy.ID
+	}
+	b.addBlock(board, board.ID, model.TypeView, "Board view", fields)
+}
+
+// addCard adds a card to a board.
+func (b *builder) addCard(board *model.Board, title string) *model.Block {
+	b.result.Report.Cards++
+	return b.addBlock(board, board.ID, model.TypeCard, title, map[string]interface{}{
+		"icon":         "",
+		"properties":   map[string]interface{}{},
+		"contentOrder": []interface{}{},
+	})
+}
+
+// addContent adds a content block to a card.
+func (b *builder) addContent(board *model.Board, card *model.Block, blockType model.BlockType, title string, fields map[string]interface{}) *model.Block {
+	block := b.addBlock(board, card.ID, blockType, title, fields)
+	if blockType != model.TypeComment {
+		card.Fields["contentOrder"] = append(card.Fields["contentOrder"].([]interface{}), block.ID)
+	}
+	return block
+}
+
+func (b *builder) addBlock(board *model.Board, parentID string, blockType model.BlockType, title string, fields map[string]interface{}) *model.Block {
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	block := &model.Block{
+		ID:       utils.NewID(model.BlockType2IDType(blockType)),
+		BoardID:  board.ID,
+		ParentID: parentID,
+		Type:     blockType,
+		Title:    title,
+		Fields:   fields,
+		Schema:   1,
+		CreateAt: b.now,
+		UpdateAt: b.now,
+	}
+	b.blocks = append(b.blocks, block)
+	return block
+}
+
+// finish returns the result of the conversion.
+func (b *builder) finish() *Result {
+	b.result.Blocks = make([]model.Block, 0, len(b.blocks))
+	for _, block := range b.blocks {
+		b.result.Blocks = append(b.result.Blocks, *block)
+	}
+	return b.result
+}
+
+// setProperty sets the value of a property of a card.
+func setProperty(card *model.Block, p *property, value interface{}) {
+	card.Fields["properties"].(map[string]interface{})[p.ID] = value
+}
+
+// dateValue returns the value of a date property.
+func dateValue(millis int64) string {
+	b, _ := json.Marshal(map[string]int64{"from": millis})
+	return string(b)
+}
@@ -0,0 +1,133 @@
+// Package importer converts the exports of other project management tools
+// to Focalboard boards and blocks, which are then imported as an archive.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// The formats of the exports that can be imported.
+const (
+	FormatTrello = "trello"
+	FormatJira   = "jira"
+	FormatAsana  = "asana"
+)
+
+// The kinds of the items of an import report.
+const (
+	KindBoard       = "board"
+	KindCard        = "card"
+	KindComment     = "comment"
+	KindAttachment  = "attachment"
+	KindCustomField = "customField"
+	KindUser        = "user"
+	KindDate        = "date"
+)
+
+var ErrNothingToImport = errors.New("the export contains no board")
+
+// ErrUnsupportedFormat is returned when converting an export of an unknown
+// format.
+type ErrUnsupportedFormat struct {
+	Format string
+}
+
+func (e ErrUnsupportedFormat) Error() string {
+	return fmt.Sprintf("unsupported import format %q", e.Format)
+}
+
+// ErrInvalidExport is returned when an export cannot be parsed.
+type ErrInvalidExport struct {
+	Format string
+	Err    error
+}
+
+func (e ErrInvalidExport) Error() string {
+	return fmt.Sprintf("invalid %s export: %v", e.Format, e.Err)
+}
+
+func (e ErrInvalidExport) Unwrap() error {
+	return e.Err
+}
+
+// ExternalUser is a user of an export. Depending on the format, only some of
+// the fields are set.
+type ExternalUser struct {
+	ID       string
+	Username string
+	FullName string
+	Email    string
+}
+
+func (u ExternalUser) displayName() string {
+	if u.FullName != "" {
+		return u.FullName
+	}
+	if u.Username != "" {
+		return u.Username
+	}
+	return u.Email
+}
+
+// UserResolver returns the ID of the Focalboard user matching a user of the
+// export, or an empty string if there is none.
+type UserResolver func(user ExternalUser) string
+
+// Options provides options when converting an export.
+type Options struct {
+	// ResolveUser maps the users of the export, such as the assignees of
+	// the cards. Users are left out if nil.
+	ResolveUser UserResolver
+}
+
+// Result is the converted content of an export.
+type Result struct {
+	Boards []*model.Board
+	Blocks []model.Block
+	Report *model.ImportReport
+}
+
+type converter func(r io.Reader, b *builder) error
+
+var converters = map[string]converter{
+	FormatTrello: convertTrello,
+	FormatJira:   convertJira,
+	FormatAsana:  convertAsana,
+}
+
+// Formats returns the supported formats.
+func Formats() []string {
+	formats := make([]string, 0, len(converters))
+	for format := range converters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Convert converts an export to boards and blocks. The items that cannot be
+// mapped are listed in the report of the result.
+func Convert(format string, r io.Reader, opts Options) (*Result, error) {
+	convert, ok := converters[format]
+	if !ok {
+		return nil, ErrUnsupportedFormat{Format: format}
+	}
+
+	b := newBuilder(format, opts)
+	if err := convert(r, b); err != nil {
+		var invalid ErrInvalidExport
+		if errors.As(err, &invalid) {
+			return nil, err
+		}
+		return nil, ErrInvalidExport{Format: format, Err: err}
+	}
+	if len(b.result.Boards) == 0 {
+		return nil, ErrNothingToImport
+	}
+	return b.finish(), nil
+}
@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+// cardsByTitle returns the cards of a result by title.
+func cardsByTitle(t *testing.T, result *Result) map[string]model.Block {
+	t.Helper()
+	cards := map[string]model.Block{}
+	for _, block := range result.Blocks {
+		if block.Type == model.TypeCard {
+			cards[block.Title] = block
+		}
+	}
+	return cards
+}
+
+// childrenOf returns the blocks of a result whose parent is the given card.
+func childrenOf(result *Result, card model.Block) []model.Block {
+	var children []model.Block
+	for _, block := range result.Blocks {
+		if block.ParentID == card.ID {
+			children = append(children, block)
+		}
+	}
+	return children
+}
+
+// propertyByName returns the schema of a card property of a board.
+func propertyByName(t *testing.T, board *model.Board, name string) map[string]interface{} {
+	t.Helper()
+	for _, p := range board.CardProperties {
+		if p["name"] == name {
+			return p
+		}
+	}
+	require.Failf(t, "missing property", "no property named %s", name)
+	return nil
+}
+
+// optionValue returns the value of the option of a property with the given
+// ID.
+func optionValue(p map[string]interface{}, optionID interface{}) string {
+	for _, option := range p["options"].([]interface{}) {
+		option := option.(map[string]interface{})
+		if option["id"] == optionID {
+			return option["value"].(string)
+		}
+	}
+	return ""
+}
+
+func cardProperty(card model.Block, p map[string]interface{}) interface{} {
+	return card.Fields["properties"].(map[string]interface{})[p["id"].(string)]
+}
+
+func reportReasons(result *Result, kind string) []string {
+	var reasons []string
+	for _, item := range result.Report.Items {
+		if item.Kind == kind {
+			reasons = append(reasons, item.Title+": "+item.Reason)
+		}
+	}
+	return reasons
+}
+
+func TestConvert(t *testing.T) {
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := Convert("monday", strings.NewReader("{}"), Options{})
+		var unsupported ErrUnsupportedFormat
+		require.True(t, errors.As(err, &unsupported))
+		require.Equal(t, "monday", unsupported.Format)
+	})
+
+	t.Run("invalid export", func(t *testing.T) {
+		for _, format := range Formats() {
+			_, err := Convert(format, strings.NewReader("{not an export"), Options{})
+			var invalid ErrInvalidExport
+			require.True(t, errors.As(err, &invalid), format)
+			require.Equal(t, format, invalid.Format)
+		}
+	})
+
+	t.Run("empty export", func(t *testing.T) {
+		_, err := Convert(FormatAsana, strings.NewReader(`{"data": []}`), Options{})
+		require.ErrorIs(t, err, ErrNothingToImport)
+	})
+
+	t.Run("formats", func(t *testing.T) {
+		require.Equal(t, []string{FormatAsana, FormatJira, FormatTrello}, Formats())
+	})
+}
@@ -0,0 +1,266 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+var errMissingSummary = errors.New("missing Summary column")
+
+// jiraDateLayouts are the date formats of the Jira CSV exports, which
+// depend on the settings of the instance.
+var jiraDateLayouts = []string{
+	"02/Jan/06 3:04 PM",
+	"02/Jan/06 15:04",
+	"02/Jan/06",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	time.RFC3339,
+}
+
+// jiraIssue is a row of a Jira CSV export. Columns such as Labels or
+// Comment are repeated for each of their values.
+type jiraIssue map[string][]string
+
+func (i jiraIssue) get(column string) string {
+	for _, value := range i[strings.ToLower(column)] {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func (i jiraIssue) all(column string) []string {
+	var values []string
+	for _, value := range i[strings.ToLower(column)] {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+type jiraBoard struct {
+	board      *model.Board
+	key        *property
+	status     *property
+	priority   *property
+	issueType  *property
+	resolution *property
+	labels     *property
+	assignee   *property
+	reporter   *property
+	due        *property
+	created    *property
+}
+
+// jiraField is a column of the export mapped to a property.
+type jiraField struct {
+	property *property
+	column   string
+}
+
+// convertJira converts a Jira CSV export, with a board per project.
+func convertJira(r io.Reader, b *builder) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return err
+	}
+	columnNames := make([]string, len(header))
+	for i := range header {
+		columnNames[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+		header[i] = strings.ToLower(columnNames[i])
+	}
+
+	hasSummary := false
+	for _, column := range header {
+		hasSummary = hasSummary || column == "summary"
+	}
+	if !hasSummary {
+		return errMissingSummary
+	}
+
+	var issues []jiraIssue
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		issue := jiraIssue{}
+		for i, value := range record {
+			if i < len(header) {
+				issue[header[i]] = append(issue[header[i]], value)
+			}
+		}
+		issues = append(issues, issue)
+	}
+
+	var projects []string
+	projectIssues := map[string][]jiraIssue{}
+	for _, issue := range issues {
+		project := issue.get("Project name")
+		if project == "" {
+			project = "Jira import"
+		}
+		if _, ok := projectIssues[project]; !ok {
+			projects = append(projects, project)
+		}
+		projectIssues[project] = append(projectIssues[project], issue)
+	}
+
+	for _, project := range projects {
+		jb := newJiraBoard(b, project, projectIssues[project])
+		for _, issue := range projectIssues[project] {
+			convertJiraIssue(b, jb, issue)
+		}
+	}
+
+	// custom fields are reported once, if any issue has a value
+	reported := map[string]bool{}
+	for i, column := range header {
+		if !strings.HasPrefix(column, "custom field (") || reported[column] {
+			continue
+		}
+		for _, issue := range issues {
+			if len(issue.all(column)) > 0 {
+				reported[column] = true
+				name := strings.TrimSuffix(columnNames[i][len("custom field ("):], ")")
+				b.report(KindCustomField, "", name, "custom fields are not imported")
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func convertJiraIssue(b *builder, jb *jiraBoard, issue jiraIssue) {
+	key := issue.get("Issue key")
+	title := issue.get("Summary")
+	card := b.addCard(jb.board, title)
+
+	if key != "" {
+		setProperty(card, jb.key, key)
+	}
+	for _, field := range []jiraField{
+		{jb.status, "Status"},
+		{jb.priority, "Priority"},
+		{jb.issueType, "Issue Type"},
+		{jb.resolution, "Resolution"},
+	} {
+		if optionID := field.property.option(issue.get(field.column)); optionID != "" {
+			setProperty(card, field.property, optionID)
+		}
+	}
+	if values := issue.all("Labels"); len(values) > 0 {
+		optionIDs := make([]string, 0, len(values))
+		for _, value := range values {
+			optionIDs = append(optionIDs, jb.labels.option(value))
+		}
+		setProperty(card, jb.labels, optionIDs)
+	}
+
+	for _, field := range []jiraField{{jb.assignee, "Assignee"}, {jb.reporter, "Reporter"}} {
+		name := issue.get(field.column)
+		if name == "" {
+			continue
+		}
+		user := ExternalUser{ID: issue.get(field.column + " Id"), FullName: name}
+		if userID := b.resolveUser(user); userID != "" {
+			setProperty(card, field.property, userID)
+		}
+	}
+
+	for _, field := range []jiraField{{jb.due, "Due date"}, {jb.created, "Created"}} {
+		value := issue.get(field.column)
+		if value == "" {
+			continue
+		}
+		if millis, ok := parseJiraDate(value); ok {
+			setProperty(card, field.property, dateValue(millis))
+		} else {
+			b.report(KindDate, key, title, fmt.Sprintf("invalid %s date %s", strings.ToLower(field.column), value))
+		}
+	}
+
+	if description := issue.get("Description"); description != "" {
+		b.addContent(jb.board, card, model.TypeText, description, nil)
+	}
+
+	// attachments are formatted as date;author;name;url
+	for _, attachment := range issue.all("Attachment") {
+		parts := strings.SplitN(attachment, ";", 4)
+		if len(parts) != 4 {
+			b.report(KindAttachment, key, title, "invalid attachment "+attachment)
+			continue
+		}
+		b.addContent(jb.board, card, model.TypeText, fmt.Sprintf("[%s](%s)", parts[2], parts[3]), nil)
+		b.report(KindAttachment, key, parts[2], "the file was not copied, the card links to it")
+	}
+
+	// comments are formatted as date;author;text
+	for _, comment := range issue.all("Comment") {
+		parts := strings.SplitN(comment, ";", 3)
+		if len(parts) != 3 {
+			b.addContent(jb.board, card, model.TypeComment, comment, nil)
+			continue
+		}
+		date := parts[0]
+		if millis, ok := parseJiraDate(date); ok {
+			date = time.UnixMilli(millis).UTC().Format(time.RFC3339)
+		}
+		b.addContent(jb.board, card, model.TypeComment, commentText(parts[1], date, parts[2]), nil)
+	}
+
+}
+
+func newJiraBoard(b *builder, project string, issues []jiraIssue) *jiraBoard {
+	values := func(column string) []string {
+		var values []string
+		for _, issue := range issues {
+			values = append(values, issue.all(column)...)
+		}
+		return values
+	}
+
+	board := b.addBoard(project, "")
+	jb := &jiraBoard{
+		board:      board,
+		status:     b.addSelectProperty(board, "Status", "select", values("Status")),
+		key:        b.addProperty(board, "Key", "text"),
+		priority:   b.addSelectProperty(board, "Priority", "select", values("Priority")),
+		issueType:  b.addSelectProperty(board, "Type", "select", values("Issue Type")),
+		resolution: b.addSelectProperty(board, "Resolution", "select", values("Resolution")),
+		labels:     b.addSelectProperty(board, "Labels", "multiSelect", values("Labels")),
+		assignee:   b.addProperty(board, "Assignee", "person"),
+		reporter:   b.addProperty(board, "Reporter", "person"),
+		due:        b.addProperty(board, "Due date", "date"),
+		created:    b.addProperty(board, "Created", "date"),
+	}
+	b.addView(board, jb.status)
+	return jb
+}
+
+func parseJiraDate(value string) (int64, bool) {
+	for _, layout := range jiraDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UnixMilli(), true
+		}
+	}
+	return 0, false
+}
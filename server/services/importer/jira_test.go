@@ -0,0 +1,96 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+const jiraExport = "\ufeff" + `Summary,Issue key,Issue Type,Status,Priority,Resolution,Assignee,Reporter,Created,Due date,Labels,Labels,Description,Comment,Comment,Attachment,Project name,Custom field (Story Points)
+Login page,WEB-1,Story,In Progress,High,,Jane Doe,John Smith,01/Jun/22 10:30 AM,15/Jun/22,frontend,auth,The login page,"02/Jun/22 9:00 AM;jdoe;Looks good","03/Jun/22 9:00 AM;jsmith;Ship it",01/Jun/22 10:31 AM;jdoe;mockup.png;https://jira.example.com/mockup.png,Website,3
+Fix crash,WEB-2,Bug,Done,Low,Fixed,,,not a date,,,,,,,,Website,
+Setup CI,OPS-1,Task,To Do,,,,,2022-06-01,,,,,,,,Operations,
+`
+
+func TestConvertJira(t *testing.T) {
+	resolver := func(user ExternalUser) string {
+		if user.FullName == "Jane Doe" {
+			return "user-jane"
+		}
+		return ""
+	}
+
+	result, err := Convert(FormatJira, strings.NewReader(jiraExport), Options{ResolveUser: resolver})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Report.Cards)
+
+	t.Run("a board per project", func(t *testing.T) {
+		require.Len(t, result.Boards, 2)
+		require.Equal(t, "Website", result.Boards[0].Title)
+		require.Equal(t, "Operations", result.Boards[1].Title)
+	})
+
+	board := result.Boards[0]
+	status := propertyByName(t, board, "Status")
+	key := propertyByName(t, board, "Key")
+	issueType := propertyByName(t, board, "Type")
+	priority := propertyByName(t, board, "Priority")
+	resolution := propertyByName(t, board, "Resolution")
+	labels := propertyByName(t, board, "Labels")
+	assignee := propertyByName(t, board, "Assignee")
+	reporter := propertyByName(t, board, "Reporter")
+	created := propertyByName(t, board, "Created")
+	due := propertyByName(t, board, "Due date")
+
+	cards := cardsByTitle(t, result)
+
+	t.Run("card properties", func(t *testing.T) {
+		card := cards["Login page"]
+		require.Equal(t, board.ID, card.BoardID)
+		require.Equal(t, "WEB-1", cardProperty(card, key))
+		require.Equal(t, "In Progress", optionValue(status, cardProperty(card, status)))
+		require.Equal(t, "Story", optionValue(issueType, cardProperty(card, issueType)))
+		require.Equal(t, "High", optionValue(priority, cardProperty(card, priority)))
+		require.Nil(t, cardProperty(card, resolution))
+
+		labelIDs := cardProperty(card, labels).([]string)
+		require.Len(t, labelIDs, 2)
+		require.Equal(t, "frontend", optionValue(labels, labelIDs[0]))
+		require.Equal(t, "auth", optionValue(labels, labelIDs[1]))
+
+		require.Equal(t, "user-jane", cardProperty(card, assignee))
+		require.Nil(t, cardProperty(card, reporter))
+		require.Equal(t, `{"from":1654079400000}`, cardProperty(card, created))
+		require.Equal(t, `{"from":1655251200000}`, cardProperty(card, due))
+
+		require.Equal(t, "Fixed", optionValue(resolution, cardProperty(cards["Fix crash"], resolution)))
+	})
+
+	t.Run("content and comments", func(t *testing.T) {
+		var texts, comments []string
+		for _, child := range childrenOf(result, cards["Login page"]) {
+			switch child.Type {
+			case model.TypeText:
+				texts = append(texts, child.Title)
+			case model.TypeComment:
+				comments = append(comments, child.Title)
+			}
+		}
+		require.Equal(t, []string{"The login page", "[mockup.png](https://jira.example.com/mockup.png)"}, texts)
+		require.Equal(t, []string{"**jdoe** (2022-06-02 09:00): Looks good", "**jsmith** (2022-06-03 09:00): Ship it"}, comments)
+	})
+
+	t.Run("report", func(t *testing.T) {
+		require.Equal(t, []string{"John Smith: no matching user, the user was left out"}, reportReasons(result, KindUser))
+		require.Equal(t, []string{"mockup.png: the file was not copied, the card links to it"}, reportReasons(result, KindAttachment))
+		require.Equal(t, []string{"Story Points: custom fields are not imported"}, reportReasons(result, KindCustomField))
+		require.Equal(t, []string{"Fix crash: invalid created date not a date"}, reportReasons(result, KindDate))
+	})
+}
+
+func TestConvertJiraMissingSummary(t *testing.T) {
+	_, err := Convert(FormatJira, strings.NewReader("Issue key,Status\nWEB-1,Done\n"), Options{})
+	require.ErrorIs(t, err, errMissingSummary)
+}
@@ -0,0 +1,289 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// trelloBoard is the part of a Trello board JSON export that is imported.
+type trelloBoard struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Desc   string `json:"desc"`
+	Labels []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+	Lists []struct {
+		ID   string  `json:"id"`
+		Name string  `json:"name"`
+		Pos  float64 `json:"pos"`
+	} `json:"lists"`
+	Cards      []trelloCard   `json:"cards"`
+	Members    []trelloMember `json:"members"`
+	Checklists []struct {
+		ID         string `json:"id"`
+		IDCard     string `json:"idCard"`
+		Name       string `json:"name"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+	Actions []struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Date string `json:"date"`
+		Data struct {
+			Text string `json:"text"`
+			Card struct {
+				ID string `json:"id"`
+			} `json:"card"`
+		} `json:"data"`
+		MemberCreator trelloMember `json:"memberCreator"`
+	} `json:"actions"`
+	CustomFields []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"customFields"`
+}
+
+type trelloCard struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Desc         string   `json:"desc"`
+	Closed       bool     `json:"closed"`
+	IDList       string   `json:"idList"`
+	IDLabels     []string `json:"idLabels"`
+	IDMembers    []string `json:"idMembers"`
+	IDChecklists []string `json:"idChecklists"`
+	Due          string   `json:"due"`
+	Pos          float64  `json:"pos"`
+	Attachments  []struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		URL      string `json:"url"`
+		IsUpload bool   `json:"isUpload"`
+	} `json:"attachments"`
+	CustomFieldItems []struct {
+		IDCustomField string `json:"idCustomField"`
+	} `json:"customFieldItems"`
+}
+
+type trelloMember struct {
+	ID       string `json:"id"`
+	FullName string `json:"fullName"`
+	Username string `json:"username"`
+}
+
+var trelloColors = map[string]string{
+	"green":  "propColorGreen",
+	"lime":   "propColorGreen",
+	"yellow": "propColorYellow",
+	"orange": "propColorOrange",
+	"red":    "propColorRed",
+	"purple": "propColorPurple",
+	"blue":   "propColorBlue",
+	"sky":    "propColorBlue",
+	"pink":   "propColorPink",
+	"black":  "propColorGray",
+}
+
+// convertTrello converts the JSON export of a Trello board. Lists become
+// the options of a select property and labels the options of a
+// multiSelect one.
+func convertTrello(r io.Reader, b *builder) error {
+	var input trelloBoard
+	if err := json.NewDecoder(r).Decode(&input); err != nil {
+		return err
+	}
+	if input.ID == "" && len(input.Lists) == 0 && len(input.Cards) == 0 {
+		return fmt.Errorf("not a board export: %w", ErrNothingToImport)
+	}
+
+	title := input.Name
+	if title == "" {
+		title = "Trello import"
+	}
+	board := b.addBoard(title, input.Desc)
+
+	sort.SliceStable(input.Lists, func(i, j int) bool { return input.Lists[i].Pos < input.Lists[j].Pos })
+	listProperty := b.addProperty(board, "List", "select")
+	lists := map[string]string{}
+	for _, list := range input.Lists {
+		lists[list.ID] = listProperty.addOption(list.Name, b.nextColor())
+	}
+
+	var labelsProperty *property
+	labels := map[string]string{}
+	if len(input.Labels) > 0 {
+		labelsProperty = b.addProperty(board, "Labels", "multiSelect")
+		for _, label := range input.Labels {
+			name := label.Name
+			if name == "" {
+				name = label.Color
+			}
+			color, ok := trelloColors[label.Color]
+			if !ok {
+				color = b.nextColor()
+			}
+			labels[label.ID] = labelsProperty.addOption(name, color)
+		}
+	}
+
+	var membersProperty *property
+	members := map[string]trelloMember{}
+	if len(input.Members) > 0 {
+		membersProperty = b.addProperty(board, "Members", "multiPerson")
+		for _, member := range input.Members {
+			members[member.ID] = member
+		}
+	}
+
+	var dueProperty *property
+	for _, card := range input.Cards {
+		if card.Due != "" {
+			dueProperty = b.addProperty(board, "Due", "date")
+			break
+		}
+	}
+
+	b.addView(board, listProperty)
+
+	customFields := map[string]string{}
+	for _, field := range input.CustomFields {
+		customFields[field.ID] = field.Name
+		b.report(KindCustomField, field.ID, field.Name, "custom fields are not imported")
+	}
+
+	checklists := map[string]int{}
+	for i, checklist := range input.Checklists {
+		checklists[checklist.ID] = i
+	}
+
+	sort.SliceStable(input.Cards, func(i, j int) bool { return input.Cards[i].Pos < input.Cards[j].Pos })
+	cards := map[string]*model.Block{}
+	for _, c := range input.Cards {
+		card := b.addCard(board, c.Name)
+		cards[c.ID] = card
+		if c.Closed {
+			card.ArchiveAt = b.now
+		}
+
+		if optionID, ok := lists[c.IDList]; ok {
+			setProperty(card, listProperty, optionID)
+		} else {
+			b.report(KindCard, c.ID, c.Name, "unknown list "+c.IDList)
+		}
+
+		if labelsProperty != nil && len(c.IDLabels) > 0 {
+			values := []string{}
+			for _, labelID := range c.IDLabels {
+				if optionID, ok := labels[labelID]; ok {
+					values = append(values, optionID)
+				}
+			}
+			setProperty(card, labelsProperty, values)
+		}
+
+		if membersProperty != nil && len(c.IDMembers) > 0 {
+			userIDs := []string{}
+			for _, memberID := range c.IDMembers {
+				member := members[memberID]
+				user := ExternalUser{ID: memberID, Username: member.Username, FullName: member.FullName}
+				if userID := b.resolveUser(user); userID != "" {
+					userIDs = append(userIDs, userID)
+				}
+			}
+			if len(userIDs) > 0 {
+				setProperty(card, membersProperty, userIDs)
+			}
+		}
+
+		if c.Due != "" {
+			if due, err := time.Parse(time.RFC3339, c.Due); err == nil {
+				setProperty(card, dueProperty, dateValue(due.UnixMilli()))
+			} else {
+				b.report(KindDate, c.ID, c.Name, "invalid due date "+c.Due)
+			}
+		}
+
+		if c.Desc != "" {
+			b.addContent(board, card, model.TypeText, c.Desc, nil)
+		}
+
+		for _, checklistID := range c.IDChecklists {
+			i, ok := checklists[checklistID]
+			if !ok {
+				continue
+			}
+			items := input.Checklists[i].CheckItems
+			sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+			for _, item := range items {
+				b.addContent(board, card, "checkbox", item.Name, map[string]interface{}{
+					"value": item.State == "complete",
+				})
+			}
+		}
+
+		for _, attachment := range c.Attachments {
+			name := attachment.Name
+			if name == "" {
+				name = attachment.URL
+			}
+			b.addContent(board, card, model.TypeText, fmt.Sprintf("[%s](%s)", name, attachment.URL), nil)
+			if attachment.IsUpload {
+				b.report(KindAttachment, attachment.ID, name, "the file was not copied, the card links to it")
+			}
+		}
+
+		reported := map[string]bool{}
+		for _, item := range c.CustomFieldItems {
+			if !reported[item.IDCustomField] {
+				reported[item.IDCustomField] = true
+				b.report(KindCustomField, c.ID, c.Name, "the value of "+customFields[item.IDCustomField]+" was not imported")
+			}
+		}
+	}
+
+	// actions are listed from the most recent one
+	for i := len(input.Actions) - 1; i >= 0; i-- {
+		action := input.Actions[i]
+		if action.Type != "commentCard" {
+			continue
+		}
+		card, ok := cards[action.Data.Card.ID]
+		if !ok {
+			b.report(KindComment, action.ID, "", "the comment is on a card that is not in the export")
+			continue
+		}
+		author := action.MemberCreator.FullName
+		if author == "" {
+			author = action.MemberCreator.Username
+		}
+		b.addContent(board, card, model.TypeComment, commentText(author, action.Date, action.Data.Text), nil)
+	}
+	return nil
+}
+
+// commentText returns the text of an imported comment, which is posted by
+// the importing user, so it is prefixed with its original author and date.
+func commentText(author, date, text string) string {
+	if t, err := time.Parse(time.RFC3339, date); err == nil {
+		date = t.Format("2006-01-02 15:04")
+	}
+	switch {
+	case author != "" && date != "":
+		return fmt.Sprintf("**%s** (%s): %s", author, date, text)
+	case author != "":
+		return fmt.Sprintf("**%s**: %s", author, text)
+	}
+	return text
+}
@@ -0,0 +1,159 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+const trelloExport = `{
+	"id": "board1",
+	"name": "Roadmap",
+	"desc": "Our roadmap",
+	"labels": [
+		{"id": "label1", "name": "Urgent", "color": "red"},
+		{"id": "label2", "name": "", "color": "blue"}
+	],
+	"lists": [
+		{"id": "list2", "name": "Done", "pos": 2},
+		{"id": "list1", "name": "To do", "pos": 1}
+	],
+	"members": [
+		{"id": "member1", "fullName": "Jane Doe", "username": "jane"},
+		{"id": "member2", "fullName": "John Smith", "username": "john"}
+	],
+	"customFields": [
+		{"id": "field1", "name": "Estimate"}
+	],
+	"checklists": [
+		{"id": "checklist1", "idCard": "card1", "name": "Steps", "checkItems": [
+			{"name": "Second", "state": "incomplete", "pos": 2},
+			{"name": "First", "state": "complete", "pos": 1}
+		]}
+	],
+	"cards": [
+		{
+			"id": "card1", "name": "Write the plan", "desc": "The plan", "idList": "list1", "pos": 1,
+			"idLabels": ["label1", "label2"], "idMembers": ["member1", "member2"],
+			"idChecklists": ["checklist1"], "due": "2022-06-01T10:00:00.000Z",
+			"attachments": [{"id": "att1", "name": "plan.pdf", "url": "https://trello.com/plan.pdf", "isUpload": true}],
+			"customFieldItems": [{"idCustomField": "field1"}]
+		},
+		{"id": "card2", "name": "Ship it", "idList": "list2", "pos": 2, "closed": true},
+		{"id": "card3", "name": "Lost", "idList": "missing", "pos": 3}
+	],
+	"actions": [
+		{"id": "action2", "type": "commentCard", "date": "2022-05-02T09:00:00.000Z",
+		 "data": {"text": "Second comment", "card": {"id": "card1"}}, "memberCreator": {"fullName": "Jane Doe"}},
+		{"id": "action1", "type": "commentCard", "date": "2022-05-01T09:00:00.000Z",
+		 "data": {"text": "First comment", "card": {"id": "card1"}}, "memberCreator": {"username": "john"}},
+		{"id": "action0", "type": "updateCard", "date": "2022-04-01T09:00:00.000Z", "data": {"card": {"id": "card1"}}}
+	]
+}`
+
+func TestConvertTrello(t *testing.T) {
+	resolver := func(user ExternalUser) string {
+		if user.Username == "jane" {
+			return "user-jane"
+		}
+		return ""
+	}
+
+	result, err := Convert(FormatTrello, strings.NewReader(trelloExport), Options{ResolveUser: resolver})
+	require.NoError(t, err)
+	require.Len(t, result.Boards, 1)
+	require.Equal(t, 3, result.Report.Cards)
+
+	board := result.Boards[0]
+	require.Equal(t, "Roadmap", board.Title)
+	require.Equal(t, "Our roadmap", board.Description)
+	require.Equal(t, model.BoardTypePrivate, board.Type)
+
+	lists := propertyByName(t, board, "List")
+	options := lists["options"].([]interface{})
+	require.Len(t, options, 2)
+	require.Equal(t, "To do", options[0].(map[string]interface{})["value"])
+	require.Equal(t, "Done", options[1].(map[string]interface{})["value"])
+
+	labels := propertyByName(t, board, "Labels")
+	members := propertyByName(t, board, "Members")
+	due := propertyByName(t, board, "Due")
+
+	var view *model.Block
+	for i := range result.Blocks {
+		if result.Blocks[i].Type == model.TypeView {
+			view = &result.Blocks[i]
+		}
+	}
+	require.NotNil(t, view)
+	require.Equal(t, lists["id"], view.Fields["groupById"])
+
+	cards := cardsByTitle(t, result)
+	require.Len(t, cards, 3)
+
+	t.Run("card properties", func(t *testing.T) {
+		card := cards["Write the plan"]
+		require.Equal(t, "To do", optionValue(lists, cardProperty(card, lists)))
+
+		labelIDs := cardProperty(card, labels).([]string)
+		require.Len(t, labelIDs, 2)
+		require.Equal(t, "Urgent", optionValue(labels, labelIDs[0]))
+		require.Equal(t, "blue", optionValue(labels, labelIDs[1]))
+
+		require.Equal(t, []string{"user-jane"}, cardProperty(card, members))
+		require.Equal(t, `{"from":1654077600000}`, cardProperty(card, due))
+		require.Zero(t, card.ArchiveAt)
+	})
+
+	t.Run("closed cards are archived", func(t *testing.T) {
+		require.NotZero(t, cards["Ship it"].ArchiveAt)
+	})
+
+	t.Run("content and comments", func(t *testing.T) {
+		card := cards["Write the plan"]
+		children := childrenOf(result, card)
+
+		var texts, comments []string
+		var checkboxes []model.Block
+		for _, child := range children {
+			switch child.Type {
+			case model.TypeText:
+				texts = append(texts, child.Title)
+			case model.TypeComment:
+				comments = append(comments, child.Title)
+			case "checkbox":
+				checkboxes = append(checkboxes, child)
+			}
+		}
+		require.Equal(t, []string{"The plan", "[plan.pdf](https://trello.com/plan.pdf)"}, texts)
+		require.Equal(t, []string{
+			"**john** (2022-05-01 09:00): First comment",
+			"**Jane Doe** (2022-05-02 09:00): Second comment",
+		}, comments)
+		require.Len(t, checkboxes, 2)
+		require.Equal(t, "First", checkboxes[0].Title)
+		require.Equal(t, true, checkboxes[0].Fields["value"])
+		require.Equal(t, "Second", checkboxes[1].Title)
+		require.Equal(t, false, checkboxes[1].Fields["value"])
+
+		// comments are not part of the content of the card
+		require.Len(t, card.Fields["contentOrder"], 4)
+	})
+
+	t.Run("report", func(t *testing.T) {
+		require.Equal(t, []string{"John Smith: no matching user, the user was left out"}, reportReasons(result, KindUser))
+		require.Equal(t, []string{"plan.pdf: the file was not copied, the card links to it"}, reportReasons(result, KindAttachment))
+		require.Equal(t, []string{
+			"Estimate: custom fields are not imported",
+			"Write the plan: the value of Estimate was not imported",
+		}, reportReasons(result, KindCustomField))
+		require.Equal(t, []string{"Lost: unknown list missing"}, reportReasons(result, KindCard))
+	})
+}
+
+func TestConvertTrelloNotABoard(t *testing.T) {
+	_, err := Convert(FormatTrello, strings.NewReader(`{"name": "nothing"}`), Options{})
+	require.ErrorIs(t, err, ErrNothingToImport)
+}
@@ -2,6 +2,8 @@ package metrics
 
 import (
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
@@ -12,6 +14,11 @@ const (
 	MetricsSubsystemBlocks = "blocks"
 	MetricsSubsystemTeams  = "teams"
 	MetricsSubsystemSystem = "system"
+	MetricsSubsystemAPI    = "api"
+	MetricsSubsystemWS     = "websocket"
+	MetricsSubsystemQueue  = "callback_queue"
+	MetricsSubsystemNotify = "notifications"
+	MetricsSubsystemDB     = "db"
 
 	MetricsCloudInstallationLabel = "installationId"
 )
@@ -42,6 +49,18 @@ type Metrics struct {
 	teamCount  prometheus.Gauge
 
 	blockLastActivity prometheus.Gauge
+
+	apiRequestDuration *prometheus.HistogramVec
+
+	websocketConnections   prometheus.Gauge
+	websocketBroadcastSize *prometheus.HistogramVec
+
+	callbackQueueLength   *prometheus.GaugeVec
+	callbackQueueDuration *prometheus.HistogramVec
+
+	notificationsDelivered *prometheus.CounterVec
+
+	storeMethodDuration *prometheus.HistogramVec
 }
 
 // NewMetrics Factory method to create a new metrics collector.
@@ -161,6 +180,70 @@ func NewMetrics(info InstanceInfo) *Metrics {
 	})
 	m.registry.MustRegister(m.blockLastActivity)
 
+	m.apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemAPI,
+		Name:        "request_duration_seconds",
+		Help:        "Duration of the API requests by route, method and status code.",
+		ConstLabels: additionalLabels,
+	}, []string{"Route", "Method", "StatusCode"})
+	m.registry.MustRegister(m.apiRequestDuration)
+
+	m.websocketConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemWS,
+		Name:        "connections_active",
+		Help:        "Number of active websocket connections.",
+		ConstLabels: additionalLabels,
+	})
+	m.registry.MustRegister(m.websocketConnections)
+
+	m.websocketBroadcastSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemWS,
+		Name:        "broadcast_listeners",
+		Help:        "Number of listeners that received each websocket broadcast, by action.",
+		Buckets:     []float64{0, 1, 2, 5, 10, 25, 50, 100, 250, 500, 1000},
+		ConstLabels: additionalLabels,
+	}, []string{"Action"})
+	m.registry.MustRegister(m.websocketBroadcastSize)
+
+	m.callbackQueueLength = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemQueue,
+		Name:        "length",
+		Help:        "Number of callbacks waiting to be executed in the queue.",
+		ConstLabels: additionalLabels,
+	}, []string{"Name"})
+	m.registry.MustRegister(m.callbackQueueLength)
+
+	m.callbackQueueDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemQueue,
+		Name:        "callback_duration_seconds",
+		Help:        "Time spent executing the callbacks of the queue.",
+		ConstLabels: additionalLabels,
+	}, []string{"Name"})
+	m.registry.MustRegister(m.callbackQueueDuration)
+
+	m.notificationsDelivered = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemNotify,
+		Name:        "delivered_total",
+		Help:        "Total number of notifications delivered by backend and result.",
+		ConstLabels: additionalLabels,
+	}, []string{"Backend", "Success"})
+	m.registry.MustRegister(m.notificationsDelivered)
+
+	m.storeMethodDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemDB,
+		Name:        "store_method_duration_seconds",
+		Help:        "Duration of the store methods.",
+		ConstLabels: additionalLabels,
+	}, []string{"Method", "Success"})
+	m.registry.MustRegister(m.storeMethodDuration)
+
 	return m
 }
 
@@ -214,3 +297,51 @@ func (m *Metrics) ObserveTeamCount(count int64) {
 		m.teamCount.Set(float64(count))
 	}
 }
+
+func (m *Metrics) ObserveAPIRequestDuration(route, method string, statusCode int, elapsed time.Duration) {
+	if m != nil {
+		m.apiRequestDuration.WithLabelValues(route, method, strconv.Itoa(statusCode)).Observe(elapsed.Seconds())
+	}
+}
+
+func (m *Metrics) IncrementWebsocketConnections() {
+	if m != nil {
+		m.websocketConnections.Inc()
+	}
+}
+
+func (m *Metrics) DecrementWebsocketConnections() {
+	if m != nil {
+		m.websocketConnections.Dec()
+	}
+}
+
+func (m *Metrics) ObserveWebsocketBroadcast(action string, listeners int) {
+	if m != nil {
+		m.websocketBroadcastSize.WithLabelValues(action).Observe(float64(listeners))
+	}
+}
+
+func (m *Metrics) ObserveCallbackQueueLength(name string, length int) {
+	if m != nil {
+		m.callbackQueueLength.WithLabelValues(name).Set(float64(length))
+	}
+}
+
+func (m *Metrics) ObserveCallbackDuration(name string, elapsed time.Duration) {
+	if m != nil {
+		m.callbackQueueDuration.WithLabelValues(name).Observe(elapsed.Seconds())
+	}
+}
+
+func (m *Metrics) IncrementNotificationsDelivered(backend string, success bool) {
+	if m != nil {
+		m.notificationsDelivered.WithLabelValues(backend, strconv.FormatBool(success)).Inc()
+	}
+}
+
+func (m *Metrics) ObserveStoreMethodDuration(method string, success bool, elapsed time.Duration) {
+	if m != nil {
+		m.storeMethodDuration.WithLabelValues(method, strconv.FormatBool(success)).Observe(elapsed.Seconds())
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/metrics"
 	"github.com/wiggin77/merror"
 
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
@@ -43,13 +44,15 @@ type Service struct {
 	mux      sync.RWMutex
 	backends []Backend
 	logger   mlog.LoggerIFace
+	metrics  *metrics.Metrics
 }
 
 // New creates a notification service with one or more Backends capable of sending notifications.
-func New(logger mlog.LoggerIFace, backends ...Backend) (*Service, error) {
+func New(logger mlog.LoggerIFace, metrics *metrics.Metrics, backends ...Backend) (*Service, error) {
 	notify := &Service{
 		backends: make([]Backend, 0, len(backends)),
 		logger:   logger,
+		metrics:  metrics,
 	}
 
 	merr := merror.New()
@@ -96,7 +99,9 @@ func (s *Service) BlockChanged(evt BlockChangeEvent) {
 	defer s.mux.RUnlock()
 
 	for _, backend := range s.backends {
-		if err := backend.BlockChanged(evt); err != nil {
+		err := backend.BlockChanged(evt)
+		s.metrics.IncrementNotificationsDelivered(backend.Name(), err == nil)
+		if err != nil {
 			s.logger.Error("Error delivering notification",
 				mlog.String("backend", backend.Name()),
 				mlog.String("action", string(evt.Action)),
@@ -0,0 +1,127 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// clamAVChunkSize is the size of the chunks streamed to clamd, which
+	// must be below its StreamMaxLength.
+	clamAVChunkSize = 64 * 1024
+
+	clamAVDialTimeout = 5 * time.Second
+	clamAVScanTimeout = 2 * time.Minute
+)
+
+var ErrClamAV = errors.New("clamd error")
+
+// ClamAVScanner scans the files with the INSTREAM command of a clamd daemon,
+// listening on a unix socket or on a TCP address.
+type ClamAVScanner struct {
+	network string
+	address string
+}
+
+// NewClamAVScanner returns a scanner talking to the clamd daemon at address,
+// which is the path of a unix socket if it starts with a slash, and a
+// host:port TCP address otherwise.
+func NewClamAVScanner(address string) *ClamAVScanner {
+	network := "tcp"
+	if strings.HasPrefix(address, "/") {
+		network = "unix"
+	}
+	return &ClamAVScanner{network: network, address: address}
+}
+
+// Ping checks that the daemon is reachable.
+func (s *ClamAVScanner) Ping() error {
+	reply, err := s.command("zPING\x00", nil)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("%w: unexpected reply %q", ErrClamAV, reply)
+	}
+	return nil
+}
+
+// Scan streams the content of a file to the daemon.
+func (s *ClamAVScanner) Scan(r io.Reader) (*Result, error) {
+	reply, err := s.command("zINSTREAM\x00", r)
+	if err != nil {
+		return nil, err
+	}
+	return parseClamAVReply(reply)
+}
+
+// command sends a command to the daemon, followed by the content of r in
+// chunks if it isn't nil, and returns the reply.
+func (s *ClamAVScanner) command(command string, r io.Reader) (string, error) {
+	conn, err := net.DialTimeout(s.network, s.address, clamAVDialTimeout)
+	if err != nil {
+		return "", fmt.Errorf("cannot connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	if err = conn.SetDeadline(time.Now().Add(clamAVScanTimeout)); err != nil {
+		return "", err
+	}
+	if _, err = io.WriteString(conn, command); err != nil {
+		return "", err
+	}
+	if r != nil {
+		if err = writeClamAVChunks(conn, r); err != nil {
+			return "", err
+		}
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return "", fmt.Errorf("cannot read the reply of clamd: %w", err)
+	}
+	return strings.TrimSuffix(reply, "\x00"), nil
+}
+
+// writeClamAVChunks writes the content of r as chunks prefixed by their
+// length, followed by an empty chunk.
+func writeClamAVChunks(w io.Writer, r io.Reader) error {
+	buf := make([]byte, 4+clamAVChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf, uint32(n))
+			if _, writeErr := w.Write(buf[:4+n]); writeErr != nil {
+				return writeErr
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := w.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+// parseClamAVReply parses the reply to a scan, which is "stream: OK" for
+// clean files, "stream: <threat> FOUND" for infected files, and ends with
+// ERROR when the file can't be scanned.
+func parseClamAVReply(reply string) (*Result, error) {
+	status := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case status == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(status, " FOUND"):
+		return &Result{Infected: true, Threat: strings.TrimSuffix(status, " FOUND")}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrClamAV, strings.TrimSpace(reply))
+}
@@ -0,0 +1,148 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClamd answers the clamd commands on a unix socket, finding a threat in
+// the streams containing "EICAR".
+type fakeClamd struct {
+	listener net.Listener
+	streams  chan []byte
+}
+
+func startFakeClamd(t *testing.T) *fakeClamd {
+	listener, err := net.Listen("unix", filepath.Join(t.TempDir(), "clamd.sock"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	d := &fakeClamd{listener: listener, streams: make(chan []byte, 10)}
+	go d.serve()
+	return d
+}
+
+func (d *fakeClamd) serve() {
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			return
+		}
+		d.handle(conn)
+	}
+}
+
+func (d *fakeClamd) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	command, err := r.ReadString(0)
+	if err != nil {
+		return
+	}
+
+	switch command {
+	case "zPING\x00":
+		_, _ = io.WriteString(conn, "PONG\x00")
+	case "zINSTREAM\x00":
+		var stream bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&stream, r, int64(size)); err != nil {
+				return
+			}
+		}
+		d.streams <- stream.Bytes()
+
+		if bytes.Contains(stream.Bytes(), []byte("EICAR")) {
+			_, _ = io.WriteString(conn, "stream: Eicar-Test-Signature FOUND\x00")
+		} else {
+			_, _ = io.WriteString(conn, "stream: OK\x00")
+		}
+	default:
+		_, _ = io.WriteString(conn, "UNKNOWN COMMAND\x00")
+	}
+}
+
+func TestClamAVScanner(t *testing.T) {
+	clamd := startFakeClamd(t)
+	scanner := NewClamAVScanner(clamd.listener.Addr().String())
+
+	t.Run("ping", func(t *testing.T) {
+		require.NoError(t, scanner.Ping())
+	})
+
+	t.Run("clean file", func(t *testing.T) {
+		result, err := scanner.Scan(strings.NewReader("hello"))
+		require.NoError(t, err)
+		assert.False(t, result.Infected)
+		assert.Equal(t, []byte("hello"), <-clamd.streams)
+	})
+
+	t.Run("file larger than a chunk", func(t *testing.T) {
+		content := bytes.Repeat([]byte("0123456789"), clamAVChunkSize/5)
+		result, err := scanner.Scan(bytes.NewReader(content))
+		require.NoError(t, err)
+		assert.False(t, result.Infected)
+		assert.Equal(t, content, <-clamd.streams)
+	})
+
+	t.Run("infected file", func(t *testing.T) {
+		result, err := scanner.Scan(strings.NewReader("X5O!P%@AP EICAR test"))
+		require.NoError(t, err)
+		assert.True(t, result.Infected)
+		assert.Equal(t, "Eicar-Test-Signature", result.Threat)
+		<-clamd.streams
+	})
+
+	t.Run("daemon unreachable", func(t *testing.T) {
+		_, err := NewClamAVScanner(filepath.Join(t.TempDir(), "missing.sock")).Scan(strings.NewReader("hello"))
+		require.Error(t, err)
+	})
+}
+
+func TestParseClamAVReply(t *testing.T) {
+	result, err := parseClamAVReply("stream: OK")
+	require.NoError(t, err)
+	assert.Equal(t, &Result{}, result)
+
+	result, err = parseClamAVReply("stream: Win.Test.EICAR_HDB-1 FOUND")
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Infected: true, Threat: "Win.Test.EICAR_HDB-1"}, result)
+
+	_, err = parseClamAVReply("INSTREAM size limit exceeded. ERROR")
+	require.ErrorIs(t, err, ErrClamAV)
+}
+
+func TestNew(t *testing.T) {
+	s, err := New(TypeNone, "")
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	s, err = New(TypeNoop, "")
+	require.NoError(t, err)
+	result, err := s.Scan(strings.NewReader("EICAR"))
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	s, err = New(TypeClamAV, "localhost:3310")
+	require.NoError(t, err)
+	assert.Equal(t, "tcp", s.(*ClamAVScanner).network)
+
+	_, err = New("other", "")
+	require.ErrorIs(t, err, ErrUnknownScanner)
+}
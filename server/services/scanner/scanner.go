@@ -0,0 +1,57 @@
+// Package scanner scans the uploaded files for malware before they can be
+// downloaded.
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// TypeNone disables the scanning of the uploaded files.
+	TypeNone = ""
+	// TypeNoop marks every file as clean, for tests and development.
+	TypeNoop = "noop"
+	// TypeClamAV scans the files with a clamd daemon.
+	TypeClamAV = "clamav"
+)
+
+var ErrUnknownScanner = errors.New("unknown file scanner")
+
+// Result is the verdict of a scan.
+type Result struct {
+	// Infected is true if malware was found in the file.
+	Infected bool
+	// Threat is the name of the malware found, if any.
+	Threat string
+}
+
+// Scanner scans the content of a file.
+type Scanner interface {
+	Scan(r io.Reader) (*Result, error)
+}
+
+// New returns the scanner of the given type, or nil if the type is
+// TypeNone. The address is used by the scanners talking to a daemon.
+func New(scannerType, address string) (Scanner, error) {
+	switch scannerType {
+	case TypeNone:
+		return nil, nil
+	case TypeNoop:
+		return NoopScanner{}, nil
+	case TypeClamAV:
+		return NewClamAVScanner(address), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownScanner, scannerType)
+}
+
+// NoopScanner reads the files and reports them as clean.
+type NoopScanner struct{}
+
+func (NoopScanner) Scan(r io.Reader) (*Result, error) {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, err
+	}
+	return &Result{}, nil
+}
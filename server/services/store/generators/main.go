@@ -48,6 +48,9 @@ func main() {
 	if err := buildTransactionalStore(); err != nil {
 		log.Fatal(err)
 	}
+	if err := buildTimerLayer(); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func buildTransactionalStore() error {
@@ -63,6 +66,19 @@ func buildTransactionalStore() error {
 	return ioutil.WriteFile(path.Join("sqlstore/public_methods.go"), formatedCode, 0644) //nolint:gosec
 }
 
+func buildTimerLayer() error {
+	code, err := generateLayer("TimerLayer", "timer_layer.go.tmpl")
+	if err != nil {
+		return err
+	}
+	formatedCode, err := format.Source(code)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path.Join("timerlayer/timerlayer.go"), formatedCode, 0644) //nolint:gosec
+}
+
 type methodParam struct {
 	Name string
 	Type string
@@ -80,8 +96,11 @@ type storeMetadata struct {
 }
 
 var blacklistedStoreMethodNames = map[string]bool{
-	"Shutdown": true,
-	"DBType":   true,
+	"Shutdown":         true,
+	"DBType":           true,
+	"GetSchemaVersion": true,
+	"ReadTables":       true,
+	"RestoreTables":    true,
 }
 
 func extractMethodMetadata(method *ast.Field, src []byte) methodData {
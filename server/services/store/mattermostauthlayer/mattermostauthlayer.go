@@ -130,6 +130,14 @@ func (s *MattermostAuthLayer) UpdateUserPasswordByID(userID, password string) er
 	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
 }
 
+func (s *MattermostAuthLayer) DeactivateUser(userID string) error {
+	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
+}
+
+func (s *MattermostAuthLayer) GetAllUsers() ([]*model.User, error) {
+	return nil, store.NewNotSupportedError("list the users using mattermost")
+}
+
 func (s *MattermostAuthLayer) PatchUserProps(userID string, patch model.UserPropPatch) error {
 	user, err := s.servicesAPI.GetUserByID(userID)
 	if err != nil {
@@ -195,6 +203,10 @@ func (s *MattermostAuthLayer) DeleteSession(sessionID string) error {
 	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
 }
 
+func (s *MattermostAuthLayer) DeleteSessionsForUser(userID string) (int64, error) {
+	return 0, store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
+}
+
 func (s *MattermostAuthLayer) CleanUpSessions(expireTime int64) error {
 	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
 }
@@ -517,6 +529,58 @@ func (s *MattermostAuthLayer) SaveFileInfo(fileInfo *mmModel.FileInfo) error {
 	return nil
 }
 
+// UpdateFileInfoImages saves the dimensions, thumbnail and preview of an
+// image file, generated once its scan found it clean.
+func (s *MattermostAuthLayer) UpdateFileInfoImages(fileInfo *mmModel.FileInfo) error {
+	query := s.getQueryBuilder().
+		Update("FileInfo").
+		Set("ThumbnailPath", fileInfo.ThumbnailPath).
+		Set("PreviewPath", fileInfo.PreviewPath).
+		Set("Width", fileInfo.Width).
+		Set("Height", fileInfo.Height).
+		Set("HasPreviewImage", fileInfo.HasPreviewImage).
+		Set("UpdateAt", utils.GetMillis()).
+		Where(sq.Eq{"Id": fileInfo.Id}).
+		Where(sq.Eq{"CreatorId": "boards"})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("failed to update the images of a fileinfo", mlog.String("id", fileInfo.Id), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *MattermostAuthLayer) DeleteFileInfo(id string) error {
+	query := s.getQueryBuilder().
+		Delete("FileInfo").
+		Where(sq.Eq{"Id": id}).
+		Where(sq.Eq{"CreatorId": "boards"})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("failed to delete fileinfo", mlog.String("id", id), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// GetTeamFilesSize returns the total size of the files uploaded in the boards
+// of a team, leaving out the files of the channels stored in the same table.
+func (s *MattermostAuthLayer) GetTeamFilesSize(teamID string) (int64, error) {
+	query := s.getQueryBuilder().
+		Select("COALESCE(SUM(Size), 0)").
+		From("FileInfo").
+		Where(sq.Eq{"CreatorId": "boards"}).
+		Where(sq.Like{"Path": teamID + "/%"}).
+		Where(sq.Eq{"DeleteAt": 0})
+
+	var size int64
+	if err := query.QueryRow().Scan(&size); err != nil {
+		s.logger.Error("failed to get the size of the team files", mlog.String("team_id", teamID), mlog.Err(err))
+		return 0, err
+	}
+	return size, nil
+}
+
 func (s *MattermostAuthLayer) GetLicense() *mmModel.License {
 	return s.servicesAPI.GetLicense()
 }
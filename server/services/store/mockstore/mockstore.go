@@ -109,6 +109,22 @@ func (mr *MockStoreMockRecorder) CreateCategory(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockStore)(nil).CreateCategory), arg0)
 }
 
+// CreateImportedBoardsAndBlocks mocks base method.
+func (m *MockStore) CreateImportedBoardsAndBlocks(arg0 *model.BoardsAndBlocks, arg1 string, arg2 *model.ImportJob) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImportedBoardsAndBlocks", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.BoardsAndBlocks)
+	ret1, _ := ret[1].([]*model.BoardMember)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateImportedBoardsAndBlocks indicates an expected call of CreateImportedBoardsAndBlocks.
+func (mr *MockStoreMockRecorder) CreateImportedBoardsAndBlocks(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImportedBoardsAndBlocks", reflect.TypeOf((*MockStore)(nil).CreateImportedBoardsAndBlocks), arg0, arg1, arg2)
+}
+
 // CreateSession mocks base method.
 func (m *MockStore) CreateSession(arg0 *model.Session) error {
 	m.ctrl.T.Helper()
@@ -166,6 +182,35 @@ func (mr *MockStoreMockRecorder) DBType() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DBType", reflect.TypeOf((*MockStore)(nil).DBType))
 }
 
+// DeactivateUser mocks base method.
+func (m *MockStore) DeactivateUser(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateUser", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateUser indicates an expected call of DeactivateUser.
+func (mr *MockStoreMockRecorder) DeactivateUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateUser", reflect.TypeOf((*MockStore)(nil).DeactivateUser), arg0)
+}
+
+// DeleteAuditRecordsBefore mocks base method.
+func (m *MockStore) DeleteAuditRecordsBefore(arg0 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuditRecordsBefore", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAuditRecordsBefore indicates an expected call of DeleteAuditRecordsBefore.
+func (mr *MockStoreMockRecorder) DeleteAuditRecordsBefore(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuditRecordsBefore", reflect.TypeOf((*MockStore)(nil).DeleteAuditRecordsBefore), arg0)
+}
+
 // DeleteBlock mocks base method.
 func (m *MockStore) DeleteBlock(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -180,6 +225,20 @@ func (mr *MockStoreMockRecorder) DeleteBlock(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlock", reflect.TypeOf((*MockStore)(nil).DeleteBlock), arg0, arg1)
 }
 
+// DeleteBlocks mocks base method.
+func (m *MockStore) DeleteBlocks(arg0 []string, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlocks", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBlocks indicates an expected call of DeleteBlocks.
+func (mr *MockStoreMockRecorder) DeleteBlocks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlocks", reflect.TypeOf((*MockStore)(nil).DeleteBlocks), arg0, arg1)
+}
+
 // DeleteBoard mocks base method.
 func (m *MockStore) DeleteBoard(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -222,6 +281,48 @@ func (mr *MockStoreMockRecorder) DeleteCategory(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockStore)(nil).DeleteCategory), arg0, arg1, arg2)
 }
 
+// DeleteFeedToken mocks base method.
+func (m *MockStore) DeleteFeedToken(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFeedToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFeedToken indicates an expected call of DeleteFeedToken.
+func (mr *MockStoreMockRecorder) DeleteFeedToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFeedToken", reflect.TypeOf((*MockStore)(nil).DeleteFeedToken), arg0)
+}
+
+// DeleteFileInfo mocks base method.
+func (m *MockStore) DeleteFileInfo(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFileInfo", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFileInfo indicates an expected call of DeleteFileInfo.
+func (mr *MockStoreMockRecorder) DeleteFileInfo(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFileInfo", reflect.TypeOf((*MockStore)(nil).DeleteFileInfo), arg0)
+}
+
+// DeleteFileScan mocks base method.
+func (m *MockStore) DeleteFileScan(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFileScan", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFileScan indicates an expected call of DeleteFileScan.
+func (mr *MockStoreMockRecorder) DeleteFileScan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFileScan", reflect.TypeOf((*MockStore)(nil).DeleteFileScan), arg0, arg1)
+}
+
 // DeleteMember mocks base method.
 func (m *MockStore) DeleteMember(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -264,6 +365,21 @@ func (mr *MockStoreMockRecorder) DeleteSession(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockStore)(nil).DeleteSession), arg0)
 }
 
+// DeleteSessionsForUser mocks base method.
+func (m *MockStore) DeleteSessionsForUser(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSessionsForUser", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSessionsForUser indicates an expected call of DeleteSessionsForUser.
+func (mr *MockStoreMockRecorder) DeleteSessionsForUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessionsForUser", reflect.TypeOf((*MockStore)(nil).DeleteSessionsForUser), arg0)
+}
+
 // DeleteSubscription mocks base method.
 func (m *MockStore) DeleteSubscription(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -278,6 +394,20 @@ func (mr *MockStoreMockRecorder) DeleteSubscription(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockStore)(nil).DeleteSubscription), arg0, arg1)
 }
 
+// DeleteTemplate mocks base method.
+func (m *MockStore) DeleteTemplate(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplate", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplate indicates an expected call of DeleteTemplate.
+func (mr *MockStoreMockRecorder) DeleteTemplate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplate", reflect.TypeOf((*MockStore)(nil).DeleteTemplate), arg0)
+}
+
 // DuplicateBlock mocks base method.
 func (m *MockStore) DuplicateBlock(arg0, arg1, arg2 string, arg3 bool) ([]model.Block, error) {
 	m.ctrl.T.Helper()
@@ -339,6 +469,36 @@ func (mr *MockStoreMockRecorder) GetAllTeams() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTeams", reflect.TypeOf((*MockStore)(nil).GetAllTeams))
 }
 
+// GetAllUsers mocks base method.
+func (m *MockStore) GetAllUsers() ([]*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllUsers")
+	ret0, _ := ret[0].([]*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllUsers indicates an expected call of GetAllUsers.
+func (mr *MockStoreMockRecorder) GetAllUsers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockStore)(nil).GetAllUsers))
+}
+
+// GetAuditRecords mocks base method.
+func (m *MockStore) GetAuditRecords(arg0 model.QueryAuditRecordsOptions) ([]*model.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditRecords", arg0)
+	ret0, _ := ret[0].([]*model.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditRecords indicates an expected call of GetAuditRecords.
+func (mr *MockStoreMockRecorder) GetAuditRecords(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditRecords", reflect.TypeOf((*MockStore)(nil).GetAuditRecords), arg0)
+}
+
 // GetBlock mocks base method.
 func (m *MockStore) GetBlock(arg0 string) (*model.Block, error) {
 	m.ctrl.T.Helper()
@@ -369,6 +529,21 @@ func (mr *MockStoreMockRecorder) GetBlockCountsByType() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockCountsByType", reflect.TypeOf((*MockStore)(nil).GetBlockCountsByType))
 }
 
+// GetBlockFileIDs mocks base method.
+func (m *MockStore) GetBlockFileIDs() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockFileIDs")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockFileIDs indicates an expected call of GetBlockFileIDs.
+func (mr *MockStoreMockRecorder) GetBlockFileIDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockFileIDs", reflect.TypeOf((*MockStore)(nil).GetBlockFileIDs))
+}
+
 // GetBlockHistory mocks base method.
 func (m *MockStore) GetBlockHistory(arg0 string, arg1 model.QueryBlockHistoryOptions) ([]model.Block, error) {
 	m.ctrl.T.Helper()
@@ -399,6 +574,21 @@ func (mr *MockStoreMockRecorder) GetBlockHistoryDescendants(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHistoryDescendants", reflect.TypeOf((*MockStore)(nil).GetBlockHistoryDescendants), arg0, arg1)
 }
 
+// GetBlockTombstones mocks base method.
+func (m *MockStore) GetBlockTombstones(arg0 string, arg1 int64) ([]model.BlockTombstone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockTombstones", arg0, arg1)
+	ret0, _ := ret[0].([]model.BlockTombstone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockTombstones indicates an expected call of GetBlockTombstones.
+func (mr *MockStoreMockRecorder) GetBlockTombstones(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockTombstones", reflect.TypeOf((*MockStore)(nil).GetBlockTombstones), arg0, arg1)
+}
+
 // GetBlocksByIDs mocks base method.
 func (m *MockStore) GetBlocksByIDs(arg0 []string) ([]model.Block, error) {
 	m.ctrl.T.Helper()
@@ -429,6 +619,21 @@ func (mr *MockStoreMockRecorder) GetBlocksForBoard(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocksForBoard", reflect.TypeOf((*MockStore)(nil).GetBlocksForBoard), arg0)
 }
 
+// GetBlocksPage mocks base method.
+func (m *MockStore) GetBlocksPage(arg0 string, arg1 model.QueryBlocksPageOptions) ([]model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlocksPage", arg0, arg1)
+	ret0, _ := ret[0].([]model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlocksPage indicates an expected call of GetBlocksPage.
+func (mr *MockStoreMockRecorder) GetBlocksPage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocksPage", reflect.TypeOf((*MockStore)(nil).GetBlocksPage), arg0, arg1)
+}
+
 // GetBlocksWithBoardID mocks base method.
 func (m *MockStore) GetBlocksWithBoardID(arg0 string) ([]model.Block, error) {
 	m.ctrl.T.Helper()
@@ -566,6 +771,21 @@ func (mr *MockStoreMockRecorder) GetBoardMemberHistory(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardMemberHistory", reflect.TypeOf((*MockStore)(nil).GetBoardMemberHistory), arg0, arg1, arg2)
 }
 
+// GetBoardsByIDs mocks base method.
+func (m *MockStore) GetBoardsByIDs(arg0 []string) ([]*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardsByIDs", arg0)
+	ret0, _ := ret[0].([]*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardsByIDs indicates an expected call of GetBoardsByIDs.
+func (mr *MockStoreMockRecorder) GetBoardsByIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardsByIDs", reflect.TypeOf((*MockStore)(nil).GetBoardsByIDs), arg0)
+}
+
 // GetBoardsForUserAndTeam mocks base method.
 func (m *MockStore) GetBoardsForUserAndTeam(arg0, arg1 string) ([]*model.Board, error) {
 	m.ctrl.T.Helper()
@@ -581,6 +801,21 @@ func (mr *MockStoreMockRecorder) GetBoardsForUserAndTeam(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardsForUserAndTeam", reflect.TypeOf((*MockStore)(nil).GetBoardsForUserAndTeam), arg0, arg1)
 }
 
+// GetBoardsInTeam mocks base method.
+func (m *MockStore) GetBoardsInTeam(arg0 string) ([]*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardsInTeam", arg0)
+	ret0, _ := ret[0].([]*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardsInTeam indicates an expected call of GetBoardsInTeam.
+func (mr *MockStoreMockRecorder) GetBoardsInTeam(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardsInTeam", reflect.TypeOf((*MockStore)(nil).GetBoardsInTeam), arg0)
+}
+
 // GetBoardsInTeamByIds mocks base method.
 func (m *MockStore) GetBoardsInTeamByIds(arg0 []string, arg1 string) ([]*model.Board, error) {
 	m.ctrl.T.Helper()
@@ -596,6 +831,36 @@ func (mr *MockStoreMockRecorder) GetBoardsInTeamByIds(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardsInTeamByIds", reflect.TypeOf((*MockStore)(nil).GetBoardsInTeamByIds), arg0, arg1)
 }
 
+// GetBoardsWithAutoArchiveRules mocks base method.
+func (m *MockStore) GetBoardsWithAutoArchiveRules() ([]*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardsWithAutoArchiveRules")
+	ret0, _ := ret[0].([]*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardsWithAutoArchiveRules indicates an expected call of GetBoardsWithAutoArchiveRules.
+func (mr *MockStoreMockRecorder) GetBoardsWithAutoArchiveRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardsWithAutoArchiveRules", reflect.TypeOf((*MockStore)(nil).GetBoardsWithAutoArchiveRules))
+}
+
+// GetCardHistory mocks base method.
+func (m *MockStore) GetCardHistory(arg0 string, arg1 []string, arg2 int64) ([]*model.CardHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.CardHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardHistory indicates an expected call of GetCardHistory.
+func (mr *MockStoreMockRecorder) GetCardHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardHistory", reflect.TypeOf((*MockStore)(nil).GetCardHistory), arg0, arg1, arg2)
+}
+
 // GetCardLimitTimestamp mocks base method.
 func (m *MockStore) GetCardLimitTimestamp() (int64, error) {
 	m.ctrl.T.Helper()
@@ -611,6 +876,21 @@ func (mr *MockStoreMockRecorder) GetCardLimitTimestamp() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardLimitTimestamp", reflect.TypeOf((*MockStore)(nil).GetCardLimitTimestamp))
 }
 
+// GetCardsForUser mocks base method.
+func (m *MockStore) GetCardsForUser(arg0 *model.UserCardsQuery) ([]model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardsForUser", arg0)
+	ret0, _ := ret[0].([]model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardsForUser indicates an expected call of GetCardsForUser.
+func (mr *MockStoreMockRecorder) GetCardsForUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardsForUser", reflect.TypeOf((*MockStore)(nil).GetCardsForUser), arg0)
+}
+
 // GetCategory mocks base method.
 func (m *MockStore) GetCategory(arg0 string) (*model.Category, error) {
 	m.ctrl.T.Helper()
@@ -656,6 +936,51 @@ func (mr *MockStoreMockRecorder) GetCloudLimits() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCloudLimits", reflect.TypeOf((*MockStore)(nil).GetCloudLimits))
 }
 
+// GetDefaultSharedCategory mocks base method.
+func (m *MockStore) GetDefaultSharedCategory(arg0 string) (*model.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefaultSharedCategory", arg0)
+	ret0, _ := ret[0].(*model.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDefaultSharedCategory indicates an expected call of GetDefaultSharedCategory.
+func (mr *MockStoreMockRecorder) GetDefaultSharedCategory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultSharedCategory", reflect.TypeOf((*MockStore)(nil).GetDefaultSharedCategory), arg0)
+}
+
+// GetFeedToken mocks base method.
+func (m *MockStore) GetFeedToken(arg0 string) (*model.FeedToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedToken", arg0)
+	ret0, _ := ret[0].(*model.FeedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedToken indicates an expected call of GetFeedToken.
+func (mr *MockStoreMockRecorder) GetFeedToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedToken", reflect.TypeOf((*MockStore)(nil).GetFeedToken), arg0)
+}
+
+// GetFeedTokenByToken mocks base method.
+func (m *MockStore) GetFeedTokenByToken(arg0 string) (*model.FeedToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedTokenByToken", arg0)
+	ret0, _ := ret[0].(*model.FeedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedTokenByToken indicates an expected call of GetFeedTokenByToken.
+func (mr *MockStoreMockRecorder) GetFeedTokenByToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedTokenByToken", reflect.TypeOf((*MockStore)(nil).GetFeedTokenByToken), arg0)
+}
+
 // GetFileInfo mocks base method.
 func (m *MockStore) GetFileInfo(arg0 string) (*model0.FileInfo, error) {
 	m.ctrl.T.Helper()
@@ -671,6 +996,66 @@ func (mr *MockStoreMockRecorder) GetFileInfo(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileInfo", reflect.TypeOf((*MockStore)(nil).GetFileInfo), arg0)
 }
 
+// GetFileInfosCreatedBefore mocks base method.
+func (m *MockStore) GetFileInfosCreatedBefore(arg0 int64) ([]*model0.FileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileInfosCreatedBefore", arg0)
+	ret0, _ := ret[0].([]*model0.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileInfosCreatedBefore indicates an expected call of GetFileInfosCreatedBefore.
+func (mr *MockStoreMockRecorder) GetFileInfosCreatedBefore(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileInfosCreatedBefore", reflect.TypeOf((*MockStore)(nil).GetFileInfosCreatedBefore), arg0)
+}
+
+// GetFileScan mocks base method.
+func (m *MockStore) GetFileScan(arg0, arg1 string) (*model.FileScan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileScan", arg0, arg1)
+	ret0, _ := ret[0].(*model.FileScan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileScan indicates an expected call of GetFileScan.
+func (mr *MockStoreMockRecorder) GetFileScan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileScan", reflect.TypeOf((*MockStore)(nil).GetFileScan), arg0, arg1)
+}
+
+// GetImportJob mocks base method.
+func (m *MockStore) GetImportJob(arg0 string) (*model.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportJob", arg0)
+	ret0, _ := ret[0].(*model.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportJob indicates an expected call of GetImportJob.
+func (mr *MockStoreMockRecorder) GetImportJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportJob", reflect.TypeOf((*MockStore)(nil).GetImportJob), arg0)
+}
+
+// GetImportJobsForUser mocks base method.
+func (m *MockStore) GetImportJobsForUser(arg0, arg1 string) ([]*model.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportJobsForUser", arg0, arg1)
+	ret0, _ := ret[0].([]*model.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportJobsForUser indicates an expected call of GetImportJobsForUser.
+func (mr *MockStoreMockRecorder) GetImportJobsForUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportJobsForUser", reflect.TypeOf((*MockStore)(nil).GetImportJobsForUser), arg0, arg1)
+}
+
 // GetLicense mocks base method.
 func (m *MockStore) GetLicense() *model0.License {
 	m.ctrl.T.Helper()
@@ -775,6 +1160,21 @@ func (mr *MockStoreMockRecorder) GetRegisteredUserCount() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredUserCount", reflect.TypeOf((*MockStore)(nil).GetRegisteredUserCount))
 }
 
+// GetSchemaVersion mocks base method.
+func (m *MockStore) GetSchemaVersion() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchemaVersion")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchemaVersion indicates an expected call of GetSchemaVersion.
+func (mr *MockStoreMockRecorder) GetSchemaVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchemaVersion", reflect.TypeOf((*MockStore)(nil).GetSchemaVersion))
+}
+
 // GetSession mocks base method.
 func (m *MockStore) GetSession(arg0 string, arg1 int64) (*model.Session, error) {
 	m.ctrl.T.Helper()
@@ -790,6 +1190,36 @@ func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
 }
 
+// GetSharedCategories mocks base method.
+func (m *MockStore) GetSharedCategories(arg0 string) ([]model.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedCategories", arg0)
+	ret0, _ := ret[0].([]model.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedCategories indicates an expected call of GetSharedCategories.
+func (mr *MockStoreMockRecorder) GetSharedCategories(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedCategories", reflect.TypeOf((*MockStore)(nil).GetSharedCategories), arg0)
+}
+
+// GetSharedCategoryBoards mocks base method.
+func (m *MockStore) GetSharedCategoryBoards(arg0 string) ([]model.CategoryBoards, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedCategoryBoards", arg0)
+	ret0, _ := ret[0].([]model.CategoryBoards)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedCategoryBoards indicates an expected call of GetSharedCategoryBoards.
+func (mr *MockStoreMockRecorder) GetSharedCategoryBoards(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedCategoryBoards", reflect.TypeOf((*MockStore)(nil).GetSharedCategoryBoards), arg0)
+}
+
 // GetSharing mocks base method.
 func (m *MockStore) GetSharing(arg0 string) (*model.Sharing, error) {
 	m.ctrl.T.Helper()
@@ -955,6 +1385,21 @@ func (mr *MockStoreMockRecorder) GetTeamCount() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamCount", reflect.TypeOf((*MockStore)(nil).GetTeamCount))
 }
 
+// GetTeamFilesSize mocks base method.
+func (m *MockStore) GetTeamFilesSize(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamFilesSize", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamFilesSize indicates an expected call of GetTeamFilesSize.
+func (mr *MockStoreMockRecorder) GetTeamFilesSize(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamFilesSize", reflect.TypeOf((*MockStore)(nil).GetTeamFilesSize), arg0)
+}
+
 // GetTeamsForUser mocks base method.
 func (m *MockStore) GetTeamsForUser(arg0 string) ([]*model.Team, error) {
 	m.ctrl.T.Helper()
@@ -970,6 +1415,21 @@ func (mr *MockStoreMockRecorder) GetTeamsForUser(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamsForUser", reflect.TypeOf((*MockStore)(nil).GetTeamsForUser), arg0)
 }
 
+// GetTemplate mocks base method.
+func (m *MockStore) GetTemplate(arg0 string) (*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplate", arg0)
+	ret0, _ := ret[0].(*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplate indicates an expected call of GetTemplate.
+func (mr *MockStoreMockRecorder) GetTemplate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplate", reflect.TypeOf((*MockStore)(nil).GetTemplate), arg0)
+}
+
 // GetTemplateBoards mocks base method.
 func (m *MockStore) GetTemplateBoards(arg0, arg1 string) ([]*model.Board, error) {
 	m.ctrl.T.Helper()
@@ -985,6 +1445,96 @@ func (mr *MockStoreMockRecorder) GetTemplateBoards(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBoards", reflect.TypeOf((*MockStore)(nil).GetTemplateBoards), arg0, arg1)
 }
 
+// GetTemplateDerivedBoard mocks base method.
+func (m *MockStore) GetTemplateDerivedBoard(arg0 string) (*model.TemplateDerivedBoard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateDerivedBoard", arg0)
+	ret0, _ := ret[0].(*model.TemplateDerivedBoard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateDerivedBoard indicates an expected call of GetTemplateDerivedBoard.
+func (mr *MockStoreMockRecorder) GetTemplateDerivedBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateDerivedBoard", reflect.TypeOf((*MockStore)(nil).GetTemplateDerivedBoard), arg0)
+}
+
+// GetTemplateDerivedBoards mocks base method.
+func (m *MockStore) GetTemplateDerivedBoards(arg0 string) ([]*model.TemplateDerivedBoard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateDerivedBoards", arg0)
+	ret0, _ := ret[0].([]*model.TemplateDerivedBoard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateDerivedBoards indicates an expected call of GetTemplateDerivedBoards.
+func (mr *MockStoreMockRecorder) GetTemplateDerivedBoards(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateDerivedBoards", reflect.TypeOf((*MockStore)(nil).GetTemplateDerivedBoards), arg0)
+}
+
+// GetTemplateForBoard mocks base method.
+func (m *MockStore) GetTemplateForBoard(arg0 string) (*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateForBoard", arg0)
+	ret0, _ := ret[0].(*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateForBoard indicates an expected call of GetTemplateForBoard.
+func (mr *MockStoreMockRecorder) GetTemplateForBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateForBoard", reflect.TypeOf((*MockStore)(nil).GetTemplateForBoard), arg0)
+}
+
+// GetTemplateForSourceBoard mocks base method.
+func (m *MockStore) GetTemplateForSourceBoard(arg0, arg1 string) (*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateForSourceBoard", arg0, arg1)
+	ret0, _ := ret[0].(*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateForSourceBoard indicates an expected call of GetTemplateForSourceBoard.
+func (mr *MockStoreMockRecorder) GetTemplateForSourceBoard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateForSourceBoard", reflect.TypeOf((*MockStore)(nil).GetTemplateForSourceBoard), arg0, arg1)
+}
+
+// GetTemplates mocks base method.
+func (m *MockStore) GetTemplates(arg0 model.QueryTemplatesOptions) ([]*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplates", arg0)
+	ret0, _ := ret[0].([]*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplates indicates an expected call of GetTemplates.
+func (mr *MockStoreMockRecorder) GetTemplates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplates", reflect.TypeOf((*MockStore)(nil).GetTemplates), arg0)
+}
+
+// GetUnfinishedFileScans mocks base method.
+func (m *MockStore) GetUnfinishedFileScans(arg0 int64) ([]*model.FileScan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnfinishedFileScans", arg0)
+	ret0, _ := ret[0].([]*model.FileScan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnfinishedFileScans indicates an expected call of GetUnfinishedFileScans.
+func (mr *MockStoreMockRecorder) GetUnfinishedFileScans(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnfinishedFileScans", reflect.TypeOf((*MockStore)(nil).GetUnfinishedFileScans), arg0)
+}
+
 // GetUsedCardsCount mocks base method.
 func (m *MockStore) GetUsedCardsCount() (int, error) {
 	m.ctrl.T.Helper()
@@ -1120,6 +1670,20 @@ func (mr *MockStoreMockRecorder) GetUsersList(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersList", reflect.TypeOf((*MockStore)(nil).GetUsersList), arg0)
 }
 
+// InsertAuditRecord mocks base method.
+func (m *MockStore) InsertAuditRecord(arg0 *model.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAuditRecord", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertAuditRecord indicates an expected call of InsertAuditRecord.
+func (mr *MockStoreMockRecorder) InsertAuditRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditRecord", reflect.TypeOf((*MockStore)(nil).InsertAuditRecord), arg0)
+}
+
 // InsertBlock mocks base method.
 func (m *MockStore) InsertBlock(arg0 *model.Block, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -1179,6 +1743,64 @@ func (mr *MockStoreMockRecorder) InsertBoardWithAdmin(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBoardWithAdmin", reflect.TypeOf((*MockStore)(nil).InsertBoardWithAdmin), arg0, arg1)
 }
 
+// InsertFileScan mocks base method.
+func (m *MockStore) InsertFileScan(arg0 *model.FileScan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertFileScan", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertFileScan indicates an expected call of InsertFileScan.
+func (mr *MockStoreMockRecorder) InsertFileScan(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertFileScan", reflect.TypeOf((*MockStore)(nil).InsertFileScan), arg0)
+}
+
+// InsertImportJob mocks base method.
+func (m *MockStore) InsertImportJob(arg0 *model.ImportJob) (*model.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertImportJob", arg0)
+	ret0, _ := ret[0].(*model.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertImportJob indicates an expected call of InsertImportJob.
+func (mr *MockStoreMockRecorder) InsertImportJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertImportJob", reflect.TypeOf((*MockStore)(nil).InsertImportJob), arg0)
+}
+
+// InsertTemplate mocks base method.
+func (m *MockStore) InsertTemplate(arg0 *model.Template) (*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplate", arg0)
+	ret0, _ := ret[0].(*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplate indicates an expected call of InsertTemplate.
+func (mr *MockStoreMockRecorder) InsertTemplate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplate", reflect.TypeOf((*MockStore)(nil).InsertTemplate), arg0)
+}
+
+// MoveBlocks mocks base method.
+func (m *MockStore) MoveBlocks(arg0 []model.Block, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveBlocks", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveBlocks indicates an expected call of MoveBlocks.
+func (mr *MockStoreMockRecorder) MoveBlocks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveBlocks", reflect.TypeOf((*MockStore)(nil).MoveBlocks), arg0, arg1)
+}
+
 // PatchBlock mocks base method.
 func (m *MockStore) PatchBlock(arg0 string, arg1 *model.BlockPatch, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -1251,6 +1873,20 @@ func (mr *MockStoreMockRecorder) PatchUserProps(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchUserProps", reflect.TypeOf((*MockStore)(nil).PatchUserProps), arg0, arg1)
 }
 
+// ReadTables mocks base method.
+func (m *MockStore) ReadTables(arg0 int, arg1 func(*model.TableBatch) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadTables", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReadTables indicates an expected call of ReadTables.
+func (mr *MockStoreMockRecorder) ReadTables(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadTables", reflect.TypeOf((*MockStore)(nil).ReadTables), arg0, arg1)
+}
+
 // RefreshSession mocks base method.
 func (m *MockStore) RefreshSession(arg0 *model.Session) error {
 	m.ctrl.T.Helper()
@@ -1279,6 +1915,20 @@ func (mr *MockStoreMockRecorder) RemoveDefaultTemplates(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDefaultTemplates", reflect.TypeOf((*MockStore)(nil).RemoveDefaultTemplates), arg0)
 }
 
+// RestoreTables mocks base method.
+func (m *MockStore) RestoreTables(arg0 func() (*model.TableBatch, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTables", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTables indicates an expected call of RestoreTables.
+func (mr *MockStoreMockRecorder) RestoreTables(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTables", reflect.TypeOf((*MockStore)(nil).RestoreTables), arg0)
+}
+
 // RunDataRetention mocks base method.
 func (m *MockStore) RunDataRetention(arg0, arg1 int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1294,6 +1944,20 @@ func (mr *MockStoreMockRecorder) RunDataRetention(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunDataRetention", reflect.TypeOf((*MockStore)(nil).RunDataRetention), arg0, arg1)
 }
 
+// SaveCardChanges mocks base method.
+func (m *MockStore) SaveCardChanges(arg0 *model.CardChanges, arg1 string, arg2 model.CardsCheck) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCardChanges", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCardChanges indicates an expected call of SaveCardChanges.
+func (mr *MockStoreMockRecorder) SaveCardChanges(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCardChanges", reflect.TypeOf((*MockStore)(nil).SaveCardChanges), arg0, arg1, arg2)
+}
+
 // SaveFileInfo mocks base method.
 func (m *MockStore) SaveFileInfo(arg0 *model0.FileInfo) error {
 	m.ctrl.T.Helper()
@@ -1323,6 +1987,20 @@ func (mr *MockStoreMockRecorder) SaveMember(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMember", reflect.TypeOf((*MockStore)(nil).SaveMember), arg0)
 }
 
+// SaveTemplateDerivedBoard mocks base method.
+func (m *MockStore) SaveTemplateDerivedBoard(arg0 *model.TemplateDerivedBoard) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTemplateDerivedBoard", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTemplateDerivedBoard indicates an expected call of SaveTemplateDerivedBoard.
+func (mr *MockStoreMockRecorder) SaveTemplateDerivedBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTemplateDerivedBoard", reflect.TypeOf((*MockStore)(nil).SaveTemplateDerivedBoard), arg0)
+}
+
 // SearchBoardsForUser mocks base method.
 func (m *MockStore) SearchBoardsForUser(arg0, arg1 string) ([]*model.Board, error) {
 	m.ctrl.T.Helper()
@@ -1397,6 +2075,20 @@ func (mr *MockStoreMockRecorder) SendMessage(arg0, arg1, arg2 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockStore)(nil).SendMessage), arg0, arg1, arg2)
 }
 
+// SetDefaultSharedCategory mocks base method.
+func (m *MockStore) SetDefaultSharedCategory(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultSharedCategory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefaultSharedCategory indicates an expected call of SetDefaultSharedCategory.
+func (mr *MockStoreMockRecorder) SetDefaultSharedCategory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultSharedCategory", reflect.TypeOf((*MockStore)(nil).SetDefaultSharedCategory), arg0, arg1)
+}
+
 // SetSystemSetting mocks base method.
 func (m *MockStore) SetSystemSetting(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -1425,6 +2117,21 @@ func (mr *MockStoreMockRecorder) Shutdown() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockStore)(nil).Shutdown))
 }
 
+// TransferBoardOwnership mocks base method.
+func (m *MockStore) TransferBoardOwnership(arg0, arg1 string) (*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferBoardOwnership", arg0, arg1)
+	ret0, _ := ret[0].(*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferBoardOwnership indicates an expected call of TransferBoardOwnership.
+func (mr *MockStoreMockRecorder) TransferBoardOwnership(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferBoardOwnership", reflect.TypeOf((*MockStore)(nil).TransferBoardOwnership), arg0, arg1)
+}
+
 // UndeleteBlock mocks base method.
 func (m *MockStore) UndeleteBlock(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -1482,6 +2189,49 @@ func (mr *MockStoreMockRecorder) UpdateCategory(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockStore)(nil).UpdateCategory), arg0)
 }
 
+// UpdateFileInfoImages mocks base method.
+func (m *MockStore) UpdateFileInfoImages(arg0 *model0.FileInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFileInfoImages", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFileInfoImages indicates an expected call of UpdateFileInfoImages.
+func (mr *MockStoreMockRecorder) UpdateFileInfoImages(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFileInfoImages", reflect.TypeOf((*MockStore)(nil).UpdateFileInfoImages), arg0)
+}
+
+// UpdateFileScan mocks base method.
+func (m *MockStore) UpdateFileScan(arg0 *model.FileScan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFileScan", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFileScan indicates an expected call of UpdateFileScan.
+func (mr *MockStoreMockRecorder) UpdateFileScan(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFileScan", reflect.TypeOf((*MockStore)(nil).UpdateFileScan), arg0)
+}
+
+// UpdateImportJob mocks base method.
+func (m *MockStore) UpdateImportJob(arg0 *model.ImportJob) (*model.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateImportJob", arg0)
+	ret0, _ := ret[0].(*model.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateImportJob indicates an expected call of UpdateImportJob.
+func (mr *MockStoreMockRecorder) UpdateImportJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImportJob", reflect.TypeOf((*MockStore)(nil).UpdateImportJob), arg0)
+}
+
 // UpdateSession mocks base method.
 func (m *MockStore) UpdateSession(arg0 *model.Session) error {
 	m.ctrl.T.Helper()
@@ -1510,6 +2260,21 @@ func (mr *MockStoreMockRecorder) UpdateSubscribersNotifiedAt(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscribersNotifiedAt", reflect.TypeOf((*MockStore)(nil).UpdateSubscribersNotifiedAt), arg0, arg1)
 }
 
+// UpdateTemplate mocks base method.
+func (m *MockStore) UpdateTemplate(arg0 *model.Template) (*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplate", arg0)
+	ret0, _ := ret[0].(*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplate indicates an expected call of UpdateTemplate.
+func (mr *MockStoreMockRecorder) UpdateTemplate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplate", reflect.TypeOf((*MockStore)(nil).UpdateTemplate), arg0)
+}
+
 // UpdateUser mocks base method.
 func (m *MockStore) UpdateUser(arg0 *model.User) error {
 	m.ctrl.T.Helper()
@@ -1552,6 +2317,20 @@ func (mr *MockStoreMockRecorder) UpdateUserPasswordByID(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPasswordByID", reflect.TypeOf((*MockStore)(nil).UpdateUserPasswordByID), arg0, arg1)
 }
 
+// UpsertFeedToken mocks base method.
+func (m *MockStore) UpsertFeedToken(arg0 *model.FeedToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertFeedToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertFeedToken indicates an expected call of UpsertFeedToken.
+func (mr *MockStoreMockRecorder) UpsertFeedToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertFeedToken", reflect.TypeOf((*MockStore)(nil).UpsertFeedToken), arg0)
+}
+
 // UpsertNotificationHint mocks base method.
 func (m *MockStore) UpsertNotificationHint(arg0 *model.NotificationHint, arg1 time.Duration) (*model.NotificationHint, error) {
 	m.ctrl.T.Helper()
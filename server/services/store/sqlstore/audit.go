@@ -0,0 +1,200 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+var auditRecordFields = []string{
+	"id",
+	"level",
+	"event",
+	"status",
+	"user_id",
+	"session_id",
+	"client",
+	"ip_address",
+	"api_path",
+	"team_id",
+	"board_id",
+	"meta",
+	"create_at",
+}
+
+func (s *SQLStore) auditRecordsFromRows(rows *sql.Rows) ([]*model.AuditRecord, error) {
+	records := []*model.AuditRecord{}
+
+	for rows.Next() {
+		var record model.AuditRecord
+		var metaJSON []byte
+		err := rows.Scan(
+			&record.ID,
+			&record.Level,
+			&record.Event,
+			&record.Status,
+			&record.UserID,
+			&record.SessionID,
+			&record.Client,
+			&record.IPAddress,
+			&record.APIPath,
+			&record.TeamID,
+			&record.BoardID,
+			&metaJSON,
+			&record.CreateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(metaJSON) > 0 {
+			if err := json.Unmarshal(metaJSON, &record.Meta); err != nil {
+				s.logger.Error("Cannot unmarshal audit record meta", mlog.String("id", record.ID), mlog.Err(err))
+				return nil, err
+			}
+		}
+		records = append(records, &record)
+	}
+	return records, nil
+}
+
+func (s *SQLStore) insertAuditRecord(db sq.BaseRunner, record *model.AuditRecord) error {
+	metaJSON, err := json.Marshal(record.Meta)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"audit_records").
+		Columns(auditRecordFields...).
+		Values(
+			record.ID,
+			record.Level,
+			record.Event,
+			record.Status,
+			record.UserID,
+			record.SessionID,
+			record.Client,
+			record.IPAddress,
+			record.APIPath,
+			record.TeamID,
+			record.BoardID,
+			string(metaJSON),
+			record.CreateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// getAuditRecords returns the audit records matching the given options, newest
+// first. Up to opts.PerPage+1 records are returned so that callers can detect
+// whether a next page exists.
+func (s *SQLStore) getAuditRecords(db sq.BaseRunner, opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, error) {
+	query := s.getQueryBuilder(db).
+		Select(auditRecordFields...).
+		From(s.tablePrefix+"audit_records").
+		OrderBy("create_at DESC", "id DESC")
+
+	if opts.UserID != "" {
+		query = query.Where(sq.Eq{"user_id": opts.UserID})
+	}
+	if opts.TeamID != "" {
+		query = query.Where(sq.Eq{"team_id": opts.TeamID})
+	}
+	if opts.BoardID != "" {
+		query = query.Where(sq.Eq{"board_id": opts.BoardID})
+	}
+	if opts.Event != "" {
+		query = query.Where(sq.Eq{"event": opts.Event})
+	}
+	if opts.Since != 0 {
+		query = query.Where(sq.GtOrEq{"create_at": opts.Since})
+	}
+	if opts.Until != 0 {
+		query = query.Where(sq.Lt{"create_at": opts.Until})
+	}
+
+	if opts.PerPage > 0 {
+		query = query.
+			Offset(uint64(opts.Page * opts.PerPage)).
+			Limit(uint64(opts.PerPage + 1))
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getAuditRecords ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.auditRecordsFromRows(rows)
+}
+
+// deleteAuditRecordsBefore permanently deletes the audit records created
+// before the given date.
+func (s *SQLStore) deleteAuditRecordsBefore(db sq.BaseRunner, date int64) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "audit_records").
+		Where(sq.Lt{"create_at": date})
+
+	result, err := query.Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// deleteAuditRecordsInBatches deletes the audit records created before the
+// given date like deleteAuditRecordsBefore, at most batchSize records at a
+// time. All the records are deleted at once if batchSize isn't positive.
+func (s *SQLStore) deleteAuditRecordsInBatches(db sq.BaseRunner, date int64, batchSize int64) (int64, error) {
+	if batchSize <= 0 {
+		return s.deleteAuditRecordsBefore(db, date)
+	}
+
+	var total int64
+	for {
+		rows, err := s.getQueryBuilder(db).
+			Select("id").
+			From(s.tablePrefix + "audit_records").
+			Where(sq.Lt{"create_at": date}).
+			OrderBy("create_at").
+			Limit(uint64(batchSize)).
+			Query()
+		if err != nil {
+			return total, err
+		}
+		ids, err := idsFromRows(rows)
+		s.CloseRows(rows)
+		if err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		result, err := s.getQueryBuilder(db).
+			Delete(s.tablePrefix + "audit_records").
+			Where(sq.Eq{"id": ids}).
+			Exec()
+		if err != nil {
+			return total, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += affected
+
+		if int64(len(ids)) < batchSize {
+			return total, nil
+		}
+	}
+}
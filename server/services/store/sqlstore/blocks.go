@@ -60,6 +60,7 @@ func (s *SQLStore) blockFields() []string {
 		"update_at",
 		"delete_at",
 		"COALESCE(board_id, '0')",
+		"COALESCE(archive_at, 0)",
 	}
 }
 
@@ -209,6 +210,80 @@ func (s *SQLStore) getBlocksForBoard(db sq.BaseRunner, boardID string) ([]model.
 	return s.blocksFromRows(rows)
 }
 
+// getBlocksPage returns the blocks of a board ordered by (update_at, id),
+// starting after the cursor if one is specified. Up to opts.PerPage+1 blocks
+// are returned so that callers can detect whether a next page exists.
+func (s *SQLStore) getBlocksPage(db sq.BaseRunner, boardID string, opts model.QueryBlocksPageOptions) ([]model.Block, error) {
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields()...).
+		From(s.tablePrefix+"blocks").
+		Where(sq.Eq{"board_id": boardID}).
+		OrderBy("update_at", "id")
+
+	if opts.ParentID != "" {
+		query = query.Where(sq.Eq{"parent_id": opts.ParentID})
+	}
+
+	if opts.BlockType != "" {
+		query = query.Where(sq.Eq{"type": opts.BlockType})
+	}
+
+	if opts.UpdatedSince != 0 {
+		query = query.Where(sq.GtOrEq{"update_at": opts.UpdatedSince})
+	}
+
+	if opts.Cursor != nil {
+		query = query.Where(sq.Or{
+			sq.Gt{"update_at": opts.Cursor.UpdateAt},
+			sq.And{sq.Eq{"update_at": opts.Cursor.UpdateAt}, sq.Gt{"id": opts.Cursor.ID}},
+		})
+	}
+
+	if opts.PerPage > 0 {
+		query = query.Limit(uint64(opts.PerPage + 1))
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBlocksPage ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.blocksFromRows(rows)
+}
+
+// getBlockTombstones returns the blocks of a board that have been deleted
+// at or after the given time and have not been restored since.
+func (s *SQLStore) getBlockTombstones(db sq.BaseRunner, boardID string, since int64) ([]model.BlockTombstone, error) {
+	query := s.getQueryBuilder(db).
+		Select("id", "MAX(COALESCE(parent_id, ''))", "MAX(type)", "MAX(delete_at)").
+		From(s.tablePrefix + "blocks_history").
+		Where(sq.Eq{"board_id": boardID}).
+		Where(sq.Gt{"delete_at": 0}).
+		Where(sq.GtOrEq{"delete_at": since}).
+		Where(sq.Expr("id NOT IN (SELECT id FROM "+s.tablePrefix+"blocks WHERE board_id = ?)", boardID)).
+		GroupBy("id").
+		OrderBy("id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBlockTombstones ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	tombstones := []model.BlockTombstone{}
+	for rows.Next() {
+		var tombstone model.BlockTombstone
+		if err := rows.Scan(&tombstone.ID, &tombstone.ParentID, &tombstone.Type, &tombstone.DeleteAt); err != nil {
+			return nil, err
+		}
+		tombstones = append(tombstones, tombstone)
+	}
+	return tombstones, nil
+}
+
 func (s *SQLStore) blocksFromRows(rows *sql.Rows) ([]model.Block, error) {
 	results := []model.Block{}
 
@@ -231,7 +306,8 @@ func (s *SQLStore) blocksFromRows(rows *sql.Rows) ([]model.Block, error) {
 			&block.CreateAt,
 			&block.UpdateAt,
 			&block.DeleteAt,
-			&block.BoardID)
+			&block.BoardID,
+			&block.ArchiveAt)
 		if err != nil {
 			// handle this error
 			s.logger.Error(`ERROR blocksFromRows`, mlog.Err(err))
@@ -262,17 +338,30 @@ func (s *SQLStore) insertBlock(db sq.BaseRunner, block *model.Block, userID stri
 		return BoardIDNilError{}
 	}
 
-	fieldsJSON, err := json.Marshal(block.Fields)
+	existingBlock, err := s.getBlock(db, block.ID)
 	if err != nil {
 		return err
 	}
+	return s.saveBlock(db, block, existingBlock, block.BoardID, nil, userID)
+}
 
-	existingBlock, err := s.getBlock(db, block.ID)
+// saveBlock inserts a block, or updates it if existingBlock is set and the
+// block is still in the board boardID. If expectedUpdateAt is set, the
+// update is only applied if the block still has this update time, so that
+// the check and the write are a single statement, and ErrBlockConflict is
+// returned otherwise.
+func (s *SQLStore) saveBlock(db sq.BaseRunner, block *model.Block, existingBlock *model.Block, boardID string, expectedUpdateAt *int64, userID string) error {
+	fieldsJSON, err := json.Marshal(block.Fields)
 	if err != nil {
 		return err
 	}
 
 	block.UpdateAt = utils.GetMillis()
+	if existingBlock != nil && block.UpdateAt <= existingBlock.UpdateAt {
+		// the update time changes on every write, even within the same
+		// millisecond, as it identifies the versions of the block
+		block.UpdateAt = existingBlock.UpdateAt + 1
+	}
 	block.ModifiedBy = userID
 
 	insertQuery := s.getQueryBuilder(db).Insert("").
@@ -290,6 +379,7 @@ func (s *SQLStore) insertBlock(db sq.BaseRunner, block *model.Block, userID stri
 			"update_at",
 			"delete_at",
 			"board_id",
+			"archive_at",
 		)
 
 	insertQueryValues := map[string]interface{}{
@@ -306,13 +396,14 @@ func (s *SQLStore) insertBlock(db sq.BaseRunner, block *model.Block, userID stri
 		"create_at":             utils.GetMillis(),
 		"update_at":             block.UpdateAt,
 		"board_id":              block.BoardID,
+		"archive_at":            block.ArchiveAt,
 	}
 
 	if existingBlock != nil {
 		// block with ID exists, so this is an update operation
 		query := s.getQueryBuilder(db).Update(s.tablePrefix+"blocks").
 			Where(sq.Eq{"id": block.ID}).
-			Where(sq.Eq{"board_id": block.BoardID}).
+			Where(sq.Eq{"board_id": boardID}).
 			Set("parent_id", block.ParentID).
 			Set("modified_by", block.ModifiedBy).
 			Set(s.escapeField("schema"), block.Schema).
@@ -320,13 +411,27 @@ func (s *SQLStore) insertBlock(db sq.BaseRunner, block *model.Block, userID stri
 			Set("title", block.Title).
 			Set("fields", fieldsJSON).
 			Set("update_at", block.UpdateAt).
-			Set("delete_at", block.DeleteAt)
+			Set("delete_at", block.DeleteAt).
+			Set("archive_at", block.ArchiveAt)
+		if expectedUpdateAt != nil {
+			query = query.Where(sq.Eq{"update_at": *expectedUpdateAt})
+		}
 
-		if _, err := query.Exec(); err != nil {
+		result, err := query.Exec()
+		if err != nil {
 			s.logger.Error(`InsertBlock error occurred while updating existing block`, mlog.String("blockID", block.ID), mlog.Err(err))
 
 			return err
 		}
+		if expectedUpdateAt != nil {
+			affected, rowsErr := result.RowsAffected()
+			if rowsErr != nil {
+				return rowsErr
+			}
+			if affected != 1 {
+				return s.blockConflictError(db, block.ID, *expectedUpdateAt)
+			}
+		}
 	} else {
 		block.CreatedBy = userID
 		query := insertQuery.SetMap(insertQueryValues).Into(s.tablePrefix + "blocks")
@@ -353,8 +458,31 @@ func (s *SQLStore) patchBlock(db sq.BaseRunner, blockID string, blockPatch *mode
 		return BlockNotFoundErr{blockID}
 	}
 
+	if blockPatch.ExpectedUpdateAt != nil && *blockPatch.ExpectedUpdateAt != existingBlock.UpdateAt {
+		return model.NewErrBlockConflict(blockID, *blockPatch.ExpectedUpdateAt, existingBlock.UpdateAt)
+	}
+
+	// the update matches the board the block is in, as the board of the
+	// patch may be another one
+	previous := *existingBlock
 	block := blockPatch.Patch(existingBlock)
-	return s.insertBlock(db, block, userID)
+	if block.BoardID == "" {
+		return BoardIDNilError{}
+	}
+	return s.saveBlock(db, block, &previous, previous.BoardID, blockPatch.ExpectedUpdateAt, userID)
+}
+
+// blockConflictError returns the error of a write expecting a block to
+// have another update time.
+func (s *SQLStore) blockConflictError(db sq.BaseRunner, blockID string, expectedUpdateAt int64) error {
+	block, err := s.getBlock(db, blockID)
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return BlockNotFoundErr{blockID}
+	}
+	return model.NewErrBlockConflict(blockID, expectedUpdateAt, block.UpdateAt)
 }
 
 func (s *SQLStore) patchBlocks(db sq.BaseRunner, blockPatches *model.BlockPatchBatch, userID string) error {
@@ -413,6 +541,7 @@ func (s *SQLStore) deleteBlock(db sq.BaseRunner, blockID string, modifiedBy stri
 			"update_at",
 			"delete_at",
 			"created_by",
+			"archive_at",
 		).
 		Values(
 			block.BoardID,
@@ -427,6 +556,7 @@ func (s *SQLStore) deleteBlock(db sq.BaseRunner, blockID string, modifiedBy stri
 			now,
 			now,
 			block.CreatedBy,
+			block.ArchiveAt,
 		)
 
 	if _, err := insertQuery.Exec(); err != nil {
@@ -444,6 +574,15 @@ func (s *SQLStore) deleteBlock(db sq.BaseRunner, blockID string, modifiedBy stri
 	return nil
 }
 
+func (s *SQLStore) deleteBlocks(db sq.BaseRunner, blockIDs []string, modifiedBy string) error {
+	for _, blockID := range blockIDs {
+		if err := s.deleteBlock(db, blockID, modifiedBy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *SQLStore) undeleteBlock(db sq.BaseRunner, blockID string, modifiedBy string) error {
 	blocks, err := s.getBlockHistory(db, blockID, model.QueryBlockHistoryOptions{Limit: 1, Descending: true})
 	if err != nil {
@@ -481,6 +620,7 @@ func (s *SQLStore) undeleteBlock(db sq.BaseRunner, blockID string, modifiedBy st
 		"update_at",
 		"delete_at",
 		"created_by",
+		"archive_at",
 	}
 
 	values := []interface{}{
@@ -497,6 +637,7 @@ func (s *SQLStore) undeleteBlock(db sq.BaseRunner, blockID string, modifiedBy st
 		now,
 		0,
 		block.CreatedBy,
+		block.ArchiveAt,
 	}
 	insertHistoryQuery := s.getQueryBuilder(db).Insert(s.tablePrefix + "blocks_history").
 		Columns(columns...).
@@ -802,3 +943,183 @@ func (s *SQLStore) duplicateBlock(db sq.BaseRunner, boardID string, blockID stri
 	}
 	return allBlocks, nil
 }
+
+// moveBlocks moves existing blocks to another board. blocks hold the new
+// version of each block, with the destination board ID. The history of
+// the board the blocks leave records them as deleted, and the history of
+// the destination board records their new version.
+func (s *SQLStore) moveBlocks(db sq.BaseRunner, blocks []model.Block, userID string) error {
+	now := utils.GetMillis()
+	for i := range blocks {
+		block := &blocks[i]
+		if block.BoardID == "" {
+			return BoardIDNilError{}
+		}
+
+		existingBlock, err := s.getBlock(db, block.ID)
+		if err != nil {
+			return err
+		}
+		if existingBlock == nil {
+			return model.NewErrNotFound("block ID=" + block.ID)
+		}
+
+		existingFieldsJSON, err := json.Marshal(existingBlock.Fields)
+		if err != nil {
+			return err
+		}
+		fieldsJSON, err := json.Marshal(block.Fields)
+		if err != nil {
+			return err
+		}
+
+		block.CreateAt = existingBlock.CreateAt
+		block.CreatedBy = existingBlock.CreatedBy
+		block.ModifiedBy = userID
+		block.UpdateAt = now
+		block.DeleteAt = 0
+
+		historyColumns := []string{
+			"board_id",
+			"id",
+			"parent_id",
+			s.escapeField("schema"),
+			"type",
+			"title",
+			"fields",
+			"modified_by",
+			"create_at",
+			"update_at",
+			"delete_at",
+			"created_by",
+			"archive_at",
+		}
+
+		deletedQuery := s.getQueryBuilder(db).Insert(s.tablePrefix+"blocks_history").
+			Columns(historyColumns...).
+			Values(
+				existingBlock.BoardID,
+				existingBlock.ID,
+				existingBlock.ParentID,
+				existingBlock.Schema,
+				existingBlock.Type,
+				existingBlock.Title,
+				existingFieldsJSON,
+				userID,
+				existingBlock.CreateAt,
+				now,
+				now,
+				existingBlock.CreatedBy,
+				existingBlock.ArchiveAt,
+			)
+		if _, err := deletedQuery.Exec(); err != nil {
+			return err
+		}
+
+		updateQuery := s.getQueryBuilder(db).Update(s.tablePrefix+"blocks").
+			Where(sq.Eq{"id": block.ID}).
+			Set("board_id", block.BoardID).
+			Set("parent_id", block.ParentID).
+			Set("modified_by", block.ModifiedBy).
+			Set(s.escapeField("schema"), block.Schema).
+			Set("type", block.Type).
+			Set("title", block.Title).
+			Set("fields", fieldsJSON).
+			Set("update_at", block.UpdateAt).
+			Set("delete_at", block.DeleteAt).
+			Set("archive_at", block.ArchiveAt)
+		if _, err := updateQuery.Exec(); err != nil {
+			return err
+		}
+
+		movedQuery := s.getQueryBuilder(db).Insert(s.tablePrefix+"blocks_history").
+			Columns(historyColumns...).
+			Values(
+				block.BoardID,
+				block.ID,
+				block.ParentID,
+				block.Schema,
+				block.Type,
+				block.Title,
+				fieldsJSON,
+				userID,
+				block.CreateAt,
+				block.UpdateAt,
+				block.DeleteAt,
+				block.CreatedBy,
+				block.ArchiveAt,
+			)
+		if _, err := movedQuery.Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveCardChanges checks the cards of the changed boards and saves the
+// changes. The board rows are locked first, so concurrent changes of the
+// same boards are checked one after the other.
+func (s *SQLStore) saveCardChanges(db sq.BaseRunner, changes *model.CardChanges, userID string, check model.CardsCheck) error {
+	if s.dbType == model.SqliteDBType {
+		s.cardChangesMutex.Lock()
+		defer s.cardChangesMutex.Unlock()
+	}
+
+	if check != nil && len(changes.BoardIDs) > 0 {
+		if err := s.lockBoards(db, changes.BoardIDs); err != nil {
+			return err
+		}
+		for _, boardID := range changes.BoardIDs {
+			cards, err := s.getBlocksWithType(db, boardID, model.TypeCard)
+			if err != nil {
+				return err
+			}
+			if err := check(boardID, cards); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(changes.InsertedBlocks) > 0 {
+		if err := s.insertBlocks(db, changes.InsertedBlocks, userID); err != nil {
+			return err
+		}
+	}
+	if changes.BlockPatches != nil {
+		if err := s.patchBlocks(db, changes.BlockPatches, userID); err != nil {
+			return err
+		}
+	}
+	if len(changes.MovedBlocks) > 0 {
+		if err := s.moveBlocks(db, changes.MovedBlocks, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockBoards locks the rows of the boards until the end of the
+// transaction. On SQLite the changes are serialized by saveCardChanges.
+func (s *SQLStore) lockBoards(db sq.BaseRunner, boardIDs []string) error {
+	if s.dbType == model.SqliteDBType {
+		return nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Select("id").
+		From(s.tablePrefix + "boards").
+		Where(sq.Eq{"id": boardIDs}).
+		OrderBy("id").
+		Suffix("FOR UPDATE")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`lockBoards ERROR`, mlog.Err(err))
+		return err
+	}
+	defer s.CloseRows(rows)
+
+	for rows.Next() {
+	}
+	return rows.Err()
+}
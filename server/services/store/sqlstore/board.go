@@ -45,6 +45,7 @@ func boardFields(prefix string) []string {
 		"create_at",
 		"update_at",
 		"delete_at",
+		"COALESCE(archive_at, 0)",
 	}
 
 	if prefix == "" {
@@ -82,6 +83,7 @@ func boardHistoryFields() []string {
 		"COALESCE(create_at, 0)",
 		"COALESCE(update_at, 0)",
 		"COALESCE(delete_at, 0)",
+		"COALESCE(archive_at, 0)",
 	}
 
 	return fields
@@ -125,6 +127,7 @@ func (s *SQLStore) boardsFromRows(rows *sql.Rows) ([]*model.Board, error) {
 			&board.CreateAt,
 			&board.UpdateAt,
 			&board.DeleteAt,
+			&board.ArchiveAt,
 		)
 		if err != nil {
 			s.logger.Error("boardsFromRows scan error", mlog.Err(err))
@@ -296,7 +299,103 @@ func (s *SQLStore) getBoardsInTeamByIds(db sq.BaseRunner, boardIDs []string, tea
 	return s.boardsFromRows(rows)
 }
 
+func (s *SQLStore) getBoardsByIDs(db sq.BaseRunner, boardIDs []string) ([]*model.Board, error) {
+	query := s.getQueryBuilder(db).
+		Select(boardFields("")...).
+		From(s.tablePrefix + "boards").
+		Where(sq.Eq{"id": boardIDs})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardsByIDs ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.boardsFromRows(rows)
+}
+
+// getBoardsInTeam returns all the boards and templates of a team,
+// regardless of their members.
+func (s *SQLStore) getBoardsInTeam(db sq.BaseRunner, teamID string) ([]*model.Board, error) {
+	query := s.getQueryBuilder(db).
+		Select(boardFields("")...).
+		From(s.tablePrefix+"boards").
+		Where(sq.Eq{"team_id": teamID}).
+		OrderBy("title", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardsInTeam ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.boardsFromRows(rows)
+}
+
+// transferBoardOwnership makes a user the creator of a board, and an admin
+// of it. The previous owner keeps their membership.
+func (s *SQLStore) transferBoardOwnership(db sq.BaseRunner, boardID, userID string) (*model.Board, error) {
+	if _, err := s.getBoard(db, boardID); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"boards").
+		Set("created_by", userID).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"id": boardID})
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`transferBoardOwnership ERROR`, mlog.String("boardID", boardID), mlog.Err(err))
+		return nil, err
+	}
+
+	member := &model.BoardMember{
+		BoardID:      boardID,
+		UserID:       userID,
+		SchemeAdmin:  true,
+		SchemeEditor: true,
+	}
+	if _, err := s.saveMember(db, member); err != nil {
+		return nil, err
+	}
+
+	return s.getBoard(db, boardID)
+}
+
+// getBoardsWithAutoArchiveRules returns the unarchived boards with an
+// option of a card property that has an auto-archive rule.
+func (s *SQLStore) getBoardsWithAutoArchiveRules(db sq.BaseRunner) ([]*model.Board, error) {
+	cardPropertiesColumn := "card_properties"
+	if s.dbType == model.PostgresDBType {
+		cardPropertiesColumn = "card_properties::text"
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(boardFields("")...).
+		From(s.tablePrefix + "boards").
+		Where(sq.Eq{"is_template": false}).
+		Where(sq.Eq{"COALESCE(archive_at, 0)": 0}).
+		Where(sq.Like{cardPropertiesColumn: `%"autoArchiveDays"%`})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardsWithAutoArchiveRules ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.boardsFromRows(rows)
+}
+
 func (s *SQLStore) insertBoard(db sq.BaseRunner, board *model.Board, userID string) (*model.Board, error) {
+	return s.saveBoard(db, board, nil, userID)
+}
+
+// saveBoard inserts or updates a board. If expectedUpdateAt is set, the
+// board is only updated if it still has this update time.
+func (s *SQLStore) saveBoard(db sq.BaseRunner, board *model.Board, expectedUpdateAt *int64, userID string) (*model.Board, error) {
 	// Generate tracking IDs for in-built templates
 	if board.IsTemplate && board.TeamID == model.GlobalTeamID {
 		//nolint:gosec
@@ -355,6 +454,14 @@ func (s *SQLStore) insertBoard(db sq.BaseRunner, board *model.Board, userID stri
 		"create_at":        board.CreateAt,
 		"update_at":        now,
 		"delete_at":        board.DeleteAt,
+		"archive_at":       board.ArchiveAt,
+	}
+
+	if existingBoard != nil && now <= existingBoard.UpdateAt {
+		// the update time must change for the expected update time of a
+		// concurrent patch not to match anymore
+		now = existingBoard.UpdateAt + 1
+		insertQueryValues["update_at"] = now
 	}
 
 	if existingBoard != nil {
@@ -373,12 +480,30 @@ func (s *SQLStore) insertBoard(db sq.BaseRunner, board *model.Board, userID stri
 			Set("properties", propertiesBytes).
 			Set("card_properties", cardPropertiesBytes).
 			Set("update_at", now).
-			Set("delete_at", board.DeleteAt)
+			Set("delete_at", board.DeleteAt).
+			Set("archive_at", board.ArchiveAt)
+		if expectedUpdateAt != nil {
+			query = query.Where(sq.Eq{"update_at": *expectedUpdateAt})
+		}
 
-		if _, err := query.Exec(); err != nil {
+		result, err := query.Exec()
+		if err != nil {
 			s.logger.Error(`InsertBoard error occurred while updating existing board`, mlog.String("boardID", board.ID), mlog.Err(err))
 			return nil, fmt.Errorf("insertBoard error occurred while updating existing board %s: %w", board.ID, err)
 		}
+		if expectedUpdateAt != nil {
+			rowsAffected, rowsErr := result.RowsAffected()
+			if rowsErr != nil {
+				return nil, rowsErr
+			}
+			if rowsAffected != 1 {
+				current, getErr := s.getBoard(db, board.ID)
+				if getErr != nil {
+					return nil, getErr
+				}
+				return nil, model.NewErrBoardConflict(board.ID, *expectedUpdateAt, current.UpdateAt)
+			}
+		}
 	} else {
 		insertQueryValues["created_by"] = userID
 		insertQueryValues["create_at"] = now
@@ -409,8 +534,12 @@ func (s *SQLStore) patchBoard(db sq.BaseRunner, boardID string, boardPatch *mode
 		return nil, BoardNotFoundErr{boardID}
 	}
 
+	if boardPatch.ExpectedUpdateAt != nil && *boardPatch.ExpectedUpdateAt != existingBoard.UpdateAt {
+		return nil, model.NewErrBoardConflict(boardID, *boardPatch.ExpectedUpdateAt, existingBoard.UpdateAt)
+	}
+
 	board := boardPatch.Patch(existingBoard)
-	return s.insertBoard(db, board, userID)
+	return s.saveBoard(db, board, boardPatch.ExpectedUpdateAt, userID)
 }
 
 func (s *SQLStore) deleteBoard(db sq.BaseRunner, boardID, userID string) error {
@@ -449,6 +578,7 @@ func (s *SQLStore) deleteBoard(db sq.BaseRunner, boardID, userID string) error {
 		"create_at":        board.CreateAt,
 		"update_at":        now,
 		"delete_at":        now,
+		"archive_at":       board.ArchiveAt,
 	}
 
 	// writing board history
@@ -811,6 +941,7 @@ func (s *SQLStore) undeleteBoard(db sq.BaseRunner, boardID string, modifiedBy st
 		"create_at",
 		"update_at",
 		"delete_at",
+		"archive_at",
 	}
 
 	values := []interface{}{
@@ -832,6 +963,7 @@ func (s *SQLStore) undeleteBoard(db sq.BaseRunner, boardID string, modifiedBy st
 		board.CreateAt,
 		now,
 		0,
+		board.ArchiveAt,
 	}
 	insertHistoryQuery := s.getQueryBuilder(db).Insert(s.tablePrefix + "boards_history").
 		Columns(columns...).
@@ -3,6 +3,7 @@ package sqlstore
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mattermost/focalboard/server/model"
@@ -145,3 +146,52 @@ func boardsInsightsFromRows(rows *sql.Rows) ([]*model.BoardInsight, error) {
 	}
 	return boardsInsights, nil
 }
+
+// getCardHistory returns the versions of the cards of a board stored in the
+// block history up to the given time, with the values of the given
+// properties extracted by the database. Entries are ordered by card and
+// update time.
+func (s *SQLStore) getCardHistory(db sq.BaseRunner, boardID string, propertyIDs []string, until int64) ([]*model.CardHistoryEntry, error) {
+	columns := []string{"id", "update_at", "delete_at"}
+	args := make([]interface{}, 0, len(propertyIDs))
+	for i, propertyID := range propertyIDs {
+		columns = append(columns, fmt.Sprintf("%s AS value%d", s.cardPropertySelector(), i))
+		args = append(args, s.cardPropertyPath(propertyID))
+	}
+
+	query := s.getQueryBuilder(db).
+		Select().
+		Column(sq.Expr(strings.Join(columns, ", "), args...)).
+		From(s.tablePrefix+"blocks_history").
+		Where(sq.Eq{"board_id": boardID}).
+		Where(sq.Eq{"type": model.TypeCard}).
+		Where(sq.LtOrEq{"update_at": until}).
+		OrderBy("id", "update_at", "insert_at")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getCardHistory ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	history := []*model.CardHistoryEntry{}
+	for rows.Next() {
+		entry := &model.CardHistoryEntry{Values: make(map[string]string, len(propertyIDs))}
+		values := make([]sql.NullString, len(propertyIDs))
+		dest := []interface{}{&entry.CardID, &entry.UpdateAt, &entry.DeleteAt}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, propertyID := range propertyIDs {
+			entry.Values[propertyID] = values[i].String
+		}
+		history = append(history, entry)
+	}
+
+	return history, nil
+}
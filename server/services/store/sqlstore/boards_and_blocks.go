@@ -42,6 +42,20 @@ func (s *SQLStore) createBoardsAndBlocksWithAdmin(db sq.BaseRunner, bab *model.B
 	return newBab, members, nil
 }
 
+// createImportedBoardsAndBlocks creates the boards and blocks imported by an
+// import job, like createBoardsAndBlocksWithAdmin, and saves the progress of
+// the job along with them, so that a resumed job doesn't import them again.
+func (s *SQLStore) createImportedBoardsAndBlocks(db sq.BaseRunner, bab *model.BoardsAndBlocks, userID string, job *model.ImportJob) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	newBab, members, err := s.createBoardsAndBlocksWithAdmin(db, bab, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.updateImportJob(db, job); err != nil {
+		return nil, nil, err
+	}
+	return newBab, members, nil
+}
+
 func (s *SQLStore) createBoardsAndBlocks(db sq.BaseRunner, bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
 	boards := []*model.Board{}
 	blocks := []model.Block{}
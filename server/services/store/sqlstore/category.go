@@ -10,9 +10,26 @@ import (
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
 )
 
+func categoryFields() []string {
+	return []string{
+		"id",
+		"name",
+		"user_id",
+		"team_id",
+		"create_at",
+		"update_at",
+		"delete_at",
+		"collapsed",
+		"COALESCE(type, 'private')",
+		"COALESCE(parent_id, '')",
+		"COALESCE(sort_order, 0)",
+		"COALESCE(is_default, false)",
+	}
+}
+
 func (s *SQLStore) getCategory(db sq.BaseRunner, id string) (*model.Category, error) {
 	query := s.getQueryBuilder(db).
-		Select("id", "name", "user_id", "team_id", "create_at", "update_at", "delete_at", "collapsed").
+		Select(categoryFields()...).
 		From(s.tablePrefix + "categories").
 		Where(sq.Eq{"id": id})
 
@@ -36,6 +53,10 @@ func (s *SQLStore) getCategory(db sq.BaseRunner, id string) (*model.Category, er
 }
 
 func (s *SQLStore) createCategory(db sq.BaseRunner, category model.Category) error {
+	if category.Type == "" {
+		category.Type = model.CategoryTypePrivate
+	}
+
 	query := s.getQueryBuilder(db).
 		Insert(s.tablePrefix+"categories").
 		Columns(
@@ -47,6 +68,10 @@ func (s *SQLStore) createCategory(db sq.BaseRunner, category model.Category) err
 			"update_at",
 			"delete_at",
 			"collapsed",
+			"type",
+			"parent_id",
+			"sort_order",
+			"is_default",
 		).
 		Values(
 			category.ID,
@@ -57,6 +82,10 @@ func (s *SQLStore) createCategory(db sq.BaseRunner, category model.Category) err
 			category.UpdateAt,
 			category.DeleteAt,
 			category.Collapsed,
+			category.Type,
+			category.ParentID,
+			category.SortOrder,
+			category.IsDefault,
 		)
 
 	_, err := query.Exec()
@@ -73,6 +102,9 @@ func (s *SQLStore) updateCategory(db sq.BaseRunner, category model.Category) err
 		Set("name", category.Name).
 		Set("update_at", category.UpdateAt).
 		Set("collapsed", category.Collapsed).
+		Set("parent_id", category.ParentID).
+		Set("sort_order", category.SortOrder).
+		Set("is_default", category.IsDefault).
 		Where(sq.Eq{"id": category.ID})
 
 	_, err := query.Exec()
@@ -109,13 +141,15 @@ func (s *SQLStore) deleteCategory(db sq.BaseRunner, categoryID, userID, teamID s
 
 func (s *SQLStore) getUserCategories(db sq.BaseRunner, userID, teamID string) ([]model.Category, error) {
 	query := s.getQueryBuilder(db).
-		Select("id", "name", "user_id", "team_id", "create_at", "update_at", "delete_at", "collapsed").
-		From(s.tablePrefix + "categories").
+		Select(categoryFields()...).
+		From(s.tablePrefix+"categories").
 		Where(sq.Eq{
 			"user_id":   userID,
 			"team_id":   teamID,
 			"delete_at": 0,
-		})
+		}).
+		Where(sq.NotEq{"type": model.CategoryTypeShared}).
+		OrderBy("sort_order", "create_at")
 
 	rows, err := query.Query()
 	if err != nil {
@@ -126,6 +160,103 @@ func (s *SQLStore) getUserCategories(db sq.BaseRunner, userID, teamID string) ([
 	return s.categoriesFromRows(rows)
 }
 
+func (s *SQLStore) getSharedCategories(db sq.BaseRunner, teamID string) ([]model.Category, error) {
+	query := s.getQueryBuilder(db).
+		Select(categoryFields()...).
+		From(s.tablePrefix+"categories").
+		Where(sq.Eq{
+			"team_id":   teamID,
+			"type":      model.CategoryTypeShared,
+			"delete_at": 0,
+		}).
+		OrderBy("sort_order", "create_at")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getSharedCategories error", mlog.Err(err))
+		return nil, err
+	}
+
+	return s.categoriesFromRows(rows)
+}
+
+func (s *SQLStore) getDefaultSharedCategory(db sq.BaseRunner, teamID string) (*model.Category, error) {
+	query := s.getQueryBuilder(db).
+		Select(categoryFields()...).
+		From(s.tablePrefix + "categories").
+		Where(sq.Eq{
+			"team_id":    teamID,
+			"type":       model.CategoryTypeShared,
+			"is_default": true,
+			"delete_at":  0,
+		})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getDefaultSharedCategory error", mlog.Err(err))
+		return nil, err
+	}
+
+	categories, err := s.categoriesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(categories) == 0 {
+		return nil, model.NewErrNotFound("default category for team " + teamID)
+	}
+
+	return &categories[0], nil
+}
+
+// setDefaultSharedCategory marks a shared category as the default one of
+// the team, clearing the flag on every other shared category. An empty
+// category ID leaves the team without a default category.
+func (s *SQLStore) setDefaultSharedCategory(db sq.BaseRunner, teamID, categoryID string) error {
+	_, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"categories").
+		Set("is_default", false).
+		Where(sq.Eq{
+			"team_id":    teamID,
+			"type":       model.CategoryTypeShared,
+			"is_default": true,
+		}).
+		Exec()
+	if err != nil {
+		s.logger.Error("setDefaultSharedCategory clear error", mlog.String("team_id", teamID), mlog.Err(err))
+		return err
+	}
+
+	if categoryID == "" {
+		return nil
+	}
+
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"categories").
+		Set("is_default", true).
+		Where(sq.Eq{
+			"id":        categoryID,
+			"team_id":   teamID,
+			"type":      model.CategoryTypeShared,
+			"delete_at": 0,
+		}).
+		Exec()
+	if err != nil {
+		s.logger.Error("setDefaultSharedCategory error", mlog.String("category_id", categoryID), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound(categoryID)
+	}
+
+	return nil
+}
+
 func (s *SQLStore) categoriesFromRows(rows *sql.Rows) ([]model.Category, error) {
 	var categories []model.Category
 
@@ -140,6 +271,10 @@ func (s *SQLStore) categoriesFromRows(rows *sql.Rows) ([]model.Category, error)
 			&category.UpdateAt,
 			&category.DeleteAt,
 			&category.Collapsed,
+			&category.Type,
+			&category.ParentID,
+			&category.SortOrder,
+			&category.IsDefault,
 		)
 
 		if err != nil {
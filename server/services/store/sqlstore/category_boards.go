@@ -34,6 +34,28 @@ func (s *SQLStore) getUserCategoryBoards(db sq.BaseRunner, userID, teamID string
 	return userCategoryBoards, nil
 }
 
+func (s *SQLStore) getSharedCategoryBoards(db sq.BaseRunner, teamID string) ([]model.CategoryBoards, error) {
+	categories, err := s.getSharedCategories(db, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedCategoryBoards := []model.CategoryBoards{}
+	for _, category := range categories {
+		boardIDs, err := s.getCategoryBoardAttributes(db, category.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		sharedCategoryBoards = append(sharedCategoryBoards, model.CategoryBoards{
+			Category: category,
+			BoardIDs: boardIDs,
+		})
+	}
+
+	return sharedCategoryBoards, nil
+}
+
 func (s *SQLStore) getCategoryBoardAttributes(db sq.BaseRunner, categoryID string) ([]string, error) {
 	query := s.getQueryBuilder(db).
 		Select("board_id").
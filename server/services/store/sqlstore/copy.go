@@ -0,0 +1,249 @@
+package sqlstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+var (
+	ErrCopyTargetNotEmpty    = errors.New("the target database already has users or boards")
+	ErrSchemaVersionMismatch = errors.New("the databases have different schema versions")
+)
+
+// CopyProgress reports the progress of the copy of a table: the number of
+// rows copied so far, out of total.
+type CopyProgress func(table string, copied, total int64)
+
+// CopyTables copies the data tables of source into target, which can use a
+// different database type and table prefix. Both stores must be at the same
+// schema version, and target must not have users nor boards other than
+// templates. The rows are read from a single snapshot of source and written
+// in a single transaction into target, in batches of batchSize rows.
+func CopyTables(source, target *SQLStore, batchSize int, progress CopyProgress) error {
+	sourceVersion, err := source.GetSchemaVersion()
+	if err != nil {
+		return err
+	}
+	targetVersion, err := target.GetSchemaVersion()
+	if err != nil {
+		return err
+	}
+	if sourceVersion != targetVersion {
+		return fmt.Errorf("%w: source at %d, target at %d", ErrSchemaVersionMismatch, sourceVersion, targetVersion)
+	}
+
+	readTx, err := source.beginSnapshotTx()
+	if err != nil {
+		return err
+	}
+	defer source.endSnapshotTx(readTx, "CopyTables")
+
+	writeTx, err := target.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+
+	if err := copyTables(source, readTx, target, writeTx, batchSize, progress); err != nil {
+		if rollbackErr := writeTx.Rollback(); rollbackErr != nil {
+			target.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "CopyTables"))
+		}
+		return err
+	}
+	return writeTx.Commit()
+}
+
+func copyTables(source *SQLStore, readTx sq.BaseRunner, target *SQLStore, writeTx sq.BaseRunner, batchSize int, progress CopyProgress) error {
+	if err := target.clearDataTables(writeTx); err != nil {
+		if errors.Is(err, model.ErrRestoreNotEmpty) {
+			return ErrCopyTargetNotEmpty
+		}
+		return err
+	}
+
+	for _, table := range dataTables {
+		total, err := source.countRows(readTx, table.name)
+		if err != nil {
+			return fmt.Errorf("cannot count the rows of table %s: %w", table.name, err)
+		}
+
+		var copied int64
+		err = source.readTable(readTx, table, batchSize, func(batch *model.TableBatch) error {
+			if err := target.insertTableBatch(writeTx, batch); err != nil {
+				return err
+			}
+			copied += int64(len(batch.Rows))
+			if progress != nil {
+				progress(table.name, copied, total)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("cannot copy table %s: %w", table.name, err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLStore) countRows(db sq.BaseRunner, table string) (int64, error) {
+	var count int64
+	err := s.getQueryBuilder(db).
+		Select("COUNT(*)").
+		From(s.tablePrefix + table).
+		QueryRow().
+		Scan(&count)
+	return count, err
+}
+
+// TableVerification is the comparison of a data table between two stores.
+type TableVerification struct {
+	Table          string
+	SourceRows     int64
+	TargetRows     int64
+	SourceChecksum string
+	TargetChecksum string
+}
+
+// Matches returns true if the table has the same rows in both stores.
+func (v TableVerification) Matches() bool {
+	return v.SourceRows == v.TargetRows && v.SourceChecksum == v.TargetChecksum
+}
+
+// VerifyTables compares the row counts and the checksums of the data tables
+// of source and target. The checksums don't depend on the order of the rows
+// nor of the columns, nor on the way each database type returns the values,
+// so they can be compared between database types.
+func VerifyTables(source, target *SQLStore, batchSize int) ([]TableVerification, error) {
+	sourceChecksums, err := source.tableChecksums(batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute the checksums of the source: %w", err)
+	}
+	targetChecksums, err := target.tableChecksums(batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute the checksums of the target: %w", err)
+	}
+
+	verifications := make([]TableVerification, 0, len(dataTables))
+	for _, table := range dataTables {
+		sourceChecksum := sourceChecksums.get(table.name)
+		targetChecksum := targetChecksums.get(table.name)
+		verifications = append(verifications, TableVerification{
+			Table:          table.name,
+			SourceRows:     sourceChecksum.rows,
+			TargetRows:     targetChecksum.rows,
+			SourceChecksum: hex.EncodeToString(sourceChecksum.sum[:]),
+			TargetChecksum: hex.EncodeToString(targetChecksum.sum[:]),
+		})
+	}
+	return verifications, nil
+}
+
+// tableChecksum is the number of rows of a table and the XOR of the
+// checksums of its rows, which are unique.
+type tableChecksum struct {
+	rows int64
+	sum  [sha256.Size]byte
+}
+
+type tableChecksums map[string]*tableChecksum
+
+func (c tableChecksums) get(table string) *tableChecksum {
+	checksum, ok := c[table]
+	if !ok {
+		checksum = &tableChecksum{}
+		c[table] = checksum
+	}
+	return checksum
+}
+
+func (s *SQLStore) tableChecksums(batchSize int) (tableChecksums, error) {
+	checksums := tableChecksums{}
+	err := s.ReadTables(batchSize, func(batch *model.TableBatch) error {
+		checksum := checksums.get(batch.Table)
+		for _, row := range batch.Rows {
+			rowSum := rowChecksum(batch.Columns, row)
+			for i := range checksum.sum {
+				checksum.sum[i] ^= rowSum[i]
+			}
+			checksum.rows++
+		}
+		return nil
+	})
+	return checksums, err
+}
+
+// rowChecksum returns the checksum of the values of a row, sorted by
+// column name.
+func rowChecksum(columns []string, row []interface{}) [sha256.Size]byte {
+	order := make([]int, len(columns))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return columns[order[i]] < columns[order[j]] })
+
+	var buf bytes.Buffer
+	for _, i := range order {
+		buf.WriteString(columns[i])
+		buf.WriteByte(0)
+		buf.WriteString(canonicalValue(row[i]))
+		buf.WriteByte(0)
+	}
+	return sha256.Sum256(buf.Bytes())
+}
+
+// canonicalValue returns the representation of a normalized column value
+// used by the checksums. Booleans are the same as the integers 0 and 1, as
+// MySQL stores them, and JSON documents are re-encoded, as Postgres and
+// MySQL may change their formatting.
+func canonicalValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "N"
+	case bool:
+		if v {
+			return "I1"
+		}
+		return "I0"
+	case int64:
+		return "I" + strconv.FormatInt(v, 10)
+	case float64:
+		return "F" + strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return "T" + v.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		return "S" + canonicalString(string(v))
+	case string:
+		return "S" + canonicalString(v)
+	}
+	return fmt.Sprintf("V%v", value)
+}
+
+func canonicalString(str string) string {
+	if str == "" || (str[0] != '{' && str[0] != '[') {
+		return str
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(str)))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil || decoder.More() {
+		return str
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return str
+	}
+	return string(b)
+}
@@ -0,0 +1,136 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func TestCopyTables(t *testing.T) {
+	source, tearDownSource := setupTestsWithPrefix(t, "test_")
+	defer tearDownSource()
+	target, tearDownTarget := setupTestsWithPrefix(t, "migrated_")
+	defer tearDownTarget()
+
+	user := &model.User{
+		ID:       utils.NewID(utils.IDTypeUser),
+		Username: "migrated.user",
+		Email:    "migrated.user@example.com",
+	}
+	require.NoError(t, source.CreateUser(user))
+
+	board, err := source.InsertBoard(&model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: "team-id",
+		Type:   model.BoardTypeOpen,
+		Title:  "Migrated board",
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select"},
+		},
+	}, user.ID)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		block := &model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			Title:    "card",
+			Fields:   map[string]interface{}{"properties": map[string]interface{}{"status": "done"}},
+		}
+		require.NoError(t, source.InsertBlock(block, user.ID))
+	}
+
+	t.Run("copy into an empty database", func(t *testing.T) {
+		progress := map[string][]int64{}
+		err := CopyTables(source, target, 2, func(table string, copied, total int64) {
+			require.LessOrEqual(t, copied, total)
+			progress[table] = append(progress[table], copied)
+		})
+		require.NoError(t, err)
+
+		require.Len(t, progress, len(dataTables))
+		require.Equal(t, []int64{2, 4, 5}, progress["blocks"])
+		require.Equal(t, []int64{1}, progress["boards"])
+		require.Equal(t, []int64{0}, progress["import_jobs"])
+
+		verifications, err := VerifyTables(source, target, 3)
+		require.NoError(t, err)
+		require.Len(t, verifications, len(dataTables))
+		for _, v := range verifications {
+			require.True(t, v.Matches(), "table %s doesn't match", v.Table)
+		}
+
+		copiedBoard, err := target.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, board.Title, copiedBoard.Title)
+		require.Equal(t, board.CardProperties, copiedBoard.CardProperties)
+
+		blocks, err := target.GetBlocksForBoard(board.ID)
+		require.NoError(t, err)
+		require.Len(t, blocks, 5)
+	})
+
+	t.Run("verification finds differences", func(t *testing.T) {
+		blocks, err := target.GetBlocksForBoard(board.ID)
+		require.NoError(t, err)
+		title := "changed"
+		require.NoError(t, target.PatchBlock(blocks[0].ID, &model.BlockPatch{Title: &title}, user.ID))
+
+		verifications, err := VerifyTables(source, target, 100)
+		require.NoError(t, err)
+		for _, v := range verifications {
+			switch v.Table {
+			case "blocks":
+				require.False(t, v.Matches())
+				require.Equal(t, v.SourceRows, v.TargetRows)
+			case "blocks_history":
+				require.False(t, v.Matches())
+				require.Equal(t, v.SourceRows+1, v.TargetRows)
+			default:
+				require.True(t, v.Matches(), "table %s doesn't match", v.Table)
+			}
+		}
+	})
+
+	t.Run("copy into a database that is not empty", func(t *testing.T) {
+		err := CopyTables(source, target, 100, nil)
+		require.ErrorIs(t, err, ErrCopyTargetNotEmpty)
+	})
+}
+
+// the values below are returned differently by each database driver for
+// the same data, and must have the same checksums.
+func TestRowChecksum(t *testing.T) {
+	createAt := time.Date(2022, 10, 19, 12, 30, 15, 123000000, time.UTC)
+	mysqlDate, err := normalizeColumnValue([]byte("2022-10-19 12:30:15.123000"), "DATETIME")
+	require.NoError(t, err)
+	mysqlBool, err := normalizeColumnValue([]byte("1"), "TINYINT")
+	require.NoError(t, err)
+	mysqlInt, err := normalizeColumnValue([]byte("42"), "BIGINT")
+	require.NoError(t, err)
+
+	columns := []string{"id", "fields", "create_at", "is_template", "sort_order", "description"}
+	sqlite := []interface{}{"id1", `{"b":1,"a":[1,2]}`, createAt, true, int64(42), nil}
+	postgres := []interface{}{"id1", `{"a": [1, 2], "b": 1}`, createAt.In(time.FixedZone("CEST", 2*60*60)), true, int64(42), nil}
+	mysql := []interface{}{[]byte("id1"), []byte(`{"a": [1, 2], "b": 1}`), mysqlDate, mysqlBool, mysqlInt, nil}
+
+	require.Equal(t, rowChecksum(columns, sqlite), rowChecksum(columns, postgres))
+	require.Equal(t, rowChecksum(columns, sqlite), rowChecksum(columns, mysql))
+
+	// the order of the columns doesn't matter
+	reordered := []string{"fields", "id", "create_at", "is_template", "sort_order", "description"}
+	reorderedRow := []interface{}{sqlite[1], sqlite[0], sqlite[2], sqlite[3], sqlite[4], sqlite[5]}
+	require.Equal(t, rowChecksum(columns, sqlite), rowChecksum(reordered, reorderedRow))
+
+	// but the values do
+	different := []interface{}{"id1", `{"b":1,"a":[2,1]}`, createAt, true, int64(42), nil}
+	require.NotEqual(t, rowChecksum(columns, sqlite), rowChecksum(columns, different))
+	empty := []interface{}{"id1", `{"b":1,"a":[1,2]}`, createAt, true, int64(42), ""}
+	require.NotEqual(t, rowChecksum(columns, sqlite), rowChecksum(columns, empty))
+}
@@ -49,6 +49,7 @@ func (s *SQLStore) getBlocksWithSameID(db sq.BaseRunner) ([]model.Block, error)
 		"update_at",
 		"delete_at",
 		"COALESCE(workspace_id, '0')",
+		"0", // archive_at doesn't exist yet when this migration runs
 	}
 
 	rows, err := s.getQueryBuilder(db).
@@ -68,22 +68,30 @@ func (s *SQLStore) runDataRetention(db sq.BaseRunner, globalRetentionDate int64,
 			PrimaryKeys:   []string{"id"},
 			BoardIDColumn: "board_id",
 		},
+		{
+			Table:         "template_derived_boards",
+			PrimaryKeys:   []string{"board_id"},
+			BoardIDColumn: "board_id",
+		},
 	}
 
+	// archived boards are kept, and the boards with archived cards only
+	// lose the blocks outside of these cards
 	subBuilder := s.getQueryBuilder(db).
-		Select("board_id, MAX(update_at) AS maxDate").
+		Select("board_id, MAX(update_at) AS maxDate, MAX(COALESCE(archive_at, 0)) AS maxArchiveDate").
 		From(s.tablePrefix + "blocks").
 		GroupBy("board_id")
 
 	subQuery, _, _ := subBuilder.ToSql()
 
 	builder := s.getQueryBuilder(db).
-		Select("id").
+		Select("id", "maxArchiveDate").
 		From(s.tablePrefix + "boards").
 		LeftJoin("( " + subQuery + " ) As subquery ON (subquery.board_id = id)").
 		Where(sq.Lt{"maxDate": globalRetentionDate}).
 		Where(sq.NotEq{"team_id": "0"}).
-		Where(sq.Eq{"is_template": false})
+		Where(sq.Eq{"is_template": false}).
+		Where(sq.Eq{"COALESCE(archive_at, 0)": 0})
 
 	rows, err := builder.Query()
 	if err != nil {
@@ -91,8 +99,21 @@ func (s *SQLStore) runDataRetention(db sq.BaseRunner, globalRetentionDate int64,
 		return 0, err
 	}
 	defer s.CloseRows(rows)
-	deleteIds, err := idsFromRows(rows)
-	if err != nil {
+	deleteIds := []string{}
+	withArchivedCardsIds := []string{}
+	for rows.Next() {
+		var boardID string
+		var maxArchiveDate int64
+		if err = rows.Scan(&boardID, &maxArchiveDate); err != nil {
+			return 0, err
+		}
+		if maxArchiveDate == 0 {
+			deleteIds = append(deleteIds, boardID)
+		} else {
+			withArchivedCardsIds = append(withArchivedCardsIds, boardID)
+		}
+	}
+	if err = rows.Err(); err != nil {
 		return 0, err
 	}
 
@@ -106,12 +127,76 @@ func (s *SQLStore) runDataRetention(db sq.BaseRunner, globalRetentionDate int64,
 			totalAffected += int(affected)
 		}
 	}
+
+	if len(withArchivedCardsIds) > 0 {
+		affected, err := s.deleteUnarchivedBlocks(db, withArchivedCardsIds, batchSize)
+		if err != nil {
+			return int64(totalAffected), err
+		}
+		totalAffected += int(affected)
+	}
+
+	auditAffected, err := s.deleteAuditRecordsInBatches(db, globalRetentionDate, batchSize)
+	if err != nil {
+		return int64(totalAffected), err
+	}
+	totalAffected += int(auditAffected)
+
 	s.logger.Info("Complete Boards Data Retention",
 		mlog.Int("Total deletion ids", len(deleteIds)),
 		mlog.Int("TotalAffected", totalAffected))
 	return int64(totalAffected), nil
 }
 
+// deleteUnarchivedBlocks deletes the blocks of the boards, and their history,
+// except for the archived cards with their descendants, and for the views
+// that show them. The boards themselves are kept.
+func (s *SQLStore) deleteUnarchivedBlocks(db sq.BaseRunner, boardIDs []string, batchSize int64) (int64, error) {
+	rows, err := s.getQueryBuilder(db).
+		Select(s.blockFields()...).
+		From(s.tablePrefix + "blocks").
+		Where(sq.Eq{"board_id": boardIDs}).
+		Query()
+	if err != nil {
+		s.logger.Error(`dataRetention blocks query ERROR`, mlog.Err(err))
+		return 0, err
+	}
+	defer s.CloseRows(rows)
+	blocks, err := s.blocksFromRows(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	keepIds := []string{}
+	archivedIDs := model.ArchivedCardBlockIDs(blocks)
+	for i := range blocks {
+		if archivedIDs[blocks[i].ID] || blocks[i].Type == model.TypeView {
+			keepIds = append(keepIds, blocks[i].ID)
+		}
+	}
+
+	// the history of the blocks deleted before is deleted too
+	whereClause := "board_id IN ('" + strings.Join(boardIDs, "','") + "')"
+	if len(keepIds) > 0 {
+		whereClause += " AND id NOT IN ('" + strings.Join(keepIds, "','") + "')"
+	}
+
+	var totalAffected int64
+	for _, table := range []string{"blocks", "blocks_history"} {
+		info := RetentionTableDeletionInfo{
+			Table:         table,
+			PrimaryKeys:   []string{"id"},
+			BoardIDColumn: "board_id",
+		}
+		affected, err := s.retentionPoliciesDeletion(db, info, whereClause, batchSize)
+		if err != nil {
+			return totalAffected, err
+		}
+		totalAffected += affected
+	}
+	return totalAffected, nil
+}
+
 func idsFromRows(rows *sql.Rows) ([]string, error) {
 	deleteIds := []string{}
 	for rows.Next() {
@@ -136,6 +221,17 @@ func (s *SQLStore) genericRetentionPoliciesDeletion(
 	batchSize int64,
 ) (int64, error) {
 	whereClause := info.BoardIDColumn + " IN ('" + strings.Join(deleteIds, "','") + "')"
+	return s.retentionPoliciesDeletion(db, info, whereClause, batchSize)
+}
+
+// retentionPoliciesDeletion deletes the rows of a table matching the where
+// clause, in batches if batchSize is positive.
+func (s *SQLStore) retentionPoliciesDeletion(
+	db sq.BaseRunner,
+	info RetentionTableDeletionInfo,
+	whereClause string,
+	batchSize int64,
+) (int64, error) {
 	deleteQuery := s.getQueryBuilder(db).
 		Delete(s.tablePrefix + info.Table).
 		Where(whereClause)
@@ -171,7 +267,9 @@ func (s *SQLStore) genericRetentionPoliciesDeletion(
 			return 0, errors.Wrap(err, "failed to get rows affected for "+info.Table)
 		}
 		totalRowsAffected += batchRowsAffected
-		if batchRowsAffected != batchSize {
+		// the history tables can have several rows per key, so a batch
+		// may delete more rows than its size
+		if batchSize <= 0 || batchRowsAffected < batchSize {
 			break
 		}
 	}
@@ -0,0 +1,80 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// upsertFeedToken saves the feed token of a user, replacing the previous
+// one.
+func (s *SQLStore) upsertFeedToken(db sq.BaseRunner, feedToken *model.FeedToken) error {
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"feed_tokens").
+		Columns("user_id", "token", "create_at").
+		Values(feedToken.UserID, feedToken.Token, feedToken.CreateAt)
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE token = ?, create_at = ?", feedToken.Token, feedToken.CreateAt)
+	} else {
+		query = query.Suffix(
+			`ON CONFLICT (user_id)
+			 DO UPDATE SET token = EXCLUDED.token, create_at = EXCLUDED.create_at`,
+		)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot upsert feed token", mlog.String("user_id", feedToken.UserID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getFeedTokenWhere(db sq.BaseRunner, where sq.Eq) (*model.FeedToken, error) {
+	row := s.getQueryBuilder(db).
+		Select("user_id", "token", "create_at").
+		From(s.tablePrefix + "feed_tokens").
+		Where(where).
+		QueryRow()
+
+	var feedToken model.FeedToken
+	err := row.Scan(&feedToken.UserID, &feedToken.Token, &feedToken.CreateAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewErrNotFound("feed token")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &feedToken, nil
+}
+
+func (s *SQLStore) getFeedToken(db sq.BaseRunner, userID string) (*model.FeedToken, error) {
+	return s.getFeedTokenWhere(db, sq.Eq{"user_id": userID})
+}
+
+func (s *SQLStore) getFeedTokenByToken(db sq.BaseRunner, token string) (*model.FeedToken, error) {
+	return s.getFeedTokenWhere(db, sq.Eq{"token": token})
+}
+
+func (s *SQLStore) deleteFeedToken(db sq.BaseRunner, userID string) error {
+	result, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "feed_tokens").
+		Where(sq.Eq{"user_id": userID}).
+		Exec()
+	if err != nil {
+		s.logger.Error("Cannot delete feed token", mlog.String("user_id", userID), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("feed token userID=" + userID)
+	}
+	return nil
+}
@@ -2,14 +2,18 @@ package sqlstore
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 
 	sq "github.com/Masterminds/squirrel"
-	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	mmModel "github.com/mattermost/mattermost-server/v6/model"
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
 )
 
-func (s *SQLStore) saveFileInfo(db sq.BaseRunner, fileInfo *model.FileInfo) error {
+func (s *SQLStore) saveFileInfo(db sq.BaseRunner, fileInfo *mmModel.FileInfo) error {
 	query := s.getQueryBuilder(db).
 		Insert(s.tablePrefix+"file_info").
 		Columns(
@@ -20,6 +24,13 @@ func (s *SQLStore) saveFileInfo(db sq.BaseRunner, fileInfo *model.FileInfo) erro
 			"size",
 			"delete_at",
 			"archived",
+			"path",
+			"thumbnail_path",
+			"preview_path",
+			"mime_type",
+			"width",
+			"height",
+			"has_preview_image",
 		).
 		Values(
 			fileInfo.Id,
@@ -29,6 +40,13 @@ func (s *SQLStore) saveFileInfo(db sq.BaseRunner, fileInfo *model.FileInfo) erro
 			fileInfo.Size,
 			fileInfo.DeleteAt,
 			false,
+			fileInfo.Path,
+			fileInfo.ThumbnailPath,
+			fileInfo.PreviewPath,
+			fileInfo.MimeType,
+			fileInfo.Width,
+			fileInfo.Height,
+			fileInfo.HasPreviewImage,
 		)
 
 	if _, err := query.Exec(); err != nil {
@@ -44,7 +62,26 @@ func (s *SQLStore) saveFileInfo(db sq.BaseRunner, fileInfo *model.FileInfo) erro
 	return nil
 }
 
-func (s *SQLStore) getFileInfo(db sq.BaseRunner, id string) (*model.FileInfo, error) {
+// updateFileInfoImages saves the dimensions, thumbnail and preview of an
+// image file, generated once its scan found it clean.
+func (s *SQLStore) updateFileInfoImages(db sq.BaseRunner, fileInfo *mmModel.FileInfo) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"file_info").
+		Set("thumbnail_path", fileInfo.ThumbnailPath).
+		Set("preview_path", fileInfo.PreviewPath).
+		Set("width", fileInfo.Width).
+		Set("height", fileInfo.Height).
+		Set("has_preview_image", fileInfo.HasPreviewImage).
+		Where(sq.Eq{"id": fileInfo.Id})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("failed to update the images of a fileinfo", mlog.String("id", fileInfo.Id), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getFileInfo(db sq.BaseRunner, id string) (*mmModel.FileInfo, error) {
 	query := s.getQueryBuilder(db).
 		Select(
 			"id",
@@ -54,13 +91,20 @@ func (s *SQLStore) getFileInfo(db sq.BaseRunner, id string) (*model.FileInfo, er
 			"extension",
 			"size",
 			"archived",
+			"path",
+			"thumbnail_path",
+			"preview_path",
+			"mime_type",
+			"width",
+			"height",
+			"has_preview_image",
 		).
 		From(s.tablePrefix + "file_info").
 		Where(sq.Eq{"Id": id})
 
 	row := query.QueryRow()
 
-	fileInfo := model.FileInfo{}
+	fileInfo := mmModel.FileInfo{}
 
 	err := row.Scan(
 		&fileInfo.Id,
@@ -70,6 +114,13 @@ func (s *SQLStore) getFileInfo(db sq.BaseRunner, id string) (*model.FileInfo, er
 		&fileInfo.Extension,
 		&fileInfo.Size,
 		&fileInfo.Archived,
+		&fileInfo.Path,
+		&fileInfo.ThumbnailPath,
+		&fileInfo.PreviewPath,
+		&fileInfo.MimeType,
+		&fileInfo.Width,
+		&fileInfo.Height,
+		&fileInfo.HasPreviewImage,
 	)
 
 	if err != nil {
@@ -83,3 +134,138 @@ func (s *SQLStore) getFileInfo(db sq.BaseRunner, id string) (*model.FileInfo, er
 
 	return &fileInfo, nil
 }
+
+func (s *SQLStore) getFileInfosCreatedBefore(db sq.BaseRunner, createAt int64) ([]*mmModel.FileInfo, error) {
+	query := s.getQueryBuilder(db).
+		Select(
+			"id",
+			"create_at",
+			"delete_at",
+			"name",
+			"extension",
+			"size",
+			"archived",
+			"path",
+			"thumbnail_path",
+			"preview_path",
+			"mime_type",
+			"width",
+			"height",
+			"has_preview_image",
+		).
+		From(s.tablePrefix + "file_info").
+		Where(sq.Lt{"create_at": createAt}).
+		OrderBy("create_at")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("error getting fileinfos", mlog.Int64("create_at", createAt), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	fileInfos := []*mmModel.FileInfo{}
+	for rows.Next() {
+		var fileInfo mmModel.FileInfo
+		err := rows.Scan(
+			&fileInfo.Id,
+			&fileInfo.CreateAt,
+			&fileInfo.DeleteAt,
+			&fileInfo.Name,
+			&fileInfo.Extension,
+			&fileInfo.Size,
+			&fileInfo.Archived,
+			&fileInfo.Path,
+			&fileInfo.ThumbnailPath,
+			&fileInfo.PreviewPath,
+			&fileInfo.MimeType,
+			&fileInfo.Width,
+			&fileInfo.Height,
+			&fileInfo.HasPreviewImage,
+		)
+		if err != nil {
+			s.logger.Error("error scanning fileinfo row", mlog.Err(err))
+			return nil, err
+		}
+		fileInfos = append(fileInfos, &fileInfo)
+	}
+	return fileInfos, rows.Err()
+}
+
+func (s *SQLStore) deleteFileInfo(db sq.BaseRunner, id string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "file_info").
+		Where(sq.Eq{"id": id})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("failed to delete fileinfo", mlog.String("id", id), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// getTeamFilesSize returns the total size of the files uploaded in the boards
+// of a team, which are stored under the directory of the team.
+func (s *SQLStore) getTeamFilesSize(db sq.BaseRunner, teamID string) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Select("COALESCE(SUM(size), 0)").
+		From(s.tablePrefix + "file_info").
+		Where(sq.Like{"path": teamID + "/%"}).
+		Where(sq.Eq{"delete_at": 0})
+
+	var size int64
+	if err := query.QueryRow().Scan(&size); err != nil {
+		s.logger.Error("failed to get the size of the team files", mlog.String("team_id", teamID), mlog.Err(err))
+		return 0, err
+	}
+	return size, nil
+}
+
+// getBlockFileIDs returns the names of the files referenced by the blocks
+// of any type, including the deleted blocks and the previous versions of
+// the blocks, which can be restored.
+func (s *SQLStore) getBlockFileIDs(db sq.BaseRunner) ([]string, error) {
+	// the fields are stored as JSON on Postgres
+	fieldsColumn := "fields"
+	if s.dbType == model.PostgresDBType {
+		fieldsColumn = "fields::text"
+	}
+
+	fileIDs := []string{}
+	seen := map[string]bool{}
+	for _, table := range []string{"blocks", "blocks_history"} {
+		rows, err := s.getQueryBuilder(db).
+			Select("fields").
+			From(s.tablePrefix + table).
+			Where(sq.Like{fieldsColumn: `%"fileId"%`}).
+			Query()
+		if err != nil {
+			s.logger.Error("failed to get the blocks with files", mlog.String("table", table), mlog.Err(err))
+			return nil, err
+		}
+
+		for rows.Next() {
+			var fieldsJSON string
+			if err := rows.Scan(&fieldsJSON); err != nil {
+				s.CloseRows(rows)
+				return nil, err
+			}
+
+			var fields map[string]interface{}
+			if err := json.Unmarshal([]byte(fieldsJSON), &fields); err != nil {
+				s.logger.Warn("invalid fields of a block", mlog.String("table", table), mlog.Err(err))
+				continue
+			}
+			if fileID, ok := fields["fileId"].(string); ok && fileID != "" && !seen[fileID] {
+				seen[fileID] = true
+				fileIDs = append(fileIDs, fileID)
+			}
+		}
+		err = rows.Err()
+		s.CloseRows(rows)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fileIDs, nil
+}
@@ -0,0 +1,155 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+var fileScanFields = []string{
+	"board_id",
+	"file_id",
+	"team_id",
+	"created_by",
+	"status",
+	"result",
+	"create_at",
+	"update_at",
+}
+
+func (s *SQLStore) fileScansFromRows(rows *sql.Rows) ([]*model.FileScan, error) {
+	scans := []*model.FileScan{}
+
+	for rows.Next() {
+		var scan model.FileScan
+		var result sql.NullString
+		err := rows.Scan(
+			&scan.BoardID,
+			&scan.FileID,
+			&scan.TeamID,
+			&scan.CreatedBy,
+			&scan.Status,
+			&result,
+			&scan.CreateAt,
+			&scan.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		scan.Result = result.String
+		scans = append(scans, &scan)
+	}
+	return scans, rows.Err()
+}
+
+func (s *SQLStore) insertFileScan(db sq.BaseRunner, scan *model.FileScan) error {
+	if scan.Status == "" {
+		scan.Status = model.FileScanStatusPending
+	}
+	now := utils.GetMillis()
+	scan.CreateAt = now
+	scan.UpdateAt = now
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"file_scans").
+		Columns(fileScanFields...).
+		Values(
+			scan.BoardID,
+			scan.FileID,
+			scan.TeamID,
+			scan.CreatedBy,
+			scan.Status,
+			scan.Result,
+			scan.CreateAt,
+			scan.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert file scan", mlog.String("board_id", scan.BoardID), mlog.String("file_id", scan.FileID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// updateFileScan saves the status and the result of a file scan.
+func (s *SQLStore) updateFileScan(db sq.BaseRunner, scan *model.FileScan) error {
+	scan.UpdateAt = utils.GetMillis()
+
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"file_scans").
+		Set("status", scan.Status).
+		Set("result", scan.Result).
+		Set("update_at", scan.UpdateAt).
+		Where(sq.Eq{"board_id": scan.BoardID, "file_id": scan.FileID}).
+		Exec()
+	if err != nil {
+		s.logger.Error("Cannot update file scan", mlog.String("board_id", scan.BoardID), mlog.String("file_id", scan.FileID), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("file scan fileID=" + scan.FileID)
+	}
+	return nil
+}
+
+func (s *SQLStore) getFileScan(db sq.BaseRunner, boardID, fileID string) (*model.FileScan, error) {
+	rows, err := s.getQueryBuilder(db).
+		Select(fileScanFields...).
+		From(s.tablePrefix + "file_scans").
+		Where(sq.Eq{"board_id": boardID, "file_id": fileID}).
+		Query()
+	if err != nil {
+		s.logger.Error(`getFileScan ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	scans, err := s.fileScansFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(scans) == 0 {
+		return nil, model.NewErrNotFound("file scan fileID=" + fileID)
+	}
+	return scans[0], nil
+}
+
+// getUnfinishedFileScans returns the pending and failed scans that weren't
+// updated since updatedBefore, from the oldest one.
+func (s *SQLStore) getUnfinishedFileScans(db sq.BaseRunner, updatedBefore int64) ([]*model.FileScan, error) {
+	rows, err := s.getQueryBuilder(db).
+		Select(fileScanFields...).
+		From(s.tablePrefix + "file_scans").
+		Where(sq.Eq{"status": []string{model.FileScanStatusPending, model.FileScanStatusFailed}}).
+		Where(sq.Lt{"update_at": updatedBefore}).
+		OrderBy("update_at").
+		Query()
+	if err != nil {
+		s.logger.Error(`getUnfinishedFileScans ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.fileScansFromRows(rows)
+}
+
+func (s *SQLStore) deleteFileScan(db sq.BaseRunner, boardID, fileID string) error {
+	_, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "file_scans").
+		Where(sq.Eq{"board_id": boardID, "file_id": fileID}).
+		Exec()
+	if err != nil {
+		s.logger.Error("Cannot delete file scan", mlog.String("board_id", boardID), mlog.String("file_id", fileID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
@@ -12,6 +12,10 @@ import (
 )
 
 func SetupTests(t *testing.T) (store.Store, func()) {
+	return setupTestsWithPrefix(t, "test_")
+}
+
+func setupTestsWithPrefix(t *testing.T, tablePrefix string) (*SQLStore, func()) {
 	dbType, connectionString, err := PrepareNewTestDatabase()
 	require.NoError(t, err)
 
@@ -25,7 +29,7 @@ func SetupTests(t *testing.T) (store.Store, func()) {
 	storeParams := Params{
 		DBType:           dbType,
 		ConnectionString: connectionString,
-		TablePrefix:      "test_",
+		TablePrefix:      tablePrefix,
 		Logger:           logger,
 		DB:               sqlDB,
 		IsPlugin:         false,
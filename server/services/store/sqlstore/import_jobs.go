@@ -0,0 +1,219 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+var importJobFields = []string{
+	"id",
+	"team_id",
+	"created_by",
+	"status",
+	"dry_run",
+	"filename",
+	"file_path",
+	"size",
+	"bytes_read",
+	"boards_processed",
+	"imported_boards",
+	"skipped_lines",
+	"error",
+	"create_at",
+	"update_at",
+}
+
+func (s *SQLStore) importJobsFromRows(rows *sql.Rows) ([]*model.ImportJob, error) {
+	jobs := []*model.ImportJob{}
+
+	for rows.Next() {
+		var job model.ImportJob
+		var filename, filePath, importedBoardsJSON, skippedLinesJSON, jobError sql.NullString
+		err := rows.Scan(
+			&job.ID,
+			&job.TeamID,
+			&job.CreatedBy,
+			&job.Status,
+			&job.DryRun,
+			&filename,
+			&filePath,
+			&job.Size,
+			&job.BytesRead,
+			&job.BoardsProcessed,
+			&importedBoardsJSON,
+			&skippedLinesJSON,
+			&jobError,
+			&job.CreateAt,
+			&job.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		job.Filename = filename.String
+		job.FilePath = filePath.String
+		job.Error = jobError.String
+		job.SetBytesRead(job.BytesRead)
+
+		job.ImportedBoards = map[string]string{}
+		if importedBoardsJSON.String != "" {
+			if err := json.Unmarshal([]byte(importedBoardsJSON.String), &job.ImportedBoards); err != nil {
+				s.logger.Error("Cannot unmarshal import job boards", mlog.String("id", job.ID), mlog.Err(err))
+				return nil, err
+			}
+		}
+		job.SkippedLines = []model.ImportSkippedLine{}
+		if skippedLinesJSON.String != "" {
+			if err := json.Unmarshal([]byte(skippedLinesJSON.String), &job.SkippedLines); err != nil {
+				s.logger.Error("Cannot unmarshal import job skipped lines", mlog.String("id", job.ID), mlog.Err(err))
+				return nil, err
+			}
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+func marshalImportJobResults(job *model.ImportJob) (string, string, error) {
+	if job.ImportedBoards == nil {
+		job.ImportedBoards = map[string]string{}
+	}
+	if job.SkippedLines == nil {
+		job.SkippedLines = []model.ImportSkippedLine{}
+	}
+
+	importedBoardsJSON, err := json.Marshal(job.ImportedBoards)
+	if err != nil {
+		return "", "", err
+	}
+	skippedLinesJSON, err := json.Marshal(job.SkippedLines)
+	if err != nil {
+		return "", "", err
+	}
+	return string(importedBoardsJSON), string(skippedLinesJSON), nil
+}
+
+func (s *SQLStore) insertImportJob(db sq.BaseRunner, job *model.ImportJob) (*model.ImportJob, error) {
+	if job.ID == "" {
+		job.ID = utils.NewID(utils.IDTypeNone)
+	}
+	if job.Status == "" {
+		job.Status = model.ImportJobStatusPending
+	}
+	now := utils.GetMillis()
+	job.CreateAt = now
+	job.UpdateAt = now
+
+	importedBoardsJSON, skippedLinesJSON, err := marshalImportJobResults(job)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"import_jobs").
+		Columns(importJobFields...).
+		Values(
+			job.ID,
+			job.TeamID,
+			job.CreatedBy,
+			job.Status,
+			job.DryRun,
+			job.Filename,
+			job.FilePath,
+			job.Size,
+			job.BytesRead,
+			job.BoardsProcessed,
+			importedBoardsJSON,
+			skippedLinesJSON,
+			job.Error,
+			job.CreateAt,
+			job.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert import job", mlog.String("id", job.ID), mlog.Err(err))
+		return nil, err
+	}
+	return job, nil
+}
+
+// updateImportJob saves the status and the progress of an import job.
+func (s *SQLStore) updateImportJob(db sq.BaseRunner, job *model.ImportJob) (*model.ImportJob, error) {
+	job.UpdateAt = utils.GetMillis()
+
+	importedBoardsJSON, skippedLinesJSON, err := marshalImportJobResults(job)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"import_jobs").
+		Set("status", job.Status).
+		Set("bytes_read", job.BytesRead).
+		Set("boards_processed", job.BoardsProcessed).
+		Set("imported_boards", importedBoardsJSON).
+		Set("skipped_lines", skippedLinesJSON).
+		Set("error", job.Error).
+		Set("update_at", job.UpdateAt).
+		Where(sq.Eq{"id": job.ID}).
+		Exec()
+	if err != nil {
+		s.logger.Error("Cannot update import job", mlog.String("id", job.ID), mlog.Err(err))
+		return nil, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, model.NewErrNotFound(job.ID)
+	}
+	return job, nil
+}
+
+func (s *SQLStore) getImportJob(db sq.BaseRunner, jobID string) (*model.ImportJob, error) {
+	rows, err := s.getQueryBuilder(db).
+		Select(importJobFields...).
+		From(s.tablePrefix + "import_jobs").
+		Where(sq.Eq{"id": jobID}).
+		Query()
+	if err != nil {
+		s.logger.Error(`getImportJob ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	jobs, err := s.importJobsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, model.NewErrNotFound(jobID)
+	}
+	return jobs[0], nil
+}
+
+// getImportJobsForUser returns the import jobs started by a user in a team,
+// from the most recent one.
+func (s *SQLStore) getImportJobsForUser(db sq.BaseRunner, teamID, userID string) ([]*model.ImportJob, error) {
+	rows, err := s.getQueryBuilder(db).
+		Select(importJobFields...).
+		From(s.tablePrefix+"import_jobs").
+		Where(sq.Eq{"team_id": teamID, "created_by": userID}).
+		OrderBy("create_at DESC", "id").
+		Query()
+	if err != nil {
+		s.logger.Error(`getImportJobsForUser ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.importJobsFromRows(rows)
+}
@@ -36,6 +36,7 @@ func legacyBoardFields(prefix string) []string {
 		"create_at",
 		"update_at",
 		"delete_at",
+		"0", // substitute for archive_at column.
 	}
 
 	if prefix == "" {
@@ -47,7 +48,7 @@ func legacyBoardFields(prefix string) []string {
 		switch {
 		case strings.HasPrefix(field, "COALESCE("):
 			prefixedFields[i] = strings.Replace(field, "COALESCE(", "COALESCE("+prefix, 1)
-		case field == "''":
+		case field == "''" || field == "0":
 			prefixedFields[i] = field
 		default:
 			prefixedFields[i] = prefix + field
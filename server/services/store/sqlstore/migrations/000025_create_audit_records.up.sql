@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}audit_records (
+    id VARCHAR(36) NOT NULL,
+    level VARCHAR(32),
+    event VARCHAR(128),
+    status VARCHAR(16),
+    user_id VARCHAR(36),
+    session_id VARCHAR(36),
+    client TEXT,
+    ip_address VARCHAR(64),
+    api_path TEXT,
+    team_id VARCHAR(36),
+    board_id VARCHAR(36),
+    meta TEXT,
+    create_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE INDEX idx_auditrecords_create_at ON {{.prefix}}audit_records(create_at);
+CREATE INDEX idx_auditrecords_user_id_create_at ON {{.prefix}}audit_records(user_id, create_at);
+CREATE INDEX idx_auditrecords_board_id_create_at ON {{.prefix}}audit_records(board_id, create_at);
@@ -0,0 +1,2 @@
+DROP TABLE {{.prefix}}template_derived_boards;
+DROP TABLE {{.prefix}}templates;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}templates (
+    id VARCHAR(36) NOT NULL,
+    team_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    source_board_id VARCHAR(36),
+    category VARCHAR(100),
+    tags TEXT,
+    version INTEGER,
+    created_by VARCHAR(36),
+    modified_by VARCHAR(36),
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE INDEX idx_templates_team_id ON {{.prefix}}templates(team_id);
+CREATE INDEX idx_templates_board_id ON {{.prefix}}templates(board_id);
+CREATE INDEX idx_templates_source_board_id ON {{.prefix}}templates(source_board_id);
+
+CREATE TABLE IF NOT EXISTS {{.prefix}}template_derived_boards (
+    board_id VARCHAR(36) NOT NULL,
+    template_id VARCHAR(36) NOT NULL,
+    template_version INTEGER,
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (board_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE INDEX idx_templatederivedboards_template_id ON {{.prefix}}template_derived_boards(template_id);
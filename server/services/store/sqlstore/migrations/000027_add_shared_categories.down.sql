@@ -0,0 +1,6 @@
+DROP INDEX {{if .mysql}}idx_categories_team_id_type ON {{.prefix}}categories{{else}}idx_categories_team_id_type{{end}};
+
+ALTER TABLE {{.prefix}}categories DROP COLUMN is_default;
+ALTER TABLE {{.prefix}}categories DROP COLUMN sort_order;
+ALTER TABLE {{.prefix}}categories DROP COLUMN parent_id;
+ALTER TABLE {{.prefix}}categories DROP COLUMN type;
@@ -0,0 +1,6 @@
+ALTER TABLE {{.prefix}}categories ADD type VARCHAR(10) DEFAULT 'private';
+ALTER TABLE {{.prefix}}categories ADD parent_id VARCHAR(36) DEFAULT '';
+ALTER TABLE {{.prefix}}categories ADD sort_order BIGINT DEFAULT 0;
+ALTER TABLE {{.prefix}}categories ADD is_default BOOLEAN DEFAULT false;
+
+CREATE INDEX idx_categories_team_id_type ON {{.prefix}}categories(team_id, type);
@@ -0,0 +1 @@
+DROP INDEX {{if .mysql}}idx_blockshistory_board_id_update_at ON {{.prefix}}blocks_history{{else}}idx_blockshistory_board_id_update_at{{end}};
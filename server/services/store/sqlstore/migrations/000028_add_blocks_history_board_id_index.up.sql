@@ -0,0 +1 @@
+CREATE INDEX idx_blockshistory_board_id_update_at ON {{.prefix}}blocks_history(board_id, update_at);
@@ -0,0 +1,2 @@
+ALTER TABLE {{.prefix}}blocks DROP COLUMN archive_at;
+ALTER TABLE {{.prefix}}blocks_history DROP COLUMN archive_at;
@@ -0,0 +1,2 @@
+ALTER TABLE {{.prefix}}boards DROP COLUMN archive_at;
+ALTER TABLE {{.prefix}}boards_history DROP COLUMN archive_at;
@@ -0,0 +1,2 @@
+ALTER TABLE {{.prefix}}boards ADD COLUMN archive_at BIGINT DEFAULT 0;
+ALTER TABLE {{.prefix}}boards_history ADD COLUMN archive_at BIGINT DEFAULT 0;
@@ -0,0 +1 @@
+DROP TABLE {{.prefix}}import_jobs;
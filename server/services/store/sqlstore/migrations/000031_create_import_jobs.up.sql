@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}import_jobs (
+    id VARCHAR(36) NOT NULL,
+    team_id VARCHAR(36) NOT NULL,
+    created_by VARCHAR(36) NOT NULL,
+    status VARCHAR(16),
+    dry_run BOOLEAN,
+    filename TEXT,
+    file_path TEXT,
+    size BIGINT,
+    bytes_read BIGINT,
+    boards_processed INTEGER,
+    imported_boards TEXT,
+    skipped_lines TEXT,
+    error TEXT,
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE INDEX idx_importjobs_team_id_created_by ON {{.prefix}}import_jobs(team_id, created_by);
@@ -0,0 +1,7 @@
+ALTER TABLE {{.prefix}}file_info DROP COLUMN path;
+ALTER TABLE {{.prefix}}file_info DROP COLUMN thumbnail_path;
+ALTER TABLE {{.prefix}}file_info DROP COLUMN preview_path;
+ALTER TABLE {{.prefix}}file_info DROP COLUMN mime_type;
+ALTER TABLE {{.prefix}}file_info DROP COLUMN width;
+ALTER TABLE {{.prefix}}file_info DROP COLUMN height;
+ALTER TABLE {{.prefix}}file_info DROP COLUMN has_preview_image;
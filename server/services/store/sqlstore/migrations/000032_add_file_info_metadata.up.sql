@@ -0,0 +1,7 @@
+ALTER TABLE {{.prefix}}file_info ADD COLUMN path VARCHAR(512) DEFAULT '';
+ALTER TABLE {{.prefix}}file_info ADD COLUMN thumbnail_path VARCHAR(512) DEFAULT '';
+ALTER TABLE {{.prefix}}file_info ADD COLUMN preview_path VARCHAR(512) DEFAULT '';
+ALTER TABLE {{.prefix}}file_info ADD COLUMN mime_type VARCHAR(256) DEFAULT '';
+ALTER TABLE {{.prefix}}file_info ADD COLUMN width INT DEFAULT 0;
+ALTER TABLE {{.prefix}}file_info ADD COLUMN height INT DEFAULT 0;
+ALTER TABLE {{.prefix}}file_info ADD COLUMN has_preview_image BOOLEAN DEFAULT FALSE;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}file_scans (
+    board_id VARCHAR(36) NOT NULL,
+    file_id VARCHAR(64) NOT NULL,
+    team_id VARCHAR(36) NOT NULL,
+    created_by VARCHAR(36) NOT NULL,
+    status VARCHAR(16),
+    result TEXT,
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (board_id, file_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE INDEX idx_filescans_status_update_at ON {{.prefix}}file_scans(status, update_at);
@@ -0,0 +1 @@
+DROP TABLE {{.prefix}}feed_tokens;
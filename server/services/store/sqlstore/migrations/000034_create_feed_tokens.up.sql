@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}feed_tokens (
+    user_id VARCHAR(36) NOT NULL,
+    token VARCHAR(36) NOT NULL,
+    create_at BIGINT,
+    PRIMARY KEY (user_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE UNIQUE INDEX idx_feedtokens_token ON {{.prefix}}feed_tokens(token);
@@ -104,6 +104,30 @@ func (s *SQLStore) CreateCategory(category model.Category) error {
 
 }
 
+func (s *SQLStore) CreateImportedBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, job *model.ImportJob) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.createImportedBoardsAndBlocks(s.db, bab, userID, job)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, nil, txErr
+	}
+	result, resultVar1, err := s.createImportedBoardsAndBlocks(tx, bab, userID, job)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "CreateImportedBoardsAndBlocks"))
+		}
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return result, resultVar1, nil
+
+}
+
 func (s *SQLStore) CreateSession(session *model.Session) error {
 	return s.createSession(s.db, session)
 
@@ -119,6 +143,16 @@ func (s *SQLStore) CreateUser(user *model.User) error {
 
 }
 
+func (s *SQLStore) DeactivateUser(userID string) error {
+	return s.deactivateUser(s.db, userID)
+
+}
+
+func (s *SQLStore) DeleteAuditRecordsBefore(date int64) (int64, error) {
+	return s.deleteAuditRecordsBefore(s.db, date)
+
+}
+
 func (s *SQLStore) DeleteBlock(blockID string, modifiedBy string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.deleteBlock(s.db, blockID, modifiedBy)
@@ -143,6 +177,30 @@ func (s *SQLStore) DeleteBlock(blockID string, modifiedBy string) error {
 
 }
 
+func (s *SQLStore) DeleteBlocks(blockIDs []string, modifiedBy string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteBlocks(s.db, blockIDs, modifiedBy)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.deleteBlocks(tx, blockIDs, modifiedBy)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteBlocks"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
 func (s *SQLStore) DeleteBoard(boardID string, userID string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.deleteBoard(s.db, boardID, userID)
@@ -196,6 +254,21 @@ func (s *SQLStore) DeleteCategory(categoryID string, userID string, teamID strin
 
 }
 
+func (s *SQLStore) DeleteFeedToken(userID string) error {
+	return s.deleteFeedToken(s.db, userID)
+
+}
+
+func (s *SQLStore) DeleteFileInfo(id string) error {
+	return s.deleteFileInfo(s.db, id)
+
+}
+
+func (s *SQLStore) DeleteFileScan(boardID string, fileID string) error {
+	return s.deleteFileScan(s.db, boardID, fileID)
+
+}
+
 func (s *SQLStore) DeleteMember(boardID string, userID string) error {
 	return s.deleteMember(s.db, boardID, userID)
 
@@ -211,11 +284,40 @@ func (s *SQLStore) DeleteSession(sessionID string) error {
 
 }
 
+func (s *SQLStore) DeleteSessionsForUser(userID string) (int64, error) {
+	return s.deleteSessionsForUser(s.db, userID)
+
+}
+
 func (s *SQLStore) DeleteSubscription(blockID string, subscriberID string) error {
 	return s.deleteSubscription(s.db, blockID, subscriberID)
 
 }
 
+func (s *SQLStore) DeleteTemplate(templateID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteTemplate(s.db, templateID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.deleteTemplate(tx, templateID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteTemplate"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
 func (s *SQLStore) DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]model.Block, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.duplicateBlock(s.db, boardID, blockID, userID, asTemplate)
@@ -274,6 +376,16 @@ func (s *SQLStore) GetAllTeams() ([]*model.Team, error) {
 
 }
 
+func (s *SQLStore) GetAllUsers() ([]*model.User, error) {
+	return s.getAllUsers(s.db)
+
+}
+
+func (s *SQLStore) GetAuditRecords(opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, error) {
+	return s.getAuditRecords(s.db, opts)
+
+}
+
 func (s *SQLStore) GetBlock(blockID string) (*model.Block, error) {
 	return s.getBlock(s.db, blockID)
 
@@ -284,6 +396,11 @@ func (s *SQLStore) GetBlockCountsByType() (map[string]int64, error) {
 
 }
 
+func (s *SQLStore) GetBlockFileIDs() ([]string, error) {
+	return s.getBlockFileIDs(s.db)
+
+}
+
 func (s *SQLStore) GetBlockHistory(blockID string, opts model.QueryBlockHistoryOptions) ([]model.Block, error) {
 	return s.getBlockHistory(s.db, blockID, opts)
 
@@ -294,6 +411,11 @@ func (s *SQLStore) GetBlockHistoryDescendants(boardID string, opts model.QueryBl
 
 }
 
+func (s *SQLStore) GetBlockTombstones(boardID string, since int64) ([]model.BlockTombstone, error) {
+	return s.getBlockTombstones(s.db, boardID, since)
+
+}
+
 func (s *SQLStore) GetBlocksByIDs(ids []string) ([]model.Block, error) {
 	return s.getBlocksByIDs(s.db, ids)
 
@@ -304,6 +426,11 @@ func (s *SQLStore) GetBlocksForBoard(boardID string) ([]model.Block, error) {
 
 }
 
+func (s *SQLStore) GetBlocksPage(boardID string, opts model.QueryBlocksPageOptions) ([]model.Block, error) {
+	return s.getBlocksPage(s.db, boardID, opts)
+
+}
+
 func (s *SQLStore) GetBlocksWithBoardID(boardID string) ([]model.Block, error) {
 	return s.getBlocksWithBoardID(s.db, boardID)
 
@@ -349,21 +476,46 @@ func (s *SQLStore) GetBoardMemberHistory(boardID string, userID string, limit ui
 
 }
 
+func (s *SQLStore) GetBoardsByIDs(boardIDs []string) ([]*model.Board, error) {
+	return s.getBoardsByIDs(s.db, boardIDs)
+
+}
+
 func (s *SQLStore) GetBoardsForUserAndTeam(userID string, teamID string) ([]*model.Board, error) {
 	return s.getBoardsForUserAndTeam(s.db, userID, teamID)
 
 }
 
+func (s *SQLStore) GetBoardsInTeam(teamID string) ([]*model.Board, error) {
+	return s.getBoardsInTeam(s.db, teamID)
+
+}
+
 func (s *SQLStore) GetBoardsInTeamByIds(boardIDs []string, teamID string) ([]*model.Board, error) {
 	return s.getBoardsInTeamByIds(s.db, boardIDs, teamID)
 
 }
 
+func (s *SQLStore) GetBoardsWithAutoArchiveRules() ([]*model.Board, error) {
+	return s.getBoardsWithAutoArchiveRules(s.db)
+
+}
+
+func (s *SQLStore) GetCardHistory(boardID string, propertyIDs []string, until int64) ([]*model.CardHistoryEntry, error) {
+	return s.getCardHistory(s.db, boardID, propertyIDs, until)
+
+}
+
 func (s *SQLStore) GetCardLimitTimestamp() (int64, error) {
 	return s.getCardLimitTimestamp(s.db)
 
 }
 
+func (s *SQLStore) GetCardsForUser(query *model.UserCardsQuery) ([]model.Block, error) {
+	return s.getCardsForUser(s.db, query)
+
+}
+
 func (s *SQLStore) GetCategory(id string) (*model.Category, error) {
 	return s.getCategory(s.db, id)
 
@@ -379,11 +531,46 @@ func (s *SQLStore) GetCloudLimits() (*mmModel.ProductLimits, error) {
 
 }
 
+func (s *SQLStore) GetDefaultSharedCategory(teamID string) (*model.Category, error) {
+	return s.getDefaultSharedCategory(s.db, teamID)
+
+}
+
+func (s *SQLStore) GetFeedToken(userID string) (*model.FeedToken, error) {
+	return s.getFeedToken(s.db, userID)
+
+}
+
+func (s *SQLStore) GetFeedTokenByToken(token string) (*model.FeedToken, error) {
+	return s.getFeedTokenByToken(s.db, token)
+
+}
+
 func (s *SQLStore) GetFileInfo(id string) (*mmModel.FileInfo, error) {
 	return s.getFileInfo(s.db, id)
 
 }
 
+func (s *SQLStore) GetFileInfosCreatedBefore(createAt int64) ([]*mmModel.FileInfo, error) {
+	return s.getFileInfosCreatedBefore(s.db, createAt)
+
+}
+
+func (s *SQLStore) GetFileScan(boardID string, fileID string) (*model.FileScan, error) {
+	return s.getFileScan(s.db, boardID, fileID)
+
+}
+
+func (s *SQLStore) GetImportJob(jobID string) (*model.ImportJob, error) {
+	return s.getImportJob(s.db, jobID)
+
+}
+
+func (s *SQLStore) GetImportJobsForUser(teamID string, userID string) ([]*model.ImportJob, error) {
+	return s.getImportJobsForUser(s.db, teamID, userID)
+
+}
+
 func (s *SQLStore) GetLicense() *mmModel.License {
 	return s.getLicense(s.db)
 
@@ -424,6 +611,16 @@ func (s *SQLStore) GetSession(token string, expireTime int64) (*model.Session, e
 
 }
 
+func (s *SQLStore) GetSharedCategories(teamID string) ([]model.Category, error) {
+	return s.getSharedCategories(s.db, teamID)
+
+}
+
+func (s *SQLStore) GetSharedCategoryBoards(teamID string) ([]model.CategoryBoards, error) {
+	return s.getSharedCategoryBoards(s.db, teamID)
+
+}
+
 func (s *SQLStore) GetSharing(rootID string) (*model.Sharing, error) {
 	return s.getSharing(s.db, rootID)
 
@@ -479,16 +676,56 @@ func (s *SQLStore) GetTeamCount() (int64, error) {
 
 }
 
+func (s *SQLStore) GetTeamFilesSize(teamID string) (int64, error) {
+	return s.getTeamFilesSize(s.db, teamID)
+
+}
+
 func (s *SQLStore) GetTeamsForUser(userID string) ([]*model.Team, error) {
 	return s.getTeamsForUser(s.db, userID)
 
 }
 
+func (s *SQLStore) GetTemplate(templateID string) (*model.Template, error) {
+	return s.getTemplate(s.db, templateID)
+
+}
+
 func (s *SQLStore) GetTemplateBoards(teamID string, userID string) ([]*model.Board, error) {
 	return s.getTemplateBoards(s.db, teamID, userID)
 
 }
 
+func (s *SQLStore) GetTemplateDerivedBoard(boardID string) (*model.TemplateDerivedBoard, error) {
+	return s.getTemplateDerivedBoard(s.db, boardID)
+
+}
+
+func (s *SQLStore) GetTemplateDerivedBoards(templateID string) ([]*model.TemplateDerivedBoard, error) {
+	return s.getTemplateDerivedBoards(s.db, templateID)
+
+}
+
+func (s *SQLStore) GetTemplateForBoard(boardID string) (*model.Template, error) {
+	return s.getTemplateForBoard(s.db, boardID)
+
+}
+
+func (s *SQLStore) GetTemplateForSourceBoard(teamID string, sourceBoardID string) (*model.Template, error) {
+	return s.getTemplateForSourceBoard(s.db, teamID, sourceBoardID)
+
+}
+
+func (s *SQLStore) GetTemplates(opts model.QueryTemplatesOptions) ([]*model.Template, error) {
+	return s.getTemplates(s.db, opts)
+
+}
+
+func (s *SQLStore) GetUnfinishedFileScans(updatedBefore int64) ([]*model.FileScan, error) {
+	return s.getUnfinishedFileScans(s.db, updatedBefore)
+
+}
+
 func (s *SQLStore) GetUsedCardsCount() (int, error) {
 	return s.getUsedCardsCount(s.db)
 
@@ -534,6 +771,11 @@ func (s *SQLStore) GetUsersList(userIDs []string) ([]*model.User, error) {
 
 }
 
+func (s *SQLStore) InsertAuditRecord(record *model.AuditRecord) error {
+	return s.insertAuditRecord(s.db, record)
+
+}
+
 func (s *SQLStore) InsertBlock(block *model.Block, userID string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.insertBlock(s.db, block, userID)
@@ -611,6 +853,45 @@ func (s *SQLStore) InsertBoardWithAdmin(board *model.Board, userID string) (*mod
 
 }
 
+func (s *SQLStore) InsertFileScan(scan *model.FileScan) error {
+	return s.insertFileScan(s.db, scan)
+
+}
+
+func (s *SQLStore) InsertImportJob(job *model.ImportJob) (*model.ImportJob, error) {
+	return s.insertImportJob(s.db, job)
+
+}
+
+func (s *SQLStore) InsertTemplate(template *model.Template) (*model.Template, error) {
+	return s.insertTemplate(s.db, template)
+
+}
+
+func (s *SQLStore) MoveBlocks(blocks []model.Block, userID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.moveBlocks(s.db, blocks, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.moveBlocks(tx, blocks, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "MoveBlocks"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
 func (s *SQLStore) PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.patchBlock(s.db, blockID, blockPatch, userID)
@@ -746,6 +1027,30 @@ func (s *SQLStore) RunDataRetention(globalRetentionDate int64, batchSize int64)
 
 }
 
+func (s *SQLStore) SaveCardChanges(changes *model.CardChanges, userID string, check model.CardsCheck) error {
+	if s.dbType == model.SqliteDBType {
+		return s.saveCardChanges(s.db, changes, userID, check)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.saveCardChanges(tx, changes, userID, check)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "SaveCardChanges"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
 func (s *SQLStore) SaveFileInfo(fileInfo *mmModel.FileInfo) error {
 	return s.saveFileInfo(s.db, fileInfo)
 
@@ -756,6 +1061,11 @@ func (s *SQLStore) SaveMember(bm *model.BoardMember) (*model.BoardMember, error)
 
 }
 
+func (s *SQLStore) SaveTemplateDerivedBoard(derivedBoard *model.TemplateDerivedBoard) error {
+	return s.saveTemplateDerivedBoard(s.db, derivedBoard)
+
+}
+
 func (s *SQLStore) SearchBoardsForUser(term string, userID string) ([]*model.Board, error) {
 	return s.searchBoardsForUser(s.db, term, userID)
 
@@ -781,11 +1091,59 @@ func (s *SQLStore) SendMessage(message string, postType string, receipts []strin
 
 }
 
+func (s *SQLStore) SetDefaultSharedCategory(teamID string, categoryID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.setDefaultSharedCategory(s.db, teamID, categoryID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.setDefaultSharedCategory(tx, teamID, categoryID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "SetDefaultSharedCategory"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
 func (s *SQLStore) SetSystemSetting(key string, value string) error {
 	return s.setSystemSetting(s.db, key, value)
 
 }
 
+func (s *SQLStore) TransferBoardOwnership(boardID string, userID string) (*model.Board, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.transferBoardOwnership(s.db, boardID, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.transferBoardOwnership(tx, boardID, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "TransferBoardOwnership"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) UndeleteBlock(blockID string, modifiedBy string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.undeleteBlock(s.db, blockID, modifiedBy)
@@ -844,6 +1202,21 @@ func (s *SQLStore) UpdateCategory(category model.Category) error {
 
 }
 
+func (s *SQLStore) UpdateFileInfoImages(fileInfo *mmModel.FileInfo) error {
+	return s.updateFileInfoImages(s.db, fileInfo)
+
+}
+
+func (s *SQLStore) UpdateFileScan(scan *model.FileScan) error {
+	return s.updateFileScan(s.db, scan)
+
+}
+
+func (s *SQLStore) UpdateImportJob(job *model.ImportJob) (*model.ImportJob, error) {
+	return s.updateImportJob(s.db, job)
+
+}
+
 func (s *SQLStore) UpdateSession(session *model.Session) error {
 	return s.updateSession(s.db, session)
 
@@ -854,6 +1227,11 @@ func (s *SQLStore) UpdateSubscribersNotifiedAt(blockID string, notifiedAt int64)
 
 }
 
+func (s *SQLStore) UpdateTemplate(template *model.Template) (*model.Template, error) {
+	return s.updateTemplate(s.db, template)
+
+}
+
 func (s *SQLStore) UpdateUser(user *model.User) error {
 	return s.updateUser(s.db, user)
 
@@ -869,6 +1247,11 @@ func (s *SQLStore) UpdateUserPasswordByID(userID string, password string) error
 
 }
 
+func (s *SQLStore) UpsertFeedToken(feedToken *model.FeedToken) error {
+	return s.upsertFeedToken(s.db, feedToken)
+
+}
+
 func (s *SQLStore) UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error) {
 	return s.upsertNotificationHint(s.db, hint, notificationFreq)
 
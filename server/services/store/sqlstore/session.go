@@ -102,6 +102,19 @@ func (s *SQLStore) deleteSession(db sq.BaseRunner, sessionID string) error {
 	return err
 }
 
+// deleteSessionsForUser deletes the sessions of a user, which logs them out
+// of every client, and returns how many sessions were deleted.
+func (s *SQLStore) deleteSessionsForUser(db sq.BaseRunner, userID string) (int64, error) {
+	query := s.getQueryBuilder(db).Delete(s.tablePrefix + "sessions").
+		Where(sq.Eq{"user_id": userID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 func (s *SQLStore) cleanUpSessions(db sq.BaseRunner, expireTimeSeconds int64) error {
 	query := s.getQueryBuilder(db).Delete(s.tablePrefix + "sessions").
 		Where(sq.Lt{"update_at": utils.GetMillis() - utils.SecondsToMillis(expireTimeSeconds)})
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"net/url"
+	"sync"
 
 	sq "github.com/Masterminds/squirrel"
 
@@ -27,6 +28,10 @@ type SQLStore struct {
 	NewMutexFn       MutexFactory
 	servicesAPI      servicesAPI
 	isBinaryParam    bool
+
+	// cardChangesMutex serializes the card changes on SQLite, whose
+	// writes don't run in transactions
+	cardChangesMutex sync.Mutex
 }
 
 // MutexFactory is used by the store in plugin mode to generate
@@ -157,3 +162,38 @@ func (s *SQLStore) searchUserChannels(db sq.BaseRunner, teamID, userID, query st
 func (s *SQLStore) getChannel(db sq.BaseRunner, teamID, channel string) (*mmModel.Channel, error) {
 	return nil, store.NewNotSupportedError("get channel not supported on standalone mode")
 }
+
+// cardPropertySelector returns the expression extracting a card property
+// from the fields column. The property path is passed as an argument and
+// built with cardPropertyPath.
+func (s *SQLStore) cardPropertySelector() string {
+	if s.dbType == model.PostgresDBType {
+		return "fields->'properties'->>?::text"
+	}
+	if s.dbType == model.MysqlDBType {
+		return "JSON_UNQUOTE(JSON_EXTRACT(fields, ?))"
+	}
+	return "json_extract(fields, ?)"
+}
+
+func (s *SQLStore) cardPropertyPath(propertyID string) string {
+	if s.dbType == model.PostgresDBType {
+		return propertyID
+	}
+	return `$.properties."` + propertyID + `"`
+}
+
+// cardDateSelector returns the expression extracting the end of the range
+// of a date property, or its start if it has no end, in milliseconds. It
+// selects NULL for the cards without a valid date.
+func (s *SQLStore) cardDateSelector(propertyID string) sq.Sqlizer {
+	value := s.cardPropertySelector()
+	path := s.cardPropertyPath(propertyID)
+	if s.dbType == model.PostgresDBType {
+		return sq.Expr(fmt.Sprintf("CASE WHEN (%[1]s) LIKE '{%%}' THEN COALESCE((%[1]s)::jsonb->>'to', (%[1]s)::jsonb->>'from')::bigint END", value), path, path, path)
+	}
+	if s.dbType == model.MysqlDBType {
+		return sq.Expr(fmt.Sprintf("CASE WHEN JSON_VALID(%[1]s) THEN CAST(COALESCE(JSON_EXTRACT(%[1]s, '$.to'), JSON_EXTRACT(%[1]s, '$.from')) AS SIGNED) END", value), path, path, path)
+	}
+	return sq.Expr(fmt.Sprintf("CASE WHEN json_valid(%[1]s) THEN COALESCE(json_extract(%[1]s, '$.to'), json_extract(%[1]s, '$.from')) END", value), path, path, path)
+}
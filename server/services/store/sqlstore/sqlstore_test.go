@@ -25,9 +25,16 @@ func TestSQLStore(t *testing.T) {
 	t.Run("DataRetention", func(t *testing.T) { storetests.StoreTestDataRetention(t, SetupTests) })
 	t.Run("CloudStore", func(t *testing.T) { storetests.StoreTestCloudStore(t, SetupTests) })
 	t.Run("StoreTestFileStore", func(t *testing.T) { storetests.StoreTestFileStore(t, SetupTests) })
+	t.Run("StoreTestFileCleanup", func(t *testing.T) { storetests.StoreTestFileCleanup(t, SetupTests) })
+	t.Run("StoreTestFileScanStore", func(t *testing.T) { storetests.StoreTestFileScanStore(t, SetupTests) })
+	t.Run("StoreTestFeedTokenStore", func(t *testing.T) { storetests.StoreTestFeedTokenStore(t, SetupTests) })
 	t.Run("StoreTestCategoryStore", func(t *testing.T) { storetests.StoreTestCategoryStore(t, SetupTests) })
 	t.Run("StoreTestCategoryBoardsStore", func(t *testing.T) { storetests.StoreTestCategoryBoardsStore(t, SetupTests) })
 	t.Run("BoardsInsightsStore", func(t *testing.T) { storetests.StoreTestBoardsInsightsStore(t, SetupTests) })
+	t.Run("AuditStore", func(t *testing.T) { storetests.StoreTestAuditStore(t, SetupTests) })
+	t.Run("TemplateLibraryStore", func(t *testing.T) { storetests.StoreTestTemplateLibraryStore(t, SetupTests) })
+	t.Run("ImportJobStore", func(t *testing.T) { storetests.StoreTestImportJobStore(t, SetupTests) })
+	t.Run("BackupStore", func(t *testing.T) { storetests.StoreTestBackupStore(t, SetupTests) })
 }
 
 //  tests for  utility functions inside sqlstore.go
@@ -63,3 +70,23 @@ func TestElementInColumn(t *testing.T) {
 		require.Equal(t, inLiteral, "position(? in test_column) > 0")
 	}
 }
+
+func TestCardPropertySelector(t *testing.T) {
+	store, tearDown := SetupTests(t)
+	sqlStore := store.(*SQLStore)
+	defer tearDown()
+
+	selector := sqlStore.cardPropertySelector()
+	path := sqlStore.cardPropertyPath("status")
+	switch sqlStore.dbType {
+	case model.SqliteDBType:
+		require.Equal(t, "json_extract(fields, ?)", selector)
+		require.Equal(t, `$.properties."status"`, path)
+	case model.MysqlDBType:
+		require.Equal(t, "JSON_UNQUOTE(JSON_EXTRACT(fields, ?))", selector)
+		require.Equal(t, `$.properties."status"`, path)
+	case model.PostgresDBType:
+		require.Equal(t, "fields->'properties'->>?::text", selector)
+		require.Equal(t, "status", path)
+	}
+}
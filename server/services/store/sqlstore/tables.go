@@ -0,0 +1,426 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// restoreMaxParams is the maximum number of parameters of the statements
+// inserting rows when restoring a table, below the limit of 999 of older
+// SQLite versions.
+const restoreMaxParams = 900
+
+// datetimeLayout is the layout of the dates returned as text by MySQL.
+const datetimeLayout = "2006-01-02 15:04:05.999999999"
+
+// sqliteDatetimeLayout is the layout of the dates restored into SQLite,
+// which compares them as text. They sort along the dates set by default
+// with STRFTIME, and are read back as time.Time by the driver.
+const sqliteDatetimeLayout = "2006-01-02 15:04:05.000000"
+
+var (
+	ErrUnknownTable      = errors.New("unknown table")
+	ErrInvalidColumnName = errors.New("invalid column name")
+
+	columnNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// dataTable is a table holding the data of a server, with the columns its
+// rows are ordered by when read in batches.
+type dataTable struct {
+	name    string
+	orderBy []string
+}
+
+// dataTables are the tables copied by backups, in the order they are
+// restored. Sessions are left out, as well as the schema migrations, which
+// are applied by the server itself.
+var dataTables = []dataTable{
+	{"system_settings", []string{"id"}},
+	{"teams", []string{"id"}},
+	{"users", []string{"id"}},
+	{"boards", []string{"id"}},
+	{"boards_history", []string{"id", "insert_at"}},
+	{"board_members", []string{"board_id", "user_id"}},
+	{"board_members_history", []string{"board_id", "user_id", "insert_at"}},
+	{"blocks", []string{"id"}},
+	{"blocks_history", []string{"id", "insert_at"}},
+	{"categories", []string{"id"}},
+	{"category_boards", []string{"id"}},
+	{"subscriptions", []string{"block_id", "subscriber_id"}},
+	{"notification_hints", []string{"block_id"}},
+	{"sharing", []string{"id"}},
+	{"file_info", []string{"id"}},
+	{"templates", []string{"id"}},
+	{"template_derived_boards", []string{"board_id"}},
+	{"audit_records", []string{"id"}},
+	{"import_jobs", []string{"id"}},
+	{"file_scans", []string{"board_id", "file_id"}},
+	{"feed_tokens", []string{"user_id"}},
+}
+
+func findDataTable(name string) (dataTable, bool) {
+	for _, table := range dataTables {
+		if table.name == name {
+			return table, true
+		}
+	}
+	return dataTable{}, false
+}
+
+// GetSchemaVersion returns the version of the last migration applied to the
+// database.
+func (s *SQLStore) GetSchemaVersion() (int, error) {
+	var version sql.NullInt64
+	err := s.getQueryBuilder(s.db).
+		Select("MAX(Version)").
+		From(s.tablePrefix + "schema_migrations").
+		QueryRow().
+		Scan(&version)
+	if err != nil {
+		s.logger.Error("Cannot get the schema version", mlog.Err(err))
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+// ReadTables reads the rows of the data tables in a single read-only
+// transaction, so that they form a consistent snapshot, and passes them to
+// fn in batches of up to batchSize rows. Each table is passed at least once,
+// with no rows if it is empty.
+func (s *SQLStore) ReadTables(batchSize int, fn func(batch *model.TableBatch) error) error {
+	tx, err := s.beginSnapshotTx()
+	if err != nil {
+		return err
+	}
+	defer s.endSnapshotTx(tx, "ReadTables")
+
+	for _, table := range dataTables {
+		if err := s.readTable(tx, table, batchSize, fn); err != nil {
+			return fmt.Errorf("cannot read table %s: %w", table.name, err)
+		}
+	}
+	return nil
+}
+
+// beginSnapshotTx starts a read-only transaction whose queries all see the
+// same snapshot of the database.
+func (s *SQLStore) beginSnapshotTx() (*sql.Tx, error) {
+	var opts *sql.TxOptions
+	if s.dbType != model.SqliteDBType {
+		opts = &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	}
+	return s.db.BeginTx(context.Background(), opts)
+}
+
+func (s *SQLStore) endSnapshotTx(tx *sql.Tx, methodName string) {
+	// the transaction only reads, so there is nothing to commit
+	if err := tx.Rollback(); err != nil {
+		s.logger.Error("transaction rollback error", mlog.Err(err), mlog.String("methodName", methodName))
+	}
+}
+
+func (s *SQLStore) readTable(db sq.BaseRunner, table dataTable, batchSize int, fn func(batch *model.TableBatch) error) error {
+	for offset := uint64(0); ; offset += uint64(batchSize) {
+		batch, err := s.readTableBatch(db, table, offset, uint64(batchSize))
+		if err != nil {
+			return err
+		}
+		if len(batch.Rows) == 0 && offset != 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch.Rows) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (s *SQLStore) readTableBatch(db sq.BaseRunner, table dataTable, offset, limit uint64) (*model.TableBatch, error) {
+	rows, err := s.getQueryBuilder(db).
+		Select("*").
+		From(s.tablePrefix + table.name).
+		OrderBy(table.orderBy...).
+		Limit(limit).
+		Offset(offset).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &model.TableBatch{
+		Table:   table.name,
+		Columns: columns,
+		Rows:    [][]interface{}{},
+	}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		for i := range values {
+			if values[i], err = normalizeColumnValue(values[i], columnTypes[i].DatabaseTypeName()); err != nil {
+				return nil, fmt.Errorf("invalid value for column %s: %w", columns[i], err)
+			}
+		}
+		batch.Rows = append(batch.Rows, values)
+	}
+	return batch, rows.Err()
+}
+
+// normalizeColumnValue converts the values returned by the database drivers
+// to the same types whatever the database: strings, int64, float64, bool,
+// time.Time or nil, given the database type name of the column.
+func normalizeColumnValue(value interface{}, typeName string) (interface{}, error) {
+	var str string
+	switch v := value.(type) {
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	default:
+		return value, nil
+	}
+
+	// MySQL returns every value as text
+	typeName = strings.ToUpper(typeName)
+	switch {
+	case strings.Contains(typeName, "INT"):
+		return strconv.ParseInt(str, 10, 64)
+	case strings.Contains(typeName, "BOOL"):
+		return strconv.ParseBool(str)
+	case typeName == "FLOAT" || typeName == "DOUBLE" || typeName == "REAL" || typeName == "DECIMAL":
+		return strconv.ParseFloat(str, 64)
+	case isDatetimeType(typeName):
+		if t, err := time.ParseInLocation(datetimeLayout, str, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return str, nil
+}
+
+func isDatetimeType(typeName string) bool {
+	typeName = strings.ToUpper(typeName)
+	return typeName == "DATETIME" || strings.HasPrefix(typeName, "TIMESTAMP")
+}
+
+// RestoreTables replaces the content of the data tables with the batches
+// returned by next, until it returns io.EOF. The restore happens in a single
+// transaction, and only if the store has no users nor boards other than
+// templates.
+func (s *SQLStore) RestoreTables(next func() (*model.TableBatch, error)) error {
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.restoreTables(tx, next); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "RestoreTables"))
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) restoreTables(db sq.BaseRunner, next func() (*model.TableBatch, error)) error {
+	if err := s.clearDataTables(db); err != nil {
+		return err
+	}
+
+	for {
+		batch, err := next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.insertTableBatch(db, batch); err != nil {
+			return fmt.Errorf("cannot restore table %s: %w", batch.Table, err)
+		}
+	}
+}
+
+// clearDataTables deletes the rows of the data tables, which is only allowed
+// if the store has no users nor boards other than templates. The default
+// templates created when the server started are deleted as well.
+func (s *SQLStore) clearDataTables(db sq.BaseRunner) error {
+	empty, err := s.isEmpty(db)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return model.ErrRestoreNotEmpty
+	}
+
+	for i := len(dataTables) - 1; i >= 0; i-- {
+		if _, err := s.getQueryBuilder(db).Delete(s.tablePrefix + dataTables[i].name).Exec(); err != nil {
+			return fmt.Errorf("cannot clear table %s: %w", dataTables[i].name, err)
+		}
+	}
+	return nil
+}
+
+// isEmpty returns true if the store has no users nor boards other than
+// templates.
+func (s *SQLStore) isEmpty(db sq.BaseRunner) (bool, error) {
+	var users, boards int64
+	err := s.getQueryBuilder(db).
+		Select("COUNT(*)").
+		From(s.tablePrefix + "users").
+		QueryRow().
+		Scan(&users)
+	if err != nil {
+		return false, err
+	}
+
+	err = s.getQueryBuilder(db).
+		Select("COUNT(*)").
+		From(s.tablePrefix + "boards").
+		Where(sq.Eq{"is_template": false}).
+		QueryRow().
+		Scan(&boards)
+	if err != nil {
+		return false, err
+	}
+	return users == 0 && boards == 0, nil
+}
+
+// insertTableBatch inserts the rows of a batch into a data table.
+func (s *SQLStore) insertTableBatch(db sq.BaseRunner, batch *model.TableBatch) error {
+	if _, ok := findDataTable(batch.Table); !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTable, batch.Table)
+	}
+	for _, column := range batch.Columns {
+		if !columnNameRegexp.MatchString(column) {
+			return fmt.Errorf("%w: %q", ErrInvalidColumnName, column)
+		}
+	}
+
+	if len(batch.Rows) == 0 {
+		return nil
+	}
+
+	datetimes, err := s.datetimeColumns(db, batch.Table, batch.Columns)
+	if err != nil {
+		return err
+	}
+
+	rowsPerInsert := 1
+	if len(batch.Columns) != 0 && len(batch.Columns) < restoreMaxParams {
+		rowsPerInsert = restoreMaxParams / len(batch.Columns)
+	}
+
+	for start := 0; start < len(batch.Rows); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(batch.Rows) {
+			end = len(batch.Rows)
+		}
+
+		query := s.getQueryBuilder(db).
+			Insert(s.tablePrefix + batch.Table).
+			Columns(batch.Columns...)
+		for _, row := range batch.Rows[start:end] {
+			query = query.Values(s.restoreRowValues(row, datetimes)...)
+		}
+		if _, err := query.Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// datetimeColumns returns whether each of the columns of a data table holds
+// dates.
+func (s *SQLStore) datetimeColumns(db sq.BaseRunner, table string, columns []string) ([]bool, error) {
+	rows, err := s.getQueryBuilder(db).
+		Select(columns...).
+		From(s.tablePrefix + table).
+		Limit(0).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	datetimes := make([]bool, len(columnTypes))
+	for i, columnType := range columnTypes {
+		datetimes[i] = isDatetimeType(columnType.DatabaseTypeName())
+	}
+	return datetimes, nil
+}
+
+// restoreRowValues returns the values of a row to insert, with its dates
+// converted for the database type of the store. Backups hold dates as RFC
+// 3339 strings, which MySQL rejects and SQLite doesn't sort along the dates
+// it sets itself.
+func (s *SQLStore) restoreRowValues(row []interface{}, datetimes []bool) []interface{} {
+	values := make([]interface{}, len(row))
+	for i, value := range row {
+		values[i] = value
+		if i < len(datetimes) && datetimes[i] {
+			values[i] = s.restoreDatetime(value)
+		}
+	}
+	return values
+}
+
+// restoreDatetime converts a date given as time.Time, or as text in RFC 3339
+// or in the layout of MySQL, to a time.Time in UTC, or to text on SQLite.
+// Other values are returned unchanged.
+func (s *SQLStore) restoreDatetime(value interface{}) interface{} {
+	var t time.Time
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case string:
+		var err error
+		if t, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			if t, err = time.ParseInLocation(datetimeLayout, v, time.UTC); err != nil {
+				return value
+			}
+		}
+	default:
+		return value
+	}
+
+	t = t.UTC()
+	if s.dbType == model.SqliteDBType {
+		return t.Format(sqliteDatetimeLayout)
+	}
+	return t
+}
@@ -0,0 +1,353 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+var templateFields = []string{
+	"id",
+	"team_id",
+	"board_id",
+	"source_board_id",
+	"category",
+	"tags",
+	"version",
+	"created_by",
+	"modified_by",
+	"create_at",
+	"update_at",
+}
+
+var templateDerivedBoardFields = []string{
+	"board_id",
+	"template_id",
+	"template_version",
+	"create_at",
+	"update_at",
+}
+
+func (s *SQLStore) templatesFromRows(rows *sql.Rows) ([]*model.Template, error) {
+	templates := []*model.Template{}
+
+	for rows.Next() {
+		var template model.Template
+		var sourceBoardID, category, tagsJSON sql.NullString
+		err := rows.Scan(
+			&template.ID,
+			&template.TeamID,
+			&template.BoardID,
+			&sourceBoardID,
+			&category,
+			&tagsJSON,
+			&template.Version,
+			&template.CreatedBy,
+			&template.ModifiedBy,
+			&template.CreateAt,
+			&template.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		template.SourceBoardID = sourceBoardID.String
+		template.Category = category.String
+		template.Tags = []string{}
+		if tagsJSON.String != "" {
+			if err := json.Unmarshal([]byte(tagsJSON.String), &template.Tags); err != nil {
+				s.logger.Error("Cannot unmarshal template tags", mlog.String("id", template.ID), mlog.Err(err))
+				return nil, err
+			}
+		}
+		templates = append(templates, &template)
+	}
+	return templates, nil
+}
+
+func (s *SQLStore) templateDerivedBoardsFromRows(rows *sql.Rows) ([]*model.TemplateDerivedBoard, error) {
+	derivedBoards := []*model.TemplateDerivedBoard{}
+
+	for rows.Next() {
+		var derivedBoard model.TemplateDerivedBoard
+		err := rows.Scan(
+			&derivedBoard.BoardID,
+			&derivedBoard.TemplateID,
+			&derivedBoard.TemplateVersion,
+			&derivedBoard.CreateAt,
+			&derivedBoard.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		derivedBoards = append(derivedBoards, &derivedBoard)
+	}
+	return derivedBoards, nil
+}
+
+func (s *SQLStore) insertTemplate(db sq.BaseRunner, template *model.Template) (*model.Template, error) {
+	if err := template.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if template.ID == "" {
+		template.ID = utils.NewID(utils.IDTypeNone)
+	}
+	if template.Tags == nil {
+		template.Tags = []string{}
+	}
+	now := utils.GetMillis()
+	template.CreateAt = now
+	template.UpdateAt = now
+
+	tagsJSON, err := json.Marshal(template.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"templates").
+		Columns(templateFields...).
+		Values(
+			template.ID,
+			template.TeamID,
+			template.BoardID,
+			template.SourceBoardID,
+			template.Category,
+			string(tagsJSON),
+			template.Version,
+			template.CreatedBy,
+			template.ModifiedBy,
+			template.CreateAt,
+			template.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert template", mlog.String("id", template.ID), mlog.Err(err))
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *SQLStore) updateTemplate(db sq.BaseRunner, template *model.Template) (*model.Template, error) {
+	if err := template.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if template.Tags == nil {
+		template.Tags = []string{}
+	}
+	template.UpdateAt = utils.GetMillis()
+
+	tagsJSON, err := json.Marshal(template.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"templates").
+		Set("board_id", template.BoardID).
+		Set("category", template.Category).
+		Set("tags", string(tagsJSON)).
+		Set("version", template.Version).
+		Set("modified_by", template.ModifiedBy).
+		Set("update_at", template.UpdateAt).
+		Where(sq.Eq{"id": template.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("Cannot update template", mlog.String("id", template.ID), mlog.Err(err))
+		return nil, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, model.NewErrNotFound(template.ID)
+	}
+	return template, nil
+}
+
+func (s *SQLStore) getTemplateBy(db sq.BaseRunner, condition sq.Eq) (*model.Template, error) {
+	query := s.getQueryBuilder(db).
+		Select(templateFields...).
+		From(s.tablePrefix + "templates").
+		Where(condition)
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getTemplateBy ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	templates, err := s.templatesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, model.NewErrNotFound("template")
+	}
+	return templates[0], nil
+}
+
+func (s *SQLStore) getTemplate(db sq.BaseRunner, templateID string) (*model.Template, error) {
+	return s.getTemplateBy(db, sq.Eq{"id": templateID})
+}
+
+func (s *SQLStore) getTemplateForBoard(db sq.BaseRunner, boardID string) (*model.Template, error) {
+	return s.getTemplateBy(db, sq.Eq{"board_id": boardID})
+}
+
+func (s *SQLStore) getTemplateForSourceBoard(db sq.BaseRunner, teamID, sourceBoardID string) (*model.Template, error) {
+	return s.getTemplateBy(db, sq.Eq{"team_id": teamID, "source_board_id": sourceBoardID})
+}
+
+// getTemplates returns the templates of a team, filtered by category and
+// tag. Tags are stored serialized, so the tag filter is applied once the
+// templates have been fetched.
+func (s *SQLStore) getTemplates(db sq.BaseRunner, opts model.QueryTemplatesOptions) ([]*model.Template, error) {
+	query := s.getQueryBuilder(db).
+		Select(templateFields...).
+		From(s.tablePrefix+"templates").
+		Where(sq.Eq{"team_id": opts.TeamID}).
+		OrderBy("category", "create_at", "id")
+
+	if opts.Category != "" {
+		query = query.Where(sq.Eq{"category": opts.Category})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getTemplates ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	templates, err := s.templatesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Tag == "" {
+		return templates, nil
+	}
+
+	filtered := []*model.Template{}
+	for _, template := range templates {
+		if template.HasTag(opts.Tag) {
+			filtered = append(filtered, template)
+		}
+	}
+	return filtered, nil
+}
+
+// deleteTemplate removes a template from the library along with the links
+// to the boards created from it. The template board is not deleted.
+func (s *SQLStore) deleteTemplate(db sq.BaseRunner, templateID string) error {
+	result, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "templates").
+		Where(sq.Eq{"id": templateID}).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound(templateID)
+	}
+
+	_, err = s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "template_derived_boards").
+		Where(sq.Eq{"template_id": templateID}).
+		Exec()
+	return err
+}
+
+// saveTemplateDerivedBoard creates or updates the link between a board and
+// the template it was created from.
+func (s *SQLStore) saveTemplateDerivedBoard(db sq.BaseRunner, derivedBoard *model.TemplateDerivedBoard) error {
+	now := utils.GetMillis()
+	if derivedBoard.CreateAt == 0 {
+		derivedBoard.CreateAt = now
+	}
+	derivedBoard.UpdateAt = now
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"template_derived_boards").
+		Columns(templateDerivedBoardFields...).
+		Values(
+			derivedBoard.BoardID,
+			derivedBoard.TemplateID,
+			derivedBoard.TemplateVersion,
+			derivedBoard.CreateAt,
+			derivedBoard.UpdateAt,
+		)
+
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE template_id = ?, template_version = ?, update_at = ?",
+			derivedBoard.TemplateID, derivedBoard.TemplateVersion, derivedBoard.UpdateAt)
+	} else {
+		query = query.Suffix(
+			`ON CONFLICT (board_id)
+			 DO UPDATE SET template_id = EXCLUDED.template_id, template_version = EXCLUDED.template_version, update_at = EXCLUDED.update_at`,
+		)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot save template derived board", mlog.String("board_id", derivedBoard.BoardID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getTemplateDerivedBoards(db sq.BaseRunner, templateID string) ([]*model.TemplateDerivedBoard, error) {
+	query := s.getQueryBuilder(db).
+		Select(templateDerivedBoardFields...).
+		From(s.tablePrefix+"template_derived_boards").
+		Where(sq.Eq{"template_id": templateID}).
+		OrderBy("create_at", "board_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getTemplateDerivedBoards ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.templateDerivedBoardsFromRows(rows)
+}
+
+func (s *SQLStore) getTemplateDerivedBoard(db sq.BaseRunner, boardID string) (*model.TemplateDerivedBoard, error) {
+	query := s.getQueryBuilder(db).
+		Select(templateDerivedBoardFields...).
+		From(s.tablePrefix + "template_derived_boards").
+		Where(sq.Eq{"board_id": boardID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getTemplateDerivedBoard ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	derivedBoards, err := s.templateDerivedBoardsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(derivedBoards) == 0 {
+		return nil, model.NewErrNotFound(boardID)
+	}
+	return derivedBoards[0], nil
+}
@@ -18,6 +18,20 @@ var (
 	errUnsupportedOperation = errors.New("unsupported operation")
 )
 
+var userFields = []string{
+	"id",
+	"username",
+	"email",
+	"password",
+	"mfa_secret",
+	"auth_service",
+	"auth_data",
+	"props",
+	"create_at",
+	"update_at",
+	"delete_at",
+}
+
 type UserNotFoundError struct {
 	id string
 }
@@ -57,19 +71,7 @@ func (s *SQLStore) getUserByCondition(db sq.BaseRunner, condition sq.Eq) (*model
 
 func (s *SQLStore) getUsersByCondition(db sq.BaseRunner, condition interface{}, limit uint64) ([]*model.User, error) {
 	query := s.getQueryBuilder(db).
-		Select(
-			"id",
-			"username",
-			"email",
-			"password",
-			"mfa_secret",
-			"auth_service",
-			"auth_data",
-			"props",
-			"create_at",
-			"update_at",
-			"delete_at",
-		).
+		Select(userFields...).
 		From(s.tablePrefix + "users").
 		Where(sq.Eq{"delete_at": 0}).
 		Where(condition)
@@ -215,6 +217,50 @@ func (s *SQLStore) getUsersByTeam(db sq.BaseRunner, _ string) ([]*model.User, er
 	return s.getUsersByCondition(db, nil, 0)
 }
 
+// getAllUsers returns every user, including the deactivated ones.
+func (s *SQLStore) getAllUsers(db sq.BaseRunner) ([]*model.User, error) {
+	query := s.getQueryBuilder(db).
+		Select(userFields...).
+		From(s.tablePrefix + "users").
+		OrderBy("username")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getAllUsers ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.usersFromRows(rows)
+}
+
+// deactivateUser marks a user as deleted, which prevents them from logging in.
+func (s *SQLStore) deactivateUser(db sq.BaseRunner, userID string) error {
+	now := utils.GetMillis()
+
+	query := s.getQueryBuilder(db).Update(s.tablePrefix+"users").
+		Set("update_at", now).
+		Set("delete_at", now).
+		Where(sq.Eq{"id": userID}).
+		Where(sq.Eq{"delete_at": 0})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+
+	rowCount, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowCount < 1 {
+		return UserNotFoundError{userID}
+	}
+
+	return nil
+}
+
 func (s *SQLStore) searchUsersByTeam(db sq.BaseRunner, _ string, searchQuery string) ([]*model.User, error) {
 	return s.getUsersByCondition(db, &sq.Like{"username": "%" + searchQuery + "%"}, 10)
 }
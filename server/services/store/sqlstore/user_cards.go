@@ -0,0 +1,118 @@
+package sqlstore
+
+import (
+	"sort"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// getCardsForUser returns the unarchived cards of the query boards that the
+// user created or is assigned to in a person or multiPerson property. If
+// the query is paginated, up to PerPage+1 cards are returned to tell if
+// there is a next page.
+func (s *SQLStore) getCardsForUser(db sq.BaseRunner, query *model.UserCardsQuery) ([]model.Block, error) {
+	if len(query.BoardIDs) == 0 {
+		return []model.Block{}, nil
+	}
+
+	assigned := sq.Or{sq.Eq{"created_by": query.UserID}}
+	for _, propertyID := range sortedKeys(query.PersonProperties) {
+		path := s.cardPropertyPath(propertyID)
+		value := s.cardPropertySelector()
+		assigned = append(assigned, sq.And{
+			sq.Eq{"board_id": query.PersonProperties[propertyID]},
+			sq.Or{
+				sq.Expr(value+" = ?", path, query.UserID),
+				// multiPerson values are arrays of user IDs
+				sq.Expr(value+" LIKE ?", path, `%"`+query.UserID+`"%`),
+			},
+		})
+	}
+
+	builder := s.getQueryBuilder(db).
+		Select(s.blockFields()...).
+		From(s.tablePrefix + "blocks").
+		Where(sq.Eq{"board_id": query.BoardIDs}).
+		Where(sq.Eq{"type": model.TypeCard}).
+		Where(sq.Eq{"COALESCE(archive_at, 0)": 0}).
+		Where(assigned)
+
+	if query.StatusOptions != nil {
+		status := sq.Or{}
+		for _, option := range query.StatusOptions {
+			status = append(status, sq.And{
+				sq.Eq{"board_id": option.BoardIDs},
+				sq.Expr(s.cardPropertySelector()+" = ?", s.cardPropertyPath(option.PropertyID), option.OptionID),
+			})
+		}
+		builder = builder.Where(status)
+	}
+
+	dueAt := s.cardDueAtSelector(query.DueProperties)
+	if query.DueAfter != 0 {
+		builder = builder.Where(sq.Expr("? >= ?", dueAt, query.DueAfter))
+	}
+	if query.DueBefore != 0 {
+		builder = builder.Where(sq.Expr("? < ?", dueAt, query.DueBefore))
+	}
+
+	direction := " ASC"
+	if query.Descending {
+		direction = " DESC"
+	}
+	switch query.SortBy {
+	case model.UserCardsSortCreateAt:
+		builder = builder.OrderBy("create_at" + direction)
+	case model.UserCardsSortTitle:
+		builder = builder.OrderBy("title" + direction)
+	case model.UserCardsSortDueAt:
+		// the cards without a due date come last
+		builder = builder.
+			OrderByClause(sq.Expr("CASE WHEN ? IS NULL THEN 1 ELSE 0 END", dueAt)).
+			OrderByClause(sq.Expr("?"+direction, dueAt))
+	default:
+		builder = builder.OrderBy("update_at" + direction)
+	}
+	builder = builder.OrderBy("id" + direction)
+
+	if query.PerPage > 0 {
+		builder = builder.
+			Offset(uint64(query.Page * query.PerPage)).
+			Limit(uint64(query.PerPage + 1))
+	}
+
+	rows, err := builder.Query()
+	if err != nil {
+		s.logger.Error(`getCardsForUser ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.blocksFromRows(rows)
+}
+
+// cardDueAtSelector returns the expression selecting the due date of the
+// cards, read from the date property of their board.
+func (s *SQLStore) cardDueAtSelector(dueProperties map[string][]string) sq.Sqlizer {
+	if len(dueProperties) == 0 {
+		return sq.Expr("NULL")
+	}
+
+	dueAt := sq.Case()
+	for _, propertyID := range sortedKeys(dueProperties) {
+		dueAt = dueAt.When(sq.Eq{"board_id": dueProperties[propertyID]}, s.cardDateSelector(propertyID))
+	}
+	return dueAt
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
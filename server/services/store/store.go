@@ -21,11 +21,16 @@ type Store interface {
 	GetBlocksWithType(boardID, blockType string) ([]model.Block, error)
 	GetSubTree2(boardID, blockID string, opts model.QuerySubtreeOptions) ([]model.Block, error)
 	GetBlocksForBoard(boardID string) ([]model.Block, error)
+	GetBlocksPage(boardID string, opts model.QueryBlocksPageOptions) ([]model.Block, error)
+	GetBlockTombstones(boardID string, since int64) ([]model.BlockTombstone, error)
+	GetCardsForUser(query *model.UserCardsQuery) ([]model.Block, error)
 	// @withTransaction
 	InsertBlock(block *model.Block, userID string) error
 	// @withTransaction
 	DeleteBlock(blockID string, modifiedBy string) error
 	// @withTransaction
+	DeleteBlocks(blockIDs []string, modifiedBy string) error
+	// @withTransaction
 	InsertBlocks(blocks []model.Block, userID string) error
 	// @withTransaction
 	UndeleteBlock(blockID string, modifiedBy string) error
@@ -46,6 +51,10 @@ type Store interface {
 	DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]model.Block, error)
 	// @withTransaction
 	PatchBlocks(blockPatches *model.BlockPatchBatch, userID string) error
+	// @withTransaction
+	MoveBlocks(blocks []model.Block, userID string) error
+	// @withTransaction
+	SaveCardChanges(changes *model.CardChanges, userID string, check model.CardsCheck) error
 
 	Shutdown() error
 
@@ -63,6 +72,8 @@ type Store interface {
 	UpdateUserPassword(username, password string) error
 	UpdateUserPasswordByID(userID, password string) error
 	GetUsersByTeam(teamID string) ([]*model.User, error)
+	GetAllUsers() ([]*model.User, error)
+	DeactivateUser(userID string) error
 	SearchUsersByTeam(teamID string, searchQuery string) ([]*model.User, error)
 	PatchUserProps(userID string, patch model.UserPropPatch) error
 
@@ -72,6 +83,7 @@ type Store interface {
 	RefreshSession(session *model.Session) error
 	UpdateSession(session *model.Session) error
 	DeleteSession(sessionID string) error
+	DeleteSessionsForUser(userID string) (int64, error)
 	CleanUpSessions(expireTime int64) error
 
 	UpsertSharing(sharing model.Sharing) error
@@ -92,6 +104,11 @@ type Store interface {
 	GetBoard(id string) (*model.Board, error)
 	GetBoardsForUserAndTeam(userID, teamID string) ([]*model.Board, error)
 	GetBoardsInTeamByIds(boardIDs []string, teamID string) ([]*model.Board, error)
+	GetBoardsByIDs(boardIDs []string) ([]*model.Board, error)
+	GetBoardsInTeam(teamID string) ([]*model.Board, error)
+	// @withTransaction
+	TransferBoardOwnership(boardID, userID string) (*model.Board, error)
+	GetBoardsWithAutoArchiveRules() ([]*model.Board, error)
 	// @withTransaction
 	DeleteBoard(boardID, userID string) error
 
@@ -109,6 +126,8 @@ type Store interface {
 	// @withTransaction
 	CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error)
 	// @withTransaction
+	CreateImportedBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, job *model.ImportJob) (*model.BoardsAndBlocks, []*model.BoardMember, error)
+	// @withTransaction
 	PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error)
 	// @withTransaction
 	DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID string) error
@@ -117,11 +136,21 @@ type Store interface {
 	CreateCategory(category model.Category) error
 	UpdateCategory(category model.Category) error
 	DeleteCategory(categoryID, userID, teamID string) error
+	GetSharedCategories(teamID string) ([]model.Category, error)
+	GetDefaultSharedCategory(teamID string) (*model.Category, error)
+	// @withTransaction
+	SetDefaultSharedCategory(teamID, categoryID string) error
 
 	GetUserCategoryBoards(userID, teamID string) ([]model.CategoryBoards, error)
+	GetSharedCategoryBoards(teamID string) ([]model.CategoryBoards, error)
 
 	GetFileInfo(id string) (*mmModel.FileInfo, error)
 	SaveFileInfo(fileInfo *mmModel.FileInfo) error
+	UpdateFileInfoImages(fileInfo *mmModel.FileInfo) error
+	GetFileInfosCreatedBefore(createAt int64) ([]*mmModel.FileInfo, error)
+	DeleteFileInfo(id string) error
+	GetTeamFilesSize(teamID string) (int64, error)
+	GetBlockFileIDs() ([]string, error)
 
 	// @withTransaction
 	AddUpdateCategoryBoard(userID, categoryID, blockID string) error
@@ -151,6 +180,11 @@ type Store interface {
 
 	DBType() string
 
+	// Backup and restore, which manage their own transactions
+	GetSchemaVersion() (int, error)
+	ReadTables(batchSize int, fn func(batch *model.TableBatch) error) error
+	RestoreTables(next func() (*model.TableBatch, error)) error
+
 	GetLicense() *mmModel.License
 	GetCloudLimits() (*mmModel.ProductLimits, error)
 	SearchUserChannels(teamID, userID, query string) ([]*mmModel.Channel, error)
@@ -161,6 +195,39 @@ type Store interface {
 	GetTeamBoardsInsights(teamID string, userID string, since int64, offset int, limit int, boardIDs []string) (*model.BoardInsightsList, error)
 	GetUserBoardsInsights(teamID string, userID string, since int64, offset int, limit int, boardIDs []string) (*model.BoardInsightsList, error)
 	GetUserTimezone(userID string) (string, error)
+	GetCardHistory(boardID string, propertyIDs []string, until int64) ([]*model.CardHistoryEntry, error)
+
+	InsertAuditRecord(record *model.AuditRecord) error
+	GetAuditRecords(opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, error)
+	DeleteAuditRecordsBefore(date int64) (int64, error)
+
+	InsertTemplate(template *model.Template) (*model.Template, error)
+	UpdateTemplate(template *model.Template) (*model.Template, error)
+	GetTemplate(templateID string) (*model.Template, error)
+	GetTemplateForBoard(boardID string) (*model.Template, error)
+	GetTemplateForSourceBoard(teamID, sourceBoardID string) (*model.Template, error)
+	GetTemplates(opts model.QueryTemplatesOptions) ([]*model.Template, error)
+	// @withTransaction
+	DeleteTemplate(templateID string) error
+	SaveTemplateDerivedBoard(derivedBoard *model.TemplateDerivedBoard) error
+	GetTemplateDerivedBoards(templateID string) ([]*model.TemplateDerivedBoard, error)
+	GetTemplateDerivedBoard(boardID string) (*model.TemplateDerivedBoard, error)
+
+	InsertImportJob(job *model.ImportJob) (*model.ImportJob, error)
+	UpdateImportJob(job *model.ImportJob) (*model.ImportJob, error)
+	GetImportJob(jobID string) (*model.ImportJob, error)
+	GetImportJobsForUser(teamID, userID string) ([]*model.ImportJob, error)
+
+	InsertFileScan(scan *model.FileScan) error
+	UpdateFileScan(scan *model.FileScan) error
+	GetFileScan(boardID, fileID string) (*model.FileScan, error)
+	GetUnfinishedFileScans(updatedBefore int64) ([]*model.FileScan, error)
+	DeleteFileScan(boardID, fileID string) error
+
+	UpsertFeedToken(feedToken *model.FeedToken) error
+	GetFeedToken(userID string) (*model.FeedToken, error)
+	GetFeedTokenByToken(token string) (*model.FeedToken, error)
+	DeleteFeedToken(userID string) error
 }
 
 type NotSupportedError struct {
@@ -0,0 +1,147 @@
+package storetests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestAuditStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("InsertAuditRecord", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testInsertAuditRecord(t, store)
+	})
+
+	t.Run("GetAuditRecords", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetAuditRecords(t, store)
+	})
+
+	t.Run("AuditRecordsDataRetention", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testAuditRecordsDataRetention(t, store)
+	})
+
+	t.Run("DeleteAuditRecordsBefore", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteAuditRecordsBefore(t, store)
+	})
+}
+
+func createTestAuditRecord(t *testing.T, store store.Store, userID, boardID, event string, createAt int64) *model.AuditRecord {
+	record := &model.AuditRecord{
+		ID:        utils.NewID(utils.IDTypeNone),
+		Level:     "mod",
+		Event:     event,
+		Status:    "success",
+		UserID:    userID,
+		SessionID: utils.NewID(utils.IDTypeNone),
+		Client:    "test-client",
+		IPAddress: "127.0.0.1",
+		APIPath:   "/api/v2/boards/" + boardID,
+		TeamID:    "team-id",
+		BoardID:   boardID,
+		Meta:      map[string]interface{}{"boardID": boardID},
+		CreateAt:  createAt,
+	}
+	require.NoError(t, store.InsertAuditRecord(record))
+	return record
+}
+
+func testInsertAuditRecord(t *testing.T, store store.Store) {
+	record := createTestAuditRecord(t, store, "user-id", "board-id", "patchBoard", 1000)
+
+	records, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, record, records[0])
+}
+
+func testGetAuditRecords(t *testing.T, store store.Store) {
+	createTestAuditRecord(t, store, "user-1", "board-1", "patchBoard", 1000)
+	createTestAuditRecord(t, store, "user-1", "board-2", "patchBoard", 2000)
+	createTestAuditRecord(t, store, "user-2", "board-1", "deleteBoard", 3000)
+	createTestAuditRecord(t, store, "user-2", "board-2", "patchBoard", 4000)
+
+	t.Run("newest first", func(t *testing.T) {
+		records, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{})
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+		assert.EqualValues(t, 4000, records[0].CreateAt)
+		assert.EqualValues(t, 1000, records[3].CreateAt)
+	})
+
+	t.Run("filter by user", func(t *testing.T) {
+		records, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{UserID: "user-1"})
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		for _, r := range records {
+			assert.Equal(t, "user-1", r.UserID)
+		}
+	})
+
+	t.Run("filter by board and event", func(t *testing.T) {
+		records, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{BoardID: "board-1", Event: "deleteBoard"})
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, "user-2", records[0].UserID)
+	})
+
+	t.Run("filter by time range", func(t *testing.T) {
+		records, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{Since: 2000, Until: 4000})
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.EqualValues(t, 3000, records[0].CreateAt)
+		assert.EqualValues(t, 2000, records[1].CreateAt)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		records, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{Page: 0, PerPage: 3})
+		require.NoError(t, err)
+		require.Len(t, records, 4, "one extra record is returned to detect the next page")
+
+		records, err = store.GetAuditRecords(model.QueryAuditRecordsOptions{Page: 1, PerPage: 3})
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.EqualValues(t, 1000, records[0].CreateAt)
+	})
+}
+
+func testAuditRecordsDataRetention(t *testing.T, store store.Store) {
+	for _, createAt := range []int64{1000, 1500, 2000, 3000} {
+		createTestAuditRecord(t, store, "user-1", "board-1", "patchBoard", createAt)
+	}
+
+	// the records are deleted in several batches
+	deleted, err := store.RunDataRetention(2500, 2)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, deleted)
+
+	records, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.EqualValues(t, 3000, records[0].CreateAt)
+}
+
+func testDeleteAuditRecordsBefore(t *testing.T, store store.Store) {
+	createTestAuditRecord(t, store, "user-1", "board-1", "patchBoard", 1000)
+	createTestAuditRecord(t, store, "user-1", "board-1", "patchBoard", 2000)
+	createTestAuditRecord(t, store, "user-1", "board-1", "patchBoard", 3000)
+
+	deleted, err := store.DeleteAuditRecordsBefore(2000)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+
+	records, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+}
@@ -0,0 +1,170 @@
+package storetests
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestBackupStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("GetSchemaVersion", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetSchemaVersion(t, store)
+	})
+
+	t.Run("ReadAndRestoreTables", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		target, tearDownTarget := setup(t)
+		defer tearDownTarget()
+		testReadAndRestoreTables(t, store, target)
+	})
+
+	t.Run("RestoreDatesFromOtherDatabases", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testRestoreDatesFromOtherDatabases(t, store)
+	})
+}
+
+func testGetSchemaVersion(t *testing.T, store store.Store) {
+	version, err := store.GetSchemaVersion()
+	require.NoError(t, err)
+	require.Positive(t, version)
+}
+
+func readAllTables(t *testing.T, store store.Store, batchSize int) []*model.TableBatch {
+	var batches []*model.TableBatch
+	err := store.ReadTables(batchSize, func(batch *model.TableBatch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	require.NoError(t, err)
+	return batches
+}
+
+func countTableRows(batches []*model.TableBatch) map[string]int {
+	counts := map[string]int{}
+	for _, batch := range batches {
+		counts[batch.Table] += len(batch.Rows)
+	}
+	return counts
+}
+
+func testReadAndRestoreTables(t *testing.T, store, target store.Store) {
+	users := createTestUsers(t, store, 3)
+	board, err := store.InsertBoard(&model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		Title:  "Backed up board",
+	}, users[0].ID)
+	require.NoError(t, err)
+
+	var blocks []model.Block
+	for i := 0; i < 5; i++ {
+		blocks = append(blocks, model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			Title:    "card",
+			Fields:   map[string]interface{}{"isTemplate": false},
+		})
+	}
+	InsertBlocks(t, store, blocks, users[0].ID)
+
+	// small batches, so that tables are read in several of them
+	batches := readAllTables(t, store, 2)
+	counts := countTableRows(batches)
+	require.Equal(t, 3, counts["users"])
+	require.Equal(t, 1, counts["boards"])
+	require.Equal(t, 5, counts["blocks"])
+	require.Contains(t, counts, "subscriptions")
+	require.NotContains(t, counts, "sessions")
+
+	for _, batch := range batches {
+		for _, row := range batch.Rows {
+			require.Len(t, row, len(batch.Columns))
+		}
+	}
+
+	t.Run("restore into a store that is not empty", func(t *testing.T) {
+		err := store.RestoreTables(func() (*model.TableBatch, error) {
+			return nil, io.EOF
+		})
+		require.ErrorIs(t, err, model.ErrRestoreNotEmpty)
+	})
+
+	t.Run("restore into an empty store", func(t *testing.T) {
+		remaining := batches
+		err := target.RestoreTables(func() (*model.TableBatch, error) {
+			if len(remaining) == 0 {
+				return nil, io.EOF
+			}
+			batch := remaining[0]
+			remaining = remaining[1:]
+			return batch, nil
+		})
+		require.NoError(t, err)
+
+		restoredBoard, err := target.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, board.Title, restoredBoard.Title)
+
+		restoredBlocks, err := target.GetBlocksForBoard(board.ID)
+		require.NoError(t, err)
+		require.Len(t, restoredBlocks, 5)
+
+		restoredUser, err := target.GetUserByID(users[1].ID)
+		require.NoError(t, err)
+		require.Equal(t, users[1].Username, restoredUser.Username)
+
+		require.Equal(t, counts, countTableRows(readAllTables(t, target, 100)))
+	})
+}
+
+func testRestoreDatesFromOtherDatabases(t *testing.T, store store.Store) {
+	boardID := utils.NewID(utils.IDTypeBoard)
+	userID := utils.NewID(utils.IDTypeUser)
+
+	// dates as written to backups taken from SQLite or Postgres, and as
+	// returned by MySQL
+	batches := []*model.TableBatch{{
+		Table:   "board_members_history",
+		Columns: []string{"board_id", "user_id", "action", "insert_at"},
+		Rows: [][]interface{}{
+			{boardID, userID, "created", "2022-10-19T09:00:00Z"},
+			{boardID, userID, "deleted", "2022-10-19T11:30:00.5+02:00"},
+			{boardID, userID, "created", "2022-10-19 10:00:00.000000"},
+		},
+	}}
+	err := store.RestoreTables(func() (*model.TableBatch, error) {
+		if len(batches) == 0 {
+			return nil, io.EOF
+		}
+		batch := batches[0]
+		batches = batches[1:]
+		return batch, nil
+	})
+	require.NoError(t, err)
+
+	entries, err := store.GetBoardMemberHistory(boardID, userID, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	expected := []time.Time{
+		time.Date(2022, 10, 19, 10, 0, 0, 0, time.UTC),
+		time.Date(2022, 10, 19, 9, 30, 0, 500000000, time.UTC),
+		time.Date(2022, 10, 19, 9, 0, 0, 0, time.UTC),
+	}
+	for i, entry := range entries {
+		require.True(t, expected[i].Equal(entry.InsertAt), "entry %d was inserted at %s", i, entry.InsertAt)
+	}
+}
@@ -1,6 +1,9 @@
 package storetests
 
 import (
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -44,11 +47,21 @@ func StoreTestBlocksStore(t *testing.T, setup func(t *testing.T) (store.Store, f
 		defer tearDown()
 		testDeleteBlock(t, store)
 	})
+	t.Run("DeleteBlocks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteBlocks(t, store)
+	})
 	t.Run("UndeleteBlock", func(t *testing.T) {
 		store, tearDown := setup(t)
 		defer tearDown()
 		testUndeleteBlock(t, store)
 	})
+	t.Run("ArchiveBlock", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testArchiveBlock(t, store)
+	})
 	t.Run("GetSubTree2", func(t *testing.T) {
 		store, tearDown := setup(t)
 		defer tearDown()
@@ -64,16 +77,41 @@ func StoreTestBlocksStore(t *testing.T, setup func(t *testing.T) (store.Store, f
 		defer tearDown()
 		testGetBlock(t, store)
 	})
+	t.Run("GetBlocksPage", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBlocksPage(t, store)
+	})
+	t.Run("GetBlockTombstones", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBlockTombstones(t, store)
+	})
 	t.Run("DuplicateBlock", func(t *testing.T) {
 		store, tearDown := setup(t)
 		defer tearDown()
 		testDuplicateBlock(t, store)
 	})
+	t.Run("MoveBlocks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testMoveBlocks(t, store)
+	})
+	t.Run("SaveCardChanges", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testSaveCardChanges(t, store)
+	})
 	t.Run("GetBlockMetadata", func(t *testing.T) {
 		store, tearDown := setup(t)
 		defer tearDown()
 		testGetBlockMetadata(t, store)
 	})
+	t.Run("GetCardsForUser", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetCardsForUser(t, store)
+	})
 }
 
 func testInsertBlock(t *testing.T, store store.Store) {
@@ -280,6 +318,18 @@ func testPatchBlock(t *testing.T, store store.Store) {
 		require.Len(t, blocks, initialCount)
 	})
 
+	t.Run("patch with another board", func(t *testing.T) {
+		otherBoardID := utils.NewID(utils.IDTypeBoard)
+		title := "Title with another board"
+		time.Sleep(1 * time.Millisecond)
+		err := store.PatchBlock("id-test", &model.BlockPatch{BoardID: &otherBoardID, Title: &title}, "user-id-1")
+		require.NoError(t, err)
+
+		block, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+		require.Equal(t, title, block.Title)
+	})
+
 	t.Run("invalid fields data", func(t *testing.T) {
 		blockPatch := model.BlockPatch{
 			UpdatedFields: map[string]interface{}{"no-serialiable-value": t.Run},
@@ -336,6 +386,92 @@ func testPatchBlock(t *testing.T, store store.Store) {
 		require.Equal(t, "new value", retrievedBlock.Fields["test3"])
 	})
 
+	t.Run("patch with the expected update time", func(t *testing.T) {
+		existingBlock, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+
+		newTitle := "Expected title"
+		blockPatch := model.BlockPatch{
+			Title:            &newTitle,
+			ExpectedUpdateAt: &existingBlock.UpdateAt,
+		}
+
+		// Wait for not colliding the ID+insert_at key
+		time.Sleep(1 * time.Millisecond)
+
+		err = store.PatchBlock("id-test", &blockPatch, "user-id-2")
+		require.NoError(t, err)
+
+		retrievedBlock, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+		require.Equal(t, "Expected title", retrievedBlock.Title)
+
+		// the same patch is now stale
+		staleTitle := "Stale title"
+		blockPatch.Title = &staleTitle
+		err = store.PatchBlock("id-test", &blockPatch, "user-id-2")
+		require.True(t, model.IsErrBlockConflict(err))
+
+		retrievedBlock, err = store.GetBlock("id-test")
+		require.NoError(t, err)
+		require.Equal(t, "Expected title", retrievedBlock.Title)
+	})
+
+	t.Run("concurrent patches with the same expected update time", func(t *testing.T) {
+		existingBlock, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+		time.Sleep(1 * time.Millisecond)
+
+		const count = 5
+		errs := make(chan error, count)
+		for i := 0; i < count; i++ {
+			title := fmt.Sprintf("Concurrent title %d", i)
+			go func() {
+				errs <- store.PatchBlock("id-test", &model.BlockPatch{
+					Title:            &title,
+					ExpectedUpdateAt: &existingBlock.UpdateAt,
+				}, "user-id-2")
+			}()
+		}
+
+		// only one of the patches is applied, the others are conflicts
+		applied := 0
+		for i := 0; i < count; i++ {
+			err := <-errs
+			if err == nil {
+				applied++
+				continue
+			}
+			require.True(t, model.IsErrBlockConflict(err), err)
+		}
+		require.Equal(t, 1, applied)
+
+		retrievedBlock, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+		require.Greater(t, retrievedBlock.UpdateAt, existingBlock.UpdateAt)
+	})
+
+	t.Run("merge block custom fields", func(t *testing.T) {
+		blockPatch := model.BlockPatch{
+			UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{"a": "1", "b": "2"}},
+		}
+
+		time.Sleep(1 * time.Millisecond)
+		require.NoError(t, store.PatchBlock("id-test", &blockPatch, "user-id-2"))
+
+		blockPatch = model.BlockPatch{
+			UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{"b": "3", "a": nil}},
+			MergeFields:   true,
+		}
+
+		time.Sleep(1 * time.Millisecond)
+		require.NoError(t, store.PatchBlock("id-test", &blockPatch, "user-id-2"))
+
+		retrievedBlock, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"b": "3"}, retrievedBlock.Fields["properties"])
+	})
+
 	t.Run("remove block custom fields", func(t *testing.T) {
 		blockPatch := model.BlockPatch{
 			DeletedFields: []string{"test", "test3", "test100"},
@@ -565,6 +701,64 @@ func testDeleteBlock(t *testing.T, store store.Store) {
 	})
 }
 
+func testDeleteBlocks(t *testing.T, store store.Store) {
+	blocksToInsert := []model.Block{
+		{ID: "block1", BoardID: testBoardID, ModifiedBy: testUserID},
+		{ID: "block2", BoardID: testBoardID, ModifiedBy: testUserID},
+		{ID: "block3", BoardID: testBoardID, ModifiedBy: testUserID},
+	}
+	InsertBlocks(t, store, blocksToInsert, "user-id-1")
+	defer DeleteBlocks(t, store, blocksToInsert, "test")
+
+	// Wait for not colliding the ID+insert_at key
+	time.Sleep(1 * time.Millisecond)
+	require.NoError(t, store.DeleteBlocks([]string{"block1", "block2", "not-exists"}, testUserID))
+
+	blocks, err := store.GetBlocksForBoard(testBoardID)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	require.Equal(t, "block3", blocks[0].ID)
+
+	history, err := store.GetBlockHistory("block1", model.QueryBlockHistoryOptions{Limit: 1, Descending: true})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.NotZero(t, history[0].DeleteAt)
+}
+
+func testArchiveBlock(t *testing.T, store store.Store) {
+	card := model.Block{
+		ID:       "card-to-archive",
+		BoardID:  testBoardID,
+		ParentID: testBoardID,
+		Type:     model.TypeCard,
+		Fields:   map[string]interface{}{},
+	}
+	InsertBlocks(t, store, []model.Block{card}, testUserID)
+	time.Sleep(1 * time.Millisecond)
+
+	archiveAt := utils.GetMillis()
+	require.NoError(t, store.PatchBlock(card.ID, &model.BlockPatch{ArchiveAt: &archiveAt}, testUserID))
+
+	archived, err := store.GetBlock(card.ID)
+	require.NoError(t, err)
+	require.Equal(t, archiveAt, archived.ArchiveAt)
+
+	history, err := store.GetBlockHistory(card.ID, model.QueryBlockHistoryOptions{Limit: 1, Descending: true})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, archiveAt, history[0].ArchiveAt)
+
+	// the archived time is kept when the card is deleted and restored
+	time.Sleep(1 * time.Millisecond)
+	require.NoError(t, store.DeleteBlock(card.ID, testUserID))
+	time.Sleep(1 * time.Millisecond)
+	require.NoError(t, store.UndeleteBlock(card.ID, testUserID))
+
+	restored, err := store.GetBlock(card.ID)
+	require.NoError(t, err)
+	require.Equal(t, archiveAt, restored.ArchiveAt)
+}
+
 func testUndeleteBlock(t *testing.T, store store.Store) {
 	boardID := testBoardID
 	userID := testUserID
@@ -766,6 +960,98 @@ func testGetBlocks(t *testing.T, store store.Store) {
 	})
 }
 
+func testGetBlocksPage(t *testing.T, store store.Store) {
+	boardID := testBoardID
+	for i := 0; i < 5; i++ {
+		blockType := model.BlockType(model.TypeCard)
+		if i%2 == 1 {
+			blockType = model.TypeView
+		}
+		block := model.Block{
+			ID:      utils.NewID(utils.IDTypeBlock),
+			BoardID: boardID,
+			Type:    blockType,
+		}
+		require.NoError(t, store.InsertBlock(&block, testUserID))
+	}
+
+	t.Run("iterate all pages with a cursor", func(t *testing.T) {
+		seen := map[string]bool{}
+		var cursor *model.BlocksCursor
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 5, "pagination should end")
+
+			blocks, err := store.GetBlocksPage(boardID, model.QueryBlocksPageOptions{Cursor: cursor, PerPage: 2})
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(blocks), 3)
+
+			if len(blocks) > 2 {
+				blocks = blocks[:2]
+			}
+			for _, block := range blocks {
+				require.False(t, seen[block.ID], "block returned twice")
+				seen[block.ID] = true
+			}
+			if len(blocks) < 2 {
+				break
+			}
+			last := blocks[len(blocks)-1]
+			cursor = &model.BlocksCursor{UpdateAt: last.UpdateAt, ID: last.ID}
+		}
+		require.Len(t, seen, 5)
+	})
+
+	t.Run("filter by type", func(t *testing.T) {
+		blocks, err := store.GetBlocksPage(boardID, model.QueryBlocksPageOptions{BlockType: model.TypeView})
+		require.NoError(t, err)
+		require.Len(t, blocks, 2)
+	})
+
+	t.Run("updated since", func(t *testing.T) {
+		blocks, err := store.GetBlocksPage(boardID, model.QueryBlocksPageOptions{})
+		require.NoError(t, err)
+		latest := blocks[len(blocks)-1].UpdateAt
+
+		time.Sleep(10 * time.Millisecond)
+		title := "updated"
+		require.NoError(t, store.PatchBlock(blocks[0].ID, &model.BlockPatch{Title: &title}, testUserID))
+
+		blocks, err = store.GetBlocksPage(boardID, model.QueryBlocksPageOptions{UpdatedSince: latest + 1})
+		require.NoError(t, err)
+		require.Len(t, blocks, 1)
+		require.Equal(t, "updated", blocks[0].Title)
+	})
+}
+
+func testGetBlockTombstones(t *testing.T, store store.Store) {
+	boardID := testBoardID
+	block := model.Block{
+		ID:      utils.NewID(utils.IDTypeBlock),
+		BoardID: boardID,
+		Type:    model.TypeCard,
+	}
+	require.NoError(t, store.InsertBlock(&block, testUserID))
+
+	before := utils.GetMillis()
+	require.NoError(t, store.DeleteBlock(block.ID, testUserID))
+
+	tombstones, err := store.GetBlockTombstones(boardID, before)
+	require.NoError(t, err)
+	require.Len(t, tombstones, 1)
+	require.Equal(t, block.ID, tombstones[0].ID)
+	require.Equal(t, model.BlockType(model.TypeCard), tombstones[0].Type)
+	require.GreaterOrEqual(t, tombstones[0].DeleteAt, before)
+
+	tombstones, err = store.GetBlockTombstones(boardID, tombstones[0].DeleteAt+1)
+	require.NoError(t, err)
+	require.Empty(t, tombstones)
+
+	require.NoError(t, store.UndeleteBlock(block.ID, testUserID))
+	tombstones, err = store.GetBlockTombstones(boardID, before)
+	require.NoError(t, err)
+	require.Empty(t, tombstones, "restored blocks should not be reported as deleted")
+}
+
 func testGetBlock(t *testing.T, store store.Store) {
 	t.Run("get a block", func(t *testing.T) {
 		block := model.Block{
@@ -851,6 +1137,81 @@ func testDuplicateBlock(t *testing.T, store store.Store) {
 	})
 }
 
+func testMoveBlocks(t *testing.T, store store.Store) {
+	destBoardID := "dest-board-id"
+	card := model.Block{
+		ID:        "card-to-move",
+		BoardID:   testBoardID,
+		ParentID:  testBoardID,
+		Type:      model.TypeCard,
+		Title:     "card",
+		CreatedBy: "user-id-1",
+		Fields:    map[string]interface{}{"properties": map[string]interface{}{"source-prop": "value"}},
+	}
+	child := model.Block{
+		ID:       "card-to-move-child",
+		BoardID:  testBoardID,
+		ParentID: "card-to-move",
+		Type:     model.TypeText,
+		Title:    "text",
+	}
+	InsertBlocks(t, store, []model.Block{card, child}, "user-id-1")
+	original, err := store.GetBlock(card.ID)
+	require.NoError(t, err)
+	time.Sleep(1 * time.Millisecond)
+
+	t.Run("move existing blocks", func(t *testing.T) {
+		card.BoardID = destBoardID
+		card.ParentID = destBoardID
+		card.Fields = map[string]interface{}{"properties": map[string]interface{}{"dest-prop": "value"}}
+		child.BoardID = destBoardID
+
+		require.NoError(t, store.MoveBlocks([]model.Block{card, child}, testUserID))
+
+		moved, err := store.GetBlock(card.ID)
+		require.NoError(t, err)
+		require.Equal(t, destBoardID, moved.BoardID)
+		require.Equal(t, destBoardID, moved.ParentID)
+		require.Equal(t, map[string]interface{}{"dest-prop": "value"}, moved.Fields["properties"])
+		require.Equal(t, original.CreateAt, moved.CreateAt)
+		require.Equal(t, original.CreatedBy, moved.CreatedBy)
+		require.Equal(t, testUserID, moved.ModifiedBy)
+
+		sourceBlocks, err := store.GetBlocksWithBoardID(testBoardID)
+		require.NoError(t, err)
+		for _, block := range sourceBlocks {
+			require.NotEqual(t, card.ID, block.ID)
+			require.NotEqual(t, child.ID, block.ID)
+		}
+		destBlocks, err := store.GetBlocksWithBoardID(destBoardID)
+		require.NoError(t, err)
+		require.Len(t, destBlocks, 2)
+
+		// the source board history records the card as deleted
+		history, err := store.GetCardHistory(testBoardID, nil, utils.GetMillis())
+		require.NoError(t, err)
+		var deleteAt int64
+		for _, entry := range history {
+			if entry.CardID == card.ID {
+				deleteAt = entry.DeleteAt
+			}
+		}
+		require.NotZero(t, deleteAt)
+
+		history, err = store.GetCardHistory(destBoardID, nil, utils.GetMillis())
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.Equal(t, card.ID, history[0].CardID)
+		require.Zero(t, history[0].DeleteAt)
+	})
+
+	t.Run("move not existing block", func(t *testing.T) {
+		missing := model.Block{ID: "not-existing-id", BoardID: destBoardID, Type: model.TypeCard}
+		err := store.MoveBlocks([]model.Block{missing}, testUserID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
 func testGetBlockMetadata(t *testing.T, store store.Store) {
 	boardID := testBoardID
 	blocks, err := store.GetBlocksForBoard(boardID)
@@ -1035,3 +1396,235 @@ func testGetBlockMetadata(t *testing.T, store store.Store) {
 		require.Equal(t, expectedBlock.ID, block.ID)
 	})
 }
+
+func testGetCardsForUser(t *testing.T, store store.Store) {
+	userID := testUserID
+	otherUserID := "other-user-id"
+
+	card := func(id, boardID, title string, properties map[string]interface{}) model.Block {
+		return model.Block{
+			ID:       id,
+			BoardID:  boardID,
+			ParentID: boardID,
+			Type:     model.TypeCard,
+			Title:    title,
+			Fields:   map[string]interface{}{"properties": properties},
+		}
+	}
+	due := func(from, to int64) string {
+		if to == 0 {
+			return fmt.Sprintf(`{"from":%d}`, from)
+		}
+		return fmt.Sprintf(`{"from":%d,"to":%d}`, from, to)
+	}
+
+	blocks := []model.Block{
+		card("assigned", "board-1", "b", map[string]interface{}{"owner": userID, "status": "done-1", "due": due(100, 300)}),
+		card("multi-assigned", "board-2", "c", map[string]interface{}{"reviewers": []interface{}{otherUserID, userID}, "deadline": due(200, 0)}),
+		card("not-assigned", "board-1", "d", map[string]interface{}{"owner": otherUserID}),
+		// owner isn't a person property on the second board
+		card("other-property", "board-2", "e", map[string]interface{}{"owner": userID}),
+		card("other-board", "board-3", "f", map[string]interface{}{"owner": userID}),
+		{ID: "view", BoardID: "board-1", ParentID: "board-1", Type: model.TypeView, Fields: map[string]interface{}{"owner": userID}},
+	}
+	require.NoError(t, store.InsertBlocks(blocks, otherUserID))
+	created := card("created", "board-1", "a", map[string]interface{}{"status": "todo-1"})
+	require.NoError(t, store.InsertBlock(&created, userID))
+	archived := card("archived", "board-1", "g", map[string]interface{}{"owner": userID})
+	archived.ArchiveAt = 1000
+	require.NoError(t, store.InsertBlock(&archived, otherUserID))
+
+	query := func(opts model.QueryUserCardsOptions) *model.UserCardsQuery {
+		return &model.UserCardsQuery{
+			UserID:   userID,
+			BoardIDs: []string{"board-1", "board-2"},
+			PersonProperties: map[string][]string{
+				"owner":     {"board-1"},
+				"reviewers": {"board-2"},
+			},
+			DueProperties: map[string][]string{
+				"due":      {"board-1"},
+				"deadline": {"board-2"},
+			},
+			QueryUserCardsOptions: opts,
+		}
+	}
+	cardIDs := func(cards []model.Block) []string {
+		ids := make([]string, len(cards))
+		for i := range cards {
+			ids[i] = cards[i].ID
+		}
+		return ids
+	}
+
+	t.Run("created and assigned cards", func(t *testing.T) {
+		cards, err := store.GetCardsForUser(query(model.QueryUserCardsOptions{SortBy: model.UserCardsSortTitle}))
+		require.NoError(t, err)
+		require.Equal(t, []string{"created", "assigned", "multi-assigned"}, cardIDs(cards))
+	})
+
+	t.Run("sort by due date", func(t *testing.T) {
+		cards, err := store.GetCardsForUser(query(model.QueryUserCardsOptions{SortBy: model.UserCardsSortDueAt}))
+		require.NoError(t, err)
+		require.Equal(t, []string{"multi-assigned", "assigned", "created"}, cardIDs(cards))
+
+		cards, err = store.GetCardsForUser(query(model.QueryUserCardsOptions{SortBy: model.UserCardsSortDueAt, Descending: true}))
+		require.NoError(t, err)
+		require.Equal(t, []string{"assigned", "multi-assigned", "created"}, cardIDs(cards))
+	})
+
+	t.Run("filter by due date", func(t *testing.T) {
+		// the end of the range is the due date
+		cards, err := store.GetCardsForUser(query(model.QueryUserCardsOptions{DueAfter: 250}))
+		require.NoError(t, err)
+		require.Equal(t, []string{"assigned"}, cardIDs(cards))
+
+		cards, err = store.GetCardsForUser(query(model.QueryUserCardsOptions{DueBefore: 250, SortBy: model.UserCardsSortTitle}))
+		require.NoError(t, err)
+		require.Equal(t, []string{"multi-assigned"}, cardIDs(cards))
+	})
+
+	t.Run("filter by status", func(t *testing.T) {
+		q := query(model.QueryUserCardsOptions{})
+		q.StatusOptions = []*model.UserCardsStatusOption{{PropertyID: "status", OptionID: "done-1", BoardIDs: []string{"board-1"}}}
+		cards, err := store.GetCardsForUser(q)
+		require.NoError(t, err)
+		require.Equal(t, []string{"assigned"}, cardIDs(cards))
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		opts := model.QueryUserCardsOptions{SortBy: model.UserCardsSortTitle, PerPage: 2}
+		cards, err := store.GetCardsForUser(query(opts))
+		require.NoError(t, err)
+		require.Equal(t, []string{"created", "assigned", "multi-assigned"}, cardIDs(cards))
+
+		opts.Page = 1
+		cards, err = store.GetCardsForUser(query(opts))
+		require.NoError(t, err)
+		require.Equal(t, []string{"multi-assigned"}, cardIDs(cards))
+	})
+
+	t.Run("no boards", func(t *testing.T) {
+		cards, err := store.GetCardsForUser(&model.UserCardsQuery{UserID: userID})
+		require.NoError(t, err)
+		require.Empty(t, cards)
+	})
+}
+
+func testSaveCardChanges(t *testing.T, store store.Store) {
+	board, err := store.InsertBoard(&model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+	}, testUserID)
+	require.NoError(t, err)
+
+	newCard := func(title string) model.Block {
+		return model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			Title:    title,
+		}
+	}
+	existing := newCard("existing")
+	require.NoError(t, store.InsertBlock(&existing, testUserID))
+
+	t.Run("the check gets the cards of the board", func(t *testing.T) {
+		var checked []model.Block
+		check := func(boardID string, cards []model.Block) error {
+			require.Equal(t, board.ID, boardID)
+			checked = cards
+			return nil
+		}
+
+		title := "patched"
+		inserted := newCard("inserted")
+		changes := &model.CardChanges{
+			BoardIDs:       []string{board.ID},
+			InsertedBlocks: []model.Block{inserted},
+			BlockPatches: &model.BlockPatchBatch{
+				BlockIDs:     []string{existing.ID},
+				BlockPatches: []model.BlockPatch{{Title: &title}},
+			},
+		}
+		require.NoError(t, store.SaveCardChanges(changes, testUserID, check))
+		require.Len(t, checked, 1)
+		require.Equal(t, existing.ID, checked[0].ID)
+
+		cards, err := store.GetBlocksWithType(board.ID, model.TypeCard)
+		require.NoError(t, err)
+		require.Len(t, cards, 2)
+		patched, err := store.GetBlock(existing.ID)
+		require.NoError(t, err)
+		require.Equal(t, title, patched.Title)
+	})
+
+	t.Run("a failed check saves nothing", func(t *testing.T) {
+		errCheck := errors.New("check failed")
+		check := func(boardID string, cards []model.Block) error {
+			return errCheck
+		}
+
+		title := "not saved"
+		changes := &model.CardChanges{
+			BoardIDs:       []string{board.ID},
+			InsertedBlocks: []model.Block{newCard("not inserted")},
+			BlockPatches: &model.BlockPatchBatch{
+				BlockIDs:     []string{existing.ID},
+				BlockPatches: []model.BlockPatch{{Title: &title}},
+			},
+		}
+		require.ErrorIs(t, store.SaveCardChanges(changes, testUserID, check), errCheck)
+
+		cards, err := store.GetBlocksWithType(board.ID, model.TypeCard)
+		require.NoError(t, err)
+		require.Len(t, cards, 2)
+		patched, err := store.GetBlock(existing.ID)
+		require.NoError(t, err)
+		require.Equal(t, "patched", patched.Title)
+	})
+
+	t.Run("concurrent changes are checked one after the other", func(t *testing.T) {
+		cards, err := store.GetBlocksWithType(board.ID, model.TypeCard)
+		require.NoError(t, err)
+		limit := len(cards) + 1
+
+		// each change adds a card only if the board has less than limit
+		// cards, so only one of them can succeed
+		check := func(boardID string, cards []model.Block) error {
+			if len(cards) >= limit {
+				return errors.New("too many cards")
+			}
+			return nil
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				changes := &model.CardChanges{
+					BoardIDs:       []string{board.ID},
+					InsertedBlocks: []model.Block{newCard(fmt.Sprintf("concurrent %d", i))},
+				}
+				errs[i] = store.SaveCardChanges(changes, testUserID, check)
+			}(i)
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+			}
+		}
+		require.Equal(t, 1, succeeded)
+
+		cards, err = store.GetBlocksWithType(board.ID, model.TypeCard)
+		require.NoError(t, err)
+		require.Len(t, cards, limit)
+	})
+}
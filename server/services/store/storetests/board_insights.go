@@ -6,6 +6,7 @@ import (
 
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,6 +20,79 @@ func StoreTestBoardsInsightsStore(t *testing.T, setup func(t *testing.T) (store.
 		defer tearDown()
 		getBoardsInsightsTest(t, store)
 	})
+	t.Run("GetCardHistory", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		getCardHistoryTest(t, store)
+	})
+}
+
+func getCardHistoryTest(t *testing.T, store store.Store) {
+	boardID := "board-id-1"
+	card1 := &model.Block{
+		ID:      "card-id-1",
+		BoardID: boardID,
+		Type:    model.TypeCard,
+		Fields: map[string]interface{}{
+			"properties": map[string]interface{}{
+				"status": "todo",
+				"due":    `{"from":1660000000000}`,
+			},
+		},
+	}
+	card2 := &model.Block{
+		ID:      "card-id-2",
+		BoardID: boardID,
+		Type:    model.TypeCard,
+		Fields:  map[string]interface{}{},
+	}
+	text := &model.Block{
+		ID:       "text-id-1",
+		BoardID:  boardID,
+		ParentID: "card-id-1",
+		Type:     model.TypeText,
+		Fields: map[string]interface{}{
+			"properties": map[string]interface{}{"status": "todo"},
+		},
+	}
+	otherBoardCard := &model.Block{
+		ID:      "card-id-3",
+		BoardID: "board-id-2",
+		Type:    model.TypeCard,
+	}
+	for _, block := range []*model.Block{card1, card2, text, otherBoardCard} {
+		require.NoError(t, store.InsertBlock(block, testUserID))
+	}
+
+	require.NoError(t, store.PatchBlock(card1.ID, &model.BlockPatch{
+		UpdatedFields: map[string]interface{}{
+			"properties": map[string]interface{}{"status": "done"},
+		},
+		MergeFields: true,
+	}, testUserID))
+	require.NoError(t, store.DeleteBlock(card2.ID, testUserID))
+
+	history, err := store.GetCardHistory(boardID, []string{"status", "due"}, utils.GetMillis()+1000)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+
+	require.Equal(t, card1.ID, history[0].CardID)
+	require.Equal(t, map[string]string{"status": "todo", "due": `{"from":1660000000000}`}, history[0].Values)
+	require.Equal(t, card1.ID, history[1].CardID)
+	require.Equal(t, map[string]string{"status": "done", "due": `{"from":1660000000000}`}, history[1].Values)
+	require.Zero(t, history[1].DeleteAt)
+
+	require.Equal(t, card2.ID, history[2].CardID)
+	require.Equal(t, map[string]string{"status": "", "due": ""}, history[2].Values)
+	require.Zero(t, history[2].DeleteAt)
+	require.Equal(t, card2.ID, history[3].CardID)
+	require.NotZero(t, history[3].DeleteAt)
+
+	t.Run("history is limited to the given time", func(t *testing.T) {
+		history, err := store.GetCardHistory(boardID, []string{"status"}, 1)
+		require.NoError(t, err)
+		require.Empty(t, history)
+	})
 }
 
 func getBoardsInsightsTest(t *testing.T, store store.Store) {
@@ -1,6 +1,7 @@
 package storetests
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -77,6 +78,31 @@ func StoreTestBoardStore(t *testing.T, setup func(t *testing.T) (store.Store, fu
 		defer tearDown()
 		testGetBoardHistory(t, store)
 	})
+	t.Run("ArchiveBoard", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testArchiveBoard(t, store)
+	})
+	t.Run("GetBoardsWithAutoArchiveRules", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBoardsWithAutoArchiveRules(t, store)
+	})
+	t.Run("GetBoardsByIDs", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBoardsByIDs(t, store)
+	})
+	t.Run("GetBoardsInTeam", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBoardsInTeam(t, store)
+	})
+	t.Run("TransferBoardOwnership", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testTransferBoardOwnership(t, store)
+	})
 }
 
 func testGetBoard(t *testing.T, store store.Store) {
@@ -446,6 +472,47 @@ func testPatchBoard(t *testing.T, store store.Store) {
 		require.NoError(t, err)
 		require.ElementsMatch(t, expectedCardProperties, patchedBoard.CardProperties)
 	})
+
+	t.Run("concurrent patches with the same expected update time", func(t *testing.T) {
+		boardID := utils.NewID(utils.IDTypeBoard)
+		board, err := store.InsertBoard(&model.Board{
+			ID:     boardID,
+			TeamID: testTeamID,
+			Type:   model.BoardTypeOpen,
+			Title:  "A title",
+		}, userID)
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+
+		const count = 5
+		errs := make(chan error, count)
+		for i := 0; i < count; i++ {
+			title := fmt.Sprintf("Concurrent title %d", i)
+			go func() {
+				_, err := store.PatchBoard(boardID, &model.BoardPatch{
+					Title:            &title,
+					ExpectedUpdateAt: &board.UpdateAt,
+				}, userID)
+				errs <- err
+			}()
+		}
+
+		// only one of the patches is applied, the others are conflicts
+		applied := 0
+		for i := 0; i < count; i++ {
+			err := <-errs
+			if err == nil {
+				applied++
+				continue
+			}
+			require.True(t, model.IsErrBoardConflict(err), err)
+		}
+		require.Equal(t, 1, applied)
+
+		patchedBoard, err := store.GetBoard(boardID)
+		require.NoError(t, err)
+		require.Greater(t, patchedBoard.UpdateAt, board.UpdateAt)
+	})
 }
 
 func testDeleteBoard(t *testing.T, store store.Store) {
@@ -1014,3 +1081,149 @@ func testGetBoardHistory(t *testing.T, store store.Store) {
 		require.Len(t, boards, 0)
 	})
 }
+
+func testArchiveBoard(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	boardID := utils.NewID(utils.IDTypeBoard)
+	_, err := store.InsertBoard(&model.Board{ID: boardID, TeamID: testTeamID, Type: model.BoardTypeOpen}, userID)
+	require.NoError(t, err)
+
+	t.Run("should persist the archived time", func(t *testing.T) {
+		archiveAt := utils.GetMillis()
+		board, err := store.PatchBoard(boardID, &model.BoardPatch{ArchiveAt: &archiveAt}, userID)
+		require.NoError(t, err)
+		require.Equal(t, archiveAt, board.ArchiveAt)
+
+		boards, err := store.GetBoardsForUserAndTeam(userID, testTeamID)
+		require.NoError(t, err)
+		require.Len(t, boards, 1)
+		require.Equal(t, archiveAt, boards[0].ArchiveAt)
+
+		history, err := store.GetBoardHistory(boardID, model.QueryBoardHistoryOptions{Limit: 1, Descending: true})
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.Equal(t, archiveAt, history[0].ArchiveAt)
+	})
+
+	t.Run("should keep the archived time through delete and undelete", func(t *testing.T) {
+		time.Sleep(1 * time.Millisecond)
+		require.NoError(t, store.DeleteBoard(boardID, userID))
+		time.Sleep(1 * time.Millisecond)
+		require.NoError(t, store.UndeleteBoard(boardID, userID))
+
+		board, err := store.GetBoard(boardID)
+		require.NoError(t, err)
+		require.NotZero(t, board.ArchiveAt)
+	})
+
+	t.Run("should restore the board", func(t *testing.T) {
+		notArchived := int64(0)
+		board, err := store.PatchBoard(boardID, &model.BoardPatch{ArchiveAt: &notArchived}, userID)
+		require.NoError(t, err)
+		require.Zero(t, board.ArchiveAt)
+	})
+}
+
+func testGetBoardsWithAutoArchiveRules(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	cardProperties := func(autoArchiveDays int) []map[string]interface{} {
+		option := map[string]interface{}{"id": "done", "value": "Done"}
+		if autoArchiveDays > 0 {
+			option["autoArchiveDays"] = autoArchiveDays
+		}
+		return []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{option}},
+		}
+	}
+
+	withRules := &model.Board{ID: "board-with-rules", TeamID: testTeamID, Type: model.BoardTypeOpen, CardProperties: cardProperties(30)}
+	withoutRules := &model.Board{ID: "board-without-rules", TeamID: testTeamID, Type: model.BoardTypeOpen, CardProperties: cardProperties(0)}
+	archived := &model.Board{ID: "board-archived", TeamID: testTeamID, Type: model.BoardTypeOpen, CardProperties: cardProperties(30), ArchiveAt: 1000}
+	template := &model.Board{ID: "board-template", TeamID: testTeamID, Type: model.BoardTypeOpen, CardProperties: cardProperties(30), IsTemplate: true}
+	for _, board := range []*model.Board{withRules, withoutRules, archived, template} {
+		_, err := store.InsertBoard(board, userID)
+		require.NoError(t, err)
+	}
+
+	boards, err := store.GetBoardsWithAutoArchiveRules()
+	require.NoError(t, err)
+	require.Len(t, boards, 1)
+	require.Equal(t, withRules.ID, boards[0].ID)
+}
+
+func testGetBoardsByIDs(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	for _, board := range []*model.Board{
+		{ID: "board-id-1", TeamID: testTeamID, Type: model.BoardTypeOpen},
+		{ID: "board-id-2", TeamID: "other-team-id", Type: model.BoardTypePrivate},
+		{ID: "board-id-3", TeamID: testTeamID, Type: model.BoardTypeOpen},
+	} {
+		_, err := store.InsertBoard(board, userID)
+		require.NoError(t, err)
+	}
+
+	boards, err := store.GetBoardsByIDs([]string{"board-id-1", "board-id-2", "missing-board-id"})
+	require.NoError(t, err)
+	ids := make([]string, len(boards))
+	for i := range boards {
+		ids[i] = boards[i].ID
+	}
+	require.ElementsMatch(t, []string{"board-id-1", "board-id-2"}, ids)
+
+	boards, err = store.GetBoardsByIDs([]string{})
+	require.NoError(t, err)
+	require.Empty(t, boards)
+}
+
+func testGetBoardsInTeam(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	for _, board := range []*model.Board{
+		{ID: "board-id-1", TeamID: testTeamID, Type: model.BoardTypeOpen, Title: "B"},
+		{ID: "board-id-2", TeamID: "other-team-id", Type: model.BoardTypeOpen, Title: "A"},
+		{ID: "board-id-3", TeamID: testTeamID, Type: model.BoardTypePrivate, Title: "A"},
+		{ID: "board-id-4", TeamID: testTeamID, Type: model.BoardTypeOpen, Title: "C", IsTemplate: true},
+	} {
+		_, err := store.InsertBoard(board, userID)
+		require.NoError(t, err)
+	}
+
+	boards, err := store.GetBoardsInTeam(testTeamID)
+	require.NoError(t, err)
+	require.Len(t, boards, 3)
+	require.Equal(t, "board-id-3", boards[0].ID)
+	require.Equal(t, "board-id-1", boards[1].ID)
+	require.Equal(t, "board-id-4", boards[2].ID)
+
+	boards, err = store.GetBoardsInTeam("empty-team-id")
+	require.NoError(t, err)
+	require.Empty(t, boards)
+}
+
+func testTransferBoardOwnership(t *testing.T, store store.Store) {
+	board, err := store.InsertBoard(&model.Board{ID: "board-id", TeamID: testTeamID, Type: model.BoardTypeOpen}, testUserID)
+	require.NoError(t, err)
+
+	t.Run("new owner", func(t *testing.T) {
+		newOwnerID := utils.NewID(utils.IDTypeUser)
+		time.Sleep(10 * time.Millisecond)
+
+		transferred, err := store.TransferBoardOwnership(board.ID, newOwnerID)
+		require.NoError(t, err)
+		require.Equal(t, newOwnerID, transferred.CreatedBy)
+		require.Greater(t, transferred.UpdateAt, board.UpdateAt)
+
+		member, err := store.GetMemberForBoard(board.ID, newOwnerID)
+		require.NoError(t, err)
+		require.True(t, member.SchemeAdmin)
+		require.True(t, member.SchemeEditor)
+	})
+
+	t.Run("missing board", func(t *testing.T) {
+		_, err := store.TransferBoardOwnership("missing-board-id", testUserID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
@@ -30,6 +30,16 @@ func StoreTestCategoryStore(t *testing.T, setup func(t *testing.T) (store.Store,
 		defer tearDown()
 		testGetUserCategories(t, store)
 	})
+	t.Run("SharedCategories", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testSharedCategories(t, store)
+	})
+	t.Run("DefaultSharedCategory", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDefaultSharedCategory(t, store)
+	})
 }
 
 func testGetCreateCategory(t *testing.T, store store.Store) {
@@ -203,3 +213,113 @@ func testGetUserCategories(t *testing.T, store store.Store) {
 	assert.NoError(t, err)
 	assert.Equal(t, 3, len(userCategories))
 }
+
+func testSharedCategories(t *testing.T, store store.Store) {
+	now := utils.GetMillis()
+	categories := []model.Category{
+		{ID: "private_category", Name: "Private", UserID: "user_id_1", TeamID: "team_id_1", Type: model.CategoryTypePrivate},
+		{ID: "shared_category_2", Name: "Shared 2", TeamID: "team_id_1", Type: model.CategoryTypeShared, SortOrder: 2},
+		{ID: "shared_category_1", Name: "Shared 1", TeamID: "team_id_1", Type: model.CategoryTypeShared, SortOrder: 1},
+		{ID: "shared_category_nested", Name: "Nested", TeamID: "team_id_1", Type: model.CategoryTypeShared, SortOrder: 3, ParentID: "shared_category_1"},
+		{ID: "shared_category_other_team", Name: "Other team", TeamID: "team_id_2", Type: model.CategoryTypeShared},
+	}
+	for _, category := range categories {
+		category.CreateAt = now
+		category.UpdateAt = now
+		assert.NoError(t, store.CreateCategory(category))
+	}
+
+	t.Run("shared categories are sorted and scoped to the team", func(t *testing.T) {
+		sharedCategories, err := store.GetSharedCategories("team_id_1")
+		assert.NoError(t, err)
+		assert.Len(t, sharedCategories, 3)
+		assert.Equal(t, "shared_category_1", sharedCategories[0].ID)
+		assert.Equal(t, "shared_category_2", sharedCategories[1].ID)
+		assert.Equal(t, "shared_category_nested", sharedCategories[2].ID)
+		assert.Equal(t, "shared_category_1", sharedCategories[2].ParentID)
+		assert.Equal(t, model.CategoryTypeShared, sharedCategories[2].Type)
+	})
+
+	t.Run("user categories don't include shared categories", func(t *testing.T) {
+		userCategories, err := store.GetUserCategoryBoards("user_id_1", "team_id_1")
+		assert.NoError(t, err)
+		assert.Len(t, userCategories, 1)
+		assert.Equal(t, "private_category", userCategories[0].ID)
+		assert.Equal(t, model.CategoryTypePrivate, userCategories[0].Type)
+	})
+
+	t.Run("shared category boards are not owned by users", func(t *testing.T) {
+		assert.NoError(t, store.AddUpdateCategoryBoard("", "shared_category_1", "board_1"))
+		assert.NoError(t, store.AddUpdateCategoryBoard("user_id_1", "private_category", "board_1"))
+
+		sharedCategoryBoards, err := store.GetSharedCategoryBoards("team_id_1")
+		assert.NoError(t, err)
+		assert.Len(t, sharedCategoryBoards, 3)
+		assert.Equal(t, []string{"board_1"}, sharedCategoryBoards[0].BoardIDs)
+		assert.Empty(t, sharedCategoryBoards[1].BoardIDs)
+
+		userCategoryBoards, err := store.GetUserCategoryBoards("user_id_1", "team_id_1")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"board_1"}, userCategoryBoards[0].BoardIDs)
+	})
+
+	t.Run("update nesting and ordering", func(t *testing.T) {
+		category, err := store.GetCategory("shared_category_nested")
+		assert.NoError(t, err)
+
+		category.ParentID = "shared_category_2"
+		category.SortOrder = 0
+		assert.NoError(t, store.UpdateCategory(*category))
+
+		sharedCategories, err := store.GetSharedCategories("team_id_1")
+		assert.NoError(t, err)
+		assert.Equal(t, "shared_category_nested", sharedCategories[0].ID)
+		assert.Equal(t, "shared_category_2", sharedCategories[0].ParentID)
+	})
+}
+
+func testDefaultSharedCategory(t *testing.T, store store.Store) {
+	now := utils.GetMillis()
+	categories := []model.Category{
+		{ID: "shared_category_1", Name: "Shared 1", TeamID: "team_id_1", Type: model.CategoryTypeShared, IsDefault: true},
+		{ID: "shared_category_2", Name: "Shared 2", TeamID: "team_id_1", Type: model.CategoryTypeShared},
+		{ID: "shared_category_other_team", Name: "Other team", TeamID: "team_id_2", Type: model.CategoryTypeShared, IsDefault: true},
+	}
+	for _, category := range categories {
+		category.CreateAt = now
+		category.UpdateAt = now
+		assert.NoError(t, store.CreateCategory(category))
+	}
+
+	defaultCategory, err := store.GetDefaultSharedCategory("team_id_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "shared_category_1", defaultCategory.ID)
+
+	t.Run("setting a default clears the previous one", func(t *testing.T) {
+		assert.NoError(t, store.SetDefaultSharedCategory("team_id_1", "shared_category_2"))
+
+		defaultCategory, err := store.GetDefaultSharedCategory("team_id_1")
+		assert.NoError(t, err)
+		assert.Equal(t, "shared_category_2", defaultCategory.ID)
+
+		previousDefault, err := store.GetCategory("shared_category_1")
+		assert.NoError(t, err)
+		assert.False(t, previousDefault.IsDefault)
+
+		otherTeamDefault, err := store.GetDefaultSharedCategory("team_id_2")
+		assert.NoError(t, err)
+		assert.Equal(t, "shared_category_other_team", otherTeamDefault.ID)
+	})
+
+	t.Run("cannot set the default to a category of another team", func(t *testing.T) {
+		err := store.SetDefaultSharedCategory("team_id_1", "shared_category_other_team")
+		assert.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("clear the default category", func(t *testing.T) {
+		assert.NoError(t, store.SetDefaultSharedCategory("team_id_1", ""))
+
+		_, err := store.GetDefaultSharedCategory("team_id_1")
+		assert.True(t, model.IsErrNotFound(err))
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/mattermost/focalboard/server/services/store"
 	"github.com/mattermost/focalboard/server/utils"
 
+	mmModel "github.com/mattermost/mattermost-server/v6/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -52,6 +53,7 @@ func LoadData(t *testing.T, store store.Store) {
 	validBlock := model.Block{
 		ID:         "id-test",
 		BoardID:    board.ID,
+		Type:       model.TypeCard,
 		ModifiedBy: testUserID,
 	}
 
@@ -111,6 +113,63 @@ func testRunDataRetention(t *testing.T, store store.Store, batchSize int) {
 		require.Equal(t, int64(0), deletions)
 	})
 
+	t.Run("test archived boards are kept", func(t *testing.T) {
+		retentionDate := utils.GetMillisForTime(time.Now().Add(time.Hour * 1))
+		archiveAt := utils.GetMillis()
+		notArchived := int64(0)
+
+		_, err := store.PatchBoard(boardID, &model.BoardPatch{ArchiveAt: &archiveAt}, testUserID)
+		require.NoError(t, err)
+		deletions, err := store.RunDataRetention(retentionDate, int64(batchSize))
+		require.NoError(t, err)
+		require.Equal(t, int64(0), deletions)
+
+		_, err = store.PatchBoard(boardID, &model.BoardPatch{ArchiveAt: &notArchived}, testUserID)
+		require.NoError(t, err)
+	})
+
+	t.Run("test only the archived cards of a board are kept", func(t *testing.T) {
+		archiveAt := utils.GetMillis()
+		notArchived := int64(0)
+
+		kept := []model.Block{
+			{ID: "id-test-text", BoardID: boardID, ParentID: "id-test", Type: model.TypeText},
+			{ID: "id-test-nested", BoardID: boardID, ParentID: "id-test-text", Type: model.TypeText},
+			{ID: "id-test-view", BoardID: boardID, ParentID: boardID, Type: model.TypeView},
+		}
+		err := store.InsertBlocks(kept, testUserID)
+		require.NoError(t, err)
+		err = store.PatchBlock("id-test", &model.BlockPatch{ArchiveAt: &archiveAt}, testUserID)
+		require.NoError(t, err)
+		err = store.PatchBlock("id-test2", &model.BlockPatch{Title: mmModel.NewString("updated")}, testUserID)
+		require.NoError(t, err)
+
+		retentionDate := utils.GetMillisForTime(time.Now().Add(time.Hour * 1))
+		deletions, err := store.RunDataRetention(retentionDate, int64(batchSize))
+		require.NoError(t, err)
+		// the three other blocks, and their four versions in the history
+		require.Equal(t, int64(7), deletions)
+
+		blocks, err := store.GetBlocksWithBoardID(boardID)
+		require.NoError(t, err)
+		blockIDs := []string{}
+		for _, block := range blocks {
+			blockIDs = append(blockIDs, block.ID)
+		}
+		require.ElementsMatch(t, []string{"id-test", "id-test-text", "id-test-nested", "id-test-view"}, blockIDs)
+
+		history, err := store.GetBlockHistory("id-test2", model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		require.Empty(t, history)
+
+		board, err := store.GetBoard(boardID)
+		require.NoError(t, err)
+		require.NotNil(t, board)
+
+		err = store.PatchBlock("id-test", &model.BlockPatch{ArchiveAt: &notArchived}, testUserID)
+		require.NoError(t, err)
+	})
+
 	t.Run("test all deletions", func(t *testing.T) {
 		deletions, err := store.RunDataRetention(utils.GetMillisForTime(time.Now().Add(time.Hour*1)), int64(batchSize))
 		require.NoError(t, err)
@@ -0,0 +1,87 @@
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestFeedTokenStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("UpsertAndGetFeedToken", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUpsertAndGetFeedToken(t, store)
+	})
+	t.Run("DeleteFeedToken", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteFeedToken(t, store)
+	})
+}
+
+func newTestFeedToken(userID string) *model.FeedToken {
+	return &model.FeedToken{
+		UserID:   userID,
+		Token:    utils.NewID(utils.IDTypeToken),
+		CreateAt: utils.GetMillis(),
+	}
+}
+
+func testUpsertAndGetFeedToken(t *testing.T, store store.Store) {
+	t.Run("a user without token", func(t *testing.T) {
+		feedToken, err := store.GetFeedToken("user-without-token")
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, feedToken)
+
+		feedToken, err = store.GetFeedTokenByToken("unknown-token")
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, feedToken)
+	})
+
+	t.Run("get by user and by token", func(t *testing.T) {
+		feedToken := newTestFeedToken("user-1")
+		require.NoError(t, store.UpsertFeedToken(feedToken))
+
+		saved, err := store.GetFeedToken("user-1")
+		require.NoError(t, err)
+		require.Equal(t, feedToken, saved)
+
+		saved, err = store.GetFeedTokenByToken(feedToken.Token)
+		require.NoError(t, err)
+		require.Equal(t, feedToken, saved)
+	})
+
+	t.Run("a new token replaces the previous one", func(t *testing.T) {
+		previous := newTestFeedToken("user-2")
+		require.NoError(t, store.UpsertFeedToken(previous))
+		feedToken := newTestFeedToken("user-2")
+		require.NoError(t, store.UpsertFeedToken(feedToken))
+
+		saved, err := store.GetFeedToken("user-2")
+		require.NoError(t, err)
+		require.Equal(t, feedToken.Token, saved.Token)
+
+		_, err = store.GetFeedTokenByToken(previous.Token)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testDeleteFeedToken(t *testing.T, store store.Store) {
+	feedToken := newTestFeedToken("user-1")
+	require.NoError(t, store.UpsertFeedToken(feedToken))
+	other := newTestFeedToken("user-2")
+	require.NoError(t, store.UpsertFeedToken(other))
+
+	require.NoError(t, store.DeleteFeedToken("user-1"))
+
+	_, err := store.GetFeedTokenByToken(feedToken.Token)
+	require.True(t, model.IsErrNotFound(err))
+	_, err = store.GetFeedToken(other.UserID)
+	require.NoError(t, err)
+
+	err = store.DeleteFeedToken("user-1")
+	require.True(t, model.IsErrNotFound(err))
+}
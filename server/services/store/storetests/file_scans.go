@@ -0,0 +1,138 @@
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestFileScanStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("InsertAndGetFileScan", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testInsertAndGetFileScan(t, store)
+	})
+	t.Run("UpdateFileScan", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUpdateFileScan(t, store)
+	})
+	t.Run("GetUnfinishedFileScans", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUnfinishedFileScans(t, store)
+	})
+	t.Run("DeleteFileScan", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteFileScan(t, store)
+	})
+}
+
+func newTestFileScan(boardID string) *model.FileScan {
+	return &model.FileScan{
+		FileID:    utils.NewID(utils.IDTypeNone) + ".png",
+		BoardID:   boardID,
+		TeamID:    testTeamID,
+		CreatedBy: testUserID,
+	}
+}
+
+func testInsertAndGetFileScan(t *testing.T, store store.Store) {
+	t.Run("a new scan is pending", func(t *testing.T) {
+		scan := newTestFileScan("board-id")
+		require.NoError(t, store.InsertFileScan(scan))
+		require.NotZero(t, scan.CreateAt)
+
+		saved, err := store.GetFileScan("board-id", scan.FileID)
+		require.NoError(t, err)
+		require.Equal(t, scan.FileID, saved.FileID)
+		require.Equal(t, testTeamID, saved.TeamID)
+		require.Equal(t, testUserID, saved.CreatedBy)
+		require.Equal(t, model.FileScanStatusPending, saved.Status)
+		require.Empty(t, saved.Result)
+	})
+
+	t.Run("the same file name in another board", func(t *testing.T) {
+		scan := newTestFileScan("board-id")
+		require.NoError(t, store.InsertFileScan(scan))
+
+		_, err := store.GetFileScan("other-board-id", scan.FileID)
+		require.True(t, model.IsErrNotFound(err))
+
+		copied := *scan
+		copied.BoardID = "other-board-id"
+		copied.Status = model.FileScanStatusInfected
+		require.NoError(t, store.InsertFileScan(&copied))
+
+		saved, err := store.GetFileScan("other-board-id", scan.FileID)
+		require.NoError(t, err)
+		require.Equal(t, model.FileScanStatusInfected, saved.Status)
+	})
+
+	t.Run("unknown file", func(t *testing.T) {
+		_, err := store.GetFileScan("board-id", "unknown.png")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testUpdateFileScan(t *testing.T, store store.Store) {
+	scan := newTestFileScan("board-id")
+	require.NoError(t, store.InsertFileScan(scan))
+
+	scan.Status = model.FileScanStatusInfected
+	scan.Result = "Eicar-Test-Signature"
+	require.NoError(t, store.UpdateFileScan(scan))
+
+	saved, err := store.GetFileScan("board-id", scan.FileID)
+	require.NoError(t, err)
+	require.Equal(t, model.FileScanStatusInfected, saved.Status)
+	require.Equal(t, "Eicar-Test-Signature", saved.Result)
+	require.GreaterOrEqual(t, saved.UpdateAt, saved.CreateAt)
+
+	t.Run("unknown file", func(t *testing.T) {
+		unknown := newTestFileScan("board-id")
+		unknown.Status = model.FileScanStatusClean
+		require.True(t, model.IsErrNotFound(store.UpdateFileScan(unknown)))
+	})
+}
+
+func testGetUnfinishedFileScans(t *testing.T, store store.Store) {
+	pending := newTestFileScan("board-id")
+	failed := newTestFileScan("board-id")
+	failed.Status = model.FileScanStatusFailed
+	clean := newTestFileScan("board-id")
+	clean.Status = model.FileScanStatusClean
+	infected := newTestFileScan("board-id")
+	infected.Status = model.FileScanStatusInfected
+	for _, scan := range []*model.FileScan{pending, failed, clean, infected} {
+		require.NoError(t, store.InsertFileScan(scan))
+	}
+
+	scans, err := store.GetUnfinishedFileScans(pending.CreateAt)
+	require.NoError(t, err)
+	require.Empty(t, scans)
+
+	scans, err = store.GetUnfinishedFileScans(utils.GetMillis() + 1)
+	require.NoError(t, err)
+	fileIDs := []string{}
+	for _, scan := range scans {
+		fileIDs = append(fileIDs, scan.FileID)
+	}
+	require.ElementsMatch(t, []string{pending.FileID, failed.FileID}, fileIDs)
+}
+
+func testDeleteFileScan(t *testing.T, store store.Store) {
+	scan := newTestFileScan("board-id")
+	require.NoError(t, store.InsertFileScan(scan))
+
+	require.NoError(t, store.DeleteFileScan("board-id", scan.FileID))
+	_, err := store.GetFileScan("board-id", scan.FileID)
+	require.True(t, model.IsErrNotFound(err))
+
+	// deleting the scan of a file that wasn't scanned isn't an error
+	require.NoError(t, store.DeleteFileScan("board-id", scan.FileID))
+}
@@ -1,12 +1,15 @@
 package storetests
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/store"
 	"github.com/mattermost/focalboard/server/utils"
 	mmModel "github.com/mattermost/mattermost-server/v6/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func StoreTestFileStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
@@ -35,4 +38,134 @@ func StoreTestFileStore(t *testing.T, setup func(t *testing.T) (store.Store, fun
 		assert.Equal(t, int64(0), retrievedFileInfo.DeleteAt)
 		assert.False(t, retrievedFileInfo.Archived)
 	})
+
+	t.Run("should save and retrieve the metadata of images", func(t *testing.T) {
+		fileInfo := &mmModel.FileInfo{
+			Id:              "file_info_2",
+			CreateAt:        utils.GetMillis(),
+			Name:            "logo.png",
+			Extension:       ".png",
+			Size:            2048,
+			Path:            "team-id/board-id/7file_info_2.png",
+			ThumbnailPath:   "team-id/board-id/7file_info_2_thumb.jpg",
+			PreviewPath:     "team-id/board-id/7file_info_2_preview.jpg",
+			MimeType:        "image/png",
+			Width:           640,
+			Height:          480,
+			HasPreviewImage: true,
+		}
+
+		err := sqlStore.SaveFileInfo(fileInfo)
+		assert.NoError(t, err)
+
+		retrievedFileInfo, err := sqlStore.GetFileInfo("file_info_2")
+		assert.NoError(t, err)
+		assert.Equal(t, fileInfo.Path, retrievedFileInfo.Path)
+		assert.Equal(t, fileInfo.ThumbnailPath, retrievedFileInfo.ThumbnailPath)
+		assert.Equal(t, fileInfo.PreviewPath, retrievedFileInfo.PreviewPath)
+		assert.Equal(t, "image/png", retrievedFileInfo.MimeType)
+		assert.Equal(t, 640, retrievedFileInfo.Width)
+		assert.Equal(t, 480, retrievedFileInfo.Height)
+		assert.True(t, retrievedFileInfo.HasPreviewImage)
+	})
+
+	t.Run("should update the images of a fileinfo", func(t *testing.T) {
+		fileInfo := &mmModel.FileInfo{
+			Id:        "file_info_3",
+			CreateAt:  utils.GetMillis(),
+			Name:      "photo.jpg",
+			Extension: ".jpg",
+			Size:      4096,
+			Path:      "team-id/board-id/7file_info_3.jpg",
+			MimeType:  "image/jpeg",
+		}
+		require.NoError(t, sqlStore.SaveFileInfo(fileInfo))
+
+		fileInfo.ThumbnailPath = "team-id/board-id/7file_info_3_thumb.jpg"
+		fileInfo.PreviewPath = "team-id/board-id/7file_info_3_preview.jpg"
+		fileInfo.Width = 800
+		fileInfo.Height = 600
+		fileInfo.HasPreviewImage = true
+		require.NoError(t, sqlStore.UpdateFileInfoImages(fileInfo))
+
+		retrievedFileInfo, err := sqlStore.GetFileInfo("file_info_3")
+		require.NoError(t, err)
+		assert.Equal(t, fileInfo.ThumbnailPath, retrievedFileInfo.ThumbnailPath)
+		assert.Equal(t, fileInfo.PreviewPath, retrievedFileInfo.PreviewPath)
+		assert.Equal(t, 800, retrievedFileInfo.Width)
+		assert.Equal(t, 600, retrievedFileInfo.Height)
+		assert.True(t, retrievedFileInfo.HasPreviewImage)
+		assert.Equal(t, "photo.jpg", retrievedFileInfo.Name)
+	})
+}
+
+func StoreTestFileCleanup(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	sqlStore, tearDown := setup(t)
+	defer tearDown()
+
+	t.Run("should return the fileinfos created before a time", func(t *testing.T) {
+		now := utils.GetMillis()
+		for i, createAt := range []int64{now - 3000, now - 2000, now} {
+			fileInfo := &mmModel.FileInfo{
+				Id:       fmt.Sprintf("old_file_%d", i),
+				CreateAt: createAt,
+				Name:     "file.txt",
+				Path:     "cleanup-team/board-id/file.txt",
+				Size:     100,
+			}
+			require.NoError(t, sqlStore.SaveFileInfo(fileInfo))
+		}
+
+		fileInfos, err := sqlStore.GetFileInfosCreatedBefore(now - 1000)
+		require.NoError(t, err)
+		require.Len(t, fileInfos, 2)
+		assert.Equal(t, "old_file_0", fileInfos[0].Id)
+		assert.Equal(t, "old_file_1", fileInfos[1].Id)
+		assert.Equal(t, "cleanup-team/board-id/file.txt", fileInfos[0].Path)
+	})
+
+	t.Run("should return the size of the files of a team", func(t *testing.T) {
+		size, err := sqlStore.GetTeamFilesSize("cleanup-team")
+		require.NoError(t, err)
+		assert.Equal(t, int64(300), size)
+
+		size, err = sqlStore.GetTeamFilesSize("empty-team")
+		require.NoError(t, err)
+		assert.Zero(t, size)
+	})
+
+	t.Run("should delete a fileinfo", func(t *testing.T) {
+		require.NoError(t, sqlStore.DeleteFileInfo("old_file_0"))
+
+		fileInfo, err := sqlStore.GetFileInfo("old_file_0")
+		require.NoError(t, err)
+		assert.Nil(t, fileInfo)
+
+		size, err := sqlStore.GetTeamFilesSize("cleanup-team")
+		require.NoError(t, err)
+		assert.Equal(t, int64(200), size)
+
+		// deleting a missing fileinfo isn't an error
+		require.NoError(t, sqlStore.DeleteFileInfo("old_file_0"))
+	})
+
+	t.Run("should return the files of the blocks, deleted or not", func(t *testing.T) {
+		userID := testUserID
+		blocks := []*model.Block{
+			{ID: "image-1", BoardID: "board-id", ParentID: "card-id", Type: model.TypeImage, Fields: map[string]interface{}{"fileId": "7image1.png"}},
+			{ID: "image-2", BoardID: "board-id", ParentID: "card-id", Type: model.TypeImage, Fields: map[string]interface{}{"fileId": "7image2.png"}},
+			{ID: "image-3", BoardID: "board-id", ParentID: "card-id", Type: model.TypeImage, Fields: map[string]interface{}{}},
+			{ID: "text-1", BoardID: "board-id", ParentID: "card-id", Type: model.TypeText, Fields: map[string]interface{}{"fileId": "7text.png"}},
+			{ID: "comment-1", BoardID: "board-id", ParentID: "card-id", Type: model.TypeComment, Fields: map[string]interface{}{"fileId": "7comment.pdf"}},
+			{ID: "text-2", BoardID: "board-id", ParentID: "card-id", Type: model.TypeText, Title: "fileId", Fields: map[string]interface{}{"other": "fileId"}},
+		}
+		for _, block := range blocks {
+			require.NoError(t, sqlStore.InsertBlock(block, userID))
+		}
+		require.NoError(t, sqlStore.DeleteBlock("image-2", userID))
+
+		fileIDs, err := sqlStore.GetBlockFileIDs()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"7image1.png", "7image2.png", "7text.png", "7comment.pdf"}, fileIDs)
+	})
 }
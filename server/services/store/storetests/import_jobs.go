@@ -0,0 +1,155 @@
+package storetests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestImportJobStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("InsertAndGetImportJob", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testInsertAndGetImportJob(t, store)
+	})
+
+	t.Run("UpdateImportJob", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUpdateImportJob(t, store)
+	})
+
+	t.Run("GetImportJobsForUser", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetImportJobsForUser(t, store)
+	})
+
+	t.Run("CreateImportedBoardsAndBlocks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCreateImportedBoardsAndBlocks(t, store)
+	})
+}
+
+func createTestImportJob(t *testing.T, store store.Store, teamID, userID string) *model.ImportJob {
+	job, err := store.InsertImportJob(&model.ImportJob{
+		TeamID:    teamID,
+		CreatedBy: userID,
+		DryRun:    true,
+		Filename:  "archive.boardarchive",
+		FilePath:  "team-id/imports/archive.boardarchive",
+		Size:      200,
+	})
+	require.NoError(t, err)
+	return job
+}
+
+func testInsertAndGetImportJob(t *testing.T, store store.Store) {
+	t.Run("insert and get a job", func(t *testing.T) {
+		job := createTestImportJob(t, store, "team-id", "user-id")
+		require.NotEmpty(t, job.ID)
+		require.Equal(t, model.ImportJobStatusPending, job.Status)
+		require.NotZero(t, job.CreateAt)
+
+		got, err := store.GetImportJob(job.ID)
+		require.NoError(t, err)
+		require.Equal(t, "team-id", got.TeamID)
+		require.Equal(t, "user-id", got.CreatedBy)
+		require.Equal(t, model.ImportJobStatusPending, got.Status)
+		require.True(t, got.DryRun)
+		require.Equal(t, "archive.boardarchive", got.Filename)
+		require.Equal(t, "team-id/imports/archive.boardarchive", got.FilePath)
+		require.EqualValues(t, 200, got.Size)
+		require.Empty(t, got.ImportedBoards)
+		require.Empty(t, got.SkippedLines)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		_, err := store.GetImportJob("unknown-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testUpdateImportJob(t *testing.T, store store.Store) {
+	t.Run("update the progress of a job", func(t *testing.T) {
+		job := createTestImportJob(t, store, "team-id", "user-id")
+		createAt := job.CreateAt
+		time.Sleep(10 * time.Millisecond)
+
+		job.Status = model.ImportJobStatusFailed
+		job.SetBytesRead(50)
+		job.BoardsProcessed = 1
+		job.ImportedBoards = map[string]string{"old-board-id": "new-board-id"}
+		job.SkippedLines = []model.ImportSkippedLine{{Board: "old-board-id", Line: 3, Type: "comment", Reason: "unsupported"}}
+		job.Error = "import failed"
+		_, err := store.UpdateImportJob(job)
+		require.NoError(t, err)
+
+		got, err := store.GetImportJob(job.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.ImportJobStatusFailed, got.Status)
+		require.EqualValues(t, 50, got.BytesRead)
+		require.Equal(t, 25, got.Progress)
+		require.Equal(t, 1, got.BoardsProcessed)
+		require.Equal(t, map[string]string{"old-board-id": "new-board-id"}, got.ImportedBoards)
+		require.Equal(t, job.SkippedLines, got.SkippedLines)
+		require.Equal(t, "import failed", got.Error)
+		require.Equal(t, createAt, got.CreateAt)
+		require.Greater(t, got.UpdateAt, createAt)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		_, err := store.UpdateImportJob(&model.ImportJob{ID: "unknown-id"})
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testGetImportJobsForUser(t *testing.T, store store.Store) {
+	first := createTestImportJob(t, store, "team-id", "user-id")
+	time.Sleep(10 * time.Millisecond)
+	second := createTestImportJob(t, store, "team-id", "user-id")
+	createTestImportJob(t, store, "team-id", "other-user-id")
+	createTestImportJob(t, store, "other-team-id", "user-id")
+
+	jobs, err := store.GetImportJobsForUser("team-id", "user-id")
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	require.Equal(t, second.ID, jobs[0].ID)
+	require.Equal(t, first.ID, jobs[1].ID)
+
+	jobs, err = store.GetImportJobsForUser("team-id", "no-jobs-user-id")
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+}
+
+func testCreateImportedBoardsAndBlocks(t *testing.T, store store.Store) {
+	job := createTestImportJob(t, store, "team-id", "user-id")
+	boardID := utils.NewID(utils.IDTypeBoard)
+	bab := &model.BoardsAndBlocks{
+		Boards: []*model.Board{{ID: boardID, TeamID: "team-id", Type: model.BoardTypeOpen, Title: "Imported board"}},
+		Blocks: []model.Block{{ID: utils.NewID(utils.IDTypeCard), BoardID: boardID, ParentID: boardID, Type: model.TypeCard}},
+	}
+	job.ImportedBoards = map[string]string{"board-dir": boardID}
+
+	newBab, members, err := store.CreateImportedBoardsAndBlocks(bab, "user-id", job)
+	require.NoError(t, err)
+	require.Len(t, newBab.Boards, 1)
+	require.Len(t, newBab.Blocks, 1)
+	require.Len(t, members, 1)
+	require.True(t, members[0].SchemeAdmin)
+
+	// the progress of the job is saved along with the board
+	got, err := store.GetImportJob(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"board-dir": boardID}, got.ImportedBoards)
+
+	board, err := store.GetBoard(boardID)
+	require.NoError(t, err)
+	require.Equal(t, "Imported board", board.Title)
+}
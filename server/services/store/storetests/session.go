@@ -28,6 +28,12 @@ func StoreTestSessionStore(t *testing.T, setup func(t *testing.T) (store.Store,
 		defer tearDown()
 		testUpdateSession(t, store)
 	})
+
+	t.Run("DeleteSessionsForUser", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteSessionsForUser(t, store)
+	})
 }
 
 func testCreateAndGetAndDeleteSession(t *testing.T, store store.Store) {
@@ -99,3 +105,28 @@ func testUpdateSession(t *testing.T, store store.Store) {
 	require.NoError(t, err)
 	require.Equal(t, session, got)
 }
+
+func testDeleteSessionsForUser(t *testing.T, store store.Store) {
+	sessions := []*model.Session{
+		{ID: "session-1", Token: "token-1", UserID: "user-1"},
+		{ID: "session-2", Token: "token-2", UserID: "user-1"},
+		{ID: "session-3", Token: "token-3", UserID: "user-2"},
+	}
+	for _, session := range sessions {
+		require.NoError(t, store.CreateSession(session))
+	}
+
+	deleted, err := store.DeleteSessionsForUser("user-1")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+
+	_, err = store.GetSession("token-1", 60*60)
+	require.Error(t, err)
+	got, err := store.GetSession("token-3", 60*60)
+	require.NoError(t, err)
+	require.Equal(t, "user-2", got.UserID)
+
+	deleted, err = store.DeleteSessionsForUser("user-1")
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+}
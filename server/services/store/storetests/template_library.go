@@ -0,0 +1,221 @@
+package storetests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+)
+
+func StoreTestTemplateLibraryStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("InsertAndGetTemplate", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testInsertAndGetTemplate(t, store)
+	})
+
+	t.Run("UpdateTemplate", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUpdateTemplate(t, store)
+	})
+
+	t.Run("GetTemplates", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetTemplates(t, store)
+	})
+
+	t.Run("DeleteTemplate", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteTemplate(t, store)
+	})
+
+	t.Run("TemplateDerivedBoards", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testTemplateDerivedBoards(t, store)
+	})
+}
+
+func createTestTemplate(t *testing.T, store store.Store, teamID, boardID, sourceBoardID, category string, tags []string) *model.Template {
+	template, err := store.InsertTemplate(&model.Template{
+		TeamID:        teamID,
+		BoardID:       boardID,
+		SourceBoardID: sourceBoardID,
+		Category:      category,
+		Tags:          tags,
+		Version:       1,
+		CreatedBy:     "user-id",
+		ModifiedBy:    "user-id",
+	})
+	require.NoError(t, err)
+	return template
+}
+
+func testInsertAndGetTemplate(t *testing.T, store store.Store) {
+	template := createTestTemplate(t, store, "team-id", "board-id", "source-board-id", "Engineering", []string{"sprint", "agile"})
+	require.NotEmpty(t, template.ID)
+	require.NotZero(t, template.CreateAt)
+
+	t.Run("by id", func(t *testing.T) {
+		rTemplate, err := store.GetTemplate(template.ID)
+		require.NoError(t, err)
+		assert.Equal(t, template, rTemplate)
+	})
+
+	t.Run("by template board", func(t *testing.T) {
+		rTemplate, err := store.GetTemplateForBoard("board-id")
+		require.NoError(t, err)
+		assert.Equal(t, template.ID, rTemplate.ID)
+	})
+
+	t.Run("by source board", func(t *testing.T) {
+		rTemplate, err := store.GetTemplateForSourceBoard("team-id", "source-board-id")
+		require.NoError(t, err)
+		assert.Equal(t, template.ID, rTemplate.ID)
+
+		_, err = store.GetTemplateForSourceBoard("other-team-id", "source-board-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("nonexistent template", func(t *testing.T) {
+		_, err := store.GetTemplate("nonexistent-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		_, err := store.InsertTemplate(&model.Template{TeamID: "team-id", BoardID: "board-id", Tags: []string{" "}})
+		require.ErrorIs(t, err, model.ErrTemplateInvalidTag)
+	})
+}
+
+func testUpdateTemplate(t *testing.T, store store.Store) {
+	template := createTestTemplate(t, store, "team-id", "board-id", "source-board-id", "Engineering", nil)
+	require.Empty(t, template.Tags)
+
+	template.BoardID = "board-id-2"
+	template.Version = 2
+	template.Category = "Product"
+	template.Tags = []string{"roadmap"}
+	template.ModifiedBy = "user-id-2"
+	_, err := store.UpdateTemplate(template)
+	require.NoError(t, err)
+
+	rTemplate, err := store.GetTemplate(template.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "board-id-2", rTemplate.BoardID)
+	assert.Equal(t, 2, rTemplate.Version)
+	assert.Equal(t, "Product", rTemplate.Category)
+	assert.Equal(t, []string{"roadmap"}, rTemplate.Tags)
+	assert.Equal(t, "user-id", rTemplate.CreatedBy)
+	assert.Equal(t, "user-id-2", rTemplate.ModifiedBy)
+
+	t.Run("nonexistent template", func(t *testing.T) {
+		_, err := store.UpdateTemplate(&model.Template{ID: "nonexistent-id"})
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testGetTemplates(t *testing.T, store store.Store) {
+	createTestTemplate(t, store, "team-id", "board-1", "", "Engineering", []string{"Sprint"})
+	createTestTemplate(t, store, "team-id", "board-2", "", "Engineering", []string{"bugs"})
+	createTestTemplate(t, store, "team-id", "board-3", "", "Product", []string{"sprint", "roadmap"})
+	createTestTemplate(t, store, "other-team-id", "board-4", "", "Engineering", []string{"sprint"})
+
+	boardIDs := func(templates []*model.Template) []string {
+		ids := []string{}
+		for _, template := range templates {
+			ids = append(ids, template.BoardID)
+		}
+		return ids
+	}
+
+	t.Run("all templates of a team", func(t *testing.T) {
+		templates, err := store.GetTemplates(model.QueryTemplatesOptions{TeamID: "team-id"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"board-1", "board-2", "board-3"}, boardIDs(templates))
+	})
+
+	t.Run("by category", func(t *testing.T) {
+		templates, err := store.GetTemplates(model.QueryTemplatesOptions{TeamID: "team-id", Category: "Engineering"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"board-1", "board-2"}, boardIDs(templates))
+	})
+
+	t.Run("by tag, ignoring case", func(t *testing.T) {
+		templates, err := store.GetTemplates(model.QueryTemplatesOptions{TeamID: "team-id", Tag: "sprint"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"board-1", "board-3"}, boardIDs(templates))
+	})
+
+	t.Run("by category and tag", func(t *testing.T) {
+		templates, err := store.GetTemplates(model.QueryTemplatesOptions{TeamID: "team-id", Category: "Product", Tag: "sprint"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"board-3"}, boardIDs(templates))
+	})
+}
+
+func testDeleteTemplate(t *testing.T, store store.Store) {
+	template := createTestTemplate(t, store, "team-id", "board-id", "", "", nil)
+	require.NoError(t, store.SaveTemplateDerivedBoard(&model.TemplateDerivedBoard{
+		BoardID:         "derived-board-id",
+		TemplateID:      template.ID,
+		TemplateVersion: 1,
+	}))
+
+	require.NoError(t, store.DeleteTemplate(template.ID))
+
+	_, err := store.GetTemplate(template.ID)
+	require.True(t, model.IsErrNotFound(err))
+
+	_, err = store.GetTemplateDerivedBoard("derived-board-id")
+	require.True(t, model.IsErrNotFound(err))
+
+	err = store.DeleteTemplate(template.ID)
+	require.True(t, model.IsErrNotFound(err))
+}
+
+func testTemplateDerivedBoards(t *testing.T, store store.Store) {
+	template := createTestTemplate(t, store, "team-id", "board-id", "", "", nil)
+
+	require.NoError(t, store.SaveTemplateDerivedBoard(&model.TemplateDerivedBoard{
+		BoardID:         "derived-board-1",
+		TemplateID:      template.ID,
+		TemplateVersion: 1,
+	}))
+	require.NoError(t, store.SaveTemplateDerivedBoard(&model.TemplateDerivedBoard{
+		BoardID:         "derived-board-2",
+		TemplateID:      template.ID,
+		TemplateVersion: 1,
+	}))
+
+	derivedBoards, err := store.GetTemplateDerivedBoards(template.ID)
+	require.NoError(t, err)
+	require.Len(t, derivedBoards, 2)
+
+	t.Run("update the version of a derived board", func(t *testing.T) {
+		require.NoError(t, store.SaveTemplateDerivedBoard(&model.TemplateDerivedBoard{
+			BoardID:         "derived-board-1",
+			TemplateID:      template.ID,
+			TemplateVersion: 3,
+		}))
+
+		derivedBoard, err := store.GetTemplateDerivedBoard("derived-board-1")
+		require.NoError(t, err)
+		assert.Equal(t, 3, derivedBoard.TemplateVersion)
+
+		derivedBoards, err := store.GetTemplateDerivedBoards(template.ID)
+		require.NoError(t, err)
+		require.Len(t, derivedBoards, 2)
+	})
+
+	t.Run("nonexistent derived board", func(t *testing.T) {
+		_, err := store.GetTemplateDerivedBoard("nonexistent-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
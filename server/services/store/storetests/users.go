@@ -43,6 +43,12 @@ func StoreTestUserStore(t *testing.T, setup func(t *testing.T) (store.Store, fun
 		defer tearDown()
 		testPatchUserProps(t, store)
 	})
+
+	t.Run("GetAllUsersAndDeactivateUser", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetAllUsersAndDeactivateUser(t, store)
+	})
 }
 
 func testGetTeamUsers(t *testing.T, store store.Store) {
@@ -226,3 +232,38 @@ func testPatchUserProps(t *testing.T, store store.Store) {
 	require.False(t, ok)
 	require.Equal(t, fetchedUser.Props["new_key_3"], "new_value_3_new_again")
 }
+
+func testGetAllUsersAndDeactivateUser(t *testing.T, store store.Store) {
+	for _, username := range []string{"zoe", "adam"} {
+		err := store.CreateUser(&model.User{
+			ID:       utils.NewID(utils.IDTypeUser),
+			Username: username,
+		})
+		require.NoError(t, err)
+	}
+
+	users, err := store.GetAllUsers()
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	require.Equal(t, "adam", users[0].Username)
+	require.Equal(t, "zoe", users[1].Username)
+
+	t.Run("DeactivateUser", func(t *testing.T) {
+		err := store.DeactivateUser(users[1].ID)
+		require.NoError(t, err)
+
+		_, err = store.GetUserByUsername("zoe")
+		require.Error(t, err)
+
+		// deactivated users are still listed
+		all, err := store.GetAllUsers()
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		require.NotZero(t, all[1].DeleteAt)
+	})
+
+	t.Run("DeactivateUser twice", func(t *testing.T) {
+		err := store.DeactivateUser(users[1].ID)
+		require.Error(t, err)
+	})
+}
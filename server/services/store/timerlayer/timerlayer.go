@@ -0,0 +1,1121 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Code generated by "make generate" from the Store interface
+// DO NOT EDIT
+
+// The timer layer wraps a store and reports the duration of every
+// method call, labelled by method name and result, to the metrics
+// service.
+
+package timerlayer
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/metrics"
+	"github.com/mattermost/focalboard/server/services/store"
+
+	mmModel "github.com/mattermost/mattermost-server/v6/model"
+)
+
+// TimerLayer is a store layer that measures the time spent in each
+// store method.
+type TimerLayer struct {
+	store.Store
+	metrics *metrics.Metrics
+}
+
+// New creates a TimerLayer wrapping the given store.
+func New(childStore store.Store, metrics *metrics.Metrics) *TimerLayer {
+	return &TimerLayer{
+		Store:   childStore,
+		metrics: metrics,
+	}
+}
+
+func (s *TimerLayer) AddUpdateCategoryBoard(userID string, categoryID string, blockID string) error {
+	start := time.Now()
+	err := s.Store.AddUpdateCategoryBoard(userID, categoryID, blockID)
+	s.metrics.ObserveStoreMethodDuration("AddUpdateCategoryBoard", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) CleanUpSessions(expireTime int64) error {
+	start := time.Now()
+	err := s.Store.CleanUpSessions(expireTime)
+	s.metrics.ObserveStoreMethodDuration("CleanUpSessions", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
+	start := time.Now()
+	result, err := s.Store.CreateBoardsAndBlocks(bab, userID)
+	s.metrics.ObserveStoreMethodDuration("CreateBoardsAndBlocks", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) CreateBoardsAndBlocksWithAdmin(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.CreateBoardsAndBlocksWithAdmin(bab, userID)
+	s.metrics.ObserveStoreMethodDuration("CreateBoardsAndBlocksWithAdmin", err == nil, time.Since(start))
+	return result, resultVar1, err
+}
+
+func (s *TimerLayer) CreateCategory(category model.Category) error {
+	start := time.Now()
+	err := s.Store.CreateCategory(category)
+	s.metrics.ObserveStoreMethodDuration("CreateCategory", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) CreateImportedBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, job *model.ImportJob) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.CreateImportedBoardsAndBlocks(bab, userID, job)
+	s.metrics.ObserveStoreMethodDuration("CreateImportedBoardsAndBlocks", err == nil, time.Since(start))
+	return result, resultVar1, err
+}
+
+func (s *TimerLayer) CreateSession(session *model.Session) error {
+	start := time.Now()
+	err := s.Store.CreateSession(session)
+	s.metrics.ObserveStoreMethodDuration("CreateSession", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) CreateSubscription(sub *model.Subscription) (*model.Subscription, error) {
+	start := time.Now()
+	result, err := s.Store.CreateSubscription(sub)
+	s.metrics.ObserveStoreMethodDuration("CreateSubscription", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) CreateUser(user *model.User) error {
+	start := time.Now()
+	err := s.Store.CreateUser(user)
+	s.metrics.ObserveStoreMethodDuration("CreateUser", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeactivateUser(userID string) error {
+	start := time.Now()
+	err := s.Store.DeactivateUser(userID)
+	s.metrics.ObserveStoreMethodDuration("DeactivateUser", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteAuditRecordsBefore(date int64) (int64, error) {
+	start := time.Now()
+	result, err := s.Store.DeleteAuditRecordsBefore(date)
+	s.metrics.ObserveStoreMethodDuration("DeleteAuditRecordsBefore", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) DeleteBlock(blockID string, modifiedBy string) error {
+	start := time.Now()
+	err := s.Store.DeleteBlock(blockID, modifiedBy)
+	s.metrics.ObserveStoreMethodDuration("DeleteBlock", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteBlocks(blockIDs []string, modifiedBy string) error {
+	start := time.Now()
+	err := s.Store.DeleteBlocks(blockIDs, modifiedBy)
+	s.metrics.ObserveStoreMethodDuration("DeleteBlocks", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteBoard(boardID string, userID string) error {
+	start := time.Now()
+	err := s.Store.DeleteBoard(boardID, userID)
+	s.metrics.ObserveStoreMethodDuration("DeleteBoard", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID string) error {
+	start := time.Now()
+	err := s.Store.DeleteBoardsAndBlocks(dbab, userID)
+	s.metrics.ObserveStoreMethodDuration("DeleteBoardsAndBlocks", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteCategory(categoryID string, userID string, teamID string) error {
+	start := time.Now()
+	err := s.Store.DeleteCategory(categoryID, userID, teamID)
+	s.metrics.ObserveStoreMethodDuration("DeleteCategory", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteFeedToken(userID string) error {
+	start := time.Now()
+	err := s.Store.DeleteFeedToken(userID)
+	s.metrics.ObserveStoreMethodDuration("DeleteFeedToken", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteFileInfo(id string) error {
+	start := time.Now()
+	err := s.Store.DeleteFileInfo(id)
+	s.metrics.ObserveStoreMethodDuration("DeleteFileInfo", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteFileScan(boardID string, fileID string) error {
+	start := time.Now()
+	err := s.Store.DeleteFileScan(boardID, fileID)
+	s.metrics.ObserveStoreMethodDuration("DeleteFileScan", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteMember(boardID string, userID string) error {
+	start := time.Now()
+	err := s.Store.DeleteMember(boardID, userID)
+	s.metrics.ObserveStoreMethodDuration("DeleteMember", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteNotificationHint(blockID string) error {
+	start := time.Now()
+	err := s.Store.DeleteNotificationHint(blockID)
+	s.metrics.ObserveStoreMethodDuration("DeleteNotificationHint", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteSession(sessionID string) error {
+	start := time.Now()
+	err := s.Store.DeleteSession(sessionID)
+	s.metrics.ObserveStoreMethodDuration("DeleteSession", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteSessionsForUser(userID string) (int64, error) {
+	start := time.Now()
+	result, err := s.Store.DeleteSessionsForUser(userID)
+	s.metrics.ObserveStoreMethodDuration("DeleteSessionsForUser", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) DeleteSubscription(blockID string, subscriberID string) error {
+	start := time.Now()
+	err := s.Store.DeleteSubscription(blockID, subscriberID)
+	s.metrics.ObserveStoreMethodDuration("DeleteSubscription", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DeleteTemplate(templateID string) error {
+	start := time.Now()
+	err := s.Store.DeleteTemplate(templateID)
+	s.metrics.ObserveStoreMethodDuration("DeleteTemplate", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.DuplicateBlock(boardID, blockID, userID, asTemplate)
+	s.metrics.ObserveStoreMethodDuration("DuplicateBlock", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) DuplicateBoard(boardID string, userID string, toTeam string, asTemplate bool) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.DuplicateBoard(boardID, userID, toTeam, asTemplate)
+	s.metrics.ObserveStoreMethodDuration("DuplicateBoard", err == nil, time.Since(start))
+	return result, resultVar1, err
+}
+
+func (s *TimerLayer) GetActiveUserCount(updatedSecondsAgo int64) (int, error) {
+	start := time.Now()
+	result, err := s.Store.GetActiveUserCount(updatedSecondsAgo)
+	s.metrics.ObserveStoreMethodDuration("GetActiveUserCount", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetAllTeams() ([]*model.Team, error) {
+	start := time.Now()
+	result, err := s.Store.GetAllTeams()
+	s.metrics.ObserveStoreMethodDuration("GetAllTeams", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetAllUsers() ([]*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.GetAllUsers()
+	s.metrics.ObserveStoreMethodDuration("GetAllUsers", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetAuditRecords(opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, error) {
+	start := time.Now()
+	result, err := s.Store.GetAuditRecords(opts)
+	s.metrics.ObserveStoreMethodDuration("GetAuditRecords", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlock(blockID string) (*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlock(blockID)
+	s.metrics.ObserveStoreMethodDuration("GetBlock", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlockCountsByType() (map[string]int64, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlockCountsByType()
+	s.metrics.ObserveStoreMethodDuration("GetBlockCountsByType", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlockFileIDs() ([]string, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlockFileIDs()
+	s.metrics.ObserveStoreMethodDuration("GetBlockFileIDs", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlockHistory(blockID string, opts model.QueryBlockHistoryOptions) ([]model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlockHistory(blockID, opts)
+	s.metrics.ObserveStoreMethodDuration("GetBlockHistory", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlockHistoryDescendants(boardID, opts)
+	s.metrics.ObserveStoreMethodDuration("GetBlockHistoryDescendants", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlockTombstones(boardID string, since int64) ([]model.BlockTombstone, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlockTombstones(boardID, since)
+	s.metrics.ObserveStoreMethodDuration("GetBlockTombstones", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlocksByIDs(ids []string) ([]model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocksByIDs(ids)
+	s.metrics.ObserveStoreMethodDuration("GetBlocksByIDs", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlocksForBoard(boardID string) ([]model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocksForBoard(boardID)
+	s.metrics.ObserveStoreMethodDuration("GetBlocksForBoard", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlocksPage(boardID string, opts model.QueryBlocksPageOptions) ([]model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocksPage(boardID, opts)
+	s.metrics.ObserveStoreMethodDuration("GetBlocksPage", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlocksWithBoardID(boardID string) ([]model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocksWithBoardID(boardID)
+	s.metrics.ObserveStoreMethodDuration("GetBlocksWithBoardID", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlocksWithParent(boardID string, parentID string) ([]model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocksWithParent(boardID, parentID)
+	s.metrics.ObserveStoreMethodDuration("GetBlocksWithParent", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlocksWithParentAndType(boardID string, parentID string, blockType string) ([]model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocksWithParentAndType(boardID, parentID, blockType)
+	s.metrics.ObserveStoreMethodDuration("GetBlocksWithParentAndType", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBlocksWithType(boardID string, blockType string) ([]model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocksWithType(boardID, blockType)
+	s.metrics.ObserveStoreMethodDuration("GetBlocksWithType", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBoard(id string) (*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoard(id)
+	s.metrics.ObserveStoreMethodDuration("GetBoard", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBoardAndCard(block *model.Block) (*model.Board, *model.Block, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.GetBoardAndCard(block)
+	s.metrics.ObserveStoreMethodDuration("GetBoardAndCard", err == nil, time.Since(start))
+	return result, resultVar1, err
+}
+
+func (s *TimerLayer) GetBoardAndCardByID(blockID string) (*model.Board, *model.Block, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.GetBoardAndCardByID(blockID)
+	s.metrics.ObserveStoreMethodDuration("GetBoardAndCardByID", err == nil, time.Since(start))
+	return result, resultVar1, err
+}
+
+func (s *TimerLayer) GetBoardHistory(boardID string, opts model.QueryBoardHistoryOptions) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoardHistory(boardID, opts)
+	s.metrics.ObserveStoreMethodDuration("GetBoardHistory", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBoardMemberHistory(boardID string, userID string, limit uint64) ([]*model.BoardMemberHistoryEntry, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoardMemberHistory(boardID, userID, limit)
+	s.metrics.ObserveStoreMethodDuration("GetBoardMemberHistory", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBoardsByIDs(boardIDs []string) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoardsByIDs(boardIDs)
+	s.metrics.ObserveStoreMethodDuration("GetBoardsByIDs", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBoardsForUserAndTeam(userID string, teamID string) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoardsForUserAndTeam(userID, teamID)
+	s.metrics.ObserveStoreMethodDuration("GetBoardsForUserAndTeam", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBoardsInTeam(teamID string) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoardsInTeam(teamID)
+	s.metrics.ObserveStoreMethodDuration("GetBoardsInTeam", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBoardsInTeamByIds(boardIDs []string, teamID string) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoardsInTeamByIds(boardIDs, teamID)
+	s.metrics.ObserveStoreMethodDuration("GetBoardsInTeamByIds", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetBoardsWithAutoArchiveRules() ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoardsWithAutoArchiveRules()
+	s.metrics.ObserveStoreMethodDuration("GetBoardsWithAutoArchiveRules", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetCardHistory(boardID string, propertyIDs []string, until int64) ([]*model.CardHistoryEntry, error) {
+	start := time.Now()
+	result, err := s.Store.GetCardHistory(boardID, propertyIDs, until)
+	s.metrics.ObserveStoreMethodDuration("GetCardHistory", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetCardLimitTimestamp() (int64, error) {
+	start := time.Now()
+	result, err := s.Store.GetCardLimitTimestamp()
+	s.metrics.ObserveStoreMethodDuration("GetCardLimitTimestamp", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetCardsForUser(query *model.UserCardsQuery) ([]model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetCardsForUser(query)
+	s.metrics.ObserveStoreMethodDuration("GetCardsForUser", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetCategory(id string) (*model.Category, error) {
+	start := time.Now()
+	result, err := s.Store.GetCategory(id)
+	s.metrics.ObserveStoreMethodDuration("GetCategory", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetChannel(teamID string, channelID string) (*mmModel.Channel, error) {
+	start := time.Now()
+	result, err := s.Store.GetChannel(teamID, channelID)
+	s.metrics.ObserveStoreMethodDuration("GetChannel", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetCloudLimits() (*mmModel.ProductLimits, error) {
+	start := time.Now()
+	result, err := s.Store.GetCloudLimits()
+	s.metrics.ObserveStoreMethodDuration("GetCloudLimits", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetDefaultSharedCategory(teamID string) (*model.Category, error) {
+	start := time.Now()
+	result, err := s.Store.GetDefaultSharedCategory(teamID)
+	s.metrics.ObserveStoreMethodDuration("GetDefaultSharedCategory", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetFeedToken(userID string) (*model.FeedToken, error) {
+	start := time.Now()
+	result, err := s.Store.GetFeedToken(userID)
+	s.metrics.ObserveStoreMethodDuration("GetFeedToken", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetFeedTokenByToken(token string) (*model.FeedToken, error) {
+	start := time.Now()
+	result, err := s.Store.GetFeedTokenByToken(token)
+	s.metrics.ObserveStoreMethodDuration("GetFeedTokenByToken", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetFileInfo(id string) (*mmModel.FileInfo, error) {
+	start := time.Now()
+	result, err := s.Store.GetFileInfo(id)
+	s.metrics.ObserveStoreMethodDuration("GetFileInfo", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetFileInfosCreatedBefore(createAt int64) ([]*mmModel.FileInfo, error) {
+	start := time.Now()
+	result, err := s.Store.GetFileInfosCreatedBefore(createAt)
+	s.metrics.ObserveStoreMethodDuration("GetFileInfosCreatedBefore", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetFileScan(boardID string, fileID string) (*model.FileScan, error) {
+	start := time.Now()
+	result, err := s.Store.GetFileScan(boardID, fileID)
+	s.metrics.ObserveStoreMethodDuration("GetFileScan", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetImportJob(jobID string) (*model.ImportJob, error) {
+	start := time.Now()
+	result, err := s.Store.GetImportJob(jobID)
+	s.metrics.ObserveStoreMethodDuration("GetImportJob", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetImportJobsForUser(teamID string, userID string) ([]*model.ImportJob, error) {
+	start := time.Now()
+	result, err := s.Store.GetImportJobsForUser(teamID, userID)
+	s.metrics.ObserveStoreMethodDuration("GetImportJobsForUser", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetLicense() *mmModel.License {
+	start := time.Now()
+	result := s.Store.GetLicense()
+	s.metrics.ObserveStoreMethodDuration("GetLicense", true, time.Since(start))
+	return result
+}
+
+func (s *TimerLayer) GetMemberForBoard(boardID string, userID string) (*model.BoardMember, error) {
+	start := time.Now()
+	result, err := s.Store.GetMemberForBoard(boardID, userID)
+	s.metrics.ObserveStoreMethodDuration("GetMemberForBoard", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetMembersForBoard(boardID string) ([]*model.BoardMember, error) {
+	start := time.Now()
+	result, err := s.Store.GetMembersForBoard(boardID)
+	s.metrics.ObserveStoreMethodDuration("GetMembersForBoard", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetMembersForUser(userID string) ([]*model.BoardMember, error) {
+	start := time.Now()
+	result, err := s.Store.GetMembersForUser(userID)
+	s.metrics.ObserveStoreMethodDuration("GetMembersForUser", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetNextNotificationHint(remove bool) (*model.NotificationHint, error) {
+	start := time.Now()
+	result, err := s.Store.GetNextNotificationHint(remove)
+	s.metrics.ObserveStoreMethodDuration("GetNextNotificationHint", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetNotificationHint(blockID string) (*model.NotificationHint, error) {
+	start := time.Now()
+	result, err := s.Store.GetNotificationHint(blockID)
+	s.metrics.ObserveStoreMethodDuration("GetNotificationHint", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetRegisteredUserCount() (int, error) {
+	start := time.Now()
+	result, err := s.Store.GetRegisteredUserCount()
+	s.metrics.ObserveStoreMethodDuration("GetRegisteredUserCount", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetSession(token string, expireTime int64) (*model.Session, error) {
+	start := time.Now()
+	result, err := s.Store.GetSession(token, expireTime)
+	s.metrics.ObserveStoreMethodDuration("GetSession", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetSharedCategories(teamID string) ([]model.Category, error) {
+	start := time.Now()
+	result, err := s.Store.GetSharedCategories(teamID)
+	s.metrics.ObserveStoreMethodDuration("GetSharedCategories", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetSharedCategoryBoards(teamID string) ([]model.CategoryBoards, error) {
+	start := time.Now()
+	result, err := s.Store.GetSharedCategoryBoards(teamID)
+	s.metrics.ObserveStoreMethodDuration("GetSharedCategoryBoards", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetSharing(rootID string) (*model.Sharing, error) {
+	start := time.Now()
+	result, err := s.Store.GetSharing(rootID)
+	s.metrics.ObserveStoreMethodDuration("GetSharing", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetSubTree2(boardID string, blockID string, opts model.QuerySubtreeOptions) ([]model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetSubTree2(boardID, blockID, opts)
+	s.metrics.ObserveStoreMethodDuration("GetSubTree2", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetSubscribersCountForBlock(blockID string) (int, error) {
+	start := time.Now()
+	result, err := s.Store.GetSubscribersCountForBlock(blockID)
+	s.metrics.ObserveStoreMethodDuration("GetSubscribersCountForBlock", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetSubscribersForBlock(blockID string) ([]*model.Subscriber, error) {
+	start := time.Now()
+	result, err := s.Store.GetSubscribersForBlock(blockID)
+	s.metrics.ObserveStoreMethodDuration("GetSubscribersForBlock", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetSubscription(blockID string, subscriberID string) (*model.Subscription, error) {
+	start := time.Now()
+	result, err := s.Store.GetSubscription(blockID, subscriberID)
+	s.metrics.ObserveStoreMethodDuration("GetSubscription", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetSubscriptions(subscriberID string) ([]*model.Subscription, error) {
+	start := time.Now()
+	result, err := s.Store.GetSubscriptions(subscriberID)
+	s.metrics.ObserveStoreMethodDuration("GetSubscriptions", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetSystemSetting(key string) (string, error) {
+	start := time.Now()
+	result, err := s.Store.GetSystemSetting(key)
+	s.metrics.ObserveStoreMethodDuration("GetSystemSetting", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetSystemSettings() (map[string]string, error) {
+	start := time.Now()
+	result, err := s.Store.GetSystemSettings()
+	s.metrics.ObserveStoreMethodDuration("GetSystemSettings", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetTeam(ID string) (*model.Team, error) {
+	start := time.Now()
+	result, err := s.Store.GetTeam(ID)
+	s.metrics.ObserveStoreMethodDuration("GetTeam", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetTeamBoardsInsights(teamID string, userID string, since int64, offset int, limit int, boardIDs []string) (*model.BoardInsightsList, error) {
+	start := time.Now()
+	result, err := s.Store.GetTeamBoardsInsights(teamID, userID, since, offset, limit, boardIDs)
+	s.metrics.ObserveStoreMethodDuration("GetTeamBoardsInsights", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetTeamCount() (int64, error) {
+	start := time.Now()
+	result, err := s.Store.GetTeamCount()
+	s.metrics.ObserveStoreMethodDuration("GetTeamCount", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetTeamFilesSize(teamID string) (int64, error) {
+	start := time.Now()
+	result, err := s.Store.GetTeamFilesSize(teamID)
+	s.metrics.ObserveStoreMethodDuration("GetTeamFilesSize", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetTeamsForUser(userID string) ([]*model.Team, error) {
+	start := time.Now()
+	result, err := s.Store.GetTeamsForUser(userID)
+	s.metrics.ObserveStoreMethodDuration("GetTeamsForUser", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetTemplate(templateID string) (*model.Template, error) {
+	start := time.Now()
+	result, err := s.Store.GetTemplate(templateID)
+	s.metrics.ObserveStoreMethodDuration("GetTemplate", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetTemplateBoards(teamID string, userID string) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetTemplateBoards(teamID, userID)
+	s.metrics.ObserveStoreMethodDuration("GetTemplateBoards", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetTemplateDerivedBoard(boardID string) (*model.TemplateDerivedBoard, error) {
+	start := time.Now()
+	result, err := s.Store.GetTemplateDerivedBoard(boardID)
+	s.metrics.ObserveStoreMethodDuration("GetTemplateDerivedBoard", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetTemplateDerivedBoards(templateID string) ([]*model.TemplateDerivedBoard, error) {
+	start := time.Now()
+	result, err := s.Store.GetTemplateDerivedBoards(templateID)
+	s.metrics.ObserveStoreMethodDuration("GetTemplateDerivedBoards", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetTemplateForBoard(boardID string) (*model.Template, error) {
+	start := time.Now()
+	result, err := s.Store.GetTemplateForBoard(boardID)
+	s.metrics.ObserveStoreMethodDuration("GetTemplateForBoard", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetTemplateForSourceBoard(teamID string, sourceBoardID string) (*model.Template, error) {
+	start := time.Now()
+	result, err := s.Store.GetTemplateForSourceBoard(teamID, sourceBoardID)
+	s.metrics.ObserveStoreMethodDuration("GetTemplateForSourceBoard", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetTemplates(opts model.QueryTemplatesOptions) ([]*model.Template, error) {
+	start := time.Now()
+	result, err := s.Store.GetTemplates(opts)
+	s.metrics.ObserveStoreMethodDuration("GetTemplates", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetUnfinishedFileScans(updatedBefore int64) ([]*model.FileScan, error) {
+	start := time.Now()
+	result, err := s.Store.GetUnfinishedFileScans(updatedBefore)
+	s.metrics.ObserveStoreMethodDuration("GetUnfinishedFileScans", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetUsedCardsCount() (int, error) {
+	start := time.Now()
+	result, err := s.Store.GetUsedCardsCount()
+	s.metrics.ObserveStoreMethodDuration("GetUsedCardsCount", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetUserBoardsInsights(teamID string, userID string, since int64, offset int, limit int, boardIDs []string) (*model.BoardInsightsList, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserBoardsInsights(teamID, userID, since, offset, limit, boardIDs)
+	s.metrics.ObserveStoreMethodDuration("GetUserBoardsInsights", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetUserByEmail(email string) (*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserByEmail(email)
+	s.metrics.ObserveStoreMethodDuration("GetUserByEmail", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetUserByID(userID string) (*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserByID(userID)
+	s.metrics.ObserveStoreMethodDuration("GetUserByID", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetUserByUsername(username string) (*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserByUsername(username)
+	s.metrics.ObserveStoreMethodDuration("GetUserByUsername", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetUserCategoryBoards(userID string, teamID string) ([]model.CategoryBoards, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserCategoryBoards(userID, teamID)
+	s.metrics.ObserveStoreMethodDuration("GetUserCategoryBoards", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetUserTimezone(userID string) (string, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserTimezone(userID)
+	s.metrics.ObserveStoreMethodDuration("GetUserTimezone", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetUsersByTeam(teamID string) ([]*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.GetUsersByTeam(teamID)
+	s.metrics.ObserveStoreMethodDuration("GetUsersByTeam", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) GetUsersList(userIDs []string) ([]*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.GetUsersList(userIDs)
+	s.metrics.ObserveStoreMethodDuration("GetUsersList", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) InsertAuditRecord(record *model.AuditRecord) error {
+	start := time.Now()
+	err := s.Store.InsertAuditRecord(record)
+	s.metrics.ObserveStoreMethodDuration("InsertAuditRecord", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) InsertBlock(block *model.Block, userID string) error {
+	start := time.Now()
+	err := s.Store.InsertBlock(block, userID)
+	s.metrics.ObserveStoreMethodDuration("InsertBlock", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) InsertBlocks(blocks []model.Block, userID string) error {
+	start := time.Now()
+	err := s.Store.InsertBlocks(blocks, userID)
+	s.metrics.ObserveStoreMethodDuration("InsertBlocks", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) InsertBoard(board *model.Board, userID string) (*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.InsertBoard(board, userID)
+	s.metrics.ObserveStoreMethodDuration("InsertBoard", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) InsertBoardWithAdmin(board *model.Board, userID string) (*model.Board, *model.BoardMember, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.InsertBoardWithAdmin(board, userID)
+	s.metrics.ObserveStoreMethodDuration("InsertBoardWithAdmin", err == nil, time.Since(start))
+	return result, resultVar1, err
+}
+
+func (s *TimerLayer) InsertFileScan(scan *model.FileScan) error {
+	start := time.Now()
+	err := s.Store.InsertFileScan(scan)
+	s.metrics.ObserveStoreMethodDuration("InsertFileScan", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) InsertImportJob(job *model.ImportJob) (*model.ImportJob, error) {
+	start := time.Now()
+	result, err := s.Store.InsertImportJob(job)
+	s.metrics.ObserveStoreMethodDuration("InsertImportJob", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) InsertTemplate(template *model.Template) (*model.Template, error) {
+	start := time.Now()
+	result, err := s.Store.InsertTemplate(template)
+	s.metrics.ObserveStoreMethodDuration("InsertTemplate", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) MoveBlocks(blocks []model.Block, userID string) error {
+	start := time.Now()
+	err := s.Store.MoveBlocks(blocks, userID)
+	s.metrics.ObserveStoreMethodDuration("MoveBlocks", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error {
+	start := time.Now()
+	err := s.Store.PatchBlock(blockID, blockPatch, userID)
+	s.metrics.ObserveStoreMethodDuration("PatchBlock", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) PatchBlocks(blockPatches *model.BlockPatchBatch, userID string) error {
+	start := time.Now()
+	err := s.Store.PatchBlocks(blockPatches, userID)
+	s.metrics.ObserveStoreMethodDuration("PatchBlocks", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) PatchBoard(boardID string, boardPatch *model.BoardPatch, userID string) (*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.PatchBoard(boardID, boardPatch, userID)
+	s.metrics.ObserveStoreMethodDuration("PatchBoard", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
+	start := time.Now()
+	result, err := s.Store.PatchBoardsAndBlocks(pbab, userID)
+	s.metrics.ObserveStoreMethodDuration("PatchBoardsAndBlocks", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) PatchUserProps(userID string, patch model.UserPropPatch) error {
+	start := time.Now()
+	err := s.Store.PatchUserProps(userID, patch)
+	s.metrics.ObserveStoreMethodDuration("PatchUserProps", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) RefreshSession(session *model.Session) error {
+	start := time.Now()
+	err := s.Store.RefreshSession(session)
+	s.metrics.ObserveStoreMethodDuration("RefreshSession", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) RemoveDefaultTemplates(boards []*model.Board) error {
+	start := time.Now()
+	err := s.Store.RemoveDefaultTemplates(boards)
+	s.metrics.ObserveStoreMethodDuration("RemoveDefaultTemplates", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) RunDataRetention(globalRetentionDate int64, batchSize int64) (int64, error) {
+	start := time.Now()
+	result, err := s.Store.RunDataRetention(globalRetentionDate, batchSize)
+	s.metrics.ObserveStoreMethodDuration("RunDataRetention", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) SaveCardChanges(changes *model.CardChanges, userID string, check model.CardsCheck) error {
+	start := time.Now()
+	err := s.Store.SaveCardChanges(changes, userID, check)
+	s.metrics.ObserveStoreMethodDuration("SaveCardChanges", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) SaveFileInfo(fileInfo *mmModel.FileInfo) error {
+	start := time.Now()
+	err := s.Store.SaveFileInfo(fileInfo)
+	s.metrics.ObserveStoreMethodDuration("SaveFileInfo", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) SaveMember(bm *model.BoardMember) (*model.BoardMember, error) {
+	start := time.Now()
+	result, err := s.Store.SaveMember(bm)
+	s.metrics.ObserveStoreMethodDuration("SaveMember", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) SaveTemplateDerivedBoard(derivedBoard *model.TemplateDerivedBoard) error {
+	start := time.Now()
+	err := s.Store.SaveTemplateDerivedBoard(derivedBoard)
+	s.metrics.ObserveStoreMethodDuration("SaveTemplateDerivedBoard", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) SearchBoardsForUser(term string, userID string) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.SearchBoardsForUser(term, userID)
+	s.metrics.ObserveStoreMethodDuration("SearchBoardsForUser", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) SearchBoardsForUserInTeam(teamID string, term string, userID string) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.SearchBoardsForUserInTeam(teamID, term, userID)
+	s.metrics.ObserveStoreMethodDuration("SearchBoardsForUserInTeam", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) SearchUserChannels(teamID string, userID string, query string) ([]*mmModel.Channel, error) {
+	start := time.Now()
+	result, err := s.Store.SearchUserChannels(teamID, userID, query)
+	s.metrics.ObserveStoreMethodDuration("SearchUserChannels", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) SearchUsersByTeam(teamID string, searchQuery string) ([]*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.SearchUsersByTeam(teamID, searchQuery)
+	s.metrics.ObserveStoreMethodDuration("SearchUsersByTeam", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) SendMessage(message string, postType string, receipts []string) error {
+	start := time.Now()
+	err := s.Store.SendMessage(message, postType, receipts)
+	s.metrics.ObserveStoreMethodDuration("SendMessage", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) SetDefaultSharedCategory(teamID string, categoryID string) error {
+	start := time.Now()
+	err := s.Store.SetDefaultSharedCategory(teamID, categoryID)
+	s.metrics.ObserveStoreMethodDuration("SetDefaultSharedCategory", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) SetSystemSetting(key string, value string) error {
+	start := time.Now()
+	err := s.Store.SetSystemSetting(key, value)
+	s.metrics.ObserveStoreMethodDuration("SetSystemSetting", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) TransferBoardOwnership(boardID string, userID string) (*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.TransferBoardOwnership(boardID, userID)
+	s.metrics.ObserveStoreMethodDuration("TransferBoardOwnership", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) UndeleteBlock(blockID string, modifiedBy string) error {
+	start := time.Now()
+	err := s.Store.UndeleteBlock(blockID, modifiedBy)
+	s.metrics.ObserveStoreMethodDuration("UndeleteBlock", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UndeleteBoard(boardID string, modifiedBy string) error {
+	start := time.Now()
+	err := s.Store.UndeleteBoard(boardID, modifiedBy)
+	s.metrics.ObserveStoreMethodDuration("UndeleteBoard", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UpdateCardLimitTimestamp(cardLimit int) (int64, error) {
+	start := time.Now()
+	result, err := s.Store.UpdateCardLimitTimestamp(cardLimit)
+	s.metrics.ObserveStoreMethodDuration("UpdateCardLimitTimestamp", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) UpdateCategory(category model.Category) error {
+	start := time.Now()
+	err := s.Store.UpdateCategory(category)
+	s.metrics.ObserveStoreMethodDuration("UpdateCategory", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UpdateFileInfoImages(fileInfo *mmModel.FileInfo) error {
+	start := time.Now()
+	err := s.Store.UpdateFileInfoImages(fileInfo)
+	s.metrics.ObserveStoreMethodDuration("UpdateFileInfoImages", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UpdateFileScan(scan *model.FileScan) error {
+	start := time.Now()
+	err := s.Store.UpdateFileScan(scan)
+	s.metrics.ObserveStoreMethodDuration("UpdateFileScan", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UpdateImportJob(job *model.ImportJob) (*model.ImportJob, error) {
+	start := time.Now()
+	result, err := s.Store.UpdateImportJob(job)
+	s.metrics.ObserveStoreMethodDuration("UpdateImportJob", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) UpdateSession(session *model.Session) error {
+	start := time.Now()
+	err := s.Store.UpdateSession(session)
+	s.metrics.ObserveStoreMethodDuration("UpdateSession", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UpdateSubscribersNotifiedAt(blockID string, notifiedAt int64) error {
+	start := time.Now()
+	err := s.Store.UpdateSubscribersNotifiedAt(blockID, notifiedAt)
+	s.metrics.ObserveStoreMethodDuration("UpdateSubscribersNotifiedAt", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UpdateTemplate(template *model.Template) (*model.Template, error) {
+	start := time.Now()
+	result, err := s.Store.UpdateTemplate(template)
+	s.metrics.ObserveStoreMethodDuration("UpdateTemplate", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) UpdateUser(user *model.User) error {
+	start := time.Now()
+	err := s.Store.UpdateUser(user)
+	s.metrics.ObserveStoreMethodDuration("UpdateUser", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UpdateUserPassword(username string, password string) error {
+	start := time.Now()
+	err := s.Store.UpdateUserPassword(username, password)
+	s.metrics.ObserveStoreMethodDuration("UpdateUserPassword", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UpdateUserPasswordByID(userID string, password string) error {
+	start := time.Now()
+	err := s.Store.UpdateUserPasswordByID(userID, password)
+	s.metrics.ObserveStoreMethodDuration("UpdateUserPasswordByID", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UpsertFeedToken(feedToken *model.FeedToken) error {
+	start := time.Now()
+	err := s.Store.UpsertFeedToken(feedToken)
+	s.metrics.ObserveStoreMethodDuration("UpsertFeedToken", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error) {
+	start := time.Now()
+	result, err := s.Store.UpsertNotificationHint(hint, notificationFreq)
+	s.metrics.ObserveStoreMethodDuration("UpsertNotificationHint", err == nil, time.Since(start))
+	return result, err
+}
+
+func (s *TimerLayer) UpsertSharing(sharing model.Sharing) error {
+	start := time.Now()
+	err := s.Store.UpsertSharing(sharing)
+	s.metrics.ObserveStoreMethodDuration("UpsertSharing", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UpsertTeamSettings(team model.Team) error {
+	start := time.Now()
+	err := s.Store.UpsertTeamSettings(team)
+	s.metrics.ObserveStoreMethodDuration("UpsertTeamSettings", err == nil, time.Since(start))
+	return err
+}
+
+func (s *TimerLayer) UpsertTeamSignupToken(team model.Team) error {
+	start := time.Now()
+	err := s.Store.UpsertTeamSignupToken(team)
+	s.metrics.ObserveStoreMethodDuration("UpsertTeamSignupToken", err == nil, time.Since(start))
+	return err
+}
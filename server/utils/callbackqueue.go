@@ -13,6 +13,13 @@ import (
 // called when dequeued.
 type CallbackFunc func() error
 
+// CallbackQueueMetrics receives the queue depth and the time spent executing
+// each callback. *metrics.Metrics satisfies this interface.
+type CallbackQueueMetrics interface {
+	ObserveCallbackQueueLength(name string, length int)
+	ObserveCallbackDuration(name string, elapsed time.Duration)
+}
+
 // CallbackQueue provides a simple thread pool for processing callbacks. Callbacks will
 // be executed in the order in which they are enqueued, but no guarantees are provided
 // regarding the order in which they finish (unless poolSize == 1).
@@ -26,11 +33,13 @@ type CallbackQueue struct {
 
 	idone uint32
 
-	logger mlog.LoggerIFace
+	logger  mlog.LoggerIFace
+	metrics CallbackQueueMetrics
 }
 
 // NewCallbackQueue creates a new CallbackQueue and starts a thread pool to service it.
-func NewCallbackQueue(name string, queueSize int, poolSize int, logger mlog.LoggerIFace) *CallbackQueue {
+// metrics is optional and can be nil.
+func NewCallbackQueue(name string, queueSize int, poolSize int, logger mlog.LoggerIFace, metrics CallbackQueueMetrics) *CallbackQueue {
 	cn := &CallbackQueue{
 		name:     name,
 		poolSize: poolSize,
@@ -38,6 +47,7 @@ func NewCallbackQueue(name string, queueSize int, poolSize int, logger mlog.Logg
 		done:     make(chan struct{}),
 		alive:    make(chan int, poolSize),
 		logger:   logger,
+		metrics:  metrics,
 	}
 
 	for i := 0; i < poolSize; i++ {
@@ -98,6 +108,13 @@ func (cn *CallbackQueue) Enqueue(f CallbackFunc) {
 		dur := time.Since(start)
 		cn.logger.Warn("CallbackQueue queue backlog", mlog.String("name", cn.name), mlog.Duration("wait_time", dur))
 	}
+	cn.observeLength()
+}
+
+func (cn *CallbackQueue) observeLength() {
+	if cn.metrics != nil {
+		cn.metrics.ObserveCallbackQueueLength(cn.name, len(cn.queue))
+	}
 }
 
 func (cn *CallbackQueue) loop(id int) {
@@ -117,8 +134,14 @@ func (cn *CallbackQueue) loop(id int) {
 }
 
 func (cn *CallbackQueue) exec(f CallbackFunc) {
+	cn.observeLength()
+	start := time.Now()
+
 	// don't let a panic in the callback exit the thread.
 	defer func() {
+		if cn.metrics != nil {
+			cn.metrics.ObserveCallbackDuration(cn.name, time.Since(start))
+		}
 		if r := recover(); r != nil {
 			cn.logger.Error("CallbackQueue callback panic",
 				mlog.String("name", cn.name),
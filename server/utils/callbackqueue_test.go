@@ -15,7 +15,7 @@ func Test_newChangeNotifier(t *testing.T) {
 	logger := mlog.CreateConsoleTestLogger(false, mlog.LvlDebug)
 
 	t.Run("startup, shutdown", func(t *testing.T) {
-		cn := NewCallbackQueue("test1", 100, 5, logger)
+		cn := NewCallbackQueue("test1", 100, 5, logger, nil)
 
 		var callbackCount int32
 		callback := func() error {
@@ -41,7 +41,7 @@ func Test_newChangeNotifier(t *testing.T) {
 	})
 
 	t.Run("handle panic", func(t *testing.T) {
-		cn := NewCallbackQueue("test2", 100, 5, logger)
+		cn := NewCallbackQueue("test2", 100, 5, logger, nil)
 
 		var callbackCount int32
 		callback := func() error {
@@ -61,4 +61,34 @@ func Test_newChangeNotifier(t *testing.T) {
 
 		assert.Equal(t, int32(loops), atomic.LoadInt32(&callbackCount))
 	})
+	t.Run("report metrics", func(t *testing.T) {
+		metrics := &testQueueMetrics{}
+		cn := NewCallbackQueue("test3", 100, 5, logger, metrics)
+
+		const loops = 10
+		for i := 0; i < loops; i++ {
+			cn.Enqueue(func() error { return nil })
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+		ok := cn.Shutdown(ctx)
+		assert.True(t, ok, "shutdown should return true (no timeout)")
+
+		assert.Equal(t, int32(loops), atomic.LoadInt32(&metrics.durationCount))
+		assert.GreaterOrEqual(t, atomic.LoadInt32(&metrics.lengthCount), int32(loops))
+	})
+}
+
+type testQueueMetrics struct {
+	lengthCount   int32
+	durationCount int32
+}
+
+func (m *testQueueMetrics) ObserveCallbackQueueLength(name string, length int) {
+	atomic.AddInt32(&m.lengthCount, 1)
+}
+
+func (m *testQueueMetrics) ObserveCallbackDuration(name string, elapsed time.Duration) {
+	atomic.AddInt32(&m.durationCount, 1)
 }
@@ -15,11 +15,14 @@ const (
 	websocketActionUpdateMember             = "UPDATE_MEMBER"
 	websocketActionDeleteMember             = "DELETE_MEMBER"
 	websocketActionUpdateBlock              = "UPDATE_BLOCK"
+	websocketActionUpdateBlocks             = "UPDATE_BLOCKS"
 	websocketActionUpdateConfig             = "UPDATE_CLIENT_CONFIG"
 	websocketActionUpdateCategory           = "UPDATE_CATEGORY"
 	websocketActionUpdateCategoryBoard      = "UPDATE_BOARD_CATEGORY"
 	websocketActionUpdateSubscription       = "UPDATE_SUBSCRIPTION"
 	websocketActionUpdateCardLimitTimestamp = "UPDATE_CARD_LIMIT_TIMESTAMP"
+	websocketActionUpdateImportJob          = "UPDATE_IMPORT_JOB"
+	websocketActionUpdateFileScan           = "UPDATE_FILE_SCAN"
 )
 
 type Store interface {
@@ -30,6 +33,7 @@ type Store interface {
 type Adapter interface {
 	BroadcastBlockChange(teamID string, block model.Block)
 	BroadcastBlockDelete(teamID, blockID, boardID string)
+	BroadcastBlockChanges(teamID, boardID string, blocks []model.Block)
 	BroadcastBoardChange(teamID string, board *model.Board)
 	BroadcastBoardDelete(teamID, boardID string)
 	BroadcastMemberChange(teamID, boardID string, member *model.BoardMember)
@@ -39,4 +43,6 @@ type Adapter interface {
 	BroadcastCategoryBoardChange(teamID, userID string, blockCategory model.BoardCategoryWebsocketData)
 	BroadcastCardLimitTimestampChange(cardLimitTimestamp int64)
 	BroadcastSubscriptionChange(teamID string, subscription *model.Subscription)
+	BroadcastImportJobChange(teamID string, job *model.ImportJob)
+	BroadcastFileScanChange(teamID string, scan *model.FileScan)
 }
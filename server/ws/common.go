@@ -19,6 +19,14 @@ type UpdateBlockMsg struct {
 	Block  model.Block `json:"block"`
 }
 
+// UpdateBlocksMsg is sent on batched block updates of a board.
+type UpdateBlocksMsg struct {
+	Action  string        `json:"action"`
+	TeamID  string        `json:"teamId"`
+	BoardID string        `json:"boardId"`
+	Blocks  []model.Block `json:"blocks"`
+}
+
 // UpdateBoardMsg is sent on block updates.
 type UpdateBoardMsg struct {
 	Action string       `json:"action"`
@@ -39,6 +47,22 @@ type UpdateSubscription struct {
 	Subscription *model.Subscription `json:"subscription"`
 }
 
+// UpdateImportJobMsg is sent to the user that started an import job when
+// its progress or status changes.
+type UpdateImportJobMsg struct {
+	Action    string           `json:"action"`
+	TeamID    string           `json:"teamId"`
+	ImportJob *model.ImportJob `json:"importJob"`
+}
+
+// UpdateFileScanMsg is sent to the user that uploaded a file when its
+// scan finishes.
+type UpdateFileScanMsg struct {
+	Action   string          `json:"action"`
+	TeamID   string          `json:"teamId"`
+	FileScan *model.FileScan `json:"fileScan"`
+}
+
 // UpdateClientConfig is sent on block updates.
 type UpdateClientConfig struct {
 	Action       string             `json:"action"`
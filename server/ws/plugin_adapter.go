@@ -469,6 +469,23 @@ func (pa *PluginAdapter) BroadcastBlockChange(teamID string, block model.Block)
 	pa.sendBoardMessage(teamID, block.BoardID, utils.StructToMap(message))
 }
 
+func (pa *PluginAdapter) BroadcastBlockChanges(teamID, boardID string, blocks []model.Block) {
+	pa.logger.Debug("BroadcastingBlockChanges",
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", boardID),
+		mlog.Int("blockCount", len(blocks)),
+	)
+
+	message := UpdateBlocksMsg{
+		Action:  websocketActionUpdateBlocks,
+		TeamID:  teamID,
+		BoardID: boardID,
+		Blocks:  blocks,
+	}
+
+	pa.sendBoardMessage(teamID, boardID, utils.StructToMap(message))
+}
+
 func (pa *PluginAdapter) BroadcastCategoryChange(category model.Category) {
 	pa.logger.Debug("BroadcastCategoryChange",
 		mlog.String("userID", category.UserID),
@@ -484,6 +501,12 @@ func (pa *PluginAdapter) BroadcastCategoryChange(category model.Category) {
 
 	payload := utils.StructToMap(message)
 
+	// shared categories are sent to everyone on the team
+	if category.IsShared() {
+		pa.sendTeamMessage(websocketActionUpdateCategory, category.TeamID, payload)
+		return
+	}
+
 	go func() {
 		clusterMessage := &ClusterMessage{
 			Payload: payload,
@@ -513,6 +536,12 @@ func (pa *PluginAdapter) BroadcastCategoryBoardChange(teamID, userID string, boa
 
 	payload := utils.StructToMap(message)
 
+	// boards placed in shared categories don't belong to a user
+	if userID == "" {
+		pa.sendTeamMessage(websocketActionUpdateCategoryBoard, teamID, payload)
+		return
+	}
+
 	go func() {
 		clusterMessage := &ClusterMessage{
 			Payload: payload,
@@ -612,6 +641,49 @@ func (pa *PluginAdapter) BroadcastSubscriptionChange(teamID string, subscription
 	pa.sendTeamMessage(websocketActionUpdateSubscription, teamID, utils.StructToMap(message))
 }
 
+func (pa *PluginAdapter) BroadcastImportJobChange(teamID string, job *model.ImportJob) {
+	message := UpdateImportJobMsg{
+		Action:    websocketActionUpdateImportJob,
+		TeamID:    teamID,
+		ImportJob: job,
+	}
+
+	pa.broadcastToUser(teamID, job.CreatedBy, message.Action, message)
+}
+
+func (pa *PluginAdapter) BroadcastFileScanChange(teamID string, scan *model.FileScan) {
+	message := UpdateFileScanMsg{
+		Action:   websocketActionUpdateFileScan,
+		TeamID:   teamID,
+		FileScan: scan,
+	}
+
+	pa.broadcastToUser(teamID, scan.CreatedBy, message.Action, message)
+}
+
+// broadcastToUser sends a message to the clients of a user, on this node
+// and on the other nodes of the cluster.
+func (pa *PluginAdapter) broadcastToUser(teamID, userID, action string, message interface{}) {
+	pa.logger.Debug("broadcastToUser",
+		mlog.String("action", action),
+		mlog.String("teamID", teamID),
+		mlog.String("userID", userID),
+	)
+
+	payload := utils.StructToMap(message)
+
+	go func() {
+		clusterMessage := &ClusterMessage{
+			Payload: payload,
+			UserID:  userID,
+		}
+
+		pa.sendMessageToCluster("websocket_message", clusterMessage)
+	}()
+
+	pa.sendUserMessageSkipCluster(action, payload, userID)
+}
+
 func (pa *PluginAdapter) BroadcastCardLimitTimestampChange(cardLimitTimestamp int64) {
 	pa.logger.Debug("BroadcastCardLimitTimestampChange",
 		mlog.Int64("cardLimitTimestamp", cardLimitTimestamp),
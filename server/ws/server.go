@@ -9,6 +9,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/mattermost/focalboard/server/auth"
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/metrics"
 	"github.com/mattermost/focalboard/server/utils"
 
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
@@ -53,6 +54,7 @@ type Server struct {
 	isMattermostAuth bool
 	logger           mlog.LoggerIFace
 	store            Store
+	metrics          *metrics.Metrics
 }
 
 type websocketSession struct {
@@ -68,7 +70,7 @@ func (wss *websocketSession) isAuthenticated() bool {
 }
 
 // NewServer creates a new Server.
-func NewServer(auth *auth.Auth, singleUserToken string, isMattermostAuth bool, logger mlog.LoggerIFace, store Store) *Server {
+func NewServer(auth *auth.Auth, singleUserToken string, isMattermostAuth bool, logger mlog.LoggerIFace, store Store, metrics *metrics.Metrics) *Server {
 	return &Server{
 		listeners:        make(map[*websocketSession]bool),
 		listenersByTeam:  make(map[string][]*websocketSession),
@@ -83,6 +85,7 @@ func NewServer(auth *auth.Auth, singleUserToken string, isMattermostAuth bool, l
 		isMattermostAuth: isMattermostAuth,
 		logger:           logger,
 		store:            store,
+		metrics:          metrics,
 	}
 }
 
@@ -290,6 +293,7 @@ func (ws *Server) addListener(listener *websocketSession) {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
 	ws.listeners[listener] = true
+	ws.metrics.IncrementWebsocketConnections()
 }
 
 // removeListener removes a listener and all its subscriptions, if
@@ -298,6 +302,12 @@ func (ws *Server) removeListener(listener *websocketSession) {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
 
+	// the listener can be removed more than once when the read loop
+	// fails, so it's only accounted the first time
+	if _, ok := ws.listeners[listener]; ok {
+		ws.metrics.DecrementWebsocketConnections()
+	}
+
 	// remove the listener from its subscriptions, if any
 
 	// team subscriptions
@@ -545,6 +555,8 @@ func (ws *Server) BroadcastBlockChange(teamID string, block model.Block) {
 		)
 	}
 
+	ws.metrics.ObserveWebsocketBroadcast(message.Action, len(listeners))
+
 	for _, listener := range listeners {
 		ws.logger.Debug("Broadcast block change",
 			mlog.String("teamID", teamID),
@@ -560,6 +572,50 @@ func (ws *Server) BroadcastBlockChange(teamID string, block model.Block) {
 	}
 }
 
+// BroadcastBlockChanges broadcasts the changes of several blocks of a
+// board to clients in a single message. Deleted blocks have DeleteAt set.
+func (ws *Server) BroadcastBlockChanges(teamID, boardID string, blocks []model.Block) {
+	message := UpdateBlocksMsg{
+		Action:  websocketActionUpdateBlocks,
+		TeamID:  teamID,
+		BoardID: boardID,
+		Blocks:  blocks,
+	}
+
+	listeners := ws.getListenersForTeamAndBoard(teamID, boardID)
+	for _, block := range blocks {
+		listeners = append(listeners, ws.getListenersForBlock(block.ID)...)
+		listeners = append(listeners, ws.getListenersForBlock(block.ParentID)...)
+	}
+
+	// a listener subscribed to several of the blocks gets the message once
+	unique := make([]*websocketSession, 0, len(listeners))
+	seen := map[*websocketSession]bool{}
+	for _, listener := range listeners {
+		if !seen[listener] {
+			seen[listener] = true
+			unique = append(unique, listener)
+		}
+	}
+
+	ws.metrics.ObserveWebsocketBroadcast(message.Action, len(unique))
+
+	for _, listener := range unique {
+		ws.logger.Debug("Broadcast block changes",
+			mlog.String("teamID", teamID),
+			mlog.String("boardID", boardID),
+			mlog.Int("block_count", len(blocks)),
+			mlog.Stringer("remoteAddr", listener.conn.RemoteAddr()),
+		)
+
+		err := listener.WriteJSON(message)
+		if err != nil {
+			ws.logger.Error("broadcast error", mlog.Err(err))
+			listener.conn.Close()
+		}
+	}
+}
+
 func (ws *Server) BroadcastCategoryChange(category model.Category) {
 	message := UpdateCategoryMessage{
 		Action:   websocketActionUpdateCategory,
@@ -574,6 +630,8 @@ func (ws *Server) BroadcastCategoryChange(category model.Category) {
 		mlog.String("categoryID", category.ID),
 	)
 
+	ws.metrics.ObserveWebsocketBroadcast(message.Action, len(listeners))
+
 	for _, listener := range listeners {
 		ws.logger.Debug("Broadcast block change",
 			mlog.Int("listener_count", len(listeners)),
@@ -604,6 +662,8 @@ func (ws *Server) BroadcastCategoryBoardChange(teamID, userID string, boardCateg
 		mlog.String("blockID", boardCategory.BoardID),
 	)
 
+	ws.metrics.ObserveWebsocketBroadcast(message.Action, len(listeners))
+
 	for _, listener := range listeners {
 		ws.logger.Debug("Broadcast block change",
 			mlog.Int("listener_count", len(listeners)),
@@ -632,6 +692,8 @@ func (ws *Server) BroadcastConfigChange(clientConfig model.ClientConfig) {
 		mlog.Int("listener_count", len(listeners)),
 	)
 
+	ws.metrics.ObserveWebsocketBroadcast(message.Action, len(listeners))
+
 	for listener := range listeners {
 		ws.logger.Debug("Broadcast Config change",
 			mlog.Stringer("remoteAddr", listener.conn.RemoteAddr()),
@@ -658,6 +720,8 @@ func (ws *Server) BroadcastBoardChange(teamID string, board *model.Board) {
 		mlog.String("boardID", board.ID),
 	)
 
+	ws.metrics.ObserveWebsocketBroadcast(message.Action, len(listeners))
+
 	for _, listener := range listeners {
 		ws.logger.Debug("Broadcast board change",
 			mlog.String("teamID", teamID),
@@ -698,6 +762,8 @@ func (ws *Server) BroadcastMemberChange(teamID, boardID string, member *model.Bo
 		mlog.String("boardID", boardID),
 	)
 
+	ws.metrics.ObserveWebsocketBroadcast(message.Action, len(listeners))
+
 	for _, listener := range listeners {
 		ws.logger.Debug("Broadcast member change",
 			mlog.String("teamID", teamID),
@@ -730,6 +796,8 @@ func (ws *Server) BroadcastMemberDelete(teamID, boardID, userID string) {
 		mlog.String("boardID", boardID),
 	)
 
+	ws.metrics.ObserveWebsocketBroadcast(message.Action, len(listeners))
+
 	for _, listener := range listeners {
 		ws.logger.Debug("Broadcast member removal",
 			mlog.String("teamID", teamID),
@@ -745,6 +813,57 @@ func (ws *Server) BroadcastMemberDelete(teamID, boardID, userID string) {
 	}
 }
 
+// BroadcastImportJobChange sends the progress of an import job to the
+// clients of the user that started it.
+func (ws *Server) BroadcastImportJobChange(teamID string, job *model.ImportJob) {
+	message := UpdateImportJobMsg{
+		Action:    websocketActionUpdateImportJob,
+		TeamID:    teamID,
+		ImportJob: job,
+	}
+
+	ws.broadcastToUser(teamID, job.CreatedBy, message.Action, message)
+}
+
+// BroadcastFileScanChange sends the result of the scan of a file to the
+// clients of the user that uploaded it.
+func (ws *Server) BroadcastFileScanChange(teamID string, scan *model.FileScan) {
+	message := UpdateFileScanMsg{
+		Action:   websocketActionUpdateFileScan,
+		TeamID:   teamID,
+		FileScan: scan,
+	}
+
+	ws.broadcastToUser(teamID, scan.CreatedBy, message.Action, message)
+}
+
+// broadcastToUser sends a message to the clients of a user that are
+// listening to a team.
+func (ws *Server) broadcastToUser(teamID, userID, action string, message interface{}) {
+	listeners := []*websocketSession{}
+	for _, listener := range ws.getListenersForTeam(teamID) {
+		if listener.userID == userID {
+			listeners = append(listeners, listener)
+		}
+	}
+
+	ws.metrics.ObserveWebsocketBroadcast(action, len(listeners))
+
+	for _, listener := range listeners {
+		ws.logger.Debug("Broadcast to user",
+			mlog.String("action", action),
+			mlog.String("teamID", teamID),
+			mlog.String("userID", userID),
+			mlog.Stringer("remoteAddr", listener.conn.RemoteAddr()),
+		)
+
+		if err := listener.WriteJSON(message); err != nil {
+			ws.logger.Error("broadcast to user error", mlog.String("action", action), mlog.Err(err))
+			listener.conn.Close()
+		}
+	}
+}
+
 func (ws *Server) BroadcastSubscriptionChange(workspaceID string, subscription *model.Subscription) {
 	// not implemented for standalone server.
 }
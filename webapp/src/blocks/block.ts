@@ -40,6 +40,7 @@ interface Block {
     createAt: number
     updateAt: number
     deleteAt: number
+    archiveAt?: number
 
     limited?: boolean
 }
@@ -34,6 +34,7 @@ type Board = {
     createAt: number
     updateAt: number
     deleteAt: number
+    archiveAt?: number
 }
 
 type BoardPatch = {
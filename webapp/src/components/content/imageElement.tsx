@@ -28,7 +28,7 @@ const ImageElement = (props: Props): JSX.Element|null => {
     useEffect(() => {
         if (!imageDataUrl) {
             const loadImage = async () => {
-                const fileURL = await octoClient.getFileAsDataUrl(block.boardId, props.block.fields.fileId)
+                const fileURL = await octoClient.getFileAsDataUrl(block.boardId, props.block.fields.fileId, 'preview')
                 setImageDataUrl(fileURL.url || '')
                 setFileInfo(fileURL)
             }
@@ -576,11 +576,20 @@ class OctoClient {
         return undefined
     }
 
-    async getFileAsDataUrl(boardId: string, fileId: string): Promise<FileInfo> {
+    // size gets the thumbnail or the preview of an image instead of the
+    // original file, which is returned for files without them
+    async getFileAsDataUrl(boardId: string, fileId: string, size?: 'thumb' | 'preview'): Promise<FileInfo> {
         let path = '/api/v2/files/teams/' + this.teamId + '/' + boardId + '/' + fileId
+        const params = new URLSearchParams()
         const readToken = Utils.getReadToken()
         if (readToken) {
-            path += `?read_token=${readToken}`
+            params.set('read_token', readToken)
+        }
+        if (size) {
+            params.set('size', size)
+        }
+        if (params.toString()) {
+            path += '?' + params.toString()
         }
         const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
         let fileInfo: FileInfo = {}
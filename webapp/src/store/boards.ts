@@ -128,7 +128,8 @@ const boardsSlice = createSlice({
         },
         updateBoards: (state, action: PayloadAction<Board[]>) => {
             for (const board of action.payload) {
-                if (board.deleteAt !== 0) {
+                // archived boards are hidden like the deleted ones
+                if (board.deleteAt !== 0 || board.archiveAt) {
                     delete state.boards[board.id]
                     delete state.templates[board.id]
                 } else if (board.isTemplate) {
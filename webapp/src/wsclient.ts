@@ -21,6 +21,7 @@ type WSCommand = {
 export type WSMessage = {
     action?: string
     block?: Block
+    blocks?: Block[]
     board?: Board
     category?: Category
     blockCategories?: BoardCategoryWebsocketData
@@ -34,6 +35,7 @@ export const ACTION_UPDATE_BOARD = 'UPDATE_BOARD'
 export const ACTION_UPDATE_MEMBER = 'UPDATE_MEMBER'
 export const ACTION_DELETE_MEMBER = 'DELETE_MEMBER'
 export const ACTION_UPDATE_BLOCK = 'UPDATE_BLOCK'
+export const ACTION_UPDATE_BLOCKS = 'UPDATE_BLOCKS'
 export const ACTION_AUTH = 'AUTH'
 export const ACTION_SUBSCRIBE_BLOCKS = 'SUBSCRIBE_BLOCKS'
 export const ACTION_SUBSCRIBE_TEAM = 'SUBSCRIBE_TEAM'
@@ -454,6 +456,9 @@ class WSClient {
                 case ACTION_UPDATE_BLOCK:
                     this.updateHandler(message)
                     break
+                case ACTION_UPDATE_BLOCKS:
+                    this.updateBlocksHandler(message)
+                    break
                 case ACTION_UPDATE_CATEGORY:
                     this.updateHandler(message)
                     break
@@ -489,6 +494,13 @@ class WSClient {
         }
     }
 
+    updateBlocksHandler(message: WSMessage): void {
+        // batched changes are processed as individual block updates
+        for (const block of message.blocks || []) {
+            this.updateHandler({action: ACTION_UPDATE_BLOCK, teamId: message.teamId, block})
+        }
+    }
+
     setOnFollowBlock(handler: FollowChangeHandler): void {
         this.onFollowBlock = handler
     }